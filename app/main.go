@@ -122,7 +122,7 @@ func main() {
 	}
 	logger.Info("Starting sidecar query server")
 
-	sidecarQueryServer, err := NewSideCarQueryServer(encCfg.Marshaler, *config, logger)
+	sidecarQueryServer, err := NewSideCarQueryServer(encCfg.Marshaler, encCfg.TxConfig, *config, logger)
 	if err != nil {
 		panic(err)
 	}
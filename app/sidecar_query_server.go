@@ -8,11 +8,13 @@ import (
 	"time"
 
 	tenderminapi "cosmossdk.io/api/cosmos/base/tendermint/v1beta1"
+	"github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/codec"
 	"github.com/labstack/echo/v4"
 
 	// nolint: staticcheck
 	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	poolmanagerqueryproto "github.com/osmosis-labs/osmosis/v25/x/poolmanager/client/queryproto"
 	// nolint: staticcheck
 	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
@@ -35,6 +37,8 @@ import (
 	poolsHttpDelivery "github.com/osmosis-labs/sqs/pools/delivery/http"
 	poolsUseCase "github.com/osmosis-labs/sqs/pools/usecase"
 	routerrepo "github.com/osmosis-labs/sqs/router/repository"
+	"github.com/osmosis-labs/sqs/router/usecase/quoteaudit"
+	"github.com/osmosis-labs/sqs/router/usecase/quotestream"
 	routerWorker "github.com/osmosis-labs/sqs/router/usecase/worker"
 	tokenshttpdelivery "github.com/osmosis-labs/sqs/tokens/delivery/http"
 	tokensusecase "github.com/osmosis-labs/sqs/tokens/usecase"
@@ -49,6 +53,8 @@ import (
 	orderbookplugindomain "github.com/osmosis-labs/sqs/domain/orderbook/plugin"
 	osmocexplugindomain "github.com/osmosis-labs/sqs/domain/osmocex/plugin"
 	passthroughdomain "github.com/osmosis-labs/sqs/domain/passthrough"
+	poolmanagergrpcclientdomain "github.com/osmosis-labs/sqs/domain/poolmanager/grpcclient"
+	"github.com/osmosis-labs/sqs/domain/swaptx"
 	"github.com/osmosis-labs/sqs/log"
 	"github.com/osmosis-labs/sqs/middleware"
 
@@ -102,7 +108,7 @@ func (sqs *sideCarQueryServer) Start(context.Context) error {
 }
 
 // NewSideCarQueryServer creates a new sidecar query server (SQS).
-func NewSideCarQueryServer(appCodec codec.Codec, config domain.Config, logger log.Logger) (SideCarQueryServer, error) {
+func NewSideCarQueryServer(appCodec codec.Codec, txConfig client.TxConfig, config domain.Config, logger log.Logger) (SideCarQueryServer, error) {
 	// Setup echo server
 	e := echo.New()
 	middleware := middleware.InitMiddleware(config.CORS, config.FlightRecord, logger)
@@ -147,11 +153,17 @@ func NewSideCarQueryServer(appCodec codec.Codec, config domain.Config, logger lo
 	}
 
 	// Initialize candidate route searcher
-	candidateRouteSearcher := routerUseCase.NewCandidateRouteFinder(routerRepository, logger)
+	candidateRouteSearcher, err := routerUseCase.NewCandidateRouteSearcher(routerRepository, poolsUseCase.GetCosmWasmPoolConfig(), config.Router.CandidateRouteAlgorithm, logger)
+	if err != nil {
+		return nil, err
+	}
 
 	// Initialize router repository, usecase
 	routerUsecase := routerUseCase.NewRouterUsecase(routerRepository, poolsUseCase, candidateRouteSearcher, tokensUseCase, *config.Router, poolsUseCase.GetCosmWasmPoolConfig(), logger, cache.New(), cache.New())
 
+	// Guards the router state updated on ingest so that batch quotes are computed over a consistent state.
+	routerStateGuard := domain.NewRouterStateGuard()
+
 	// Initialize system handler
 	chainInfoRepository := chaininforepo.New()
 	chainInfoUseCase := chaininfousecase.NewChainInfoUsecase(chainInfoRepository)
@@ -210,7 +222,26 @@ func NewSideCarQueryServer(appCodec codec.Codec, config domain.Config, logger lo
 	if err := tokenshttpdelivery.NewTokensHandler(e, *config.Pricing, tokensUseCase, pricingSimpleRouterUsecase, logger); err != nil {
 		return nil, err
 	}
-	routerHttpDelivery.NewRouterHandler(e, routerUsecase, tokensUseCase, logger)
+	swapTxBuilder := swaptx.NewTxBuilder(txConfig, appCodec, passthroughGRPCClient.GetChainGRPCClient(), config.ChainID)
+
+	// Quote streaming relies on the end block updates from the grpc ingester.
+	var quoteStreamer mvc.QuoteStreamer
+	if config.QuoteStream != nil && config.QuoteStream.Enabled && config.GRPCIngester.Enabled {
+		quoteStreamer = quotestream.New(routerUsecase, routerStateGuard, *config.QuoteStream, logger)
+	}
+
+	// Quote audit relies on the end block updates from the grpc ingester.
+	var quoteAuditor mvc.QuoteAuditor
+	if config.QuoteAudit != nil && config.QuoteAudit.Enabled && config.GRPCIngester.Enabled {
+		poolManagerClient := poolmanagergrpcclientdomain.New(poolmanagerqueryproto.NewQueryClient(passthroughGRPCClient.GetChainGRPCClient()))
+
+		quoteAuditor, err = quoteaudit.New(routerUsecase, routerStateGuard, poolManagerClient, *config.QuoteAudit, logger)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	routerHttpDelivery.NewRouterHandler(e, routerUsecase, tokensUseCase, routerStateGuard, swapTxBuilder, quoteStreamer, quoteAuditor, logger)
 
 	// Create a Numia HTTP client
 	passthroughConfig := config.Passthrough
@@ -260,6 +291,7 @@ func NewSideCarQueryServer(appCodec codec.Codec, config domain.Config, logger lo
 			quotePriceUpdateWorker,
 			candidateRouteSearchDataWorker,
 			orderBookUseCase,
+			routerStateGuard,
 			logger,
 		)
 
@@ -299,9 +331,22 @@ func NewSideCarQueryServer(appCodec codec.Codec, config domain.Config, logger lo
 			}
 		}
 
+		// Register the quote streamer to push the quote updates at the end of each block.
+		if quoteStreamer != nil {
+			ingestUseCase.RegisterEndBlockProcessPlugin(quoteStreamer)
+		}
+
+		// Register the quote auditor to audit the quotes against the chain at the end of the blocks.
+		if quoteAuditor != nil {
+			ingestUseCase.RegisterEndBlockProcessPlugin(quoteAuditor)
+		}
+
 		// Register chain info use case as a listener to the pool liquidity compute worker (healthcheck).
 		poolLiquidityComputeWorker.RegisterListener(chainInfoUseCase)
 
+		// Register the ingest use case to re-rate the pools once their liquidity capitalization is repriced.
+		poolLiquidityComputeWorker.RegisterListener(ingestUseCase)
+
 		grpcIngestHandler, err := ingestrpcdelivry.NewIngestGRPCHandler(ingestUseCase, *grpcIngesterConfig, logger)
 		if err != nil {
 			panic(err)
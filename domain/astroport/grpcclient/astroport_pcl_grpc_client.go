@@ -0,0 +1,103 @@
+package astroportgrpcclientdomain
+
+import (
+	"context"
+	"fmt"
+
+	cosmwasmdomain "github.com/osmosis-labs/sqs/domain/cosmwasm"
+	"github.com/osmosis-labs/sqs/sqsdomain/json"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+)
+
+// AstroportPCLClient is an interface for fetching the params of the Astroport PCL pools from their contracts.
+type AstroportPCLClient interface {
+	// GetPairAssetDenoms fetches the denoms of the pool assets in the order of the contract.
+	// Returns error if any of the assets is a CW20 token.
+	GetPairAssetDenoms(ctx context.Context, contractAddress string) ([]string, error)
+
+	// GetPoolParams fetches the params of the pool from the config of the contract.
+	// Returns error if the config has no params.
+	GetPoolParams(ctx context.Context, contractAddress string) (PoolParams, error)
+
+	// GetAssetPrecisions fetches the decimal precisions of the given pool asset denoms as registered
+	// in the coin registry of the pair factory, which is where the contract takes them from at instantiation.
+	GetAssetPrecisions(ctx context.Context, contractAddress string, denoms []string) ([]uint32, error)
+}
+
+// astroportPCLClientImpl is an implementation of AstroportPCLClient.
+type astroportPCLClientImpl struct {
+	wasmClient wasmtypes.QueryClient
+}
+
+var _ AstroportPCLClient = (*astroportPCLClientImpl)(nil)
+
+// New creates a new astroportPCLClientImpl.
+func New(wasmClient wasmtypes.QueryClient) *astroportPCLClientImpl {
+	return &astroportPCLClientImpl{
+		wasmClient: wasmClient,
+	}
+}
+
+// GetPairAssetDenoms implements AstroportPCLClient.
+func (a *astroportPCLClientImpl) GetPairAssetDenoms(ctx context.Context, contractAddress string) ([]string, error) {
+	var pair pairResponse
+	if err := cosmwasmdomain.QueryCosmwasmContract(ctx, a.wasmClient, contractAddress, pairRequest{}, &pair); err != nil {
+		return nil, err
+	}
+
+	denoms := make([]string, 0, len(pair.AssetInfos))
+	for _, info := range pair.AssetInfos {
+		if info.NativeToken == nil {
+			return nil, fmt.Errorf("pair %s has a non-native asset", contractAddress)
+		}
+
+		denoms = append(denoms, info.NativeToken.Denom)
+	}
+
+	return denoms, nil
+}
+
+// GetPoolParams implements AstroportPCLClient.
+func (a *astroportPCLClientImpl) GetPoolParams(ctx context.Context, contractAddress string) (PoolParams, error) {
+	var config configResponse
+	if err := cosmwasmdomain.QueryCosmwasmContract(ctx, a.wasmClient, contractAddress, configRequest{}, &config); err != nil {
+		return PoolParams{}, err
+	}
+
+	if len(config.Params) == 0 {
+		return PoolParams{}, fmt.Errorf("pair %s has no params in its config", contractAddress)
+	}
+
+	var params PoolParams
+	if err := json.Unmarshal(config.Params, &params); err != nil {
+		return PoolParams{}, err
+	}
+
+	return params, nil
+}
+
+// GetAssetPrecisions implements AstroportPCLClient.
+func (a *astroportPCLClientImpl) GetAssetPrecisions(ctx context.Context, contractAddress string, denoms []string) ([]uint32, error) {
+	var config configResponse
+	if err := cosmwasmdomain.QueryCosmwasmContract(ctx, a.wasmClient, contractAddress, configRequest{}, &config); err != nil {
+		return nil, err
+	}
+
+	var factoryConfig factoryConfigResponse
+	if err := cosmwasmdomain.QueryCosmwasmContract(ctx, a.wasmClient, config.FactoryAddr, configRequest{}, &factoryConfig); err != nil {
+		return nil, err
+	}
+
+	precisions := make([]uint32, 0, len(denoms))
+	for _, denom := range denoms {
+		var token nativeTokenResponse
+		if err := cosmwasmdomain.QueryCosmwasmContract(ctx, a.wasmClient, factoryConfig.CoinRegistryAddress, nativeTokenRequest{NativeToken: nativeToken{Denom: denom}}, &token); err != nil {
+			return nil, fmt.Errorf("failed to query the precision of %s: %w", denom, err)
+		}
+
+		precisions = append(precisions, token.Decimals)
+	}
+
+	return precisions, nil
+}
@@ -0,0 +1,183 @@
+package astroportgrpcclientdomain_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	astroportgrpcclientdomain "github.com/osmosis-labs/sqs/domain/astroport/grpcclient"
+	"github.com/osmosis-labs/sqs/domain/mocks"
+)
+
+// The fixtures below follow the response format of the pair and config queries of the
+// astroport-pair-concentrated contract, of the config query of its factory and of the native_token query
+// of the coin registry. They are written by hand rather than captured from a node,
+// with the params of a volatile pool, and must be replaced by captured responses when updating the contract version.
+const (
+	pairAddress         = "osmo1pcl"
+	factoryAddress      = "osmo1factory"
+	coinRegistryAddress = "osmo1registry"
+
+	pairQuery   = `{"pair":{}}`
+	configQuery = `{"config":{}}`
+
+	pairResponseFixture = `{
+		"asset_infos": [
+			{"native_token": {"denom": "uosmo"}},
+			{"native_token": {"denom": "ibc/D189335C6E4A68B513C10AB227BF1C1D38C746766278BA3EEB4FB14124F1D858"}}
+		],
+		"contract_addr": "osmo1pcl",
+		"liquidity_token": "factory/osmo1pcl/astroport/share",
+		"pair_type": {"custom": "concentrated"}
+	}`
+
+	pairResponseCW20Fixture = `{
+		"asset_infos": [
+			{"native_token": {"denom": "uosmo"}},
+			{"token": {"contract_addr": "osmo1cw20"}}
+		],
+		"contract_addr": "osmo1pcl",
+		"liquidity_token": "factory/osmo1pcl/astroport/share",
+		"pair_type": {"custom": "concentrated"}
+	}`
+
+	// The params decode to:
+	// {"amp":"40","gamma":"0.000145","mid_fee":"0.0026","out_fee":"0.0045","fee_gamma":"0.00023",
+	// "repeg_profit_threshold":"0.000002","min_price_scale_delta":"0.000146","price_scale":"0.302571428571428571",
+	// "ma_half_time":600,"track_asset_balances":false,"fee_share":null}
+	configResponseFixture = `{
+		"block_time_last": 1718000000,
+		"params": "eyJhbXAiOiI0MCIsImdhbW1hIjoiMC4wMDAxNDUiLCJtaWRfZmVlIjoiMC4wMDI2Iiwib3V0X2ZlZSI6IjAuMDA0NSIsImZlZV9nYW1tYSI6IjAuMDAwMjMiLCJyZXBlZ19wcm9maXRfdGhyZXNob2xkIjoiMC4wMDAwMDIiLCJtaW5fcHJpY2Vfc2NhbGVfZGVsdGEiOiIwLjAwMDE0NiIsInByaWNlX3NjYWxlIjoiMC4zMDI1NzE0Mjg1NzE0Mjg1NzEiLCJtYV9oYWxmX3RpbWUiOjYwMCwidHJhY2tfYXNzZXRfYmFsYW5jZXMiOmZhbHNlLCJmZWVfc2hhcmUiOm51bGx9",
+		"owner": "osmo1owner",
+		"factory_addr": "osmo1factory",
+		"tracker_addr": null
+	}`
+
+	factoryConfigResponseFixture = `{
+		"owner": "osmo1owner",
+		"pair_configs": [],
+		"token_code_id": 0,
+		"fee_address": null,
+		"generator_address": null,
+		"whitelist_code_id": 0,
+		"coin_registry_address": "osmo1registry"
+	}`
+
+	configResponseNoParamsFixture = `{
+		"block_time_last": 1718000000,
+		"params": null,
+		"owner": "osmo1owner",
+		"factory_addr": "osmo1factory",
+		"tracker_addr": null
+	}`
+)
+
+// newWasmClient returns a wasm client that responds to the given queries of the given contract addresses with the given responses.
+func newWasmClient(responses map[string]map[string]string) *mocks.WasmQueryClientMock {
+	return &mocks.WasmQueryClientMock{
+		SmartContractStateFunc: func(ctx context.Context, in *wasmtypes.QuerySmartContractStateRequest, opts ...grpc.CallOption) (*wasmtypes.QuerySmartContractStateResponse, error) {
+			response, ok := responses[in.Address][string(in.QueryData)]
+			if !ok {
+				return nil, errors.New("unexpected query " + string(in.QueryData) + " to " + in.Address)
+			}
+
+			return &wasmtypes.QuerySmartContractStateResponse{Data: []byte(response)}, nil
+		},
+	}
+}
+
+func TestGetPairAssetDenoms(t *testing.T) {
+	tests := map[string]struct {
+		response       string
+		expectedDenoms []string
+		expectedError  bool
+	}{
+		"native assets": {
+			response:       pairResponseFixture,
+			expectedDenoms: []string{"uosmo", "ibc/D189335C6E4A68B513C10AB227BF1C1D38C746766278BA3EEB4FB14124F1D858"},
+		},
+		"cw20 asset": {
+			response:      pairResponseCW20Fixture,
+			expectedError: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := astroportgrpcclientdomain.New(newWasmClient(map[string]map[string]string{pairAddress: {pairQuery: tc.response}}))
+
+			denoms, err := client.GetPairAssetDenoms(context.Background(), pairAddress)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedDenoms, denoms)
+		})
+	}
+}
+
+func TestGetPoolParams(t *testing.T) {
+	tests := map[string]struct {
+		response       string
+		expectedParams astroportgrpcclientdomain.PoolParams
+		expectedError  bool
+	}{
+		"params": {
+			response: configResponseFixture,
+			expectedParams: astroportgrpcclientdomain.PoolParams{
+				Amp:        osmomath.NewDec(40),
+				Gamma:      osmomath.MustNewDecFromStr("0.000145"),
+				MidFee:     osmomath.MustNewDecFromStr("0.0026"),
+				OutFee:     osmomath.MustNewDecFromStr("0.0045"),
+				FeeGamma:   osmomath.MustNewDecFromStr("0.00023"),
+				PriceScale: osmomath.MustNewDecFromStr("0.302571428571428571"),
+			},
+		},
+		"no params": {
+			response:      configResponseNoParamsFixture,
+			expectedError: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			client := astroportgrpcclientdomain.New(newWasmClient(map[string]map[string]string{pairAddress: {configQuery: tc.response}}))
+
+			params, err := client.GetPoolParams(context.Background(), pairAddress)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedParams, params)
+		})
+	}
+}
+
+func TestGetAssetPrecisions(t *testing.T) {
+	client := astroportgrpcclientdomain.New(newWasmClient(map[string]map[string]string{
+		pairAddress:    {configQuery: configResponseFixture},
+		factoryAddress: {configQuery: factoryConfigResponseFixture},
+		coinRegistryAddress: {
+			`{"native_token":{"denom":"uosmo"}}`:    `{"denom": "uosmo", "decimals": 6}`,
+			`{"native_token":{"denom":"weth-wei"}}`: `{"denom": "weth-wei", "decimals": 18}`,
+		},
+	}))
+
+	precisions, err := client.GetAssetPrecisions(context.Background(), pairAddress, []string{"uosmo", "weth-wei"})
+	require.NoError(t, err)
+	require.Equal(t, []uint32{6, 18}, precisions)
+
+	// The denom is not registered.
+	_, err = client.GetAssetPrecisions(context.Background(), pairAddress, []string{"uosmo", "uatom"})
+	require.Error(t, err)
+}
@@ -0,0 +1,77 @@
+package astroportgrpcclientdomain
+
+import (
+	"github.com/osmosis-labs/osmosis/osmomath"
+)
+
+// pairRequest is a struct that represents the payload for the pair query.
+type pairRequest struct {
+	Pair struct{} `json:"pair"`
+}
+
+// nativeTokenInfo is a struct that represents the native token variant of the asset info.
+type nativeTokenInfo struct {
+	Denom string `json:"denom"`
+}
+
+// cw20TokenInfo is a struct that represents the CW20 token variant of the asset info.
+type cw20TokenInfo struct {
+	ContractAddr string `json:"contract_addr"`
+}
+
+// assetInfo is a struct that represents the asset info of the pair.
+// Exactly one of the variants is set.
+type assetInfo struct {
+	NativeToken *nativeTokenInfo `json:"native_token,omitempty"`
+	Token       *cw20TokenInfo   `json:"token,omitempty"`
+}
+
+// pairResponse is a struct that represents the response payload for the pair query.
+type pairResponse struct {
+	AssetInfos []assetInfo `json:"asset_infos"`
+}
+
+// configRequest is a struct that represents the payload for the config query.
+type configRequest struct {
+	Config struct{} `json:"config"`
+}
+
+// configResponse is a struct that represents the response payload for the config query.
+// The params are the JSON encoded PoolParams, serialized as base64 by the contract.
+type configResponse struct {
+	BlockTimeLast uint64 `json:"block_time_last"`
+	Params        []byte `json:"params"`
+	FactoryAddr   string `json:"factory_addr"`
+}
+
+// factoryConfigResponse is a struct that represents the response payload for the config query of the pair factory.
+type factoryConfigResponse struct {
+	CoinRegistryAddress string `json:"coin_registry_address"`
+}
+
+// nativeToken is a struct that represents the request payload for the native_token query of the coin registry.
+type nativeToken struct {
+	Denom string `json:"denom"`
+}
+
+// nativeTokenRequest is a struct that represents the payload for the native_token query of the coin registry.
+type nativeTokenRequest struct {
+	NativeToken nativeToken `json:"native_token"`
+}
+
+// nativeTokenResponse is a struct that represents the response payload for the native_token query of the coin registry.
+type nativeTokenResponse struct {
+	Denom    string `json:"denom"`
+	Decimals uint32 `json:"decimals"`
+}
+
+// PoolParams is a struct that represents the params of the pool decoded from the config query.
+// The amp and gamma are those at the block of the query, accounting for any ongoing ramp.
+type PoolParams struct {
+	Amp        osmomath.Dec `json:"amp"`
+	Gamma      osmomath.Dec `json:"gamma"`
+	MidFee     osmomath.Dec `json:"mid_fee"`
+	OutFee     osmomath.Dec `json:"out_fee"`
+	FeeGamma   osmomath.Dec `json:"fee_gamma"`
+	PriceScale osmomath.Dec `json:"price_scale"`
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+)
+
+// BasketLeg is a single exact amount in swap of a basket swap.
+type BasketLeg struct {
+	TokenIn       sdk.Coin
+	TokenOutDenom string
+}
+
+// BasketQuote is the combined quote of the legs of a basket swap.
+// The legs are quoted in order. The amount out of each leg accounts for the liquidity
+// consumed by the preceding legs that swap through the same pools in the same direction.
+// Since the routes of each leg are chosen against the pre-trade state, the quote depends on the order of the legs.
+type BasketQuote struct {
+	// AmountIn is the total amount in across the legs per denom.
+	AmountIn sdk.Coins `json:"amount_in"`
+	// AmountOut is the total amount out across the legs per denom.
+	AmountOut sdk.Coins `json:"amount_out"`
+	// Legs are the quotes of the legs in the order of the request.
+	Legs []BasketQuoteLeg `json:"legs"`
+}
+
+// BasketQuoteLeg is the quote of a single leg of a basket swap.
+type BasketQuoteLeg struct {
+	// Quote is the prepared quote of the leg with the amounts out adjusted for the shared pools.
+	Quote Quote `json:"quote"`
+	// StandaloneAmountOut is the amount out of the leg if it were swapped on its own.
+	StandaloneAmountOut osmomath.Int `json:"standalone_amount_out"`
+	// SharedPoolIDs are the IDs of the pools in the routes of the leg that the preceding legs swap through
+	// in the same direction.
+	SharedPoolIDs []uint64 `json:"shared_pool_ids"`
+}
+
+// NewWeightedBasketLegs returns the legs swapping the given token in into each of the token out denoms
+// pro-rata to the given weights. The weights need not sum to one. The remainder of rounding the amounts down
+// is swapped into the first token out denom.
+// Returns error if:
+// - the number of weights does not match the number of token out denoms
+// - any of the weights is not positive
+// - any of the legs would have a zero amount in
+func NewWeightedBasketLegs(tokenIn sdk.Coin, tokenOutDenoms []string, weights []osmomath.Dec) ([]BasketLeg, error) {
+	if len(tokenOutDenoms) == 0 {
+		return nil, errors.New("at least one token out denom is required")
+	}
+
+	if len(weights) != len(tokenOutDenoms) {
+		return nil, fmt.Errorf("number of weights (%d) must be equal to the number of token out denoms (%d)", len(weights), len(tokenOutDenoms))
+	}
+
+	totalWeight := osmomath.ZeroDec()
+	for _, weight := range weights {
+		if weight.IsNil() || !weight.IsPositive() {
+			return nil, fmt.Errorf("weight (%s) must be positive", weight)
+		}
+		totalWeight = totalWeight.Add(weight)
+	}
+
+	legs := make([]BasketLeg, 0, len(tokenOutDenoms))
+	remainder := tokenIn.Amount
+	for i, tokenOutDenom := range tokenOutDenoms {
+		amountIn := tokenIn.Amount.ToLegacyDec().MulMut(weights[i]).QuoMut(totalWeight).TruncateInt()
+		remainder = remainder.Sub(amountIn)
+
+		legs = append(legs, BasketLeg{
+			TokenIn:       sdk.NewCoin(tokenIn.Denom, amountIn),
+			TokenOutDenom: tokenOutDenom,
+		})
+	}
+	legs[0].TokenIn.Amount = legs[0].TokenIn.Amount.Add(remainder)
+
+	for _, leg := range legs {
+		if !leg.TokenIn.Amount.IsPositive() {
+			return nil, fmt.Errorf("amount in of the leg into %s must be positive", leg.TokenOutDenom)
+		}
+	}
+
+	return legs, nil
+}
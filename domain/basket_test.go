@@ -0,0 +1,99 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	"github.com/osmosis-labs/sqs/domain"
+)
+
+func TestNewWeightedBasketLegs(t *testing.T) {
+	tests := []struct {
+		name             string
+		tokenIn          sdk.Coin
+		tokenOutDenoms   []string
+		weights          []osmomath.Dec
+		expectedAmountIn []osmomath.Int
+		expectErr        bool
+	}{
+		{
+			name:           "weights summing to one",
+			tokenIn:        sdk.NewCoin("uusdc", osmomath.NewInt(1000)),
+			tokenOutDenoms: []string{"uatom", "uosmo", "utia"},
+			weights:        []osmomath.Dec{osmomath.MustNewDecFromStr("0.4"), osmomath.MustNewDecFromStr("0.3"), osmomath.MustNewDecFromStr("0.3")},
+			expectedAmountIn: []osmomath.Int{
+				osmomath.NewInt(400),
+				osmomath.NewInt(300),
+				osmomath.NewInt(300),
+			},
+		},
+		{
+			name:           "unnormalized weights with the remainder in the first leg",
+			tokenIn:        sdk.NewCoin("uusdc", osmomath.NewInt(1000)),
+			tokenOutDenoms: []string{"uatom", "uosmo", "utia"},
+			weights:        []osmomath.Dec{osmomath.OneDec(), osmomath.OneDec(), osmomath.OneDec()},
+			expectedAmountIn: []osmomath.Int{
+				osmomath.NewInt(334),
+				osmomath.NewInt(333),
+				osmomath.NewInt(333),
+			},
+		},
+		{
+			name:           "single token out denom",
+			tokenIn:        sdk.NewCoin("uusdc", osmomath.NewInt(1000)),
+			tokenOutDenoms: []string{"uatom"},
+			weights:        []osmomath.Dec{osmomath.MustNewDecFromStr("0.1")},
+			expectedAmountIn: []osmomath.Int{
+				osmomath.NewInt(1000),
+			},
+		},
+		{
+			name:           "weights mismatch",
+			tokenIn:        sdk.NewCoin("uusdc", osmomath.NewInt(1000)),
+			tokenOutDenoms: []string{"uatom", "uosmo"},
+			weights:        []osmomath.Dec{osmomath.OneDec()},
+			expectErr:      true,
+		},
+		{
+			name:           "zero weight",
+			tokenIn:        sdk.NewCoin("uusdc", osmomath.NewInt(1000)),
+			tokenOutDenoms: []string{"uatom", "uosmo"},
+			weights:        []osmomath.Dec{osmomath.OneDec(), osmomath.ZeroDec()},
+			expectErr:      true,
+		},
+		{
+			name:           "zero amount in leg",
+			tokenIn:        sdk.NewCoin("uusdc", osmomath.NewInt(10)),
+			tokenOutDenoms: []string{"uatom", "uosmo"},
+			weights:        []osmomath.Dec{osmomath.NewDec(100), osmomath.OneDec()},
+			expectErr:      true,
+		},
+		{
+			name:      "no token out denoms",
+			tokenIn:   sdk.NewCoin("uusdc", osmomath.NewInt(1000)),
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			legs, err := domain.NewWeightedBasketLegs(tc.tokenIn, tc.tokenOutDenoms, tc.weights)
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			require.Len(t, legs, len(tc.expectedAmountIn))
+			for i, leg := range legs {
+				require.Equal(t, tc.tokenIn.Denom, leg.TokenIn.Denom)
+				require.Equal(t, tc.expectedAmountIn[i].String(), leg.TokenIn.Amount.String())
+				require.Equal(t, tc.tokenOutDenoms[i], leg.TokenOutDenom)
+			}
+		})
+	}
+}
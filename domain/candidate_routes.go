@@ -3,6 +3,9 @@ package domain
 import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/osmosis-labs/sqs/sqsdomain"
+
+	cosmwasmpooltypes "github.com/osmosis-labs/osmosis/v25/x/cosmwasmpool/types"
+	poolmanagertypes "github.com/osmosis-labs/osmosis/v25/x/poolmanager/types"
 )
 
 // CandidateRoutePoolFiltrerCb defines a candidate route pool filter
@@ -26,6 +29,18 @@ type CandidateRouteSearchOptions struct {
 	// If at least one of the callbacks in-slice returns true, the ShouldSkipPool function will
 	// also return true.
 	PoolFiltersAnyOf []CandidateRoutePoolFiltrerCb
+
+	// IntermediaryDenomsToSkip are the denoms that the candidate routes
+	// must not go through. Token in and token out denoms are unaffected.
+	IntermediaryDenomsToSkip map[string]struct{}
+
+	// LiquidityFilteredPoolCb is called with the ID of each pool skipped for having
+	// liquidity capitalization below MinPoolLiquidityCap. Optional.
+	LiquidityFilteredPoolCb func(poolID uint64)
+
+	// Algorithm is the candidate route search algorithm.
+	// If empty, the default algorithm of the searcher is used.
+	Algorithm CandidateRouteAlgorithm
 }
 
 // ShouldSkipPool returns true if the candidate route algorithm should skip
@@ -39,6 +54,13 @@ func (c CandidateRouteSearchOptions) ShouldSkipPool(pool *sqsdomain.PoolWrapper)
 	return false
 }
 
+// ShouldSkipIntermediaryDenom returns true if the candidate route algorithm should not
+// route through the given intermediary denom.
+func (c CandidateRouteSearchOptions) ShouldSkipIntermediaryDenom(denom string) bool {
+	_, ok := c.IntermediaryDenomsToSkip[denom]
+	return ok
+}
+
 // CandidateRoutePoolIDFilterOptionCb encapsulates the pool IDs that should be skipped by the candidate route
 // algorithm, exposing an API to determine whether the given pool mathes any of the pool IDs that
 // should be skipped.
@@ -53,6 +75,131 @@ func (c CandidateRoutePoolIDFilterOptionCb) ShouldSkipPool(pool *sqsdomain.PoolW
 	return ok
 }
 
+// CandidateRouteAlgorithm is the algorithm of the candidate route search.
+type CandidateRouteAlgorithm string
+
+const (
+	// CandidateRouteAlgorithmBFS searches the candidate routes breadth-first over the pools ranked by liquidity.
+	CandidateRouteAlgorithmBFS CandidateRouteAlgorithm = "bfs"
+	// CandidateRouteAlgorithmPriceAware searches the k shortest paths over the pool graph
+	// with the edges weighted by the estimated swap rates net of the spread factors.
+	CandidateRouteAlgorithmPriceAware CandidateRouteAlgorithm = "price-aware"
+)
+
+// CandidateRouteAlgorithms are all the supported candidate route algorithms.
+var CandidateRouteAlgorithms = []CandidateRouteAlgorithm{
+	CandidateRouteAlgorithmBFS,
+	CandidateRouteAlgorithmPriceAware,
+}
+
+// IsValid returns true if the algorithm is one of CandidateRouteAlgorithms.
+func (a CandidateRouteAlgorithm) IsValid() bool {
+	for _, algorithm := range CandidateRouteAlgorithms {
+		if a == algorithm {
+			return true
+		}
+	}
+	return false
+}
+
+// CandidateRoutePoolType is the type of pool that the candidate route search may be restricted to.
+// It extends the poolmanager pool types with the kinds of CosmWasm pools supported by the router.
+type CandidateRoutePoolType string
+
+const (
+	CandidateRoutePoolTypeBalancer          CandidateRoutePoolType = "balancer"
+	CandidateRoutePoolTypeStableswap        CandidateRoutePoolType = "stableswap"
+	CandidateRoutePoolTypeConcentrated      CandidateRoutePoolType = "concentrated"
+	CandidateRoutePoolTypeTransmuter        CandidateRoutePoolType = "transmuter"
+	CandidateRoutePoolTypeAlloyedTransmuter CandidateRoutePoolType = "alloyed_transmuter"
+	CandidateRoutePoolTypeOrderbook         CandidateRoutePoolType = "orderbook"
+	// CandidateRoutePoolTypeCosmWasm is the type of generalized CosmWasm pools.
+	CandidateRoutePoolTypeCosmWasm CandidateRoutePoolType = "cosmwasm"
+)
+
+// CandidateRoutePoolTypes are all the supported candidate route pool types.
+var CandidateRoutePoolTypes = []CandidateRoutePoolType{
+	CandidateRoutePoolTypeBalancer,
+	CandidateRoutePoolTypeStableswap,
+	CandidateRoutePoolTypeConcentrated,
+	CandidateRoutePoolTypeTransmuter,
+	CandidateRoutePoolTypeAlloyedTransmuter,
+	CandidateRoutePoolTypeOrderbook,
+	CandidateRoutePoolTypeCosmWasm,
+}
+
+// IsValid returns true if the pool type is one of CandidateRoutePoolTypes.
+func (t CandidateRoutePoolType) IsValid() bool {
+	for _, poolType := range CandidateRoutePoolTypes {
+		if t == poolType {
+			return true
+		}
+	}
+	return false
+}
+
+// GetCandidateRoutePoolType returns the candidate route pool type of the given pool.
+// CosmWasm pools matched by a configured implementation are classified by its name regardless of their code ID.
+// Other CosmWasm pools that are neither orderbooks, alloyed transmuters nor have one of the
+// transmuter code IDs are considered generalized CosmWasm pools.
+func GetCandidateRoutePoolType(pool *sqsdomain.PoolWrapper, cosmWasmPoolsConfig CosmWasmPoolRouterConfig) CandidateRoutePoolType {
+	switch pool.GetType() {
+	case poolmanagertypes.Balancer:
+		return CandidateRoutePoolTypeBalancer
+	case poolmanagertypes.Stableswap:
+		return CandidateRoutePoolTypeStableswap
+	case poolmanagertypes.Concentrated:
+		return CandidateRoutePoolTypeConcentrated
+	}
+
+	cosmWasmPoolModel := pool.SQSModel.CosmWasmPoolModel
+	if implementationName, ok := cosmWasmPoolsConfig.GetImplementation(cosmWasmPoolModel); ok {
+		switch implementationName {
+		case TransmuterCosmWasmPoolImplementation:
+			return CandidateRoutePoolTypeTransmuter
+		case AlloyedTransmuterCosmWasmPoolImplementation:
+			return CandidateRoutePoolTypeAlloyedTransmuter
+		case OrderbookCosmWasmPoolImplementation:
+			return CandidateRoutePoolTypeOrderbook
+		default:
+			return CandidateRoutePoolTypeCosmWasm
+		}
+	}
+
+	if cosmWasmPoolModel != nil {
+		if cosmWasmPoolModel.IsOrderbook() {
+			return CandidateRoutePoolTypeOrderbook
+		}
+
+		if cosmWasmPoolModel.IsAlloyTransmuter() {
+			return CandidateRoutePoolTypeAlloyedTransmuter
+		}
+	}
+
+	if cosmWasmPool, ok := pool.GetUnderlyingPool().(cosmwasmpooltypes.CosmWasmExtension); ok {
+		if _, isTransmuter := cosmWasmPoolsConfig.TransmuterCodeIDs[cosmWasmPool.GetCodeId()]; isTransmuter {
+			return CandidateRoutePoolTypeTransmuter
+		}
+	}
+
+	return CandidateRoutePoolTypeCosmWasm
+}
+
+// CandidateRoutePoolTypeFilterOptionCb encapsulates the pool types that the candidate route
+// algorithm is restricted to, exposing an API to determine whether the given pool is of any other type.
+type CandidateRoutePoolTypeFilterOptionCb struct {
+	PoolTypesToKeep map[CandidateRoutePoolType]struct{}
+	// CosmWasmPoolsConfig distinguishes the transmuter pools from the generalized CosmWasm pools
+	// by their implementation or code ID.
+	CosmWasmPoolsConfig CosmWasmPoolRouterConfig
+}
+
+// ShouldSkipPool returns true if the type of the given pool is not present in c.PoolTypesToKeep
+func (c CandidateRoutePoolTypeFilterOptionCb) ShouldSkipPool(pool *sqsdomain.PoolWrapper) bool {
+	_, ok := c.PoolTypesToKeep[GetCandidateRoutePoolType(pool, c.CosmWasmPoolsConfig)]
+	return !ok
+}
+
 var (
 	// ShouldSkipOrderbookPool skips orderbook pools
 	// by returning true if pool.SQSModel.CosmWasmPoolModel is not nil
@@ -69,6 +216,11 @@ type CandidateRouteSearcher interface {
 	// using the given options.
 	// Returns the candidate routes and an error if any.
 	FindCandidateRoutes(tokenIn sdk.Coin, tokenOutDenom string, options CandidateRouteSearchOptions) (sqsdomain.CandidateRoutes, error)
+
+	// FindCandidateCycles finds candidate routes starting and ending in the given denom
+	// using the given options.
+	// Returns the candidate cycles and an error if any.
+	FindCandidateCycles(denom string, options CandidateRouteSearchOptions) (sqsdomain.CandidateRoutes, error)
 }
 
 // CandidateRouteDenomData represents the data for a candidate route for a given denom.
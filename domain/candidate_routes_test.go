@@ -8,6 +8,8 @@ import (
 	"github.com/osmosis-labs/sqs/sqsdomain"
 	"github.com/osmosis-labs/sqs/sqsdomain/cosmwasmpool"
 	"github.com/stretchr/testify/require"
+
+	poolmanagertypes "github.com/osmosis-labs/osmosis/v25/x/poolmanager/types"
 )
 
 // This test validates the ShouldSkipPool() method of the candidate route search
@@ -134,3 +136,175 @@ func TestCandidateRouteSearchOptions_ShouldSkipPool(t *testing.T) {
 		})
 	}
 }
+
+// This test validates that the pool type filter skips the pools
+// of the types that are not present in the filter.
+func TestCandidateRoutePoolTypeFilterOptionCb_ShouldSkipPool(t *testing.T) {
+	var (
+		balancerPool = sqsdomain.PoolWrapper{
+			ChainModel: &mocks.ChainPoolMock{ID: 1, Type: poolmanagertypes.Balancer},
+		}
+		concentratedPool = sqsdomain.PoolWrapper{
+			ChainModel: &mocks.ChainPoolMock{ID: 2, Type: poolmanagertypes.Concentrated},
+		}
+		orderbookPool = sqsdomain.PoolWrapper{
+			ChainModel: &mocks.ChainPoolMock{ID: 3, Type: poolmanagertypes.CosmWasm},
+			SQSModel: sqsdomain.SQSPool{
+				CosmWasmPoolModel: &cosmwasmpool.CosmWasmPoolModel{
+					ContractInfo: cosmwasmpool.ContractInfo{
+						Contract: cosmwasmpool.ORDERBOOK_CONTRACT_NAME,
+						Version:  cosmwasmpool.ORDERBOOK_MIN_CONTRACT_VERSION,
+					},
+					Data: cosmwasmpool.CosmWasmPoolData{
+						Orderbook: &cosmwasmpool.OrderbookData{},
+					},
+				},
+			},
+		}
+		// Does not have any of the transmuter code IDs.
+		generalizedCosmWasmPool = sqsdomain.PoolWrapper{
+			ChainModel: &mocks.ChainPoolMock{ID: 4, Type: poolmanagertypes.CosmWasm},
+		}
+	)
+
+	tests := []struct {
+		name string
+
+		poolTypesToKeep []domain.CandidateRoutePoolType
+
+		poolToTest sqsdomain.PoolWrapper
+
+		expectedShouldSkip bool
+	}{
+		{
+			name: "balancer pool, balancer kept -> returns false",
+
+			poolTypesToKeep: []domain.CandidateRoutePoolType{domain.CandidateRoutePoolTypeBalancer},
+			poolToTest:      balancerPool,
+
+			expectedShouldSkip: false,
+		},
+		{
+			name: "concentrated pool, balancer kept -> returns true",
+
+			poolTypesToKeep: []domain.CandidateRoutePoolType{domain.CandidateRoutePoolTypeBalancer},
+			poolToTest:      concentratedPool,
+
+			expectedShouldSkip: true,
+		},
+		{
+			name: "orderbook pool, orderbook kept -> returns false",
+
+			poolTypesToKeep: []domain.CandidateRoutePoolType{domain.CandidateRoutePoolTypeBalancer, domain.CandidateRoutePoolTypeOrderbook},
+			poolToTest:      orderbookPool,
+
+			expectedShouldSkip: false,
+		},
+		{
+			name: "orderbook pool, generalized cosmwasm kept -> returns true",
+
+			poolTypesToKeep: []domain.CandidateRoutePoolType{domain.CandidateRoutePoolTypeCosmWasm},
+			poolToTest:      orderbookPool,
+
+			expectedShouldSkip: true,
+		},
+		{
+			name: "generalized cosmwasm pool, transmuter kept -> returns true",
+
+			poolTypesToKeep: []domain.CandidateRoutePoolType{domain.CandidateRoutePoolTypeTransmuter},
+			poolToTest:      generalizedCosmWasmPool,
+
+			expectedShouldSkip: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			poolTypeFilter := domain.CandidateRoutePoolTypeFilterOptionCb{
+				PoolTypesToKeep: map[domain.CandidateRoutePoolType]struct{}{},
+			}
+			for _, poolType := range tc.poolTypesToKeep {
+				poolTypeFilter.PoolTypesToKeep[poolType] = struct{}{}
+			}
+
+			// System under test.
+			shouldSkip := poolTypeFilter.ShouldSkipPool(&tc.poolToTest)
+
+			// Validate result.
+			require.Equal(t, tc.expectedShouldSkip, shouldSkip)
+		})
+	}
+}
+
+// This test validates that the CosmWasm pools matched by a configured implementation
+// are classified by its name even if their code ID is not configured.
+func TestGetCandidateRoutePoolType_Implementations(t *testing.T) {
+	transmuterMatcher, err := cosmwasmpool.NewContractMatcher(cosmwasmpool.ALLOY_TRANSMUTER_CONTRACT_NAME, "< "+cosmwasmpool.ALLOY_TRANSMUTER_MIN_CONTRACT_VERSION)
+	require.NoError(t, err)
+
+	var (
+		// Transmuter v1 pool whose code ID is not in the transmuter code IDs.
+		registryOnlyTransmuterPool = sqsdomain.PoolWrapper{
+			ChainModel: &mocks.ChainPoolMock{ID: 1, Type: poolmanagertypes.CosmWasm},
+			SQSModel: sqsdomain.SQSPool{
+				CosmWasmPoolModel: &cosmwasmpool.CosmWasmPoolModel{
+					ContractInfo: cosmwasmpool.ContractInfo{
+						Contract: cosmwasmpool.ALLOY_TRANSMUTER_CONTRACT_NAME,
+						Version:  "2.0.0",
+					},
+				},
+			},
+		}
+
+		registryConfig = domain.CosmWasmPoolRouterConfig{
+			TransmuterCodeIDs: map[uint64]struct{}{},
+			Implementations: []domain.CosmWasmPoolImplementation{
+				{Matcher: transmuterMatcher, Name: domain.TransmuterCosmWasmPoolImplementation},
+			},
+		}
+	)
+
+	tests := []struct {
+		name string
+
+		cosmWasmPoolsConfig domain.CosmWasmPoolRouterConfig
+
+		expectedPoolType domain.CandidateRoutePoolType
+	}{
+		{
+			name: "matched by the transmuter implementation -> transmuter",
+
+			cosmWasmPoolsConfig: registryConfig,
+
+			expectedPoolType: domain.CandidateRoutePoolTypeTransmuter,
+		},
+		{
+			name: "no implementation and no transmuter code ID -> generalized cosmwasm",
+
+			cosmWasmPoolsConfig: domain.CosmWasmPoolRouterConfig{
+				TransmuterCodeIDs: map[uint64]struct{}{},
+			},
+
+			expectedPoolType: domain.CandidateRoutePoolTypeCosmWasm,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			// System under test.
+			poolType := domain.GetCandidateRoutePoolType(&registryOnlyTransmuterPool, tc.cosmWasmPoolsConfig)
+
+			// Validate result.
+			require.Equal(t, tc.expectedPoolType, poolType)
+
+			// The pool type filter must agree with the classification.
+			poolTypeFilter := domain.CandidateRoutePoolTypeFilterOptionCb{
+				PoolTypesToKeep:     map[domain.CandidateRoutePoolType]struct{}{domain.CandidateRoutePoolTypeTransmuter: {}},
+				CosmWasmPoolsConfig: tc.cosmWasmPoolsConfig,
+			}
+			require.Equal(t, tc.expectedPoolType != domain.CandidateRoutePoolTypeTransmuter, poolTypeFilter.ShouldSkipPool(&registryOnlyTransmuterPool))
+		})
+	}
+}
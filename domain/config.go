@@ -60,6 +60,12 @@ type Config struct {
 
 	// SideCarQueryServer CORS configuration.
 	CORS *CORSConfig `mapstructure:"cors"`
+
+	// QuoteStream encapsulates the quote streaming config.
+	QuoteStream *QuoteStreamConfig `mapstructure:"quote-stream"`
+
+	// QuoteAudit encapsulates the quote audit config.
+	QuoteAudit *QuoteAuditConfig `mapstructure:"quote-audit"`
 }
 
 const envPrefix = "SQS"
@@ -100,16 +106,26 @@ var (
 				641,
 				842,
 			},
+			GeneralCosmWasmCalcQueryBudget:        0,
+			GeneralCosmWasmCalcAmountBucketDigits: 0,
+			GeneralCosmWasmCalcMemoMaxEntries:     10000,
+			CosmWasmPoolImplementations:           []CosmWasmPoolImplementationConfig{},
+			AstroportPCLEnabled:                   false,
 		},
 		Router: &RouterConfig{
-			PreferredPoolIDs:                 []uint64{},
-			MaxPoolsPerRoute:                 4,
-			MaxRoutes:                        20,
-			MaxSplitRoutes:                   3,
-			MinPoolLiquidityCap:              0,
-			RouteCacheEnabled:                true,
-			CandidateRouteCacheExpirySeconds: 1200,
-			RankedRouteCacheExpirySeconds:    45,
+			PreferredPoolIDs:                       []uint64{},
+			MaxPoolsPerRoute:                       4,
+			MaxRoutes:                              20,
+			MaxSplitRoutes:                         3,
+			SplitIncrements:                        DefaultSplitIncrements,
+			SplitRefinementRounds:                  0,
+			MinPoolLiquidityCap:                    0,
+			RouteCacheEnabled:                      true,
+			CandidateRouteCacheExpirySeconds:       1200,
+			RankedRouteCacheExpirySeconds:          45,
+			RouteCacheInvalidationEnabled:          true,
+			CandidateRouteCacheInvalidationEnabled: false,
+			PoolFullSortIntervalBlocks:             50,
 			DynamicMinLiquidityCapFiltersDesc: []DynamicMinLiquidityCapFilterEntry{
 				{
 					MinTokensCap: 1000000,
@@ -132,6 +148,21 @@ var (
 					FilterValue:  1,
 				},
 			},
+			GasCostModel: GasCostModelConfig{
+				Enabled:                     true,
+				FeeDenom:                    "uosmo",
+				GasPrice:                    "0.0025",
+				BaseGas:                     100000,
+				BalancerGas:                 50000,
+				StableSwapGas:               70000,
+				ConcentratedGas:             80000,
+				ConcentratedTickCrossingGas: 30000,
+				CosmWasmGas:                 150000,
+				OrderbookTickGas:            25000,
+			},
+			CandidateRouteAlgorithm: CandidateRouteAlgorithmBFS,
+			QuoteComputeDeadlineMs:  0,
+			StateGuardTimeoutMs:     5000,
 		},
 		Pricing: &PricingConfig{
 			CacheExpiryMs:             2000,
@@ -175,6 +206,21 @@ var (
 			AllowedMethods: "HEAD, GET, POST, HEAD, GET, POST, DELETE, OPTIONS, PATCH, PUT",
 			AllowedOrigin:  "*",
 		},
+		QuoteStream: &QuoteStreamConfig{
+			Enabled:                  false,
+			MaxSubscriptions:         1000,
+			MaxQuotesPerSubscription: 10,
+		},
+		QuoteAudit: &QuoteAuditConfig{
+			Enabled:               false,
+			IntervalBlocks:        10,
+			Pairs:                 []QuoteAuditPairConfig{},
+			ServedQuoteSampleRate: 0.01,
+			MaxSampledQuotes:      100,
+			MaxWorstDivergences:   20,
+			PairsComputeTimeoutMs: 1000,
+			ChainQueryTimeoutMs:   5000,
+		},
 	}
 )
 
@@ -367,6 +413,11 @@ func (c Config) Validate() error {
 		return err
 	}
 
+	// Validate the gas cost model.
+	if err := c.Router.GasCostModel.Validate(); err != nil {
+		return err
+	}
+
 	return nil
 }
 
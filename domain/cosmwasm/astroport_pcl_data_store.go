@@ -0,0 +1,40 @@
+package cosmwasmdomain
+
+import (
+	"sync"
+
+	"github.com/osmosis-labs/sqs/sqsdomain/cosmwasmpool"
+)
+
+// AstroportPCLDataStore stores the PCL data of the Astroport PCL pools fetched from their contracts
+// for the pools whose ingested model does not carry it.
+// The stored data is never mutated, a refresh replaces it.
+type AstroportPCLDataStore struct {
+	mu   sync.RWMutex
+	data map[uint64]*cosmwasmpool.AstroportPCLData
+}
+
+// NewAstroportPCLDataStore returns a new empty PCL data store.
+func NewAstroportPCLDataStore() *AstroportPCLDataStore {
+	return &AstroportPCLDataStore{
+		data: make(map[uint64]*cosmwasmpool.AstroportPCLData),
+	}
+}
+
+// Get returns the PCL data of the given pool.
+// Returns false if the data of the pool has not been fetched yet.
+func (s *AstroportPCLDataStore) Get(poolID uint64) (*cosmwasmpool.AstroportPCLData, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	data, ok := s.data[poolID]
+	return data, ok
+}
+
+// Set replaces the PCL data of the given pool.
+func (s *AstroportPCLDataStore) Set(poolID uint64, data *cosmwasmpool.AstroportPCLData) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.data[poolID] = data
+}
@@ -0,0 +1,194 @@
+package cosmwasmdomain
+
+import (
+	"context"
+	"sync"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+)
+
+// CalcQueryCb is a callback that queries the pool contract for the amount calculated from the given amount.
+type CalcQueryCb func(ctx context.Context, amount osmomath.Int) (osmomath.Int, error)
+
+// calcQueryKey is the key of a memoised calc query within the entries of a pool.
+type calcQueryKey struct {
+	givenDenom string
+	otherDenom string
+	// exactOut is true for the queries of the token in given the token out.
+	exactOut bool
+	// amountBucket is the string representation of the bucket of the given amount.
+	amountBucket string
+}
+
+// CalcQueryMemo memoises the results of the calc queries to the generalized cosmwasm pools.
+// Such pools make a network request to chain per calculation. The memo allows them to be quoted
+// many times within a block, such as by the split routes, without repeating the requests.
+//
+// The results are keyed by the pool, the denom pair, the swap direction and the bucket of the given amount.
+// The results of a pool are valid until the pool is updated. See InvalidatePools.
+//
+// The number of concurrent queries across all callers is bounded by the budget of the memo.
+// The number of memoised results across all pools is bounded by the max entries of the memo.
+type CalcQueryMemo struct {
+	mu      sync.RWMutex
+	entries map[uint64]map[calcQueryKey]osmomath.Int
+	// numEntries is the number of memoised results across all pools.
+	numEntries int
+	// epoch is incremented on every invalidation so that the results of the queries
+	// started before the invalidation are not memoised.
+	epoch uint64
+
+	// budget bounds the number of concurrent queries.
+	budget chan struct{}
+	// amountBucketDigits is the number of significant digits that the given amounts are rounded to
+	// for bucketing. Zero disables the bucketing.
+	amountBucketDigits int
+	// maxEntries is the max number of memoised results across all pools.
+	// Once reached, the memo is cleared before storing the next result. Zero disables the bound.
+	maxEntries int
+}
+
+// NewCalcQueryMemo returns a new calc query memo with the given budget of concurrent queries,
+// the number of significant digits of the amount buckets and the max number of memoised results.
+// If the number of digits is zero, each amount is its own bucket.
+// If the max number of memoised results is zero, the memo is unbounded.
+// CONTRACT: maxConcurrentQueries is positive.
+func NewCalcQueryMemo(maxConcurrentQueries int, amountBucketDigits int, maxEntries int) *CalcQueryMemo {
+	return &CalcQueryMemo{
+		entries:            make(map[uint64]map[calcQueryKey]osmomath.Int),
+		budget:             make(chan struct{}, maxConcurrentQueries),
+		amountBucketDigits: amountBucketDigits,
+		maxEntries:         maxEntries,
+	}
+}
+
+// CalcOutAmtGivenIn returns the memoised amount of the token out for the given token in amount
+// of the given pool. If not memoised, it is queried with queryCb.
+// See getOrQuery for details.
+func (m *CalcQueryMemo) CalcOutAmtGivenIn(ctx context.Context, poolID uint64, tokenInDenom string, tokenInAmount osmomath.Int, tokenOutDenom string, queryCb CalcQueryCb) (osmomath.Int, error) {
+	return m.getOrQuery(ctx, poolID, tokenInDenom, tokenInAmount, tokenOutDenom, false, queryCb)
+}
+
+// CalcInAmtGivenOut returns the memoised amount of the token in required for the given token out amount
+// of the given pool. If not memoised, it is queried with queryCb.
+// See getOrQuery for details.
+func (m *CalcQueryMemo) CalcInAmtGivenOut(ctx context.Context, poolID uint64, tokenOutDenom string, tokenOutAmount osmomath.Int, tokenInDenom string, queryCb CalcQueryCb) (osmomath.Int, error) {
+	return m.getOrQuery(ctx, poolID, tokenOutDenom, tokenOutAmount, tokenInDenom, true, queryCb)
+}
+
+// InvalidatePools drops the memoised results of the given pools.
+// It must be called whenever the pools are updated.
+func (m *CalcQueryMemo) InvalidatePools(poolIDs map[uint64]struct{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for poolID := range poolIDs {
+		m.numEntries -= len(m.entries[poolID])
+		delete(m.entries, poolID)
+	}
+
+	m.epoch++
+}
+
+// getOrQuery returns the memoised result for the bucket of the given amount.
+// If not memoised, the bucket amount is queried with queryCb within the budget of concurrent queries
+// and the result is memoised. Errors are not memoised. If the memo is full, it is cleared before memoising the result.
+//
+// The result of the bucket is returned as is for all the amounts within it. The given token in amount is rounded down
+// to its bucket and the given token out amount is rounded up to its bucket so that, as long as the pool gives more
+// token out for more token in, the result is never more favorable than the one of the given amount.
+// Scaling the result of the bucket to the given amount instead would be more favorable on concave curves.
+//
+// Returns zero without querying if the given amount is zero.
+// Returns error if the context is cancelled while waiting for the budget or if the query fails.
+func (m *CalcQueryMemo) getOrQuery(ctx context.Context, poolID uint64, givenDenom string, givenAmount osmomath.Int, otherDenom string, exactOut bool, queryCb CalcQueryCb) (osmomath.Int, error) {
+	if givenAmount.IsZero() {
+		return osmomath.ZeroInt(), nil
+	}
+
+	bucketAmount := m.bucketAmount(givenAmount, exactOut)
+
+	key := calcQueryKey{
+		givenDenom:   givenDenom,
+		otherDenom:   otherDenom,
+		exactOut:     exactOut,
+		amountBucket: bucketAmount.String(),
+	}
+
+	m.mu.RLock()
+	result, ok := m.entries[poolID][key]
+	epoch := m.epoch
+	m.mu.RUnlock()
+
+	if !ok {
+		select {
+		case m.budget <- struct{}{}:
+		case <-ctx.Done():
+			return osmomath.Int{}, ctx.Err()
+		}
+
+		var err error
+		result, err = queryCb(ctx, bucketAmount)
+		<-m.budget
+		if err != nil {
+			return osmomath.Int{}, err
+		}
+
+		m.mu.Lock()
+		// The pool may have been updated while querying.
+		if m.epoch == epoch {
+			m.store(poolID, key, result)
+		}
+		m.mu.Unlock()
+	}
+
+	return result, nil
+}
+
+// store memoises the given result, clearing the memo first if it is full.
+// CONTRACT: the caller holds the write lock.
+func (m *CalcQueryMemo) store(poolID uint64, key calcQueryKey, result osmomath.Int) {
+	poolEntries, ok := m.entries[poolID]
+	if ok {
+		// The result may have been memoised by a concurrent query of the same key.
+		if _, ok := poolEntries[key]; ok {
+			poolEntries[key] = result
+			return
+		}
+	}
+
+	if m.maxEntries > 0 && m.numEntries >= m.maxEntries {
+		m.entries = make(map[uint64]map[calcQueryKey]osmomath.Int)
+		m.numEntries = 0
+		poolEntries, ok = nil, false
+	}
+
+	if !ok {
+		poolEntries = make(map[calcQueryKey]osmomath.Int)
+		m.entries[poolID] = poolEntries
+	}
+
+	poolEntries[key] = result
+	m.numEntries++
+}
+
+// bucketAmount rounds the given amount to the significant digits of the memo, up if roundUp is true and down otherwise.
+// Returns the amount itself if the bucketing is disabled or the amount has no more digits than that.
+func (m *CalcQueryMemo) bucketAmount(amount osmomath.Int, roundUp bool) osmomath.Int {
+	if m.amountBucketDigits <= 0 {
+		return amount
+	}
+
+	truncatedDigits := len(amount.String()) - m.amountBucketDigits
+	if truncatedDigits <= 0 {
+		return amount
+	}
+
+	bucketSize := osmomath.NewInt(10).ToLegacyDec().Power(uint64(truncatedDigits)).TruncateInt()
+	bucket := amount.Quo(bucketSize).Mul(bucketSize)
+	if roundUp && !bucket.Equal(amount) {
+		bucket = bucket.Add(bucketSize)
+	}
+
+	return bucket
+}
@@ -0,0 +1,284 @@
+package cosmwasmdomain_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	cosmwasmdomain "github.com/osmosis-labs/sqs/domain/cosmwasm"
+)
+
+const (
+	poolID      = uint64(1)
+	otherPoolID = uint64(2)
+	denomA      = "denomA"
+	denomB      = "denomB"
+)
+
+// newCountingQueryCb returns a query callback that doubles the amount and counts its calls.
+func newCountingQueryCb(calls *atomic.Int32) cosmwasmdomain.CalcQueryCb {
+	return func(ctx context.Context, amount osmomath.Int) (osmomath.Int, error) {
+		calls.Add(1)
+		return amount.MulRaw(2), nil
+	}
+}
+
+func TestCalcQueryMemo_Memoises(t *testing.T) {
+	var calls atomic.Int32
+	queryCb := newCountingQueryCb(&calls)
+
+	memo := cosmwasmdomain.NewCalcQueryMemo(1, 0, 0)
+	ctx := context.Background()
+
+	amountOut, err := memo.CalcOutAmtGivenIn(ctx, poolID, denomA, osmomath.NewInt(100), denomB, queryCb)
+	require.NoError(t, err)
+	require.Equal(t, osmomath.NewInt(200), amountOut)
+	require.Equal(t, int32(1), calls.Load())
+
+	// Same key is memoised.
+	amountOut, err = memo.CalcOutAmtGivenIn(ctx, poolID, denomA, osmomath.NewInt(100), denomB, queryCb)
+	require.NoError(t, err)
+	require.Equal(t, osmomath.NewInt(200), amountOut)
+	require.Equal(t, int32(1), calls.Load())
+
+	// Different amount, direction, denom pair and pool are queried separately.
+	_, err = memo.CalcOutAmtGivenIn(ctx, poolID, denomA, osmomath.NewInt(101), denomB, queryCb)
+	require.NoError(t, err)
+	_, err = memo.CalcInAmtGivenOut(ctx, poolID, denomA, osmomath.NewInt(100), denomB, queryCb)
+	require.NoError(t, err)
+	_, err = memo.CalcOutAmtGivenIn(ctx, poolID, denomB, osmomath.NewInt(100), denomA, queryCb)
+	require.NoError(t, err)
+	_, err = memo.CalcOutAmtGivenIn(ctx, otherPoolID, denomA, osmomath.NewInt(100), denomB, queryCb)
+	require.NoError(t, err)
+	require.Equal(t, int32(5), calls.Load())
+
+	// Zero amount is not queried.
+	amountOut, err = memo.CalcOutAmtGivenIn(ctx, poolID, denomA, osmomath.ZeroInt(), denomB, queryCb)
+	require.NoError(t, err)
+	require.True(t, amountOut.IsZero())
+	require.Equal(t, int32(5), calls.Load())
+}
+
+func TestCalcQueryMemo_InvalidatePools(t *testing.T) {
+	var calls atomic.Int32
+	queryCb := newCountingQueryCb(&calls)
+
+	memo := cosmwasmdomain.NewCalcQueryMemo(1, 0, 0)
+	ctx := context.Background()
+
+	for _, id := range []uint64{poolID, otherPoolID} {
+		_, err := memo.CalcOutAmtGivenIn(ctx, id, denomA, osmomath.NewInt(100), denomB, queryCb)
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(2), calls.Load())
+
+	memo.InvalidatePools(map[uint64]struct{}{poolID: {}})
+
+	// The invalidated pool is queried again.
+	_, err := memo.CalcOutAmtGivenIn(ctx, poolID, denomA, osmomath.NewInt(100), denomB, queryCb)
+	require.NoError(t, err)
+	require.Equal(t, int32(3), calls.Load())
+
+	// The other pool is still memoised.
+	_, err = memo.CalcOutAmtGivenIn(ctx, otherPoolID, denomA, osmomath.NewInt(100), denomB, queryCb)
+	require.NoError(t, err)
+	require.Equal(t, int32(3), calls.Load())
+}
+
+func TestCalcQueryMemo_MaxEntries(t *testing.T) {
+	var calls atomic.Int32
+	queryCb := newCountingQueryCb(&calls)
+
+	const maxEntries = 2
+	memo := cosmwasmdomain.NewCalcQueryMemo(1, 0, maxEntries)
+	ctx := context.Background()
+
+	for _, amount := range []int64{100, 200} {
+		_, err := memo.CalcOutAmtGivenIn(ctx, poolID, denomA, osmomath.NewInt(amount), denomB, queryCb)
+		require.NoError(t, err)
+	}
+	require.Equal(t, int32(2), calls.Load())
+
+	// The full memo is cleared before memoising the result of another pool.
+	_, err := memo.CalcOutAmtGivenIn(ctx, otherPoolID, denomA, osmomath.NewInt(100), denomB, queryCb)
+	require.NoError(t, err)
+	require.Equal(t, int32(3), calls.Load())
+
+	_, err = memo.CalcOutAmtGivenIn(ctx, otherPoolID, denomA, osmomath.NewInt(100), denomB, queryCb)
+	require.NoError(t, err)
+	require.Equal(t, int32(3), calls.Load())
+
+	// The results memoised before clearing are queried again.
+	_, err = memo.CalcOutAmtGivenIn(ctx, poolID, denomA, osmomath.NewInt(100), denomB, queryCb)
+	require.NoError(t, err)
+	require.Equal(t, int32(4), calls.Load())
+
+	// The invalidated entries free up the memo.
+	memo.InvalidatePools(map[uint64]struct{}{poolID: {}})
+
+	_, err = memo.CalcOutAmtGivenIn(ctx, poolID, denomA, osmomath.NewInt(200), denomB, queryCb)
+	require.NoError(t, err)
+	require.Equal(t, int32(5), calls.Load())
+
+	_, err = memo.CalcOutAmtGivenIn(ctx, otherPoolID, denomA, osmomath.NewInt(100), denomB, queryCb)
+	require.NoError(t, err)
+	require.Equal(t, int32(5), calls.Load())
+}
+
+func TestCalcQueryMemo_ErrorsNotMemoised(t *testing.T) {
+	var calls atomic.Int32
+	failingQueryCb := func(ctx context.Context, amount osmomath.Int) (osmomath.Int, error) {
+		calls.Add(1)
+		return osmomath.Int{}, errors.New("query failed")
+	}
+
+	memo := cosmwasmdomain.NewCalcQueryMemo(1, 0, 0)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		_, err := memo.CalcOutAmtGivenIn(ctx, poolID, denomA, osmomath.NewInt(100), denomB, failingQueryCb)
+		require.Error(t, err)
+	}
+	require.Equal(t, int32(2), calls.Load())
+}
+
+func TestCalcQueryMemo_AmountBuckets(t *testing.T) {
+	var calls atomic.Int32
+	queryCb := newCountingQueryCb(&calls)
+
+	memo := cosmwasmdomain.NewCalcQueryMemo(1, 2, 0)
+	ctx := context.Background()
+
+	// Bucket of 1200 is queried and the result is exact.
+	amountOut, err := memo.CalcOutAmtGivenIn(ctx, poolID, denomA, osmomath.NewInt(1200), denomB, queryCb)
+	require.NoError(t, err)
+	require.Equal(t, osmomath.NewInt(2400), amountOut)
+	require.Equal(t, int32(1), calls.Load())
+
+	// 1234 is rounded down to the bucket of 1200 whose result is returned as is.
+	amountOut, err = memo.CalcOutAmtGivenIn(ctx, poolID, denomA, osmomath.NewInt(1234), denomB, queryCb)
+	require.NoError(t, err)
+	require.Equal(t, osmomath.NewInt(2400), amountOut)
+	require.Equal(t, int32(1), calls.Load())
+
+	// The token out amounts are rounded up to their bucket.
+	amountIn, err := memo.CalcInAmtGivenOut(ctx, poolID, denomB, osmomath.NewInt(300), denomA, queryCb)
+	require.NoError(t, err)
+	require.Equal(t, osmomath.NewInt(600), amountIn)
+	require.Equal(t, int32(2), calls.Load())
+
+	amountIn, err = memo.CalcInAmtGivenOut(ctx, poolID, denomB, osmomath.NewInt(291), denomA, queryCb)
+	require.NoError(t, err)
+	require.Equal(t, osmomath.NewInt(600), amountIn)
+	require.Equal(t, int32(2), calls.Load())
+}
+
+// Validates that the bucketed results are never more favorable than the exact ones on the non-linear curves
+// of a constant product pool: the amount out is never above the exact one and the amount in never below.
+func TestCalcQueryMemo_AmountBuckets_ErrorDirection(t *testing.T) {
+	const reserve = 1_000_000
+
+	var (
+		// Concave amount out given the amount in.
+		calcOutCb = func(ctx context.Context, amountIn osmomath.Int) (osmomath.Int, error) {
+			return amountIn.MulRaw(reserve).Quo(amountIn.AddRaw(reserve)), nil
+		}
+		// Convex amount in given the amount out, rounded up.
+		calcInCb = func(ctx context.Context, amountOut osmomath.Int) (osmomath.Int, error) {
+			numerator := amountOut.MulRaw(reserve)
+			denominator := osmomath.NewInt(reserve).Sub(amountOut)
+			return numerator.Add(denominator).SubRaw(1).Quo(denominator), nil
+		}
+	)
+
+	memo := cosmwasmdomain.NewCalcQueryMemo(1, 2, 0)
+	ctx := context.Background()
+
+	for _, amount := range []int64{1_234, 9_999, 12_345, 56_789, 123_456, 456_789, 870_001} {
+		givenAmount := osmomath.NewInt(amount)
+
+		exactAmountOut, err := calcOutCb(ctx, givenAmount)
+		require.NoError(t, err)
+
+		amountOut, err := memo.CalcOutAmtGivenIn(ctx, poolID, denomA, givenAmount, denomB, calcOutCb)
+		require.NoError(t, err)
+		require.True(t, amountOut.LTE(exactAmountOut), "amount in %d: amount out %s above exact %s", amount, amountOut, exactAmountOut)
+
+		exactAmountIn, err := calcInCb(ctx, givenAmount)
+		require.NoError(t, err)
+
+		amountIn, err := memo.CalcInAmtGivenOut(ctx, poolID, denomB, givenAmount, denomA, calcInCb)
+		require.NoError(t, err)
+		require.True(t, amountIn.GTE(exactAmountIn), "amount out %d: amount in %s below exact %s", amount, amountIn, exactAmountIn)
+	}
+}
+
+func TestCalcQueryMemo_Budget(t *testing.T) {
+	const budget = 2
+
+	var (
+		inFlight    atomic.Int32
+		maxInFlight atomic.Int32
+		release     = make(chan struct{})
+	)
+
+	queryCb := func(ctx context.Context, amount osmomath.Int) (osmomath.Int, error) {
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+
+		for {
+			prev := maxInFlight.Load()
+			if current <= prev || maxInFlight.CompareAndSwap(prev, current) {
+				break
+			}
+		}
+
+		<-release
+		return amount, nil
+	}
+
+	memo := cosmwasmdomain.NewCalcQueryMemo(budget, 0, 0)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := int64(1); i <= 5; i++ {
+		wg.Add(1)
+		go func(amount int64) {
+			defer wg.Done()
+			_, err := memo.CalcOutAmtGivenIn(ctx, poolID, denomA, osmomath.NewInt(amount), denomB, queryCb)
+			assert.NoError(t, err)
+		}(i)
+	}
+
+	// Release the queries one at a time.
+	for i := 0; i < 5; i++ {
+		release <- struct{}{}
+	}
+	wg.Wait()
+
+	require.LessOrEqual(t, maxInFlight.Load(), int32(budget))
+
+	// Cancelled context fails while waiting for the budget.
+	blockingMemo := cosmwasmdomain.NewCalcQueryMemo(1, 0, 0)
+	go func() {
+		_, _ = blockingMemo.CalcOutAmtGivenIn(ctx, poolID, denomA, osmomath.NewInt(1), denomB, queryCb)
+	}()
+
+	cancelledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	// Wait until the first query holds the budget.
+	require.Eventually(t, func() bool { return inFlight.Load() == 1 }, time.Second, time.Millisecond)
+
+	_, err := blockingMemo.CalcOutAmtGivenIn(cancelledCtx, poolID, denomA, osmomath.NewInt(2), denomB, queryCb)
+	require.ErrorIs(t, err, context.Canceled)
+
+	release <- struct{}{}
+}
@@ -16,6 +16,12 @@ type CosmWasmPoolsParams struct {
 	Config                domain.CosmWasmPoolRouterConfig
 	WasmClient            wasmtypes.QueryClient
 	ScalingFactorGetterCb domain.ScalingFactorGetterCb
+	// CalcQueryMemo memoises the calc queries of the generalized cosmwasm pools.
+	// Nil if disabled.
+	CalcQueryMemo *CalcQueryMemo
+	// AstroportPCLDataStore holds the PCL data fetched from the contracts of the Astroport PCL pools.
+	// Nil if the native PCL math is disabled.
+	AstroportPCLDataStore *AstroportPCLDataStore
 }
 
 // QueryCosmwasmContract queries the cosmwasm contract given the contract address, request and response
@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"errors"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+)
+
+const (
+	// DefaultCyclicArbMaxPoolsPerCycle is the default maximum number of pools in a cycle.
+	DefaultCyclicArbMaxPoolsPerCycle = 3
+	// DefaultCyclicArbMaxCycles is the default maximum number of candidate cycles to evaluate.
+	DefaultCyclicArbMaxCycles = 20
+)
+
+// ErrCyclicArbMaxPoolsPerCycleNotValid is returned if the max pools per cycle cannot fit a cycle.
+var ErrCyclicArbMaxPoolsPerCycleNotValid = errors.New("max pools per cycle must be at least 2")
+
+// CyclicArbOptions defines the options for the cyclic arbitrage search.
+type CyclicArbOptions struct {
+	// MaxPoolsPerCycle is the maximum number of pools in a cycle. Must be at least 2.
+	MaxPoolsPerCycle int
+	// MaxCycles is the maximum number of candidate cycles to evaluate.
+	MaxCycles int
+	// MinPoolLiquidityCap is the minimum liquidity cap for a pool to be considered.
+	// If zero, the router min pool liquidity cap is used.
+	MinPoolLiquidityCap uint64
+	// MaxAmountIn is the upper bound of the amount in search.
+	// If nil or zero, the balance of the denom in the first pool of the cycle is used.
+	MaxAmountIn osmomath.Int
+}
+
+// Validate returns ErrCyclicArbMaxPoolsPerCycleNotValid if the max pools per cycle is less than 2
+// since a cycle requires at least one pool out of the denom and one pool back.
+func (o CyclicArbOptions) Validate() error {
+	if o.MaxPoolsPerCycle < 2 {
+		return ErrCyclicArbMaxPoolsPerCycleNotValid
+	}
+	return nil
+}
+
+// CyclicArb is a profitable cycle of swaps starting and ending in the same denom.
+type CyclicArb struct {
+	// Denom is the denom that the cycle starts and ends in.
+	Denom string `json:"denom"`
+	// PoolIDs are the IDs of the pools in the cycle, ordered in the swap direction.
+	PoolIDs []uint64 `json:"pool_ids"`
+	// Denoms are the token out denoms of the pools in the cycle.
+	// The last denom is always the cycle denom.
+	Denoms []string `json:"denoms"`
+	// AmountIn is the amount in that maximizes the profit of the cycle.
+	AmountIn osmomath.Int `json:"amount_in"`
+	// AmountOut is the amount out of swapping the amount in over the cycle, net of the taker fees.
+	AmountOut osmomath.Int `json:"amount_out"`
+	// Profit is the amount out minus the amount in.
+	Profit osmomath.Int `json:"profit"`
+
+	// Pools are the routable pools of the cycle that can be used for simulating
+	// or executing the swap.
+	Pools []RoutablePool `json:"-"`
+}
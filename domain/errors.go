@@ -22,6 +22,31 @@ var (
 	ErrQuoteDenomNotValid      = errors.New("quote denom is empty")
 	ErrPoolIDNotValid          = errors.New("pool ID is zero")
 	ErrContractAddressNotValid = errors.New("contract address is empty")
+	ErrQuoteIDNotValid         = errors.New("quote ID is not valid")
+)
+
+var (
+	ErrQuoteStreamDisabled                 = errors.New("quote streaming is disabled")
+	ErrQuoteStreamSubscriptionLimitReached = errors.New("quote stream subscription limit reached")
+	ErrQuoteStreamTooManyQuotes            = errors.New("too many quotes requested for the quote stream subscription")
+)
+
+var (
+	ErrQuoteAuditDisabled = errors.New("quote audit is disabled")
+)
+
+var (
+	ErrNoAmountWithinPriceImpact = errors.New("no amount found within the max price impact")
+)
+
+var (
+	// ErrComputeDeadlineExceeded is the cause of the quote computation context being cancelled
+	// once the compute deadline is exceeded.
+	ErrComputeDeadlineExceeded = errors.New("quote compute deadline exceeded")
+
+	// ErrRouterStateGuardTimeout is the cause of the computation context of an HTTP request being cancelled
+	// once it held the router state guard for longer than the state guard timeout.
+	ErrRouterStateGuardTimeout = errors.New("router state guard timeout exceeded")
 )
 
 // GetStatusCode returbs status code given error
@@ -37,6 +62,18 @@ func GetStatusCode(err error) int {
 		return http.StatusNotFound
 	case ErrConflict:
 		return http.StatusConflict
+	case ErrQuoteStreamDisabled, ErrQuoteAuditDisabled:
+		return http.StatusServiceUnavailable
+	case ErrQuoteStreamSubscriptionLimitReached:
+		return http.StatusTooManyRequests
+	case ErrQuoteStreamTooManyQuotes, ErrCyclicArbMaxPoolsPerCycleNotValid:
+		return http.StatusBadRequest
+	case ErrNoAmountWithinPriceImpact:
+		return http.StatusNotFound
+	case ErrComputeDeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case ErrRouterStateGuardTimeout:
+		return http.StatusServiceUnavailable
 	default:
 		return http.StatusInternalServerError
 	}
@@ -270,6 +307,15 @@ func (e OrderbookNotEnoughLiquidityToCompleteSwapError) Error() string {
 	return fmt.Sprintf("not enough liquidity to complete swap in pool (%d) with amount in (%s)", e.PoolId, e.AmountIn)
 }
 
+type OrderbookNotEnoughLiquidityToCompleteSwapExactOutError struct {
+	PoolId    uint64
+	AmountOut string
+}
+
+func (e OrderbookNotEnoughLiquidityToCompleteSwapExactOutError) Error() string {
+	return fmt.Sprintf("not enough liquidity to complete swap in pool (%d) with amount out (%s)", e.PoolId, e.AmountOut)
+}
+
 type OrderbookTickIndexOutOfBoundError struct {
 	PoolId       uint64
 	TickIndex    int
@@ -329,3 +375,21 @@ type StaticRateLimiterInvalidUpperLimitError struct {
 func (e StaticRateLimiterInvalidUpperLimitError) Error() string {
 	return fmt.Sprintf("invalid upper limit (%s) for weight (%s) and denom (%s)", e.UpperLimit, e.Weight, e.Denom)
 }
+
+type ChangeRateLimiterInvalidUpperLimitError struct {
+	UpperLimit string
+	Weight     string
+	Denom      string
+}
+
+func (e ChangeRateLimiterInvalidUpperLimitError) Error() string {
+	return fmt.Sprintf("weight (%s) of denom (%s) exceeds the change rate limiter upper limit (%s)", e.Weight, e.Denom, e.UpperLimit)
+}
+
+type ExactAmountOutNotSupportedError struct {
+	PoolId uint64
+}
+
+func (e ExactAmountOutNotSupportedError) Error() string {
+	return fmt.Sprintf("exact amount out swap is not supported by pool (%d)", e.PoolId)
+}
@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+)
+
+// GasCostModelConfig defines the per pool type model for estimating the gas consumed by a swap.
+// The estimates are used for ranking and splitting the routes by the amount out net of the gas cost.
+type GasCostModelConfig struct {
+	// Flag to enable the gas-aware route ranking.
+	// If disabled, the gas-aware ranking requests are rejected.
+	Enabled bool `mapstructure:"enabled"`
+
+	// The denom that the gas is paid in.
+	FeeDenom string `mapstructure:"fee-denom"`
+
+	// The gas price in the fee denom as a decimal string.
+	GasPrice string `mapstructure:"gas-price"`
+
+	// The gas consumed by the swap message regardless of the route.
+	BaseGas uint64 `mapstructure:"base-gas"`
+
+	// The gas consumed by a swap over a balancer pool.
+	BalancerGas uint64 `mapstructure:"balancer-gas"`
+
+	// The gas consumed by a swap over a stableswap pool.
+	StableSwapGas uint64 `mapstructure:"stableswap-gas"`
+
+	// The gas consumed by a swap over a concentrated pool without crossing any ticks.
+	ConcentratedGas uint64 `mapstructure:"concentrated-gas"`
+
+	// The gas consumed by every initialized tick crossed in a concentrated pool.
+	ConcentratedTickCrossingGas uint64 `mapstructure:"concentrated-tick-crossing-gas"`
+
+	// The gas consumed by executing a CosmWasm pool contract.
+	// Applies to the transmuter, alloyed transmuter, orderbook and generalized CosmWasm pools.
+	CosmWasmGas uint64 `mapstructure:"cosmwasm-gas"`
+
+	// The gas consumed by every tick walked in an orderbook pool.
+	OrderbookTickGas uint64 `mapstructure:"orderbook-tick-gas"`
+}
+
+// TickCrossingEstimator is implemented by the routable pools whose swap gas
+// grows with the number of ticks crossed.
+type TickCrossingEstimator interface {
+	// EstimateTicksCrossed returns the number of ticks crossed when swapping the given token in.
+	EstimateTicksCrossed(ctx context.Context, tokenIn sdk.Coin) (int, error)
+}
+
+// GasEstimate is the estimated gas cost of executing a quote.
+type GasEstimate struct {
+	// Gas is the estimated gas consumed by the swap.
+	Gas uint64 `json:"gas"`
+	// Fee is the estimated fee paid for the gas.
+	Fee sdk.Coin `json:"fee"`
+	// CostInTokenOut is the estimated fee converted into the token out denom.
+	CostInTokenOut osmomath.Int `json:"cost_in_token_out"`
+	// NetAmountOut is the amount out minus the cost in the token out denom.
+	NetAmountOut osmomath.Int `json:"net_amount_out"`
+}
+
+// Validate validates the gas cost model config.
+// Returns an error if the model is enabled and either the fee denom is empty or the gas price is not
+// a valid non-negative decimal.
+func (c GasCostModelConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.FeeDenom == "" {
+		return fmt.Errorf("gas cost model fee denom must be set")
+	}
+
+	if _, err := c.GetGasPrice(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetGasPrice returns the gas price in the fee denom.
+// Returns an error if the gas price is not a valid non-negative decimal.
+func (c GasCostModelConfig) GetGasPrice() (osmomath.Dec, error) {
+	gasPrice, err := osmomath.NewDecFromStr(c.GasPrice)
+	if err != nil {
+		return osmomath.Dec{}, fmt.Errorf("gas cost model gas price (%s) is not valid: %w", c.GasPrice, err)
+	}
+
+	if gasPrice.IsNegative() {
+		return osmomath.Dec{}, fmt.Errorf("gas cost model gas price (%s) must be non-negative", c.GasPrice)
+	}
+
+	return gasPrice, nil
+}
+
+// GetPoolGas returns the gas consumed by a swap over the pool of the given type
+// crossing the given number of ticks. The ticks are ignored for the pool types
+// whose gas does not depend on them.
+func (c GasCostModelConfig) GetPoolGas(poolType SQSPoolType, ticksCrossed int) uint64 {
+	ticks := uint64(max(ticksCrossed, 0))
+
+	switch poolType {
+	case Balancer:
+		return c.BalancerGas
+	case StableSwap:
+		return c.StableSwapGas
+	case Concentrated:
+		return c.ConcentratedGas + ticks*c.ConcentratedTickCrossingGas
+	case Orderbook:
+		return c.CosmWasmGas + ticks*c.OrderbookTickGas
+	case TransmuterV1, AlloyedTransmuter, AstroportPCL, GeneralizedCosmWasm:
+		return c.CosmWasmGas
+	default:
+		return 0
+	}
+}
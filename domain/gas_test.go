@@ -0,0 +1,86 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/osmosis-labs/sqs/domain"
+)
+
+var defaultGasCostModel = domain.GasCostModelConfig{
+	Enabled:                     true,
+	FeeDenom:                    "uosmo",
+	GasPrice:                    "0.0025",
+	BaseGas:                     100000,
+	BalancerGas:                 50000,
+	StableSwapGas:               70000,
+	ConcentratedGas:             80000,
+	ConcentratedTickCrossingGas: 30000,
+	CosmWasmGas:                 150000,
+	OrderbookTickGas:            25000,
+}
+
+func TestGasCostModelConfigGetPoolGas(t *testing.T) {
+	tests := []struct {
+		name         string
+		poolType     domain.SQSPoolType
+		ticksCrossed int
+		expectedGas  uint64
+	}{
+		{name: "balancer ignores ticks", poolType: domain.Balancer, ticksCrossed: 3, expectedGas: 50000},
+		{name: "stableswap", poolType: domain.StableSwap, expectedGas: 70000},
+		{name: "concentrated without ticks crossed", poolType: domain.Concentrated, expectedGas: 80000},
+		{name: "concentrated with ticks crossed", poolType: domain.Concentrated, ticksCrossed: 3, expectedGas: 80000 + 3*30000},
+		{name: "concentrated with negative ticks crossed", poolType: domain.Concentrated, ticksCrossed: -1, expectedGas: 80000},
+		{name: "orderbook with ticks walked", poolType: domain.Orderbook, ticksCrossed: 2, expectedGas: 150000 + 2*25000},
+		{name: "transmuter", poolType: domain.TransmuterV1, expectedGas: 150000},
+		{name: "alloyed transmuter", poolType: domain.AlloyedTransmuter, expectedGas: 150000},
+		{name: "astroport pcl", poolType: domain.AstroportPCL, expectedGas: 150000},
+		{name: "generalized cosmwasm", poolType: domain.GeneralizedCosmWasm, expectedGas: 150000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.expectedGas, defaultGasCostModel.GetPoolGas(tt.poolType, tt.ticksCrossed))
+		})
+	}
+}
+
+func TestGasCostModelConfigValidate(t *testing.T) {
+	withFeeDenom := func(feeDenom string) domain.GasCostModelConfig {
+		config := defaultGasCostModel
+		config.FeeDenom = feeDenom
+		return config
+	}
+
+	withGasPrice := func(gasPrice string) domain.GasCostModelConfig {
+		config := defaultGasCostModel
+		config.GasPrice = gasPrice
+		return config
+	}
+
+	tests := []struct {
+		name        string
+		config      domain.GasCostModelConfig
+		expectError bool
+	}{
+		{name: "valid", config: defaultGasCostModel},
+		{name: "disabled is not validated", config: domain.GasCostModelConfig{}},
+		{name: "empty fee denom", config: withFeeDenom(""), expectError: true},
+		{name: "invalid gas price", config: withGasPrice("invalid"), expectError: true},
+		{name: "negative gas price", config: withGasPrice("-0.1"), expectError: true},
+		{name: "zero gas price", config: withGasPrice("0")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"errors"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+)
+
+// LiquidityDepth is the curve of the amounts out and the execution prices across a ladder of amounts in
+// for both directions of a token pair. Together, the two sides form a synthetic order book for the pair.
+// The amounts are in chain units and the execution prices are in the quote denom per base denom in chain units.
+type LiquidityDepth struct {
+	BaseDenom  string `json:"base_denom"`
+	QuoteDenom string `json:"quote_denom"`
+	// Bids are the levels of selling the base for the quote. The amounts in are in the base denom.
+	Bids []LiquidityDepthLevel `json:"bids"`
+	// Asks are the levels of buying the base with the quote. The amounts in are in the quote denom.
+	// The amounts in are the amounts in of the bids converted at the execution price of the smallest bid
+	// so that the levels of both sides are of comparable size.
+	Asks []LiquidityDepthLevel `json:"asks"`
+}
+
+// LiquidityDepthLevel is a single level of the liquidity depth curve.
+type LiquidityDepthLevel struct {
+	AmountIn  osmomath.Int `json:"amount_in"`
+	AmountOut osmomath.Int `json:"amount_out"`
+	// ExecutionPrice is the price of the base in the quote denom achieved by the swap.
+	ExecutionPrice osmomath.Dec `json:"execution_price"`
+}
+
+// NewLogSpacedAmounts returns the given number of amounts from min to max, both inclusive, spaced evenly on the log scale.
+// The amounts are rounded to integers. The consecutive amounts that are equal after rounding are deduplicated.
+// Returns error if min is not positive, max is less than min or the number of levels is less than 2.
+func NewLogSpacedAmounts(minAmount, maxAmount osmomath.Int, levels int) ([]osmomath.Int, error) {
+	if minAmount.IsNil() || !minAmount.IsPositive() {
+		return nil, errors.New("min amount must be positive")
+	}
+
+	if maxAmount.IsNil() || maxAmount.LT(minAmount) {
+		return nil, errors.New("max amount must not be less than min amount")
+	}
+
+	if levels < 2 {
+		return nil, errors.New("number of levels must be at least 2")
+	}
+
+	ratio, err := maxAmount.ToLegacyDec().QuoMut(minAmount.ToLegacyDec()).ApproxRoot(uint64(levels - 1))
+	if err != nil {
+		return nil, err
+	}
+
+	amounts := make([]osmomath.Int, 0, levels)
+	amounts = append(amounts, minAmount)
+
+	current := minAmount.ToLegacyDec()
+	for i := 1; i < levels-1; i++ {
+		current = current.MulMut(ratio)
+
+		amount := current.RoundInt()
+		if amount.GT(amounts[len(amounts)-1]) && amount.LT(maxAmount) {
+			amounts = append(amounts, amount)
+		}
+	}
+
+	if maxAmount.GT(amounts[len(amounts)-1]) {
+		amounts = append(amounts, maxAmount)
+	}
+
+	return amounts, nil
+}
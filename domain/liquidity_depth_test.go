@@ -0,0 +1,100 @@
+package domain_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	"github.com/osmosis-labs/sqs/domain"
+)
+
+func TestNewLogSpacedAmounts(t *testing.T) {
+	tests := []struct {
+		name            string
+		minAmount       osmomath.Int
+		maxAmount       osmomath.Int
+		levels          int
+		expectedAmounts []osmomath.Int
+		expectErr       bool
+	}{
+		{
+			name:      "powers of ten",
+			minAmount: osmomath.NewInt(1_000_000),
+			maxAmount: osmomath.NewInt(1_000_000_000),
+			levels:    4,
+			expectedAmounts: []osmomath.Int{
+				osmomath.NewInt(1_000_000),
+				osmomath.NewInt(10_000_000),
+				osmomath.NewInt(100_000_000),
+				osmomath.NewInt(1_000_000_000),
+			},
+		},
+		{
+			name:      "two levels",
+			minAmount: osmomath.NewInt(5),
+			maxAmount: osmomath.NewInt(500),
+			levels:    2,
+			expectedAmounts: []osmomath.Int{
+				osmomath.NewInt(5),
+				osmomath.NewInt(500),
+			},
+		},
+		{
+			name:      "duplicates after rounding are dropped",
+			minAmount: osmomath.NewInt(1),
+			maxAmount: osmomath.NewInt(2),
+			levels:    5,
+			expectedAmounts: []osmomath.Int{
+				osmomath.NewInt(1),
+				osmomath.NewInt(2),
+			},
+		},
+		{
+			name:      "min equals max",
+			minAmount: osmomath.NewInt(7),
+			maxAmount: osmomath.NewInt(7),
+			levels:    3,
+			expectedAmounts: []osmomath.Int{
+				osmomath.NewInt(7),
+			},
+		},
+		{
+			name:      "zero min",
+			minAmount: osmomath.ZeroInt(),
+			maxAmount: osmomath.NewInt(100),
+			levels:    3,
+			expectErr: true,
+		},
+		{
+			name:      "max less than min",
+			minAmount: osmomath.NewInt(100),
+			maxAmount: osmomath.NewInt(10),
+			levels:    3,
+			expectErr: true,
+		},
+		{
+			name:      "single level",
+			minAmount: osmomath.NewInt(1),
+			maxAmount: osmomath.NewInt(100),
+			levels:    1,
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			amounts, err := domain.NewLogSpacedAmounts(tc.minAmount, tc.maxAmount, tc.levels)
+			if tc.expectErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			require.Len(t, amounts, len(tc.expectedAmounts))
+			for i := range amounts {
+				require.Equal(t, tc.expectedAmounts[i].String(), amounts[i].String())
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package mocks
+
+import (
+	"context"
+
+	astroportgrpcclientdomain "github.com/osmosis-labs/sqs/domain/astroport/grpcclient"
+)
+
+var _ astroportgrpcclientdomain.AstroportPCLClient = (*AstroportPCLGRPCClientMock)(nil)
+
+// AstroportPCLGRPCClientMock is a mock struct that implements astroportgrpcclientdomain.AstroportPCLClient.
+type AstroportPCLGRPCClientMock struct {
+	GetPairAssetDenomsCb func(ctx context.Context, contractAddress string) ([]string, error)
+	GetPoolParamsCb      func(ctx context.Context, contractAddress string) (astroportgrpcclientdomain.PoolParams, error)
+	GetAssetPrecisionsCb func(ctx context.Context, contractAddress string, denoms []string) ([]uint32, error)
+}
+
+func (a *AstroportPCLGRPCClientMock) GetPairAssetDenoms(ctx context.Context, contractAddress string) ([]string, error) {
+	if a.GetPairAssetDenomsCb != nil {
+		return a.GetPairAssetDenomsCb(ctx, contractAddress)
+	}
+
+	return nil, nil
+}
+
+func (a *AstroportPCLGRPCClientMock) GetPoolParams(ctx context.Context, contractAddress string) (astroportgrpcclientdomain.PoolParams, error) {
+	if a.GetPoolParamsCb != nil {
+		return a.GetPoolParamsCb(ctx, contractAddress)
+	}
+
+	return astroportgrpcclientdomain.PoolParams{}, nil
+}
+
+func (a *AstroportPCLGRPCClientMock) GetAssetPrecisions(ctx context.Context, contractAddress string, denoms []string) ([]uint32, error) {
+	if a.GetAssetPrecisionsCb != nil {
+		return a.GetAssetPrecisionsCb(ctx, contractAddress, denoms)
+	}
+
+	return nil, nil
+}
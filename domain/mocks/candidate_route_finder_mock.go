@@ -17,3 +17,8 @@ var _ domain.CandidateRouteSearcher = CandidateRouteFinderMock{}
 func (c CandidateRouteFinderMock) FindCandidateRoutes(tokenIn types.Coin, tokenOutDenom string, options domain.CandidateRouteSearchOptions) (sqsdomain.CandidateRoutes, error) {
 	return c.Routes, c.Error
 }
+
+// FindCandidateCycles implements domain.CandidateRouteSearcher.
+func (c CandidateRouteFinderMock) FindCandidateCycles(denom string, options domain.CandidateRouteSearchOptions) (sqsdomain.CandidateRoutes, error) {
+	return c.Routes, c.Error
+}
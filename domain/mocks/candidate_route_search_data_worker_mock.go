@@ -10,6 +10,8 @@ import (
 type CandidateRouteSearchDataWorkerMock struct {
 	ComputeSearchDataSyncFunc  func(ctx context.Context, height uint64, uniqueBlockPoolMetaData domain.BlockPoolMetadata) error
 	ComputeSearchDataAsyncFunc func(ctx context.Context, height uint64, uniqueBlockPoolMetaData domain.BlockPoolMetadata) error
+	PrepareSearchDataFunc      func(uniqueBlockPoolMetaData domain.BlockPoolMetadata, pools domain.CandidateRouteSearchPoolReader) (map[string]domain.CandidateRouteDenomData, error)
+	StoreSearchDataFunc        func(ctx context.Context, height uint64, searchData map[string]domain.CandidateRouteDenomData)
 
 	RegisterListenerFunc func(listener domain.CandidateRouteSearchDataUpdateListener)
 }
@@ -28,6 +30,19 @@ func (m *CandidateRouteSearchDataWorkerMock) ComputeSearchDataAsync(ctx context.
 	return nil
 }
 
+func (m *CandidateRouteSearchDataWorkerMock) PrepareSearchData(uniqueBlockPoolMetaData domain.BlockPoolMetadata, pools domain.CandidateRouteSearchPoolReader) (map[string]domain.CandidateRouteDenomData, error) {
+	if m.PrepareSearchDataFunc != nil {
+		return m.PrepareSearchDataFunc(uniqueBlockPoolMetaData, pools)
+	}
+	return nil, nil
+}
+
+func (m *CandidateRouteSearchDataWorkerMock) StoreSearchData(ctx context.Context, height uint64, searchData map[string]domain.CandidateRouteDenomData) {
+	if m.StoreSearchDataFunc != nil {
+		m.StoreSearchDataFunc(ctx, height, searchData)
+	}
+}
+
 func (m *CandidateRouteSearchDataWorkerMock) RegisterListener(listener domain.CandidateRouteSearchDataUpdateListener) {
 	if m.RegisterListenerFunc != nil {
 		m.RegisterListenerFunc(listener)
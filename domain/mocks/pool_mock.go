@@ -18,6 +18,7 @@ import (
 
 type MockRoutablePool struct {
 	CalculateTokenOutByTokenInFunc func(ctx context.Context, tokenIn sdk.Coin) (sdk.Coin, error)
+	CalculateTokenInByTokenOutFunc func(ctx context.Context, tokenOut sdk.Coin) (sdk.Coin, error)
 
 	ChainPoolModel    poolmanagertypes.PoolI
 	TickModel         *sqsdomain.TickModel
@@ -80,8 +81,8 @@ func (mp *MockRoutablePool) GetSpreadFactor() math.LegacyDec {
 }
 
 // SetTokenOutDenom implements domain.RoutablePool.
-func (*MockRoutablePool) SetTokenOutDenom(tokenOutDenom string) {
-	panic("unimplemented")
+func (mp *MockRoutablePool) SetTokenOutDenom(tokenOutDenom string) {
+	mp.TokenOutDenom = tokenOutDenom
 }
 
 var DefaultSpreadFactor = osmomath.MustNewDecFromStr("0.005")
@@ -130,6 +131,25 @@ func (mp *MockRoutablePool) CalculateTokenOutByTokenIn(_ctx context.Context, tok
 	return balancerPool.CalcOutAmtGivenIn(sdk.Context{}, sdk.NewCoins(tokenIn), mp.TokenOutDenom, mp.SpreadFactor)
 }
 
+// CalculateTokenInByTokenOut implements routerusecase.RoutablePool.
+func (mp *MockRoutablePool) CalculateTokenInByTokenOut(_ctx context.Context, tokenOut sdk.Coin) (sdk.Coin, error) {
+	if mp.CalculateTokenInByTokenOutFunc != nil {
+		return mp.CalculateTokenInByTokenOutFunc(_ctx, tokenOut)
+	}
+
+	if mp.PoolType == poolmanagertypes.CosmWasm {
+		return sdk.NewCoin(mp.TokenInDenom, tokenOut.Amount), nil
+	}
+
+	// Cast to balancer
+	balancerPool, ok := mp.ChainPoolModel.(*balancer.Pool)
+	if !ok {
+		panic("not a balancer pool")
+	}
+
+	return balancerPool.CalcInAmtGivenOut(sdk.Context{}, sdk.NewCoins(tokenOut), mp.TokenInDenom, mp.SpreadFactor)
+}
+
 // String implements domain.RoutablePool.
 func (*MockRoutablePool) String() string {
 	panic("unimplemented")
@@ -172,6 +192,11 @@ func (mp *MockRoutablePool) ChargeTakerFeeExactIn(tokenIn sdk.Coin) (tokenInAfte
 	return tokenIn.Sub(sdk.NewCoin(tokenIn.Denom, mp.TakerFee.Mul(tokenIn.Amount.ToLegacyDec()).TruncateInt()))
 }
 
+// ChargeTakerFeeExactOut implements domain.RoutablePool.
+func (mp *MockRoutablePool) ChargeTakerFeeExactOut(tokenIn sdk.Coin) (tokenInAfterFee sdk.Coin) {
+	return sdk.NewCoin(tokenIn.Denom, tokenIn.Amount.ToLegacyDec().Quo(osmomath.OneDec().Sub(mp.TakerFee)).Ceil().TruncateInt())
+}
+
 // GetTakerFee implements sqsdomain.PoolI.
 func (mp *MockRoutablePool) GetTakerFee() math.LegacyDec {
 	return mp.TakerFee
@@ -246,6 +271,7 @@ func deepCopyPool(mp *MockRoutablePool) *MockRoutablePool {
 
 		// Note these are not deep copied.
 		ChainPoolModel: mp.ChainPoolModel,
+		TokenInDenom:   mp.TokenInDenom,
 		TokenOutDenom:  mp.TokenOutDenom,
 		Balances:       newBalances,
 		TakerFee:       mp.TakerFee.Clone(),
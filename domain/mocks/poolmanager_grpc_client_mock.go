@@ -0,0 +1,36 @@
+package mocks
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	poolmanagertypes "github.com/osmosis-labs/osmosis/v25/x/poolmanager/types"
+
+	poolmanagergrpcclientdomain "github.com/osmosis-labs/sqs/domain/poolmanager/grpcclient"
+)
+
+var _ poolmanagergrpcclientdomain.PoolManagerClient = (*PoolManagerGRPCClientMock)(nil)
+
+// PoolManagerGRPCClientMock is a mock struct that implements poolmanagergrpcclientdomain.PoolManagerClient.
+type PoolManagerGRPCClientMock struct {
+	EstimateSwapExactAmountInCb           func(ctx context.Context, height uint64, tokenIn sdk.Coin, routes []poolmanagertypes.SwapAmountInRoute) (osmomath.Int, error)
+	EstimateSinglePoolSwapExactAmountInCb func(ctx context.Context, height uint64, poolID uint64, tokenIn sdk.Coin, tokenOutDenom string) (osmomath.Int, error)
+}
+
+func (p *PoolManagerGRPCClientMock) EstimateSwapExactAmountIn(ctx context.Context, height uint64, tokenIn sdk.Coin, routes []poolmanagertypes.SwapAmountInRoute) (osmomath.Int, error) {
+	if p.EstimateSwapExactAmountInCb != nil {
+		return p.EstimateSwapExactAmountInCb(ctx, height, tokenIn, routes)
+	}
+
+	return osmomath.ZeroInt(), nil
+}
+
+func (p *PoolManagerGRPCClientMock) EstimateSinglePoolSwapExactAmountIn(ctx context.Context, height uint64, poolID uint64, tokenIn sdk.Coin, tokenOutDenom string) (osmomath.Int, error) {
+	if p.EstimateSinglePoolSwapExactAmountInCb != nil {
+		return p.EstimateSinglePoolSwapExactAmountInCb(ctx, height, poolID, tokenIn, tokenOutDenom)
+	}
+
+	return osmomath.ZeroInt(), nil
+}
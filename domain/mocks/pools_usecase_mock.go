@@ -29,6 +29,7 @@ type PoolsUsecaseMock struct {
 	GetCosmWasmPoolConfigFunc           func() domain.CosmWasmPoolRouterConfig
 	CalcExitCFMMPoolFunc                func(poolID uint64, exitingShares osmomath.Int) (sdk.Coins, error)
 	GetAllCanonicalOrderbookPoolIDsFunc func() ([]domain.CanonicalOrderBooksResult, error)
+	StagePoolsFunc                      func(pools []sqsdomain.PoolI) mvc.StagedPools
 
 	Pools        []sqsdomain.PoolI
 	TickModelMap map[uint64]*sqsdomain.TickModel
@@ -52,6 +53,15 @@ func (pm *PoolsUsecaseMock) GetCanonicalOrderbookPool(baseDenom string, quoteDen
 	panic("unimplemented")
 }
 
+// StagePools implements mvc.PoolsUsecase.
+// Returns the mock itself unless StagePoolsFunc is set.
+func (pm *PoolsUsecaseMock) StagePools(pools []sqsdomain.PoolI) mvc.StagedPools {
+	if pm.StagePoolsFunc != nil {
+		return pm.StagePoolsFunc(pools)
+	}
+	return pm
+}
+
 // StorePools implements mvc.PoolsUsecase.
 func (pm *PoolsUsecaseMock) StorePools(pools []sqsdomain.PoolI) error {
 	if pm.StorePoolsFunc != nil {
@@ -11,6 +11,7 @@ import (
 
 type RouteMock struct {
 	CalculateTokenOutByTokenInFunc      func(ctx context.Context, tokenIn types.Coin) (types.Coin, error)
+	CalculateTokenInByTokenOutFunc      func(ctx context.Context, tokenOut types.Coin) (types.Coin, error)
 	ContainsGeneralizedCosmWasmPoolFunc func() bool
 	GetPoolsFunc                        func() []domain.RoutablePool
 	GetTokenOutDenomFunc                func() string
@@ -28,6 +29,15 @@ func (r *RouteMock) CalculateTokenOutByTokenIn(ctx context.Context, tokenIn type
 	panic("unimplemented")
 }
 
+// CalculateTokenInByTokenOut implements domain.Route.
+func (r *RouteMock) CalculateTokenInByTokenOut(ctx context.Context, tokenOut types.Coin) (types.Coin, error) {
+	if r.CalculateTokenInByTokenOutFunc != nil {
+		return r.CalculateTokenInByTokenOutFunc(ctx, tokenOut)
+	}
+
+	panic("unimplemented")
+}
+
 // ContainsGeneralizedCosmWasmPool implements domain.Route.
 func (r *RouteMock) ContainsGeneralizedCosmWasmPool() bool {
 	if r.ContainsGeneralizedCosmWasmPoolFunc != nil {
@@ -24,6 +24,11 @@ type RouterUsecaseMock struct {
 	GetCustomDirectQuoteFunc                     func(ctx context.Context, tokenIn sdk.Coin, tokenOutDenom string, poolID uint64) (domain.Quote, error)
 	GetCustomDirectQuoteMultiPoolFunc            func(ctx context.Context, tokenIn sdk.Coin, tokenOutDenom []string, poolIDs []uint64) (domain.Quote, error)
 	GetCustomDirectQuoteMultiPoolInGivenOutFunc  func(ctx context.Context, tokenOut sdk.Coin, tokenInDenom []string, poolIDs []uint64) (domain.Quote, error)
+	GetQuoteFromSpecFunc                         func(ctx context.Context, spec domain.QuoteSpec) (domain.Quote, error)
+	GetMaxAmountForPriceImpactFunc               func(ctx context.Context, tokenInDenom, tokenOutDenom string, swapMethod domain.TokenSwapMethod, maxPriceImpact osmomath.Dec, opts ...domain.RouterOption) (domain.Quote, error)
+	GetLiquidityDepthFunc                        func(ctx context.Context, baseDenom, quoteDenom string, baseAmounts []osmomath.Int, opts ...domain.RouterOption) (domain.LiquidityDepth, error)
+	FindCyclicArbsFunc                           func(ctx context.Context, denom string, options domain.CyclicArbOptions) ([]domain.CyclicArb, error)
+	GetOptimalBasketQuoteFunc                    func(ctx context.Context, legs []domain.BasketLeg, opts ...domain.RouterOption) (domain.BasketQuote, error)
 	GetCandidateRoutesFunc                       func(ctx context.Context, tokenIn sdk.Coin, tokenOutDenom string) (sqsdomain.CandidateRoutes, error)
 	GetTakerFeeFunc                              func(poolID uint64) ([]sqsdomain.TakerFeeForPair, error)
 	SetTakerFeesFunc                             func(takerFees sqsdomain.TakerFeeMap)
@@ -34,6 +39,7 @@ type RouterUsecaseMock struct {
 	GetConfigFunc                                func() domain.RouterConfig
 	ConvertMinTokensPoolLiquidityCapToFilterFunc func(minTokensPoolLiquidityCap uint64) uint64
 	SetSortedPoolsFunc                           func(pools []sqsdomain.PoolI)
+	InvalidateRouteCachesFunc                    func(poolIDs map[uint64]struct{}) int
 	GetMinPoolLiquidityCapFilterFunc             func(tokenInDenom string, tokenOutDenom string) (uint64, error)
 }
 
@@ -101,6 +107,41 @@ func (m *RouterUsecaseMock) GetCustomDirectQuoteMultiPoolInGivenOut(ctx context.
 	panic("unimplemented")
 }
 
+func (m *RouterUsecaseMock) GetQuoteFromSpec(ctx context.Context, spec domain.QuoteSpec) (domain.Quote, error) {
+	if m.GetQuoteFromSpecFunc != nil {
+		return m.GetQuoteFromSpecFunc(ctx, spec)
+	}
+	panic("unimplemented")
+}
+
+func (m *RouterUsecaseMock) GetMaxAmountForPriceImpact(ctx context.Context, tokenInDenom, tokenOutDenom string, swapMethod domain.TokenSwapMethod, maxPriceImpact osmomath.Dec, opts ...domain.RouterOption) (domain.Quote, error) {
+	if m.GetMaxAmountForPriceImpactFunc != nil {
+		return m.GetMaxAmountForPriceImpactFunc(ctx, tokenInDenom, tokenOutDenom, swapMethod, maxPriceImpact, opts...)
+	}
+	panic("unimplemented")
+}
+
+func (m *RouterUsecaseMock) GetLiquidityDepth(ctx context.Context, baseDenom, quoteDenom string, baseAmounts []osmomath.Int, opts ...domain.RouterOption) (domain.LiquidityDepth, error) {
+	if m.GetLiquidityDepthFunc != nil {
+		return m.GetLiquidityDepthFunc(ctx, baseDenom, quoteDenom, baseAmounts, opts...)
+	}
+	panic("unimplemented")
+}
+
+func (m *RouterUsecaseMock) FindCyclicArbs(ctx context.Context, denom string, options domain.CyclicArbOptions) ([]domain.CyclicArb, error) {
+	if m.FindCyclicArbsFunc != nil {
+		return m.FindCyclicArbsFunc(ctx, denom, options)
+	}
+	panic("unimplemented")
+}
+
+func (m *RouterUsecaseMock) GetOptimalBasketQuote(ctx context.Context, legs []domain.BasketLeg, opts ...domain.RouterOption) (domain.BasketQuote, error) {
+	if m.GetOptimalBasketQuoteFunc != nil {
+		return m.GetOptimalBasketQuoteFunc(ctx, legs, opts...)
+	}
+	panic("unimplemented")
+}
+
 func (m *RouterUsecaseMock) GetCandidateRoutes(ctx context.Context, tokenIn sdk.Coin, tokenOutDenom string) (sqsdomain.CandidateRoutes, error) {
 	if m.GetCandidateRoutesFunc != nil {
 		return m.GetCandidateRoutesFunc(ctx, tokenIn, tokenOutDenom)
@@ -168,3 +209,10 @@ func (m *RouterUsecaseMock) SetSortedPools(pools []sqsdomain.PoolI) {
 		m.SetSortedPoolsFunc(pools)
 	}
 }
+
+func (m *RouterUsecaseMock) InvalidateRouteCaches(poolIDs map[uint64]struct{}) int {
+	if m.InvalidateRouteCachesFunc != nil {
+		return m.InvalidateRouteCachesFunc(poolIDs)
+	}
+	return 0
+}
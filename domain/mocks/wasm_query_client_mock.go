@@ -0,0 +1,26 @@
+package mocks
+
+import (
+	"context"
+
+	wasmtypes "github.com/CosmWasm/wasmd/x/wasm/types"
+	"google.golang.org/grpc"
+)
+
+var _ wasmtypes.QueryClient = (*WasmQueryClientMock)(nil)
+
+// WasmQueryClientMock is a mock struct that implements wasmtypes.QueryClient.
+// Only the smart contract state query is mocked, calling any other query panics.
+type WasmQueryClientMock struct {
+	wasmtypes.QueryClient
+
+	SmartContractStateFunc func(ctx context.Context, in *wasmtypes.QuerySmartContractStateRequest, opts ...grpc.CallOption) (*wasmtypes.QuerySmartContractStateResponse, error)
+}
+
+func (w *WasmQueryClientMock) SmartContractState(ctx context.Context, in *wasmtypes.QuerySmartContractStateRequest, opts ...grpc.CallOption) (*wasmtypes.QuerySmartContractStateResponse, error) {
+	if w.SmartContractStateFunc != nil {
+		return w.SmartContractStateFunc(ctx, in, opts...)
+	}
+
+	panic("unimplemented")
+}
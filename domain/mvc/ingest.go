@@ -10,11 +10,15 @@ import (
 
 // IngestUsecase represent the ingest's usecases
 type IngestUsecase interface {
-	// ProcessBlockData processes the block data as defined by height, takerFeesMap and poolData
+	// ProcessBlockData processes the block data as defined by height, blockTime, takerFeesMap and poolData
 	// Prior to loading pools into the repository, the pools are transformed and instrumented with pool TVL data.
-	ProcessBlockData(ctx context.Context, height uint64, takerFeesMap sqsdomain.TakerFeeMap, poolData []*types.PoolData) (err error)
+	// blockTime is the time of the block (Unix timestamp in nanoseconds). Zero if not sent by the node.
+	ProcessBlockData(ctx context.Context, height uint64, blockTime int64, takerFeesMap sqsdomain.TakerFeeMap, poolData []*types.PoolData) (err error)
 
 	// RegisterEndBlockProcessPlugin registers the end block process plugin
 	// That is called at the end of the block
 	RegisterEndBlockProcessPlugin(plugin domain.EndBlockProcessPlugin)
+
+	// PoolLiquidityComputeListener re-rates the pools once their liquidity capitalization is repriced.
+	domain.PoolLiquidityComputeListener
 }
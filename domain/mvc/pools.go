@@ -43,6 +43,17 @@ type PoolsUsecase interface {
 	// IsCanonicalOrderbookPool returns true if the given pool ID is a canonical orderbook pool
 	// for some token pair.
 	IsCanonicalOrderbookPool(poolID uint64) bool
+
+	// StagePools returns the view of the stored pools as if the given pools were stored without storing them
+	// so that the state derived from the pools can be built before they are stored with StorePools.
+	StagePools(pools []sqsdomain.PoolI) StagedPools
+}
+
+// StagedPools is the view of the stored pools updated with pools that are not stored yet.
+type StagedPools interface {
+	domain.CandidateRouteSearchPoolReader
+
+	GetAllPools() ([]sqsdomain.PoolI, error)
 }
 
 type PoolHandler interface {
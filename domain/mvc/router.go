@@ -72,6 +72,25 @@ type RouterUsecase interface {
 	// GetCustomDirectQuoteMultiPool calculates direct custom quote for given tokenOut and tokenInDenom over given poolID route.
 	// Underlying implementation uses GetCustomDirectQuote.
 	GetCustomDirectQuoteMultiPoolInGivenOut(ctx context.Context, tokenOut sdk.Coin, tokenInDenom []string, poolIDs []uint64) (domain.Quote, error)
+	// GetQuoteFromSpec re-evaluates the quote over the routes and the split of the given spec against the current state.
+	// It does not search for the routes. Each route is computed as the custom direct quote over its pools.
+	GetQuoteFromSpec(ctx context.Context, spec domain.QuoteSpec) (domain.Quote, error)
+	// GetMaxAmountForPriceImpact returns the optimal quote for the largest amount of the given token whose price impact
+	// does not exceed the given max. The given token is the token in for the exact amount in and the token out for the
+	// exact amount out swap method. The returned quote is already prepared for output.
+	GetMaxAmountForPriceImpact(ctx context.Context, tokenInDenom, tokenOutDenom string, swapMethod domain.TokenSwapMethod, maxPriceImpact osmomath.Dec, opts ...domain.RouterOption) (domain.Quote, error)
+	// GetLiquidityDepth returns the curve of the amounts out and the execution prices for selling each of the given
+	// amounts of the base for the quote and for buying the base with the same amounts converted into the quote denom.
+	// CONTRACT: the base amounts are sorted in increasing order.
+	GetLiquidityDepth(ctx context.Context, baseDenom, quoteDenom string, baseAmounts []osmomath.Int, opts ...domain.RouterOption) (domain.LiquidityDepth, error)
+	// FindCyclicArbs searches the pool graph for the profitable cycles starting and ending in the given denom.
+	// The amount in of each cycle is chosen to maximize the profit.
+	// Returns the cycles sorted by profit in descending order.
+	FindCyclicArbs(ctx context.Context, denom string, options domain.CyclicArbOptions) ([]domain.CyclicArb, error)
+	// GetOptimalBasketQuote returns the combined quote of the given legs of a basket swap quoted in order.
+	// The amount out of each leg accounts for the liquidity consumed by the preceding legs in the shared pools.
+	// The returned quotes of the legs are already prepared for output.
+	GetOptimalBasketQuote(ctx context.Context, legs []domain.BasketLeg, opts ...domain.RouterOption) (domain.BasketQuote, error)
 	// GetCandidateRoutes returns the candidate routes for the given tokenIn and tokenOutDenom.
 	GetCandidateRoutes(ctx context.Context, tokenIn sdk.Coin, tokenOutDenom string) (sqsdomain.CandidateRoutes, error)
 	// GetTakerFee returns the taker fee for all token pairs in a pool.
@@ -111,4 +130,36 @@ type RouterUsecase interface {
 	// CONTRACT: the pools are already sorted according to the desired parameters.
 	// See sortPools() function.
 	SetSortedPools(pools []sqsdomain.PoolI)
+
+	// InvalidateRouteCaches evicts the cached ranked routes going through any of the given pools,
+	// together with the cached candidate routes if their invalidation is enabled.
+	// It is called with the pools updated within a block. Returns the number of evicted entries.
+	// No-op if the route cache invalidation is disabled.
+	InvalidateRouteCaches(poolIDs map[uint64]struct{}) int
+}
+
+// QuoteStreamer streams the quotes to the subscribers after each ingested block.
+type QuoteStreamer interface {
+	domain.EndBlockProcessPlugin
+
+	// Subscribe subscribes to the given quotes. The first quote for each request is computed immediately.
+	// Subsequently, the quote is recomputed and pushed to the returned channel after each block
+	// that updates any of the pools in its route.
+	// The returned unsubscribe function must be called once the subscriber is done. It closes the channel.
+	// Returns error if the subscription limit is reached or if too many quotes are requested.
+	Subscribe(ctx context.Context, requests []domain.QuoteStreamRequest) (<-chan domain.QuoteStreamUpdate, func(), error)
+}
+
+// QuoteAuditor audits the accuracy of the quotes by re-simulating them against the chain
+// at the end of the ingested blocks.
+type QuoteAuditor interface {
+	domain.EndBlockProcessPlugin
+
+	// SampleQuote samples the given prepared exact amount in quote for the next audit
+	// according to the configured sample rate. Never blocks.
+	SampleQuote(quote domain.Quote)
+
+	// GetWorstDivergences returns the pools with the largest absolute divergence from the chain simulation
+	// observed in the latest audit, sorted by the absolute divergence in descending order.
+	GetWorstDivergences() []domain.QuoteAuditDivergence
 }
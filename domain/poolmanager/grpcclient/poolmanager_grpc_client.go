@@ -0,0 +1,78 @@
+package poolmanagergrpcclientdomain
+
+import (
+	"context"
+	"strconv"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	"github.com/osmosis-labs/osmosis/v25/x/poolmanager/client/queryproto"
+	poolmanagertypes "github.com/osmosis-labs/osmosis/v25/x/poolmanager/types"
+)
+
+// PoolManagerClient is an interface for simulating swaps against the chain poolmanager module.
+type PoolManagerClient interface {
+	// EstimateSwapExactAmountIn estimates the amount out of swapping the token in over the given routes.
+	// The taker fee is charged on every hop.
+	// If height is non-zero, the estimate is computed against the state at that height.
+	EstimateSwapExactAmountIn(ctx context.Context, height uint64, tokenIn sdk.Coin, routes []poolmanagertypes.SwapAmountInRoute) (osmomath.Int, error)
+
+	// EstimateSinglePoolSwapExactAmountIn estimates the amount out of swapping the token in over the given pool.
+	// No taker fee is charged.
+	// If height is non-zero, the estimate is computed against the state at that height.
+	EstimateSinglePoolSwapExactAmountIn(ctx context.Context, height uint64, poolID uint64, tokenIn sdk.Coin, tokenOutDenom string) (osmomath.Int, error)
+}
+
+// poolManagerClientImpl is an implementation of PoolManagerClient.
+type poolManagerClientImpl struct {
+	queryClient queryproto.QueryClient
+}
+
+var _ PoolManagerClient = (*poolManagerClientImpl)(nil)
+
+// New creates a new poolManagerClientImpl.
+func New(queryClient queryproto.QueryClient) *poolManagerClientImpl {
+	return &poolManagerClientImpl{
+		queryClient: queryClient,
+	}
+}
+
+// EstimateSwapExactAmountIn implements PoolManagerClient.
+func (p *poolManagerClientImpl) EstimateSwapExactAmountIn(ctx context.Context, height uint64, tokenIn sdk.Coin, routes []poolmanagertypes.SwapAmountInRoute) (osmomath.Int, error) {
+	resp, err := p.queryClient.EstimateSwapExactAmountIn(withHeight(ctx, height), &queryproto.EstimateSwapExactAmountInRequest{
+		TokenIn: tokenIn.String(),
+		Routes:  routes,
+	})
+	if err != nil {
+		return osmomath.Int{}, err
+	}
+
+	return resp.TokenOutAmount, nil
+}
+
+// EstimateSinglePoolSwapExactAmountIn implements PoolManagerClient.
+func (p *poolManagerClientImpl) EstimateSinglePoolSwapExactAmountIn(ctx context.Context, height uint64, poolID uint64, tokenIn sdk.Coin, tokenOutDenom string) (osmomath.Int, error) {
+	resp, err := p.queryClient.EstimateSinglePoolSwapExactAmountIn(withHeight(ctx, height), &queryproto.EstimateSinglePoolSwapExactAmountInRequest{
+		PoolId:        poolID,
+		TokenIn:       tokenIn.String(),
+		TokenOutDenom: tokenOutDenom,
+	})
+	if err != nil {
+		return osmomath.Int{}, err
+	}
+
+	return resp.TokenOutAmount, nil
+}
+
+// withHeight returns the context that queries the state at the given height.
+// Returns the context as is if height is zero, in which case the latest state is queried.
+func withHeight(ctx context.Context, height uint64) context.Context {
+	if height == 0 {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, grpctypes.GRPCBlockHeightHeader, strconv.FormatUint(height, 10))
+}
@@ -0,0 +1,117 @@
+package poolmanagergrpcclientdomain_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	grpctypes "github.com/cosmos/cosmos-sdk/types/grpc"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	"github.com/osmosis-labs/osmosis/v25/x/poolmanager/client/queryproto"
+	poolmanagertypes "github.com/osmosis-labs/osmosis/v25/x/poolmanager/types"
+
+	poolmanagergrpcclientdomain "github.com/osmosis-labs/sqs/domain/poolmanager/grpcclient"
+)
+
+// queryServerStub is the local node stub that records the requests and the requested heights.
+type queryServerStub struct {
+	queryproto.UnimplementedQueryServer
+
+	heights                []string
+	swapRequests           []*queryproto.EstimateSwapExactAmountInRequest
+	singlePoolSwapRequests []*queryproto.EstimateSinglePoolSwapExactAmountInRequest
+	tokenOutAmount         osmomath.Int
+}
+
+func (s *queryServerStub) EstimateSwapExactAmountIn(ctx context.Context, req *queryproto.EstimateSwapExactAmountInRequest) (*queryproto.EstimateSwapExactAmountInResponse, error) {
+	s.recordHeight(ctx)
+	s.swapRequests = append(s.swapRequests, req)
+	return &queryproto.EstimateSwapExactAmountInResponse{TokenOutAmount: s.tokenOutAmount}, nil
+}
+
+func (s *queryServerStub) EstimateSinglePoolSwapExactAmountIn(ctx context.Context, req *queryproto.EstimateSinglePoolSwapExactAmountInRequest) (*queryproto.EstimateSwapExactAmountInResponse, error) {
+	s.recordHeight(ctx)
+	s.singlePoolSwapRequests = append(s.singlePoolSwapRequests, req)
+	return &queryproto.EstimateSwapExactAmountInResponse{TokenOutAmount: s.tokenOutAmount}, nil
+}
+
+// recordHeight records the height header of the request. Empty if unset.
+func (s *queryServerStub) recordHeight(ctx context.Context) {
+	height := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(grpctypes.GRPCBlockHeightHeader); len(values) > 0 {
+			height = values[0]
+		}
+	}
+	s.heights = append(s.heights, height)
+}
+
+// newClientWithStub returns the client connected to the local node stub.
+func newClientWithStub(t *testing.T, stub *queryServerStub) poolmanagergrpcclientdomain.PoolManagerClient {
+	listener := bufconn.Listen(1024 * 1024)
+
+	server := grpc.NewServer()
+	queryproto.RegisterQueryServer(server, stub)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return poolmanagergrpcclientdomain.New(queryproto.NewQueryClient(conn))
+}
+
+// TestPoolManagerClient tests that the estimates are requested from the node
+// at the given height, and at the latest height if the height is zero.
+func TestPoolManagerClient(t *testing.T) {
+	var (
+		ctx = context.Background()
+
+		tokenIn = sdk.NewCoin("uosmo", osmomath.NewInt(1_000_000))
+		routes  = []poolmanagertypes.SwapAmountInRoute{
+			{PoolId: 1, TokenOutDenom: "uion"},
+			{PoolId: 2, TokenOutDenom: "uatom"},
+		}
+	)
+
+	stub := &queryServerStub{tokenOutAmount: osmomath.NewInt(12345)}
+	client := newClientWithStub(t, stub)
+
+	// System under test
+	amountOut, err := client.EstimateSwapExactAmountIn(ctx, 100, tokenIn, routes)
+	require.NoError(t, err)
+	require.Equal(t, "12345", amountOut.String())
+
+	amountOut, err = client.EstimateSinglePoolSwapExactAmountIn(ctx, 0, 1, tokenIn, "uion")
+	require.NoError(t, err)
+	require.Equal(t, "12345", amountOut.String())
+
+	require.Equal(t, []string{"100", ""}, stub.heights)
+
+	require.Len(t, stub.swapRequests, 1)
+	require.Equal(t, tokenIn.String(), stub.swapRequests[0].TokenIn)
+	require.Equal(t, routes, stub.swapRequests[0].Routes)
+
+	require.Len(t, stub.singlePoolSwapRequests, 1)
+	require.Equal(t, uint64(1), stub.singlePoolSwapRequests[0].PoolId)
+	require.Equal(t, tokenIn.String(), stub.singlePoolSwapRequests[0].TokenIn)
+	require.Equal(t, "uion", stub.singlePoolSwapRequests[0].TokenOutDenom)
+}
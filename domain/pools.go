@@ -5,6 +5,8 @@ import (
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/osmosis-labs/osmosis/osmomath"
+
+	"github.com/osmosis-labs/sqs/sqsdomain/cosmwasmpool"
 )
 
 // CosmWasmPoolRouterConfig is the config for the CosmWasm pools in the router
@@ -17,9 +19,57 @@ type CosmWasmPoolRouterConfig struct {
 	OrderbookCodeIDs map[uint64]struct{}
 	// code IDs for the generalized cosmwasm pool type
 	GeneralCosmWasmCodeIDs map[uint64]struct{}
+	// GeneralCosmWasmCalcQueryBudget is the max number of concurrent calc queries to the generalized cosmwasm pools.
+	// If positive, the calc queries are memoised and the generalized cosmwasm pools are included in split routes.
+	GeneralCosmWasmCalcQueryBudget int
 
 	// ChainGRPCGatewayEndpoint is the endpoint for the chain's gRPC gateway
 	ChainGRPCGatewayEndpoint string
+
+	// Implementations are the routable pool implementations of the CosmWasm pools matched by their contract info.
+	// The pools matched by an implementation are supported regardless of their code ID.
+	Implementations []CosmWasmPoolImplementation
+
+	// AstroportPCLEnabled enables the native math of the pools mapped to the astroport-pcl implementation.
+	// If false, these pools are routed as generalized CosmWasm pools.
+	AstroportPCLEnabled bool
+}
+
+// CosmWasmPoolImplementation maps the CosmWasm pools whose contract info matches the matcher
+// to the routable pool implementation registered under the name.
+type CosmWasmPoolImplementation struct {
+	Matcher cosmwasmpool.ContractMatcher
+	Name    string
+}
+
+const (
+	// TransmuterCosmWasmPoolImplementation is the name of the transmuter v1 routable pool implementation.
+	TransmuterCosmWasmPoolImplementation = "transmuter"
+	// AlloyedTransmuterCosmWasmPoolImplementation is the name of the alloyed transmuter routable pool implementation.
+	AlloyedTransmuterCosmWasmPoolImplementation = "alloyed-transmuter"
+	// OrderbookCosmWasmPoolImplementation is the name of the orderbook routable pool implementation.
+	OrderbookCosmWasmPoolImplementation = "orderbook"
+	// AstroportPCLCosmWasmPoolImplementation is the name of the Astroport PCL routable pool implementation.
+	AstroportPCLCosmWasmPoolImplementation = "astroport-pcl"
+	// GeneralizedCosmWasmPoolImplementation is the name of the generalized CosmWasm routable pool implementation
+	// that queries the chain for quotes and spot prices.
+	GeneralizedCosmWasmPoolImplementation = "generalized-cosmwasm"
+)
+
+// GetImplementation returns the name of the first implementation matching the contract info of the given model.
+// Returns false if the model is nil or if no implementation matches it.
+func (c CosmWasmPoolRouterConfig) GetImplementation(model *cosmwasmpool.CosmWasmPoolModel) (string, bool) {
+	if model == nil {
+		return "", false
+	}
+
+	for _, implementation := range c.Implementations {
+		if implementation.Matcher.Matches(model.ContractInfo) {
+			return implementation.Name, true
+		}
+	}
+
+	return "", false
 }
 
 // ScalingFactorGetterCb is a callback that is used to get the scaling factor for a given denom.
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"github.com/osmosis-labs/osmosis/osmomath"
+)
+
+// QuoteAuditConfig defines the config for auditing the accuracy of the quotes against the chain simulation.
+type QuoteAuditConfig struct {
+	// Flag to enable the quote audit.
+	// Requires the GRPC ingester to be enabled since the quotes are audited at the end of the ingested blocks.
+	Enabled bool `mapstructure:"enabled"`
+
+	// The number of blocks between the audits.
+	IntervalBlocks uint64 `mapstructure:"interval-blocks"`
+
+	// The pairs that are quoted and audited on every audit.
+	Pairs []QuoteAuditPairConfig `mapstructure:"pairs"`
+
+	// The fraction of the served exact amount in quotes that are sampled for the next audit, between 0 and 1.
+	ServedQuoteSampleRate float64 `mapstructure:"served-quote-sample-rate"`
+
+	// The maximum number of the served quotes sampled between the audits.
+	// The quotes sampled beyond the limit are dropped.
+	MaxSampledQuotes int `mapstructure:"max-sampled-quotes"`
+
+	// The maximum number of the worst divergences returned by the endpoint.
+	MaxWorstDivergences int `mapstructure:"max-worst-divergences"`
+
+	// The maximum time in milliseconds spent computing the quotes of the pairs while holding the router state guard.
+	// The pairs that are not quoted in time are skipped until the next audit. Unbounded if not positive.
+	PairsComputeTimeoutMs int `mapstructure:"pairs-compute-timeout-ms"`
+
+	// The maximum time in milliseconds that each chain simulation query may take. Unbounded if not positive.
+	ChainQueryTimeoutMs int `mapstructure:"chain-query-timeout-ms"`
+}
+
+// QuoteAuditPairConfig is the exact amount in quote audited on every audit.
+type QuoteAuditPairConfig struct {
+	// String representation of the sdk.Coin denoting the input token.
+	TokenIn       string `mapstructure:"token-in"`
+	TokenOutDenom string `mapstructure:"token-out-denom"`
+}
+
+// QuoteAuditDivergence is the divergence of the amount out computed by SQS
+// from the amount out simulated by the chain for a single pool of a quote.
+type QuoteAuditDivergence struct {
+	// Height is the height of the state that both amounts out are computed against.
+	Height        uint64 `json:"height"`
+	PoolID        uint64 `json:"pool_id"`
+	PoolType      string `json:"pool_type"`
+	TokenIn       string `json:"token_in"`
+	TokenOutDenom string `json:"token_out_denom"`
+	// SQSAmountOut is the amount out computed by SQS.
+	SQSAmountOut osmomath.Int `json:"sqs_amount_out"`
+	// ChainAmountOut is the amount out simulated by the chain.
+	ChainAmountOut osmomath.Int `json:"chain_amount_out"`
+	// Divergence is the SQS amount out relative to the chain amount out, minus one.
+	// Positive if SQS overestimates the amount out.
+	Divergence osmomath.Dec `json:"divergence"`
+}
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"github.com/osmosis-labs/osmosis/osmomath"
+)
+
+// RouteDropReason is the reason for which a candidate route was not considered for the final quote.
+type RouteDropReason string
+
+const (
+	// RouteDropReasonCalculationError is set when the direct quote over the route fails to compute.
+	RouteDropReasonCalculationError RouteDropReason = "calculation_error"
+	// RouteDropReasonDuplicatePool is set when the route goes through a pool of a higher ranked route.
+	RouteDropReasonDuplicatePool RouteDropReason = "duplicate_pool"
+	// RouteDropReasonMaxSplitRoutes is set when the route is ranked below the maximum number of split routes.
+	RouteDropReasonMaxSplitRoutes RouteDropReason = "max_split_routes"
+	// RouteDropReasonGeneralizedCosmWasmPool is set when the route is excluded from splits
+	// for containing a generalized CosmWasm pool.
+	RouteDropReasonGeneralizedCosmWasmPool RouteDropReason = "generalized_cosmwasm_pool"
+)
+
+// QuoteExplain describes how the router arrived at the quote.
+// It is populated by the router when requested with WithQuoteExplain.
+type QuoteExplain struct {
+	// CandidateRoutesCacheHit is true if the candidate routes were read from cache.
+	CandidateRoutesCacheHit bool `json:"candidate_routes_cache_hit"`
+	// RankedRoutesCacheHit is true if the ranked routes were read from cache.
+	// If so, the candidate route search is skipped and the routes are the cached ranked routes.
+	RankedRoutesCacheHit bool `json:"ranked_routes_cache_hit"`
+	// MinPoolLiquidityCap is the minimum liquidity capitalization of the pools applied by the candidate route search.
+	MinPoolLiquidityCap uint64 `json:"min_pool_liquidity_cap"`
+	// LiquidityFilteredPoolIDs are the IDs of the pools skipped by the candidate route search
+	// for having liquidity capitalization below MinPoolLiquidityCap.
+	// Empty if the candidate routes were read from cache.
+	LiquidityFilteredPoolIDs []uint64 `json:"liquidity_filtered_pool_ids"`
+	// Routes are the candidate routes with their direct quotes in the order they were found.
+	Routes []ExplainRoute `json:"routes"`
+	// Split is the best split quote computed over the ranked routes.
+	// Nil if no split quote was computed.
+	Split *ExplainSplit `json:"split,omitempty"`
+}
+
+// ExplainRoute describes the direct quote over a single candidate route.
+type ExplainRoute struct {
+	// PoolIDs are the IDs of the pools in the route ordered from token in to token out.
+	PoolIDs []uint64 `json:"pool_ids"`
+	// AmountIn is the amount of token in of the direct quote.
+	AmountIn osmomath.Int `json:"amount_in"`
+	// AmountOut is the amount of token out of the direct quote.
+	AmountOut osmomath.Int `json:"amount_out"`
+	// DropReason is the reason the route was dropped. Empty if the route was kept.
+	DropReason RouteDropReason `json:"drop_reason,omitempty"`
+	// Error is the error that occurred when computing the direct quote, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// ExplainSplit describes the split quote.
+type ExplainSplit struct {
+	// Selected is true if the split quote was selected over the best single route quote.
+	Selected bool `json:"selected"`
+	// Routes are the routes of the split with their proportions.
+	Routes []ExplainSplitRoute `json:"routes,omitempty"`
+	// Error is the error that occurred when computing the split quote, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// ExplainSplitRoute describes a single route of the split quote.
+type ExplainSplitRoute struct {
+	// PoolIDs are the IDs of the pools in the route ordered from token in to token out.
+	PoolIDs []uint64 `json:"pool_ids"`
+	// AmountIn is the amount of token in swapped over the route.
+	AmountIn osmomath.Int `json:"amount_in"`
+	// AmountOut is the amount of token out received from the route.
+	AmountOut osmomath.Int `json:"amount_out"`
+	// Proportion is the proportion of the given token amount routed through the route.
+	// That is, of the token in for the exact amount in and of the token out for the exact amount out swap method.
+	Proportion osmomath.Dec `json:"proportion"`
+}
@@ -0,0 +1,160 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+)
+
+// QuoteSpec identifies the routes and the split of a quote together with the height
+// of the state it was computed against.
+// It is encoded into the quote ID so that the quote can be re-evaluated against the current state
+// without being stored. The JSON field names are abbreviated to keep the ID short.
+type QuoteSpec struct {
+	// SwapMethod is the swap method of the quote.
+	SwapMethod TokenSwapMethod `json:"m"`
+	// Height is the height of the state the quote was computed against.
+	Height uint64 `json:"h"`
+	// Token is the given token. That is, the token in for the exact amount in
+	// and the token out for the exact amount out swap method.
+	Token sdk.Coin `json:"t"`
+	// QuotedAmount is the computed amount. That is, the amount out for the exact amount in
+	// and the amount in for the exact amount out swap method.
+	QuotedAmount osmomath.Int `json:"q"`
+	// Routes are the routes of the split.
+	Routes []QuoteSpecRoute `json:"r"`
+}
+
+// QuoteSpecRoute identifies a single route of the quote split.
+type QuoteSpecRoute struct {
+	// PoolIDs are the IDs of the pools in the route.
+	// For the exact amount in swap method, the pools are ordered from token in to token out.
+	// For the exact amount out swap method, the pools are ordered from token out to token in.
+	PoolIDs []uint64 `json:"p"`
+	// Denoms are the token out denoms of the pools for the exact amount in
+	// and the token in denoms of the pools for the exact amount out swap method.
+	Denoms []string `json:"d"`
+	// Amount is the amount of the given token routed through the route.
+	Amount osmomath.Int `json:"a"`
+}
+
+// NewQuoteSpec returns the spec of the given quote computed at the given height.
+//
+// CONTRACT: the quote has been prepared with PrepareResult. For the exact amount out quote, this implies that
+// GetAmountIn returns the token out and GetAmountOut returns the token in amount. The routes have the in and out amounts
+// in the direction of the swap and the pools are ordered from token out to token in.
+//
+// Returns error if the swap method is invalid or if the quote has no routes.
+func NewQuoteSpec(quote Quote, swapMethod TokenSwapMethod, height uint64) (QuoteSpec, error) {
+	if swapMethod != TokenSwapMethodExactIn && swapMethod != TokenSwapMethodExactOut {
+		return QuoteSpec{}, fmt.Errorf("invalid swap method %d", swapMethod)
+	}
+
+	routes := quote.GetRoute()
+	if len(routes) == 0 {
+		return QuoteSpec{}, errors.New("quote has no routes")
+	}
+
+	spec := QuoteSpec{
+		SwapMethod:   swapMethod,
+		Height:       height,
+		Token:        quote.GetAmountIn(),
+		QuotedAmount: quote.GetAmountOut(),
+		Routes:       make([]QuoteSpecRoute, 0, len(routes)),
+	}
+
+	for _, route := range routes {
+		pools := route.GetPools()
+
+		specRoute := QuoteSpecRoute{
+			PoolIDs: make([]uint64, 0, len(pools)),
+			Denoms:  make([]string, 0, len(pools)),
+			Amount:  route.GetAmountIn(),
+		}
+
+		if swapMethod == TokenSwapMethodExactOut {
+			specRoute.Amount = route.GetAmountOut()
+		}
+
+		for _, pool := range pools {
+			specRoute.PoolIDs = append(specRoute.PoolIDs, pool.GetId())
+
+			if swapMethod == TokenSwapMethodExactIn {
+				specRoute.Denoms = append(specRoute.Denoms, pool.GetTokenOutDenom())
+			} else {
+				specRoute.Denoms = append(specRoute.Denoms, pool.GetTokenInDenom())
+			}
+		}
+
+		spec.Routes = append(spec.Routes, specRoute)
+	}
+
+	return spec, nil
+}
+
+// ID returns the quote ID encoding the spec.
+// The ID is deterministic. That is, the same spec always results in the same ID.
+func (s QuoteSpec) ID() (string, error) {
+	bz, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(bz), nil
+}
+
+// ParseQuoteID parses the quote spec from the given quote ID.
+// Returns ErrQuoteIDNotValid if the ID fails to decode or if the decoded spec is invalid.
+func ParseQuoteID(quoteID string) (QuoteSpec, error) {
+	bz, err := base64.RawURLEncoding.DecodeString(quoteID)
+	if err != nil {
+		return QuoteSpec{}, ErrQuoteIDNotValid
+	}
+
+	var spec QuoteSpec
+	if err := json.Unmarshal(bz, &spec); err != nil {
+		return QuoteSpec{}, ErrQuoteIDNotValid
+	}
+
+	if err := spec.validate(); err != nil {
+		return QuoteSpec{}, fmt.Errorf("%w: %s", ErrQuoteIDNotValid, err)
+	}
+
+	return spec, nil
+}
+
+// validate validates the decoded spec.
+func (s QuoteSpec) validate() error {
+	if s.SwapMethod != TokenSwapMethodExactIn && s.SwapMethod != TokenSwapMethodExactOut {
+		return fmt.Errorf("invalid swap method %d", s.SwapMethod)
+	}
+
+	if s.Token.Amount.IsNil() || !s.Token.IsValid() || !s.Token.IsPositive() {
+		return fmt.Errorf("invalid token %s", s.Token)
+	}
+
+	if s.QuotedAmount.IsNil() || s.QuotedAmount.IsNegative() {
+		return errors.New("invalid quoted amount")
+	}
+
+	if len(s.Routes) == 0 {
+		return errors.New("no routes")
+	}
+
+	for i, route := range s.Routes {
+		if len(route.PoolIDs) == 0 || len(route.PoolIDs) != len(route.Denoms) {
+			return fmt.Errorf("route %d must have the same non-zero number of pool IDs and denoms", i)
+		}
+
+		if route.Amount.IsNil() || !route.Amount.IsPositive() {
+			return fmt.Errorf("route %d has invalid amount", i)
+		}
+	}
+
+	return nil
+}
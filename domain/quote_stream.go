@@ -0,0 +1,35 @@
+package domain
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// QuoteStreamConfig defines the config for streaming quotes to the subscribers after each block.
+type QuoteStreamConfig struct {
+	// Flag to enable the quote streaming.
+	// Requires the GRPC ingester to be enabled since the quotes are recomputed at the end of each ingested block.
+	Enabled bool `mapstructure:"enabled"`
+
+	// The maximum number of concurrent subscriptions.
+	MaxSubscriptions int `mapstructure:"max-subscriptions"`
+
+	// The maximum number of quotes that a single subscription may stream.
+	MaxQuotesPerSubscription int `mapstructure:"max-quotes-per-subscription"`
+}
+
+// QuoteStreamRequest is the exact amount in quote that is streamed to the subscriber.
+type QuoteStreamRequest struct {
+	TokenIn       sdk.Coin
+	TokenOutDenom string
+}
+
+// QuoteStreamUpdate is the quote pushed to the subscriber.
+// Exactly one of the quote or the error is set.
+type QuoteStreamUpdate struct {
+	// Height is the height of the router state the quote was computed against.
+	Height        uint64   `json:"height"`
+	TokenIn       sdk.Coin `json:"token_in"`
+	TokenOutDenom string   `json:"token_out_denom"`
+	Quote         Quote    `json:"quote,omitempty"`
+	Error         string   `json:"error,omitempty"`
+}
@@ -31,6 +31,8 @@ const (
 	AlloyedTransmuter
 	// Orderbook is an Orderbook pool type.
 	Orderbook
+	// AstroportPCL is an Astroport PCL pool type.
+	AstroportPCL
 )
 
 // RoutablePool is an interface that represents a pool that can be routed over.
@@ -60,8 +62,18 @@ type RoutablePool interface {
 
 	CalculateTokenOutByTokenIn(ctx context.Context, tokenIn sdk.Coin) (sdk.Coin, error)
 
+	// CalculateTokenInByTokenOut calculates the minimal amount of token in
+	// that is required to receive the given token out from the pool.
+	// The token in denom is the one set via SetTokenInDenom.
+	// The taker fee is not accounted for. See ChargeTakerFeeExactOut.
+	CalculateTokenInByTokenOut(ctx context.Context, tokenOut sdk.Coin) (sdk.Coin, error)
+
 	ChargeTakerFeeExactIn(tokenIn sdk.Coin) (tokenInAfterFee sdk.Coin)
 
+	// ChargeTakerFeeExactOut returns the token in amount that must be provided
+	// so that after the taker fee is charged, the given token in amount remains.
+	ChargeTakerFeeExactOut(tokenIn sdk.Coin) (tokenInAfterFee sdk.Coin)
+
 	GetTakerFee() osmomath.Dec
 
 	GetSpreadFactor() osmomath.Dec
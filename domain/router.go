@@ -2,6 +2,8 @@ package domain
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/osmosis-labs/sqs/log"
@@ -14,6 +16,39 @@ import (
 type RoutableResultPool interface {
 	RoutablePool
 	GetBalances() sdk.Coins
+
+	// SetExecuteAsExactIn flags the pool as one that does not support the exact amount out swap API.
+	// The swap through such pool is executed as exact amount in with the computed token in amount.
+	SetExecuteAsExactIn(executeAsExactIn bool)
+	// IsExecutedAsExactIn returns true if the pool is flagged via SetExecuteAsExactIn.
+	IsExecutedAsExactIn() bool
+
+	// SetSwapBreakdown sets the breakdown of the swap through the pool.
+	SetSwapBreakdown(breakdown *PoolSwapBreakdown)
+	// GetSwapBreakdown returns the breakdown of the swap through the pool.
+	// Nil if not set.
+	GetSwapBreakdown() *PoolSwapBreakdown
+}
+
+// PoolSwapBreakdown is the breakdown of the swap through a single pool of the route.
+// The prices have the hop token in as base and the hop token out as quote.
+type PoolSwapBreakdown struct {
+	// TokenIn is the token swapped into the pool, including the taker fee.
+	TokenIn sdk.Coin `json:"token_in"`
+	// TokenOut is the token received from the pool.
+	TokenOut sdk.Coin `json:"token_out"`
+	// SpotPrice is the spot price of the pool before the swap.
+	// Zero if the spot price fails to compute.
+	SpotPrice osmomath.Dec `json:"spot_price"`
+	// EffectivePrice is the execution price of the swap after the taker fee is charged.
+	EffectivePrice osmomath.Dec `json:"effective_price"`
+	// PriceImpact is the effective price relative to the spot price, minus one.
+	// Zero if the spot price is zero.
+	PriceImpact osmomath.Dec `json:"price_impact"`
+	// SpreadFactorCharged is the spread factor charged on the token in after the taker fee.
+	SpreadFactorCharged sdk.Coin `json:"spread_factor_charged"`
+	// TakerFeeCharged is the taker fee charged on the token in.
+	TakerFeeCharged sdk.Coin `json:"taker_fee_charged"`
 }
 
 type Route interface {
@@ -30,6 +65,11 @@ type Route interface {
 	// Returns error if the calculation fails.
 	CalculateTokenOutByTokenIn(ctx context.Context, tokenIn sdk.Coin) (sdk.Coin, error)
 
+	// CalculateTokenInByTokenOut calculates the token in amount required to receive the given token out amount.
+	// Pools are traversed from last to first, charging the taker fee of each pool on its token in.
+	// Returns error if the calculation fails.
+	CalculateTokenInByTokenOut(ctx context.Context, tokenOut sdk.Coin) (sdk.Coin, error)
+
 	// Returns token out denom of the last pool in the route.
 	// If route is empty, returns empty string.
 	GetTokenOutDenom() string
@@ -72,6 +112,14 @@ type Quote interface {
 	// for the tokens. In that case, we invalidate spot price by setting it to zero.
 	PrepareResult(ctx context.Context, scalingFactor osmomath.Dec, logger log.Logger) ([]SplitRoute, osmomath.Dec, error)
 
+	// SetQuoteID sets the quote ID and the height of the state the quote was computed against.
+	// See QuoteSpec for the quote ID details.
+	SetQuoteID(quoteID string, height uint64)
+	// GetQuoteID returns the quote ID. Empty if not set.
+	GetQuoteID() string
+	// GetHeight returns the height of the state the quote was computed against. Zero if not set.
+	GetHeight() uint64
+
 	String() string
 }
 
@@ -94,6 +142,16 @@ type RouterConfig struct {
 	// Maximum number of routes to split across.
 	MaxSplitRoutes int `mapstructure:"max-split-routes"`
 
+	// Number of increments that the amount is divided into when computing split routes.
+	// Higher values lead to finer splits at the cost of latency.
+	// Zero falls back to DefaultSplitIncrements. Capped at MaxSplitIncrements.
+	SplitIncrements int `mapstructure:"split-increments"`
+
+	// Maximum number of refinement rounds performed around the best split.
+	// Each round costs at most O(n^2) route quote estimates where n is the number of split routes.
+	// Zero disables the refinement. Capped at MaxSplitRefinementRounds.
+	SplitRefinementRounds int `mapstructure:"split-refinement-rounds"`
+
 	// Minimum liquidity capitalization for a pool to be considered in the router.
 	// The denomination assumed is pricing.default-quote-human-denom.
 	MinPoolLiquidityCap uint64 `mapstructure:"min-pool-liquidity-cap"`
@@ -107,8 +165,41 @@ type RouterConfig struct {
 	// How long the route is cached for before expiry in seconds.
 	RankedRouteCacheExpirySeconds int `mapstructure:"ranked-route-cache-expiry-seconds"`
 
+	// Whether to evict the cached ranked routes going through the pools updated within a block.
+	// If disabled, the cached routes only expire by TTL.
+	RouteCacheInvalidationEnabled bool `mapstructure:"route-cache-invalidation-enabled"`
+
+	// Whether to also evict the cached candidate routes going through the pools updated within a block.
+	// The candidate routes do not depend on the pool reserves so they are only expired by TTL by default.
+	// Has no effect unless RouteCacheInvalidationEnabled is set.
+	CandidateRouteCacheInvalidationEnabled bool `mapstructure:"candidate-route-cache-invalidation-enabled"`
+
+	// The number of blocks after which all pools are re-sorted.
+	// In between, only the pools updated within a block are repositioned.
+	// If at most one, all pools are re-sorted on every block.
+	PoolFullSortIntervalBlocks uint64 `mapstructure:"pool-full-sort-interval-blocks"`
+
 	// DynamicMinLiquidityCapFiltersAsc is a list of dynamic min liquidity cap filters in descending order.
 	DynamicMinLiquidityCapFiltersDesc []DynamicMinLiquidityCapFilterEntry `mapstructure:"dynamic-min-liquidity-cap-filters-desc"`
+
+	// GasCostModel is the model for estimating the gas consumed by the swaps
+	// when ranking the routes by the amount out net of the gas cost.
+	GasCostModel GasCostModelConfig `mapstructure:"gas-cost-model"`
+
+	// CandidateRouteAlgorithm is the default candidate route search algorithm.
+	// One of CandidateRouteAlgorithms. Empty defaults to CandidateRouteAlgorithmBFS.
+	CandidateRouteAlgorithm CandidateRouteAlgorithm `mapstructure:"candidate-route-algorithm"`
+
+	// QuoteComputeDeadlineMs is the default deadline for computing an exact amount in quote in milliseconds.
+	// Once it is exceeded, the best quote found so far is returned flagged as partial.
+	// Zero disables the deadline.
+	QuoteComputeDeadlineMs int `mapstructure:"quote-compute-deadline-ms"`
+
+	// StateGuardTimeoutMs bounds the time an HTTP request may compute over the router state
+	// while holding the router state guard in milliseconds so that no request stalls the ingest.
+	// Once it is exceeded, the requests fail with ErrRouterStateGuardTimeout rather than returning
+	// the results computed so far. Zero disables the bound.
+	StateGuardTimeoutMs int `mapstructure:"state-guard-timeout-ms"`
 }
 
 type PoolsConfig struct {
@@ -123,12 +214,89 @@ type PoolsConfig struct {
 
 	// Code IDs of generalized CosmWasm pools that are supported.
 	// NOTE: that these pools make network requests to chain for quote estimation.
-	// As a result, they are excluded from split routes.
+	// As a result, they are excluded from split routes unless their calc queries are memoised.
 	GeneralCosmWasmCodeIDs []uint64 `mapstructure:"general-cosmwasm-code-ids"`
+
+	// GeneralCosmWasmCalcQueryBudget is the max number of concurrent calc queries to the generalized CosmWasm pools.
+	// If positive, the calc queries are memoised until the pool is updated and the generalized CosmWasm pools
+	// are included in split routes. Zero disables the memo.
+	GeneralCosmWasmCalcQueryBudget int `mapstructure:"general-cosmwasm-calc-query-budget"`
+
+	// GeneralCosmWasmCalcAmountBucketDigits is the number of significant digits that the amounts of the memoised
+	// calc queries are rounded to, down for the token in and up for the token out. The amounts within the same bucket
+	// share the result of a single query. Zero memoises each amount separately.
+	GeneralCosmWasmCalcAmountBucketDigits int `mapstructure:"general-cosmwasm-calc-amount-bucket-digits"`
+
+	// GeneralCosmWasmCalcMemoMaxEntries is the max number of the memoised calc query results across all pools.
+	// Once reached, the memo is cleared before storing the next result. Zero leaves the memo unbounded.
+	GeneralCosmWasmCalcMemoMaxEntries int `mapstructure:"general-cosmwasm-calc-memo-max-entries"`
+
+	// CosmWasmPoolImplementations map the CosmWasm pool contracts by their cw2 contract info
+	// to the routable pool implementations registered in the pool registry.
+	// The matched pools are supported regardless of the code IDs above. The first matching entry wins.
+	CosmWasmPoolImplementations []CosmWasmPoolImplementationConfig `mapstructure:"cosmwasm-pool-implementations"`
+
+	// AstroportPCLEnabled enables the native math of the pools mapped to the astroport-pcl implementation
+	// and the fetching of their params from the contracts.
+	// Disabled by default until the math is checked against recorded contract simulations,
+	// in which case these pools are routed as generalized CosmWasm pools.
+	AstroportPCLEnabled bool `mapstructure:"astroport-pcl-enabled"`
+}
+
+// CosmWasmPoolImplementationConfig maps a CosmWasm pool contract to a registered routable pool implementation.
+type CosmWasmPoolImplementationConfig struct {
+	// Implementation is the name the routable pool implementation is registered under.
+	Implementation string `mapstructure:"implementation"`
+	// Contract is the cw2 contract name to match.
+	// Empty defaults to the contract name registered with the implementation.
+	Contract string `mapstructure:"contract"`
+	// VersionConstraint is the semver constraint on the cw2 contract version to match.
+	// Empty defaults to the version constraint registered with the implementation.
+	VersionConstraint string `mapstructure:"version-constraint"`
 }
 
 const DisableSplitRoutes = 0
 
+const (
+	// DefaultSplitIncrements is the default number of increments that the amount is divided into
+	// when computing split routes.
+	DefaultSplitIncrements = 10
+	// MaxSplitIncrements is the maximum number of increments that the amount may be divided into
+	// when computing split routes. Bounds the latency of the split computation.
+	MaxSplitIncrements = 100
+	// MaxSplitRefinementRounds is the maximum number of refinement rounds that may be performed
+	// around the best split. Bounds the latency of the split computation.
+	MaxSplitRefinementRounds = 64
+)
+
+// RouterStateGuard guards the router state so that the readers may compute over a consistent
+// view of it across multiple operations. The ingester holds the write lock while
+// updating the router state with the data from a new block.
+type RouterStateGuard struct {
+	sync.RWMutex
+
+	// height is the height of the block the router state was last updated with.
+	height uint64
+}
+
+// NewRouterStateGuard returns a new router state guard.
+func NewRouterStateGuard() *RouterStateGuard {
+	return &RouterStateGuard{}
+}
+
+// SetHeight records the height of the block the router state is updated with.
+// CONTRACT: the write lock is held by the caller for the whole update of the router state.
+func (g *RouterStateGuard) SetHeight(height uint64) {
+	g.height = height
+}
+
+// GetHeight returns the height of the block the router state was last updated with. Zero before the first block.
+// CONTRACT: the read lock is held by the caller for the whole computation over the router state
+// so that the height is the one of the state the computation is performed against.
+func (g *RouterStateGuard) GetHeight() uint64 {
+	return g.height
+}
+
 type RouterState struct {
 	Pools                    []sqsdomain.PoolI
 	TakerFees                sqsdomain.TakerFeeMap
@@ -146,6 +314,10 @@ type RouterOptions struct {
 	MaxPoolsPerRoute int
 	MaxRoutes        int
 	MaxSplitRoutes   int
+	// SplitIncrements is the number of increments that the amount is divided into when computing split routes.
+	SplitIncrements int
+	// SplitRefinementRounds is the maximum number of refinement rounds performed around the best split.
+	SplitRefinementRounds int
 	// MinPoolLiquidityCap is the minimum liquidity capitalization required for a pool to be considered in the route.
 	MinPoolLiquidityCap uint64
 	// The number of milliseconds to cache candidate routes for before expiry.
@@ -159,6 +331,26 @@ type RouterOptions struct {
 	// If at least one of the callbacks in-slice returns true, the ShouldSkipPool function will
 	// also return true.
 	CandidateRoutesPoolFiltersAnyOf []CandidateRoutePoolFiltrerCb
+	// CandidateRoutesPoolTypes restricts the candidate routes to the pools of the given types.
+	// If empty, pools of all types are considered.
+	CandidateRoutesPoolTypes []CandidateRoutePoolType
+	// CandidateRoutesIntermediaryDenomsToSkip are the denoms that the candidate routes must not go through.
+	CandidateRoutesIntermediaryDenomsToSkip map[string]struct{}
+	// DisableDynamicMinPoolLiquidityCap flag controlling whether MinPoolLiquidityCap should be used as is
+	// rather than overwritten by the dynamic min liquidity cap of the token pair.
+	DisableDynamicMinPoolLiquidityCap bool
+	// Explain is populated with the description of how the quote was computed if non-nil.
+	Explain *QuoteExplain
+	// GasPriceInTokenOut is the price of a unit of gas in the token out denom.
+	// If non-nil, the exact amount in routes are ranked and split by the amount out
+	// net of the gas cost estimated with the configured gas cost model.
+	GasPriceInTokenOut *osmomath.Dec
+	// CandidateRouteAlgorithm overrides the configured candidate route search algorithm if non-empty.
+	CandidateRouteAlgorithm CandidateRouteAlgorithm
+	// ComputeDeadline bounds the time spent computing an exact amount in quote.
+	// Once it is exceeded, the best quote found so far is returned flagged as partial.
+	// Zero disables the deadline.
+	ComputeDeadline time.Duration
 }
 
 // DefaultRouterOptions defines the default options for the router
@@ -201,6 +393,27 @@ func WithMaxSplitRoutes(maxSplitRoutes int) RouterOption {
 	}
 }
 
+// WithSplitIncrements configures the router options with the split increments.
+func WithSplitIncrements(splitIncrements int) RouterOption {
+	return func(o *RouterOptions) {
+		o.SplitIncrements = splitIncrements
+	}
+}
+
+// WithSplitRefinementRounds configures the router options with the split refinement rounds.
+func WithSplitRefinementRounds(splitRefinementRounds int) RouterOption {
+	return func(o *RouterOptions) {
+		o.SplitRefinementRounds = splitRefinementRounds
+	}
+}
+
+// WithComputeDeadline configures the router options with the quote compute deadline.
+func WithComputeDeadline(computeDeadline time.Duration) RouterOption {
+	return func(o *RouterOptions) {
+		o.ComputeDeadline = computeDeadline
+	}
+}
+
 // WithDisableCache configures the options to disable cache.
 func WithDisableCache() RouterOption {
 	return func(o *RouterOptions) {
@@ -217,6 +430,60 @@ func WithCandidateRoutesPoolFiltersAnyOf(filters ...CandidateRoutePoolFiltrerCb)
 	}
 }
 
+// WithCandidateRoutesPoolTypes configures the router options to restrict the candidate routes
+// to the pools of the given types.
+func WithCandidateRoutesPoolTypes(poolTypes ...CandidateRoutePoolType) RouterOption {
+	return func(o *RouterOptions) {
+		o.CandidateRoutesPoolTypes = poolTypes
+	}
+}
+
+// WithCandidateRoutesIntermediaryDenomsToSkip configures the router options with the denoms
+// that the candidate routes must not go through.
+func WithCandidateRoutesIntermediaryDenomsToSkip(denoms ...string) RouterOption {
+	return func(o *RouterOptions) {
+		o.CandidateRoutesIntermediaryDenomsToSkip = make(map[string]struct{}, len(denoms))
+		for _, denom := range denoms {
+			o.CandidateRoutesIntermediaryDenomsToSkip[denom] = struct{}{}
+		}
+	}
+}
+
+// WithDisableDynamicMinPoolLiquidityCap configures the router options to use the min pool liquidity
+// capitalization as is rather than overwriting it with the dynamic value for the token pair.
+func WithDisableDynamicMinPoolLiquidityCap() RouterOption {
+	return func(o *RouterOptions) {
+		o.DisableDynamicMinPoolLiquidityCap = true
+	}
+}
+
+// WithQuoteExplain configures the router options to populate the given explain
+// with the description of how the quote was computed.
+func WithQuoteExplain(explain *QuoteExplain) RouterOption {
+	return func(o *RouterOptions) {
+		o.Explain = explain
+	}
+}
+
+// WithGasAwareRanking configures the router options to rank and split the exact amount in routes
+// by the amount out net of the estimated gas cost. The gas price must be denominated in the token out.
+// The ranked route cache is neither read nor written since the cached routes are ranked by the amount out alone.
+// The candidate route cache is still used since the candidate routes do not depend on the ranking.
+func WithGasAwareRanking(gasPriceInTokenOut osmomath.Dec) RouterOption {
+	return func(o *RouterOptions) {
+		o.GasPriceInTokenOut = &gasPriceInTokenOut
+	}
+}
+
+// WithCandidateRouteAlgorithm configures the router options with the candidate route search algorithm.
+// Note that the cached routes are found with the configured algorithm. The callers overriding it
+// must bypass the caches with WithDisableCache.
+func WithCandidateRouteAlgorithm(algorithm CandidateRouteAlgorithm) RouterOption {
+	return func(o *RouterOptions) {
+		o.CandidateRouteAlgorithm = algorithm
+	}
+}
+
 // CandidateRouteSearchDataWorker defines the interface for the candidate route search data worker.
 // It pre-computes data necessary for efficiently computing candidate routes.
 type CandidateRouteSearchDataWorker interface {
@@ -226,10 +493,28 @@ type CandidateRouteSearchDataWorker interface {
 	// ComputeSearchDataAsync computes the candidate route search data asyncronously.
 	ComputeSearchDataAsync(ctx context.Context, height uint64, uniqueBlockPoolMetaData BlockPoolMetadata) error
 
+	// PrepareSearchData computes the candidate route search data from the given pools without storing it
+	// so that it can be computed before the pools are stored. See StoreSearchData.
+	PrepareSearchData(uniqueBlockPoolMetaData BlockPoolMetadata, pools CandidateRouteSearchPoolReader) (map[string]CandidateRouteDenomData, error)
+
+	// StoreSearchData stores the candidate route search data computed by PrepareSearchData
+	// and notifies the listeners of the update.
+	StoreSearchData(ctx context.Context, height uint64, searchData map[string]CandidateRouteDenomData)
+
 	// RegisterListener registers a listener for candidate route data updates.
 	RegisterListener(listener CandidateRouteSearchDataUpdateListener)
 }
 
+// CandidateRouteSearchPoolReader reads the pools that the candidate route search data is computed from.
+type CandidateRouteSearchPoolReader interface {
+	// GetPools returns the pools corresponding to the given IDs.
+	GetPools(opts ...PoolsOption) ([]sqsdomain.PoolI, error)
+
+	// IsCanonicalOrderbookPool returns true if the given pool ID is a canonical orderbook pool
+	// for at least one of the base and quote denoms.
+	IsCanonicalOrderbookPool(poolID uint64) bool
+}
+
 // PricingUpdateListener defines the interface for the candidate route search data listener.
 type CandidateRouteSearchDataUpdateListener interface {
 	// OnSearchDataUpdate notifies the listener of the candidate route data update.
@@ -0,0 +1,256 @@
+package swaptx
+
+import (
+	"errors"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	poolmanagertypes "github.com/osmosis-labs/osmosis/v25/x/poolmanager/types"
+	"github.com/osmosis-labs/sqs/domain"
+)
+
+var (
+	ErrQuoteHasNoRoutes         = errors.New("quote has no routes")
+	ErrSlippageToleranceInvalid = errors.New("slippage tolerance must be in range [0, 1)")
+)
+
+// NewSwapAmountInRoutes converts the pools into the poolmanager swap amount in routes.
+// CONTRACT: the pools are ordered from token in to token out.
+func NewSwapAmountInRoutes(pools []domain.RoutablePool) []poolmanagertypes.SwapAmountInRoute {
+	routes := make([]poolmanagertypes.SwapAmountInRoute, 0, len(pools))
+	for _, pool := range pools {
+		routes = append(routes, poolmanagertypes.SwapAmountInRoute{
+			PoolId:        pool.GetId(),
+			TokenOutDenom: pool.GetTokenOutDenom(),
+		})
+	}
+	return routes
+}
+
+// NewSwapAmountOutRoutes converts the pools into the poolmanager swap amount out routes.
+// CONTRACT: the pools are ordered from token out to token in as in the exact amount out quote.
+// The poolmanager expects the routes from token in to token out. As a result, the order is reversed.
+func NewSwapAmountOutRoutes(pools []domain.RoutablePool) []poolmanagertypes.SwapAmountOutRoute {
+	routes := make([]poolmanagertypes.SwapAmountOutRoute, 0, len(pools))
+	for i := len(pools) - 1; i >= 0; i-- {
+		routes = append(routes, poolmanagertypes.SwapAmountOutRoute{
+			PoolId:       pools[i].GetId(),
+			TokenInDenom: pools[i].GetTokenInDenom(),
+		})
+	}
+	return routes
+}
+
+// NewMsgSwapExactAmountIn returns the exact amount in swap message over the given pools.
+// CONTRACT: the pools are ordered from token in to token out.
+func NewMsgSwapExactAmountIn(sender string, tokenIn sdk.Coin, pools []domain.RoutablePool, tokenOutMinAmount osmomath.Int) *poolmanagertypes.MsgSwapExactAmountIn {
+	return &poolmanagertypes.MsgSwapExactAmountIn{
+		Sender:            sender,
+		Routes:            NewSwapAmountInRoutes(pools),
+		TokenIn:           tokenIn,
+		TokenOutMinAmount: tokenOutMinAmount,
+	}
+}
+
+// MinAmountOut returns the minimum amount out given the slippage tolerance.
+// The result is truncated.
+func MinAmountOut(amountOut osmomath.Int, slippageTolerance osmomath.Dec) osmomath.Int {
+	return amountOut.ToLegacyDec().MulMut(osmomath.OneDec().Sub(slippageTolerance)).TruncateInt()
+}
+
+// MaxAmountIn returns the maximum amount in given the slippage tolerance.
+// The result is rounded up.
+func MaxAmountIn(amountIn osmomath.Int, slippageTolerance osmomath.Dec) osmomath.Int {
+	return amountIn.ToLegacyDec().MulMut(osmomath.OneDec().Add(slippageTolerance)).Ceil().TruncateInt()
+}
+
+// ValidateSlippageTolerance returns error if the slippage tolerance is not in the [0, 1) range.
+func ValidateSlippageTolerance(slippageTolerance osmomath.Dec) error {
+	if slippageTolerance.IsNil() || slippageTolerance.IsNegative() || slippageTolerance.GTE(osmomath.OneDec()) {
+		return ErrSlippageToleranceInvalid
+	}
+	return nil
+}
+
+// NewSwapMsgFromQuote returns the swap message executing the given quote on behalf of the sender.
+// The min amount out or the max amount in are adjusted by the slippage tolerance depending on the swap method.
+//
+// For the exact amount in swap method, MsgSwapExactAmountIn is returned for single route quotes and
+// MsgSplitRouteSwapExactAmountIn for split quotes.
+//
+// For the exact amount out swap method, MsgSwapExactAmountOut is returned for single route quotes and
+// MsgSplitRouteSwapExactAmountOut for split quotes. If any of the pools are flagged to be executed as exact amount in,
+// the exact amount in messages are returned instead. The slippage tolerance is then applied to the token in of every route
+// as the max amount in would be, while the min amount out is the exact token out of the quote.
+//
+// CONTRACT: the quote has been prepared with PrepareResult. For the exact amount out quote, this implies that
+// GetAmountIn returns the token out and GetAmountOut returns the token in amount. The routes have the in and out amounts
+// in the direction of the swap and the pools are ordered from token out to token in.
+//
+// Returns error if the quote has no routes or if the slippage tolerance is invalid.
+func NewSwapMsgFromQuote(sender string, quote domain.Quote, swapMethod domain.TokenSwapMethod, slippageTolerance osmomath.Dec) (sdk.Msg, error) {
+	if err := ValidateSlippageTolerance(slippageTolerance); err != nil {
+		return nil, err
+	}
+
+	routes := quote.GetRoute()
+	if len(routes) == 0 {
+		return nil, ErrQuoteHasNoRoutes
+	}
+
+	switch swapMethod {
+	case domain.TokenSwapMethodExactIn:
+		return newSwapExactAmountInMsgFromQuote(sender, quote.GetAmountIn(), routes, quote.GetAmountOut(), slippageTolerance), nil
+	case domain.TokenSwapMethodExactOut:
+		tokenOut := quote.GetAmountIn()
+
+		// The token in denom is the token in denom of the last pool in the route.
+		pools := routes[0].GetPools()
+		if len(pools) == 0 {
+			return nil, ErrQuoteHasNoRoutes
+		}
+		tokenIn := sdk.NewCoin(pools[len(pools)-1].GetTokenInDenom(), quote.GetAmountOut())
+
+		if isAnyExecutedAsExactIn(routes) {
+			// Reorder the pools from token in to token out and restore the token out denoms
+			// so that the exact amount in message can be built over them.
+			// The token in of every route is raised by the slippage tolerance so that the swap
+			// still yields the exact token out if the price moves against the sender.
+			exactInRoutes := make([]domain.SplitRoute, 0, len(routes))
+			maxTokenIn := sdk.NewCoin(tokenIn.Denom, osmomath.ZeroInt())
+			for _, route := range routes {
+				routeMaxAmountIn := MaxAmountIn(route.GetAmountIn(), slippageTolerance)
+				maxTokenIn.Amount = maxTokenIn.Amount.Add(routeMaxAmountIn)
+
+				exactInRoutes = append(exactInRoutes, exactInSplitRoute{
+					SplitRoute: route,
+					pools:      newExactInPools(route.GetPools(), tokenOut.Denom),
+					amountIn:   routeMaxAmountIn,
+				})
+			}
+
+			// The slippage tolerance is already applied to the token in, the token out must be received in full.
+			return newSwapExactAmountInMsgFromQuote(sender, maxTokenIn, exactInRoutes, tokenOut.Amount, osmomath.ZeroDec()), nil
+		}
+
+		return newSwapExactAmountOutMsgFromQuote(sender, tokenOut, routes, tokenIn.Amount, slippageTolerance), nil
+	default:
+		return nil, fmt.Errorf("unsupported swap method %d", swapMethod)
+	}
+}
+
+// newSwapExactAmountInMsgFromQuote returns the exact amount in message for the given routes.
+func newSwapExactAmountInMsgFromQuote(sender string, tokenIn sdk.Coin, routes []domain.SplitRoute, amountOut osmomath.Int, slippageTolerance osmomath.Dec) sdk.Msg {
+	tokenOutMinAmount := MinAmountOut(amountOut, slippageTolerance)
+
+	if len(routes) == 1 {
+		return NewMsgSwapExactAmountIn(sender, tokenIn, routes[0].GetPools(), tokenOutMinAmount)
+	}
+
+	splitRoutes := make([]poolmanagertypes.SwapAmountInSplitRoute, 0, len(routes))
+	for _, route := range routes {
+		splitRoutes = append(splitRoutes, poolmanagertypes.SwapAmountInSplitRoute{
+			Pools:         NewSwapAmountInRoutes(route.GetPools()),
+			TokenInAmount: route.GetAmountIn(),
+		})
+	}
+
+	return &poolmanagertypes.MsgSplitRouteSwapExactAmountIn{
+		Sender:            sender,
+		Routes:            splitRoutes,
+		TokenInDenom:      tokenIn.Denom,
+		TokenOutMinAmount: tokenOutMinAmount,
+	}
+}
+
+// newSwapExactAmountOutMsgFromQuote returns the exact amount out message for the given routes.
+func newSwapExactAmountOutMsgFromQuote(sender string, tokenOut sdk.Coin, routes []domain.SplitRoute, amountIn osmomath.Int, slippageTolerance osmomath.Dec) sdk.Msg {
+	tokenInMaxAmount := MaxAmountIn(amountIn, slippageTolerance)
+
+	if len(routes) == 1 {
+		return &poolmanagertypes.MsgSwapExactAmountOut{
+			Sender:           sender,
+			Routes:           NewSwapAmountOutRoutes(routes[0].GetPools()),
+			TokenInMaxAmount: tokenInMaxAmount,
+			TokenOut:         tokenOut,
+		}
+	}
+
+	splitRoutes := make([]poolmanagertypes.SwapAmountOutSplitRoute, 0, len(routes))
+	for _, route := range routes {
+		splitRoutes = append(splitRoutes, poolmanagertypes.SwapAmountOutSplitRoute{
+			Pools:          NewSwapAmountOutRoutes(route.GetPools()),
+			TokenOutAmount: route.GetAmountOut(),
+		})
+	}
+
+	return &poolmanagertypes.MsgSplitRouteSwapExactAmountOut{
+		Sender:           sender,
+		Routes:           splitRoutes,
+		TokenOutDenom:    tokenOut.Denom,
+		TokenInMaxAmount: tokenInMaxAmount,
+	}
+}
+
+// isAnyExecutedAsExactIn returns true if any of the pools in the routes
+// does not support the exact amount out swap API.
+func isAnyExecutedAsExactIn(routes []domain.SplitRoute) bool {
+	for _, route := range routes {
+		for _, pool := range route.GetPools() {
+			resultPool, ok := pool.(domain.RoutableResultPool)
+			if ok && resultPool.IsExecutedAsExactIn() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// exactInSplitRoute overrides the pools and the amount in of the split route.
+type exactInSplitRoute struct {
+	domain.SplitRoute
+	pools    []domain.RoutablePool
+	amountIn osmomath.Int
+}
+
+// GetPools implements domain.SplitRoute.
+func (r exactInSplitRoute) GetPools() []domain.RoutablePool {
+	return r.pools
+}
+
+// GetAmountIn implements domain.SplitRoute.
+func (r exactInSplitRoute) GetAmountIn() osmomath.Int {
+	return r.amountIn
+}
+
+// exactInPool overrides the token out denom of the pool.
+type exactInPool struct {
+	domain.RoutablePool
+	tokenOutDenom string
+}
+
+// GetTokenOutDenom implements domain.RoutablePool.
+func (p exactInPool) GetTokenOutDenom() string {
+	return p.tokenOutDenom
+}
+
+// newExactInPools returns the pools of the exact amount out quote route ordered from token in to token out
+// with the token out denoms set. The token out denom of each pool is the token in denom of the next one,
+// and the token out denom of the last pool is the token out denom of the quote.
+func newExactInPools(pools []domain.RoutablePool, tokenOutDenom string) []domain.RoutablePool {
+	exactInPools := make([]domain.RoutablePool, 0, len(pools))
+	for i := len(pools) - 1; i >= 0; i-- {
+		currentTokenOutDenom := tokenOutDenom
+		if i > 0 {
+			currentTokenOutDenom = pools[i-1].GetTokenInDenom()
+		}
+
+		exactInPools = append(exactInPools, exactInPool{
+			RoutablePool:  pools[i],
+			tokenOutDenom: currentTokenOutDenom,
+		})
+	}
+	return exactInPools
+}
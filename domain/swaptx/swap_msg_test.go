@@ -0,0 +1,259 @@
+package swaptx_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	poolmanagertypes "github.com/osmosis-labs/osmosis/v25/x/poolmanager/types"
+	"github.com/osmosis-labs/sqs/domain"
+	"github.com/osmosis-labs/sqs/domain/swaptx"
+	"github.com/osmosis-labs/sqs/router/usecase"
+	"github.com/osmosis-labs/sqs/router/usecase/pools"
+	"github.com/osmosis-labs/sqs/router/usecase/route"
+)
+
+const (
+	sender = "osmo1sender"
+
+	denomA = "denomA"
+	denomB = "denomB"
+	denomC = "denomC"
+)
+
+var (
+	zeroDec           = osmomath.ZeroDec()
+	slippageTolerance = osmomath.NewDecWithPrec(1, 2)
+)
+
+// newExactAmountInRoute returns the route with the given pools and amounts in the exact amount in quote layout.
+func newExactAmountInRoute(inAmount, outAmount int64, pools ...domain.RoutablePool) *usecase.RouteWithOutAmount {
+	return &usecase.RouteWithOutAmount{
+		RouteImpl: route.RouteImpl{Pools: pools},
+		InAmount:  osmomath.NewInt(inAmount),
+		OutAmount: osmomath.NewInt(outAmount),
+	}
+}
+
+func TestNewSwapMsgFromQuote(t *testing.T) {
+	// A -> B -> C through pools 1 and 2.
+	exactInPoolOne := pools.NewRoutableResultPool(1, poolmanagertypes.Balancer, zeroDec, denomB, zeroDec, 0)
+	exactInPoolTwo := pools.NewRoutableResultPool(2, poolmanagertypes.Balancer, zeroDec, denomC, zeroDec, 0)
+	// A -> C through pool 3.
+	exactInPoolThree := pools.NewRoutableResultPool(3, poolmanagertypes.Balancer, zeroDec, denomC, zeroDec, 0)
+
+	// C <- B <- A through pools 2 and 1 as in the exact amount out quote.
+	exactOutPoolTwo := pools.NewExactAmountOutRoutableResultPool(2, poolmanagertypes.Balancer, zeroDec, denomB, zeroDec, 0)
+	exactOutPoolOne := pools.NewExactAmountOutRoutableResultPool(1, poolmanagertypes.Balancer, zeroDec, denomA, zeroDec, 0)
+	// C <- A through pool 3.
+	exactOutPoolThree := pools.NewExactAmountOutRoutableResultPool(3, poolmanagertypes.Balancer, zeroDec, denomA, zeroDec, 0)
+
+	// C <- A through orderbook pool 4 that does not support the exact amount out swap API.
+	exactOutOrderbookPool := pools.NewExactAmountOutRoutableResultPool(4, poolmanagertypes.CosmWasm, zeroDec, denomA, zeroDec, 0)
+	exactOutOrderbookPool.(domain.RoutableResultPool).SetExecuteAsExactIn(true)
+
+	testcases := []struct {
+		name       string
+		quote      domain.Quote
+		swapMethod domain.TokenSwapMethod
+		slippage   osmomath.Dec
+
+		expectedMsg   sdk.Msg
+		expectedError error
+	}{
+		{
+			name: "exact amount in, single route",
+			quote: &usecase.QuoteExactAmountIn{
+				AmountIn:  sdk.NewCoin(denomA, osmomath.NewInt(1000)),
+				AmountOut: osmomath.NewInt(2000),
+				Route:     []domain.SplitRoute{newExactAmountInRoute(1000, 2000, exactInPoolOne, exactInPoolTwo)},
+			},
+			swapMethod: domain.TokenSwapMethodExactIn,
+			slippage:   slippageTolerance,
+
+			expectedMsg: &poolmanagertypes.MsgSwapExactAmountIn{
+				Sender: sender,
+				Routes: []poolmanagertypes.SwapAmountInRoute{
+					{PoolId: 1, TokenOutDenom: denomB},
+					{PoolId: 2, TokenOutDenom: denomC},
+				},
+				TokenIn:           sdk.NewCoin(denomA, osmomath.NewInt(1000)),
+				TokenOutMinAmount: osmomath.NewInt(1980),
+			},
+		},
+		{
+			name: "exact amount in, split route",
+			quote: &usecase.QuoteExactAmountIn{
+				AmountIn:  sdk.NewCoin(denomA, osmomath.NewInt(1000)),
+				AmountOut: osmomath.NewInt(2001),
+				Route: []domain.SplitRoute{
+					newExactAmountInRoute(600, 1200, exactInPoolOne, exactInPoolTwo),
+					newExactAmountInRoute(400, 801, exactInPoolThree),
+				},
+			},
+			swapMethod: domain.TokenSwapMethodExactIn,
+			slippage:   slippageTolerance,
+
+			expectedMsg: &poolmanagertypes.MsgSplitRouteSwapExactAmountIn{
+				Sender: sender,
+				Routes: []poolmanagertypes.SwapAmountInSplitRoute{
+					{
+						Pools: []poolmanagertypes.SwapAmountInRoute{
+							{PoolId: 1, TokenOutDenom: denomB},
+							{PoolId: 2, TokenOutDenom: denomC},
+						},
+						TokenInAmount: osmomath.NewInt(600),
+					},
+					{
+						Pools:         []poolmanagertypes.SwapAmountInRoute{{PoolId: 3, TokenOutDenom: denomC}},
+						TokenInAmount: osmomath.NewInt(400),
+					},
+				},
+				TokenInDenom: denomA,
+				// 2001 * 0.99 = 1980.99 truncated
+				TokenOutMinAmount: osmomath.NewInt(1980),
+			},
+		},
+		{
+			name: "exact amount out, single route",
+			quote: &usecase.QuoteExactAmountIn{
+				AmountIn:  sdk.NewCoin(denomC, osmomath.NewInt(2000)),
+				AmountOut: osmomath.NewInt(1001),
+				Route:     []domain.SplitRoute{newExactAmountInRoute(1001, 2000, exactOutPoolTwo, exactOutPoolOne)},
+			},
+			swapMethod: domain.TokenSwapMethodExactOut,
+			slippage:   slippageTolerance,
+
+			expectedMsg: &poolmanagertypes.MsgSwapExactAmountOut{
+				Sender: sender,
+				Routes: []poolmanagertypes.SwapAmountOutRoute{
+					{PoolId: 1, TokenInDenom: denomA},
+					{PoolId: 2, TokenInDenom: denomB},
+				},
+				// 1001 * 1.01 = 1011.01 rounded up
+				TokenInMaxAmount: osmomath.NewInt(1012),
+				TokenOut:         sdk.NewCoin(denomC, osmomath.NewInt(2000)),
+			},
+		},
+		{
+			name: "exact amount out, split route",
+			quote: &usecase.QuoteExactAmountIn{
+				AmountIn:  sdk.NewCoin(denomC, osmomath.NewInt(2000)),
+				AmountOut: osmomath.NewInt(1000),
+				Route: []domain.SplitRoute{
+					newExactAmountInRoute(600, 1200, exactOutPoolTwo, exactOutPoolOne),
+					newExactAmountInRoute(400, 800, exactOutPoolThree),
+				},
+			},
+			swapMethod: domain.TokenSwapMethodExactOut,
+			slippage:   slippageTolerance,
+
+			expectedMsg: &poolmanagertypes.MsgSplitRouteSwapExactAmountOut{
+				Sender: sender,
+				Routes: []poolmanagertypes.SwapAmountOutSplitRoute{
+					{
+						Pools: []poolmanagertypes.SwapAmountOutRoute{
+							{PoolId: 1, TokenInDenom: denomA},
+							{PoolId: 2, TokenInDenom: denomB},
+						},
+						TokenOutAmount: osmomath.NewInt(1200),
+					},
+					{
+						Pools:          []poolmanagertypes.SwapAmountOutRoute{{PoolId: 3, TokenInDenom: denomA}},
+						TokenOutAmount: osmomath.NewInt(800),
+					},
+				},
+				TokenOutDenom:    denomC,
+				TokenInMaxAmount: osmomath.NewInt(1010),
+			},
+		},
+		{
+			name: "exact amount out, pool executed as exact amount in",
+			quote: &usecase.QuoteExactAmountIn{
+				AmountIn:  sdk.NewCoin(denomC, osmomath.NewInt(2000)),
+				AmountOut: osmomath.NewInt(1000),
+				Route:     []domain.SplitRoute{newExactAmountInRoute(1000, 2000, exactOutOrderbookPool)},
+			},
+			swapMethod: domain.TokenSwapMethodExactOut,
+			slippage:   slippageTolerance,
+
+			expectedMsg: &poolmanagertypes.MsgSwapExactAmountIn{
+				Sender: sender,
+				Routes: []poolmanagertypes.SwapAmountInRoute{{PoolId: 4, TokenOutDenom: denomC}},
+				// The slippage is applied to the token in: 1000 * 1.01 = 1010
+				TokenIn: sdk.NewCoin(denomA, osmomath.NewInt(1010)),
+				// The token out is kept exact.
+				TokenOutMinAmount: osmomath.NewInt(2000),
+			},
+		},
+		{
+			name: "exact amount out, split route with pool executed as exact amount in",
+			quote: &usecase.QuoteExactAmountIn{
+				AmountIn:  sdk.NewCoin(denomC, osmomath.NewInt(2000)),
+				AmountOut: osmomath.NewInt(1001),
+				Route: []domain.SplitRoute{
+					newExactAmountInRoute(601, 1200, exactOutOrderbookPool),
+					newExactAmountInRoute(400, 800, exactOutPoolThree),
+				},
+			},
+			swapMethod: domain.TokenSwapMethodExactOut,
+			slippage:   slippageTolerance,
+
+			expectedMsg: &poolmanagertypes.MsgSplitRouteSwapExactAmountIn{
+				Sender: sender,
+				Routes: []poolmanagertypes.SwapAmountInSplitRoute{
+					{
+						Pools: []poolmanagertypes.SwapAmountInRoute{{PoolId: 4, TokenOutDenom: denomC}},
+						// 601 * 1.01 = 607.01 rounded up
+						TokenInAmount: osmomath.NewInt(608),
+					},
+					{
+						Pools:         []poolmanagertypes.SwapAmountInRoute{{PoolId: 3, TokenOutDenom: denomC}},
+						TokenInAmount: osmomath.NewInt(404),
+					},
+				},
+				TokenInDenom:      denomA,
+				TokenOutMinAmount: osmomath.NewInt(2000),
+			},
+		},
+		{
+			name: "invalid slippage tolerance",
+			quote: &usecase.QuoteExactAmountIn{
+				AmountIn:  sdk.NewCoin(denomA, osmomath.NewInt(1000)),
+				AmountOut: osmomath.NewInt(2000),
+				Route:     []domain.SplitRoute{newExactAmountInRoute(1000, 2000, exactInPoolThree)},
+			},
+			swapMethod: domain.TokenSwapMethodExactIn,
+			slippage:   osmomath.OneDec(),
+
+			expectedError: swaptx.ErrSlippageToleranceInvalid,
+		},
+		{
+			name: "no routes",
+			quote: &usecase.QuoteExactAmountIn{
+				AmountIn:  sdk.NewCoin(denomA, osmomath.NewInt(1000)),
+				AmountOut: osmomath.NewInt(2000),
+			},
+			swapMethod: domain.TokenSwapMethodExactIn,
+			slippage:   slippageTolerance,
+
+			expectedError: swaptx.ErrQuoteHasNoRoutes,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			msg, err := swaptx.NewSwapMsgFromQuote(sender, tc.quote, tc.swapMethod, tc.slippage)
+			if tc.expectedError != nil {
+				require.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedMsg, msg)
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package swaptx
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cosmos/cosmos-sdk/client"
+	"github.com/cosmos/cosmos-sdk/client/tx"
+	"github.com/cosmos/cosmos-sdk/codec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	gogogrpc "github.com/cosmos/gogoproto/grpc"
+)
+
+// DefaultGasAdjustment is the multiplier applied to the simulated gas used.
+const DefaultGasAdjustment = 1.02
+
+// UnsignedTx is the unsigned transaction built from the messages.
+type UnsignedTx struct {
+	// Msgs are the JSON encoded messages of the transaction.
+	Msgs []json.RawMessage `json:"msgs"`
+	// Tx is the JSON encoded unsigned transaction.
+	Tx json.RawMessage `json:"tx"`
+	// TxBytes are the proto encoded unsigned transaction.
+	// Base64 encoded in JSON.
+	TxBytes []byte `json:"tx_bytes"`
+	// GasEstimate is the gas used by the simulated transaction adjusted by the gas adjustment.
+	// It is set as the gas limit of the unsigned transaction.
+	GasEstimate uint64 `json:"gas_estimate"`
+}
+
+// TxBuilder builds unsigned transactions.
+type TxBuilder interface {
+	// BuildUnsignedTx builds the unsigned transaction from the given messages on behalf of the sender.
+	// The gas limit is set to the gas estimated by simulating the transaction against the chain.
+	// The fee and the signatures are left for the sender to set.
+	// Returns error if the sender account does not exist on chain or if the simulation fails.
+	BuildUnsignedTx(ctx context.Context, sender string, msgs ...sdk.Msg) (*UnsignedTx, error)
+}
+
+type txBuilder struct {
+	txConfig client.TxConfig
+	codec    codec.Codec
+	grpcConn gogogrpc.ClientConn
+	chainID  string
+}
+
+var _ TxBuilder = &txBuilder{}
+
+// NewTxBuilder returns a new unsigned transaction builder.
+// The given gRPC connection to the chain is used for account queries and transaction simulation.
+func NewTxBuilder(txConfig client.TxConfig, codec codec.Codec, grpcConn gogogrpc.ClientConn, chainID string) TxBuilder {
+	return &txBuilder{
+		txConfig: txConfig,
+		codec:    codec,
+		grpcConn: grpcConn,
+		chainID:  chainID,
+	}
+}
+
+// BuildUnsignedTx implements TxBuilder.
+func (b *txBuilder) BuildUnsignedTx(ctx context.Context, sender string, msgs ...sdk.Msg) (*UnsignedTx, error) {
+	accountNumber, sequence, err := b.getAccount(ctx, sender)
+	if err != nil {
+		return nil, err
+	}
+
+	txFactory := tx.Factory{}
+	txFactory = txFactory.WithTxConfig(b.txConfig)
+	txFactory = txFactory.WithAccountNumber(accountNumber)
+	txFactory = txFactory.WithSequence(sequence)
+	txFactory = txFactory.WithChainID(b.chainID)
+	txFactory = txFactory.WithGasAdjustment(DefaultGasAdjustment)
+
+	_, gasEstimate, err := CalculateGas(ctx, b.grpcConn, txFactory, msgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	txFactory = txFactory.WithGas(gasEstimate)
+
+	unsignedTx, err := txFactory.BuildUnsignedTx(msgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	txBytes, err := b.txConfig.TxEncoder()(unsignedTx.GetTx())
+	if err != nil {
+		return nil, err
+	}
+
+	txJSON, err := b.txConfig.TxJSONEncoder()(unsignedTx.GetTx())
+	if err != nil {
+		return nil, err
+	}
+
+	msgsJSON := make([]json.RawMessage, 0, len(msgs))
+	for _, msg := range msgs {
+		msgJSON, err := b.codec.MarshalInterfaceJSON(msg)
+		if err != nil {
+			return nil, err
+		}
+		msgsJSON = append(msgsJSON, msgJSON)
+	}
+
+	return &UnsignedTx{
+		Msgs:        msgsJSON,
+		Tx:          txJSON,
+		TxBytes:     txBytes,
+		GasEstimate: gasEstimate,
+	}, nil
+}
+
+// getAccount returns the account number and the sequence of the given address.
+func (b *txBuilder) getAccount(ctx context.Context, address string) (uint64, uint64, error) {
+	res, err := authtypes.NewQueryClient(b.grpcConn).Account(ctx, &authtypes.QueryAccountRequest{Address: address})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var account authtypes.AccountI
+	if err := b.codec.UnpackAny(res.Account, &account); err != nil {
+		return 0, 0, err
+	}
+
+	return account.GetAccountNumber(), account.GetSequence(), nil
+}
+
+// CalculateGas simulates the execution of a transaction and returns the
+// simulation response obtained by the query and the adjusted gas amount.
+func CalculateGas(
+	ctx context.Context,
+	clientCtx gogogrpc.ClientConn, txf tx.Factory, msgs ...sdk.Msg,
+) (*txtypes.SimulateResponse, uint64, error) {
+	txBytes, err := txf.BuildSimTx(msgs...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	txSvcClient := txtypes.NewServiceClient(clientCtx)
+	simRes, err := txSvcClient.Simulate(ctx, &txtypes.SimulateRequest{
+		TxBytes: txBytes,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return simRes, uint64(txf.GasAdjustment() * float64(simRes.GasInfo.GasUsed)), nil
+}
@@ -20,6 +20,12 @@ var (
 	// * pool_id - the indentifier of the pool being processed
 	SQSIngestUsecaseProcessOrderbookPoolErrorMetricName = "sqs_ingest_usecase_process_orderbook_pool_error_total"
 
+	// sqs_pools_usecase_refresh_astroport_pcl_pool_error_total
+	//
+	// counter that measures the number of errors that occur when refreshing the params of an Astroport PCL pool in pools usecase
+	// The pools that fail keep the params of their previous refresh, if any, and are routed as generalized CosmWasm pools otherwise.
+	SQSPoolsUsecaseRefreshAstroportPCLPoolErrorMetricName = "sqs_pools_usecase_refresh_astroport_pcl_pool_error_total"
+
 	// sqs_ingest_usecase_process_block_error
 	//
 	// counter that measures the number of errors that occur during processing a block in ingest usecase
@@ -113,6 +119,13 @@ var (
 	// * cache_type - the type of cache being used
 	SQSRoutesCacheWritesCounterMetricName = "sqs_routes_cache_write_total"
 
+	// sqs_routes_cache_evictions_total
+	//
+	// counter that measures the number of route cache entries evicted for routing through the pools updated within a block
+	// Has the following labels:
+	// * cache_type - the type of cache being used
+	SQSRoutesCacheEvictionsCounterMetricName = "sqs_routes_cache_evictions_total"
+
 	// sqs_pricing_cache_hits_total
 	//
 	// counter that measures the number of pricing cache hits
@@ -143,6 +156,74 @@ var (
 	// counter that measures the number of pricing coingecko cache misses
 	SQSPricingCoingeckoCacheMissesCounterMetricName = "sqs_pricing_coingecko_cache_misses_total"
 
+	// sqs_quote_stream_subscriptions
+	//
+	// gauge that measures the number of active quote stream subscriptions
+	SQSQuoteStreamSubscriptionsMetricName = "sqs_quote_stream_subscriptions"
+
+	// sqs_quote_stream_updates_total
+	//
+	// counter that measures the number of quote updates pushed to the quote stream subscribers
+	SQSQuoteStreamUpdatesCounterMetricName = "sqs_quote_stream_updates_total"
+
+	// sqs_quote_stream_dropped_updates_total
+	//
+	// counter that measures the number of quote updates dropped due to the slow quote stream subscribers
+	SQSQuoteStreamDroppedUpdatesCounterMetricName = "sqs_quote_stream_dropped_updates_total"
+
+	// sqs_quote_stream_coalesced_blocks_total
+	//
+	// counter that measures the number of blocks whose quote stream updates were coalesced into the next processed block
+	// since the previous block was still being processed
+	SQSQuoteStreamCoalescedBlocksCounterMetricName = "sqs_quote_stream_coalesced_blocks_total"
+
+	// sqs_quote_audit_pool_divergence
+	//
+	// histogram that measures the absolute relative divergence of the amount out computed by SQS
+	// from the amount out simulated by the chain for the pools of the audited quotes
+	//
+	// Has the following labels:
+	// * pool_type - the type of the audited pool
+	SQSQuoteAuditPoolDivergenceMetricName = "sqs_quote_audit_pool_divergence"
+
+	// sqs_quote_audit_route_divergence
+	//
+	// histogram that measures the absolute relative divergence of the amount out computed by SQS
+	// from the amount out simulated by the chain for the routes of the audited quotes
+	SQSQuoteAuditRouteDivergenceMetricName = "sqs_quote_audit_route_divergence"
+
+	// sqs_quote_audit_errors_total
+	//
+	// counter that measures the number of errors that occur during the quote audit
+	//
+	// Has the following labels:
+	// * stage - the stage of the audit that failed, one of quote, route or pool
+	SQSQuoteAuditErrorsCounterMetricName = "sqs_quote_audit_errors_total"
+
+	// sqs_quote_audit_dropped_samples_total
+	//
+	// counter that measures the number of sampled served quotes dropped due to the sample limit
+	SQSQuoteAuditDroppedSamplesCounterMetricName = "sqs_quote_audit_dropped_samples_total"
+
+	// sqs_quote_compute_deadline_exceeded_total
+	//
+	// counter that measures the number of quote computations that exceeded the compute deadline
+	//
+	// Has the following labels:
+	// * stage - the stage of the quote computation cut by the deadline, one of ranking, split or refinement
+	// * outcome - partial if the best quote found so far was returned, error if none was found
+	SQSQuoteComputeDeadlineExceededCounterMetricName = "sqs_quote_compute_deadline_exceeded_total"
+
+	// sqs_router_ranked_routes_coalescing_total
+	//
+	// counter that measures the number of ranked route computations coalesced by their cache key,
+	// the dedup rate being the share of the shared results
+	//
+	// Has the following labels:
+	// * result - computed if computed by the caller, shared if the result of a concurrent identical computation was reused,
+	// fallback if the concurrent identical computation failed and the caller computed it again
+	SQSRouterRankedRoutesCoalescingCounterMetricName = "sqs_router_ranked_routes_coalescing_total"
+
 	SQSIngestHandlerProcessBlockDurationGauge = prometheus.NewGauge(
 		prometheus.GaugeOpts{
 			Name: SQSIngestUsecaseProcessBlockDurationMetricName,
@@ -164,6 +245,13 @@ var (
 		},
 	)
 
+	SQSPoolsUsecaseRefreshAstroportPCLPoolErrorCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: SQSPoolsUsecaseRefreshAstroportPCLPoolErrorMetricName,
+			Help: "counter that measures the number of errors that occur when refreshing the params of an Astroport PCL pool in pools usecase",
+		},
+	)
+
 	SQSIngestHandlerPoolParseErrorCounter = prometheus.NewCounter(
 		prometheus.CounterOpts{
 			Name: SQSIngestUsecaseParsePoolErrorMetricName,
@@ -250,6 +338,14 @@ var (
 		[]string{"route", "cache_type"},
 	)
 
+	SQSRoutesCacheEvictionsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: SQSRoutesCacheEvictionsCounterMetricName,
+			Help: "Total number of cache evictions for routing through updated pools",
+		},
+		[]string{"cache_type"},
+	)
+
 	SQSPricingCacheHitsCounter = prometheus.NewCounter(
 		prometheus.CounterOpts{
 			Name: SQSPricingCacheHitsCounterMetricName,
@@ -290,12 +386,92 @@ var (
 			Help: "Total number of pricing coingecko cache misses",
 		},
 	)
+
+	SQSQuoteStreamSubscriptionsGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: SQSQuoteStreamSubscriptionsMetricName,
+			Help: "gauge that measures the number of active quote stream subscriptions",
+		},
+	)
+
+	SQSQuoteStreamUpdatesCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: SQSQuoteStreamUpdatesCounterMetricName,
+			Help: "Total number of quote updates pushed to the quote stream subscribers",
+		},
+	)
+
+	SQSQuoteStreamDroppedUpdatesCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: SQSQuoteStreamDroppedUpdatesCounterMetricName,
+			Help: "Total number of quote updates dropped due to the slow quote stream subscribers",
+		},
+	)
+
+	SQSQuoteStreamCoalescedBlocksCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: SQSQuoteStreamCoalescedBlocksCounterMetricName,
+			Help: "Total number of blocks whose quote stream updates were coalesced into the next processed block",
+		},
+	)
+
+	// quoteAuditDivergenceBuckets are the buckets of the absolute relative divergence, from 0.01 bps to 100%.
+	quoteAuditDivergenceBuckets = prometheus.ExponentialBuckets(0.000001, 10, 7)
+
+	SQSQuoteAuditPoolDivergenceHistogram = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    SQSQuoteAuditPoolDivergenceMetricName,
+			Help:    "histogram that measures the absolute relative divergence of the SQS amount out from the chain simulation per pool type",
+			Buckets: quoteAuditDivergenceBuckets,
+		},
+		[]string{"pool_type"},
+	)
+
+	SQSQuoteAuditRouteDivergenceHistogram = prometheus.NewHistogram(
+		prometheus.HistogramOpts{
+			Name:    SQSQuoteAuditRouteDivergenceMetricName,
+			Help:    "histogram that measures the absolute relative divergence of the SQS amount out from the chain simulation per route",
+			Buckets: quoteAuditDivergenceBuckets,
+		},
+	)
+
+	SQSQuoteAuditErrorsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: SQSQuoteAuditErrorsCounterMetricName,
+			Help: "Total number of errors during the quote audit",
+		},
+		[]string{"stage"},
+	)
+
+	SQSQuoteAuditDroppedSamplesCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: SQSQuoteAuditDroppedSamplesCounterMetricName,
+			Help: "Total number of sampled served quotes dropped due to the sample limit",
+		},
+	)
+
+	SQSQuoteComputeDeadlineExceededCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: SQSQuoteComputeDeadlineExceededCounterMetricName,
+			Help: "Total number of quote computations that exceeded the compute deadline",
+		},
+		[]string{"stage", "outcome"},
+	)
+
+	SQSRouterRankedRoutesCoalescingCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: SQSRouterRankedRoutesCoalescingCounterMetricName,
+			Help: "Total number of ranked route computations coalesced by their cache key",
+		},
+		[]string{"result"},
+	)
 )
 
 func init() {
 	prometheus.MustRegister(SQSIngestHandlerProcessBlockDurationGauge)
 	prometheus.MustRegister(SQSIngestHandlerProcessBlockErrorCounter)
 	prometheus.MustRegister(SQSIngestHandlerProcessOrderbookPoolErrorCounter)
+	prometheus.MustRegister(SQSPoolsUsecaseRefreshAstroportPCLPoolErrorCounter)
 	prometheus.MustRegister(SQSIngestHandlerPoolParseErrorCounter)
 	prometheus.MustRegister(SQSPricingWorkerComputeDurationGauge)
 	prometheus.MustRegister(SQSPricingWorkerComputeErrorCounter)
@@ -308,10 +484,21 @@ func init() {
 	prometheus.MustRegister(SQSRoutesCacheHitsCounter)
 	prometheus.MustRegister(SQSRoutesCacheMissesCounter)
 	prometheus.MustRegister(SQSRoutesCacheWritesCounter)
+	prometheus.MustRegister(SQSRoutesCacheEvictionsCounter)
 	prometheus.MustRegister(SQSPricingCacheHitsCounter)
 	prometheus.MustRegister(SQSPricingCacheMissesCounter)
 	prometheus.MustRegister(SQSPricingTruncationCounter)
 	prometheus.MustRegister(SQSPricingSpotPriceError)
 	prometheus.MustRegister(SQSPricingCoingeckoCacheHitsCounter)
 	prometheus.MustRegister(SQSPricingCoingeckoCacheMissesCounter)
+	prometheus.MustRegister(SQSQuoteStreamSubscriptionsGauge)
+	prometheus.MustRegister(SQSQuoteStreamUpdatesCounter)
+	prometheus.MustRegister(SQSQuoteStreamDroppedUpdatesCounter)
+	prometheus.MustRegister(SQSQuoteStreamCoalescedBlocksCounter)
+	prometheus.MustRegister(SQSQuoteAuditPoolDivergenceHistogram)
+	prometheus.MustRegister(SQSQuoteAuditRouteDivergenceHistogram)
+	prometheus.MustRegister(SQSQuoteAuditErrorsCounter)
+	prometheus.MustRegister(SQSQuoteAuditDroppedSamplesCounter)
+	prometheus.MustRegister(SQSQuoteComputeDeadlineExceededCounter)
+	prometheus.MustRegister(SQSRouterRankedRoutesCoalescingCounter)
 }
@@ -105,7 +105,7 @@ func (i *IngestGRPCHandler) ProcessBlock(ctx context.Context, req *prototypes.Pr
 			span := trace.SpanFromContext(parentCtx)
 			ctx = trace.ContextWithSpan(ctx, span)
 
-			if err := i.ingestUseCase.ProcessBlockData(ctx, req.BlockHeight, takerFeeMap, req.Pools); err != nil {
+			if err := i.ingestUseCase.ProcessBlockData(ctx, req.BlockHeight, req.BlockTime, takerFeeMap, req.Pools); err != nil {
 				// Increment error counter
 				i.logger.Error(domain.SQSIngestUsecaseProcessBlockErrorMetricName, zap.Uint64("height", req.BlockHeight), zap.Error(err))
 				domain.SQSIngestHandlerProcessBlockErrorCounter.Inc()
@@ -20,8 +20,8 @@ func TransferDenomLiquidityMap(transferTo, transferFrom domain.DenomPoolLiquidit
 	return transferDenomLiquidityMap(transferTo, transferFrom)
 }
 
-func ProcessSQSModelMut(sqsModel *sqsdomain.SQSPool) error {
-	return processSQSModelMut(sqsModel)
+func ProcessSQSModelMut(sqsModel *sqsdomain.SQSPool, blockTime int64) error {
+	return processSQSModelMut(sqsModel, blockTime)
 }
 
 func UpdateCurrentBlockLiquidityMapAlloyed(currentBlockLiquidityMap domain.DenomPoolLiquidityMap, poolID uint64, alloyedDenom string) domain.DenomPoolLiquidityMap {
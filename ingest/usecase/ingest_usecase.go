@@ -45,6 +45,15 @@ type ingestUseCase struct {
 	// Worker that computes candidate routes for all tokens.
 	candidateRouteSearchWorker domain.CandidateRouteSearchDataWorker
 
+	// Sorter that maintains the pools sorted for the routers across blocks.
+	poolSorter *routerusecase.PoolSorter
+
+	// Guard held while updating the router state so that readers requiring
+	// a consistent view over it do not observe a partially updated state.
+	routerStateGuard *domain.RouterStateGuard
+	// Mutex serializing the updates of the router state since they are built before taking the guard.
+	routerStateUpdateMu sync.Mutex
+
 	// endBlockProcessPlugins are the plugins to execute at the end of the block.
 	endBlockProcessPlugins []domain.EndBlockProcessPlugin
 
@@ -87,7 +96,9 @@ var (
 )
 
 // NewIngestUsecase will create a new pools use case object
-func NewIngestUsecase(poolsUseCase mvc.PoolsUsecase, routerUseCase mvc.RouterUsecase, pricingRouterUsecase mvc.RouterUsecase, tokensUseCase mvc.TokensUsecase, chainInfoUseCase mvc.ChainInfoUsecase, codec codec.Codec, quotePriceUpdateWorker domain.PricingWorker, candidateRouteSearchWorker domain.CandidateRouteSearchDataWorker, orderBookUseCase mvc.OrderBookUsecase, logger log.Logger) (mvc.IngestUsecase, error) {
+func NewIngestUsecase(poolsUseCase mvc.PoolsUsecase, routerUseCase mvc.RouterUsecase, pricingRouterUsecase mvc.RouterUsecase, tokensUseCase mvc.TokensUsecase, chainInfoUseCase mvc.ChainInfoUsecase, codec codec.Codec, quotePriceUpdateWorker domain.PricingWorker, candidateRouteSearchWorker domain.CandidateRouteSearchDataWorker, orderBookUseCase mvc.OrderBookUsecase, routerStateGuard *domain.RouterStateGuard, logger log.Logger) (mvc.IngestUsecase, error) {
+	routerConfig := routerUseCase.GetConfig()
+
 	return &ingestUseCase{
 		codec: codec,
 
@@ -107,11 +118,15 @@ func NewIngestUsecase(poolsUseCase mvc.PoolsUsecase, routerUseCase mvc.RouterUse
 
 		candidateRouteSearchWorker: candidateRouteSearchWorker,
 
+		poolSorter: routerusecase.NewPoolSorter(poolsUseCase.GetCosmWasmPoolConfig(), routerConfig.PreferredPoolIDs, routerConfig.PoolFullSortIntervalBlocks, logger),
+
+		routerStateGuard: routerStateGuard,
+
 		firstHeightAfterStartUp: atomic.Uint64{},
 	}, nil
 }
 
-func (p *ingestUseCase) ProcessBlockData(ctx context.Context, height uint64, takerFeesMap sqsdomain.TakerFeeMap, poolData []*types.PoolData) (err error) {
+func (p *ingestUseCase) ProcessBlockData(ctx context.Context, height uint64, blockTime int64, takerFeesMap sqsdomain.TakerFeeMap, poolData []*types.PoolData) (err error) {
 	ctx, span := tracer.Start(ctx, "ingestUseCase.ProcessBlockData")
 	defer span.End()
 
@@ -125,36 +140,13 @@ func (p *ingestUseCase) ProcessBlockData(ctx context.Context, height uint64, tak
 
 	startProcessingTime := time.Now()
 
-	p.routerUsecase.SetTakerFees(takerFeesMap)
-
 	// Parse the pools
-	pools, uniqueBlockPoolMetadata, err := p.parsePoolData(ctx, poolData)
+	pools, uniqueBlockPoolMetadata, err := p.parsePoolData(ctx, poolData, blockTime)
 	if err != nil {
 		return err
 	}
 
-	// Store the pools
-	if err := p.poolsUseCase.StorePools(pools); err != nil {
-		return err
-	}
-
-	// Get all pools (already updated with the newly ingested pools)
-	allPools, err := p.poolsUseCase.GetAllPools()
-	if err != nil {
-		return err
-	}
-
-	// Sort and store pools.
-	p.logger.Info("sorting pools", zap.Uint64("height", height), zap.Duration("duration_since_start", time.Since(startProcessingTime)))
-
-	p.sortAndStorePools(allPools)
-
-	// If an error occurs, we should return it and not proceed with the next steps.
-	// The pricing relies on the search data. As a result, by returnining an error we trigger a fallback mechanism
-	// Note that compute search data is always synchronous because it is needed for all subsequent pre-computations within a block.
-	// Its latency is estimated to be negligile. As a result, it is not a concern.
-	if err := p.candidateRouteSearchWorker.ComputeSearchDataSync(ctx, height, uniqueBlockPoolMetadata); err != nil {
-		p.logger.Error("failed to compute search data", zap.Error(err))
+	if err := p.updateRouterState(ctx, height, takerFeesMap, pools, uniqueBlockPoolMetadata, startProcessingTime); err != nil {
 		return err
 	}
 
@@ -204,6 +196,61 @@ func (p *ingestUseCase) ProcessBlockData(ctx context.Context, height uint64, tak
 	return nil
 }
 
+// updateRouterState updates the router state with the taker fees and the pools from the block.
+// The new router state is built from the staged pools before taking the router state guard so that the guard
+// is only held while publishing it and the readers are not blocked while it is built.
+func (p *ingestUseCase) updateRouterState(ctx context.Context, height uint64, takerFeesMap sqsdomain.TakerFeeMap, pools []sqsdomain.PoolI, uniqueBlockPoolMetadata domain.BlockPoolMetadata, startProcessingTime time.Time) error {
+	p.routerStateUpdateMu.Lock()
+	defer p.routerStateUpdateMu.Unlock()
+
+	// View of the stored pools as if the pools from the block were stored.
+	stagedPools := p.poolsUseCase.StagePools(pools)
+
+	// Reposition the pools updated within the block among the sorted pools.
+	// All pools (including the staged ones) are re-sorted periodically. See routerusecase.PoolSorter for details.
+	p.logger.Info("sorting pools", zap.Uint64("height", height), zap.Duration("duration_since_start", time.Since(startProcessingTime)))
+
+	sortedPools, err := p.poolSorter.Update(pools, stagedPools.GetAllPools)
+	if err != nil {
+		return err
+	}
+
+	// If an error occurs, we should return it and not proceed with the next steps.
+	// The pricing relies on the search data. As a result, by returnining an error we trigger a fallback mechanism
+	// Note that the search data is always computed synchronously because it is needed for all subsequent pre-computations within a block.
+	searchData, err := p.candidateRouteSearchWorker.PrepareSearchData(uniqueBlockPoolMetadata, stagedPools)
+	if err != nil {
+		p.logger.Error("failed to compute search data", zap.Error(err))
+		return err
+	}
+
+	p.routerStateGuard.Lock()
+	defer p.routerStateGuard.Unlock()
+
+	// Record the height together with the state so that the readers stamp their results with the height
+	// of the state they computed against.
+	p.routerStateGuard.SetHeight(height)
+
+	p.routerUsecase.SetTakerFees(takerFeesMap)
+
+	// Store the pools
+	if err := p.poolsUseCase.StorePools(pools); err != nil {
+		return err
+	}
+
+	// Store the sorted pools in the routers.
+	p.routerUsecase.SetSortedPools(sortedPools)
+	p.pricingRouterUsecase.SetSortedPools(sortedPools)
+
+	p.candidateRouteSearchWorker.StoreSearchData(ctx, height, searchData)
+
+	// Evict the cached routes going through the updated pools so that they are recomputed against the new state.
+	// The time-based expiry remains as a backstop.
+	p.invalidateRouteCaches(height, uniqueBlockPoolMetadata.PoolIDs)
+
+	return nil
+}
+
 // RegisterEndBlockProcessPlugin implements mvc.IngestUsecase.
 func (p *ingestUseCase) RegisterEndBlockProcessPlugin(plugin domain.EndBlockProcessPlugin) {
 	p.endBlockProcessPlugins = append(p.endBlockProcessPlugins, plugin)
@@ -220,27 +267,56 @@ func (p *ingestUseCase) updateAssetsAtHeightIntervalAsync(height uint64) {
 	}()
 }
 
-// sortAndStorePools sorts the pools and stores them in the router.
-// TODO: instead of resorting all pools every block, we should put the updated pools in the correct position
-func (p *ingestUseCase) sortAndStorePools(pools []sqsdomain.PoolI) {
-	cosmWasmPoolConfig := p.poolsUseCase.GetCosmWasmPoolConfig()
-	routerConfig := p.routerUsecase.GetConfig()
+// OnPoolLiquidityCompute implements domain.PoolLiquidityComputeListener.
+// The liquidity capitalization of the pools updated within a block is repriced asynchronously after the block
+// is ingested, so the pools were rated with their stale liquidity capitalization. Once repriced, they are re-rated
+// and repositioned among the sorted pools that are then published under the router state guard.
+func (p *ingestUseCase) OnPoolLiquidityCompute(ctx context.Context, height uint64, blockPoolMetaData domain.BlockPoolMetadata) error {
+	if len(blockPoolMetaData.PoolIDs) == 0 {
+		return nil
+	}
 
-	sortedPools, _ := routerusecase.ValidateAndSortPools(pools, cosmWasmPoolConfig, routerConfig.PreferredPoolIDs, p.logger)
+	repricedPools, err := p.poolsUseCase.GetPools(domain.WithPoolIDFilter(domain.KeysFromMap(blockPoolMetaData.PoolIDs)))
+	if err != nil {
+		return err
+	}
+
+	p.routerStateUpdateMu.Lock()
+	defer p.routerStateUpdateMu.Unlock()
+
+	sortedPools, ok := p.poolSorter.Rerate(repricedPools)
+	if !ok {
+		return nil
+	}
+
+	p.routerStateGuard.Lock()
+	defer p.routerStateGuard.Unlock()
 
-	// Sort the pools and store them in the router.
 	p.routerUsecase.SetSortedPools(sortedPools)
 	p.pricingRouterUsecase.SetSortedPools(sortedPools)
+
+	p.logger.Debug("re-rated repriced pools", zap.Uint64("height", height), zap.Int("repriced_pools", len(repricedPools)))
+
+	return nil
+}
+
+// invalidateRouteCaches evicts the cached routes going through the given pools from the routers.
+func (p *ingestUseCase) invalidateRouteCaches(height uint64, poolIDs map[uint64]struct{}) {
+	evictedCount := p.routerUsecase.InvalidateRouteCaches(poolIDs)
+	evictedCount += p.pricingRouterUsecase.InvalidateRouteCaches(poolIDs)
+
+	p.logger.Debug("invalidated route caches", zap.Uint64("height", height), zap.Int("updated_pools", len(poolIDs)), zap.Int("evicted_entries", evictedCount))
 }
 
 // parsePoolData parses the pool data and returns the pool objects.
-func (p *ingestUseCase) parsePoolData(ctx context.Context, poolData []*types.PoolData) ([]sqsdomain.PoolI, domain.BlockPoolMetadata, error) {
+// blockTime is the time of the block the pool data is ingested at (Unix timestamp in nanoseconds).
+func (p *ingestUseCase) parsePoolData(ctx context.Context, poolData []*types.PoolData, blockTime int64) ([]sqsdomain.PoolI, domain.BlockPoolMetadata, error) {
 	poolResultChan := make(chan poolResult, len(poolData))
 
 	// Parse the pools concurrently
 	for _, pool := range poolData {
 		go func(pool *types.PoolData) {
-			poolResultData, err := p.parsePool(pool)
+			poolResultData, err := p.parsePool(pool, blockTime)
 
 			poolResultChan <- poolResult{
 				pool: poolResultData,
@@ -449,7 +525,7 @@ func transferDenomLiquidityMap(transferTo, transferFrom domain.DenomPoolLiquidit
 
 // parsePool parses the pool data and returns the pool object
 // For concentrated pools, it also processes the tick model
-func (p *ingestUseCase) parsePool(pool *types.PoolData) (sqsdomain.PoolI, error) {
+func (p *ingestUseCase) parsePool(pool *types.PoolData, blockTime int64) (sqsdomain.PoolI, error) {
 	poolWrapper := sqsdomain.PoolWrapper{}
 
 	if err := p.codec.UnmarshalInterfaceJSON(pool.ChainModel, &poolWrapper.ChainModel); err != nil {
@@ -468,7 +544,7 @@ func (p *ingestUseCase) parsePool(pool *types.PoolData) (sqsdomain.PoolI, error)
 	}
 
 	// Process the SQS model
-	if err := processSQSModelMut(&poolWrapper.SQSModel); err != nil {
+	if err := processSQSModelMut(&poolWrapper.SQSModel, blockTime); err != nil {
 		p.logger.Error("error processing SQS model", zap.Error(err))
 	}
 
@@ -486,8 +562,8 @@ func (p *ingestUseCase) executeEndBlockProcessPlugins(ctx context.Context, block
 
 // processSQSModelMut processes the SQS model and updates it.
 // Specifically, it removes the gamm shares from the balances and pool denoms.
-// Additionally it updates the alloyed denom if it is an alloy transmuter.
-func processSQSModelMut(sqsModel *sqsdomain.SQSPool) error {
+// Additionally it updates the alloyed denom and the block time if it is an alloy transmuter.
+func processSQSModelMut(sqsModel *sqsdomain.SQSPool, blockTime int64) error {
 	// Update alloyed denom since it is not in the balances.
 	cosmWasmModel := sqsModel.CosmWasmPoolModel
 	if cosmWasmModel != nil && cosmWasmModel.IsAlloyTransmuter() {
@@ -495,6 +571,9 @@ func processSQSModelMut(sqsModel *sqsdomain.SQSPool) error {
 			return fmt.Errorf("alloy transmuter data is nil, skipping silently, contract %s, version %s", cosmWasmModel.ContractInfo.Contract, cosmWasmModel.ContractInfo.Version)
 		}
 
+		// The change rate limiter is checked at the time of the ingested block.
+		cosmWasmModel.Data.AlloyTransmuter.BlockTime = blockTime
+
 		alloyedDenom := cosmWasmModel.Data.AlloyTransmuter.AlloyedDenom
 
 		sqsModel.PoolDenoms = append(sqsModel.PoolDenoms, alloyedDenom)
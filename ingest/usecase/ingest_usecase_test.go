@@ -2,19 +2,26 @@ package usecase_test
 
 import (
 	"context"
+	"encoding/json"
 	"math/rand"
 	"sync"
 	"testing"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/osmosis-labs/osmosis/osmomath"
+	cwpoolmodel "github.com/osmosis-labs/osmosis/v25/x/cosmwasmpool/model"
+	"github.com/osmosis-labs/sqs/app"
 	"github.com/osmosis-labs/sqs/domain"
+	cosmwasmdomain "github.com/osmosis-labs/sqs/domain/cosmwasm"
 	"github.com/osmosis-labs/sqs/domain/mocks"
+	"github.com/osmosis-labs/sqs/domain/mvc"
 	"github.com/osmosis-labs/sqs/ingest/usecase"
 	"github.com/osmosis-labs/sqs/log"
+	"github.com/osmosis-labs/sqs/router/usecase/pools"
 	"github.com/osmosis-labs/sqs/router/usecase/routertesting"
 	"github.com/osmosis-labs/sqs/sqsdomain"
 	"github.com/osmosis-labs/sqs/sqsdomain/cosmwasmpool"
+	"github.com/osmosis-labs/sqs/sqsdomain/proto/types"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -32,6 +39,7 @@ var (
 
 	UOSMO   = routertesting.UOSMO
 	USDC    = routertesting.USDC
+	USDT    = routertesting.USDT
 	ATOM    = routertesting.ATOM
 	ALLBTC  = routertesting.ALLBTC
 	ALLUSDT = routertesting.ALLUSDT
@@ -440,12 +448,13 @@ func (s *IngestUseCaseTestSuite) TestCallUpdateAssetsAtHeightIntervalSync() {
 				},
 				&mocks.CandidateRouteSearchDataWorkerMock{},
 				nil,
+				domain.NewRouterStateGuard(),
 				noOpLogger,
 			)
 			s.Require().NoError(err)
 
 			for height := 0; height < tt.wantCallCount; height++ {
-				err = ingester.ProcessBlockData(context.TODO(), uint64(height)+1, nil, nil)
+				err = ingester.ProcessBlockData(context.TODO(), uint64(height)+1, 0, nil, nil)
 				s.Require().NoError(err)
 			}
 
@@ -457,6 +466,7 @@ func (s *IngestUseCaseTestSuite) TestCallUpdateAssetsAtHeightIntervalSync() {
 }
 
 func (s *IngestUseCaseTestSuite) TestProcessSQSModelMut() {
+	const defaultBlockTime = int64(1_000_000)
 
 	var (
 		defaultModel = &sqsdomain.SQSPool{
@@ -592,13 +602,18 @@ func (s *IngestUseCaseTestSuite) TestProcessSQSModelMut() {
 		tc := tc
 		s.T().Run(tc.name, func(t *testing.T) {
 			// System under test
-			err := usecase.ProcessSQSModelMut(tc.sqsModel)
+			err := usecase.ProcessSQSModelMut(tc.sqsModel, defaultBlockTime)
 
 			if tc.expectedErr {
 				s.Require().Error(err)
 			} else {
 				s.Require().NoError(err)
 				s.Require().Equal(tc.expectedSQSModel, tc.sqsModel)
+
+				// The block time is set on the alloyed transmuter data.
+				if cosmWasmModel := tc.sqsModel.CosmWasmPoolModel; cosmWasmModel != nil && cosmWasmModel.Data.AlloyTransmuter != nil {
+					s.Require().Equal(defaultBlockTime, cosmWasmModel.Data.AlloyTransmuter.BlockTime)
+				}
 			}
 		})
 	}
@@ -736,3 +751,232 @@ func deepCopyDenomLiquidityMap(m domain.DenomPoolLiquidityMap) domain.DenomPoolL
 	}
 	return copy
 }
+
+// Validates that the change rate limiter of an alloyed transmuter pool ingested through ProcessBlockData
+// is checked at the time of the ingested block.
+// The division size is 100. At the block time T, the window starts 1000 before it and spans both divisions:
+// - the first division started 900 before T with the value of 0.6
+// - the second division started 100 before T with the value of 0.3333
+// The moving average is (0.6 * 800 + 0.3333 * 100) / 900 = 0.5703 for an upper limit of 0.6703.
+// Once the window advanced past both divisions, the upper limit is 0.3333 + 0.1 = 0.4333.
+// Swapping 600_000 USDC for USDT brings the USDC weight to 1.6 / 3.6 = 0.4444, which is only within the former.
+func (s *IngestUseCaseTestSuite) TestProcessBlockData_AlloyTransmuterBlockTime() {
+	const (
+		codeID    = uint64(814)
+		blockTime = int64(1_000_000)
+	)
+
+	encCfg := app.MakeEncodingConfig()
+
+	chainModel, err := encCfg.Marshaler.MarshalInterfaceJSON(&cwpoolmodel.CosmWasmPool{
+		ContractAddress: "osmo1alloyed",
+		PoolId:          defaultPoolID,
+		CodeId:          codeID,
+	})
+	s.Require().NoError(err)
+
+	sqsModel, err := json.Marshal(sqsdomain.SQSPool{
+		PoolLiquidityCap: osmomath.ZeroInt(),
+		Balances: sdk.NewCoins(
+			sdk.NewCoin(USDC, osmomath.NewInt(1_000_000)),
+			sdk.NewCoin(USDT, osmomath.NewInt(2_000_000)),
+		),
+		PoolDenoms:   []string{USDC, USDT},
+		SpreadFactor: osmomath.ZeroDec(),
+		CosmWasmPoolModel: cosmwasmpool.NewCWPoolModel(
+			cosmwasmpool.ALLOY_TRANSMUTER_CONTRACT_NAME, cosmwasmpool.ALLOY_TRANSMUTER_MIN_CONTRACT_VERSION,
+			cosmwasmpool.CosmWasmPoolData{
+				AlloyTransmuter: &cosmwasmpool.AlloyTransmuterData{
+					AlloyedDenom: ALLUSDT,
+					AssetConfigs: []cosmwasmpool.TransmuterAssetConfig{
+						{Denom: USDC, NormalizationFactor: oneInt},
+						{Denom: USDT, NormalizationFactor: oneInt},
+					},
+					RateLimiterConfig: cosmwasmpool.AlloyedRateLimiter{
+						ChangeLimiterByDenomMap: map[string]cosmwasmpool.ChangeLimiter{
+							USDC: {
+								Divisions: []cosmwasmpool.Division{
+									{StartedAt: blockTime - 900, UpdatedAt: blockTime - 900, LatestValue: "0.6", Integral: "0"},
+									{StartedAt: blockTime - 100, UpdatedAt: blockTime - 100, LatestValue: "0.3333", Integral: "0"},
+								},
+								LatestValue:    "0.3333",
+								WindowConfig:   cosmwasmpool.WindowConfig{WindowSize: 1000, DivisionCount: 10},
+								BoundaryOffset: "0.1",
+							},
+						},
+					},
+				},
+			},
+		),
+	})
+	s.Require().NoError(err)
+
+	cosmWasmPoolsParams := cosmwasmdomain.CosmWasmPoolsParams{
+		Config: domain.CosmWasmPoolRouterConfig{
+			AlloyedTransmuterCodeIDs: map[uint64]struct{}{codeID: {}},
+		},
+		ScalingFactorGetterCb: domain.UnsetScalingFactorGetterCb,
+	}
+
+	tokenIn := sdk.NewCoin(USDC, osmomath.NewInt(600_000))
+
+	tests := []struct {
+		name      string
+		blockTime int64
+
+		expectRateLimiterErr bool
+	}{
+		{
+			name:      "within the upper limit at the block time",
+			blockTime: blockTime,
+		},
+		{
+			name:      "window advanced past both divisions",
+			blockTime: blockTime + 2000,
+
+			expectRateLimiterErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		s.Run(tc.name, func() {
+			var storedPools []sqsdomain.PoolI
+
+			ingester, err := usecase.NewIngestUsecase(
+				&mocks.PoolsUsecaseMock{
+					StorePoolsFunc: func(updatedPools []sqsdomain.PoolI) error {
+						storedPools = updatedPools
+						return nil
+					},
+					GetAllPoolsFunc: func() ([]sqsdomain.PoolI, error) {
+						return storedPools, nil
+					},
+				},
+				&mocks.RouterUsecaseMock{},
+				&mocks.RouterUsecaseMock{},
+				&mocks.TokensUsecaseMock{
+					UpdateAssetsAtHeightIntervalSyncFunc: func(height uint64) error {
+						return nil
+					},
+				},
+				&mocks.ChainInfoUsecaseMock{},
+				encCfg.Marshaler,
+				&mocks.PricingWorkerMock{
+					UpdatePricesAsyncFunc: func(height uint64, uniqueBlockPoolMetaData domain.BlockPoolMetadata) {
+						// do nothing
+					},
+				},
+				&mocks.CandidateRouteSearchDataWorkerMock{},
+				nil,
+				domain.NewRouterStateGuard(),
+				noOpLogger,
+			)
+			s.Require().NoError(err)
+
+			// System under test
+			err = ingester.ProcessBlockData(context.TODO(), 1, tc.blockTime, nil, []*types.PoolData{
+				{ChainModel: chainModel, SqsModel: sqsModel},
+			})
+			s.Require().NoError(err)
+			s.Require().Len(storedPools, 1)
+
+			routablePool, err := pools.NewRoutablePool(storedPools[0], USDT, osmomath.ZeroDec(), cosmWasmPoolsParams)
+			s.Require().NoError(err)
+			s.Require().Equal(domain.AlloyedTransmuter, routablePool.GetSQSType())
+
+			_, err = routablePool.CalculateTokenOutByTokenIn(context.TODO(), tokenIn)
+			if tc.expectRateLimiterErr {
+				s.Require().ErrorAs(err, &domain.ChangeRateLimiterInvalidUpperLimitError{})
+				return
+			}
+			s.Require().NoError(err)
+		})
+	}
+}
+
+// Validates that the new router state is built before taking the router state guard
+// and that the guard is only held while publishing it.
+func (s *IngestUseCaseTestSuite) TestProcessBlockData_BuildsRouterStateOutsideGuard() {
+	var (
+		stateGuard = domain.NewRouterStateGuard()
+
+		searchData = map[string]domain.CandidateRouteDenomData{UOSMO: {}}
+
+		published []string
+	)
+
+	isGuardHeld := func() bool {
+		if stateGuard.TryRLock() {
+			stateGuard.RUnlock()
+			return false
+		}
+		return true
+	}
+
+	stagedPools := &mocks.PoolsUsecaseMock{
+		GetAllPoolsFunc: func() ([]sqsdomain.PoolI, error) {
+			// The pools are sorted outside of the guard.
+			s.Require().False(isGuardHeld())
+			return nil, nil
+		},
+	}
+
+	routerUsecase := &mocks.RouterUsecaseMock{
+		SetSortedPoolsFunc: func(pools []sqsdomain.PoolI) {
+			s.Require().True(isGuardHeld())
+			published = append(published, "sorted pools")
+		},
+	}
+
+	ingester, err := usecase.NewIngestUsecase(
+		&mocks.PoolsUsecaseMock{
+			StagePoolsFunc: func(pools []sqsdomain.PoolI) mvc.StagedPools {
+				s.Require().False(isGuardHeld())
+				return stagedPools
+			},
+			StorePoolsFunc: func(pools []sqsdomain.PoolI) error {
+				s.Require().True(isGuardHeld())
+				published = append(published, "pools")
+				return nil
+			},
+		},
+		routerUsecase,
+		&mocks.RouterUsecaseMock{},
+		&mocks.TokensUsecaseMock{
+			UpdateAssetsAtHeightIntervalSyncFunc: func(height uint64) error {
+				return nil
+			},
+		},
+		&mocks.ChainInfoUsecaseMock{},
+		nil,
+		&mocks.PricingWorkerMock{
+			UpdatePricesAsyncFunc: func(height uint64, uniqueBlockPoolMetaData domain.BlockPoolMetadata) {
+				// do nothing
+			},
+		},
+		&mocks.CandidateRouteSearchDataWorkerMock{
+			PrepareSearchDataFunc: func(uniqueBlockPoolMetaData domain.BlockPoolMetadata, pools domain.CandidateRouteSearchPoolReader) (map[string]domain.CandidateRouteDenomData, error) {
+				s.Require().False(isGuardHeld())
+				s.Require().Same(stagedPools, pools)
+				return searchData, nil
+			},
+			StoreSearchDataFunc: func(ctx context.Context, height uint64, actualSearchData map[string]domain.CandidateRouteDenomData) {
+				s.Require().True(isGuardHeld())
+				s.Require().Equal(searchData, actualSearchData)
+				published = append(published, "search data")
+			},
+		},
+		nil,
+		stateGuard,
+		noOpLogger,
+	)
+	s.Require().NoError(err)
+
+	// System under test
+	err = ingester.ProcessBlockData(context.TODO(), 1, 0, nil, nil)
+	s.Require().NoError(err)
+
+	s.Require().Equal([]string{"pools", "sorted pools", "search data"}, published)
+	s.Require().False(isGuardHeld())
+}
@@ -13,7 +13,6 @@ import (
 	"time"
 
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
-	gogogrpc "github.com/cosmos/gogoproto/grpc"
 	"go.uber.org/zap"
 
 	cometrpc "github.com/cometbft/cometbft/rpc/client/http"
@@ -29,6 +28,7 @@ import (
 	poolmanagertypes "github.com/osmosis-labs/osmosis/v25/x/poolmanager/types"
 	"github.com/osmosis-labs/sqs/domain"
 	orderbookplugindomain "github.com/osmosis-labs/sqs/domain/orderbook/plugin"
+	"github.com/osmosis-labs/sqs/domain/swaptx"
 	blockctx "github.com/osmosis-labs/sqs/ingest/usecase/plugins/orderbookfiller/context/block"
 	msgctx "github.com/osmosis-labs/sqs/ingest/usecase/plugins/orderbookfiller/context/msg"
 	txctx "github.com/osmosis-labs/sqs/ingest/usecase/plugins/orderbookfiller/context/tx"
@@ -265,14 +265,6 @@ func (o *orderbookFillerIngestPlugin) executeTx(
 }
 
 func (o *orderbookFillerIngestPlugin) simulateSwapExactAmountIn(ctx blockctx.BlockCtxI, tokenIn sdk.Coin, route []domain.RoutablePool) (msgctx.MsgContextI, error) {
-	poolManagerRoute := make([]poolmanagertypes.SwapAmountInRoute, len(route))
-	for i, r := range route {
-		poolManagerRoute[i] = poolmanagertypes.SwapAmountInRoute{
-			PoolId:        r.GetId(),
-			TokenOutDenom: r.GetTokenOutDenom(),
-		}
-	}
-
 	// Note that we lower the slippage bound, allowing losses.
 	// We still do profitability checks for all swaps > $5 of value down below.
 	// However, we allow for losses in the case of small swaps.
@@ -280,12 +272,7 @@ func (o *orderbookFillerIngestPlugin) simulateSwapExactAmountIn(ctx blockctx.Blo
 	// $5 * (1 - 0.9995) = $0.002
 	slippageBound := tokenIn.Amount.ToLegacyDec().Mul(lossTolerance).TruncateInt()
 
-	swapMsg := &poolmanagertypes.MsgSwapExactAmountIn{
-		Sender:            o.keyring.GetAddress().String(),
-		Routes:            poolManagerRoute,
-		TokenIn:           tokenIn,
-		TokenOutMinAmount: slippageBound,
-	}
+	swapMsg := swaptx.NewMsgSwapExactAmountIn(o.keyring.GetAddress().String(), tokenIn, route, slippageBound)
 
 	// Estimate transaction
 	gasResult, adjustedGasUsed, err := o.simulateMsgs(ctx.AsGoCtx(), []sdk.Msg{swapMsg})
@@ -338,10 +325,10 @@ func (o *orderbookFillerIngestPlugin) simulateMsgs(ctx context.Context, msgs []s
 	txFactory = txFactory.WithAccountNumber(accNum)
 	txFactory = txFactory.WithSequence(accSeq)
 	txFactory = txFactory.WithChainID(chainID)
-	txFactory = txFactory.WithGasAdjustment(1.02)
+	txFactory = txFactory.WithGasAdjustment(swaptx.DefaultGasAdjustment)
 
 	// Estimate transaction
-	gasResult, adjustedGasUsed, err := CalculateGas(ctx, o.passthroughGRPCClient.GetChainGRPCClient(), txFactory, msgs...)
+	gasResult, adjustedGasUsed, err := swaptx.CalculateGas(ctx, o.passthroughGRPCClient.GetChainGRPCClient(), txFactory, msgs...)
 	if err != nil {
 		return nil, adjustedGasUsed, err
 	}
@@ -349,28 +336,6 @@ func (o *orderbookFillerIngestPlugin) simulateMsgs(ctx context.Context, msgs []s
 	return gasResult, adjustedGasUsed, nil
 }
 
-// CalculateGas simulates the execution of a transaction and returns the
-// simulation response obtained by the query and the adjusted gas amount.
-func CalculateGas(
-	ctx context.Context,
-	clientCtx gogogrpc.ClientConn, txf tx.Factory, msgs ...sdk.Msg,
-) (*txtypes.SimulateResponse, uint64, error) {
-	txBytes, err := txf.BuildSimTx(msgs...)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	txSvcClient := txtypes.NewServiceClient(clientCtx)
-	simRes, err := txSvcClient.Simulate(ctx, &txtypes.SimulateRequest{
-		TxBytes: txBytes,
-	})
-	if err != nil {
-		return nil, 0, err
-	}
-
-	return simRes, uint64(txf.GasAdjustment() * float64(simRes.GasInfo.GasUsed)), nil
-}
-
 // broadcastTransaction broadcasts a transaction to the chain.
 // Returning the result and error.
 func broadcastTransaction(ctx context.Context, txBytes []byte, rpcEndpoint string) (*coretypes.ResultBroadcastTx, error) {
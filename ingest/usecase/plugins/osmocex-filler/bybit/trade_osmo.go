@@ -11,11 +11,10 @@ import (
 	txtypes "github.com/cosmos/cosmos-sdk/types/tx"
 	signing "github.com/cosmos/cosmos-sdk/types/tx/signing"
 	authsigning "github.com/cosmos/cosmos-sdk/x/auth/signing"
-	gogogrpc "github.com/cosmos/gogoproto/grpc"
 	"github.com/cosmos/ibc-go/v7/testing/simapp"
 	"github.com/osmosis-labs/osmosis/osmomath"
-	poolmanagertypes "github.com/osmosis-labs/osmosis/v25/x/poolmanager/types"
 	"github.com/osmosis-labs/sqs/domain"
+	"github.com/osmosis-labs/sqs/domain/swaptx"
 	"go.uber.org/zap"
 )
 
@@ -56,21 +55,7 @@ func (be *BybitExchange) simulateOsmoTradeOutGivenIn(coinIn sdk.Coin, denomOut s
 		return nil, osmomath.ZeroBigDec(), 0, fmt.Errorf("split route should have 1 route")
 	}
 
-	route := splitRoute[0].GetPools()
-	poolmanagerRoute := make([]poolmanagertypes.SwapAmountInRoute, 0, len(route))
-	for _, pool := range route {
-		poolmanagerRoute = append(poolmanagerRoute, poolmanagertypes.SwapAmountInRoute{
-			PoolId:        pool.GetId(),
-			TokenOutDenom: pool.GetTokenOutDenom(),
-		})
-	}
-
-	swapMsg := &poolmanagertypes.MsgSwapExactAmountIn{
-		Sender:            (*be.osmoKeyring).GetAddress().String(),
-		Routes:            poolmanagerRoute,
-		TokenIn:           coinIn,
-		TokenOutMinAmount: amountOutExpectedBigDec.Dec().RoundInt(),
-	}
+	swapMsg := swaptx.NewMsgSwapExactAmountIn((*be.osmoKeyring).GetAddress().String(), coinIn, splitRoute[0].GetPools(), amountOutExpectedBigDec.Dec().RoundInt())
 
 	msgs := []sdk.Msg{swapMsg}
 
@@ -231,35 +216,13 @@ func (be *BybitExchange) simulateOsmoMsgs(ctx context.Context, msgs []sdk.Msg) (
 	txFactory = txFactory.WithAccountNumber(accNum)
 	txFactory = txFactory.WithSequence(accSeq)
 	txFactory = txFactory.WithChainID(chainID)
-	txFactory = txFactory.WithGasAdjustment(1.02)
+	txFactory = txFactory.WithGasAdjustment(swaptx.DefaultGasAdjustment)
 
 	// Estimate transaction
-	gasResult, adjustedGasUsed, err := CalculateGas(ctx, (*be.osmoPassthroughGRPCClient).GetChainGRPCClient(), txFactory, msgs...)
+	gasResult, adjustedGasUsed, err := swaptx.CalculateGas(ctx, (*be.osmoPassthroughGRPCClient).GetChainGRPCClient(), txFactory, msgs...)
 	if err != nil {
 		return nil, adjustedGasUsed, err
 	}
 
 	return gasResult, adjustedGasUsed, nil
 }
-
-// CalculateGas simulates the execution of a transaction and returns the
-// simulation response obtained by the query and the adjusted gas amount.
-func CalculateGas(
-	ctx context.Context,
-	clientCtx gogogrpc.ClientConn, txf tx.Factory, msgs ...sdk.Msg,
-) (*txtypes.SimulateResponse, uint64, error) {
-	txBytes, err := txf.BuildSimTx(msgs...)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	txSvcClient := txtypes.NewServiceClient(clientCtx)
-	simRes, err := txSvcClient.Simulate(ctx, &txtypes.SimulateRequest{
-		TxBytes: txBytes,
-	})
-	if err != nil {
-		return nil, 0, err
-	}
-
-	return simRes, uint64(txf.GasAdjustment() * float64(simRes.GasInfo.GasUsed)), nil
-}
@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/osmosis-labs/sqs/domain"
+	astroportgrpcclientdomain "github.com/osmosis-labs/sqs/domain/astroport/grpcclient"
+	cosmwasmdomain "github.com/osmosis-labs/sqs/domain/cosmwasm"
+	"github.com/osmosis-labs/sqs/log"
+	"github.com/osmosis-labs/sqs/sqsdomain/cosmwasmpool"
+)
+
+// astroportPCLQueryTimeout bounds the contract queries of a single Astroport PCL pool refresh.
+const astroportPCLQueryTimeout = 5 * time.Second
+
+// astroportPCLAssets are the denoms and the decimal precisions of the assets of an Astroport PCL pool
+// in the order of the contract. They are fixed at the instantiation of the contract.
+type astroportPCLAssets struct {
+	denoms     []string
+	precisions []uint32
+}
+
+// astroportPCLRefresher refreshes the PCL data of the Astroport PCL pools from their contracts
+// in the background so that the contract queries do not hold up the block ingestion.
+//
+// The pools are enqueued as they are stored, that is, whenever their contract state changes.
+// A pool enqueued again before its refresh is refreshed once. The assets of a pool are fetched once
+// while the params are fetched on every refresh. Until the refresh completes, the pool is quoted with
+// the params of the previous refresh, if any, and as a generalized CosmWasm pool otherwise.
+type astroportPCLRefresher struct {
+	client astroportgrpcclientdomain.AstroportPCLClient
+	store  *cosmwasmdomain.AstroportPCLDataStore
+
+	mu sync.Mutex
+	// pending are the contract addresses of the pools awaiting a refresh keyed by pool ID.
+	pending map[uint64]string
+	// notifyCh signals the worker that pools are pending.
+	notifyCh chan struct{}
+
+	// assets are the assets of the refreshed pools keyed by pool ID.
+	// Only accessed by the worker.
+	assets map[uint64]astroportPCLAssets
+
+	logger log.Logger
+}
+
+// newAstroportPCLRefresher returns a new refresher storing the fetched PCL data in the given store.
+// The refresher does not process the enqueued pools until run is called.
+func newAstroportPCLRefresher(client astroportgrpcclientdomain.AstroportPCLClient, store *cosmwasmdomain.AstroportPCLDataStore, logger log.Logger) *astroportPCLRefresher {
+	return &astroportPCLRefresher{
+		client:   client,
+		store:    store,
+		pending:  make(map[uint64]string),
+		notifyCh: make(chan struct{}, 1),
+		assets:   make(map[uint64]astroportPCLAssets),
+		logger:   logger,
+	}
+}
+
+// enqueue schedules the refresh of the given pool without blocking.
+func (r *astroportPCLRefresher) enqueue(poolID uint64, contractAddress string) {
+	r.mu.Lock()
+	r.pending[poolID] = contractAddress
+	r.mu.Unlock()
+
+	select {
+	case r.notifyCh <- struct{}{}:
+	default:
+		// The worker is already notified.
+	}
+}
+
+// run refreshes the enqueued pools until the context is cancelled.
+func (r *astroportPCLRefresher) run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.notifyCh:
+		}
+
+		r.mu.Lock()
+		pending := r.pending
+		r.pending = make(map[uint64]string, len(pending))
+		r.mu.Unlock()
+
+		for poolID, contractAddress := range pending {
+			if err := r.refreshPool(ctx, poolID, contractAddress); err != nil {
+				domain.SQSPoolsUsecaseRefreshAstroportPCLPoolErrorCounter.Inc()
+				r.logger.Error(domain.SQSPoolsUsecaseRefreshAstroportPCLPoolErrorMetricName, zap.Error(err), zap.Uint64("pool_id", poolID))
+			}
+		}
+	}
+}
+
+// refreshPool fetches the PCL data of the given pool from its contract and stores it.
+// The assets are only fetched if they have not been fetched by a previous refresh.
+// Returns error if any of the queries fails or if the fetched data is invalid, leaving the stored data unchanged.
+func (r *astroportPCLRefresher) refreshPool(ctx context.Context, poolID uint64, contractAddress string) error {
+	ctx, cancel := context.WithTimeout(ctx, astroportPCLQueryTimeout)
+	defer cancel()
+
+	assets, ok := r.assets[poolID]
+	if !ok {
+		denoms, err := r.client.GetPairAssetDenoms(ctx, contractAddress)
+		if err != nil {
+			return err
+		}
+
+		precisions, err := r.client.GetAssetPrecisions(ctx, contractAddress, denoms)
+		if err != nil {
+			return err
+		}
+
+		assets = astroportPCLAssets{denoms: denoms, precisions: precisions}
+	}
+
+	params, err := r.client.GetPoolParams(ctx, contractAddress)
+	if err != nil {
+		return err
+	}
+
+	data := &cosmwasmpool.AstroportPCLData{
+		AssetDenoms:     assets.denoms,
+		AssetPrecisions: assets.precisions,
+		Amp:             params.Amp,
+		Gamma:           params.Gamma,
+		MidFee:          params.MidFee,
+		OutFee:          params.OutFee,
+		FeeGamma:        params.FeeGamma,
+		PriceScale:      params.PriceScale,
+	}
+
+	if err := data.Validate(); err != nil {
+		return err
+	}
+
+	r.assets[poolID] = assets
+	r.store.Set(poolID, data)
+
+	return nil
+}
@@ -0,0 +1,246 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	cosmwasmpoolmodel "github.com/osmosis-labs/osmosis/v25/x/cosmwasmpool/model"
+	poolmanagertypes "github.com/osmosis-labs/osmosis/v25/x/poolmanager/types"
+
+	astroportgrpcclientdomain "github.com/osmosis-labs/sqs/domain/astroport/grpcclient"
+	cosmwasmdomain "github.com/osmosis-labs/sqs/domain/cosmwasm"
+	"github.com/osmosis-labs/sqs/domain/mocks"
+	"github.com/osmosis-labs/sqs/log"
+	"github.com/osmosis-labs/sqs/pools/usecase"
+	"github.com/osmosis-labs/sqs/sqsdomain"
+	"github.com/osmosis-labs/sqs/sqsdomain/cosmwasmpool"
+)
+
+const pclContractAddress = "osmo1pcl"
+
+var (
+	pclPoolParams = astroportgrpcclientdomain.PoolParams{
+		Amp:        osmomath.NewDec(40),
+		Gamma:      osmomath.MustNewDecFromStr("0.000145"),
+		MidFee:     osmomath.MustNewDecFromStr("0.0026"),
+		OutFee:     osmomath.MustNewDecFromStr("0.0045"),
+		FeeGamma:   osmomath.MustNewDecFromStr("0.00023"),
+		PriceScale: osmomath.MustNewDecFromStr("0.302571428571428571"),
+	}
+
+	pclData = &cosmwasmpool.AstroportPCLData{
+		AssetDenoms:     []string{denomOne, denomTwo},
+		AssetPrecisions: []uint32{6, 18},
+		Amp:             pclPoolParams.Amp,
+		Gamma:           pclPoolParams.Gamma,
+		MidFee:          pclPoolParams.MidFee,
+		OutFee:          pclPoolParams.OutFee,
+		FeeGamma:        pclPoolParams.FeeGamma,
+		PriceScale:      pclPoolParams.PriceScale,
+	}
+)
+
+// pclClientCalls counts the contract queries made by the refresher.
+type pclClientCalls struct {
+	pair       int
+	precisions int
+	params     int
+}
+
+// newPCLClientMock returns a client mock returning the default PCL data and counting the queries.
+func (s *PoolsUsecaseTestSuite) newPCLClientMock(calls *pclClientCalls) *mocks.AstroportPCLGRPCClientMock {
+	return &mocks.AstroportPCLGRPCClientMock{
+		GetPairAssetDenomsCb: func(ctx context.Context, contractAddress string) ([]string, error) {
+			s.Require().Equal(pclContractAddress, contractAddress)
+			calls.pair++
+			return pclData.AssetDenoms, nil
+		},
+		GetAssetPrecisionsCb: func(ctx context.Context, contractAddress string, denoms []string) ([]uint32, error) {
+			s.Require().Equal(pclData.AssetDenoms, denoms)
+			calls.precisions++
+			return pclData.AssetPrecisions, nil
+		},
+		GetPoolParamsCb: func(ctx context.Context, contractAddress string) (astroportgrpcclientdomain.PoolParams, error) {
+			s.Require().Equal(pclContractAddress, contractAddress)
+			calls.params++
+			return pclPoolParams, nil
+		},
+	}
+}
+
+// newPCLPool returns an Astroport PCL pool with the given ingested data.
+func newPCLPool(data *cosmwasmpool.AstroportPCLData) *sqsdomain.PoolWrapper {
+	return &sqsdomain.PoolWrapper{
+		ChainModel: &cosmwasmpoolmodel.CosmWasmPool{PoolId: defaultPoolID, ContractAddress: pclContractAddress},
+		SQSModel: sqsdomain.SQSPool{
+			CosmWasmPoolModel: cosmwasmpool.NewCWPoolModel(
+				cosmwasmpool.ASTROPORT_PCL_CONTRACT_NAME,
+				cosmwasmpool.ASTROPORT_PCL_MIN_CONTRACT_VERSION,
+				cosmwasmpool.CosmWasmPoolData{AstroportPCL: data},
+			),
+		},
+	}
+}
+
+// Validates that a refresh stores the data fetched from the contract
+// and that the assets are only fetched by the first refresh of a pool.
+func (s *PoolsUsecaseTestSuite) TestAstroportPCLRefresher_RefreshPool() {
+	calls := pclClientCalls{}
+	store := cosmwasmdomain.NewAstroportPCLDataStore()
+	refresher := usecase.NewAstroportPCLRefresher(s.newPCLClientMock(&calls), store, &log.NoOpLogger{})
+
+	for i := 0; i < 2; i++ {
+		// System under test
+		err := refresher.RefreshPool(context.Background(), defaultPoolID, pclContractAddress)
+		s.Require().NoError(err)
+
+		actualData, ok := store.Get(defaultPoolID)
+		s.Require().True(ok)
+		s.Require().Equal(pclData, actualData)
+	}
+
+	s.Require().Equal(pclClientCalls{pair: 1, precisions: 1, params: 2}, calls)
+}
+
+// Validates that a failed refresh leaves the stored data unchanged
+// and that the assets are fetched again if the first refresh fails.
+func (s *PoolsUsecaseTestSuite) TestAstroportPCLRefresher_RefreshPool_Error() {
+	var (
+		queryErr = errors.New("query failed")
+
+		invalidParams = func() astroportgrpcclientdomain.PoolParams {
+			params := pclPoolParams
+			params.MidFee = osmomath.OneDec()
+			return params
+		}()
+	)
+
+	tests := []struct {
+		name       string
+		storedData *cosmwasmpool.AstroportPCLData
+		updateMock func(client *mocks.AstroportPCLGRPCClientMock)
+	}{
+		{
+			name: "pair query fails",
+			updateMock: func(client *mocks.AstroportPCLGRPCClientMock) {
+				client.GetPairAssetDenomsCb = func(ctx context.Context, contractAddress string) ([]string, error) {
+					return nil, queryErr
+				}
+			},
+		},
+		{
+			name: "precisions query fails",
+			updateMock: func(client *mocks.AstroportPCLGRPCClientMock) {
+				client.GetAssetPrecisionsCb = func(ctx context.Context, contractAddress string, denoms []string) ([]uint32, error) {
+					return nil, queryErr
+				}
+			},
+		},
+		{
+			name:       "config query fails with previously stored data",
+			storedData: pclData,
+			updateMock: func(client *mocks.AstroportPCLGRPCClientMock) {
+				client.GetPoolParamsCb = func(ctx context.Context, contractAddress string) (astroportgrpcclientdomain.PoolParams, error) {
+					return astroportgrpcclientdomain.PoolParams{}, queryErr
+				}
+			},
+		},
+		{
+			name: "invalid params",
+			updateMock: func(client *mocks.AstroportPCLGRPCClientMock) {
+				client.GetPoolParamsCb = func(ctx context.Context, contractAddress string) (astroportgrpcclientdomain.PoolParams, error) {
+					return invalidParams, nil
+				}
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		s.Run(tc.name, func() {
+			calls := pclClientCalls{}
+			client := s.newPCLClientMock(&calls)
+			tc.updateMock(client)
+
+			store := cosmwasmdomain.NewAstroportPCLDataStore()
+			if tc.storedData != nil {
+				store.Set(defaultPoolID, tc.storedData)
+			}
+
+			refresher := usecase.NewAstroportPCLRefresher(client, store, &log.NoOpLogger{})
+
+			// System under test
+			err := refresher.RefreshPool(context.Background(), defaultPoolID, pclContractAddress)
+			s.Require().Error(err)
+
+			actualData, ok := store.Get(defaultPoolID)
+			s.Require().Equal(tc.storedData != nil, ok)
+			s.Require().Equal(tc.storedData, actualData)
+
+			// The assets are not cached by a failed refresh.
+			*client = *s.newPCLClientMock(&calls)
+			calls = pclClientCalls{}
+
+			err = refresher.RefreshPool(context.Background(), defaultPoolID, pclContractAddress)
+			s.Require().NoError(err)
+			s.Require().Equal(pclClientCalls{pair: 1, precisions: 1, params: 1}, calls)
+		})
+	}
+}
+
+// Validates that the worker refreshes the enqueued pools.
+func (s *PoolsUsecaseTestSuite) TestAstroportPCLRefresher_Run() {
+	calls := pclClientCalls{}
+	store := cosmwasmdomain.NewAstroportPCLDataStore()
+	refresher := usecase.NewAstroportPCLRefresher(s.newPCLClientMock(&calls), store, &log.NoOpLogger{})
+
+	poolsUsecase := s.newDefaultPoolsUseCase()
+	poolsUsecase.SetAstroportPCLRefresher(refresher)
+
+	// Enqueued before the worker runs.
+	err := poolsUsecase.StorePools([]sqsdomain.PoolI{newPCLPool(nil)})
+	s.Require().NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// System under test
+	go refresher.Run(ctx)
+
+	s.Require().Eventually(func() bool {
+		_, ok := store.Get(defaultPoolID)
+		return ok
+	}, time.Second, 10*time.Millisecond)
+
+	actualData, _ := store.Get(defaultPoolID)
+	s.Require().Equal(pclData, actualData)
+}
+
+// Validates that storing the pools enqueues the refresh of the Astroport PCL pools missing the PCL data only.
+func (s *PoolsUsecaseTestSuite) TestStorePools_EnqueuesAstroportPCLRefresh() {
+	var (
+		pclPoolWithData = newPCLPool(pclData)
+
+		otherCosmWasmPool = &mocks.MockRoutablePool{
+			ChainPoolModel: &mocks.ChainPoolMock{
+				ID:   defaultPoolID + 2,
+				Type: poolmanagertypes.CosmWasm,
+			},
+			ID: defaultPoolID + 2,
+		}
+	)
+	pclPoolWithData.ChainModel = &cosmwasmpoolmodel.CosmWasmPool{PoolId: defaultPoolID + 1, ContractAddress: "osmo1pclwithdata"}
+
+	refresher := usecase.NewAstroportPCLRefresher(&mocks.AstroportPCLGRPCClientMock{}, cosmwasmdomain.NewAstroportPCLDataStore(), &log.NoOpLogger{})
+
+	poolsUsecase := s.newDefaultPoolsUseCase()
+	poolsUsecase.SetAstroportPCLRefresher(refresher)
+
+	// System under test
+	err := poolsUsecase.StorePools([]sqsdomain.PoolI{newPCLPool(nil), pclPoolWithData, otherCosmWasmPool})
+	s.Require().NoError(err)
+
+	s.Require().Equal(map[uint64]string{defaultPoolID: pclContractAddress}, refresher.GetPending())
+}
@@ -1,8 +1,13 @@
 package usecase
 
 import (
+	"context"
+
 	"github.com/osmosis-labs/osmosis/osmomath"
 	"github.com/osmosis-labs/sqs/domain"
+	astroportgrpcclientdomain "github.com/osmosis-labs/sqs/domain/astroport/grpcclient"
+	cosmwasmdomain "github.com/osmosis-labs/sqs/domain/cosmwasm"
+	"github.com/osmosis-labs/sqs/log"
 	"github.com/osmosis-labs/sqs/sqsdomain"
 
 	"github.com/osmosis-labs/osmosis/v25/x/gamm/types"
@@ -11,8 +16,9 @@ import (
 )
 
 type (
-	OrderBookEntry = orderBookEntry
-	PoolsUsecase   = poolsUseCase
+	OrderBookEntry        = orderBookEntry
+	PoolsUsecase          = poolsUseCase
+	AstroportPCLRefresher = astroportPCLRefresher
 )
 
 const (
@@ -50,3 +56,35 @@ func (p *poolsUseCase) RetainPoolIfMatchesOptions(poolsToUpdate []sqsdomain.Pool
 func (p *poolsUseCase) CalcExitPool(ctx sdk.Context, pool types.CFMMPoolI, exitingSharesIn osmomath.Int, exitFee osmomath.Dec) (sdk.Coins, error) {
 	return calcExitPool(ctx, pool, exitingSharesIn, exitFee)
 }
+
+func (p *poolsUseCase) GetCalcQueryMemo() *cosmwasmdomain.CalcQueryMemo {
+	return p.cosmWasmPoolsParams.CalcQueryMemo
+}
+
+func NewAstroportPCLRefresher(client astroportgrpcclientdomain.AstroportPCLClient, store *cosmwasmdomain.AstroportPCLDataStore, logger log.Logger) *AstroportPCLRefresher {
+	return newAstroportPCLRefresher(client, store, logger)
+}
+
+func (r *astroportPCLRefresher) RefreshPool(ctx context.Context, poolID uint64, contractAddress string) error {
+	return r.refreshPool(ctx, poolID, contractAddress)
+}
+
+func (r *astroportPCLRefresher) Run(ctx context.Context) {
+	r.run(ctx)
+}
+
+func (r *astroportPCLRefresher) GetPending() map[uint64]string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pending := make(map[uint64]string, len(r.pending))
+	for poolID, contractAddress := range r.pending {
+		pending[poolID] = contractAddress
+	}
+	return pending
+}
+
+// WARNING: this method is only meant for setting up tests. Do not move out of export_test.go
+func (p *poolsUseCase) SetAstroportPCLRefresher(refresher *astroportPCLRefresher) {
+	p.astroportPCLRefresher = refresher
+}
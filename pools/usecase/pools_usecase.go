@@ -25,6 +25,7 @@ import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
 	"github.com/osmosis-labs/sqs/domain"
+	astroportgrpcclientdomain "github.com/osmosis-labs/sqs/domain/astroport/grpcclient"
 	"github.com/osmosis-labs/sqs/domain/mvc"
 	passthroughdomain "github.com/osmosis-labs/sqs/domain/passthrough"
 	routerrepo "github.com/osmosis-labs/sqs/router/repository"
@@ -54,6 +55,10 @@ type poolsUseCase struct {
 
 	cosmWasmPoolsParams cosmwasmdomain.CosmWasmPoolsParams
 
+	// astroportPCLRefresher refreshes the PCL data of the stored Astroport PCL pools.
+	// Nil if the native PCL math is disabled.
+	astroportPCLRefresher *astroportPCLRefresher
+
 	aprPrefetcher      datafetchers.MapFetcher[uint64, passthroughdomain.PoolAPR]
 	poolFeesPrefetcher datafetchers.MapFetcher[uint64, passthroughdomain.PoolFee]
 
@@ -89,11 +94,32 @@ func NewPoolsUsecase(poolsConfig *domain.PoolsConfig, chainGRPCGatewayEndpoint s
 		generalizedCosmWasmCodeIDsMap[codeID] = struct{}{}
 	}
 
+	cosmWasmPoolImplementations, err := pools.NewCosmWasmPoolImplementations(poolsConfig.CosmWasmPoolImplementations)
+	if err != nil {
+		return nil, err
+	}
+
 	wasmClient, err := initializeWasmClient(chainGRPCGatewayEndpoint)
 	if err != nil {
 		return nil, err
 	}
 
+	var calcQueryMemo *cosmwasmdomain.CalcQueryMemo
+	if poolsConfig.GeneralCosmWasmCalcQueryBudget > 0 {
+		calcQueryMemo = cosmwasmdomain.NewCalcQueryMemo(poolsConfig.GeneralCosmWasmCalcQueryBudget, poolsConfig.GeneralCosmWasmCalcAmountBucketDigits, poolsConfig.GeneralCosmWasmCalcMemoMaxEntries)
+	}
+
+	var (
+		astroportPCLDataStore *cosmwasmdomain.AstroportPCLDataStore
+		pclRefresher          *astroportPCLRefresher
+	)
+	if poolsConfig.AstroportPCLEnabled {
+		astroportPCLDataStore = cosmwasmdomain.NewAstroportPCLDataStore()
+		pclRefresher = newAstroportPCLRefresher(astroportgrpcclientdomain.New(wasmClient), astroportPCLDataStore, logger)
+
+		go pclRefresher.run(context.Background())
+	}
+
 	return &poolsUseCase{
 		pools:            sync.Map{},
 		routerRepository: routerRepository,
@@ -105,13 +131,25 @@ func NewPoolsUsecase(poolsConfig *domain.PoolsConfig, chainGRPCGatewayEndpoint s
 				OrderbookCodeIDs:         orderbookCodeIDsMap,
 				GeneralCosmWasmCodeIDs:   generalizedCosmWasmCodeIDsMap,
 				ChainGRPCGatewayEndpoint: chainGRPCGatewayEndpoint,
+
+				GeneralCosmWasmCalcQueryBudget: poolsConfig.GeneralCosmWasmCalcQueryBudget,
+
+				Implementations: cosmWasmPoolImplementations,
+
+				AstroportPCLEnabled: poolsConfig.AstroportPCLEnabled,
 			},
 
 			WasmClient: wasmClient,
 
 			ScalingFactorGetterCb: scalingFactorGetterCb,
+
+			CalcQueryMemo: calcQueryMemo,
+
+			AstroportPCLDataStore: astroportPCLDataStore,
 		},
 
+		astroportPCLRefresher: pclRefresher,
+
 		logger: logger,
 	}, nil
 }
@@ -157,7 +195,8 @@ func (p *poolsUseCase) GetRoutesFromCandidates(candidateRoutes sqsdomain.Candida
 				return nil, err
 			}
 
-			// Get taker fee
+			// Get taker fee of the hop.
+			// The chain charges each hop the taker fee of the hop token in and token out denoms.
 			takerFee, exists := p.routerRepository.GetTakerFee(previousTokenOutDenom, candidatePool.TokenOutDenom)
 			if !exists {
 				takerFee = sqsdomain.DefaultTakerFee
@@ -169,6 +208,10 @@ func (p *poolsUseCase) GetRoutesFromCandidates(candidateRoutes sqsdomain.Candida
 				break
 			}
 
+			// Set token in denom so that the pool can be used for
+			// computing token in given token out.
+			routablePool.SetTokenInDenom(previousTokenOutDenom)
+
 			isGeneralizedCosmWasmPool := routablePool.GetSQSType() == domain.GeneralizedCosmWasm
 			if isGeneralizedCosmWasmPool {
 				containsGeneralizedCosmWasmPool = true
@@ -176,6 +219,8 @@ func (p *poolsUseCase) GetRoutesFromCandidates(candidateRoutes sqsdomain.Candida
 
 			// Create routable pool
 			routablePools = append(routablePools, routablePool)
+
+			previousTokenOutDenom = candidatePool.TokenOutDenom
 		}
 
 		// Skip the route if there was an error
@@ -355,6 +400,15 @@ func (p *poolsUseCase) GetPools(opts ...domain.PoolsOption) ([]sqsdomain.PoolI,
 
 // StorePools implements mvc.PoolsUsecase.
 func (p *poolsUseCase) StorePools(pools []sqsdomain.PoolI) error {
+	// Drop the memoised calc queries of the updated pools.
+	if p.cosmWasmPoolsParams.CalcQueryMemo != nil {
+		updatedPoolIDs := make(map[uint64]struct{}, len(pools))
+		for _, pool := range pools {
+			updatedPoolIDs[pool.GetId()] = struct{}{}
+		}
+		p.cosmWasmPoolsParams.CalcQueryMemo.InvalidatePools(updatedPoolIDs)
+	}
+
 	for _, pool := range pools {
 		// Store pool
 		poolID := pool.GetId()
@@ -363,6 +417,16 @@ func (p *poolsUseCase) StorePools(pools []sqsdomain.PoolI) error {
 		// If orderbook, update top liquidity pool for base and quote denom if it has higher liquidity capitalization.
 		sqsModel := pool.GetSQSPoolModel()
 		cosmWasmPoolModel := sqsModel.CosmWasmPoolModel
+
+		// If Astroport PCL, schedule the refresh of its params since the contract state changed.
+		// The refresh runs in the background so that the contract queries do not block the ingestion.
+		if p.astroportPCLRefresher != nil && cosmWasmPoolModel != nil && cosmWasmPoolModel.Data.AstroportPCL == nil && cosmWasmPoolModel.IsAstroportPCL() {
+			chainCosmWasmPool, ok := pool.GetUnderlyingPool().(*cosmwasmpoolmodel.CosmWasmPool)
+			if ok && chainCosmWasmPool != nil {
+				p.astroportPCLRefresher.enqueue(poolID, chainCosmWasmPool.ContractAddress)
+			}
+		}
+
 		if cosmWasmPoolModel != nil && cosmWasmPoolModel.Data.Orderbook != nil && cosmWasmPoolModel.IsOrderbook() {
 			baseDenom := cosmWasmPoolModel.Data.Orderbook.BaseDenom
 			quoteDenom := cosmWasmPoolModel.Data.Orderbook.QuoteDenom
@@ -94,6 +94,16 @@ func (s *PoolsUsecaseTestSuite) TestGetRoutesFromCandidates() {
 		ID:             defaultPoolID,
 	}
 
+	// Setup the pool for the second hop of the multi-hop route
+	secondHopPoolID := s.PrepareBalancerPoolWithCoins(sdk.NewCoin(denomTwo, defaultAmt0), sdk.NewCoin(denomThree, defaultAmt1))
+	secondHopBalancerPool, err := s.App.GAMMKeeper.GetPool(s.Ctx, secondHopPoolID)
+	s.Require().NoError(err)
+
+	secondHopPool := &mocks.MockRoutablePool{
+		ChainPoolModel: secondHopBalancerPool,
+		ID:             defaultPoolID + 1,
+	}
+
 	validPools := []sqsdomain.PoolI{
 		defaultPool,
 	}
@@ -130,6 +140,34 @@ func (s *PoolsUsecaseTestSuite) TestGetRoutesFromCandidates() {
 		}: defaultTakerFee,
 	}
 
+	var (
+		secondHopTakerFee = osmomath.MustNewDecFromStr("0.003")
+		// The taker fee of the route token in and the second hop token out must not be charged to the second hop.
+		routeTakerFee = osmomath.MustNewDecFromStr("0.005")
+	)
+
+	multiHopCandidateRoutes := sqsdomain.CandidateRoutes{
+		Routes: []sqsdomain.CandidateRoute{
+			{
+				Pools: []sqsdomain.CandidatePool{
+					{
+						ID:            defaultPoolID,
+						TokenOutDenom: denomTwo,
+					},
+					{
+						ID:            defaultPoolID + 1,
+						TokenOutDenom: denomThree,
+					},
+				},
+			},
+		},
+	}
+
+	multiHopTakerFeeMap := sqsdomain.TakerFeeMap{}
+	multiHopTakerFeeMap.SetTakerFee(denomOne, denomTwo, defaultTakerFee)
+	multiHopTakerFeeMap.SetTakerFee(denomTwo, denomThree, secondHopTakerFee)
+	multiHopTakerFeeMap.SetTakerFee(denomOne, denomThree, routeTakerFee)
+
 	tests := []struct {
 		name string
 
@@ -215,9 +253,29 @@ func (s *PoolsUsecaseTestSuite) TestGetRoutesFromCandidates() {
 				},
 			},
 		},
+		{
+			name:  "valid conversion of single multi-hop route - taker fee keyed on the hop token in denom",
+			pools: []sqsdomain.PoolI{defaultPool, secondHopPool},
+
+			candidateRoutes: multiHopCandidateRoutes,
+			takerFeeMap:     multiHopTakerFeeMap,
+
+			tokenInDenom:  denomOne,
+			tokenOutDenom: denomThree,
+
+			expectedRoutes: []route.RouteImpl{
+				{
+					Pools: []domain.RoutablePool{
+						s.newRoutablePool(defaultPool, denomTwo, defaultTakerFee),
+						// The second hop is charged the taker fee of its own token in and token out
+						// rather than the one of the route token in and the hop token out.
+						s.newRoutablePool(secondHopPool, denomThree, secondHopTakerFee),
+					},
+				},
+			},
+		},
 
 		// TODO:
-		// Valid conversion of single multi-hop route
 		// Valid conversion of two routes where one is multi hop
 	}
 
@@ -494,6 +552,57 @@ func (s *PoolsUsecaseTestSuite) TestStorePools() {
 	s.Require().Error(err)
 }
 
+// Validates that storing the pools drops the memoised calc queries of the stored pools only.
+func (s *PoolsUsecaseTestSuite) TestStorePools_InvalidatesCalcQueryMemo() {
+	var (
+		updatedPool = &mocks.MockRoutablePool{
+			ChainPoolModel: &mocks.ChainPoolMock{
+				ID:   defaultPoolID,
+				Type: poolmanagertypes.CosmWasm,
+			},
+			ID: defaultPoolID,
+		}
+
+		otherPoolID = defaultPoolID + 1
+	)
+
+	routerRepo := routerrepo.New(&log.NoOpLogger{})
+	poolsUsecase, err := usecase.NewPoolsUsecase(&domain.PoolsConfig{
+		GeneralCosmWasmCalcQueryBudget: 1,
+	}, "node-uri-placeholder", routerRepo, domain.UnsetScalingFactorGetterCb, &log.NoOpLogger{})
+	s.Require().NoError(err)
+
+	calcQueryMemo := poolsUsecase.GetCalcQueryMemo()
+	s.Require().NotNil(calcQueryMemo)
+
+	calls := 0
+	queryCb := func(ctx context.Context, amount osmomath.Int) (osmomath.Int, error) {
+		calls++
+		return amount, nil
+	}
+
+	ctx := context.Background()
+	for _, poolID := range []uint64{defaultPoolID, otherPoolID} {
+		_, err := calcQueryMemo.CalcOutAmtGivenIn(ctx, poolID, denomOne, osmomath.NewInt(100), denomTwo, queryCb)
+		s.Require().NoError(err)
+	}
+	s.Require().Equal(2, calls)
+
+	// System under test
+	err = poolsUsecase.StorePools([]sqsdomain.PoolI{updatedPool})
+	s.Require().NoError(err)
+
+	// The stored pool is queried again.
+	_, err = calcQueryMemo.CalcOutAmtGivenIn(ctx, defaultPoolID, denomOne, osmomath.NewInt(100), denomTwo, queryCb)
+	s.Require().NoError(err)
+	s.Require().Equal(3, calls)
+
+	// The other pool is still memoised.
+	_, err = calcQueryMemo.CalcOutAmtGivenIn(ctx, otherPoolID, denomOne, osmomath.NewInt(100), denomTwo, queryCb)
+	s.Require().NoError(err)
+	s.Require().Equal(3, calls)
+}
+
 // This test validates that the canonical orderbook pool IDs are returned as intended
 // if they are correctly set. The correctness of setting them is ensured
 // by the StorePools and ProcessOrderbookPoolIDForBaseQuote tests.
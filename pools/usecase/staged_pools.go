@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	cosmwasmpoolmodel "github.com/osmosis-labs/osmosis/v25/x/cosmwasmpool/model"
+
+	"github.com/osmosis-labs/sqs/domain"
+	"github.com/osmosis-labs/sqs/domain/mvc"
+	"github.com/osmosis-labs/sqs/sqsdomain"
+)
+
+// stagedPools is the view of the pools stored in the pools usecase updated with the staged pools.
+// The canonical orderbook pools are determined as if the staged pools were stored.
+type stagedPools struct {
+	poolsUseCase *poolsUseCase
+
+	// pools are the staged pools by ID.
+	pools map[uint64]sqsdomain.PoolI
+	// canonicalOrderbookPoolIDs are the orderbook pools whose canonical status is changed by the staged pools.
+	// True if the pool becomes canonical, false if it is replaced by a staged pool with a higher liquidity capitalization.
+	canonicalOrderbookPoolIDs map[uint64]bool
+}
+
+var _ mvc.StagedPools = &stagedPools{}
+
+// StagePools implements mvc.PoolsUsecase.
+// The canonical orderbook pools are updated for the staged orderbook pools the same way as StorePools does.
+func (p *poolsUseCase) StagePools(pools []sqsdomain.PoolI) mvc.StagedPools {
+	staged := &stagedPools{
+		poolsUseCase:              p,
+		pools:                     make(map[uint64]sqsdomain.PoolI, len(pools)),
+		canonicalOrderbookPoolIDs: map[uint64]bool{},
+	}
+
+	// The canonical orderbook entries updated by the staged pools by base and quote denom key.
+	updatedEntries := map[string]orderBookEntry{}
+
+	for _, pool := range pools {
+		poolID := pool.GetId()
+		staged.pools[poolID] = pool
+
+		cosmWasmPoolModel := pool.GetSQSPoolModel().CosmWasmPoolModel
+		if cosmWasmPoolModel == nil || cosmWasmPoolModel.Data.Orderbook == nil || !cosmWasmPoolModel.IsOrderbook() {
+			continue
+		}
+
+		chainCosmWasmPool, ok := pool.GetUnderlyingPool().(*cosmwasmpoolmodel.CosmWasmPool)
+		if !ok || chainCosmWasmPool == nil {
+			continue
+		}
+
+		baseQuoteKey := formatBaseQuoteDenom(cosmWasmPoolModel.Data.Orderbook.BaseDenom, cosmWasmPoolModel.Data.Orderbook.QuoteDenom)
+		poolLiquidityCapitalization := pool.GetLiquidityCap()
+
+		topLiquidityOrderBookEntry, found := updatedEntries[baseQuoteKey]
+		if !found {
+			topLiquidityOrderBook, ok := p.canonicalOrderBookForBaseQuoteDenom.Load(baseQuoteKey)
+			if ok {
+				topLiquidityOrderBookEntry, found = topLiquidityOrderBook.(orderBookEntry)
+				if !found {
+					// StorePools skips the pool on the same failure.
+					continue
+				}
+			}
+		}
+
+		if found {
+			if poolLiquidityCapitalization.LTE(topLiquidityOrderBookEntry.LiquidityCap) {
+				continue
+			}
+
+			staged.canonicalOrderbookPoolIDs[topLiquidityOrderBookEntry.PoolID] = false
+		}
+
+		updatedEntries[baseQuoteKey] = orderBookEntry{
+			PoolID:          poolID,
+			LiquidityCap:    poolLiquidityCapitalization,
+			ContractAddress: chainCosmWasmPool.ContractAddress,
+		}
+		staged.canonicalOrderbookPoolIDs[poolID] = true
+	}
+
+	return staged
+}
+
+// GetPools implements mvc.StagedPools.
+func (s *stagedPools) GetPools(opts ...domain.PoolsOption) ([]sqsdomain.PoolI, error) {
+	options := domain.PoolsOptions{
+		MinPoolLiquidityCap:  0,
+		PoolIDFilter:         []uint64{},
+		WithMarketIncentives: false,
+		HadEmptyFilter:       false,
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	if options.HadEmptyFilter {
+		return nil, nil
+	}
+
+	poolsToFilter := make([]sqsdomain.PoolI, 0, len(options.PoolIDFilter))
+	if len(options.PoolIDFilter) > 0 {
+		for _, poolID := range options.PoolIDFilter {
+			pool, ok := s.pools[poolID]
+			if !ok {
+				var err error
+				pool, err = s.poolsUseCase.GetPool(poolID)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			poolsToFilter = append(poolsToFilter, pool)
+		}
+	} else {
+		allPools, err := s.GetAllPools()
+		if err != nil {
+			return nil, err
+		}
+
+		poolsToFilter = allPools
+	}
+
+	pools := make([]sqsdomain.PoolI, 0, len(poolsToFilter))
+	for _, pool := range poolsToFilter {
+		pools = s.poolsUseCase.retainPoolIfMatchesOptions(pools, pool, options)
+	}
+
+	return pools, nil
+}
+
+// GetAllPools implements mvc.StagedPools.
+func (s *stagedPools) GetAllPools() ([]sqsdomain.PoolI, error) {
+	storedPools, err := s.poolsUseCase.GetAllPools()
+	if err != nil {
+		return nil, err
+	}
+
+	allPools := make([]sqsdomain.PoolI, 0, len(storedPools)+len(s.pools))
+	for _, pool := range storedPools {
+		if _, ok := s.pools[pool.GetId()]; ok {
+			continue
+		}
+
+		allPools = append(allPools, pool)
+	}
+
+	for _, pool := range s.pools {
+		allPools = append(allPools, pool)
+	}
+
+	return allPools, nil
+}
+
+// IsCanonicalOrderbookPool implements mvc.StagedPools.
+func (s *stagedPools) IsCanonicalOrderbookPool(poolID uint64) bool {
+	if isCanonical, ok := s.canonicalOrderbookPoolIDs[poolID]; ok {
+		return isCanonical
+	}
+
+	return s.poolsUseCase.IsCanonicalOrderbookPool(poolID)
+}
@@ -0,0 +1,106 @@
+package usecase_test
+
+import (
+	"sort"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	cosmwasmpoolmodel "github.com/osmosis-labs/osmosis/v25/x/cosmwasmpool/model"
+	poolmanagertypes "github.com/osmosis-labs/osmosis/v25/x/poolmanager/types"
+
+	"github.com/osmosis-labs/sqs/domain"
+	"github.com/osmosis-labs/sqs/domain/mocks"
+	"github.com/osmosis-labs/sqs/sqsdomain"
+	"github.com/osmosis-labs/sqs/sqsdomain/cosmwasmpool"
+)
+
+// newOrderbookPool returns an orderbook pool over denomOne and denomTwo with the given ID and liquidity capitalization.
+func newOrderbookPool(poolID uint64, liquidityCap osmomath.Int) *mocks.MockRoutablePool {
+	return &mocks.MockRoutablePool{
+		ChainPoolModel: &cosmwasmpoolmodel.CosmWasmPool{
+			PoolId:          poolID,
+			ContractAddress: "orderbook-address",
+		},
+		ID: poolID,
+		CosmWasmPoolModel: &cosmwasmpool.CosmWasmPoolModel{
+			ContractInfo: cosmwasmpool.ContractInfo{
+				Contract: cosmwasmpool.ORDERBOOK_CONTRACT_NAME,
+				Version:  cosmwasmpool.ORDERBOOK_MIN_CONTRACT_VERSION,
+			},
+			Data: cosmwasmpool.CosmWasmPoolData{
+				Orderbook: &cosmwasmpool.OrderbookData{
+					BaseDenom:  denomOne,
+					QuoteDenom: denomTwo,
+				},
+			},
+		},
+		PoolLiquidityCap: liquidityCap,
+	}
+}
+
+// newBalancerPool returns a balancer pool with the given ID and liquidity capitalization.
+func newBalancerPool(poolID uint64, liquidityCap osmomath.Int) *mocks.MockRoutablePool {
+	return &mocks.MockRoutablePool{
+		ChainPoolModel: &mocks.ChainPoolMock{
+			ID:   poolID,
+			Type: poolmanagertypes.Balancer,
+		},
+		ID:               poolID,
+		PoolLiquidityCap: liquidityCap,
+	}
+}
+
+// Validates that the staged pools are viewed as if they were stored, including the canonical orderbook pools,
+// while the stored pools are left unchanged until the staged pools are stored.
+func (s *PoolsUsecaseTestSuite) TestStagePools() {
+	var (
+		storedBalancerPool  = newBalancerPool(defaultPoolID, osmomath.NewInt(10))
+		storedOrderbookPool = newOrderbookPool(defaultPoolID+1, osmomath.NewInt(100))
+
+		updatedBalancerPool = newBalancerPool(defaultPoolID, osmomath.NewInt(20))
+		newOrderbook        = newOrderbookPool(defaultPoolID+2, osmomath.NewInt(200))
+		newBalancer         = newBalancerPool(defaultPoolID+3, osmomath.NewInt(30))
+
+		blockPools = []sqsdomain.PoolI{updatedBalancerPool, newOrderbook, newBalancer}
+	)
+
+	poolsUsecase := s.newDefaultPoolsUseCase()
+	err := poolsUsecase.StorePools([]sqsdomain.PoolI{storedBalancerPool, storedOrderbookPool})
+	s.Require().NoError(err)
+
+	// System under test
+	stagedPools := poolsUsecase.StagePools(blockPools)
+
+	// The staged pools replace the stored ones with the same ID.
+	actualPools, err := stagedPools.GetPools(domain.WithPoolIDFilter([]uint64{defaultPoolID, defaultPoolID + 1, defaultPoolID + 2, defaultPoolID + 3}))
+	s.Require().NoError(err)
+	s.Require().Equal([]sqsdomain.PoolI{updatedBalancerPool, storedOrderbookPool, newOrderbook, newBalancer}, actualPools)
+
+	allPools, err := stagedPools.GetAllPools()
+	s.Require().NoError(err)
+	sort.Slice(allPools, func(i, j int) bool {
+		return allPools[i].GetId() < allPools[j].GetId()
+	})
+	s.Require().Equal(actualPools, allPools)
+
+	// The staged orderbook with the higher liquidity capitalization replaces the stored canonical orderbook.
+	s.Require().False(stagedPools.IsCanonicalOrderbookPool(storedOrderbookPool.ID))
+	s.Require().True(stagedPools.IsCanonicalOrderbookPool(newOrderbook.ID))
+
+	// The stored pools are unchanged.
+	actualPool, err := poolsUsecase.GetPool(defaultPoolID)
+	s.Require().NoError(err)
+	s.Require().Equal(storedBalancerPool, actualPool)
+
+	_, err = poolsUsecase.GetPool(newOrderbook.ID)
+	s.Require().ErrorIs(err, domain.PoolNotFoundError{PoolID: newOrderbook.ID})
+
+	s.Require().True(poolsUsecase.IsCanonicalOrderbookPool(storedOrderbookPool.ID))
+	s.Require().False(poolsUsecase.IsCanonicalOrderbookPool(newOrderbook.ID))
+
+	// Storing the staged pools yields the staged view.
+	err = poolsUsecase.StorePools(blockPools)
+	s.Require().NoError(err)
+
+	s.Require().False(poolsUsecase.IsCanonicalOrderbookPool(storedOrderbookPool.ID))
+	s.Require().True(poolsUsecase.IsCanonicalOrderbookPool(newOrderbook.ID))
+}
@@ -1,8 +1,14 @@
 package http
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"sync"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"go.opentelemetry.io/otel/attribute"
@@ -13,18 +19,31 @@ import (
 	"github.com/osmosis-labs/osmosis/osmomath"
 	"github.com/osmosis-labs/sqs/domain"
 	"github.com/osmosis-labs/sqs/domain/mvc"
+	"github.com/osmosis-labs/sqs/domain/swaptx"
 	"github.com/osmosis-labs/sqs/log"
 	"github.com/osmosis-labs/sqs/router/types"
 )
 
 // RouterHandler  represent the httphandler for the router
 type RouterHandler struct {
-	RUsecase mvc.RouterUsecase
-	TUsecase mvc.TokensUsecase
-	logger   log.Logger
+	RUsecase   mvc.RouterUsecase
+	TUsecase   mvc.TokensUsecase
+	StateGuard *domain.RouterStateGuard
+	TxBuilder  swaptx.TxBuilder
+	// QuoteStreamer is nil if quote streaming is disabled.
+	QuoteStreamer mvc.QuoteStreamer
+	// QuoteAuditor is nil if the quote audit is disabled.
+	QuoteAuditor mvc.QuoteAuditor
+	logger       log.Logger
 }
 
-const routerResource = "/router"
+const (
+	routerResource = "/router"
+
+	// quoteStreamKeepAliveInterval is the interval at which the keep-alive comments are written to the quote stream
+	// so that the idle connections are not closed by the proxies.
+	quoteStreamKeepAliveInterval = 15 * time.Second
+)
 
 var (
 	oneDec = osmomath.OneDec()
@@ -35,15 +54,28 @@ func formatRouterResource(resource string) string {
 }
 
 // NewRouterHandler will initialize the pools/ resources endpoint
-func NewRouterHandler(e *echo.Echo, us mvc.RouterUsecase, tu mvc.TokensUsecase, logger log.Logger) {
+func NewRouterHandler(e *echo.Echo, us mvc.RouterUsecase, tu mvc.TokensUsecase, stateGuard *domain.RouterStateGuard, txBuilder swaptx.TxBuilder, quoteStreamer mvc.QuoteStreamer, quoteAuditor mvc.QuoteAuditor, logger log.Logger) {
 	handler := &RouterHandler{
-		RUsecase: us,
-		TUsecase: tu,
-		logger:   logger,
+		RUsecase:      us,
+		TUsecase:      tu,
+		StateGuard:    stateGuard,
+		TxBuilder:     txBuilder,
+		QuoteStreamer: quoteStreamer,
+		QuoteAuditor:  quoteAuditor,
+		logger:        logger,
 	}
 	e.GET(formatRouterResource("/quote"), handler.GetOptimalQuote)
+	e.GET(formatRouterResource("/quote/:id"), handler.GetQuoteByID)
+	e.POST(formatRouterResource("/quotes"), handler.GetOptimalQuotes)
+	e.GET(formatRouterResource("/quote-tx"), handler.GetOptimalQuoteTx)
+	e.GET(formatRouterResource("/quote-stream"), handler.GetQuoteStream)
+	e.GET(formatRouterResource("/quote-audit"), handler.GetQuoteAudit)
 	e.GET(formatRouterResource("/routes"), handler.GetCandidateRoutes)
 	e.GET(formatRouterResource("/cached-routes"), handler.GetCachedCandidateRoutes)
+	e.GET(formatRouterResource("/max-amount-for-impact"), handler.GetMaxAmountForImpact)
+	e.GET(formatRouterResource("/depth"), handler.GetDepth)
+	e.GET(formatRouterResource("/cyclic-arbs"), handler.GetCyclicArbs)
+	e.GET(formatRouterResource("/basket-quote"), handler.GetBasketQuote)
 	e.GET(formatRouterResource("/spot-price-pool/:id"), handler.GetSpotPriceForPool)
 	e.GET(formatRouterResource("/custom-direct-quote"), handler.GetDirectCustomQuote)
 	e.GET(formatRouterResource("/taker-fee-pool/:id"), handler.GetTakerFee)
@@ -68,6 +100,18 @@ func NewRouterHandler(e *echo.Echo, us mvc.RouterUsecase, tu mvc.TokensUsecase,
 // @Param  singleRoute     query  bool    false  "Boolean flag indicating whether to return single routes (no splits). False (splits enabled) by default."
 // @Param  humanDenoms     query  bool    true "Boolean flag indicating whether the given denoms are human readable or not. Human denoms get converted to chain internally"
 // @Param  applyExponents  query  bool    false  "Boolean flag indicating whether to apply exponents to the spot price. False by default."
+// @Param  splitIncrements        query  int  false  "Number of increments the amount is divided into when computing splits. Higher values give finer splits at the cost of latency. Configured default if unset."
+// @Param  splitRefinementRounds  query  int  false  "Maximum number of refinement rounds around the best split. Configured default if unset."
+// @Param  excludePoolIDs    query  string  false  "Comma-separated list of pool IDs that the routes must not go through."                                      example(1,1135)
+// @Param  onlyPoolTypes     query  string  false  "Comma-separated list of pool types the routes are restricted to. One of balancer, stableswap, concentrated, transmuter, alloyed_transmuter, orderbook, cosmwasm."  example(balancer,concentrated)
+// @Param  excludeDenoms     query  string  false  "Comma-separated list of intermediary denoms that the routes must not go through."                          example(uion)
+// @Param  maxPoolsPerRoute  query  int     false  "Maximum number of pools in a route. Configured default if unset."
+// @Param  maxRoutes         query  int     false  "Maximum number of candidate routes. Configured default if unset."
+// @Param  minLiquidityCap   query  int     false  "Minimum liquidity capitalization of the pools in the routes. Configured default if unset."
+// @Param  candidateRouteAlgorithm  query  string  false  "Candidate route search algorithm. One of bfs, price-aware. Configured default if unset."  example(price-aware)
+// @Param  explain           query  bool    false  "Boolean flag indicating whether to return the description of how the quote was computed. If true, the quote and the description are returned as types.GetQuoteExplainResponse."
+// @Param  gasAware          query  bool    false  "Boolean flag indicating whether to rank and split the routes by the amount out net of the estimated gas cost. Only supported for the exact amount in swap method. If true, the gas estimate is returned with the quote."
+// @Param  computeDeadlineMs query  int     false  "Deadline for computing the quote in milliseconds. Once exceeded, the best quote found so far is returned with the partial flag set. Only supported for the exact amount in swap method. Configured default if unset."
 // @Success 200  {object}  domain.Quote  "The computed best route quote"
 // @Router /router/quote [get]
 func (a *RouterHandler) GetOptimalQuote(c echo.Context) (err error) {
@@ -89,11 +133,326 @@ func (a *RouterHandler) GetOptimalQuote(c echo.Context) (err error) {
 		return c.JSON(http.StatusBadRequest, domain.ResponseError{Message: err.Error()})
 	}
 
-	// Validate the request
+	tokenIn, tokenOutDenom, err := a.validateQuoteRequest(c, &req)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, domain.ResponseError{Message: err.Error()})
+	}
+
+	var explain *domain.QuoteExplain
+	if req.Explain {
+		explain = &domain.QuoteExplain{}
+	}
+
+	// Compute and stamp the quote over a consistent view of the router state.
+	quoteCtx, unlock := a.rLockStateGuard(ctx)
+	quote, err := a.getOptimalQuote(quoteCtx, &req, *tokenIn, tokenOutDenom, explain)
+	err = stateGuardTimeoutError(quoteCtx, err)
+	unlock()
+	if err != nil {
+		return c.JSON(domain.GetStatusCode(err), domain.ResponseError{Message: err.Error()})
+	}
+
+	span.SetAttributes(attribute.Stringer("token_out", quote.GetAmountOut()))
+	span.SetAttributes(attribute.Stringer("price_impact", quote.GetPriceImpact()))
+
+	if a.QuoteAuditor != nil && req.SwapMethod() == domain.TokenSwapMethodExactIn {
+		a.QuoteAuditor.SampleQuote(quote)
+	}
+
+	if explain != nil {
+		return c.JSON(http.StatusOK, types.GetQuoteExplainResponse{
+			Quote:   quote,
+			Explain: explain,
+		})
+	}
+
+	return c.JSON(http.StatusOK, quote)
+}
+
+// @Summary Optimal Quote Transaction
+// @Description Returns the best quote together with the unsigned transaction executing it on behalf of the sender.
+// @Description
+// @Description Accepts the same parameters as the `/router/quote` endpoint as well as the sender and the slippage tolerance.
+// @Description For exact amount in swap method, the min amount out is the quoted amount out adjusted by the slippage tolerance.
+// @Description For exact amount out swap method, the max amount in is the quoted amount in adjusted by the slippage tolerance.
+// @Description Routes through pools that do not support the exact amount out swap API are executed as exact amount in
+// @Description with the token in raised by the slippage tolerance and the exact token out as the min amount out.
+// @Description
+// @Description The gas limit of the transaction is estimated by simulating it against the chain.
+// @Description The fee and the signatures are left for the sender to set.
+// @ID get-route-quote-tx
+// @Produce  json
+// @Param  tokenIn            query  string  false  "String representation of the sdk.Coin denoting the input token for the exact amount in swap method."     example(1000000uosmo)
+// @Param  tokenOutDenom      query  string  false  "String representing the denomination of the output token for the exact amount in swap method."           example(uion)
+// @Param  tokenOut           query  string  false  "String representation of the sdk.Coin denoting the output token for the exact amount out swap method."   example(2353uion)
+// @Param  tokenInDenom       query  string  false  "String representing the denomination of the input token for the exact amount out swap method."           example(uosmo)
+// @Param  sender             query  string  true   "Bech32 address of the sender of the swap with the account address prefix of the chain."
+// @Param  slippageTolerance  query  string  true   "Slippage tolerance as a decimal in range [0, 1)."                                                        example(0.01)
+// @Param  singleRoute        query  bool    false  "Boolean flag indicating whether to return single routes (no splits). False (splits enabled) by default."
+// @Param  humanDenoms        query  bool    true   "Boolean flag indicating whether the given denoms are human readable or not. Human denoms get converted to chain internally"
+// @Success 200  {object}  types.GetQuoteTxResponse  "The computed best route quote and the unsigned transaction"
+// @Router /router/quote-tx [get]
+func (a *RouterHandler) GetOptimalQuoteTx(c echo.Context) (err error) {
+	ctx := c.Request().Context()
+
+	var req types.GetQuoteTxRequest
+	if err := UnmarshalRequest(c, &req); err != nil {
+		return c.JSON(http.StatusBadRequest, domain.ResponseError{Message: err.Error()})
+	}
+
+	if err := req.ValidateTxParams(); err != nil {
+		return c.JSON(http.StatusBadRequest, domain.ResponseError{Message: err.Error()})
+	}
+
+	tokenIn, tokenOutDenom, err := a.validateQuoteRequest(c, &req.GetQuoteRequest)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, domain.ResponseError{Message: err.Error()})
+	}
+
+	// Compute and stamp the quote over a consistent view of the router state.
+	// The guard is released before simulating the transaction against the chain.
+	quoteCtx, unlock := a.rLockStateGuard(ctx)
+	quote, err := a.getOptimalQuote(quoteCtx, &req.GetQuoteRequest, *tokenIn, tokenOutDenom, nil)
+	err = stateGuardTimeoutError(quoteCtx, err)
+	unlock()
+	if err != nil {
+		return c.JSON(domain.GetStatusCode(err), domain.ResponseError{Message: err.Error()})
+	}
+
+	swapMsg, err := swaptx.NewSwapMsgFromQuote(req.Sender, quote, req.SwapMethod(), req.SlippageTolerance)
+	if err != nil {
+		return c.JSON(domain.GetStatusCode(err), domain.ResponseError{Message: err.Error()})
+	}
+
+	unsignedTx, err := a.TxBuilder.BuildUnsignedTx(ctx, req.Sender, swapMsg)
+	if err != nil {
+		return c.JSON(domain.GetStatusCode(err), domain.ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, types.GetQuoteTxResponse{
+		Quote:      quote,
+		UnsignedTx: unsignedTx,
+	})
+}
+
+// @Summary Quote Stream
+// @Description Streams the best exact amount in quotes for the given pairs as server-sent events.
+// @Description
+// @Description The i-th `tokenIn` is quoted against the i-th `tokenOutDenom`.
+// @Description The initial quotes are pushed upon subscription. Afterwards, a quote is recomputed and pushed
+// @Description at the end of every block that updates any of the pools in its route.
+// @Description Each update is sent as a `quote` event with the JSON encoded domain.QuoteStreamUpdate as data.
+// @Description If the quote fails to compute, the error is set instead of the quote.
+// @Description
+// @Description The updates are dropped if the subscriber falls behind.
+// @Description Returns 429 if the maximum number of subscriptions is reached.
+// @ID get-route-quote-stream
+// @Produce  text/event-stream
+// @Param  tokenIn        query  string  true  "Comma-separated list of sdk.Coin string representations denoting the input tokens."  example(1000000uosmo,1000000uion)
+// @Param  tokenOutDenom  query  string  true  "Comma-separated list of the output token denominations."                             example(uion,uosmo)
+// @Param  humanDenoms    query  bool    true  "Boolean flag indicating whether the given denoms are human readable or not. Human denoms get converted to chain internally"
+// @Success 200  {object}  domain.QuoteStreamUpdate  "The stream of quote updates"
+// @Router /router/quote-stream [get]
+func (a *RouterHandler) GetQuoteStream(c echo.Context) (err error) {
+	ctx := c.Request().Context()
+
+	if a.QuoteStreamer == nil {
+		return c.JSON(domain.GetStatusCode(domain.ErrQuoteStreamDisabled), domain.ResponseError{Message: domain.ErrQuoteStreamDisabled.Error()})
+	}
+
+	var req types.GetQuoteStreamRequest
+	if err := UnmarshalRequest(c, &req); err != nil {
+		return c.JSON(http.StatusBadRequest, domain.ResponseError{Message: err.Error()})
+	}
+
 	if err := req.Validate(); err != nil {
 		return c.JSON(http.StatusBadRequest, domain.ResponseError{Message: err.Error()})
 	}
 
+	denoms := make([]string, 0, 2*len(req.TokenIn))
+	for i := range req.TokenIn {
+		denoms = append(denoms, req.TokenIn[i].Denom, req.TokenOutDenom[i])
+	}
+
+	chainDenoms, err := mvc.ValidateChainDenomsQueryParam(c, a.TUsecase, denoms)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, domain.ResponseError{Message: err.Error()})
+	}
+
+	// Update the denoms in case they were translated from human to chain.
+	for i := range req.TokenIn {
+		req.TokenIn[i].Denom = chainDenoms[2*i]
+		req.TokenOutDenom[i] = chainDenoms[2*i+1]
+	}
+
+	updates, unsubscribe, err := a.QuoteStreamer.Subscribe(ctx, req.QuoteStreamRequests())
+	if err != nil {
+		return c.JSON(domain.GetStatusCode(err), domain.ResponseError{Message: err.Error()})
+	}
+	defer unsubscribe()
+
+	resp := c.Response()
+	resp.Header().Set(echo.HeaderContentType, "text/event-stream")
+	resp.Header().Set("Cache-Control", "no-cache")
+	resp.Header().Set("Connection", "keep-alive")
+	resp.WriteHeader(http.StatusOK)
+	resp.Flush()
+
+	keepAliveTicker := time.NewTicker(quoteStreamKeepAliveInterval)
+	defer keepAliveTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case update, ok := <-updates:
+			if !ok {
+				return nil
+			}
+
+			data, err := json.Marshal(update)
+			if err != nil {
+				return err
+			}
+
+			if _, err := fmt.Fprintf(resp, "event: quote\ndata: %s\n\n", data); err != nil {
+				return err
+			}
+			resp.Flush()
+		case <-keepAliveTicker.C:
+			if _, err := fmt.Fprint(resp, ": keep-alive\n\n"); err != nil {
+				return err
+			}
+			resp.Flush()
+		}
+	}
+}
+
+// @Summary Batch of Optimal Quotes
+// @Description Returns the best quotes it can compute for the given batch of exact in and exact out quote requests.
+// @Description
+// @Description Each item of the `quotes` array accepts the same parameters as the `/router/quote` endpoint.
+// @Description The quotes are computed concurrently over the same router state.
+// @Description The quotes sharing a pair of denoms also share the candidate route computation.
+// @Description The results are returned in the order of the request with either the quote or the error set.
+// @ID get-route-quotes
+// @Accept  json
+// @Produce  json
+// @Param  request      body   types.GetQuotesRequest  true   "Batch of quote requests."
+// @Param  humanDenoms  query  bool                    true   "Boolean flag indicating whether the given denoms are human readable or not. Human denoms get converted to chain internally"
+// @Success 200  {object}  types.GetQuotesResponse  "The computed best route quotes"
+// @Router /router/quotes [post]
+func (a *RouterHandler) GetOptimalQuotes(c echo.Context) (err error) {
+	ctx := c.Request().Context()
+
+	var req types.GetQuotesRequest
+	if err := UnmarshalRequest(c, &req); err != nil {
+		return c.JSON(http.StatusBadRequest, domain.ResponseError{Message: err.Error()})
+	}
+
+	if err := req.Validate(); err != nil {
+		return c.JSON(http.StatusBadRequest, domain.ResponseError{Message: err.Error()})
+	}
+
+	type batchQuoteItem struct {
+		req           *types.GetQuoteRequest
+		tokenIn       *sdk.Coin
+		tokenOutDenom string
+	}
+
+	var (
+		results = make([]types.QuoteResult, len(req.Quotes))
+		items   = make([]batchQuoteItem, len(req.Quotes))
+
+		// Indexes of the items grouped by the pair of denoms.
+		pairItemIndexes = make(map[string][]int)
+	)
+
+	for i, item := range req.Quotes {
+		quoteReq, err := item.ToGetQuoteRequest()
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+
+		tokenIn, tokenOutDenom, err := a.validateQuoteRequest(c, quoteReq)
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+
+		items[i] = batchQuoteItem{req: quoteReq, tokenIn: tokenIn, tokenOutDenom: tokenOutDenom}
+
+		// Note that the candidate routes for exact amount out are searched from the token out.
+		// As a result, it is the given token that determines the candidate routes for both swap methods.
+		pairKey := tokenIn.Denom + "/" + tokenOutDenom
+		pairItemIndexes[pairKey] = append(pairItemIndexes[pairKey], i)
+	}
+
+	// Prevent the router state from being updated by ingest while computing the quotes.
+	ctx, unlock := a.rLockStateGuard(ctx)
+	defer unlock()
+
+	// Bounds the number of quotes computed at once across the pairs.
+	quoteSemaphore := make(chan struct{}, types.MaxBatchQuoteConcurrency)
+
+	computeQuoteCb := func(i int) {
+		quoteSemaphore <- struct{}{}
+		defer func() { <-quoteSemaphore }()
+
+		// Skip the quotes that have not started before the state guard timeout.
+		if ctx.Err() != nil {
+			results[i].Error = context.Cause(ctx).Error()
+			return
+		}
+
+		quote, err := a.getOptimalQuote(ctx, items[i].req, *items[i].tokenIn, items[i].tokenOutDenom, nil)
+		if err := stateGuardTimeoutError(ctx, err); err != nil {
+			results[i].Error = err.Error()
+			return
+		}
+
+		results[i].Quote = quote
+	}
+
+	wg := sync.WaitGroup{}
+	for _, itemIndexes := range pairItemIndexes {
+		wg.Add(1)
+		go func(itemIndexes []int) {
+			defer wg.Done()
+
+			// The first quote of the pair populates the candidate route cache
+			// that is then reused by the rest of the quotes for the same pair.
+			computeQuoteCb(itemIndexes[0])
+
+			pairWg := sync.WaitGroup{}
+			for _, i := range itemIndexes[1:] {
+				pairWg.Add(1)
+				go func(i int) {
+					defer pairWg.Done()
+					computeQuoteCb(i)
+				}(i)
+			}
+			pairWg.Wait()
+		}(itemIndexes)
+	}
+	wg.Wait()
+
+	return c.JSON(http.StatusOK, types.GetQuotesResponse{Quotes: results})
+}
+
+// validateQuoteRequest validates the quote request and translates the denoms from human to chain if requested.
+// Returns the given token and the denom of the other token according to the swap method.
+// That is, token in and token out denom for the exact amount in and token out and token in denom otherwise.
+func (a *RouterHandler) validateQuoteRequest(c echo.Context, req *types.GetQuoteRequest) (*sdk.Coin, string, error) {
+	if err := req.Validate(); err != nil {
+		return nil, "", err
+	}
+
+	if req.GasAware && !a.RUsecase.GetConfig().GasCostModel.Enabled {
+		return nil, "", types.ErrGasAwareNotEnabled
+	}
+
 	var (
 		tokenIn       *sdk.Coin
 		tokenOutDenom string
@@ -105,29 +464,45 @@ func (a *RouterHandler) GetOptimalQuote(c echo.Context) (err error) {
 		tokenIn, tokenOutDenom = req.TokenOut, req.TokenInDenom
 	}
 
-	chainDenoms, err := mvc.ValidateChainDenomsQueryParam(c, a.TUsecase, []string{tokenIn.Denom, tokenOutDenom})
+	chainDenoms, err := mvc.ValidateChainDenomsQueryParam(c, a.TUsecase, append([]string{tokenIn.Denom, tokenOutDenom}, req.ExcludeDenoms...))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, domain.ResponseError{Message: err.Error()})
+		return nil, "", err
 	}
 
 	// Update coins token in denom it case it was translated from human to chain.
 	tokenIn.Denom = chainDenoms[0]
 	tokenOutDenom = chainDenoms[1]
+	req.ExcludeDenoms = chainDenoms[2:]
 
-	var routerOpts []domain.RouterOption
-	if req.SingleRoute {
-		routerOpts = append(routerOpts, domain.WithMaxSplitRoutes(domain.DisableSplitRoutes))
+	return tokenIn, tokenOutDenom, nil
+}
+
+// getOptimalQuote computes the optimal quote for the validated request according to its swap method
+// and prepares the result for the response. If explain is non-nil, it is populated with the description
+// of how the quote was computed.
+// CONTRACT: the router state guard is read-locked by the caller.
+func (a *RouterHandler) getOptimalQuote(ctx context.Context, req *types.GetQuoteRequest, tokenIn sdk.Coin, tokenOutDenom string, explain *domain.QuoteExplain) (quote domain.Quote, err error) {
+	routerOpts := req.RouterOptions()
+	if explain != nil {
+		routerOpts = append(routerOpts, domain.WithQuoteExplain(explain))
+	}
+
+	if req.GasAware {
+		gasPriceInTokenOut, err := a.getGasPriceInTokenOut(ctx, tokenOutDenom)
+		if err != nil {
+			return nil, err
+		}
+		routerOpts = append(routerOpts, domain.WithGasAwareRanking(gasPriceInTokenOut))
 	}
 
-	var quote domain.Quote
 	if req.SwapMethod() == domain.TokenSwapMethodExactIn {
-		quote, err = a.RUsecase.GetOptimalQuote(ctx, *tokenIn, tokenOutDenom, routerOpts...)
+		quote, err = a.RUsecase.GetOptimalQuote(ctx, tokenIn, tokenOutDenom, routerOpts...)
 	} else {
-		quote, err = a.RUsecase.GetOptimalQuoteInGivenOut(ctx, *tokenIn, tokenOutDenom, routerOpts...)
+		quote, err = a.RUsecase.GetOptimalQuoteInGivenOut(ctx, tokenIn, tokenOutDenom, routerOpts...)
 	}
 
 	if err != nil {
-		return c.JSON(domain.GetStatusCode(err), domain.ResponseError{Message: err.Error()})
+		return nil, err
 	}
 
 	scalingFactor := oneDec
@@ -135,15 +510,116 @@ func (a *RouterHandler) GetOptimalQuote(c echo.Context) (err error) {
 		scalingFactor = a.getSpotPriceScalingFactor(tokenIn.Denom, tokenOutDenom)
 	}
 
-	_, _, err = quote.PrepareResult(ctx, scalingFactor, a.logger)
+	if _, _, err := quote.PrepareResult(ctx, scalingFactor, a.logger); err != nil {
+		return nil, err
+	}
+
+	if err := a.setQuoteID(quote, req.SwapMethod()); err != nil {
+		return nil, err
+	}
+
+	return quote, nil
+}
+
+// setQuoteID stamps the prepared quote with the height of the router state and the quote ID.
+// CONTRACT: the router state guard is read-locked by the caller for both the computation of the quote and the stamping
+// so that the height is the one of the state the quote was computed against.
+func (a *RouterHandler) setQuoteID(quote domain.Quote, swapMethod domain.TokenSwapMethod) error {
+	height := a.StateGuard.GetHeight()
+
+	spec, err := domain.NewQuoteSpec(quote, swapMethod, height)
+	if err != nil {
+		return err
+	}
+
+	quoteID, err := spec.ID()
 	if err != nil {
+		return err
+	}
+
+	quote.SetQuoteID(quoteID, height)
+
+	return nil
+}
+
+// rLockStateGuard read-locks the router state guard so that the request computes over a consistent view of the router state.
+// Returns the context of the computation under the guard and the function releasing the guard.
+// The context is bounded by router.state-guard-timeout-ms with domain.ErrRouterStateGuardTimeout as the cause
+// so that no request holds the guard and stalls the ingest for longer. The cause is distinct from the quote compute deadline
+// so that the quotes cut by the timeout are not mistaken for partial quotes. See stateGuardTimeoutError.
+func (a *RouterHandler) rLockStateGuard(ctx context.Context) (context.Context, func()) {
+	a.StateGuard.RLock()
+
+	timeoutMs := a.RUsecase.GetConfig().StateGuardTimeoutMs
+	if timeoutMs <= 0 {
+		return ctx, a.StateGuard.RUnlock
+	}
+
+	ctx, cancel := context.WithTimeoutCause(ctx, time.Duration(timeoutMs)*time.Millisecond, domain.ErrRouterStateGuardTimeout)
+
+	return ctx, func() {
+		cancel()
+		a.StateGuard.RUnlock()
+	}
+}
+
+// stateGuardTimeoutError returns domain.ErrRouterStateGuardTimeout if the state guard timeout of the given context
+// was exceeded during the computation. Otherwise, returns the given error.
+// The computations cut by the timeout may return the results computed so far without an error,
+// so the requests fail instead of returning them.
+// CONTRACT: called before releasing the guard.
+func stateGuardTimeoutError(ctx context.Context, err error) error {
+	if errors.Is(context.Cause(ctx), domain.ErrRouterStateGuardTimeout) {
+		return domain.ErrRouterStateGuardTimeout
+	}
+
+	return err
+}
+
+// @Summary Re-evaluate Quote
+// @Description Re-evaluates the quote with the given ID over the same routes and split against the current state.
+// @Description
+// @Description The quote ID is returned by the quote endpoints together with the height of the state the quote was computed against.
+// @Description It encodes the routes and the split of the quote so that no quote is stored on the server.
+// @Description The routes are not searched again. If any of them fails to compute against the current state, an error is returned.
+// @Description
+// @Description The drift is the re-evaluated amount minus the quoted amount. That is, the amount out for the exact amount in
+// @Description and the amount in for the exact amount out swap method. Clients may use it to decide whether to re-quote before signing.
+// @ID get-route-quote-by-id
+// @Produce  json
+// @Param  id  path  string  true  "The quote ID."
+// @Success 200  {object}  types.GetQuoteByIDResponse  "The re-evaluated quote with the drift from the quoted amount"
+// @Router /router/quote/{id} [get]
+func (a *RouterHandler) GetQuoteByID(c echo.Context) (err error) {
+	ctx := c.Request().Context()
+
+	var req types.GetQuoteByIDRequest
+	if err := UnmarshalRequest(c, &req); err != nil {
+		return c.JSON(http.StatusBadRequest, domain.ResponseError{Message: err.Error()})
+	}
+
+	if err := req.Validate(); err != nil {
+		return c.JSON(http.StatusBadRequest, domain.ResponseError{Message: err.Error()})
+	}
+
+	// Prevent the router state from being updated by ingest while re-evaluating the routes of the split.
+	ctx, unlock := a.rLockStateGuard(ctx)
+	defer unlock()
+
+	quote, err := a.RUsecase.GetQuoteFromSpec(ctx, req.Spec)
+	if err := stateGuardTimeoutError(ctx, err); err != nil {
 		return c.JSON(domain.GetStatusCode(err), domain.ResponseError{Message: err.Error()})
 	}
 
-	span.SetAttributes(attribute.Stringer("token_out", quote.GetAmountOut()))
-	span.SetAttributes(attribute.Stringer("price_impact", quote.GetPriceImpact()))
+	if _, _, err := quote.PrepareResult(ctx, oneDec, a.logger); err != nil {
+		return c.JSON(domain.GetStatusCode(err), domain.ResponseError{Message: err.Error()})
+	}
 
-	return c.JSON(http.StatusOK, quote)
+	if err := a.setQuoteID(quote, req.Spec.SwapMethod); err != nil {
+		return c.JSON(domain.GetStatusCode(err), domain.ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, types.NewGetQuoteByIDResponse(&req, quote))
 }
 
 // @Summary Compute the quote for the given poolID
@@ -209,6 +685,10 @@ func (a *RouterHandler) GetDirectCustomQuote(c echo.Context) (err error) {
 	tokenIn.Denom = chainDenoms[0]
 	tokenOutDenom = chainDenoms[1:]
 
+	// Prevent the router state from being updated by ingest while computing and stamping the quote.
+	ctx, unlock := a.rLockStateGuard(ctx)
+	defer unlock()
+
 	// Get the quote based on the swap method.
 	var quote domain.Quote
 	if req.SwapMethod() == domain.TokenSwapMethodExactIn {
@@ -216,7 +696,7 @@ func (a *RouterHandler) GetDirectCustomQuote(c echo.Context) (err error) {
 	} else {
 		quote, err = a.RUsecase.GetCustomDirectQuoteMultiPoolInGivenOut(ctx, *tokenIn, tokenOutDenom, req.PoolID)
 	}
-	if err != nil {
+	if err := stateGuardTimeoutError(ctx, err); err != nil {
 		return c.JSON(domain.GetStatusCode(err), domain.ResponseError{Message: err.Error()})
 	}
 
@@ -230,6 +710,10 @@ func (a *RouterHandler) GetDirectCustomQuote(c echo.Context) (err error) {
 		return c.JSON(domain.GetStatusCode(err), domain.ResponseError{Message: err.Error()})
 	}
 
+	if err := a.setQuoteID(quote, req.SwapMethod()); err != nil {
+		return c.JSON(domain.GetStatusCode(err), domain.ResponseError{Message: err.Error()})
+	}
+
 	return c.JSON(http.StatusOK, quote)
 }
 
@@ -270,6 +754,231 @@ func (a *RouterHandler) GetCandidateRoutes(c echo.Context) error {
 	return nil
 }
 
+// @Summary Max Amount Within Price Impact
+// @Description Returns the largest amount of the given token that can be swapped with at most the given price impact
+// @Description together with its optimal quote and the achieved price impact.
+// @Description
+// @Description For the exact amount in swap method, the search is over the amount of the token in.
+// @Description For the exact amount out swap method, the search is over the amount of the token out.
+// @Description The quotes are computed the same way as by `/router/quote`, splits included. The amount is found within 0.1%.
+// @ID get-router-max-amount-for-impact
+// @Produce  json
+// @Param  tokenInDenom    query  string  true   "The denom of the token in."  example(uosmo)
+// @Param  tokenOutDenom   query  string  true   "The denom of the token out."  example(uion)
+// @Param  maxPriceImpact  query  string  true   "The max absolute price impact as a decimal in range (0, 1)."  example(0.01)
+// @Param  exactOut        query  bool    false  "Boolean flag indicating whether to search over the token out amount with the exact amount out swap method. False by default."
+// @Param  singleRoute     query  bool    false  "Boolean flag indicating whether to return single routes (no splits). False (splits enabled) by default."
+// @Param  humanDenoms     query  bool    false  "Boolean flag indicating whether the given denoms are human readable or not. Human denoms get converted to chain internally"
+// @Success 200  {object}  types.GetMaxAmountForImpactResponse  "The largest amount within the max price impact with its quote"
+// @Router /router/max-amount-for-impact [get]
+func (a *RouterHandler) GetMaxAmountForImpact(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req types.GetMaxAmountForImpactRequest
+	if err := UnmarshalRequest(c, &req); err != nil {
+		return c.JSON(http.StatusBadRequest, domain.ResponseError{Message: err.Error()})
+	}
+
+	if err := req.Validate(); err != nil {
+		return c.JSON(http.StatusBadRequest, domain.ResponseError{Message: err.Error()})
+	}
+
+	chainDenoms, err := mvc.ValidateChainDenomsQueryParam(c, a.TUsecase, []string{req.TokenInDenom, req.TokenOutDenom})
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, domain.ResponseError{Message: err.Error()})
+	}
+
+	// Prevent the router state from being updated by ingest while searching over the amounts.
+	ctx, unlock := a.rLockStateGuard(ctx)
+	defer unlock()
+
+	quote, err := a.RUsecase.GetMaxAmountForPriceImpact(ctx, chainDenoms[0], chainDenoms[1], req.SwapMethod(), req.MaxPriceImpact, req.RouterOptions()...)
+	if err := stateGuardTimeoutError(ctx, err); err != nil {
+		return c.JSON(domain.GetStatusCode(err), domain.ResponseError{Message: err.Error()})
+	}
+
+	if err := a.setQuoteID(quote, req.SwapMethod()); err != nil {
+		return c.JSON(domain.GetStatusCode(err), domain.ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, types.NewGetMaxAmountForImpactResponse(quote))
+}
+
+// @Summary Liquidity Depth
+// @Description Returns the curve of the amounts out and the execution prices across a log-spaced ladder of amounts in
+// @Description for both directions of the pair. Together, the bids and the asks form a synthetic order book for the pair.
+// @Description
+// @Description The bids sell the base for the quote over the ladder of base amounts. The asks buy the base with the quote
+// @Description over the same ladder converted into the quote denom at the execution price of the smallest bid.
+// @Description The execution prices are in the quote denom per base denom in chain units.
+// @Description Each side reuses the same candidate routes for all of its levels. Splits are included unless disabled.
+// @Description The levels of each side stop at the first amount that fails to quote.
+// @ID get-router-depth
+// @Produce  json
+// @Param  base         query  string  true   "The base denom."  example(uosmo)
+// @Param  quote        query  string  true   "The quote denom."  example(uion)
+// @Param  minAmount    query  string  false  "The smallest base amount of the ladder. Defaults to one whole base token or to maxAmount divided by 10^6."
+// @Param  maxAmount    query  string  false  "The largest base amount of the ladder. Defaults to minAmount multiplied by 10^6."
+// @Param  levels       query  int     false  "The number of levels per side between 2 and 50. Defaults to 20."
+// @Param  singleRoute  query  bool    false  "Boolean flag indicating whether to return single routes (no splits). False (splits enabled) by default."
+// @Param  humanDenoms  query  bool    false  "Boolean flag indicating whether the given denoms are human readable or not. Human denoms get converted to chain internally"
+// @Success 200  {object}  domain.LiquidityDepth  "The bids and the asks of the pair"
+// @Router /router/depth [get]
+func (a *RouterHandler) GetDepth(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req types.GetDepthRequest
+	if err := UnmarshalRequest(c, &req); err != nil {
+		return c.JSON(http.StatusBadRequest, domain.ResponseError{Message: err.Error()})
+	}
+
+	if err := req.Validate(); err != nil {
+		return c.JSON(http.StatusBadRequest, domain.ResponseError{Message: err.Error()})
+	}
+
+	chainDenoms, err := mvc.ValidateChainDenomsQueryParam(c, a.TUsecase, []string{req.Base, req.Quote})
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, domain.ResponseError{Message: err.Error()})
+	}
+
+	baseDenom, quoteDenom := chainDenoms[0], chainDenoms[1]
+
+	// Default to one whole base token as the smallest amount.
+	defaultMinAmount := osmomath.NewInt(1_000_000)
+	if baseToken, err := a.TUsecase.GetMetadataByChainDenom(baseDenom); err == nil {
+		defaultMinAmount = osmomath.NewInt(10).ToLegacyDec().Power(uint64(baseToken.Precision)).TruncateInt()
+	}
+
+	baseAmounts, err := req.Amounts(defaultMinAmount)
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, domain.ResponseError{Message: err.Error()})
+	}
+
+	// Prevent the router state from being updated by ingest while computing the levels.
+	ctx, unlock := a.rLockStateGuard(ctx)
+	defer unlock()
+
+	depth, err := a.RUsecase.GetLiquidityDepth(ctx, baseDenom, quoteDenom, baseAmounts, req.RouterOptions()...)
+	if err := stateGuardTimeoutError(ctx, err); err != nil {
+		return c.JSON(domain.GetStatusCode(err), domain.ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, depth)
+}
+
+// @Summary Cyclic Arbitrage Opportunities
+// @Description Searches the pool graph for the profitable cycles of swaps starting and ending in the given denom.
+// @Description
+// @Description The candidate cycles are found over the same pool data as the candidate routes. For each cycle,
+// @Description the amount in maximizing the profit is searched up to `maxAmountIn` or, if not given, up to the balance
+// @Description of the denom in the first pool of the cycle. The amounts out are net of the taker fees but not of the gas fees.
+// @Description Cycles containing generalized CosmWasm pools are not evaluated.
+// @ID get-router-cyclic-arbs
+// @Produce  json
+// @Param  denom             query  string  true   "The denom that the cycles start and end in."  example(uosmo)
+// @Param  maxAmountIn       query  string  false  "The upper bound of the amount in search."
+// @Param  maxPoolsPerCycle  query  int     false  "The maximum number of pools in a cycle. Defaults to 3."
+// @Param  maxCycles         query  int     false  "The maximum number of candidate cycles to evaluate. Defaults to 20."
+// @Param  minLiquidityCap   query  int     false  "The minimum liquidity capitalization of the pools in the cycles. Defaults to the router config."
+// @Param  humanDenoms       query  bool    false  "Boolean flag indicating whether the given denom is human readable or not. Human denoms get converted to chain internally"
+// @Success 200  {object}  types.GetCyclicArbsResponse  "The profitable cycles sorted by profit in descending order"
+// @Router /router/cyclic-arbs [get]
+func (a *RouterHandler) GetCyclicArbs(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req types.GetCyclicArbsRequest
+	if err := UnmarshalRequest(c, &req); err != nil {
+		return c.JSON(http.StatusBadRequest, domain.ResponseError{Message: err.Error()})
+	}
+
+	if err := req.Validate(); err != nil {
+		return c.JSON(http.StatusBadRequest, domain.ResponseError{Message: err.Error()})
+	}
+
+	chainDenoms, err := mvc.ValidateChainDenomsQueryParam(c, a.TUsecase, []string{req.Denom})
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, domain.ResponseError{Message: err.Error()})
+	}
+
+	// Prevent the router state from being updated by ingest while evaluating the cycles.
+	ctx, unlock := a.rLockStateGuard(ctx)
+	defer unlock()
+
+	height := a.StateGuard.GetHeight()
+
+	arbs, err := a.RUsecase.FindCyclicArbs(ctx, chainDenoms[0], req.CyclicArbOptions())
+	if err := stateGuardTimeoutError(ctx, err); err != nil {
+		return c.JSON(domain.GetStatusCode(err), domain.ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, types.GetCyclicArbsResponse{
+		Height: height,
+		Arbs:   arbs,
+	})
+}
+
+// @Summary Basket Quote
+// @Description Returns the combined quote of the legs of a basket swap.
+// @Description
+// @Description Either a single `tokenIn` is split across the comma-separated `tokenOutDenom` pro-rata to `tokenOutWeights`
+// @Description (equally if not given), or each of the comma-separated `tokenIn` is swapped into the single `tokenOutDenom`
+// @Description or into the `tokenOutDenom` at the same index.
+// @Description
+// @Description The legs are quoted in order with the routes of each leg chosen independently. The amounts out of each leg
+// @Description are then re-simulated after the preceding legs swapping through the same pools in the same direction
+// @Description so that the legs do not each assume the full pre-trade liquidity. The swaps in the opposite direction are not netted.
+// @Description The standalone amount out of each leg is the amount out if it were swapped on its own.
+// @ID get-router-basket-quote
+// @Produce  json
+// @Param  tokenIn          query  string  true   "The comma-separated coins to swap."  example(1000000uosmo)
+// @Param  tokenOutDenom    query  string  true   "The comma-separated denoms to swap into."  example(uion,uatom)
+// @Param  tokenOutWeights  query  string  false  "The comma-separated weights of the denoms to swap into. Only allowed with a single tokenIn."  example(0.7,0.3)
+// @Param  singleRoute      query  bool    false  "Boolean flag indicating whether to return single routes (no splits). False (splits enabled) by default."
+// @Param  humanDenoms      query  bool    false  "Boolean flag indicating whether the given denoms are human readable or not. Human denoms get converted to chain internally"
+// @Success 200  {object}  types.GetBasketQuoteResponse  "The combined quote of the legs"
+// @Router /router/basket-quote [get]
+func (a *RouterHandler) GetBasketQuote(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var req types.GetBasketQuoteRequest
+	if err := UnmarshalRequest(c, &req); err != nil {
+		return c.JSON(http.StatusBadRequest, domain.ResponseError{Message: err.Error()})
+	}
+
+	if err := req.Validate(); err != nil {
+		return c.JSON(http.StatusBadRequest, domain.ResponseError{Message: err.Error()})
+	}
+
+	chainDenoms, err := mvc.ValidateChainDenomsQueryParam(c, a.TUsecase, req.Denoms())
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, domain.ResponseError{Message: err.Error()})
+	}
+
+	// Update the denoms in case they were translated from human to chain.
+	req.SetDenoms(chainDenoms)
+
+	legs, err := req.Legs()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, domain.ResponseError{Message: err.Error()})
+	}
+
+	// Prevent the router state from being updated by ingest between the legs.
+	ctx, unlock := a.rLockStateGuard(ctx)
+	defer unlock()
+
+	height := a.StateGuard.GetHeight()
+
+	basketQuote, err := a.RUsecase.GetOptimalBasketQuote(ctx, legs, req.RouterOptions()...)
+	if err := stateGuardTimeoutError(ctx, err); err != nil {
+		return c.JSON(domain.GetStatusCode(err), domain.ResponseError{Message: err.Error()})
+	}
+
+	return c.JSON(http.StatusOK, types.GetBasketQuoteResponse{
+		Height: height,
+		Quote:  basketQuote,
+	})
+}
+
 func (a *RouterHandler) GetTakerFee(c echo.Context) error {
 	idStr := c.Param("id")
 	poolID, err := strconv.ParseUint(idStr, 10, 64)
@@ -303,6 +1012,27 @@ func (a *RouterHandler) GetCachedCandidateRoutes(c echo.Context) error {
 	return c.JSON(http.StatusOK, routes)
 }
 
+// @Summary Quote Audit
+// @Description Returns the pools with the largest divergence of the amount out computed by SQS
+// @Description from the amount out simulated by the chain, as observed in the latest quote audit.
+// @Description
+// @Description The audit periodically re-simulates the configured pairs and a sample of the served exact amount in quotes
+// @Description against the chain at the height the quotes were computed at.
+// @Description The divergence is the SQS amount out relative to the chain amount out, minus one.
+// @Description The results are sorted by the absolute divergence in descending order.
+// @Description Returns 503 if the quote audit is disabled.
+// @ID get-route-quote-audit
+// @Produce  json
+// @Success 200  {array}  domain.QuoteAuditDivergence  "The worst divergences per pool"
+// @Router /router/quote-audit [get]
+func (a *RouterHandler) GetQuoteAudit(c echo.Context) error {
+	if a.QuoteAuditor == nil {
+		return c.JSON(domain.GetStatusCode(domain.ErrQuoteAuditDisabled), domain.ResponseError{Message: domain.ErrQuoteAuditDisabled.Error()})
+	}
+
+	return c.JSON(http.StatusOK, a.QuoteAuditor.GetWorstDivergences())
+}
+
 // TODO: authentication for the endpoint and enable only in dev mode.
 func (a *RouterHandler) StoreRouterStateInFiles(c echo.Context) error {
 	if err := a.RUsecase.StoreRouterStateFiles(); err != nil {
@@ -360,6 +1090,46 @@ func (a *RouterHandler) getSpotPriceScalingFactor(tokenInDenom, tokenOutDenom st
 	return scalingFactor
 }
 
+// getGasPriceInTokenOut returns the price of a unit of gas in the token out denom.
+// The gas price of the gas cost model is converted from the fee denom using the chain pricing source.
+// Returns error if the fee denom fails to be priced in the token out denom.
+func (a *RouterHandler) getGasPriceInTokenOut(ctx context.Context, tokenOutDenom string) (osmomath.Dec, error) {
+	gasCostModel := a.RUsecase.GetConfig().GasCostModel
+
+	gasPrice, err := gasCostModel.GetGasPrice()
+	if err != nil {
+		return osmomath.Dec{}, err
+	}
+
+	if gasCostModel.FeeDenom == tokenOutDenom {
+		return gasPrice, nil
+	}
+
+	prices, err := a.TUsecase.GetPrices(ctx, []string{gasCostModel.FeeDenom}, []string{tokenOutDenom}, domain.ChainPricingSourceType)
+	if err != nil {
+		return osmomath.Dec{}, fmt.Errorf("%w: %s", types.ErrGasPriceInTokenOutNotFound, err)
+	}
+
+	price := prices.GetPriceForDenom(gasCostModel.FeeDenom, tokenOutDenom)
+	if price.IsZero() {
+		return osmomath.Dec{}, types.ErrGasPriceInTokenOutNotFound
+	}
+
+	// The prices are computed over the human denoms. As a result, the price
+	// is converted into the chain denoms using the scaling factors.
+	feeDenomScalingFactor, err := a.TUsecase.GetChainScalingFactorByDenomMut(gasCostModel.FeeDenom)
+	if err != nil {
+		return osmomath.Dec{}, fmt.Errorf("%w: %s", types.ErrGasPriceInTokenOutNotFound, err)
+	}
+
+	tokenOutScalingFactor, err := a.TUsecase.GetChainScalingFactorByDenomMut(tokenOutDenom)
+	if err != nil {
+		return osmomath.Dec{}, fmt.Errorf("%w: %s", types.ErrGasPriceInTokenOutNotFound, err)
+	}
+
+	return gasPrice.Mul(price.Dec()).MulMut(tokenOutScalingFactor).QuoMut(feeDenomScalingFactor), nil
+}
+
 func getValidTokenInStr(c echo.Context) (string, error) {
 	tokenInStr := c.QueryParam("tokenIn")
 
@@ -2,9 +2,12 @@ package http_test
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -12,6 +15,7 @@ import (
 	"github.com/osmosis-labs/sqs/domain"
 	"github.com/osmosis-labs/sqs/domain/mocks"
 	routerdelivery "github.com/osmosis-labs/sqs/router/delivery/http"
+	"github.com/osmosis-labs/sqs/router/types"
 	"github.com/osmosis-labs/sqs/router/usecase/routertesting"
 	"github.com/stretchr/testify/suite"
 )
@@ -26,6 +30,17 @@ var (
 	UATOM = routertesting.ATOM
 )
 
+const defaultHeight = 100
+
+// newStateGuard returns the router state guard of the state updated at defaultHeight.
+func newStateGuard() *domain.RouterStateGuard {
+	stateGuard := domain.NewRouterStateGuard()
+	stateGuard.Lock()
+	stateGuard.SetHeight(defaultHeight)
+	stateGuard.Unlock()
+	return stateGuard
+}
+
 func TestRouterHandlerSuite(t *testing.T) {
 	suite.Run(t, new(RouterHandlerSuite))
 }
@@ -59,6 +74,7 @@ func (s *RouterHandlerSuite) TestGetOptimalQuote() {
 						return true
 					},
 				},
+				StateGuard: newStateGuard(),
 				RUsecase: &mocks.RouterUsecaseMock{
 					GetOptimalQuoteFunc: func(ctx context.Context, tokenIn sdk.Coin, tokenOutDenom string, opts ...domain.RouterOption) (domain.Quote, error) {
 						return s.NewExactAmountInQuote(poolOne, poolTwo, poolThree), nil
@@ -82,6 +98,7 @@ func (s *RouterHandlerSuite) TestGetOptimalQuote() {
 						return true
 					},
 				},
+				StateGuard: newStateGuard(),
 				RUsecase: &mocks.RouterUsecaseMock{
 					GetOptimalQuoteInGivenOutFunc: func(ctx context.Context, tokenIn sdk.Coin, tokenOutDenom string, opts ...domain.RouterOption) (domain.Quote, error) {
 						return s.NewExactAmountOutQuote(poolOne, poolTwo, poolThree), nil
@@ -127,6 +144,72 @@ func (s *RouterHandlerSuite) TestGetOptimalQuote() {
 			expectedResponse:   `{"message": "tokenOut is invalid - must be in the format amountDenom"}`,
 			expectedError:      true,
 		},
+		{
+			name: "compute deadline for exact out request",
+			queryParams: map[string]string{
+				"tokenOut":          "1000ibc/498A0751C798A0D9A389AA3691123DADA57DAA4FE165D5C75894505B876BA6E4",
+				"tokenInDenom":      "ibc/EA1D43981D5C9A1C4AAEA9C23BB1D4FA126BA9BC7020A25E0AE4AA841EA25DC5",
+				"computeDeadlineMs": "250",
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"message": "computeDeadlineMs is only supported for the exact amount in swap method"}`,
+			expectedError:      true,
+		},
+		{
+			name: "state guard timeout for exact in request",
+			queryParams: map[string]string{
+				"tokenIn":       "1000ibc/EA1D43981D5C9A1C4AAEA9C23BB1D4FA126BA9BC7020A25E0AE4AA841EA25DC5",
+				"tokenOutDenom": "ibc/498A0751C798A0D9A389AA3691123DADA57DAA4FE165D5C75894505B876BA6E4",
+			},
+			handler: &routerdelivery.RouterHandler{
+				TUsecase: &mocks.TokensUsecaseMock{
+					IsValidChainDenomFunc: func(chainDenom string) bool {
+						return true
+					},
+				},
+				StateGuard: newStateGuard(),
+				RUsecase: &mocks.RouterUsecaseMock{
+					GetConfigFunc: func() domain.RouterConfig {
+						return domain.RouterConfig{StateGuardTimeoutMs: 10}
+					},
+					// Returns the quote found so far once the state guard timeout is exceeded.
+					GetOptimalQuoteFunc: func(ctx context.Context, tokenIn sdk.Coin, tokenOutDenom string, opts ...domain.RouterOption) (domain.Quote, error) {
+						<-ctx.Done()
+						return s.NewExactAmountInQuote(poolOne, poolTwo, poolThree), nil
+					},
+				},
+			},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedResponse:   `{"message": "router state guard timeout exceeded"}`,
+			expectedError:      true,
+		},
+		{
+			name: "state guard timeout for exact out request",
+			queryParams: map[string]string{
+				"tokenOut":     "1000ibc/498A0751C798A0D9A389AA3691123DADA57DAA4FE165D5C75894505B876BA6E4",
+				"tokenInDenom": "ibc/EA1D43981D5C9A1C4AAEA9C23BB1D4FA126BA9BC7020A25E0AE4AA841EA25DC5",
+			},
+			handler: &routerdelivery.RouterHandler{
+				TUsecase: &mocks.TokensUsecaseMock{
+					IsValidChainDenomFunc: func(chainDenom string) bool {
+						return true
+					},
+				},
+				StateGuard: newStateGuard(),
+				RUsecase: &mocks.RouterUsecaseMock{
+					GetConfigFunc: func() domain.RouterConfig {
+						return domain.RouterConfig{StateGuardTimeoutMs: 10}
+					},
+					GetOptimalQuoteInGivenOutFunc: func(ctx context.Context, tokenIn sdk.Coin, tokenOutDenom string, opts ...domain.RouterOption) (domain.Quote, error) {
+						<-ctx.Done()
+						return s.NewExactAmountOutQuote(poolOne, poolTwo, poolThree), nil
+					},
+				},
+			},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedResponse:   `{"message": "router state guard timeout exceeded"}`,
+			expectedError:      true,
+		},
 	}
 	for _, tc := range testcases {
 		s.Run(tc.name, func() {
@@ -155,12 +238,163 @@ func (s *RouterHandlerSuite) TestGetOptimalQuote() {
 			s.Assert().Equal(tc.expectedStatusCode, rec.Code)
 			s.Assert().JSONEq(
 				strings.TrimSpace(tc.expectedResponse),
-				strings.TrimSpace(rec.Body.String()),
+				s.removeQuoteStamp(rec.Body.String()),
 			)
 		})
 	}
 }
 
+func (s *RouterHandlerSuite) TestGetOptimalQuotes() {
+	_, poolOne := s.PoolOne()
+	_, poolTwo := s.PoolTwo()
+	_, poolThree := s.PoolThree()
+
+	handler := &routerdelivery.RouterHandler{
+		TUsecase: &mocks.TokensUsecaseMock{
+			IsValidChainDenomFunc: func(chainDenom string) bool {
+				return true
+			},
+		},
+		StateGuard: newStateGuard(),
+		RUsecase: &mocks.RouterUsecaseMock{
+			GetOptimalQuoteFunc: func(ctx context.Context, tokenIn sdk.Coin, tokenOutDenom string, opts ...domain.RouterOption) (domain.Quote, error) {
+				return s.NewExactAmountInQuote(poolOne, poolTwo, poolThree), nil
+			},
+		},
+	}
+
+	testcases := []struct {
+		name               string
+		body               string
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{
+			name: "valid and invalid items",
+			body: `{"quotes": [
+				{"tokenIn": "1000ibc/EA1D43981D5C9A1C4AAEA9C23BB1D4FA126BA9BC7020A25E0AE4AA841EA25DC5", "tokenOutDenom": "ibc/498A0751C798A0D9A389AA3691123DADA57DAA4FE165D5C75894505B876BA6E4", "singleRoute": true, "applyExponents": true},
+				{"tokenIn": "invalid_denom", "tokenOutDenom": "usdc"},
+				{"tokenIn": "1000ust", "tokenOut": "1000usdc"}
+			]}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"quotes": [
+				{"quote": ` + s.MustReadFile("../../usecase/routertesting/parsing/quote_amount_in_response.json") + `},
+				{"error": "tokenIn is invalid - must be in the format amountDenom"},
+				{"error": "swap method is invalid - must be either swap exact amount in or swap exact amount out"}
+			]}`,
+		},
+		{
+			name: "compute deadline items",
+			body: `{"quotes": [
+				{"tokenIn": "1000ibc/EA1D43981D5C9A1C4AAEA9C23BB1D4FA126BA9BC7020A25E0AE4AA841EA25DC5", "tokenOutDenom": "ibc/498A0751C798A0D9A389AA3691123DADA57DAA4FE165D5C75894505B876BA6E4", "singleRoute": true, "applyExponents": true, "computeDeadlineMs": 250},
+				{"tokenOut": "1000ibc/498A0751C798A0D9A389AA3691123DADA57DAA4FE165D5C75894505B876BA6E4", "tokenInDenom": "ibc/EA1D43981D5C9A1C4AAEA9C23BB1D4FA126BA9BC7020A25E0AE4AA841EA25DC5", "computeDeadlineMs": 250}
+			]}`,
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"quotes": [
+				{"quote": ` + s.MustReadFile("../../usecase/routertesting/parsing/quote_amount_in_response.json") + `},
+				{"error": "computeDeadlineMs is only supported for the exact amount in swap method"}
+			]}`,
+		},
+		{
+			name:               "empty batch",
+			body:               `{"quotes": []}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"message": "number of quotes must be between 1 and 50"}`,
+		},
+		{
+			name:               "invalid body",
+			body:               `{"quotes": "invalid"}`,
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"message": "request body is invalid - must be a JSON object with the quotes array"}`,
+		},
+	}
+
+	for _, tc := range testcases {
+		s.Run(tc.name, func() {
+			e := echo.New()
+			req := httptest.NewRequest(echo.POST, "/", strings.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			err := handler.GetOptimalQuotes(c)
+			s.Assert().NoError(err)
+			s.Assert().Equal(tc.expectedStatusCode, rec.Code)
+			s.Assert().JSONEq(tc.expectedResponse, s.removeQuoteStamp(rec.Body.String()))
+		})
+	}
+}
+
+// Tests that the batch quotes are computed at most types.MaxBatchQuoteConcurrency at a time and that
+// the quotes are cut by the state guard timeout so that the guard is released.
+func (s *RouterHandlerSuite) TestGetOptimalQuotes_StateGuardBounds() {
+	const numQuotes = types.MaxQuotesPerBatch
+
+	items := make([]string, 0, numQuotes)
+	for i := 0; i < numQuotes; i++ {
+		items = append(items, fmt.Sprintf(`{"tokenIn": "%d%s", "tokenOutDenom": "%s"}`, i+1, UOSMO, USDC))
+	}
+	body := `{"quotes": [` + strings.Join(items, ",") + `]}`
+
+	var (
+		inFlight    atomic.Int32
+		maxInFlight atomic.Int32
+	)
+
+	stateGuard := newStateGuard()
+	handler := &routerdelivery.RouterHandler{
+		TUsecase: &mocks.TokensUsecaseMock{
+			IsValidChainDenomFunc: func(chainDenom string) bool {
+				return true
+			},
+		},
+		RUsecase: &mocks.RouterUsecaseMock{
+			GetConfigFunc: func() domain.RouterConfig {
+				return domain.RouterConfig{StateGuardTimeoutMs: 50}
+			},
+			GetOptimalQuoteFunc: func(ctx context.Context, tokenIn sdk.Coin, tokenOutDenom string, opts ...domain.RouterOption) (domain.Quote, error) {
+				current := inFlight.Add(1)
+				defer inFlight.Add(-1)
+
+				for {
+					prev := maxInFlight.Load()
+					if current <= prev || maxInFlight.CompareAndSwap(prev, current) {
+						break
+					}
+				}
+
+				// Block until the state guard timeout.
+				<-ctx.Done()
+				return nil, context.Cause(ctx)
+			},
+		},
+		StateGuard: stateGuard,
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(echo.POST, "/", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := handler.GetOptimalQuotes(c)
+	s.Require().NoError(err)
+	s.Require().Equal(http.StatusOK, rec.Code)
+
+	var response types.GetQuotesResponse
+	s.Require().NoError(json.Unmarshal(rec.Body.Bytes(), &response))
+	s.Require().Len(response.Quotes, numQuotes)
+	for _, result := range response.Quotes {
+		s.Require().Equal(domain.ErrRouterStateGuardTimeout.Error(), result.Error)
+	}
+
+	s.Require().LessOrEqual(maxInFlight.Load(), int32(types.MaxBatchQuoteConcurrency))
+
+	// The guard is released so that the ingest may proceed.
+	s.Require().True(stateGuard.TryLock())
+	stateGuard.Unlock()
+}
+
 func (s *RouterHandlerSuite) TestGetDirectCustomQuote() {
 	// Prepare 3 pools, we create once and reuse them in the test cases
 	// It's done to avoid creating them multiple times and increasing pool IDs counter.
@@ -190,6 +424,7 @@ func (s *RouterHandlerSuite) TestGetDirectCustomQuote() {
 						return true
 					},
 				},
+				StateGuard: newStateGuard(),
 				RUsecase: &mocks.RouterUsecaseMock{
 					GetCustomDirectQuoteMultiPoolFunc: func(ctx context.Context, tokenIn sdk.Coin, tokenOutDenom []string, poolIDs []uint64) (domain.Quote, error) {
 						return s.NewExactAmountInQuote(poolOne, poolTwo, poolThree), nil
@@ -213,6 +448,7 @@ func (s *RouterHandlerSuite) TestGetDirectCustomQuote() {
 						return true
 					},
 				},
+				StateGuard: newStateGuard(),
 				RUsecase: &mocks.RouterUsecaseMock{
 					GetCustomDirectQuoteMultiPoolInGivenOutFunc: func(ctx context.Context, tokenOut sdk.Coin, tokenInDenom []string, poolIDs []uint64) (domain.Quote, error) {
 						return s.NewExactAmountOutQuote(poolOne, poolTwo, poolThree), nil
@@ -239,6 +475,7 @@ func (s *RouterHandlerSuite) TestGetDirectCustomQuote() {
 						return false
 					},
 				},
+				StateGuard: newStateGuard(),
 				RUsecase: &mocks.RouterUsecaseMock{
 					GetCustomDirectQuoteMultiPoolInGivenOutFunc: func(ctx context.Context, tokenOut sdk.Coin, tokenInDenom []string, poolIDs []uint64) (domain.Quote, error) {
 						return s.NewExactAmountOutQuote(poolOne, poolTwo, poolThree), nil
@@ -265,6 +502,7 @@ func (s *RouterHandlerSuite) TestGetDirectCustomQuote() {
 						return false
 					},
 				},
+				StateGuard: newStateGuard(),
 				RUsecase: &mocks.RouterUsecaseMock{
 					GetCustomDirectQuoteMultiPoolInGivenOutFunc: func(ctx context.Context, tokenOut sdk.Coin, tokenInDenom []string, poolIDs []uint64) (domain.Quote, error) {
 						return s.NewExactAmountOutQuote(poolOne, poolTwo, poolThree), nil
@@ -359,8 +597,47 @@ func (s *RouterHandlerSuite) TestGetDirectCustomQuote() {
 			s.Assert().Equal(tc.expectedStatusCode, rec.Code)
 			s.Assert().JSONEq(
 				strings.TrimSpace(tc.expectedResponse),
-				strings.TrimSpace(rec.Body.String()),
+				s.removeQuoteStamp(rec.Body.String()),
 			)
 		})
 	}
 }
+
+// removeQuoteStamp validates that every quote in the given response is stamped with defaultHeight
+// and the quote ID of its routes. Returns the response with the stamps removed so that it can be
+// compared against the fixtures.
+func (s *RouterHandlerSuite) removeQuoteStamp(response string) string {
+	var parsed any
+	s.Require().NoError(json.Unmarshal([]byte(response), &parsed))
+
+	var removeStamp func(v any)
+	removeStamp = func(v any) {
+		switch v := v.(type) {
+		case map[string]any:
+			if quoteID, ok := v["quote_id"]; ok {
+				spec, err := domain.ParseQuoteID(quoteID.(string))
+				s.Require().NoError(err)
+				s.Require().Equal(uint64(defaultHeight), spec.Height)
+				s.Require().Len(spec.Routes, len(v["route"].([]any)))
+				s.Require().Equal(float64(defaultHeight), v["height"])
+
+				delete(v, "quote_id")
+				delete(v, "height")
+			}
+
+			for _, child := range v {
+				removeStamp(child)
+			}
+		case []any:
+			for _, child := range v {
+				removeStamp(child)
+			}
+		}
+	}
+	removeStamp(parsed)
+
+	bz, err := json.Marshal(parsed)
+	s.Require().NoError(err)
+
+	return string(bz)
+}
@@ -1,6 +1,11 @@
 package types
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/osmosis-labs/sqs/domain"
+)
 
 // Handler Errors
 var (
@@ -16,4 +21,37 @@ var (
 	ErrNumOfTokenOutDenomPoolsMismatch = errors.New("number of tokenOutDenom must be equal to number of pool IDs")
 	ErrNumOfTokenInDenomPoolsMismatch  = errors.New("number of tokenInDenom must be equal to number of pool IDs")
 	ErrInvalidRouteType                = errors.New("invalid route type")
+	ErrSplitIncrementsNotValid         = fmt.Errorf("splitIncrements must be an integer between 0 and %d", domain.MaxSplitIncrements)
+	ErrSplitRefinementRoundsNotValid   = fmt.Errorf("splitRefinementRounds must be an integer between 0 and %d", domain.MaxSplitRefinementRounds)
+	ErrQuotesRequestBodyNotValid       = errors.New("request body is invalid - must be a JSON object with the quotes array")
+	ErrQuotesBatchSizeNotValid         = fmt.Errorf("number of quotes must be between 1 and %d", MaxQuotesPerBatch)
+	ErrSenderNotValid                  = errors.New("sender must be a valid bech32 account address of the chain")
+	ErrSlippageToleranceNotValid       = errors.New("slippageTolerance must be a decimal in range [0, 1)")
+	ErrExcludePoolIDsNotValid          = errors.New("excludePoolIDs must be a comma-separated list of pool IDs")
+	ErrOnlyPoolTypesNotValid           = fmt.Errorf("onlyPoolTypes must be a comma-separated list of pool types out of %v", domain.CandidateRoutePoolTypes)
+	ErrExcludeDenomsNotValid           = errors.New("excludeDenoms must be a comma-separated list of denoms")
+	ErrMaxPoolsPerRouteNotValid        = fmt.Errorf("maxPoolsPerRoute must be an integer between 0 and %d", MaxRequestedPoolsPerRoute)
+	ErrMaxRoutesNotValid               = fmt.Errorf("maxRoutes must be an integer between 0 and %d", MaxRequestedRoutes)
+	ErrMinLiquidityCapNotValid         = errors.New("minLiquidityCap must be a non-negative integer")
+	ErrNumOfTokenOutDenomMismatch      = errors.New("number of tokenOutDenom must be equal to number of tokenIn")
+	ErrQuoteIDRoutesNotValid           = fmt.Errorf("quote ID must have at most %d routes of at most %d pools each", MaxRequestedRoutes, MaxRequestedPoolsPerRoute)
+	ErrGasAwareNotValid                = errors.New("gasAware is only supported for the exact amount in swap method")
+	ErrGasAwareNotEnabled              = errors.New("gas-aware ranking is not enabled")
+	ErrGasPriceInTokenOutNotFound      = errors.New("failed to price the gas in the token out denom")
+	ErrGasEstimateNotFound             = errors.New("failed to estimate the gas of the quote")
+	ErrMaxPriceImpactNotValid          = errors.New("maxPriceImpact must be a decimal in range (0, 1)")
+	ErrBaseNotSpecified                = errors.New("base is required")
+	ErrQuoteNotSpecified               = errors.New("quote is required")
+	ErrDepthAmountNotValid             = errors.New("minAmount and maxAmount must be positive integers with maxAmount not less than minAmount")
+	ErrDepthLevelsNotValid             = fmt.Errorf("levels must be an integer between 2 and %d", MaxDepthLevels)
+	ErrDenomNotSpecified               = errors.New("denom is required")
+	ErrMaxAmountInNotValid             = errors.New("maxAmountIn must be a positive integer")
+	ErrMaxPoolsPerCycleNotValid        = fmt.Errorf("maxPoolsPerCycle must be an integer between 2 and %d", MaxRequestedPoolsPerRoute)
+	ErrMaxCyclesNotValid               = fmt.Errorf("maxCycles must be an integer between 0 and %d", MaxRequestedRoutes)
+	ErrCandidateRouteAlgorithmNotValid = fmt.Errorf("candidateRouteAlgorithm must be one of %v", domain.CandidateRouteAlgorithms)
+	ErrBasketLegsNotValid              = fmt.Errorf("number of basket legs must be between 1 and %d", MaxBasketLegs)
+	ErrBasketTokenOutDenomMismatch     = errors.New("number of tokenOutDenom must be either one or equal to number of tokenIn when multiple tokenIn are given")
+	ErrTokenOutWeightsNotValid         = errors.New("tokenOutWeights must be a comma-separated list of positive decimals, one per tokenOutDenom, given with a single tokenIn")
+	ErrComputeDeadlineNotValid         = fmt.Errorf("computeDeadlineMs must be an integer between 0 and %d", MaxRequestedComputeDeadlineMs)
+	ErrComputeDeadlineNotSupported     = errors.New("computeDeadlineMs is only supported for the exact amount in swap method")
 )
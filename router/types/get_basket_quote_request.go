@@ -0,0 +1,191 @@
+package types
+
+import (
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	"github.com/osmosis-labs/sqs/domain"
+)
+
+// MaxBasketLegs is the maximum number of legs that may be requested from the basket quote endpoint.
+const MaxBasketLegs = 10
+
+// GetBasketQuoteRequest represents the basket quote request for the /router/basket-quote endpoint.
+// Either a single token in is swapped into each of the token out denoms pro-rata to the weights,
+// or each of the token in coins is swapped into the single token out denom or into the token out denom
+// at the same index.
+type GetBasketQuoteRequest struct {
+	TokenIn       []sdk.Coin
+	TokenOutDenom []string
+	// TokenOutWeights are the weights of the token out denoms. Optional.
+	// Only allowed with a single token in. The token in is split equally if not given.
+	TokenOutWeights []osmomath.Dec
+	SingleRoute     bool
+}
+
+// GetBasketQuoteResponse represents the response of the /router/basket-quote endpoint.
+type GetBasketQuoteResponse struct {
+	// Height is the height of the state the legs were quoted against.
+	Height uint64 `json:"height"`
+	// Quote is the combined quote of the legs.
+	Quote domain.BasketQuote `json:"quote"`
+}
+
+// UnmarshalHTTPRequest unmarshals the HTTP request to GetBasketQuoteRequest.
+// It returns an error if any of the token in coins or of the weights is invalid.
+func (r *GetBasketQuoteRequest) UnmarshalHTTPRequest(c echo.Context) error {
+	var err error
+
+	if tokenIn := c.QueryParam("tokenIn"); tokenIn != "" {
+		for _, tokenInStr := range strings.Split(tokenIn, ",") {
+			tokenInCoin, err := sdk.ParseCoinNormalized(strings.TrimSpace(tokenInStr))
+			if err != nil {
+				return ErrTokenInNotValid
+			}
+			r.TokenIn = append(r.TokenIn, tokenInCoin)
+		}
+	}
+
+	if tokenOutDenom := c.QueryParam("tokenOutDenom"); tokenOutDenom != "" {
+		for _, denom := range strings.Split(tokenOutDenom, ",") {
+			r.TokenOutDenom = append(r.TokenOutDenom, strings.TrimSpace(denom))
+		}
+	}
+
+	if tokenOutWeights := c.QueryParam("tokenOutWeights"); tokenOutWeights != "" {
+		for _, weightStr := range strings.Split(tokenOutWeights, ",") {
+			weight, err := osmomath.NewDecFromStr(strings.TrimSpace(weightStr))
+			if err != nil {
+				return ErrTokenOutWeightsNotValid
+			}
+			r.TokenOutWeights = append(r.TokenOutWeights, weight)
+		}
+	}
+
+	r.SingleRoute, err = domain.ParseBooleanQueryParam(c, "singleRoute")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate validates the GetBasketQuoteRequest.
+func (r *GetBasketQuoteRequest) Validate() error {
+	if len(r.TokenIn) == 0 {
+		return ErrTokenInNotSpecified
+	}
+
+	for _, tokenIn := range r.TokenIn {
+		if !tokenIn.Amount.IsPositive() {
+			return ErrTokenInNotValid
+		}
+	}
+
+	if len(r.TokenOutDenom) == 0 {
+		return ErrTokenOutDenomNotSpecified
+	}
+
+	if len(r.TokenIn) > 1 && len(r.TokenOutDenom) != 1 && len(r.TokenOutDenom) != len(r.TokenIn) {
+		return ErrBasketTokenOutDenomMismatch
+	}
+
+	numLegs := max(len(r.TokenIn), len(r.TokenOutDenom))
+	if numLegs > MaxBasketLegs {
+		return ErrBasketLegsNotValid
+	}
+
+	if len(r.TokenOutWeights) > 0 {
+		if len(r.TokenIn) > 1 || len(r.TokenOutWeights) != len(r.TokenOutDenom) {
+			return ErrTokenOutWeightsNotValid
+		}
+
+		for _, weight := range r.TokenOutWeights {
+			if !weight.IsPositive() {
+				return ErrTokenOutWeightsNotValid
+			}
+		}
+	}
+
+	for i := 0; i < numLegs; i++ {
+		if err := domain.ValidateInputDenoms(r.tokenInAt(i).Denom, r.tokenOutDenomAt(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Denoms returns the token in denoms followed by the token out denoms.
+func (r *GetBasketQuoteRequest) Denoms() []string {
+	denoms := make([]string, 0, len(r.TokenIn)+len(r.TokenOutDenom))
+	for _, tokenIn := range r.TokenIn {
+		denoms = append(denoms, tokenIn.Denom)
+	}
+	return append(denoms, r.TokenOutDenom...)
+}
+
+// SetDenoms sets the token in and the token out denoms in the order returned by Denoms.
+// Used to update the denoms in case they were translated from human to chain.
+func (r *GetBasketQuoteRequest) SetDenoms(denoms []string) {
+	for i := range r.TokenIn {
+		r.TokenIn[i].Denom = denoms[i]
+	}
+	copy(r.TokenOutDenom, denoms[len(r.TokenIn):])
+}
+
+// Legs returns the legs of the basket swap.
+// A single token in is split across the token out denoms pro-rata to the weights or equally if not given.
+// Returns error if any of the split legs would have a zero amount in.
+// CONTRACT: the request is validated.
+func (r *GetBasketQuoteRequest) Legs() ([]domain.BasketLeg, error) {
+	if len(r.TokenIn) == 1 {
+		weights := r.TokenOutWeights
+		if len(weights) == 0 {
+			weights = make([]osmomath.Dec, len(r.TokenOutDenom))
+			for i := range weights {
+				weights[i] = osmomath.OneDec()
+			}
+		}
+
+		return domain.NewWeightedBasketLegs(r.TokenIn[0], r.TokenOutDenom, weights)
+	}
+
+	legs := make([]domain.BasketLeg, 0, len(r.TokenIn))
+	for i := range r.TokenIn {
+		legs = append(legs, domain.BasketLeg{
+			TokenIn:       r.tokenInAt(i),
+			TokenOutDenom: r.tokenOutDenomAt(i),
+		})
+	}
+	return legs, nil
+}
+
+// RouterOptions returns the router options overridden by the request.
+func (r *GetBasketQuoteRequest) RouterOptions() []domain.RouterOption {
+	var routerOpts []domain.RouterOption
+	if r.SingleRoute {
+		routerOpts = append(routerOpts, domain.WithMaxSplitRoutes(domain.DisableSplitRoutes))
+	}
+	return routerOpts
+}
+
+// tokenInAt returns the token in of the i-th leg before splitting a single token in.
+func (r *GetBasketQuoteRequest) tokenInAt(i int) sdk.Coin {
+	if len(r.TokenIn) == 1 {
+		return r.TokenIn[0]
+	}
+	return r.TokenIn[i]
+}
+
+// tokenOutDenomAt returns the token out denom of the i-th leg.
+func (r *GetBasketQuoteRequest) tokenOutDenomAt(i int) string {
+	if len(r.TokenOutDenom) == 1 {
+		return r.TokenOutDenom[0]
+	}
+	return r.TokenOutDenom[i]
+}
@@ -0,0 +1,200 @@
+package types_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	"github.com/osmosis-labs/sqs/domain"
+	"github.com/osmosis-labs/sqs/router/types"
+)
+
+// TestGetBasketQuoteRequestUnmarshal tests the UnmarshalHTTPRequest, Validate and Legs methods of GetBasketQuoteRequest.
+func TestGetBasketQuoteRequestUnmarshal(t *testing.T) {
+	testcases := []struct {
+		name                  string
+		queryParams           map[string]string
+		expectedLegs          []domain.BasketLeg
+		expectedUnmarshalErr  bool
+		expectedValidationErr error
+	}{
+		{
+			name: "single token in split equally",
+			queryParams: map[string]string{
+				"tokenIn":       "1000uusdc",
+				"tokenOutDenom": "uatom,uosmo",
+			},
+			expectedLegs: []domain.BasketLeg{
+				{TokenIn: sdk.NewCoin("uusdc", osmomath.NewInt(500)), TokenOutDenom: "uatom"},
+				{TokenIn: sdk.NewCoin("uusdc", osmomath.NewInt(500)), TokenOutDenom: "uosmo"},
+			},
+		},
+		{
+			name: "single token in split by weights",
+			queryParams: map[string]string{
+				"tokenIn":         "1000uusdc",
+				"tokenOutDenom":   "uatom, uosmo",
+				"tokenOutWeights": "0.7, 0.3",
+			},
+			expectedLegs: []domain.BasketLeg{
+				{TokenIn: sdk.NewCoin("uusdc", osmomath.NewInt(700)), TokenOutDenom: "uatom"},
+				{TokenIn: sdk.NewCoin("uusdc", osmomath.NewInt(300)), TokenOutDenom: "uosmo"},
+			},
+		},
+		{
+			name: "multiple tokens in into a single token out",
+			queryParams: map[string]string{
+				"tokenIn":       "1000uatom,2000uosmo",
+				"tokenOutDenom": "uusdc",
+			},
+			expectedLegs: []domain.BasketLeg{
+				{TokenIn: sdk.NewCoin("uatom", osmomath.NewInt(1000)), TokenOutDenom: "uusdc"},
+				{TokenIn: sdk.NewCoin("uosmo", osmomath.NewInt(2000)), TokenOutDenom: "uusdc"},
+			},
+		},
+		{
+			name: "multiple tokens in into the token out at the same index",
+			queryParams: map[string]string{
+				"tokenIn":       "1000uatom,2000uosmo",
+				"tokenOutDenom": "uusdc,uion",
+			},
+			expectedLegs: []domain.BasketLeg{
+				{TokenIn: sdk.NewCoin("uatom", osmomath.NewInt(1000)), TokenOutDenom: "uusdc"},
+				{TokenIn: sdk.NewCoin("uosmo", osmomath.NewInt(2000)), TokenOutDenom: "uion"},
+			},
+		},
+		{
+			name: "invalid token in",
+			queryParams: map[string]string{
+				"tokenIn":       "uusdc",
+				"tokenOutDenom": "uatom",
+			},
+			expectedUnmarshalErr: true,
+		},
+		{
+			name: "invalid weight",
+			queryParams: map[string]string{
+				"tokenIn":         "1000uusdc",
+				"tokenOutDenom":   "uatom",
+				"tokenOutWeights": "abc",
+			},
+			expectedUnmarshalErr: true,
+		},
+		{
+			name: "zero token in",
+			queryParams: map[string]string{
+				"tokenIn":       "0uusdc",
+				"tokenOutDenom": "uatom",
+			},
+			expectedValidationErr: types.ErrTokenInNotValid,
+		},
+		{
+			name: "missing token in",
+			queryParams: map[string]string{
+				"tokenOutDenom": "uatom",
+			},
+			expectedValidationErr: types.ErrTokenInNotSpecified,
+		},
+		{
+			name: "missing token out denom",
+			queryParams: map[string]string{
+				"tokenIn": "1000uusdc",
+			},
+			expectedValidationErr: types.ErrTokenOutDenomNotSpecified,
+		},
+		{
+			name: "token out denoms mismatch",
+			queryParams: map[string]string{
+				"tokenIn":       "1000uatom,2000uosmo,3000uion",
+				"tokenOutDenom": "uusdc,uusdt",
+			},
+			expectedValidationErr: types.ErrBasketTokenOutDenomMismatch,
+		},
+		{
+			name: "weights with multiple tokens in",
+			queryParams: map[string]string{
+				"tokenIn":         "1000uatom,2000uosmo",
+				"tokenOutDenom":   "uusdc",
+				"tokenOutWeights": "1",
+			},
+			expectedValidationErr: types.ErrTokenOutWeightsNotValid,
+		},
+		{
+			name: "weights mismatch",
+			queryParams: map[string]string{
+				"tokenIn":         "1000uusdc",
+				"tokenOutDenom":   "uatom,uosmo",
+				"tokenOutWeights": "1",
+			},
+			expectedValidationErr: types.ErrTokenOutWeightsNotValid,
+		},
+		{
+			name: "non-positive weight",
+			queryParams: map[string]string{
+				"tokenIn":         "1000uusdc",
+				"tokenOutDenom":   "uatom,uosmo",
+				"tokenOutWeights": "1,0",
+			},
+			expectedValidationErr: types.ErrTokenOutWeightsNotValid,
+		},
+		{
+			name: "too many legs",
+			queryParams: map[string]string{
+				"tokenIn":       "1000uusdc",
+				"tokenOutDenom": "a,b,c,d,e,f,g,h,i,j,k",
+			},
+			expectedValidationErr: types.ErrBasketLegsNotValid,
+		},
+		{
+			name: "same token in and token out denom",
+			queryParams: map[string]string{
+				"tokenIn":       "1000uusdc",
+				"tokenOutDenom": "uatom,uusdc",
+			},
+			expectedValidationErr: domain.SameDenomError{DenomA: "uusdc", DenomB: "uusdc"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(echo.GET, "/", nil)
+			q := req.URL.Query()
+			for k, v := range tc.queryParams {
+				q.Add(k, v)
+			}
+			req.URL.RawQuery = q.Encode()
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			var result types.GetBasketQuoteRequest
+			err := (&result).UnmarshalHTTPRequest(c)
+			if tc.expectedUnmarshalErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			err = result.Validate()
+			if tc.expectedValidationErr != nil {
+				assert.ErrorIs(t, err, tc.expectedValidationErr)
+				return
+			}
+			assert.NoError(t, err)
+
+			legs, err := result.Legs()
+			require.NoError(t, err)
+			require.Len(t, legs, len(tc.expectedLegs))
+			for i, leg := range legs {
+				assert.Equal(t, tc.expectedLegs[i].TokenIn.String(), leg.TokenIn.String())
+				assert.Equal(t, tc.expectedLegs[i].TokenOutDenom, leg.TokenOutDenom)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package types
+
+import (
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	"github.com/osmosis-labs/sqs/domain"
+)
+
+// GetCyclicArbsRequest represents the cyclic arbitrage search request for the /router/cyclic-arbs endpoint.
+type GetCyclicArbsRequest struct {
+	// Denom is the denom that the cycles start and end in.
+	Denom string
+	// MaxAmountIn is the upper bound of the amount in search. Optional.
+	MaxAmountIn osmomath.Int
+	// MaxPoolsPerCycle overrides the default maximum number of pools in a cycle. Optional.
+	MaxPoolsPerCycle int
+	// MaxCycles overrides the default maximum number of candidate cycles. Optional.
+	MaxCycles int
+	// MinLiquidityCap overrides the router min pool liquidity cap. Optional.
+	MinLiquidityCap uint64
+}
+
+// GetCyclicArbsResponse represents the response of the /router/cyclic-arbs endpoint.
+type GetCyclicArbsResponse struct {
+	// Height is the height of the state the cycles were evaluated against.
+	Height uint64 `json:"height"`
+	// Arbs are the profitable cycles sorted by profit in descending order.
+	Arbs []domain.CyclicArb `json:"arbs"`
+}
+
+// UnmarshalHTTPRequest unmarshals the HTTP request to GetCyclicArbsRequest.
+// It returns an error if any of the optional parameters fails to parse.
+func (r *GetCyclicArbsRequest) UnmarshalHTTPRequest(c echo.Context) error {
+	var err error
+
+	r.Denom = c.QueryParam("denom")
+
+	if maxAmountIn := c.QueryParam("maxAmountIn"); maxAmountIn != "" {
+		var ok bool
+		r.MaxAmountIn, ok = osmomath.NewIntFromString(maxAmountIn)
+		if !ok {
+			return ErrMaxAmountInNotValid
+		}
+	}
+
+	if maxPoolsPerCycle := c.QueryParam("maxPoolsPerCycle"); maxPoolsPerCycle != "" {
+		r.MaxPoolsPerCycle, err = strconv.Atoi(maxPoolsPerCycle)
+		if err != nil {
+			return ErrMaxPoolsPerCycleNotValid
+		}
+	}
+
+	if maxCycles := c.QueryParam("maxCycles"); maxCycles != "" {
+		r.MaxCycles, err = strconv.Atoi(maxCycles)
+		if err != nil {
+			return ErrMaxCyclesNotValid
+		}
+	}
+
+	if minLiquidityCap := c.QueryParam("minLiquidityCap"); minLiquidityCap != "" {
+		r.MinLiquidityCap, err = strconv.ParseUint(minLiquidityCap, 10, 64)
+		if err != nil {
+			return ErrMinLiquidityCapNotValid
+		}
+	}
+
+	return nil
+}
+
+// Validate validates the GetCyclicArbsRequest.
+// The search is bounded by the same limits as the requested route constraints.
+func (r *GetCyclicArbsRequest) Validate() error {
+	if r.Denom == "" {
+		return ErrDenomNotSpecified
+	}
+
+	if !r.MaxAmountIn.IsNil() && !r.MaxAmountIn.IsPositive() {
+		return ErrMaxAmountInNotValid
+	}
+
+	if r.MaxPoolsPerCycle != 0 && (r.MaxPoolsPerCycle < 2 || r.MaxPoolsPerCycle > MaxRequestedPoolsPerRoute) {
+		return ErrMaxPoolsPerCycleNotValid
+	}
+
+	if r.MaxCycles < 0 || r.MaxCycles > MaxRequestedRoutes {
+		return ErrMaxCyclesNotValid
+	}
+
+	return nil
+}
+
+// CyclicArbOptions returns the cyclic arbitrage search options from the request.
+func (r *GetCyclicArbsRequest) CyclicArbOptions() domain.CyclicArbOptions {
+	return domain.CyclicArbOptions{
+		MaxPoolsPerCycle:    r.MaxPoolsPerCycle,
+		MaxCycles:           r.MaxCycles,
+		MinPoolLiquidityCap: r.MinLiquidityCap,
+		MaxAmountIn:         r.MaxAmountIn,
+	}
+}
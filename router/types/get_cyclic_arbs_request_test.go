@@ -0,0 +1,142 @@
+package types_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	"github.com/osmosis-labs/sqs/router/types"
+)
+
+// TestGetCyclicArbsRequestUnmarshal tests the UnmarshalHTTPRequest and Validate methods of GetCyclicArbsRequest.
+func TestGetCyclicArbsRequestUnmarshal(t *testing.T) {
+	testcases := []struct {
+		name                  string
+		queryParams           map[string]string
+		expectedResult        *types.GetCyclicArbsRequest
+		expectedUnmarshalErr  error
+		expectedValidationErr error
+	}{
+		{
+			name: "valid request with denom only",
+			queryParams: map[string]string{
+				"denom": "uosmo",
+			},
+			expectedResult: &types.GetCyclicArbsRequest{
+				Denom: "uosmo",
+			},
+		},
+		{
+			name: "valid request with all parameters",
+			queryParams: map[string]string{
+				"denom":            "uosmo",
+				"maxAmountIn":      "1000000",
+				"maxPoolsPerCycle": "4",
+				"maxCycles":        "10",
+				"minLiquidityCap":  "5000",
+			},
+			expectedResult: &types.GetCyclicArbsRequest{
+				Denom:            "uosmo",
+				MaxAmountIn:      osmomath.NewInt(1000000),
+				MaxPoolsPerCycle: 4,
+				MaxCycles:        10,
+				MinLiquidityCap:  5000,
+			},
+		},
+		{
+			name:                  "missing denom",
+			queryParams:           map[string]string{},
+			expectedValidationErr: types.ErrDenomNotSpecified,
+		},
+		{
+			name: "invalid max amount in",
+			queryParams: map[string]string{
+				"denom":       "uosmo",
+				"maxAmountIn": "abc",
+			},
+			expectedUnmarshalErr: types.ErrMaxAmountInNotValid,
+		},
+		{
+			name: "zero max amount in",
+			queryParams: map[string]string{
+				"denom":       "uosmo",
+				"maxAmountIn": "0",
+			},
+			expectedValidationErr: types.ErrMaxAmountInNotValid,
+		},
+		{
+			name: "invalid max pools per cycle",
+			queryParams: map[string]string{
+				"denom":            "uosmo",
+				"maxPoolsPerCycle": "abc",
+			},
+			expectedUnmarshalErr: types.ErrMaxPoolsPerCycleNotValid,
+		},
+		{
+			name: "single pool cycle",
+			queryParams: map[string]string{
+				"denom":            "uosmo",
+				"maxPoolsPerCycle": "1",
+			},
+			expectedValidationErr: types.ErrMaxPoolsPerCycleNotValid,
+		},
+		{
+			name: "too many pools per cycle",
+			queryParams: map[string]string{
+				"denom":            "uosmo",
+				"maxPoolsPerCycle": "7",
+			},
+			expectedValidationErr: types.ErrMaxPoolsPerCycleNotValid,
+		},
+		{
+			name: "too many cycles",
+			queryParams: map[string]string{
+				"denom":     "uosmo",
+				"maxCycles": "51",
+			},
+			expectedValidationErr: types.ErrMaxCyclesNotValid,
+		},
+		{
+			name: "invalid min liquidity cap",
+			queryParams: map[string]string{
+				"denom":           "uosmo",
+				"minLiquidityCap": "-1",
+			},
+			expectedUnmarshalErr: types.ErrMinLiquidityCapNotValid,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(echo.GET, "/", nil)
+			q := req.URL.Query()
+			for k, v := range tc.queryParams {
+				q.Add(k, v)
+			}
+			req.URL.RawQuery = q.Encode()
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			var result types.GetCyclicArbsRequest
+			err := (&result).UnmarshalHTTPRequest(c)
+			if tc.expectedUnmarshalErr != nil {
+				assert.ErrorIs(t, err, tc.expectedUnmarshalErr)
+				return
+			}
+			assert.NoError(t, err)
+
+			err = result.Validate()
+			if tc.expectedValidationErr != nil {
+				assert.ErrorIs(t, err, tc.expectedValidationErr)
+				return
+			}
+			assert.NoError(t, err)
+
+			assert.Equal(t, tc.expectedResult, &result)
+		})
+	}
+}
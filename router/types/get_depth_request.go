@@ -0,0 +1,143 @@
+package types
+
+import (
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	"github.com/osmosis-labs/sqs/domain"
+)
+
+const (
+	// MaxDepthLevels is the maximum number of levels per side that may be requested from the depth endpoint.
+	MaxDepthLevels = 50
+	// DefaultDepthLevels is the number of levels per side if not requested.
+	DefaultDepthLevels = 20
+	// DefaultDepthOrdersOfMagnitude is the number of orders of magnitude between the smallest and the largest
+	// level if either is not requested.
+	DefaultDepthOrdersOfMagnitude = 6
+)
+
+// GetDepthRequest represents the liquidity depth request for the /router/depth endpoint.
+// The amounts are in the base denom. The amounts of the asks are converted into the quote denom.
+type GetDepthRequest struct {
+	Base  string
+	Quote string
+	// MinAmount is the smallest amount of the ladder. Optional.
+	MinAmount osmomath.Int
+	// MaxAmount is the largest amount of the ladder. Optional.
+	MaxAmount osmomath.Int
+	// Levels is the number of levels per side. Optional.
+	Levels      int
+	SingleRoute bool
+}
+
+// UnmarshalHTTPRequest unmarshals the HTTP request to GetDepthRequest.
+// It returns an error if any of the optional parameters fails to parse.
+func (r *GetDepthRequest) UnmarshalHTTPRequest(c echo.Context) error {
+	var err error
+
+	r.Base = c.QueryParam("base")
+	r.Quote = c.QueryParam("quote")
+
+	if minAmount := c.QueryParam("minAmount"); minAmount != "" {
+		var ok bool
+		r.MinAmount, ok = osmomath.NewIntFromString(minAmount)
+		if !ok {
+			return ErrDepthAmountNotValid
+		}
+	}
+
+	if maxAmount := c.QueryParam("maxAmount"); maxAmount != "" {
+		var ok bool
+		r.MaxAmount, ok = osmomath.NewIntFromString(maxAmount)
+		if !ok {
+			return ErrDepthAmountNotValid
+		}
+	}
+
+	if levels := c.QueryParam("levels"); levels != "" {
+		r.Levels, err = strconv.Atoi(levels)
+		if err != nil {
+			return ErrDepthLevelsNotValid
+		}
+	}
+
+	r.SingleRoute, err = domain.ParseBooleanQueryParam(c, "singleRoute")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate validates the GetDepthRequest.
+func (r *GetDepthRequest) Validate() error {
+	if r.Base == "" {
+		return ErrBaseNotSpecified
+	}
+
+	if r.Quote == "" {
+		return ErrQuoteNotSpecified
+	}
+
+	if err := domain.ValidateInputDenoms(r.Base, r.Quote); err != nil {
+		return err
+	}
+
+	if !r.MinAmount.IsNil() && !r.MinAmount.IsPositive() {
+		return ErrDepthAmountNotValid
+	}
+
+	if !r.MaxAmount.IsNil() && !r.MaxAmount.IsPositive() {
+		return ErrDepthAmountNotValid
+	}
+
+	if !r.MinAmount.IsNil() && !r.MaxAmount.IsNil() && r.MaxAmount.LT(r.MinAmount) {
+		return ErrDepthAmountNotValid
+	}
+
+	if r.Levels != 0 && (r.Levels < 2 || r.Levels > MaxDepthLevels) {
+		return ErrDepthLevelsNotValid
+	}
+
+	return nil
+}
+
+// Amounts returns the log-spaced ladder of the base amounts.
+// If either bound is not requested, it is set DefaultDepthOrdersOfMagnitude orders of magnitude away from the other.
+// If neither is requested, the smallest amount is the given default.
+func (r *GetDepthRequest) Amounts(defaultMinAmount osmomath.Int) ([]osmomath.Int, error) {
+	rangeMultiplier := osmomath.NewInt(10).ToLegacyDec().Power(DefaultDepthOrdersOfMagnitude).TruncateInt()
+
+	minAmount, maxAmount := r.MinAmount, r.MaxAmount
+	switch {
+	case minAmount.IsNil() && maxAmount.IsNil():
+		minAmount = defaultMinAmount
+		maxAmount = minAmount.Mul(rangeMultiplier)
+	case minAmount.IsNil():
+		minAmount = maxAmount.Quo(rangeMultiplier)
+		if minAmount.IsZero() {
+			minAmount = osmomath.OneInt()
+		}
+	case maxAmount.IsNil():
+		maxAmount = minAmount.Mul(rangeMultiplier)
+	}
+
+	levels := r.Levels
+	if levels == 0 {
+		levels = DefaultDepthLevels
+	}
+
+	return domain.NewLogSpacedAmounts(minAmount, maxAmount, levels)
+}
+
+// RouterOptions returns the router options overridden by the request.
+func (r *GetDepthRequest) RouterOptions() []domain.RouterOption {
+	var routerOpts []domain.RouterOption
+	if r.SingleRoute {
+		routerOpts = append(routerOpts, domain.WithMaxSplitRoutes(domain.DisableSplitRoutes))
+	}
+	return routerOpts
+}
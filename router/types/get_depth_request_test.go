@@ -0,0 +1,150 @@
+package types_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	"github.com/osmosis-labs/sqs/router/types"
+)
+
+// TestGetDepthRequestUnmarshal tests the UnmarshalHTTPRequest, Validate and Amounts methods of GetDepthRequest.
+func TestGetDepthRequestUnmarshal(t *testing.T) {
+	defaultMinAmount := osmomath.NewInt(1_000_000)
+
+	testcases := []struct {
+		name                  string
+		queryParams           map[string]string
+		expectedMinAmount     osmomath.Int
+		expectedMaxAmount     osmomath.Int
+		expectedLevels        int
+		expectedUnmarshalErr  bool
+		expectedValidationErr error
+	}{
+		{
+			name: "defaults",
+			queryParams: map[string]string{
+				"base":  "uosmo",
+				"quote": "uion",
+			},
+			expectedMinAmount: defaultMinAmount,
+			expectedMaxAmount: osmomath.NewInt(1_000_000_000_000),
+			expectedLevels:    types.DefaultDepthLevels,
+		},
+		{
+			name: "all parameters",
+			queryParams: map[string]string{
+				"base":        "uosmo",
+				"quote":       "uion",
+				"minAmount":   "100",
+				"maxAmount":   "100000",
+				"levels":      "4",
+				"singleRoute": "true",
+			},
+			expectedMinAmount: osmomath.NewInt(100),
+			expectedMaxAmount: osmomath.NewInt(100000),
+			expectedLevels:    4,
+		},
+		{
+			name: "max amount only",
+			queryParams: map[string]string{
+				"base":      "uosmo",
+				"quote":     "uion",
+				"maxAmount": "5000000000",
+			},
+			expectedMinAmount: osmomath.NewInt(5000),
+			expectedMaxAmount: osmomath.NewInt(5000000000),
+			expectedLevels:    types.DefaultDepthLevels,
+		},
+		{
+			name: "missing base",
+			queryParams: map[string]string{
+				"quote": "uion",
+			},
+			expectedValidationErr: types.ErrBaseNotSpecified,
+		},
+		{
+			name: "missing quote",
+			queryParams: map[string]string{
+				"base": "uosmo",
+			},
+			expectedValidationErr: types.ErrQuoteNotSpecified,
+		},
+		{
+			name: "invalid min amount",
+			queryParams: map[string]string{
+				"base":      "uosmo",
+				"quote":     "uion",
+				"minAmount": "abc",
+			},
+			expectedUnmarshalErr: true,
+		},
+		{
+			name: "max amount less than min amount",
+			queryParams: map[string]string{
+				"base":      "uosmo",
+				"quote":     "uion",
+				"minAmount": "1000",
+				"maxAmount": "100",
+			},
+			expectedValidationErr: types.ErrDepthAmountNotValid,
+		},
+		{
+			name: "too many levels",
+			queryParams: map[string]string{
+				"base":   "uosmo",
+				"quote":  "uion",
+				"levels": "51",
+			},
+			expectedValidationErr: types.ErrDepthLevelsNotValid,
+		},
+		{
+			name: "single level",
+			queryParams: map[string]string{
+				"base":   "uosmo",
+				"quote":  "uion",
+				"levels": "1",
+			},
+			expectedValidationErr: types.ErrDepthLevelsNotValid,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(echo.GET, "/", nil)
+			q := req.URL.Query()
+			for k, v := range tc.queryParams {
+				q.Add(k, v)
+			}
+			req.URL.RawQuery = q.Encode()
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			var result types.GetDepthRequest
+			err := (&result).UnmarshalHTTPRequest(c)
+			if tc.expectedUnmarshalErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			err = result.Validate()
+			if tc.expectedValidationErr != nil {
+				assert.ErrorIs(t, err, tc.expectedValidationErr)
+				return
+			}
+			assert.NoError(t, err)
+
+			amounts, err := result.Amounts(defaultMinAmount)
+			require.NoError(t, err)
+			require.Len(t, amounts, tc.expectedLevels)
+			assert.Equal(t, tc.expectedMinAmount.String(), amounts[0].String())
+			assert.Equal(t, tc.expectedMaxAmount.String(), amounts[len(amounts)-1].String())
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package types
+
+import (
+	"github.com/labstack/echo/v4"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	"github.com/osmosis-labs/sqs/domain"
+)
+
+// GetMaxAmountForImpactRequest represents the request for the /router/max-amount-for-impact endpoint.
+type GetMaxAmountForImpactRequest struct {
+	TokenInDenom  string
+	TokenOutDenom string
+	// MaxPriceImpact is the max absolute price impact of the quote.
+	MaxPriceImpact osmomath.Dec
+	// ExactOut searches over the token out amount with the exact amount out swap method
+	// instead of the token in amount with the exact amount in swap method.
+	ExactOut    bool
+	SingleRoute bool
+}
+
+// GetMaxAmountForImpactResponse represents the response of the /router/max-amount-for-impact endpoint.
+type GetMaxAmountForImpactResponse struct {
+	// Amount is the largest amount of the given token found within the max price impact.
+	// That is, of the token in for the exact amount in and of the token out for the exact amount out swap method.
+	Amount sdk.Coin `json:"amount"`
+	// PriceImpact is the price impact achieved by the quote.
+	PriceImpact osmomath.Dec `json:"price_impact"`
+	// Quote is the optimal quote for the amount.
+	Quote domain.Quote `json:"quote"`
+}
+
+// UnmarshalHTTPRequest unmarshals the HTTP request to GetMaxAmountForImpactRequest.
+// It returns an error if the max price impact or any of the boolean parameters fails to parse.
+func (r *GetMaxAmountForImpactRequest) UnmarshalHTTPRequest(c echo.Context) error {
+	var err error
+
+	r.TokenInDenom = c.QueryParam("tokenInDenom")
+	r.TokenOutDenom = c.QueryParam("tokenOutDenom")
+
+	if maxPriceImpact := c.QueryParam("maxPriceImpact"); maxPriceImpact != "" {
+		r.MaxPriceImpact, err = osmomath.NewDecFromStr(maxPriceImpact)
+		if err != nil {
+			return ErrMaxPriceImpactNotValid
+		}
+	}
+
+	r.ExactOut, err = domain.ParseBooleanQueryParam(c, "exactOut")
+	if err != nil {
+		return err
+	}
+
+	r.SingleRoute, err = domain.ParseBooleanQueryParam(c, "singleRoute")
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate validates the GetMaxAmountForImpactRequest.
+func (r *GetMaxAmountForImpactRequest) Validate() error {
+	if r.TokenInDenom == "" {
+		return ErrTokenInDenomNotSpecified
+	}
+
+	if r.TokenOutDenom == "" {
+		return ErrTokenOutDenomNotSpecified
+	}
+
+	if err := domain.ValidateInputDenoms(r.TokenInDenom, r.TokenOutDenom); err != nil {
+		return err
+	}
+
+	if r.MaxPriceImpact.IsNil() || !r.MaxPriceImpact.IsPositive() || r.MaxPriceImpact.GTE(osmomath.OneDec()) {
+		return ErrMaxPriceImpactNotValid
+	}
+
+	return nil
+}
+
+// SwapMethod returns the swap method of the request.
+func (r *GetMaxAmountForImpactRequest) SwapMethod() domain.TokenSwapMethod {
+	if r.ExactOut {
+		return domain.TokenSwapMethodExactOut
+	}
+	return domain.TokenSwapMethodExactIn
+}
+
+// RouterOptions returns the router options overridden by the request.
+func (r *GetMaxAmountForImpactRequest) RouterOptions() []domain.RouterOption {
+	var routerOpts []domain.RouterOption
+	if r.SingleRoute {
+		routerOpts = append(routerOpts, domain.WithMaxSplitRoutes(domain.DisableSplitRoutes))
+	}
+	return routerOpts
+}
+
+// NewGetMaxAmountForImpactResponse returns the response for the quote found within the max price impact.
+//
+// CONTRACT: the quote has been prepared with PrepareResult.
+func NewGetMaxAmountForImpactResponse(quote domain.Quote) *GetMaxAmountForImpactResponse {
+	return &GetMaxAmountForImpactResponse{
+		// For the exact amount out, the prepared quote returns the token out from GetAmountIn.
+		// See NewQuoteSpec for details.
+		Amount:      quote.GetAmountIn(),
+		PriceImpact: quote.GetPriceImpact(),
+		Quote:       quote,
+	}
+}
@@ -0,0 +1,151 @@
+package types_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	"github.com/osmosis-labs/sqs/domain"
+	"github.com/osmosis-labs/sqs/router/types"
+)
+
+// TestGetMaxAmountForImpactRequestUnmarshal tests the UnmarshalHTTPRequest and Validate methods of GetMaxAmountForImpactRequest.
+func TestGetMaxAmountForImpactRequestUnmarshal(t *testing.T) {
+	testcases := []struct {
+		name                  string
+		queryParams           map[string]string
+		expectedResult        *types.GetMaxAmountForImpactRequest
+		expectedSwapMethod    domain.TokenSwapMethod
+		expectedUnmarshalErr  bool
+		expectedValidationErr error
+	}{
+		{
+			name: "valid exact amount in request",
+			queryParams: map[string]string{
+				"tokenInDenom":   "uosmo",
+				"tokenOutDenom":  "uion",
+				"maxPriceImpact": "0.01",
+			},
+			expectedResult: &types.GetMaxAmountForImpactRequest{
+				TokenInDenom:   "uosmo",
+				TokenOutDenom:  "uion",
+				MaxPriceImpact: osmomath.MustNewDecFromStr("0.01"),
+			},
+			expectedSwapMethod: domain.TokenSwapMethodExactIn,
+		},
+		{
+			name: "valid exact amount out single route request",
+			queryParams: map[string]string{
+				"tokenInDenom":   "uosmo",
+				"tokenOutDenom":  "uion",
+				"maxPriceImpact": "0.05",
+				"exactOut":       "true",
+				"singleRoute":    "true",
+			},
+			expectedResult: &types.GetMaxAmountForImpactRequest{
+				TokenInDenom:   "uosmo",
+				TokenOutDenom:  "uion",
+				MaxPriceImpact: osmomath.MustNewDecFromStr("0.05"),
+				ExactOut:       true,
+				SingleRoute:    true,
+			},
+			expectedSwapMethod: domain.TokenSwapMethodExactOut,
+		},
+		{
+			name: "invalid max price impact",
+			queryParams: map[string]string{
+				"tokenInDenom":   "uosmo",
+				"tokenOutDenom":  "uion",
+				"maxPriceImpact": "abc",
+			},
+			expectedUnmarshalErr: true,
+		},
+		{
+			name: "invalid exact out",
+			queryParams: map[string]string{
+				"tokenInDenom":   "uosmo",
+				"tokenOutDenom":  "uion",
+				"maxPriceImpact": "0.01",
+				"exactOut":       "maybe",
+			},
+			expectedUnmarshalErr: true,
+		},
+		{
+			name: "missing token in denom",
+			queryParams: map[string]string{
+				"tokenOutDenom":  "uion",
+				"maxPriceImpact": "0.01",
+			},
+			expectedValidationErr: types.ErrTokenInDenomNotSpecified,
+		},
+		{
+			name: "missing token out denom",
+			queryParams: map[string]string{
+				"tokenInDenom":   "uosmo",
+				"maxPriceImpact": "0.01",
+			},
+			expectedValidationErr: types.ErrTokenOutDenomNotSpecified,
+		},
+		{
+			name: "missing max price impact",
+			queryParams: map[string]string{
+				"tokenInDenom":  "uosmo",
+				"tokenOutDenom": "uion",
+			},
+			expectedValidationErr: types.ErrMaxPriceImpactNotValid,
+		},
+		{
+			name: "zero max price impact",
+			queryParams: map[string]string{
+				"tokenInDenom":   "uosmo",
+				"tokenOutDenom":  "uion",
+				"maxPriceImpact": "0",
+			},
+			expectedValidationErr: types.ErrMaxPriceImpactNotValid,
+		},
+		{
+			name: "max price impact of one",
+			queryParams: map[string]string{
+				"tokenInDenom":   "uosmo",
+				"tokenOutDenom":  "uion",
+				"maxPriceImpact": "1",
+			},
+			expectedValidationErr: types.ErrMaxPriceImpactNotValid,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(echo.GET, "/", nil)
+			q := req.URL.Query()
+			for k, v := range tc.queryParams {
+				q.Add(k, v)
+			}
+			req.URL.RawQuery = q.Encode()
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			var result types.GetMaxAmountForImpactRequest
+			err := (&result).UnmarshalHTTPRequest(c)
+			if tc.expectedUnmarshalErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			err = result.Validate()
+			if tc.expectedValidationErr != nil {
+				assert.ErrorIs(t, err, tc.expectedValidationErr)
+				return
+			}
+			assert.NoError(t, err)
+
+			assert.Equal(t, tc.expectedResult, &result)
+			assert.Equal(t, tc.expectedSwapMethod, result.SwapMethod())
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package types
+
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	"github.com/osmosis-labs/sqs/domain"
+)
+
+// GetQuoteByIDRequest represents the quote re-evaluation request for the /router/quote/:id endpoint.
+type GetQuoteByIDRequest struct {
+	QuoteID string
+	Spec    domain.QuoteSpec
+}
+
+// GetQuoteByIDResponse represents the response of the /router/quote/:id endpoint.
+// The quoted amount is the amount out for the exact amount in and the amount in
+// for the exact amount out swap method.
+type GetQuoteByIDResponse struct {
+	QuoteID string `json:"quote_id"`
+	// QuotedHeight is the height of the state the quote was originally computed against.
+	QuotedHeight uint64 `json:"quoted_height"`
+	// Height is the height of the state the quote was re-evaluated against.
+	Height uint64 `json:"height"`
+	// Quote is the quote over the same routes and split re-evaluated against the current state.
+	Quote domain.Quote `json:"quote"`
+	// QuotedAmount is the originally quoted amount.
+	QuotedAmount osmomath.Int `json:"quoted_amount"`
+	// Drift is the re-evaluated amount minus the quoted amount.
+	// For the exact amount in swap method, negative drift means that less token out is received.
+	// For the exact amount out swap method, positive drift means that more token in is required.
+	Drift osmomath.Int `json:"drift"`
+	// DriftRatio is the drift relative to the quoted amount. Zero if the quoted amount is zero.
+	DriftRatio osmomath.Dec `json:"drift_ratio"`
+}
+
+// UnmarshalHTTPRequest unmarshals the HTTP request to GetQuoteByIDRequest.
+// It returns an error if the quote ID fails to parse.
+func (r *GetQuoteByIDRequest) UnmarshalHTTPRequest(c echo.Context) error {
+	r.QuoteID = c.Param("id")
+
+	spec, err := domain.ParseQuoteID(r.QuoteID)
+	if err != nil {
+		return err
+	}
+	r.Spec = spec
+
+	return nil
+}
+
+// Validate validates the GetQuoteByIDRequest.
+// The quote ID is client-provided, so the number of routes and pools re-evaluated is limited
+// the same way as for the requested route constraints.
+func (r *GetQuoteByIDRequest) Validate() error {
+	if len(r.Spec.Routes) > MaxRequestedRoutes {
+		return ErrQuoteIDRoutesNotValid
+	}
+
+	for _, route := range r.Spec.Routes {
+		if len(route.PoolIDs) > MaxRequestedPoolsPerRoute {
+			return ErrQuoteIDRoutesNotValid
+		}
+	}
+
+	return nil
+}
+
+// NewGetQuoteByIDResponse returns the response for the re-evaluated quote.
+//
+// CONTRACT: the quote has been prepared with PrepareResult and stamped with the quote ID.
+func NewGetQuoteByIDResponse(r *GetQuoteByIDRequest, quote domain.Quote) *GetQuoteByIDResponse {
+	drift := quote.GetAmountOut().Sub(r.Spec.QuotedAmount)
+
+	driftRatio := osmomath.ZeroDec()
+	if !r.Spec.QuotedAmount.IsZero() {
+		driftRatio = drift.ToLegacyDec().QuoMut(r.Spec.QuotedAmount.ToLegacyDec())
+	}
+
+	return &GetQuoteByIDResponse{
+		QuoteID:      r.QuoteID,
+		QuotedHeight: r.Spec.Height,
+		Height:       quote.GetHeight(),
+		Quote:        quote,
+		QuotedAmount: r.Spec.QuotedAmount,
+		Drift:        drift,
+		DriftRatio:   driftRatio,
+	}
+}
@@ -0,0 +1,124 @@
+package types_test
+
+import (
+	"encoding/base64"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	"github.com/osmosis-labs/sqs/domain"
+	"github.com/osmosis-labs/sqs/router/types"
+)
+
+// TestGetQuoteByIDRequestUnmarshal tests the UnmarshalHTTPRequest and Validate methods of GetQuoteByIDRequest.
+func TestGetQuoteByIDRequestUnmarshal(t *testing.T) {
+	validSpec := domain.QuoteSpec{
+		SwapMethod:   domain.TokenSwapMethodExactIn,
+		Height:       100,
+		Token:        sdk.NewCoin("ust", osmomath.NewInt(1000)),
+		QuotedAmount: osmomath.NewInt(990),
+		Routes: []domain.QuoteSpecRoute{
+			{PoolIDs: []uint64{1, 2}, Denoms: []string{"uosmo", "usdc"}, Amount: osmomath.NewInt(600)},
+			{PoolIDs: []uint64{3}, Denoms: []string{"usdc"}, Amount: osmomath.NewInt(400)},
+		},
+	}
+
+	noRoutesSpec := validSpec
+	noRoutesSpec.Routes = nil
+
+	mismatchedDenomsSpec := validSpec
+	mismatchedDenomsSpec.Routes = []domain.QuoteSpecRoute{
+		{PoolIDs: []uint64{1, 2}, Denoms: []string{"usdc"}, Amount: osmomath.NewInt(1000)},
+	}
+
+	tooManyPoolsSpec := validSpec
+	tooManyPoolsSpec.Routes = []domain.QuoteSpecRoute{
+		{PoolIDs: []uint64{1, 2, 3, 4, 5, 6, 7}, Denoms: []string{"a", "b", "c", "d", "e", "f", "usdc"}, Amount: osmomath.NewInt(1000)},
+	}
+
+	mustID := func(spec domain.QuoteSpec) string {
+		id, err := spec.ID()
+		require.NoError(t, err)
+		return id
+	}
+
+	testcases := []struct {
+		name                  string
+		quoteID               string
+		expectedSpec          domain.QuoteSpec
+		expectedUnmarshalErr  error
+		expectedValidationErr error
+	}{
+		{
+			name:         "valid quote ID",
+			quoteID:      mustID(validSpec),
+			expectedSpec: validSpec,
+		},
+		{
+			name:                 "invalid encoding",
+			quoteID:              "not base64!",
+			expectedUnmarshalErr: domain.ErrQuoteIDNotValid,
+		},
+		{
+			name:                 "invalid JSON",
+			quoteID:              base64.RawURLEncoding.EncodeToString([]byte("{invalid")),
+			expectedUnmarshalErr: domain.ErrQuoteIDNotValid,
+		},
+		{
+			name:                 "no routes",
+			quoteID:              mustID(noRoutesSpec),
+			expectedUnmarshalErr: domain.ErrQuoteIDNotValid,
+		},
+		{
+			name:                 "mismatched pool IDs and denoms",
+			quoteID:              mustID(mismatchedDenomsSpec),
+			expectedUnmarshalErr: domain.ErrQuoteIDNotValid,
+		},
+		{
+			name:                  "too many pools per route",
+			quoteID:               mustID(tooManyPoolsSpec),
+			expectedValidationErr: types.ErrQuoteIDRoutesNotValid,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(echo.GET, "/", nil)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.SetParamNames("id")
+			c.SetParamValues(tc.quoteID)
+
+			var result types.GetQuoteByIDRequest
+			err := (&result).UnmarshalHTTPRequest(c)
+			if tc.expectedUnmarshalErr != nil {
+				assert.ErrorIs(t, err, tc.expectedUnmarshalErr)
+				return
+			}
+			assert.NoError(t, err)
+
+			err = result.Validate()
+			if tc.expectedValidationErr != nil {
+				assert.ErrorIs(t, err, tc.expectedValidationErr)
+				return
+			}
+			assert.NoError(t, err)
+
+			assert.Equal(t, tc.quoteID, result.QuoteID)
+			// The quote ID is deterministic.
+			assert.Equal(t, tc.quoteID, mustID(result.Spec))
+			assert.Equal(t, tc.expectedSpec.Height, result.Spec.Height)
+			assert.Equal(t, tc.expectedSpec.Token.String(), result.Spec.Token.String())
+			assert.Equal(t, tc.expectedSpec.QuotedAmount.String(), result.Spec.QuotedAmount.String())
+			assert.Equal(t, tc.expectedSpec.Routes[0].PoolIDs, result.Spec.Routes[0].PoolIDs)
+			assert.Equal(t, tc.expectedSpec.Routes[1].Denoms, result.Spec.Routes[1].Denoms)
+		})
+	}
+}
@@ -1,12 +1,25 @@
 package types
 
 import (
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/osmosis-labs/sqs/domain"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/labstack/echo/v4"
 )
 
+const (
+	// MaxRequestedPoolsPerRoute is the maximum number of pools per route that may be requested.
+	MaxRequestedPoolsPerRoute = 6
+	// MaxRequestedRoutes is the maximum number of candidate routes that may be requested.
+	MaxRequestedRoutes = 50
+	// MaxRequestedComputeDeadlineMs is the maximum quote compute deadline in milliseconds that may be requested.
+	MaxRequestedComputeDeadlineMs = 10_000
+)
+
 // GetQuoteRequest represents swap quote request for the /router/quote endpoint.
 type GetQuoteRequest struct {
 	TokenIn        *sdk.Coin
@@ -16,6 +29,48 @@ type GetQuoteRequest struct {
 	SingleRoute    bool
 	HumanDenoms    bool
 	ApplyExponents bool
+	// SplitIncrements overrides the number of increments used for computing split routes.
+	// Zero means the configured default.
+	SplitIncrements int
+	// SplitRefinementRounds overrides the number of refinement rounds used for computing split routes.
+	// Zero means the configured default.
+	SplitRefinementRounds int
+
+	// ExcludePoolIDs are the IDs of the pools that the routes must not go through.
+	ExcludePoolIDs []uint64
+	// OnlyPoolTypes restricts the routes to the pools of the given types.
+	OnlyPoolTypes []domain.CandidateRoutePoolType
+	// ExcludeDenoms are the intermediary denoms that the routes must not go through.
+	ExcludeDenoms []string
+	// MaxPoolsPerRoute overrides the maximum number of pools per route.
+	// Zero means the configured default.
+	MaxPoolsPerRoute int
+	// MaxRoutes overrides the maximum number of candidate routes.
+	// Zero means the configured default.
+	MaxRoutes int
+	// MinLiquidityCap overrides the minimum liquidity capitalization of the pools in the routes.
+	// Nil means the configured default.
+	MinLiquidityCap *uint64
+	// CandidateRouteAlgorithm overrides the candidate route search algorithm.
+	// Empty means the configured default.
+	CandidateRouteAlgorithm domain.CandidateRouteAlgorithm
+
+	// Explain requests the description of how the quote was computed to be returned with the quote.
+	Explain bool
+
+	// GasAware requests the routes to be ranked and split by the amount out net of the estimated gas cost.
+	// Only supported for the exact amount in swap method.
+	GasAware bool
+
+	// ComputeDeadlineMs overrides the quote compute deadline in milliseconds.
+	// Zero means the configured default. Only supported for the exact amount in swap method.
+	ComputeDeadlineMs int
+}
+
+// GetQuoteExplainResponse represents the response of the /router/quote endpoint when the explain is requested.
+type GetQuoteExplainResponse struct {
+	Quote   domain.Quote         `json:"quote"`
+	Explain *domain.QuoteExplain `json:"explain"`
 }
 
 // UnmarshalHTTPRequest unmarshals the HTTP request to GetQuoteRequest.
@@ -32,6 +87,16 @@ func (r *GetQuoteRequest) UnmarshalHTTPRequest(c echo.Context) error {
 		return err
 	}
 
+	r.Explain, err = domain.ParseBooleanQueryParam(c, "explain")
+	if err != nil {
+		return err
+	}
+
+	r.GasAware, err = domain.ParseBooleanQueryParam(c, "gasAware")
+	if err != nil {
+		return err
+	}
+
 	if tokenIn := c.QueryParam("tokenIn"); tokenIn != "" {
 		tokenInCoin, err := sdk.ParseCoinNormalized(tokenIn)
 		if err != nil {
@@ -48,12 +113,145 @@ func (r *GetQuoteRequest) UnmarshalHTTPRequest(c echo.Context) error {
 		r.TokenOut = &tokenOutCoin
 	}
 
+	if splitIncrements := c.QueryParam("splitIncrements"); splitIncrements != "" {
+		r.SplitIncrements, err = strconv.Atoi(splitIncrements)
+		if err != nil {
+			return ErrSplitIncrementsNotValid
+		}
+	}
+
+	if splitRefinementRounds := c.QueryParam("splitRefinementRounds"); splitRefinementRounds != "" {
+		r.SplitRefinementRounds, err = strconv.Atoi(splitRefinementRounds)
+		if err != nil {
+			return ErrSplitRefinementRoundsNotValid
+		}
+	}
+
+	if computeDeadlineMs := c.QueryParam("computeDeadlineMs"); computeDeadlineMs != "" {
+		r.ComputeDeadlineMs, err = strconv.Atoi(computeDeadlineMs)
+		if err != nil {
+			return ErrComputeDeadlineNotValid
+		}
+	}
+
+	if excludePoolIDs := c.QueryParam("excludePoolIDs"); excludePoolIDs != "" {
+		r.ExcludePoolIDs, err = domain.ParseNumbers(excludePoolIDs)
+		if err != nil {
+			return ErrExcludePoolIDsNotValid
+		}
+	}
+
+	if onlyPoolTypes := c.QueryParam("onlyPoolTypes"); onlyPoolTypes != "" {
+		for _, poolType := range strings.Split(onlyPoolTypes, ",") {
+			r.OnlyPoolTypes = append(r.OnlyPoolTypes, domain.CandidateRoutePoolType(strings.TrimSpace(poolType)))
+		}
+	}
+
+	if excludeDenoms := c.QueryParam("excludeDenoms"); excludeDenoms != "" {
+		for _, denom := range strings.Split(excludeDenoms, ",") {
+			r.ExcludeDenoms = append(r.ExcludeDenoms, strings.TrimSpace(denom))
+		}
+	}
+
+	if maxPoolsPerRoute := c.QueryParam("maxPoolsPerRoute"); maxPoolsPerRoute != "" {
+		r.MaxPoolsPerRoute, err = strconv.Atoi(maxPoolsPerRoute)
+		if err != nil {
+			return ErrMaxPoolsPerRouteNotValid
+		}
+	}
+
+	if maxRoutes := c.QueryParam("maxRoutes"); maxRoutes != "" {
+		r.MaxRoutes, err = strconv.Atoi(maxRoutes)
+		if err != nil {
+			return ErrMaxRoutesNotValid
+		}
+	}
+
+	if minLiquidityCap := c.QueryParam("minLiquidityCap"); minLiquidityCap != "" {
+		minLiquidityCapValue, err := strconv.ParseUint(minLiquidityCap, 10, 64)
+		if err != nil {
+			return ErrMinLiquidityCapNotValid
+		}
+		r.MinLiquidityCap = &minLiquidityCapValue
+	}
+
+	r.CandidateRouteAlgorithm = domain.CandidateRouteAlgorithm(c.QueryParam("candidateRouteAlgorithm"))
+
 	r.TokenInDenom = c.QueryParam("tokenInDenom")
 	r.TokenOutDenom = c.QueryParam("tokenOutDenom")
 
 	return nil
 }
 
+// RouterOptions returns the router options overridden by the request.
+func (r *GetQuoteRequest) RouterOptions() []domain.RouterOption {
+	var routerOpts []domain.RouterOption
+	if r.SingleRoute {
+		routerOpts = append(routerOpts, domain.WithMaxSplitRoutes(domain.DisableSplitRoutes))
+	}
+
+	if r.SplitIncrements > 0 {
+		routerOpts = append(routerOpts, domain.WithSplitIncrements(r.SplitIncrements))
+	}
+
+	if r.SplitRefinementRounds > 0 {
+		routerOpts = append(routerOpts, domain.WithSplitRefinementRounds(r.SplitRefinementRounds))
+	}
+
+	if r.ComputeDeadlineMs > 0 {
+		routerOpts = append(routerOpts, domain.WithComputeDeadline(time.Duration(r.ComputeDeadlineMs)*time.Millisecond))
+	}
+
+	if len(r.ExcludePoolIDs) > 0 {
+		poolIDFilter := domain.CandidateRoutePoolIDFilterOptionCb{
+			PoolIDsToSkip: make(map[uint64]struct{}, len(r.ExcludePoolIDs)),
+		}
+		for _, poolID := range r.ExcludePoolIDs {
+			poolIDFilter.PoolIDsToSkip[poolID] = struct{}{}
+		}
+		routerOpts = append(routerOpts, domain.WithCandidateRoutesPoolFiltersAnyOf(poolIDFilter.ShouldSkipPool))
+	}
+
+	if len(r.OnlyPoolTypes) > 0 {
+		routerOpts = append(routerOpts, domain.WithCandidateRoutesPoolTypes(r.OnlyPoolTypes...))
+	}
+
+	if len(r.ExcludeDenoms) > 0 {
+		routerOpts = append(routerOpts, domain.WithCandidateRoutesIntermediaryDenomsToSkip(r.ExcludeDenoms...))
+	}
+
+	if r.MaxPoolsPerRoute > 0 {
+		routerOpts = append(routerOpts, domain.WithMaxPoolsPerRoute(r.MaxPoolsPerRoute))
+	}
+
+	if r.MaxRoutes > 0 {
+		routerOpts = append(routerOpts, domain.WithMaxRoutes(r.MaxRoutes))
+	}
+
+	if r.MinLiquidityCap != nil {
+		routerOpts = append(routerOpts, domain.WithMinPoolLiquidityCap(*r.MinLiquidityCap), domain.WithDisableDynamicMinPoolLiquidityCap())
+	}
+
+	if r.CandidateRouteAlgorithm != "" {
+		routerOpts = append(routerOpts, domain.WithCandidateRouteAlgorithm(r.CandidateRouteAlgorithm))
+	}
+
+	// The cached routes are computed with the default constraints.
+	// As a result, the caches are bypassed for the requests overriding them.
+	if r.HasRouteConstraints() {
+		routerOpts = append(routerOpts, domain.WithDisableCache())
+	}
+
+	return routerOpts
+}
+
+// HasRouteConstraints returns true if the request overrides any of the default constraints
+// on the candidate routes or the algorithm searching for them.
+func (r *GetQuoteRequest) HasRouteConstraints() bool {
+	return len(r.ExcludePoolIDs) > 0 || len(r.OnlyPoolTypes) > 0 || len(r.ExcludeDenoms) > 0 ||
+		r.MaxPoolsPerRoute > 0 || r.MaxRoutes > 0 || r.MinLiquidityCap != nil || r.CandidateRouteAlgorithm != ""
+}
+
 // SwapMethod returns the swap method of the request.
 // Request may contain data for both swap methods, only one of them should be specified, otherwise it's invalid.
 func (r *GetQuoteRequest) SwapMethod() domain.TokenSwapMethod {
@@ -95,5 +293,49 @@ func (r *GetQuoteRequest) Validate() error {
 		a, b = r.TokenOut.Denom, r.TokenInDenom
 	}
 
+	if r.SplitIncrements < 0 || r.SplitIncrements > domain.MaxSplitIncrements {
+		return ErrSplitIncrementsNotValid
+	}
+
+	if r.SplitRefinementRounds < 0 || r.SplitRefinementRounds > domain.MaxSplitRefinementRounds {
+		return ErrSplitRefinementRoundsNotValid
+	}
+
+	if r.ComputeDeadlineMs < 0 || r.ComputeDeadlineMs > MaxRequestedComputeDeadlineMs {
+		return ErrComputeDeadlineNotValid
+	}
+
+	for _, poolType := range r.OnlyPoolTypes {
+		if !poolType.IsValid() {
+			return ErrOnlyPoolTypesNotValid
+		}
+	}
+
+	for _, denom := range r.ExcludeDenoms {
+		if denom == "" {
+			return ErrExcludeDenomsNotValid
+		}
+	}
+
+	if r.MaxPoolsPerRoute < 0 || r.MaxPoolsPerRoute > MaxRequestedPoolsPerRoute {
+		return ErrMaxPoolsPerRouteNotValid
+	}
+
+	if r.MaxRoutes < 0 || r.MaxRoutes > MaxRequestedRoutes {
+		return ErrMaxRoutesNotValid
+	}
+
+	if r.CandidateRouteAlgorithm != "" && !r.CandidateRouteAlgorithm.IsValid() {
+		return ErrCandidateRouteAlgorithmNotValid
+	}
+
+	if r.GasAware && method != domain.TokenSwapMethodExactIn {
+		return ErrGasAwareNotValid
+	}
+
+	if r.ComputeDeadlineMs > 0 && method != domain.TokenSwapMethodExactIn {
+		return ErrComputeDeadlineNotSupported
+	}
+
 	return domain.ValidateInputDenoms(a, b)
 }
@@ -62,6 +62,42 @@ func TestGetQuoteRequestUnmarshal(t *testing.T) {
 			expectedResult: nil,
 			expectedError:  true,
 		},
+		{
+			name: "valid request with explain",
+			queryParams: map[string]string{
+				"tokenIn":       "1000ust",
+				"tokenOutDenom": "usdc",
+				"explain":       "true",
+			},
+			expectedResult: &types.GetQuoteRequest{
+				TokenIn:       &sdk.Coin{Denom: "ust", Amount: sdk.NewInt(1000)},
+				TokenOutDenom: "usdc",
+				Explain:       true,
+			},
+		},
+		{
+			name: "valid request with gasAware",
+			queryParams: map[string]string{
+				"tokenIn":       "1000ust",
+				"tokenOutDenom": "usdc",
+				"gasAware":      "true",
+			},
+			expectedResult: &types.GetQuoteRequest{
+				TokenIn:       &sdk.Coin{Denom: "ust", Amount: sdk.NewInt(1000)},
+				TokenOutDenom: "usdc",
+				GasAware:      true,
+			},
+		},
+		{
+			name: "invalid explain param",
+			queryParams: map[string]string{
+				"tokenIn":       "1000ust",
+				"tokenOutDenom": "usdc",
+				"explain":       "invalid",
+			},
+			expectedResult: nil,
+			expectedError:  true,
+		},
 		{
 			name: "invalid tokenIn param",
 			queryParams: map[string]string{
@@ -84,6 +120,109 @@ func TestGetQuoteRequestUnmarshal(t *testing.T) {
 			expectedResult: nil,
 			expectedError:  true,
 		},
+		{
+			name: "valid request with split options",
+			queryParams: map[string]string{
+				"tokenIn":               "1000ust",
+				"tokenOutDenom":         "usdc",
+				"splitIncrements":       "20",
+				"splitRefinementRounds": "8",
+			},
+			expectedResult: &types.GetQuoteRequest{
+				TokenIn:               &sdk.Coin{Denom: "ust", Amount: sdk.NewInt(1000)},
+				TokenOutDenom:         "usdc",
+				SplitIncrements:       20,
+				SplitRefinementRounds: 8,
+			},
+		},
+		{
+			name: "invalid splitIncrements param",
+			queryParams: map[string]string{
+				"tokenIn":         "1000ust",
+				"tokenOutDenom":   "usdc",
+				"splitIncrements": "invalid",
+			},
+			expectedResult: nil,
+			expectedError:  true,
+		},
+		{
+			name: "invalid splitRefinementRounds param",
+			queryParams: map[string]string{
+				"tokenIn":               "1000ust",
+				"tokenOutDenom":         "usdc",
+				"splitRefinementRounds": "1.5",
+			},
+			expectedResult: nil,
+			expectedError:  true,
+		},
+		{
+			name: "valid request with computeDeadlineMs",
+			queryParams: map[string]string{
+				"tokenIn":           "1000ust",
+				"tokenOutDenom":     "usdc",
+				"computeDeadlineMs": "250",
+			},
+			expectedResult: &types.GetQuoteRequest{
+				TokenIn:           &sdk.Coin{Denom: "ust", Amount: sdk.NewInt(1000)},
+				TokenOutDenom:     "usdc",
+				ComputeDeadlineMs: 250,
+			},
+		},
+		{
+			name: "invalid computeDeadlineMs param",
+			queryParams: map[string]string{
+				"tokenIn":           "1000ust",
+				"tokenOutDenom":     "usdc",
+				"computeDeadlineMs": "1s",
+			},
+			expectedResult: nil,
+			expectedError:  true,
+		},
+		{
+			name: "valid request with route constraints",
+			queryParams: map[string]string{
+				"tokenIn":                 "1000ust",
+				"tokenOutDenom":           "usdc",
+				"excludePoolIDs":          "1,1135",
+				"onlyPoolTypes":           "balancer, concentrated",
+				"excludeDenoms":           "uion,uatom",
+				"maxPoolsPerRoute":        "2",
+				"maxRoutes":               "10",
+				"minLiquidityCap":         "0",
+				"candidateRouteAlgorithm": "price-aware",
+			},
+			expectedResult: &types.GetQuoteRequest{
+				TokenIn:                 &sdk.Coin{Denom: "ust", Amount: sdk.NewInt(1000)},
+				TokenOutDenom:           "usdc",
+				ExcludePoolIDs:          []uint64{1, 1135},
+				OnlyPoolTypes:           []domain.CandidateRoutePoolType{domain.CandidateRoutePoolTypeBalancer, domain.CandidateRoutePoolTypeConcentrated},
+				ExcludeDenoms:           []string{"uion", "uatom"},
+				MaxPoolsPerRoute:        2,
+				MaxRoutes:               10,
+				MinLiquidityCap:         new(uint64),
+				CandidateRouteAlgorithm: domain.CandidateRouteAlgorithmPriceAware,
+			},
+		},
+		{
+			name: "invalid excludePoolIDs param",
+			queryParams: map[string]string{
+				"tokenIn":        "1000ust",
+				"tokenOutDenom":  "usdc",
+				"excludePoolIDs": "1,abc",
+			},
+			expectedResult: nil,
+			expectedError:  true,
+		},
+		{
+			name: "invalid minLiquidityCap param",
+			queryParams: map[string]string{
+				"tokenIn":         "1000ust",
+				"tokenOutDenom":   "usdc",
+				"minLiquidityCap": "-1",
+			},
+			expectedResult: nil,
+			expectedError:  true,
+		},
 	}
 
 	for _, tc := range testcases {
@@ -176,6 +315,50 @@ func TestGetQuoteRequestSwapMethod(t *testing.T) {
 	}
 }
 
+// TestGetQuoteRequestHasRouteConstraints tests the HasRouteConstraints method of GetQuoteRequest.
+func TestGetQuoteRequestHasRouteConstraints(t *testing.T) {
+	minLiquidityCap := uint64(0)
+
+	testcases := []struct {
+		name     string
+		request  *types.GetQuoteRequest
+		expected bool
+	}{
+		{
+			name:     "no constraints",
+			request:  &types.GetQuoteRequest{},
+			expected: false,
+		},
+		{
+			name:     "excluded pool IDs",
+			request:  &types.GetQuoteRequest{ExcludePoolIDs: []uint64{1}},
+			expected: true,
+		},
+		{
+			name:     "min liquidity cap",
+			request:  &types.GetQuoteRequest{MinLiquidityCap: &minLiquidityCap},
+			expected: true,
+		},
+		{
+			name:     "candidate route algorithm",
+			request:  &types.GetQuoteRequest{CandidateRouteAlgorithm: domain.CandidateRouteAlgorithmPriceAware},
+			expected: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.request.HasRouteConstraints())
+
+			options := domain.RouterOptions{}
+			for _, opt := range tc.request.RouterOptions() {
+				opt(&options)
+			}
+			assert.Equal(t, tc.expected, options.DisableCache)
+		})
+	}
+}
+
 // TestGetQuoteRequestValidate tests the Validate method of GetQuoteRequest.
 func TestGetQuoteRequestValidate(t *testing.T) {
 	testcases := []struct {
@@ -231,6 +414,112 @@ func TestGetQuoteRequestValidate(t *testing.T) {
 				DenomB: "usdt",
 			},
 		},
+		{
+			name: "invalid request with split increments above maximum",
+			request: &types.GetQuoteRequest{
+				TokenIn:         &sdk.Coin{Denom: "ust", Amount: sdk.NewInt(1000)},
+				TokenOutDenom:   "usdc",
+				SplitIncrements: domain.MaxSplitIncrements + 1,
+			},
+			expectedError: types.ErrSplitIncrementsNotValid,
+		},
+		{
+			name: "invalid request with negative split refinement rounds",
+			request: &types.GetQuoteRequest{
+				TokenIn:               &sdk.Coin{Denom: "ust", Amount: sdk.NewInt(1000)},
+				TokenOutDenom:         "usdc",
+				SplitRefinementRounds: -1,
+			},
+			expectedError: types.ErrSplitRefinementRoundsNotValid,
+		},
+		{
+			name: "invalid request with unknown pool type",
+			request: &types.GetQuoteRequest{
+				TokenIn:       &sdk.Coin{Denom: "ust", Amount: sdk.NewInt(1000)},
+				TokenOutDenom: "usdc",
+				OnlyPoolTypes: []domain.CandidateRoutePoolType{domain.CandidateRoutePoolTypeBalancer, "unknown"},
+			},
+			expectedError: types.ErrOnlyPoolTypesNotValid,
+		},
+		{
+			name: "invalid request with empty exclude denom",
+			request: &types.GetQuoteRequest{
+				TokenIn:       &sdk.Coin{Denom: "ust", Amount: sdk.NewInt(1000)},
+				TokenOutDenom: "usdc",
+				ExcludeDenoms: []string{""},
+			},
+			expectedError: types.ErrExcludeDenomsNotValid,
+		},
+		{
+			name: "invalid request with max pools per route above maximum",
+			request: &types.GetQuoteRequest{
+				TokenIn:          &sdk.Coin{Denom: "ust", Amount: sdk.NewInt(1000)},
+				TokenOutDenom:    "usdc",
+				MaxPoolsPerRoute: types.MaxRequestedPoolsPerRoute + 1,
+			},
+			expectedError: types.ErrMaxPoolsPerRouteNotValid,
+		},
+		{
+			name: "invalid request with max routes above maximum",
+			request: &types.GetQuoteRequest{
+				TokenIn:       &sdk.Coin{Denom: "ust", Amount: sdk.NewInt(1000)},
+				TokenOutDenom: "usdc",
+				MaxRoutes:     types.MaxRequestedRoutes + 1,
+			},
+			expectedError: types.ErrMaxRoutesNotValid,
+		},
+		{
+			name: "invalid request with unknown candidate route algorithm",
+			request: &types.GetQuoteRequest{
+				TokenIn:                 &sdk.Coin{Denom: "ust", Amount: sdk.NewInt(1000)},
+				TokenOutDenom:           "usdc",
+				CandidateRouteAlgorithm: "unknown",
+			},
+			expectedError: types.ErrCandidateRouteAlgorithmNotValid,
+		},
+		{
+			name: "valid exact in request with gasAware",
+			request: &types.GetQuoteRequest{
+				TokenIn:       &sdk.Coin{Denom: "ust", Amount: sdk.NewInt(1000)},
+				TokenOutDenom: "usdc",
+				GasAware:      true,
+			},
+		},
+		{
+			name: "invalid exact out request with gasAware",
+			request: &types.GetQuoteRequest{
+				TokenOut:     &sdk.Coin{Denom: "ust", Amount: sdk.NewInt(1000)},
+				TokenInDenom: "usdc",
+				GasAware:     true,
+			},
+			expectedError: types.ErrGasAwareNotValid,
+		},
+		{
+			name: "valid exact in request with compute deadline",
+			request: &types.GetQuoteRequest{
+				TokenIn:           &sdk.Coin{Denom: "ust", Amount: sdk.NewInt(1000)},
+				TokenOutDenom:     "usdc",
+				ComputeDeadlineMs: types.MaxRequestedComputeDeadlineMs,
+			},
+		},
+		{
+			name: "invalid request with compute deadline above maximum",
+			request: &types.GetQuoteRequest{
+				TokenIn:           &sdk.Coin{Denom: "ust", Amount: sdk.NewInt(1000)},
+				TokenOutDenom:     "usdc",
+				ComputeDeadlineMs: types.MaxRequestedComputeDeadlineMs + 1,
+			},
+			expectedError: types.ErrComputeDeadlineNotValid,
+		},
+		{
+			name: "invalid exact out request with compute deadline",
+			request: &types.GetQuoteRequest{
+				TokenOut:          &sdk.Coin{Denom: "ust", Amount: sdk.NewInt(1000)},
+				TokenInDenom:      "usdc",
+				ComputeDeadlineMs: 250,
+			},
+			expectedError: types.ErrComputeDeadlineNotSupported,
+		},
 	}
 
 	for _, tc := range testcases {
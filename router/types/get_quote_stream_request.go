@@ -0,0 +1,70 @@
+package types
+
+import (
+	"strings"
+
+	"github.com/osmosis-labs/sqs/domain"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/labstack/echo/v4"
+)
+
+// GetQuoteStreamRequest represents the quote stream subscription request for the /router/quote-stream endpoint.
+// The i-th token in is quoted against the i-th token out denom.
+type GetQuoteStreamRequest struct {
+	TokenIn       []sdk.Coin
+	TokenOutDenom []string
+}
+
+// UnmarshalHTTPRequest unmarshals the HTTP request to GetQuoteStreamRequest.
+// It returns an error if any of the token in coins is invalid.
+func (r *GetQuoteStreamRequest) UnmarshalHTTPRequest(c echo.Context) error {
+	if tokenIn := c.QueryParam("tokenIn"); tokenIn != "" {
+		for _, tokenInStr := range strings.Split(tokenIn, ",") {
+			tokenInCoin, err := sdk.ParseCoinNormalized(strings.TrimSpace(tokenInStr))
+			if err != nil {
+				return ErrTokenInNotValid
+			}
+			r.TokenIn = append(r.TokenIn, tokenInCoin)
+		}
+	}
+
+	if tokenOutDenom := c.QueryParam("tokenOutDenom"); tokenOutDenom != "" {
+		for _, denom := range strings.Split(tokenOutDenom, ",") {
+			r.TokenOutDenom = append(r.TokenOutDenom, strings.TrimSpace(denom))
+		}
+	}
+
+	return nil
+}
+
+// Validate validates the GetQuoteStreamRequest.
+func (r *GetQuoteStreamRequest) Validate() error {
+	if len(r.TokenIn) == 0 {
+		return ErrTokenInNotSpecified
+	}
+
+	if len(r.TokenIn) != len(r.TokenOutDenom) {
+		return ErrNumOfTokenOutDenomMismatch
+	}
+
+	for i := range r.TokenIn {
+		if err := domain.ValidateInputDenoms(r.TokenIn[i].Denom, r.TokenOutDenom[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// QuoteStreamRequests returns the requests to subscribe to.
+func (r *GetQuoteStreamRequest) QuoteStreamRequests() []domain.QuoteStreamRequest {
+	requests := make([]domain.QuoteStreamRequest, 0, len(r.TokenIn))
+	for i := range r.TokenIn {
+		requests = append(requests, domain.QuoteStreamRequest{
+			TokenIn:       r.TokenIn[i],
+			TokenOutDenom: r.TokenOutDenom[i],
+		})
+	}
+	return requests
+}
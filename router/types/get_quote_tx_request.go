@@ -0,0 +1,58 @@
+package types
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	"github.com/labstack/echo/v4"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	"github.com/osmosis-labs/sqs/domain"
+	"github.com/osmosis-labs/sqs/domain/swaptx"
+)
+
+// GetQuoteTxRequest represents swap transaction request for the /router/quote-tx endpoint.
+// It extends GetQuoteRequest with the sender and the slippage tolerance used for building the swap message.
+type GetQuoteTxRequest struct {
+	GetQuoteRequest
+	Sender            string
+	SlippageTolerance osmomath.Dec
+}
+
+// GetQuoteTxResponse represents the response of the /router/quote-tx endpoint.
+type GetQuoteTxResponse struct {
+	Quote      domain.Quote       `json:"quote"`
+	UnsignedTx *swaptx.UnsignedTx `json:"unsigned_tx"`
+}
+
+// UnmarshalHTTPRequest unmarshals the HTTP request to GetQuoteTxRequest.
+// It returns an error if the request is invalid.
+func (r *GetQuoteTxRequest) UnmarshalHTTPRequest(c echo.Context) error {
+	if err := r.GetQuoteRequest.UnmarshalHTTPRequest(c); err != nil {
+		return err
+	}
+
+	r.Sender = c.QueryParam("sender")
+
+	slippageTolerance, err := osmomath.NewDecFromStr(c.QueryParam("slippageTolerance"))
+	if err != nil {
+		return ErrSlippageToleranceNotValid
+	}
+	r.SlippageTolerance = slippageTolerance
+
+	return nil
+}
+
+// ValidateTxParams validates the sender and the slippage tolerance of the GetQuoteTxRequest.
+// The sender must be a bech32 account address with the account address prefix of the chain.
+func (r *GetQuoteTxRequest) ValidateTxParams() error {
+	prefix, _, err := bech32.DecodeAndConvert(r.Sender)
+	if err != nil || prefix != sdk.GetConfig().GetBech32AccountAddrPrefix() {
+		return ErrSenderNotValid
+	}
+
+	if err := swaptx.ValidateSlippageTolerance(r.SlippageTolerance); err != nil {
+		return ErrSlippageToleranceNotValid
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package types_test
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	"github.com/osmosis-labs/sqs/router/types"
+)
+
+var (
+	senderAddress = sdk.AccAddress([]byte("sender______________"))
+
+	// validSender has the account address prefix of the chain.
+	validSender = senderAddress.String()
+)
+
+// TestGetQuoteTxRequestUnmarshal tests the UnmarshalHTTPRequest and ValidateTxParams methods of GetQuoteTxRequest.
+func TestGetQuoteTxRequestUnmarshal(t *testing.T) {
+	testcases := []struct {
+		name                  string
+		queryParams           map[string]string
+		expectedResult        *types.GetQuoteTxRequest
+		expectedUnmarshalErr  error
+		expectedValidationErr error
+	}{
+		{
+			name: "valid request",
+			queryParams: map[string]string{
+				"tokenIn":           "1000ust",
+				"tokenOutDenom":     "usdc",
+				"sender":            validSender,
+				"slippageTolerance": "0.01",
+			},
+			expectedResult: &types.GetQuoteTxRequest{
+				GetQuoteRequest: types.GetQuoteRequest{
+					TokenIn:       &sdk.Coin{Denom: "ust", Amount: sdk.NewInt(1000)},
+					TokenOutDenom: "usdc",
+				},
+				Sender:            validSender,
+				SlippageTolerance: osmomath.NewDecWithPrec(1, 2),
+			},
+		},
+		{
+			name: "missing slippage tolerance",
+			queryParams: map[string]string{
+				"tokenIn":       "1000ust",
+				"tokenOutDenom": "usdc",
+				"sender":        validSender,
+			},
+			expectedUnmarshalErr: types.ErrSlippageToleranceNotValid,
+		},
+		{
+			name: "slippage tolerance out of range",
+			queryParams: map[string]string{
+				"tokenIn":           "1000ust",
+				"tokenOutDenom":     "usdc",
+				"sender":            validSender,
+				"slippageTolerance": "1",
+			},
+			expectedValidationErr: types.ErrSlippageToleranceNotValid,
+		},
+		{
+			name: "invalid sender",
+			queryParams: map[string]string{
+				"tokenIn":           "1000ust",
+				"tokenOutDenom":     "usdc",
+				"sender":            "invalid",
+				"slippageTolerance": "0.01",
+			},
+			expectedValidationErr: types.ErrSenderNotValid,
+		},
+		{
+			name: "sender with the account address prefix of another chain",
+			queryParams: map[string]string{
+				"tokenIn":           "1000ust",
+				"tokenOutDenom":     "usdc",
+				"sender":            mustBech32Encode("juno", senderAddress),
+				"slippageTolerance": "0.01",
+			},
+			expectedValidationErr: types.ErrSenderNotValid,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(echo.GET, "/", nil)
+			q := req.URL.Query()
+			for k, v := range tc.queryParams {
+				q.Add(k, v)
+			}
+			req.URL.RawQuery = q.Encode()
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			var result types.GetQuoteTxRequest
+			err := (&result).UnmarshalHTTPRequest(c)
+			if tc.expectedUnmarshalErr != nil {
+				assert.ErrorIs(t, err, tc.expectedUnmarshalErr)
+				return
+			}
+			assert.NoError(t, err)
+
+			err = result.ValidateTxParams()
+			if tc.expectedValidationErr != nil {
+				assert.ErrorIs(t, err, tc.expectedValidationErr)
+				return
+			}
+			assert.NoError(t, err)
+
+			assert.Equal(t, tc.expectedResult, &result)
+		})
+	}
+}
+
+// mustBech32Encode returns the bech32 encoding of the address with the given prefix.
+func mustBech32Encode(prefix string, address sdk.AccAddress) string {
+	encoded, err := bech32.ConvertAndEncode(prefix, address)
+	if err != nil {
+		panic(err)
+	}
+	return encoded
+}
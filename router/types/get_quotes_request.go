@@ -0,0 +1,115 @@
+package types
+
+import (
+	"encoding/json"
+
+	"github.com/osmosis-labs/sqs/domain"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/labstack/echo/v4"
+)
+
+// MaxQuotesPerBatch is the maximum number of quotes that can be requested in a single batch.
+const MaxQuotesPerBatch = 50
+
+// MaxBatchQuoteConcurrency is the maximum number of quotes of a single batch that are computed concurrently.
+const MaxBatchQuoteConcurrency = 8
+
+// GetQuotesRequest represents the batch swap quote request for the /router/quotes endpoint.
+type GetQuotesRequest struct {
+	Quotes []QuoteRequestItem `json:"quotes"`
+}
+
+// QuoteRequestItem represents a single quote request within the batch.
+// The fields mirror the query parameters of the /router/quote endpoint.
+type QuoteRequestItem struct {
+	TokenIn               string `json:"tokenIn,omitempty"`
+	TokenOutDenom         string `json:"tokenOutDenom,omitempty"`
+	TokenOut              string `json:"tokenOut,omitempty"`
+	TokenInDenom          string `json:"tokenInDenom,omitempty"`
+	SingleRoute           bool   `json:"singleRoute,omitempty"`
+	ApplyExponents        bool   `json:"applyExponents,omitempty"`
+	SplitIncrements       int    `json:"splitIncrements,omitempty"`
+	SplitRefinementRounds int    `json:"splitRefinementRounds,omitempty"`
+
+	ExcludePoolIDs   []uint64                        `json:"excludePoolIDs,omitempty"`
+	OnlyPoolTypes    []domain.CandidateRoutePoolType `json:"onlyPoolTypes,omitempty"`
+	ExcludeDenoms    []string                        `json:"excludeDenoms,omitempty"`
+	MaxPoolsPerRoute int                             `json:"maxPoolsPerRoute,omitempty"`
+	MaxRoutes        int                             `json:"maxRoutes,omitempty"`
+	MinLiquidityCap  *uint64                         `json:"minLiquidityCap,omitempty"`
+
+	ComputeDeadlineMs int `json:"computeDeadlineMs,omitempty"`
+}
+
+// GetQuotesResponse represents the response of the /router/quotes endpoint.
+// The results are in the same order as the requested quotes.
+type GetQuotesResponse struct {
+	Quotes []QuoteResult `json:"quotes"`
+}
+
+// QuoteResult represents the result of a single quote within the batch.
+// Exactly one of the quote or the error is set.
+type QuoteResult struct {
+	Quote domain.Quote `json:"quote,omitempty"`
+	Error string       `json:"error,omitempty"`
+}
+
+// UnmarshalHTTPRequest unmarshals the HTTP request body to GetQuotesRequest.
+// It returns an error if the body is not valid JSON.
+func (r *GetQuotesRequest) UnmarshalHTTPRequest(c echo.Context) error {
+	if err := json.NewDecoder(c.Request().Body).Decode(r); err != nil {
+		return ErrQuotesRequestBodyNotValid
+	}
+
+	return nil
+}
+
+// Validate validates the batch size of the GetQuotesRequest.
+// Individual quote requests are validated separately so that
+// an invalid item does not fail the whole batch.
+func (r *GetQuotesRequest) Validate() error {
+	if len(r.Quotes) == 0 || len(r.Quotes) > MaxQuotesPerBatch {
+		return ErrQuotesBatchSizeNotValid
+	}
+
+	return nil
+}
+
+// ToGetQuoteRequest converts the item to GetQuoteRequest.
+// Returns error if the token in or the token out are not valid coins.
+func (i QuoteRequestItem) ToGetQuoteRequest() (*GetQuoteRequest, error) {
+	req := &GetQuoteRequest{
+		TokenOutDenom:         i.TokenOutDenom,
+		TokenInDenom:          i.TokenInDenom,
+		SingleRoute:           i.SingleRoute,
+		ApplyExponents:        i.ApplyExponents,
+		SplitIncrements:       i.SplitIncrements,
+		SplitRefinementRounds: i.SplitRefinementRounds,
+		ExcludePoolIDs:        i.ExcludePoolIDs,
+		OnlyPoolTypes:         i.OnlyPoolTypes,
+		ExcludeDenoms:         i.ExcludeDenoms,
+		MaxPoolsPerRoute:      i.MaxPoolsPerRoute,
+		MaxRoutes:             i.MaxRoutes,
+		MinLiquidityCap:       i.MinLiquidityCap,
+		ComputeDeadlineMs:     i.ComputeDeadlineMs,
+	}
+
+	if i.TokenIn != "" {
+		tokenInCoin, err := sdk.ParseCoinNormalized(i.TokenIn)
+		if err != nil {
+			return nil, ErrTokenInNotValid
+		}
+		req.TokenIn = &tokenInCoin
+	}
+
+	if i.TokenOut != "" {
+		tokenOutCoin, err := sdk.ParseCoinNormalized(i.TokenOut)
+		if err != nil {
+			return nil, ErrTokenOutNotValid
+		}
+		req.TokenOut = &tokenOutCoin
+	}
+
+	return req, nil
+}
@@ -0,0 +1,199 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	"github.com/osmosis-labs/sqs/domain"
+	"github.com/osmosis-labs/sqs/router/usecase/route"
+)
+
+// basketPoolSwapKey identifies the direction of the swaps through a pool.
+type basketPoolSwapKey struct {
+	poolID        uint64
+	tokenInDenom  string
+	tokenOutDenom string
+}
+
+// basketPoolUsage is the amount in after the taker fee swapped through each pool in each direction
+// by the legs of a basket swap quoted so far.
+type basketPoolUsage map[basketPoolSwapKey]osmomath.Int
+
+// GetOptimalBasketQuote implements mvc.RouterUsecase.
+// Each leg is quoted by GetOptimalQuote in the order given. The routes of each leg are then re-simulated
+// on top of the swaps of the preceding legs through the same pools in the same direction. That is, the amount out
+// of a pool shared with the preceding legs is the marginal amount out of swapping the leg amount after theirs.
+// The swaps in the opposite direction are not netted so the shared pools only ever reduce the amounts out.
+// Note that the routes of each leg are chosen independently of the other legs against the pre-trade pool state
+// rather than re-quoted against the liquidity left by the preceding legs. As a result, the basket quote depends
+// on the order of the legs: the earlier legs get the better prices of the shared pools and the later legs
+// may be routed through pools that are no longer optimal for them.
+// The swap breakdowns and the price impact of the prepared quotes are computed against the simulated state.
+// That is, the amounts out of the shared pools are the marginal ones and the price impact of a later leg is relative to
+// the pre-trade spot price, including the impact of the preceding legs.
+// Returns error if:
+// - no legs are given
+// - any of the legs fails to quote
+// - fails to re-simulate any of the legs
+// - the context is cancelled before all legs are quoted
+func (r *routerUseCaseImpl) GetOptimalBasketQuote(ctx context.Context, legs []domain.BasketLeg, opts ...domain.RouterOption) (domain.BasketQuote, error) {
+	if len(legs) == 0 {
+		return domain.BasketQuote{}, errors.New("no legs given for the basket quote")
+	}
+
+	basketQuote := domain.BasketQuote{
+		AmountIn:  sdk.NewCoins(),
+		AmountOut: sdk.NewCoins(),
+		Legs:      make([]domain.BasketQuoteLeg, 0, len(legs)),
+	}
+
+	usage := basketPoolUsage{}
+	for i, leg := range legs {
+		if ctx.Err() != nil {
+			return domain.BasketQuote{}, context.Cause(ctx)
+		}
+
+		standaloneQuote, err := r.GetOptimalQuote(ctx, leg.TokenIn, leg.TokenOutDenom, opts...)
+		if err != nil {
+			return domain.BasketQuote{}, fmt.Errorf("failed to quote basket leg %d (%s for %s): %w", i, leg.TokenIn, leg.TokenOutDenom, err)
+		}
+
+		legQuote, sharedPoolIDs, err := simulateBasketLeg(ctx, standaloneQuote, usage)
+		if err != nil {
+			return domain.BasketQuote{}, fmt.Errorf("failed to simulate basket leg %d (%s for %s): %w", i, leg.TokenIn, leg.TokenOutDenom, err)
+		}
+
+		if _, _, err := legQuote.PrepareResult(ctx, osmomath.OneDec(), r.logger); err != nil {
+			return domain.BasketQuote{}, err
+		}
+
+		basketQuote.AmountIn = basketQuote.AmountIn.Add(leg.TokenIn)
+		basketQuote.AmountOut = basketQuote.AmountOut.Add(sdk.NewCoin(leg.TokenOutDenom, legQuote.GetAmountOut()))
+		basketQuote.Legs = append(basketQuote.Legs, domain.BasketQuoteLeg{
+			Quote:               legQuote,
+			StandaloneAmountOut: standaloneQuote.GetAmountOut(),
+			SharedPoolIDs:       sharedPoolIDs,
+		})
+	}
+
+	return basketQuote, nil
+}
+
+// simulateBasketLeg re-simulates the routes of the given unprepared exact amount in quote on top of the pool usage
+// of the preceding legs and records the usage of the leg. Returns the unprepared quote over the same routes with the
+// re-simulated amounts out and the IDs of the pools shared with the preceding legs.
+// The shared pools in the returned routes are wrapped in basketSimulatedPool so that preparing the quote
+// computes the swap breakdowns against the simulated state.
+func simulateBasketLeg(ctx context.Context, quote domain.Quote, usage basketPoolUsage) (legQuote domain.Quote, sharedPoolIDs []uint64, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			legQuote, sharedPoolIDs = nil, nil
+			err = fmt.Errorf("error when simulating basket leg: %v", r)
+		}
+	}()
+
+	tokenInDenom := quote.GetAmountIn().Denom
+
+	var (
+		routes         = make([]domain.SplitRoute, 0, len(quote.GetRoute()))
+		totalAmountOut = osmomath.ZeroInt()
+	)
+	sharedPoolIDs = []uint64{}
+
+	for _, splitRoute := range quote.GetRoute() {
+		tokenIn := sdk.NewCoin(tokenInDenom, splitRoute.GetAmountIn())
+
+		routePools := make([]domain.RoutablePool, 0, len(splitRoute.GetPools()))
+		for _, pool := range splitRoute.GetPools() {
+			key := basketPoolSwapKey{
+				poolID:        pool.GetId(),
+				tokenInDenom:  tokenIn.Denom,
+				tokenOutDenom: pool.GetTokenOutDenom(),
+			}
+
+			tokenInAfterFee := pool.ChargeTakerFeeExactIn(tokenIn)
+			if tokenInAfterFee.Amount.IsZero() {
+				routePools = append(routePools, pool)
+				tokenIn = sdk.NewCoin(key.tokenOutDenom, osmomath.ZeroInt())
+				continue
+			}
+
+			priorAmountIn, isShared := usage[key]
+			if isShared {
+				sharedPoolIDs = append(sharedPoolIDs, key.poolID)
+				pool = &basketSimulatedPool{
+					RoutablePool: pool,
+					priorTokenIn: sdk.NewCoin(tokenIn.Denom, priorAmountIn),
+				}
+			} else {
+				priorAmountIn = osmomath.ZeroInt()
+			}
+			routePools = append(routePools, pool)
+
+			tokenOut, err := pool.CalculateTokenOutByTokenIn(ctx, tokenInAfterFee)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			usage[key] = priorAmountIn.Add(tokenInAfterFee.Amount)
+			tokenIn = tokenOut
+		}
+
+		routes = append(routes, &RouteWithOutAmount{
+			RouteImpl: route.RouteImpl{
+				Pools:                      routePools,
+				HasGeneralizedCosmWasmPool: splitRoute.ContainsGeneralizedCosmWasmPool(),
+			},
+			InAmount:  splitRoute.GetAmountIn(),
+			OutAmount: tokenIn.Amount,
+		})
+
+		totalAmountOut = totalAmountOut.Add(tokenIn.Amount)
+	}
+
+	return &quoteExactAmountIn{
+		AmountIn:  quote.GetAmountIn(),
+		AmountOut: totalAmountOut,
+		Route:     routes,
+	}, sharedPoolIDs, nil
+}
+
+// basketSimulatedPool is the pool that a basket leg swaps through after the preceding legs swapped
+// the prior token in through it in the same direction. Its amounts out are the marginal amounts out
+// of swapping after the prior token in. The remaining methods, such as the spot price, are those of the pre-trade state.
+type basketSimulatedPool struct {
+	domain.RoutablePool
+	// priorTokenIn is the token in after the taker fee swapped through the pool by the preceding legs.
+	priorTokenIn sdk.Coin
+}
+
+// CalculateTokenOutByTokenIn implements domain.RoutablePool.
+func (p *basketSimulatedPool) CalculateTokenOutByTokenIn(ctx context.Context, tokenIn sdk.Coin) (sdk.Coin, error) {
+	return calculateMarginalTokenOut(ctx, p.RoutablePool, p.priorTokenIn, tokenIn)
+}
+
+// calculateMarginalTokenOut returns the amount out of swapping the token in through the pool
+// after the prior token in has been swapped through it in the same direction.
+// CONTRACT: both amounts are after the taker fee.
+func calculateMarginalTokenOut(ctx context.Context, pool domain.RoutablePool, priorTokenIn, tokenIn sdk.Coin) (sdk.Coin, error) {
+	priorTokenOut, err := pool.CalculateTokenOutByTokenIn(ctx, priorTokenIn)
+	if err != nil {
+		return sdk.Coin{}, err
+	}
+
+	totalTokenOut, err := pool.CalculateTokenOutByTokenIn(ctx, priorTokenIn.Add(tokenIn))
+	if err != nil {
+		return sdk.Coin{}, err
+	}
+
+	marginalAmountOut := totalTokenOut.Amount.Sub(priorTokenOut.Amount)
+	if marginalAmountOut.IsNegative() {
+		marginalAmountOut = osmomath.ZeroInt()
+	}
+
+	return sdk.NewCoin(totalTokenOut.Denom, marginalAmountOut), nil
+}
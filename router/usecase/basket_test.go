@@ -0,0 +1,154 @@
+package usecase_test
+
+import (
+	"context"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/osmosis-labs/osmosis/osmomath"
+	poolmanagertypes "github.com/osmosis-labs/osmosis/v25/x/poolmanager/types"
+	"github.com/osmosis-labs/sqs/domain"
+	"github.com/osmosis-labs/sqs/domain/mocks"
+	"github.com/osmosis-labs/sqs/log"
+	"github.com/osmosis-labs/sqs/router/usecase"
+	"github.com/osmosis-labs/sqs/router/usecase/route"
+	"github.com/osmosis-labs/sqs/router/usecase/routertesting"
+)
+
+// Validates that the legs swapping through the same pool in the same direction
+// are re-simulated on top of the swaps of the preceding legs.
+// The pool is a constant product pool with 1000 of each token.
+// - a standalone swap of 100 in returns 1000 * 100 / 1100 = 90
+// - a swap of 100 in after 100 in returns 1000 * 200 / 1200 - 90 = 76
+// The swaps in the opposite direction are not netted.
+// The prepared swap breakdowns are computed against the simulated state with the pre-trade spot price of 1.
+func (s *RouterTestSuite) TestSimulateBasketLeg() {
+	const reserve = 1_000
+
+	newConstantProductPool := func(tokenOutDenom string) *mocks.MockRoutablePool {
+		return &mocks.MockRoutablePool{
+			ID:            1,
+			PoolType:      poolmanagertypes.CosmWasm,
+			TokenOutDenom: tokenOutDenom,
+			TakerFee:      osmomath.ZeroDec(),
+			CalculateTokenOutByTokenInFunc: func(ctx context.Context, tokenIn sdk.Coin) (sdk.Coin, error) {
+				amountOut := tokenIn.Amount.MulRaw(reserve).Quo(tokenIn.Amount.AddRaw(reserve))
+				return sdk.NewCoin(tokenOutDenom, amountOut), nil
+			},
+		}
+	}
+
+	var (
+		ctx = context.TODO()
+
+		osmoToUSDCRoute = route.RouteImpl{Pools: []domain.RoutablePool{newConstantProductPool(USDC)}}
+		usdcToOSMORoute = route.RouteImpl{Pools: []domain.RoutablePool{newConstantProductPool(UOSMO)}}
+
+		tokenIn = sdk.NewCoin(UOSMO, osmomath.NewInt(100))
+	)
+
+	tests := []struct {
+		name                  string
+		route                 route.RouteImpl
+		tokenIn               sdk.Coin
+		expectedAmountOut     osmomath.Int
+		expectedSharedPoolIDs []uint64
+		expectedPriceImpact   osmomath.Dec
+	}{
+		{
+			name:                  "first leg swaps against the pre-trade state",
+			route:                 osmoToUSDCRoute,
+			tokenIn:               tokenIn,
+			expectedAmountOut:     osmomath.NewInt(90),
+			expectedSharedPoolIDs: []uint64{},
+			expectedPriceImpact:   osmomath.MustNewDecFromStr("-0.1"),
+		},
+		{
+			name:                  "second leg in the same direction swaps after the first",
+			route:                 osmoToUSDCRoute,
+			tokenIn:               tokenIn,
+			expectedAmountOut:     osmomath.NewInt(76),
+			expectedSharedPoolIDs: []uint64{1},
+			expectedPriceImpact:   osmomath.MustNewDecFromStr("-0.24"),
+		},
+		{
+			name:                  "leg in the opposite direction is not netted",
+			route:                 usdcToOSMORoute,
+			tokenIn:               sdk.NewCoin(USDC, osmomath.NewInt(100)),
+			expectedAmountOut:     osmomath.NewInt(90),
+			expectedSharedPoolIDs: []uint64{},
+			expectedPriceImpact:   osmomath.MustNewDecFromStr("-0.1"),
+		},
+	}
+
+	// Note: the usage is carried over the test cases in order.
+	usage := usecase.BasketPoolUsage{}
+	for _, tc := range tests {
+		s.Run(tc.name, func() {
+			standaloneQuote, err := usecase.GetSplitQuote(ctx, []route.RouteImpl{tc.route}, tc.tokenIn)
+			s.Require().NoError(err)
+
+			// System under test
+			legQuote, sharedPoolIDs, err := usecase.SimulateBasketLeg(ctx, standaloneQuote, usage)
+			s.Require().NoError(err)
+
+			s.Require().Equal(tc.tokenIn, legQuote.GetAmountIn())
+			s.Require().Equal(tc.expectedAmountOut.String(), legQuote.GetAmountOut().String())
+			s.Require().Equal(tc.expectedSharedPoolIDs, sharedPoolIDs)
+
+			routes := legQuote.GetRoute()
+			s.Require().Len(routes, 1)
+			s.Require().Equal(tc.tokenIn.Amount.String(), routes[0].GetAmountIn().String())
+			s.Require().Equal(tc.expectedAmountOut.String(), routes[0].GetAmountOut().String())
+
+			// The breakdown and the price impact of the prepared quote reflect the simulated state.
+			preparedRoutes, _, err := legQuote.PrepareResult(ctx, osmomath.OneDec(), &log.NoOpLogger{})
+			s.Require().NoError(err)
+
+			preparedPools := preparedRoutes[0].GetPools()
+			s.Require().Len(preparedPools, 1)
+
+			resultPool, ok := preparedPools[0].(domain.RoutableResultPool)
+			s.Require().True(ok)
+
+			breakdown := resultPool.GetSwapBreakdown()
+			s.Require().NotNil(breakdown)
+			s.Require().Equal(tc.expectedAmountOut.String(), breakdown.TokenOut.Amount.String())
+			s.Require().Equal(tc.expectedPriceImpact.String(), breakdown.PriceImpact.String())
+			s.Require().Equal(tc.expectedPriceImpact.String(), legQuote.GetPriceImpact().String())
+		})
+	}
+}
+
+// Validates that the second of two identical legs returns less than the first
+// on mainnet state since it swaps through the same pools after the first.
+func (s *RouterTestSuite) TestGetOptimalBasketQuote_Mainnet() {
+	mainnetState := s.SetupMainnetState()
+
+	mainnetUsecase := s.SetupRouterAndPoolsUsecase(mainnetState, routertesting.WithLoggerDisabled())
+
+	leg := domain.BasketLeg{
+		TokenIn:       sdk.NewCoin(UOSMO, osmomath.NewInt(1_000_000_000)),
+		TokenOutDenom: USDC,
+	}
+
+	// System under test
+	basketQuote, err := mainnetUsecase.Router.GetOptimalBasketQuote(context.Background(), []domain.BasketLeg{leg, leg})
+	s.Require().NoError(err)
+
+	s.Require().Len(basketQuote.Legs, 2)
+	s.Require().Equal(sdk.NewCoins(sdk.NewCoin(UOSMO, leg.TokenIn.Amount.MulRaw(2))), basketQuote.AmountIn)
+
+	first, second := basketQuote.Legs[0], basketQuote.Legs[1]
+
+	// The first leg is not affected by the other legs.
+	s.Require().Empty(first.SharedPoolIDs)
+	s.Require().Equal(first.StandaloneAmountOut.String(), first.Quote.GetAmountOut().String())
+
+	// The second leg swaps through the same pools after the first.
+	s.Require().NotEmpty(second.SharedPoolIDs)
+	s.Require().Equal(first.StandaloneAmountOut.String(), second.StandaloneAmountOut.String())
+	s.Require().True(second.Quote.GetAmountOut().LT(first.Quote.GetAmountOut()))
+
+	totalAmountOut := first.Quote.GetAmountOut().Add(second.Quote.GetAmountOut())
+	s.Require().Equal(sdk.NewCoins(sdk.NewCoin(USDC, totalAmountOut)), basketQuote.AmountOut)
+}
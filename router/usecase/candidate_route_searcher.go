@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/osmosis-labs/sqs/domain"
+	"github.com/osmosis-labs/sqs/domain/mvc"
+	"github.com/osmosis-labs/sqs/log"
+	"github.com/osmosis-labs/sqs/sqsdomain"
+)
+
+// candidateRouteSearcherSelector dispatches the candidate route searches to the searcher
+// of the algorithm requested by the search options, falling back to the default algorithm.
+type candidateRouteSearcherSelector struct {
+	defaultAlgorithm domain.CandidateRouteAlgorithm
+	searchers        map[domain.CandidateRouteAlgorithm]domain.CandidateRouteSearcher
+}
+
+var _ domain.CandidateRouteSearcher = candidateRouteSearcherSelector{}
+
+// NewCandidateRouteSearcher returns the candidate route searcher supporting all candidate route algorithms.
+// The searches with no algorithm in the options use the given default algorithm.
+// If the default algorithm is empty, the breadth-first search is the default.
+// Returns error if the default algorithm is not valid.
+func NewCandidateRouteSearcher(candidateRouteDataHolder mvc.CandidateRouteSearchDataHolder, cosmWasmPoolsConfig domain.CosmWasmPoolRouterConfig, defaultAlgorithm domain.CandidateRouteAlgorithm, logger log.Logger) (domain.CandidateRouteSearcher, error) {
+	if defaultAlgorithm == "" {
+		defaultAlgorithm = domain.CandidateRouteAlgorithmBFS
+	}
+
+	if !defaultAlgorithm.IsValid() {
+		return nil, fmt.Errorf("candidate route algorithm (%s) is not valid, must be one of %v", defaultAlgorithm, domain.CandidateRouteAlgorithms)
+	}
+
+	return candidateRouteSearcherSelector{
+		defaultAlgorithm: defaultAlgorithm,
+		searchers: map[domain.CandidateRouteAlgorithm]domain.CandidateRouteSearcher{
+			domain.CandidateRouteAlgorithmBFS:        NewCandidateRouteFinder(candidateRouteDataHolder, logger),
+			domain.CandidateRouteAlgorithmPriceAware: NewPriceAwareCandidateRouteFinder(candidateRouteDataHolder, cosmWasmPoolsConfig, logger),
+		},
+	}, nil
+}
+
+// FindCandidateRoutes implements domain.CandidateRouteSearcher.
+func (s candidateRouteSearcherSelector) FindCandidateRoutes(tokenIn sdk.Coin, tokenOutDenom string, options domain.CandidateRouteSearchOptions) (sqsdomain.CandidateRoutes, error) {
+	return s.getSearcher(options.Algorithm).FindCandidateRoutes(tokenIn, tokenOutDenom, options)
+}
+
+// FindCandidateCycles implements domain.CandidateRouteSearcher.
+func (s candidateRouteSearcherSelector) FindCandidateCycles(denom string, options domain.CandidateRouteSearchOptions) (sqsdomain.CandidateRoutes, error) {
+	return s.getSearcher(options.Algorithm).FindCandidateCycles(denom, options)
+}
+
+// getSearcher returns the searcher of the given algorithm or of the default algorithm
+// if the given one is empty or not supported.
+func (s candidateRouteSearcherSelector) getSearcher(algorithm domain.CandidateRouteAlgorithm) domain.CandidateRouteSearcher {
+	if searcher, ok := s.searchers[algorithm]; ok {
+		return searcher
+	}
+	return s.searchers[s.defaultAlgorithm]
+}
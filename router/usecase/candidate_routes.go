@@ -2,6 +2,7 @@ package usecase
 
 import (
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/osmosis-labs/osmosis/osmomath"
 	"github.com/osmosis-labs/sqs/domain"
 	"github.com/osmosis-labs/sqs/domain/mvc"
 	"github.com/osmosis-labs/sqs/log"
@@ -55,38 +56,13 @@ func (c candidateRouteFinder) FindCandidateRoutes(tokenIn sdk.Coin, tokenOutDeno
 		return sqsdomain.CandidateRoutes{}, err
 	}
 
-	if len(denomData.CanonicalOrderbooks) > 0 {
-		canonicalOrderbook, ok := denomData.CanonicalOrderbooks[tokenOutDenom]
-		if ok {
-			shouldSkipCanonicalOrderbook := false
-			// Filter the canonical orderbook pool using the pool filters.
-			for _, filter := range options.PoolFiltersAnyOf {
-				// nolint: forcetypeassert
-				canonicalOrderbookPoolWrapper := (canonicalOrderbook).(*sqsdomain.PoolWrapper)
-				if filter(canonicalOrderbookPoolWrapper) {
-					shouldSkipCanonicalOrderbook = true
-					break
-				}
-			}
-
-			if !shouldSkipCanonicalOrderbook {
-				// Add the canonical orderbook as a route.
-				routes = append(routes, candidateRouteWrapper{
-					IsCanonicalOrderboolRoute: true,
-					Pools: []candidatePoolWrapper{
-						{
-							CandidatePool: sqsdomain.CandidatePool{
-								ID:            canonicalOrderbook.GetId(),
-								TokenOutDenom: tokenOutDenom,
-							},
-							PoolDenoms: canonicalOrderbook.GetSQSPoolModel().PoolDenoms,
-						},
-					},
-				})
-			}
-
-			visited[canonicalOrderbook.GetId()] = struct{}{}
-		}
+	canonicalOrderbookRoute, canonicalOrderbookPoolID, ok := getCanonicalOrderbookRoute(denomData, tokenOutDenom, options)
+	if ok {
+		// Add the canonical orderbook as a route.
+		routes = append(routes, canonicalOrderbookRoute)
+	}
+	if canonicalOrderbookPoolID != 0 {
+		visited[canonicalOrderbookPoolID] = struct{}{}
 	}
 
 	for len(queue) > 0 && len(routes) < options.MaxRoutes {
@@ -132,6 +108,9 @@ func (c candidateRouteFinder) FindCandidateRoutes(tokenIn sdk.Coin, tokenOutDeno
 
 			if pool.GetLiquidityCap().Uint64() < options.MinPoolLiquidityCap {
 				visited[poolID] = struct{}{}
+				if options.LiquidityFilteredPoolCb != nil {
+					options.LiquidityFilteredPoolCb(poolID)
+				}
 				// Skip pools that have less liquidity than the minimum required.
 				continue
 			}
@@ -189,6 +168,11 @@ func (c candidateRouteFinder) FindCandidateRoutes(tokenIn sdk.Coin, tokenOutDeno
 					continue
 				}
 
+				// Avoid routing through the intermediary denoms that the options are configured to skip.
+				if !hasTokenOut && options.ShouldSkipIntermediaryDenom(denom) {
+					continue
+				}
+
 				denomData, err := c.candidateRouteDataHolder.GetDenomData(currenTokenInDenom)
 				if err != nil {
 					return sqsdomain.CandidateRoutes{}, err
@@ -236,6 +220,132 @@ func (c candidateRouteFinder) FindCandidateRoutes(tokenIn sdk.Coin, tokenOutDeno
 	return validateAndFilterRoutes(routes, tokenIn.Denom, c.logger)
 }
 
+// getCanonicalOrderbookRoute returns the single pool route through the canonical orderbook of the token in denom
+// with the given token out denom, given the candidate route search data of the token in denom.
+// The ID of the canonical orderbook pool is returned even if the pool is filtered out by the pool filters of the options
+// so that the callers exclude it from the other routes. Zero if there is no such orderbook.
+// Returns false if there is no such orderbook or if it is filtered out.
+func getCanonicalOrderbookRoute(denomData domain.CandidateRouteDenomData, tokenOutDenom string, options domain.CandidateRouteSearchOptions) (candidateRouteWrapper, uint64, bool) {
+	canonicalOrderbook, ok := denomData.CanonicalOrderbooks[tokenOutDenom]
+	if !ok {
+		return candidateRouteWrapper{}, 0, false
+	}
+
+	canonicalOrderbookPoolID := canonicalOrderbook.GetId()
+
+	// Filter the canonical orderbook pool using the pool filters.
+	for _, filter := range options.PoolFiltersAnyOf {
+		// nolint: forcetypeassert
+		canonicalOrderbookPoolWrapper := (canonicalOrderbook).(*sqsdomain.PoolWrapper)
+		if filter(canonicalOrderbookPoolWrapper) {
+			return candidateRouteWrapper{}, canonicalOrderbookPoolID, false
+		}
+	}
+
+	return candidateRouteWrapper{
+		IsCanonicalOrderboolRoute: true,
+		Pools: []candidatePoolWrapper{
+			{
+				CandidatePool: sqsdomain.CandidatePool{
+					ID:            canonicalOrderbookPoolID,
+					TokenOutDenom: tokenOutDenom,
+				},
+				PoolDenoms: canonicalOrderbook.GetSQSPoolModel().PoolDenoms,
+			},
+		},
+	}, canonicalOrderbookPoolID, true
+}
+
+// FindCandidateCycles implements domain.CandidateRouteSearcher.
+// The first pool of each cycle is taken from the ranked pools of the denom, swapping the denom
+// into any of the other pool denoms. The rest of the cycle is a candidate route from that pool denom
+// back to the denom that does not go through the first pool again.
+// The options apply to the whole cycle. That is, MaxPoolsPerRoute bounds the number of pools in a cycle
+// and MaxRoutes bounds the number of cycles.
+// Returns domain.ErrCyclicArbMaxPoolsPerCycleNotValid if MaxPoolsPerRoute is less than 2.
+func (c candidateRouteFinder) FindCandidateCycles(denom string, options domain.CandidateRouteSearchOptions) (sqsdomain.CandidateRoutes, error) {
+	cycles := sqsdomain.CandidateRoutes{
+		Routes:        make([]sqsdomain.CandidateRoute, 0, options.MaxRoutes),
+		UniquePoolIDs: make(map[uint64]struct{}),
+	}
+
+	// A cycle requires at least one pool out of the denom and one pool back.
+	if options.MaxPoolsPerRoute < 2 {
+		return sqsdomain.CandidateRoutes{}, domain.ErrCyclicArbMaxPoolsPerCycleNotValid
+	}
+
+	denomData, err := c.candidateRouteDataHolder.GetDenomData(denom)
+	if err != nil {
+		return sqsdomain.CandidateRoutes{}, err
+	}
+
+	rankedPools := denomData.SortedPools
+
+	for i := 0; i < len(rankedPools) && len(cycles.Routes) < options.MaxRoutes; i++ {
+		// Unsafe cast for performance reasons.
+		// nolint: forcetypeassert
+		pool := (rankedPools[i]).(*sqsdomain.PoolWrapper)
+		poolID := pool.ChainModel.GetId()
+
+		if options.ShouldSkipPool(pool) {
+			continue
+		}
+
+		if pool.GetLiquidityCap().Uint64() < options.MinPoolLiquidityCap {
+			if options.LiquidityFilteredPoolCb != nil {
+				options.LiquidityFilteredPoolCb(poolID)
+			}
+			continue
+		}
+
+		// The way back must not go through the first pool of the cycle.
+		firstPoolFilter := domain.CandidateRoutePoolIDFilterOptionCb{
+			PoolIDsToSkip: map[uint64]struct{}{poolID: {}},
+		}
+
+		for _, firstPoolTokenOutDenom := range pool.SQSModel.PoolDenoms {
+			if len(cycles.Routes) >= options.MaxRoutes {
+				break
+			}
+
+			if firstPoolTokenOutDenom == denom || options.ShouldSkipIntermediaryDenom(firstPoolTokenOutDenom) {
+				continue
+			}
+
+			// Copy to avoid mutating the filters of the caller.
+			wayBackOptions := options
+			wayBackOptions.MaxRoutes = options.MaxRoutes - len(cycles.Routes)
+			wayBackOptions.MaxPoolsPerRoute = options.MaxPoolsPerRoute - 1
+			wayBackOptions.PoolFiltersAnyOf = append(append(make([]domain.CandidateRoutePoolFiltrerCb, 0, len(options.PoolFiltersAnyOf)+1), options.PoolFiltersAnyOf...), firstPoolFilter.ShouldSkipPool)
+
+			wayBackRoutes, err := c.FindCandidateRoutes(sdk.NewCoin(firstPoolTokenOutDenom, osmomath.ZeroInt()), denom, wayBackOptions)
+			if err != nil {
+				c.logger.Debug("failed to find the way back in candidate cycle search", zap.String("denom", firstPoolTokenOutDenom), zap.Error(err))
+				continue
+			}
+
+			for _, wayBackRoute := range wayBackRoutes.Routes {
+				cyclePools := make([]sqsdomain.CandidatePool, 0, len(wayBackRoute.Pools)+1)
+				cyclePools = append(cyclePools, sqsdomain.CandidatePool{
+					ID:            poolID,
+					TokenOutDenom: firstPoolTokenOutDenom,
+				})
+				cyclePools = append(cyclePools, wayBackRoute.Pools...)
+
+				for _, cyclePool := range cyclePools {
+					cycles.UniquePoolIDs[cyclePool.ID] = struct{}{}
+				}
+
+				cycles.Routes = append(cycles.Routes, sqsdomain.CandidateRoute{
+					Pools: cyclePools,
+				})
+			}
+		}
+	}
+
+	return cycles, nil
+}
+
 // Pool represents a pool in the decentralized exchange.
 type Pool struct {
 	ID       int
@@ -9,8 +9,19 @@ import (
 	"github.com/osmosis-labs/sqs/router/usecase/routertesting"
 )
 
-// Microbenchmark for the GetSplitQuote function.
+// Microbenchmark for the breadth-first candidate route search.
 func BenchmarkCandidateRouteSearcher(b *testing.B) {
+	benchmarkCandidateRouteSearcher(b, domain.CandidateRouteAlgorithmBFS)
+}
+
+// Microbenchmark for the price-aware candidate route search.
+func BenchmarkCandidateRouteSearcher_PriceAware(b *testing.B) {
+	benchmarkCandidateRouteSearcher(b, domain.CandidateRouteAlgorithmPriceAware)
+}
+
+// benchmarkCandidateRouteSearcher benchmarks the candidate route search with the given algorithm
+// between OSMO and ATOM on the mainnet state.
+func benchmarkCandidateRouteSearcher(b *testing.B, algorithm domain.CandidateRouteAlgorithm) {
 	// This is a hack to be able to use test suite helpers with the benchmark.
 	// We need to set testing.T for assertings within the helpers. Otherwise, it would block
 	s := RouterTestSuite{}
@@ -31,6 +42,7 @@ func BenchmarkCandidateRouteSearcher(b *testing.B) {
 		MaxRoutes:           routerConfig.MaxRoutes,
 		MaxPoolsPerRoute:    routerConfig.MaxPoolsPerRoute,
 		MinPoolLiquidityCap: 1,
+		Algorithm:           algorithm,
 	}
 
 	b.ResetTimer()
@@ -1,6 +1,8 @@
 package usecase_test
 
 import (
+	"context"
+	"fmt"
 	"slices"
 	"testing"
 
@@ -25,6 +27,7 @@ var (
 // Additionally, for every route, the test validates that the denoms are indeed present in the pool denoms of each pool.
 // Lastly, that the number of pools in route is less than or equal to the max number of pools per route and greater than zero
 // while also above the minimum pool liquidity cap.
+// The test runs for every candidate route algorithm.
 func (s *RouterTestSuite) TestCandidateRouteSearcher_HappyPath() {
 
 	mainnetState := s.SetupMainnetState()
@@ -58,70 +61,110 @@ func (s *RouterTestSuite) TestCandidateRouteSearcher_HappyPath() {
 		},
 	}
 
-	for _, tc := range tests {
-		s.T().Run(tc.name, func(t *testing.T) {
+	for _, algorithm := range domain.CandidateRouteAlgorithms {
+		for _, tc := range tests {
+			s.T().Run(fmt.Sprintf("%s %s", algorithm, tc.name), func(t *testing.T) {
 
-			routerConfig := usecase.Router.GetConfig()
-			candidateRouteOptions := domain.CandidateRouteSearchOptions{
-				MaxRoutes:           routerConfig.MaxRoutes,
-				MaxPoolsPerRoute:    routerConfig.MaxPoolsPerRoute,
-				MinPoolLiquidityCap: routerConfig.MinPoolLiquidityCap,
-			}
+				routerConfig := usecase.Router.GetConfig()
+				candidateRouteOptions := domain.CandidateRouteSearchOptions{
+					MaxRoutes:           routerConfig.MaxRoutes,
+					MaxPoolsPerRoute:    routerConfig.MaxPoolsPerRoute,
+					MinPoolLiquidityCap: routerConfig.MinPoolLiquidityCap,
+					Algorithm:           algorithm,
+				}
 
-			expectedMinPoolLiquidityCapInt := osmomath.NewInt(int64(routerConfig.MinPoolLiquidityCap))
+				expectedMinPoolLiquidityCapInt := osmomath.NewInt(int64(routerConfig.MinPoolLiquidityCap))
+
+				// System under test
+				candidateRoutes, err := usecase.CandidateRouteSearcher.FindCandidateRoutes(tc.tokenIn, tc.tokenOutDenom, candidateRouteOptions)
+				s.Require().NoError(err)
+
+				// Validate that at least one route found
+				s.Require().Greater(len(candidateRoutes.Routes), 0)
+				// Validate that the number of routes found is less than or equal to the max number of routes.
+				s.Require().LessOrEqual(len(candidateRoutes.Routes), candidateRouteOptions.MaxRoutes)
+				// Validate that the unieque pools are non-empty.
+				s.Require().Greater(len(candidateRoutes.UniquePoolIDs), 0)
+
+				// Validate each route and its pools to be within he configured bounds.
+				for _, route := range candidateRoutes.Routes {
+					// Validate that the route is non-empty.
+					s.Require().Greater(len(route.Pools), 0)
+					// Validate that the route is less than or equal to the max number of pools per route.
+					s.Require().LessOrEqual(len(route.Pools), candidateRouteOptions.MaxPoolsPerRoute)
+
+					curTokenInDenom := tc.tokenIn.Denom
+
+					for _, pool := range route.Pools {
+						// Validate that the pool ID is in the unique pool IDs.
+						s.Require().Contains(candidateRoutes.UniquePoolIDs, pool.ID)
+
+						// Validate that the pool ID is in the pools above min liquidity.
+						poolInRoute, err := usecase.Pools.GetPool(pool.ID)
+						s.Require().NoError(err)
+
+						cosmwasmModel := poolInRoute.GetSQSPoolModel().CosmWasmPoolModel
+						isOrderbook := cosmwasmModel != nil && cosmwasmModel.IsOrderbook()
+						// Note: canonical order books are injected into routes, completely ignoring liquidity caps
+						// so we don't need to check for liquidity caps for canonical order books
+						if !isOrderbook {
+							s.Require().True(poolInRoute.GetPoolLiquidityCap().GTE(expectedMinPoolLiquidityCapInt), "poolID: %d, expectedMinPoolLiquidityCapInt: %s, poolInRoute.GetPoolLiquidityCap(): %s", pool.ID, expectedMinPoolLiquidityCapInt, poolInRoute.GetPoolLiquidityCap())
+						}
+
+						// Pool contains token in
+						poolDenoms := poolInRoute.GetPoolDenoms()
+						s.Require().True(slices.Contains(poolDenoms, curTokenInDenom))
+
+						// Pool contains token out
+						tokenOut := pool.TokenOutDenom
+						s.Require().True(slices.Contains(poolInRoute.GetPoolDenoms(), tokenOut))
+
+						// Change tokenInDenom to tokenOutDenom for the next iteration
+						curTokenInDenom = tokenOut
+					}
 
-			// System under test
-			candidateRoutes, err := usecase.CandidateRouteSearcher.FindCandidateRoutes(tc.tokenIn, tc.tokenOutDenom, candidateRouteOptions)
-			s.Require().NoError(err)
+					// Validate that the resulting token out denom equals to the one set by the test
+					// Note that we set he curTokenInDenom to the tokenOutDenom of the last pool in the route
+					s.Require().Equal(tc.tokenOutDenom, curTokenInDenom)
+				}
+			})
+		}
+	}
+}
 
-			// Validate that at least one route found
-			s.Require().Greater(len(candidateRoutes.Routes), 0)
-			// Validate that the number of routes found is less than or equal to the max number of routes.
-			s.Require().LessOrEqual(len(candidateRoutes.Routes), candidateRouteOptions.MaxRoutes)
-			// Validate that the unieque pools are non-empty.
-			s.Require().Greater(len(candidateRoutes.UniquePoolIDs), 0)
-
-			// Validate each route and its pools to be within he configured bounds.
-			for _, route := range candidateRoutes.Routes {
-				// Validate that the route is non-empty.
-				s.Require().Greater(len(route.Pools), 0)
-				// Validate that the route is less than or equal to the max number of pools per route.
-				s.Require().LessOrEqual(len(route.Pools), candidateRouteOptions.MaxPoolsPerRoute)
-
-				curTokenInDenom := tc.tokenIn.Denom
-
-				for _, pool := range route.Pools {
-					// Validate that the pool ID is in the unique pool IDs.
-					s.Require().Contains(candidateRoutes.UniquePoolIDs, pool.ID)
-
-					// Validate that the pool ID is in the pools above min liquidity.
-					poolInRoute, err := usecase.Pools.GetPool(pool.ID)
-					s.Require().NoError(err)
-
-					cosmwasmModel := poolInRoute.GetSQSPoolModel().CosmWasmPoolModel
-					isOrderbook := cosmwasmModel != nil && cosmwasmModel.IsOrderbook()
-					// Note: canonical order books are injected into routes, completely ignoring liquidity caps
-					// so we don't need to check for liquidity caps for canonical order books
-					if !isOrderbook {
-						s.Require().True(poolInRoute.GetPoolLiquidityCap().GTE(expectedMinPoolLiquidityCapInt), "poolID: %d, expectedMinPoolLiquidityCapInt: %s, poolInRoute.GetPoolLiquidityCap(): %s", pool.ID, expectedMinPoolLiquidityCapInt, poolInRoute.GetPoolLiquidityCap())
-					}
+// Compares the quality of the candidate routes found by the price-aware search against the breadth-first search
+// on the mainnet state. For every token pair, the best single route quote over the candidate routes of each algorithm
+// is computed and logged. The price-aware search is validated to quote a positive amount out.
+// Note that neither algorithm is guaranteed to find the better route since the price-aware search ignores
+// the price impact and the breadth-first search ignores the prices.
+func (s *RouterTestSuite) TestCandidateRouteSearcher_PriceAwareVsBFS() {
+	mainnetState := s.SetupMainnetState()
 
-					// Pool contains token in
-					poolDenoms := poolInRoute.GetPoolDenoms()
-					s.Require().True(slices.Contains(poolDenoms, curTokenInDenom))
+	usecase := s.SetupRouterAndPoolsUsecase(mainnetState, routertesting.WithLoggerDisabled())
 
-					// Pool contains token out
-					tokenOut := pool.TokenOutDenom
-					s.Require().True(slices.Contains(poolInRoute.GetPoolDenoms(), tokenOut))
+	tests := []struct {
+		tokenIn       sdk.Coin
+		tokenOutDenom string
+	}{
+		{tokenIn: sdk.NewCoin(UOSMO, defaultAmount), tokenOutDenom: ATOM},
+		{tokenIn: sdk.NewCoin(UOSMO, defaultAmount), tokenOutDenom: USDT},
+		{tokenIn: sdk.NewCoin(USDC, defaultAmount), tokenOutDenom: TIA},
+		{tokenIn: sdk.NewCoin(UMEE, defaultAmount), tokenOutDenom: AKT},
+		{tokenIn: sdk.NewCoin(ALLBTC, one), tokenOutDenom: USDC},
+	}
 
-					// Change tokenInDenom to tokenOutDenom for the next iteration
-					curTokenInDenom = tokenOut
-				}
+	for _, tc := range tests {
+		s.T().Run(fmt.Sprintf("%s -> %s", tc.tokenIn.Denom, tc.tokenOutDenom), func(t *testing.T) {
+			bfsQuote, err := usecase.Router.GetOptimalQuote(context.Background(), tc.tokenIn, tc.tokenOutDenom, domain.WithMaxSplitRoutes(domain.DisableSplitRoutes), domain.WithCandidateRouteAlgorithm(domain.CandidateRouteAlgorithmBFS), domain.WithDisableCache())
+			s.Require().NoError(err)
 
-				// Validate that the resulting token out denom equals to the one set by the test
-				// Note that we set he curTokenInDenom to the tokenOutDenom of the last pool in the route
-				s.Require().Equal(tc.tokenOutDenom, curTokenInDenom)
-			}
+			// System under test
+			priceAwareQuote, err := usecase.Router.GetOptimalQuote(context.Background(), tc.tokenIn, tc.tokenOutDenom, domain.WithMaxSplitRoutes(domain.DisableSplitRoutes), domain.WithCandidateRouteAlgorithm(domain.CandidateRouteAlgorithmPriceAware), domain.WithDisableCache())
+			s.Require().NoError(err)
+			s.Require().True(priceAwareQuote.GetAmountOut().IsPositive())
+
+			bfsAmountOut, priceAwareAmountOut := bfsQuote.GetAmountOut(), priceAwareQuote.GetAmountOut()
+			t.Logf("bfs: %s, price-aware: %s, price-aware relative to bfs: %s", bfsAmountOut, priceAwareAmountOut, priceAwareAmountOut.ToLegacyDec().QuoMut(bfsAmountOut.ToLegacyDec()))
 		})
 	}
 }
@@ -173,6 +216,98 @@ func (s *RouterTestSuite) TestCandidateRouteSearcher_SkipPoolOption() {
 	s.Require().False(didFindExpectedPoolID)
 }
 
+// Validates that the candidate route searcher does not route through
+// the intermediary denoms configured to be skipped via the options.
+// The test is set up between OSMO and ATOM, skipping the intermediary denom
+// of the first multi-hop route found without the option.
+func (s *RouterTestSuite) TestCandidateRouteSearcher_SkipIntermediaryDenomOption() {
+	mainnetState := s.SetupMainnetState()
+
+	usecase := s.SetupRouterAndPoolsUsecase(mainnetState)
+
+	oneOSMOIn := sdk.NewCoin(UOSMO, defaultAmount)
+
+	routerConfig := usecase.Router.GetConfig()
+	candidateRouteOptions := domain.CandidateRouteSearchOptions{
+		MaxRoutes:           routerConfig.MaxRoutes,
+		MaxPoolsPerRoute:    routerConfig.MaxPoolsPerRoute,
+		MinPoolLiquidityCap: routerConfig.MinPoolLiquidityCap,
+	}
+
+	// System under test #1
+	candidateRoutes, err := usecase.CandidateRouteSearcher.FindCandidateRoutes(oneOSMOIn, ATOM, candidateRouteOptions)
+	s.Require().NoError(err)
+
+	intermediaryDenom := ""
+	for _, route := range candidateRoutes.Routes {
+		if len(route.Pools) > 1 {
+			intermediaryDenom = route.Pools[0].TokenOutDenom
+			break
+		}
+	}
+	s.Require().NotEmpty(intermediaryDenom)
+
+	// Now, skip the intermediary denom
+	candidateRouteOptions.IntermediaryDenomsToSkip = map[string]struct{}{
+		intermediaryDenom: {},
+	}
+
+	// System under test #2
+	candidateRoutes, err = usecase.CandidateRouteSearcher.FindCandidateRoutes(oneOSMOIn, ATOM, candidateRouteOptions)
+	s.Require().NoError(err)
+	s.Require().NotEmpty(candidateRoutes.Routes)
+
+	for _, route := range candidateRoutes.Routes {
+		for _, pool := range route.Pools[:len(route.Pools)-1] {
+			s.Require().NotEqual(intermediaryDenom, pool.TokenOutDenom)
+		}
+	}
+}
+
+// Validates that the candidate cycles start and end in the given denom, do not repeat pools
+// and respect the cycle options.
+func (s *RouterTestSuite) TestCandidateRouteSearcher_FindCandidateCycles() {
+	mainnetState := s.SetupMainnetState()
+
+	usecase := s.SetupRouterAndPoolsUsecase(mainnetState)
+
+	routerConfig := usecase.Router.GetConfig()
+	candidateRouteOptions := domain.CandidateRouteSearchOptions{
+		MaxRoutes:           20,
+		MaxPoolsPerRoute:    3,
+		MinPoolLiquidityCap: routerConfig.MinPoolLiquidityCap,
+	}
+
+	// System under test
+	cycles, err := usecase.CandidateRouteSearcher.FindCandidateCycles(UOSMO, candidateRouteOptions)
+	s.Require().NoError(err)
+	s.Require().NotEmpty(cycles.Routes)
+	s.Require().LessOrEqual(len(cycles.Routes), candidateRouteOptions.MaxRoutes)
+
+	for _, cycle := range cycles.Routes {
+		s.Require().GreaterOrEqual(len(cycle.Pools), 2)
+		s.Require().LessOrEqual(len(cycle.Pools), candidateRouteOptions.MaxPoolsPerRoute)
+
+		// Ends in the denom it starts in.
+		s.Require().Equal(UOSMO, cycle.Pools[len(cycle.Pools)-1].TokenOutDenom)
+
+		uniquePoolIDs := make(map[uint64]struct{}, len(cycle.Pools))
+		for _, pool := range cycle.Pools {
+			_, seen := uniquePoolIDs[pool.ID]
+			s.Require().False(seen)
+			uniquePoolIDs[pool.ID] = struct{}{}
+
+			_, ok := cycles.UniquePoolIDs[pool.ID]
+			s.Require().True(ok)
+		}
+	}
+
+	// A cycle requires at least two pools.
+	candidateRouteOptions.MaxPoolsPerRoute = 1
+	_, err = usecase.CandidateRouteSearcher.FindCandidateCycles(UOSMO, candidateRouteOptions)
+	s.Require().ErrorIs(err, domain.ErrCyclicArbMaxPoolsPerCycleNotValid)
+}
+
 func (s *RouterTestSuite) validateExpectedPoolIDOneHopRoute(route sqsdomain.CandidateRoute, expectedPoolID uint64) {
 	routePools := route.Pools
 	s.Require().Equal(1, len(routePools))
@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/osmosis-labs/sqs/domain"
+)
+
+const (
+	// computeDeadlineStageRanking is the stage of estimating and ranking the single route quotes.
+	computeDeadlineStageRanking = "ranking"
+	// computeDeadlineStageSplit is the stage of the dynamic programming over the splits.
+	computeDeadlineStageSplit = "split"
+	// computeDeadlineStageRefinement is the stage of refining the best split.
+	computeDeadlineStageRefinement = "refinement"
+
+	// computeDeadlineOutcomePartial is the outcome of returning the best quote found so far.
+	computeDeadlineOutcomePartial = "partial"
+	// computeDeadlineOutcomeError is the outcome of failing since no quote was found.
+	computeDeadlineOutcomeError = "error"
+)
+
+// withComputeDeadline returns the context that is cancelled with domain.ErrComputeDeadlineExceeded
+// as the cause once the given compute deadline elapses.
+// If the compute deadline is not positive, the given context is returned as is.
+func withComputeDeadline(ctx context.Context, computeDeadline time.Duration) (context.Context, context.CancelFunc) {
+	if computeDeadline <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeoutCause(ctx, computeDeadline, domain.ErrComputeDeadlineExceeded)
+}
+
+// isComputeDeadlineExceeded returns true if the compute deadline of the given context is exceeded.
+// The cancellation of the context for any other reason, such as the client disconnecting, is ignored.
+func isComputeDeadlineExceeded(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), domain.ErrComputeDeadlineExceeded)
+}
+
+// isComputeInterrupted returns true if the computation over the given context must stop, either since
+// the compute deadline is exceeded or since the router state guard timeout of the request is exceeded.
+// Unlike the former, the latter fails the request so the quotes computed so far are not flagged as partial for it.
+func isComputeInterrupted(ctx context.Context) bool {
+	return isComputeDeadlineExceeded(ctx) || errors.Is(context.Cause(ctx), domain.ErrRouterStateGuardTimeout)
+}
+
+// computeDeadlineError returns domain.ErrComputeDeadlineExceeded if the compute deadline was exceeded
+// at the given stage before any quote was found, recording it in the metrics. Otherwise, returns the given error.
+func computeDeadlineError(ctx context.Context, stage string, err error) error {
+	if !isComputeDeadlineExceeded(ctx) {
+		return err
+	}
+
+	domain.SQSQuoteComputeDeadlineExceededCounter.WithLabelValues(stage, computeDeadlineOutcomeError).Inc()
+
+	return domain.ErrComputeDeadlineExceeded
+}
+
+// setPartialQuote flags the given quote as partial since the compute deadline was exceeded
+// at the given stage, recording it in the metrics.
+func setPartialQuote(quote domain.Quote, stage string) {
+	if q, ok := quote.(*quoteExactAmountIn); ok {
+		q.Partial = true
+	}
+
+	domain.SQSQuoteComputeDeadlineExceededCounter.WithLabelValues(stage, computeDeadlineOutcomePartial).Inc()
+}
+
+// isPartialQuote returns true if the given quote is flagged as partial.
+func isPartialQuote(quote domain.Quote) bool {
+	q, ok := quote.(*quoteExactAmountIn)
+	return ok && q.Partial
+}
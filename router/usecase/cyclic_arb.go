@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	"github.com/osmosis-labs/sqs/domain"
+	"github.com/osmosis-labs/sqs/router/usecase/route"
+)
+
+const (
+	// maxCyclicArbSearchIterations bounds the number of ternary search iterations per cycle.
+	// Each iteration shrinks the search interval by a third so this covers amounts well beyond any pool balance.
+	maxCyclicArbSearchIterations = 256
+	// cyclicArbSearchFinalAmounts is the number of amounts evaluated exhaustively once the ternary search converges.
+	cyclicArbSearchFinalAmounts = 3
+	// cyclicArbPreScanBase is the ratio between the consecutive amounts evaluated by the pre-scan
+	// and the ratio of the ternary search interval bounds to the best pre-scanned amount.
+	cyclicArbPreScanBase = 10
+)
+
+// FindCyclicArbs implements mvc.RouterUsecase.
+// The candidate cycles are found by the candidate route searcher over the candidate route search data.
+// For each cycle, the amount in maximizing the profit is found by a ternary search over the routable pool math.
+// The cycles containing generalized CosmWasm pools are skipped since every step of the search would query the chain.
+// Returns the profitable cycles sorted by profit in descending order.
+// Returns error if:
+// - the max pools per cycle is less than 2
+// - fails to find the candidate cycles
+// - fails to convert the candidate cycles to routes
+// - the context is cancelled
+func (r *routerUseCaseImpl) FindCyclicArbs(ctx context.Context, denom string, options domain.CyclicArbOptions) ([]domain.CyclicArb, error) {
+	if options.MaxPoolsPerCycle == 0 {
+		options.MaxPoolsPerCycle = domain.DefaultCyclicArbMaxPoolsPerCycle
+	}
+	if options.MaxCycles == 0 {
+		options.MaxCycles = domain.DefaultCyclicArbMaxCycles
+	}
+	if options.MinPoolLiquidityCap == 0 {
+		options.MinPoolLiquidityCap = r.defaultConfig.MinPoolLiquidityCap
+	}
+
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	candidateCycles, err := r.candidateRouteSearcher.FindCandidateCycles(denom, domain.CandidateRouteSearchOptions{
+		MaxRoutes:           options.MaxCycles,
+		MaxPoolsPerRoute:    options.MaxPoolsPerCycle,
+		MinPoolLiquidityCap: options.MinPoolLiquidityCap,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	cycles, err := r.poolsUsecase.GetRoutesFromCandidates(candidateCycles, denom, denom)
+	if err != nil {
+		return nil, err
+	}
+
+	cyclicArbs := make([]domain.CyclicArb, 0, len(cycles))
+	for _, cycle := range cycles {
+		if ctx.Err() != nil {
+			return nil, context.Cause(ctx)
+		}
+
+		if cycle.ContainsGeneralizedCosmWasmPool() {
+			continue
+		}
+
+		maxAmountIn := options.MaxAmountIn
+		if maxAmountIn.IsNil() || maxAmountIn.IsZero() {
+			firstPool, err := r.poolsUsecase.GetPool(cycle.Pools[0].GetId())
+			if err != nil {
+				continue
+			}
+			maxAmountIn = firstPool.GetSQSPoolModel().Balances.AmountOf(denom)
+		}
+
+		cyclicArb, ok := findCyclicArbAmountIn(ctx, cycle, denom, maxAmountIn)
+		if !ok {
+			continue
+		}
+
+		cyclicArbs = append(cyclicArbs, cyclicArb)
+	}
+
+	sort.SliceStable(cyclicArbs, func(i, j int) bool {
+		return cyclicArbs[i].Profit.GT(cyclicArbs[j].Profit)
+	})
+
+	return cyclicArbs, nil
+}
+
+// findCyclicArbAmountIn searches for the amount in within [1, maxAmountIn] that maximizes the profit
+// of swapping the denom over the given cycle.
+// The amounts log-spaced by cyclicArbPreScanBase from maxAmountIn down to one are pre-scanned first
+// so that the ternary search is bracketed around the most profitable of them. Otherwise, the ternary search
+// over the whole range may converge to a less profitable local maximum at a different order of magnitude.
+// Within the bracket, the profit is assumed to be unimodal in the amount in. The amounts failing the swap are treated
+// as too large since the pools run out of liquidity.
+// Returns false if the cycle is not profitable for any of the evaluated amounts.
+func findCyclicArbAmountIn(ctx context.Context, cycle route.RouteImpl, denom string, maxAmountIn osmomath.Int) (domain.CyclicArb, bool) {
+	if maxAmountIn.IsNil() || !maxAmountIn.IsPositive() {
+		return domain.CyclicArb{}, false
+	}
+
+	// profitAt returns the amount out and the profit of swapping the given amount in over the cycle.
+	// Returns false if the swap fails.
+	profitAt := func(amountIn osmomath.Int) (osmomath.Int, osmomath.Int, bool) {
+		tokenOut, err := cycle.CalculateTokenOutByTokenIn(ctx, sdk.NewCoin(denom, amountIn))
+		if err != nil || tokenOut.Amount.IsNil() {
+			return osmomath.Int{}, osmomath.Int{}, false
+		}
+		return tokenOut.Amount, tokenOut.Amount.Sub(amountIn), true
+	}
+
+	var preScanAmountIn, preScanProfit osmomath.Int
+	for amountIn := maxAmountIn; amountIn.IsPositive(); amountIn = amountIn.QuoRaw(cyclicArbPreScanBase) {
+		_, profit, ok := profitAt(amountIn)
+		if ok && (preScanAmountIn.IsNil() || profit.GT(preScanProfit)) {
+			preScanAmountIn, preScanProfit = amountIn, profit
+		}
+	}
+
+	if preScanAmountIn.IsNil() {
+		return domain.CyclicArb{}, false
+	}
+
+	lo, hi := preScanAmountIn.QuoRaw(cyclicArbPreScanBase), osmomath.MinInt(preScanAmountIn.MulRaw(cyclicArbPreScanBase), maxAmountIn)
+	if !lo.IsPositive() {
+		lo = osmomath.OneInt()
+	}
+
+	for i := 0; i < maxCyclicArbSearchIterations && hi.Sub(lo).GT(osmomath.NewInt(2)); i++ {
+		third := hi.Sub(lo).QuoRaw(3)
+		left, right := lo.Add(third), hi.Sub(third)
+
+		_, leftProfit, leftOk := profitAt(left)
+		if !leftOk {
+			hi = left
+			continue
+		}
+
+		_, rightProfit, rightOk := profitAt(right)
+		if !rightOk || leftProfit.GTE(rightProfit) {
+			hi = right
+		} else {
+			lo = left
+		}
+	}
+
+	var (
+		bestAmountIn, bestAmountOut, bestProfit osmomath.Int
+		found                                   bool
+	)
+	for i, amountIn := 0, lo; i < cyclicArbSearchFinalAmounts && amountIn.LTE(hi); i, amountIn = i+1, amountIn.AddRaw(1) {
+		amountOut, profit, ok := profitAt(amountIn)
+		if !ok {
+			break
+		}
+
+		if !found || profit.GT(bestProfit) {
+			bestAmountIn, bestAmountOut, bestProfit = amountIn, amountOut, profit
+			found = true
+		}
+	}
+
+	if !found || !bestProfit.IsPositive() {
+		return domain.CyclicArb{}, false
+	}
+
+	pools := cycle.GetPools()
+	poolIDs := make([]uint64, 0, len(pools))
+	denoms := make([]string, 0, len(pools))
+	for _, pool := range pools {
+		poolIDs = append(poolIDs, pool.GetId())
+		denoms = append(denoms, pool.GetTokenOutDenom())
+	}
+
+	return domain.CyclicArb{
+		Denom:     denom,
+		PoolIDs:   poolIDs,
+		Denoms:    denoms,
+		AmountIn:  bestAmountIn,
+		AmountOut: bestAmountOut,
+		Profit:    bestProfit,
+		Pools:     pools,
+	}, true
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
@@ -12,19 +13,64 @@ import (
 	"github.com/osmosis-labs/sqs/router/usecase/route"
 )
 
+// generalizedCosmWasmPrefetchWorkers is the max number of goroutines that prefetch the amounts
+// of the routes containing generalized cosmwasm pools for a single split quote.
+const generalizedCosmWasmPrefetchWorkers = 8
+
 type split struct {
 	routeIncrements []uint8
 	amountOut       osmomath.Int
 }
 
-const totalIncrements = uint8(10)
+// splitOptions configures the granularity and the latency bounds of the split quote computation.
+type splitOptions struct {
+	// totalIncrements is the number of increments that the amount is divided into
+	// in the dynamic programming step.
+	totalIncrements uint8
+	// refinementRounds is the maximum number of refinement rounds performed
+	// around the best split found by the dynamic programming step.
+	// Zero disables the refinement.
+	refinementRounds int
+}
+
+// defaultSplitOptions are the split options used when none are configured.
+var defaultSplitOptions = splitOptions{
+	totalIncrements:  domain.DefaultSplitIncrements,
+	refinementRounds: 0,
+}
+
+// newSplitOptions returns the split options from the router options.
+// Falls back to domain.DefaultSplitIncrements if the increments are not set.
+// The increments and the refinement rounds are capped at domain.MaxSplitIncrements
+// and domain.MaxSplitRefinementRounds respectively.
+func newSplitOptions(routerOptions domain.RouterOptions) splitOptions {
+	options := defaultSplitOptions
+
+	if routerOptions.SplitIncrements > 0 {
+		options.totalIncrements = uint8(min(routerOptions.SplitIncrements, domain.MaxSplitIncrements))
+	}
+
+	if routerOptions.SplitRefinementRounds > 0 {
+		options.refinementRounds = min(routerOptions.SplitRefinementRounds, domain.MaxSplitRefinementRounds)
+	}
+
+	return options
+}
 
 // getSplitQuote returns the best quote for the given routes and tokenIn.
 // It uses dynamic programming to find the optimal split of the tokenIn among the routes.
 // The algorithm is based on the knapsack problem.
-// The time complexity is O(n * m), where n is the number of routes and m is the totalIncrements.
+// The time complexity is O(n * m^2), where n is the number of routes and m is the totalIncrements.
 // The space complexity is O(n * m).
-func getSplitQuote(ctx context.Context, routes []route.RouteImpl, tokenIn sdk.Coin) (domain.Quote, error) {
+//
+// If refinement rounds are configured, the best split is then refined beyond the granularity
+// of the increments. See refineSplit for details.
+//
+// If the compute deadline is exceeded while filling the tables, the cause is returned as the error.
+// If it is exceeded while refining, the best split found so far is returned flagged as partial.
+func getSplitQuote(ctx context.Context, routes []route.RouteImpl, tokenIn sdk.Coin, options splitOptions) (domain.Quote, error) {
+	totalIncrements := options.totalIncrements
+
 	// Routes must be non-empty
 	if len(routes) == 0 {
 		return nil, errors.New("no routes")
@@ -74,10 +120,16 @@ func getSplitQuote(ctx context.Context, routes []route.RouteImpl, tokenIn sdk.Co
 	inAmountDec := tokenIn.Amount.ToLegacyDec()
 
 	// callback with caching capabilities.
-	computeAndCacheOutAmountCb := getComputeAndCacheOutAmountCb(ctx, inAmountDec, tokenIn.Denom, routes)
+	computeAndCacheOutAmountCb := getComputeAndCacheOutAmountCb(ctx, inAmountDec, tokenIn.Denom, routes, totalIncrements)
 
 	// Step 2: fill the tables
 	for x := uint8(1); x <= totalIncrements; x++ {
+		// The tables filled so far only split a fraction of the amount, so the split
+		// is abandoned once the compute deadline is exceeded.
+		if isComputeInterrupted(ctx) {
+			return nil, context.Cause(ctx)
+		}
+
 		for j := 1; j <= len(routes); j++ {
 			dp[x][j] = dp[x][j-1] // Not using the j-th route
 			proportions[x][j] = 0 // Default increment (0% of the token)
@@ -100,6 +152,11 @@ func getSplitQuote(ctx context.Context, routes []route.RouteImpl, tokenIn sdk.Co
 		}
 	}
 
+	// The routes may fail to be quoted after the compute deadline is exceeded, leaving the tables incomplete.
+	if isComputeInterrupted(ctx) {
+		return nil, context.Cause(ctx)
+	}
+
 	// Step 3: trace back to find the optimal proportions
 	x, j := totalIncrements, len(routes)
 	optimalProportions := make([]uint8, len(routes)+1)
@@ -170,18 +227,186 @@ func getSplitQuote(ctx context.Context, routes []route.RouteImpl, tokenIn sdk.Co
 		return nil, fmt.Errorf("total increments (%d) does not match expected total increments (%d)", totalIncrementsInSplits, totalIncrements)
 	}
 
+	isPartial := false
+	if options.refinementRounds > 0 {
+		resultRoutes, bestSplit.amountOut = refineSplitQuote(ctx, routes, bestSplit.routeIncrements, tokenIn, totalIncrements, options.refinementRounds, resultRoutes, bestSplit.amountOut)
+
+		// The refinement keeps the best split found so far once the compute deadline is exceeded.
+		isPartial = isComputeDeadlineExceeded(ctx)
+	}
+
 	quote := &quoteExactAmountIn{
 		AmountIn:  tokenIn,
 		AmountOut: bestSplit.amountOut,
 		Route:     resultRoutes,
+		Partial:   isPartial,
 	}
 
 	return quote, nil
 }
 
+// refineSplitQuote refines the split found by the dynamic programming step beyond
+// the granularity of the increments. Returns the refined result routes and the total amount out.
+// If the refinement does not improve the amount out, the given result routes and amount out are returned.
+func refineSplitQuote(ctx context.Context, routes []route.RouteImpl, routeIncrements []uint8, tokenIn sdk.Coin, totalIncrements uint8, refinementRounds int, resultRoutes []domain.SplitRoute, amountOut osmomath.Int) ([]domain.SplitRoute, osmomath.Int) {
+	tokenAmountDec := tokenIn.Amount.ToLegacyDec()
+
+	// Convert the increments into the in amounts.
+	inAmounts := make([]osmomath.Int, len(routes))
+	for i, currentRouteIncrement := range routeIncrements {
+		inAmounts[i] = sdk.NewDec(int64(currentRouteIncrement)).QuoInt64Mut(int64(totalIncrements)).MulMut(tokenAmountDec).TruncateInt()
+	}
+
+	evaluateRouteCb := func(routeIndex int, inAmount osmomath.Int) (osmomath.Int, bool) {
+		// Reject the remaining moves once the compute deadline is exceeded.
+		if isComputeInterrupted(ctx) {
+			return osmomath.Int{}, false
+		}
+
+		coinOut, err := routes[routeIndex].CalculateTokenOutByTokenIn(ctx, sdk.NewCoin(tokenIn.Denom, inAmount))
+		if err != nil {
+			return osmomath.Int{}, false
+		}
+
+		if coinOut.IsNil() || coinOut.IsZero() {
+			return zero, true
+		}
+
+		return coinOut.Amount, true
+	}
+
+	// Initial step is half of the increment.
+	initialStep := tokenIn.Amount.QuoRaw(2 * int64(totalIncrements))
+
+	refinedInAmounts, refinedOutAmounts, refinedAmountOut := refineSplit(inAmounts, initialStep, refinementRounds, evaluateRouteCb, func(candidate, best osmomath.Int) bool {
+		return candidate.GT(best)
+	})
+
+	if !refinedAmountOut.GT(amountOut) {
+		return resultRoutes, amountOut
+	}
+
+	refinedResultRoutes := make([]domain.SplitRoute, 0, len(routes))
+	for i := range routes {
+		if refinedInAmounts[i].IsZero() || refinedOutAmounts[i].IsZero() {
+			continue
+		}
+
+		refinedResultRoutes = append(refinedResultRoutes, &RouteWithOutAmount{
+			RouteImpl: routes[i],
+			InAmount:  refinedInAmounts[i],
+			OutAmount: refinedOutAmounts[i],
+		})
+	}
+
+	return refinedResultRoutes, refinedAmountOut
+}
+
+// refineSplit performs a local search around the given split of amounts across routes.
+// In every round, it attempts to move the step amount from one route to another for every pair of routes,
+// applying the move that leads to the best total value as determined by isBetter.
+// If no move improves the total value, the step is halved. The search stops when either
+// the refinement rounds are exhausted or the step becomes zero.
+//
+// evaluateRouteCb returns the value of the given route for the given amount and false if the route
+// fails to process the amount. The results are cached so that each route and amount pair is evaluated at most once.
+// Returns the refined amounts, the values of each route and the total value.
+//
+// The time complexity is O(r * n^2), where r is the number of refinement rounds and n is the number of routes.
+func refineSplit(amounts []osmomath.Int, step osmomath.Int, refinementRounds int, evaluateRouteCb func(routeIndex int, amount osmomath.Int) (osmomath.Int, bool), isBetter func(candidate, best osmomath.Int) bool) ([]osmomath.Int, []osmomath.Int, osmomath.Int) {
+	type evaluation struct {
+		value osmomath.Int
+		ok    bool
+	}
+
+	cache := make([]map[string]evaluation, len(amounts))
+	for i := range cache {
+		cache[i] = make(map[string]evaluation)
+	}
+
+	evaluateCb := func(routeIndex int, amount osmomath.Int) (osmomath.Int, bool) {
+		if amount.IsZero() {
+			return zero, true
+		}
+
+		key := amount.String()
+		if result, ok := cache[routeIndex][key]; ok {
+			return result.value, result.ok
+		}
+
+		value, ok := evaluateRouteCb(routeIndex, amount)
+		cache[routeIndex][key] = evaluation{value: value, ok: ok}
+		return value, ok
+	}
+
+	refinedAmounts := make([]osmomath.Int, len(amounts))
+	values := make([]osmomath.Int, len(amounts))
+	totalValue := osmomath.ZeroInt()
+	for i, amount := range amounts {
+		value, ok := evaluateCb(i, amount)
+		if !ok {
+			// The given split must be valid.
+			return amounts, nil, osmomath.ZeroInt()
+		}
+
+		refinedAmounts[i] = amount
+		values[i] = value
+		totalValue = totalValue.Add(value)
+	}
+
+	for round := 0; round < refinementRounds && step.IsPositive(); round++ {
+		bestFrom, bestTo := -1, -1
+		bestTotalValue := totalValue
+		var bestFromValue, bestToValue osmomath.Int
+
+		for from := range refinedAmounts {
+			if refinedAmounts[from].LT(step) {
+				continue
+			}
+
+			fromValue, ok := evaluateCb(from, refinedAmounts[from].Sub(step))
+			if !ok {
+				continue
+			}
+
+			for to := range refinedAmounts {
+				if to == from {
+					continue
+				}
+
+				toValue, ok := evaluateCb(to, refinedAmounts[to].Add(step))
+				if !ok {
+					continue
+				}
+
+				candidateTotalValue := totalValue.Sub(values[from]).Sub(values[to]).Add(fromValue).Add(toValue)
+				if isBetter(candidateTotalValue, bestTotalValue) {
+					bestFrom, bestTo = from, to
+					bestTotalValue = candidateTotalValue
+					bestFromValue, bestToValue = fromValue, toValue
+				}
+			}
+		}
+
+		// No improving move at the current granularity, make it finer.
+		if bestFrom == -1 {
+			step = step.QuoRaw(2)
+			continue
+		}
+
+		refinedAmounts[bestFrom] = refinedAmounts[bestFrom].Sub(step)
+		refinedAmounts[bestTo] = refinedAmounts[bestTo].Add(step)
+		values[bestFrom] = bestFromValue
+		values[bestTo] = bestToValue
+		totalValue = bestTotalValue
+	}
+
+	return refinedAmounts, values, totalValue
+}
+
 // This function computes the inAmountIncrement for a given proportion p.
 // It caches the result on the stack to avoid recomputing it.
-func getComputeAndCacheInAmountIncrementCb(totalInAmountDec osmomath.Dec) func(p uint8) osmomath.Int {
+func getComputeAndCacheInAmountIncrementCb(totalInAmountDec osmomath.Dec, totalIncrements uint8) func(p uint8) osmomath.Int {
 	inAmountIncrements := make(map[uint8]osmomath.Int, totalIncrements)
 	return func(p uint8) osmomath.Int {
 		// If the inAmountIncrement has already been computed, return the cached value.
@@ -200,7 +425,7 @@ func getComputeAndCacheInAmountIncrementCb(totalInAmountDec osmomath.Dec) func(p
 
 // This function computes the outAmountIncrement for a given routeIndex and inAmountIncrement.
 // It caches the result on the stack to avoid recomputing it.
-func getComputeAndCacheOutAmountCb(ctx context.Context, totalInAmountDec osmomath.Dec, tokenInDenom string, routes []route.RouteImpl) func(int, uint8) osmomath.Int {
+func getComputeAndCacheOutAmountCb(ctx context.Context, totalInAmountDec osmomath.Dec, tokenInDenom string, routes []route.RouteImpl, totalIncrements uint8) func(int, uint8) osmomath.Int {
 	// Pre-compute routes cache map.
 	routeOutAmtCache := make(map[int]map[uint8]osmomath.Int, len(routes))
 	for routeIndex := 0; routeIndex < len(routes); routeIndex++ {
@@ -208,7 +433,20 @@ func getComputeAndCacheOutAmountCb(ctx context.Context, totalInAmountDec osmomat
 	}
 
 	// Get callback with in amount increment capabilities.
-	computeAndCacheInAmountIncrementCb := getComputeAndCacheInAmountIncrementCb(totalInAmountDec)
+	computeAndCacheInAmountIncrementCb := getComputeAndCacheInAmountIncrementCb(totalInAmountDec, totalIncrements)
+
+	computeOutAmount := func(routeIndex int, inAmountIncrement osmomath.Int) osmomath.Int {
+		// This is the expensive computation that we aim to avoid.
+		curRouteOutAmountIncrement, _ := routes[routeIndex].CalculateTokenOutByTokenIn(ctx, sdk.NewCoin(tokenInDenom, inAmountIncrement))
+
+		if curRouteOutAmountIncrement.IsNil() || curRouteOutAmountIncrement.IsZero() {
+			curRouteOutAmountIncrement.Amount = zero
+		}
+
+		return curRouteOutAmountIncrement.Amount
+	}
+
+	prefetchGeneralizedCosmWasmRouteAmounts(routes, totalIncrements, computeAndCacheInAmountIncrementCb, routeOutAmtCache, computeOutAmount)
 
 	return func(routeIndex int, increment uint8) osmomath.Int {
 		inAmountIncrement := computeAndCacheInAmountIncrementCb(increment)
@@ -217,15 +455,338 @@ func getComputeAndCacheOutAmountCb(ctx context.Context, totalInAmountDec osmomat
 		if ok {
 			return curRouteAmt
 		}
+
+		curRouteAmt = computeOutAmount(routeIndex, inAmountIncrement)
+
+		routeOutAmtCache[routeIndex][increment] = curRouteAmt
+
+		return curRouteAmt
+	}
+}
+
+// prefetchGeneralizedCosmWasmRouteAmounts concurrently computes the amounts for all non-zero increments
+// of the routes that contain generalized cosmwasm pools and stores them in the given cache.
+// Each such computation makes network requests to chain that would otherwise be made one at a time
+// by the dynamic programming step. The amounts are computed by at most generalizedCosmWasmPrefetchWorkers
+// goroutines while the number of concurrent requests across all quotes is further bounded by the calc query memo
+// of the pools.
+// CONTRACT: computeAmountCb is safe for concurrent use.
+func prefetchGeneralizedCosmWasmRouteAmounts(routes []route.RouteImpl, totalIncrements uint8, amountIncrementCb func(uint8) osmomath.Int, routeAmtCache map[int]map[uint8]osmomath.Int, computeAmountCb func(routeIndex int, amountIncrement osmomath.Int) osmomath.Int) {
+	type prefetchedAmount struct {
+		routeIndex int
+		increment  uint8
+		amount     osmomath.Int
+	}
+
+	prefetchedAmounts := make([]*prefetchedAmount, 0)
+	for routeIndex, route := range routes {
+		if !route.ContainsGeneralizedCosmWasmPool() {
+			continue
+		}
+
+		for increment := uint8(1); increment <= totalIncrements; increment++ {
+			prefetchedAmounts = append(prefetchedAmounts, &prefetchedAmount{
+				routeIndex: routeIndex,
+				increment:  increment,
+				amount:     amountIncrementCb(increment),
+			})
+		}
+	}
+
+	if len(prefetchedAmounts) == 0 {
+		return
+	}
+
+	prefetchedAmountsCh := make(chan *prefetchedAmount, len(prefetchedAmounts))
+	for _, prefetched := range prefetchedAmounts {
+		prefetchedAmountsCh <- prefetched
+	}
+	close(prefetchedAmountsCh)
+
+	numWorkers := min(generalizedCosmWasmPrefetchWorkers, len(prefetchedAmounts))
+
+	var wg sync.WaitGroup
+	wg.Add(numWorkers)
+	for i := 0; i < numWorkers; i++ {
+		go func() {
+			defer wg.Done()
+			for prefetched := range prefetchedAmountsCh {
+				prefetched.amount = computeAmountCb(prefetched.routeIndex, prefetched.amount)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, prefetched := range prefetchedAmounts {
+		routeAmtCache[prefetched.routeIndex][prefetched.increment] = prefetched.amount
+	}
+}
+
+// getSplitQuoteInGivenOut returns the best exact amount out quote for the given routes and tokenOut.
+// It is the inverse of getSplitQuote. The tokenOut is split among the routes in increments
+// so that the total amount of token in required to receive the tokenOut is minimized.
+// The routes must be ordered from token in to token out.
+//
+// The states that can not be satisfied by the routes are represented by nil values in the dp table.
+// The remainder of the tokenOut that is lost to truncation of the increments is assigned to the last route used
+// with its token in amount recomputed.
+//
+// If refinement rounds are configured, the best split is then refined beyond the granularity
+// of the increments. See refineSplit for details.
+//
+// The returned quote is over the routes from token in to token out.
+func getSplitQuoteInGivenOut(ctx context.Context, routes []route.RouteImpl, tokenOut sdk.Coin, tokenInDenom string, options splitOptions) (*quoteExactAmountIn, error) {
+	totalIncrements := options.totalIncrements
+
+	// Routes must be non-empty
+	if len(routes) == 0 {
+		return nil, errors.New("no routes")
+	}
+	// If only one route, return the best single route quote
+	if len(routes) == 1 {
+		route := routes[0]
+		coinIn, err := route.CalculateTokenInByTokenOut(ctx, tokenOut)
+		if err != nil {
+			return nil, err
+		}
+
+		quote := &quoteExactAmountIn{
+			AmountIn:  sdk.NewCoin(tokenInDenom, coinIn.Amount),
+			AmountOut: tokenOut.Amount,
+			Route: []domain.SplitRoute{&RouteWithOutAmount{
+				RouteImpl: route,
+				OutAmount: tokenOut.Amount,
+				InAmount:  coinIn.Amount,
+			}},
+		}
+
+		return quote, nil
+	}
+
+	// proportions[x][j] stores the proportion of token out received from the j-th
+	// route that leads to the optimal value at each state.
+	proportions := make([][]uint8, totalIncrements+1)
+	// dp stores the minimum input values. Nil value signifies that the state
+	// can not be satisfied.
+	dp := make([][]osmomath.Int, totalIncrements+1)
+
+	// Step 1: initialize tables
+	for i := 0; i < int(totalIncrements+1); i++ {
+		dp[i] = make([]osmomath.Int, len(routes)+1)
+
+		proportions[i] = make([]uint8, len(routes)+1)
+	}
+
+	// Initialize the first column with 0
+	for j := 0; j <= len(routes); j++ {
+		dp[0][j] = zero
+	}
+
+	// callback with caching capabilities.
+	computeAndCacheInAmountCb := getComputeAndCacheInAmountCb(ctx, tokenOut.Amount.ToLegacyDec(), tokenOut.Denom, routes, totalIncrements)
+
+	// Step 2: fill the tables
+	for x := uint8(1); x <= totalIncrements; x++ {
+		for j := 1; j <= len(routes); j++ {
+			dp[x][j] = dp[x][j-1] // Not using the j-th route
+			proportions[x][j] = 0 // Default increment (0% of the token)
+
+			for p := uint8(1); p <= x; p++ {
+				// The recurrence relation is:
+				// dp[x][j] = min(dp[x][j−1], dp[x−p][j−1] + input to j-th route for proportion p of output)
+				previous := dp[x-p][j-1]
+				if previous.IsNil() {
+					continue
+				}
+
+				routeInAmount := computeAndCacheInAmountCb(j-1, p)
+				if routeInAmount.IsNil() {
+					continue
+				}
+
+				choice := previous.Add(routeInAmount)
+
+				if dp[x][j].IsNil() || choice.LT(dp[x][j]) {
+					dp[x][j] = choice
+					proportions[x][j] = p
+				}
+			}
+		}
+	}
+
+	if dp[totalIncrements][len(routes)].IsNil() {
+		return nil, fmt.Errorf("no split of routes can produce the token out (%s)", tokenOut)
+	}
+
+	// Step 3: trace back to find the optimal proportions
+	x, j := totalIncrements, len(routes)
+	optimalProportions := make([]uint8, len(routes)+1)
+	for j > 0 {
+		optimalProportions[j] = proportions[x][j]
+		x -= proportions[x][j]
+		j -= 1
+	}
+
+	optimalProportions = optimalProportions[1:]
+
+	// Step 4: construct the result routes
+	computeAndCacheOutAmountIncrementCb := getComputeAndCacheInAmountIncrementCb(tokenOut.Amount.ToLegacyDec(), totalIncrements)
+
+	resultRoutes := make([]*RouteWithOutAmount, 0, len(routes))
+	resultRouteIndexes := make([]int, 0, len(routes))
+	totalAmountInFromSplits := osmomath.ZeroInt()
+	totalAmountOutFromSplits := osmomath.ZeroInt()
+	for i, currentRouteIncrement := range optimalProportions {
+		outAmount := computeAndCacheOutAmountIncrementCb(currentRouteIncrement)
+		if outAmount.IsZero() {
+			continue
+		}
+
+		inAmount := computeAndCacheInAmountCb(i, currentRouteIncrement)
+
+		resultRoutes = append(resultRoutes, &RouteWithOutAmount{
+			RouteImpl: routes[i],
+			InAmount:  inAmount,
+			OutAmount: outAmount,
+		})
+		resultRouteIndexes = append(resultRouteIndexes, i)
+
+		totalAmountInFromSplits = totalAmountInFromSplits.Add(inAmount)
+		totalAmountOutFromSplits = totalAmountOutFromSplits.Add(outAmount)
+	}
+
+	if len(resultRoutes) == 0 {
+		return nil, errors.New("amount out is too small to split, try increasing amount out")
+	}
+
+	// Assign the remainder lost to truncation to the last route.
+	remainder := tokenOut.Amount.Sub(totalAmountOutFromSplits)
+	if remainder.IsPositive() {
+		lastRoute := resultRoutes[len(resultRoutes)-1]
+
+		lastRouteOutAmount := lastRoute.OutAmount.Add(remainder)
+		lastRouteCoinIn, err := lastRoute.CalculateTokenInByTokenOut(ctx, sdk.NewCoin(tokenOut.Denom, lastRouteOutAmount))
+		if err != nil {
+			return nil, err
+		}
+
+		totalAmountInFromSplits = totalAmountInFromSplits.Sub(lastRoute.InAmount).Add(lastRouteCoinIn.Amount)
+
+		lastRoute.InAmount = lastRouteCoinIn.Amount
+		lastRoute.OutAmount = lastRouteOutAmount
+	}
+
+	splitRoutes := make([]domain.SplitRoute, 0, len(resultRoutes))
+	for _, resultRoute := range resultRoutes {
+		splitRoutes = append(splitRoutes, resultRoute)
+	}
+
+	if options.refinementRounds > 0 {
+		// Convert the result routes into the out amounts of every route.
+		outAmounts := make([]osmomath.Int, len(routes))
+		for i := range outAmounts {
+			outAmounts[i] = zero
+		}
+		for i, resultRoute := range resultRoutes {
+			outAmounts[resultRouteIndexes[i]] = resultRoute.OutAmount
+		}
+
+		splitRoutes, totalAmountInFromSplits = refineSplitQuoteInGivenOut(ctx, routes, outAmounts, tokenOut, totalIncrements, options.refinementRounds, splitRoutes, totalAmountInFromSplits)
+	}
+
+	quote := &quoteExactAmountIn{
+		AmountIn:  sdk.NewCoin(tokenInDenom, totalAmountInFromSplits),
+		AmountOut: tokenOut.Amount,
+		Route:     splitRoutes,
+	}
+
+	return quote, nil
+}
+
+// refineSplitQuoteInGivenOut refines the split of the token out found by the dynamic programming step beyond
+// the granularity of the increments so that the total amount of token in is minimized.
+// Returns the refined result routes and the total amount in.
+// If the refinement does not reduce the amount in, the given result routes and amount in are returned.
+func refineSplitQuoteInGivenOut(ctx context.Context, routes []route.RouteImpl, outAmounts []osmomath.Int, tokenOut sdk.Coin, totalIncrements uint8, refinementRounds int, resultRoutes []domain.SplitRoute, amountIn osmomath.Int) ([]domain.SplitRoute, osmomath.Int) {
+	evaluateRouteCb := func(routeIndex int, outAmount osmomath.Int) (osmomath.Int, bool) {
+		coinIn, err := routes[routeIndex].CalculateTokenInByTokenOut(ctx, sdk.NewCoin(tokenOut.Denom, outAmount))
+		if err != nil || coinIn.IsNil() || coinIn.IsZero() {
+			// The route can not produce the out amount.
+			return osmomath.Int{}, false
+		}
+
+		return coinIn.Amount, true
+	}
+
+	// Initial step is half of the increment.
+	initialStep := tokenOut.Amount.QuoRaw(2 * int64(totalIncrements))
+
+	refinedOutAmounts, refinedInAmounts, refinedAmountIn := refineSplit(outAmounts, initialStep, refinementRounds, evaluateRouteCb, func(candidate, best osmomath.Int) bool {
+		return candidate.LT(best)
+	})
+
+	// The refinement fails to evaluate the given split if the total is zero.
+	if refinedAmountIn.IsZero() || !refinedAmountIn.LT(amountIn) {
+		return resultRoutes, amountIn
+	}
+
+	refinedResultRoutes := make([]domain.SplitRoute, 0, len(routes))
+	for i := range routes {
+		if refinedOutAmounts[i].IsZero() {
+			continue
+		}
+
+		refinedResultRoutes = append(refinedResultRoutes, &RouteWithOutAmount{
+			RouteImpl: routes[i],
+			InAmount:  refinedInAmounts[i],
+			OutAmount: refinedOutAmounts[i],
+		})
+	}
+
+	return refinedResultRoutes, refinedAmountIn
+}
+
+// This function computes the token in required by the route at routeIndex to receive
+// the out amount increment for the given proportion.
+// It caches the result on the stack to avoid recomputing it.
+// Returns nil Int if the route fails to produce the out amount increment.
+func getComputeAndCacheInAmountCb(ctx context.Context, totalOutAmountDec osmomath.Dec, tokenOutDenom string, routes []route.RouteImpl, totalIncrements uint8) func(int, uint8) osmomath.Int {
+	// Pre-compute routes cache map.
+	routeInAmtCache := make(map[int]map[uint8]osmomath.Int, len(routes))
+	for routeIndex := 0; routeIndex < len(routes); routeIndex++ {
+		routeInAmtCache[routeIndex] = make(map[uint8]osmomath.Int, totalIncrements+1)
+	}
+
+	// Note that the increments are computed the same way for the token out.
+	computeAndCacheOutAmountIncrementCb := getComputeAndCacheInAmountIncrementCb(totalOutAmountDec, totalIncrements)
+
+	computeInAmount := func(routeIndex int, outAmountIncrement osmomath.Int) osmomath.Int {
+		if outAmountIncrement.IsZero() {
+			return zero
+		}
+
 		// This is the expensive computation that we aim to avoid.
-		curRouteOutAmountIncrement, _ := routes[routeIndex].CalculateTokenOutByTokenIn(ctx, sdk.NewCoin(tokenInDenom, inAmountIncrement))
+		curRouteInAmountIncrement, err := routes[routeIndex].CalculateTokenInByTokenOut(ctx, sdk.NewCoin(tokenOutDenom, outAmountIncrement))
+		if err != nil || curRouteInAmountIncrement.IsNil() || curRouteInAmountIncrement.IsZero() {
+			// Signifies that the route can not produce the out amount increment.
+			curRouteInAmountIncrement.Amount = osmomath.Int{}
+		}
 
-		if curRouteOutAmountIncrement.IsNil() || curRouteOutAmountIncrement.IsZero() {
-			curRouteOutAmountIncrement.Amount = zero
+		return curRouteInAmountIncrement.Amount
+	}
+
+	prefetchGeneralizedCosmWasmRouteAmounts(routes, totalIncrements, computeAndCacheOutAmountIncrementCb, routeInAmtCache, computeInAmount)
+
+	return func(routeIndex int, increment uint8) osmomath.Int {
+		curRouteAmt, ok := routeInAmtCache[routeIndex][increment]
+		if ok {
+			return curRouteAmt
 		}
 
-		routeOutAmtCache[routeIndex][increment] = curRouteOutAmountIncrement.Amount
+		curRouteAmt = computeInAmount(routeIndex, computeAndCacheOutAmountIncrementCb(increment))
 
-		return curRouteOutAmountIncrement.Amount
+		routeInAmtCache[routeIndex][increment] = curRouteAmt
+
+		return curRouteAmt
 	}
 }
@@ -6,6 +6,7 @@ import (
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/osmosis-labs/osmosis/osmomath"
+	"github.com/osmosis-labs/sqs/domain"
 	"github.com/osmosis-labs/sqs/router/usecase"
 )
 
@@ -36,3 +37,42 @@ func BenchmarkGetSplitQuote(b *testing.B) {
 		}
 	}
 }
+
+// Microbenchmark for the GetSplitQuote function with different granularity and refinement options.
+func BenchmarkGetSplitQuoteWithOptions(b *testing.B) {
+	// This is a hack to be able to use test suite helpers with the benchmark.
+	// We need to set testing.T for assertings within the helpers. Otherwise, it would block
+	s := RouterTestSuite{}
+	s.SetT(&testing.T{})
+
+	const displayDenomIn = "pepe"
+	var (
+		amountIn = osmomath.NewInt(9_000_000_000_000_000_000)
+	)
+
+	tokenIn, rankedRoutes := s.setupSplitsMainnetTestCase(displayDenomIn, amountIn, USDC)
+
+	benchmarks := []struct {
+		name    string
+		options domain.RouterOptions
+	}{
+		{name: "10 increments", options: domain.RouterOptions{SplitIncrements: 10}},
+		{name: "20 increments", options: domain.RouterOptions{SplitIncrements: 20}},
+		{name: "50 increments", options: domain.RouterOptions{SplitIncrements: 50}},
+		{name: "10 increments, 8 refinement rounds", options: domain.RouterOptions{SplitIncrements: 10, SplitRefinementRounds: 8}},
+		{name: "10 increments, 32 refinement rounds", options: domain.RouterOptions{SplitIncrements: 10, SplitRefinementRounds: 32}},
+	}
+
+	for _, bm := range benchmarks {
+		bm := bm
+		b.Run(bm.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				// System under test.
+				_, err := usecase.GetSplitQuoteWithOptions(context.TODO(), rankedRoutes, tokenIn, bm.options)
+				if err != nil {
+					b.Errorf("GetSplitQuoteWithOptions returned an error: %v", err)
+				}
+			}
+		})
+	}
+}
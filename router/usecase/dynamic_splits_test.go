@@ -2,10 +2,16 @@ package usecase_test
 
 import (
 	"context"
+	"errors"
+	"sync/atomic"
+	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/osmosis-labs/osmosis/osmomath"
 	"github.com/osmosis-labs/sqs/domain"
+	"github.com/osmosis-labs/sqs/domain/cache"
+	"github.com/osmosis-labs/sqs/domain/mocks"
+	"github.com/osmosis-labs/sqs/log"
 	"github.com/osmosis-labs/sqs/router/usecase"
 	"github.com/osmosis-labs/sqs/router/usecase/route"
 	"github.com/osmosis-labs/sqs/router/usecase/routertesting"
@@ -27,6 +33,324 @@ func (s *RouterTestSuite) TestGetSplitQuote() {
 	s.Require().NoError(err)
 }
 
+// Validates that the token out is split among the routes so that the total token in is minimized.
+// The first route requires as much token in as token out but can only produce up to half of the token out.
+// The second route requires twice as much token in as token out.
+func (s *RouterTestSuite) TestGetSplitQuoteInGivenOut() {
+	var (
+		tokenOut     = sdk.NewCoin(USDC, osmomath.NewInt(1_000))
+		tokenInDenom = ETH
+
+		cheapLimitedRoute = route.RouteImpl{
+			Pools: []domain.RoutablePool{
+				&mocks.MockRoutablePool{ID: 1, TakerFee: osmomath.ZeroDec(), CalculateTokenInByTokenOutFunc: func(ctx context.Context, tokenOut sdk.Coin) (sdk.Coin, error) {
+					if tokenOut.Amount.GT(osmomath.NewInt(500)) {
+						return sdk.Coin{}, errors.New("not enough liquidity")
+					}
+					return sdk.NewCoin(tokenInDenom, tokenOut.Amount), nil
+				}},
+			},
+		}
+
+		expensiveRoute = route.RouteImpl{
+			Pools: []domain.RoutablePool{
+				&mocks.MockRoutablePool{ID: 2, TakerFee: osmomath.ZeroDec(), CalculateTokenInByTokenOutFunc: func(ctx context.Context, tokenOut sdk.Coin) (sdk.Coin, error) {
+					return sdk.NewCoin(tokenInDenom, tokenOut.Amount.MulRaw(2)), nil
+				}},
+			},
+		}
+	)
+
+	splitQuote, err := usecase.GetSplitQuoteInGivenOut(context.TODO(), []route.RouteImpl{cheapLimitedRoute, expensiveRoute}, tokenOut, tokenInDenom)
+	s.Require().NoError(err)
+
+	// 500 through the first route and 500 * 2 through the second route.
+	s.Require().Equal(sdk.NewCoin(tokenInDenom, osmomath.NewInt(1_500)), splitQuote.GetAmountIn())
+	s.Require().Equal(tokenOut.Amount, splitQuote.GetAmountOut())
+
+	routes := splitQuote.GetRoute()
+	s.Require().Len(routes, 2)
+
+	s.Require().Equal(osmomath.NewInt(500), routes[0].GetAmountIn())
+	s.Require().Equal(osmomath.NewInt(500), routes[0].GetAmountOut())
+	s.Require().Equal(osmomath.NewInt(1_000), routes[1].GetAmountIn())
+	s.Require().Equal(osmomath.NewInt(500), routes[1].GetAmountOut())
+
+	// The first route alone can not produce the token out.
+	_, err = usecase.GetSplitQuoteInGivenOut(context.TODO(), []route.RouteImpl{cheapLimitedRoute}, tokenOut, tokenInDenom)
+	s.Require().Error(err)
+}
+
+// Validates that the refinement of the exact amount out split finds a split requiring less token in
+// than the one restricted to the increments.
+// The first route requires as much token in as token out but can only produce up to 550 of the token out.
+// The second route requires twice as much token in as token out.
+// With 10 increments, the best split is 500/500 for a total of 1500 in.
+// With refinement, the best split is 550/450 for a total of 1450 in.
+func (s *RouterTestSuite) TestGetSplitQuoteInGivenOut_Refinement() {
+	var (
+		tokenOut     = sdk.NewCoin(USDC, osmomath.NewInt(1_000))
+		tokenInDenom = ETH
+
+		cheapLimitedRoute = route.RouteImpl{
+			Pools: []domain.RoutablePool{
+				&mocks.MockRoutablePool{ID: 1, TakerFee: osmomath.ZeroDec(), CalculateTokenInByTokenOutFunc: func(ctx context.Context, tokenOut sdk.Coin) (sdk.Coin, error) {
+					if tokenOut.Amount.GT(osmomath.NewInt(550)) {
+						return sdk.Coin{}, errors.New("not enough liquidity")
+					}
+					return sdk.NewCoin(tokenInDenom, tokenOut.Amount), nil
+				}},
+			},
+		}
+
+		expensiveRoute = route.RouteImpl{
+			Pools: []domain.RoutablePool{
+				&mocks.MockRoutablePool{ID: 2, TakerFee: osmomath.ZeroDec(), CalculateTokenInByTokenOutFunc: func(ctx context.Context, tokenOut sdk.Coin) (sdk.Coin, error) {
+					return sdk.NewCoin(tokenInDenom, tokenOut.Amount.MulRaw(2)), nil
+				}},
+			},
+		}
+
+		routes = []route.RouteImpl{cheapLimitedRoute, expensiveRoute}
+	)
+
+	coarseQuote, err := usecase.GetSplitQuoteInGivenOutWithOptions(context.TODO(), routes, tokenOut, tokenInDenom, domain.RouterOptions{})
+	s.Require().NoError(err)
+	s.Require().Equal(osmomath.NewInt(1_500), coarseQuote.GetAmountIn().Amount)
+
+	refinedQuote, err := usecase.GetSplitQuoteInGivenOutWithOptions(context.TODO(), routes, tokenOut, tokenInDenom, domain.RouterOptions{SplitRefinementRounds: 8})
+	s.Require().NoError(err)
+	s.Require().Equal(osmomath.NewInt(1_450), refinedQuote.GetAmountIn().Amount)
+	s.Require().Equal(tokenOut.Amount, refinedQuote.GetAmountOut())
+
+	refinedRoutes := refinedQuote.GetRoute()
+	s.Require().Len(refinedRoutes, 2)
+	s.Require().Equal(osmomath.NewInt(550), refinedRoutes[0].GetAmountOut())
+	s.Require().Equal(osmomath.NewInt(550), refinedRoutes[0].GetAmountIn())
+	s.Require().Equal(osmomath.NewInt(450), refinedRoutes[1].GetAmountOut())
+	s.Require().Equal(osmomath.NewInt(900), refinedRoutes[1].GetAmountIn())
+}
+
+// Validates that the refinement finds a better split than the one restricted to the increments.
+// The first route has a 1:1 rate but can only process up to 550 of the token in.
+// The second route has a 2:1 rate.
+// With 10 increments, the best split is 500/500 for a total of 750 out.
+// With refinement, the best split is 550/450 for a total of 775 out.
+func (s *RouterTestSuite) TestGetSplitQuote_Refinement() {
+	var (
+		tokenIn       = sdk.NewCoin(ETH, osmomath.NewInt(1_000))
+		tokenOutDenom = USDC
+
+		cheapLimitedRoute = route.RouteImpl{
+			Pools: []domain.RoutablePool{
+				&mocks.MockRoutablePool{ID: 1, TakerFee: osmomath.ZeroDec(), CalculateTokenOutByTokenInFunc: func(ctx context.Context, tokenIn sdk.Coin) (sdk.Coin, error) {
+					if tokenIn.Amount.GT(osmomath.NewInt(550)) {
+						return sdk.Coin{}, errors.New("not enough liquidity")
+					}
+					return sdk.NewCoin(tokenOutDenom, tokenIn.Amount), nil
+				}},
+			},
+		}
+
+		expensiveRoute = route.RouteImpl{
+			Pools: []domain.RoutablePool{
+				&mocks.MockRoutablePool{ID: 2, TakerFee: osmomath.ZeroDec(), CalculateTokenOutByTokenInFunc: func(ctx context.Context, tokenIn sdk.Coin) (sdk.Coin, error) {
+					return sdk.NewCoin(tokenOutDenom, tokenIn.Amount.QuoRaw(2)), nil
+				}},
+			},
+		}
+
+		routes = []route.RouteImpl{cheapLimitedRoute, expensiveRoute}
+	)
+
+	coarseQuote, err := usecase.GetSplitQuoteWithOptions(context.TODO(), routes, tokenIn, domain.RouterOptions{})
+	s.Require().NoError(err)
+	s.Require().Equal(osmomath.NewInt(750), coarseQuote.GetAmountOut())
+
+	refinedQuote, err := usecase.GetSplitQuoteWithOptions(context.TODO(), routes, tokenIn, domain.RouterOptions{SplitRefinementRounds: 8})
+	s.Require().NoError(err)
+	s.Require().Equal(osmomath.NewInt(775), refinedQuote.GetAmountOut())
+
+	refinedRoutes := refinedQuote.GetRoute()
+	s.Require().Len(refinedRoutes, 2)
+	s.Require().Equal(osmomath.NewInt(550), refinedRoutes[0].GetAmountIn())
+	s.Require().Equal(osmomath.NewInt(450), refinedRoutes[1].GetAmountIn())
+
+	// Finer increments without refinement find the same split.
+	fineQuote, err := usecase.GetSplitQuoteWithOptions(context.TODO(), routes, tokenIn, domain.RouterOptions{SplitIncrements: 20})
+	s.Require().NoError(err)
+	s.Require().Equal(osmomath.NewInt(775), fineQuote.GetAmountOut())
+}
+
+// Validates that the split is abandoned if the compute deadline is exceeded while filling the tables
+// and that the best split found so far is returned flagged as partial if it is exceeded while refining.
+func (s *RouterTestSuite) TestGetSplitQuote_ComputeDeadline() {
+	var (
+		tokenIn       = sdk.NewCoin(ETH, osmomath.NewInt(1_000))
+		tokenOutDenom = USDC
+	)
+
+	// newRoutes returns the routes of TestGetSplitQuote_Refinement with the compute deadline exceeded
+	// as soon as the cheap route is quoted with an amount outside of the increments.
+	newRoutes := func(cancel context.CancelCauseFunc) []route.RouteImpl {
+		cheapLimitedRoute := route.RouteImpl{
+			Pools: []domain.RoutablePool{
+				&mocks.MockRoutablePool{ID: 1, TakerFee: osmomath.ZeroDec(), CalculateTokenOutByTokenInFunc: func(ctx context.Context, tokenIn sdk.Coin) (sdk.Coin, error) {
+					if !tokenIn.Amount.ModRaw(100).IsZero() {
+						cancel(domain.ErrComputeDeadlineExceeded)
+					}
+					if tokenIn.Amount.GT(osmomath.NewInt(550)) {
+						return sdk.Coin{}, errors.New("not enough liquidity")
+					}
+					return sdk.NewCoin(tokenOutDenom, tokenIn.Amount), nil
+				}},
+			},
+		}
+
+		expensiveRoute := route.RouteImpl{
+			Pools: []domain.RoutablePool{
+				&mocks.MockRoutablePool{ID: 2, TakerFee: osmomath.ZeroDec(), CalculateTokenOutByTokenInFunc: func(ctx context.Context, tokenIn sdk.Coin) (sdk.Coin, error) {
+					return sdk.NewCoin(tokenOutDenom, tokenIn.Amount.QuoRaw(2)), nil
+				}},
+			},
+		}
+
+		return []route.RouteImpl{cheapLimitedRoute, expensiveRoute}
+	}
+
+	s.Run("exceeded while filling the tables", func() {
+		ctx, cancel := context.WithCancelCause(context.Background())
+		cancel(domain.ErrComputeDeadlineExceeded)
+
+		_, err := usecase.GetSplitQuoteWithOptions(ctx, newRoutes(cancel), tokenIn, domain.RouterOptions{SplitRefinementRounds: 8})
+		s.Require().ErrorIs(err, domain.ErrComputeDeadlineExceeded)
+	})
+
+	s.Run("exceeded while refining", func() {
+		ctx, cancel := context.WithCancelCause(context.Background())
+		defer cancel(nil)
+
+		splitQuote, err := usecase.GetSplitQuoteWithOptions(ctx, newRoutes(cancel), tokenIn, domain.RouterOptions{SplitRefinementRounds: 8})
+		s.Require().NoError(err)
+
+		// The split restricted to the increments is kept.
+		s.Require().Equal(osmomath.NewInt(750), splitQuote.GetAmountOut())
+
+		q, ok := splitQuote.(*usecase.QuoteImpl)
+		s.Require().True(ok)
+		s.Require().True(q.Partial)
+	})
+
+	s.Run("not exceeded", func() {
+		splitQuote, err := usecase.GetSplitQuoteWithOptions(context.Background(), newRoutes(func(error) {}), tokenIn, domain.RouterOptions{SplitRefinementRounds: 8})
+		s.Require().NoError(err)
+		s.Require().Equal(osmomath.NewInt(775), splitQuote.GetAmountOut())
+
+		q, ok := splitQuote.(*usecase.QuoteImpl)
+		s.Require().True(ok)
+		s.Require().False(q.Partial)
+	})
+}
+
+// Validates that the amounts of all increments of the routes containing generalized cosmwasm pools are prefetched
+// into the cache by a bounded number of goroutines while the other routes are left to the dynamic programming step.
+func (s *RouterTestSuite) TestPrefetchGeneralizedCosmWasmRouteAmounts() {
+	const totalIncrements = uint8(10)
+
+	routes := []route.RouteImpl{
+		{HasGeneralizedCosmWasmPool: true},
+		{},
+		{HasGeneralizedCosmWasmPool: true},
+	}
+
+	routeAmtCache := make(map[int]map[uint8]osmomath.Int, len(routes))
+	for i := range routes {
+		routeAmtCache[i] = make(map[uint8]osmomath.Int)
+	}
+
+	amountIncrementCb := func(increment uint8) osmomath.Int {
+		return osmomath.NewInt(int64(increment) * 100)
+	}
+
+	var (
+		calls       atomic.Int32
+		inFlight    atomic.Int32
+		maxInFlight atomic.Int32
+	)
+
+	computeAmountCb := func(routeIndex int, amountIncrement osmomath.Int) osmomath.Int {
+		calls.Add(1)
+
+		current := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			prevMax := maxInFlight.Load()
+			if current <= prevMax || maxInFlight.CompareAndSwap(prevMax, current) {
+				break
+			}
+		}
+
+		// Give the other goroutines the chance to run concurrently.
+		time.Sleep(time.Millisecond)
+
+		return amountIncrement.MulRaw(int64(routeIndex + 1))
+	}
+
+	// System under test
+	usecase.PrefetchGeneralizedCosmWasmRouteAmounts(routes, totalIncrements, amountIncrementCb, routeAmtCache, computeAmountCb)
+
+	s.Require().Equal(int32(2*totalIncrements), calls.Load())
+	s.Require().LessOrEqual(maxInFlight.Load(), int32(8))
+
+	for increment := uint8(1); increment <= totalIncrements; increment++ {
+		s.Require().Equal(amountIncrementCb(increment), routeAmtCache[0][increment])
+		s.Require().Equal(amountIncrementCb(increment).MulRaw(3), routeAmtCache[2][increment])
+	}
+	s.Require().Empty(routeAmtCache[1])
+}
+
+// Validates that the routes containing generalized cosmwasm pools are only included in the split routes
+// if the calc queries of such pools are memoised within a positive budget.
+func (s *RouterTestSuite) TestFilterGeneralizedCosmWasmPoolRoutesForSplits() {
+	routes := []route.RouteImpl{
+		{HasGeneralizedCosmWasmPool: true},
+		{},
+	}
+
+	tests := map[string]struct {
+		calcQueryBudget int
+
+		expectedRoutes []route.RouteImpl
+	}{
+		"memo disabled": {
+			calcQueryBudget: 0,
+			expectedRoutes:  []route.RouteImpl{routes[1]},
+		},
+		"memo enabled": {
+			calcQueryBudget: 1,
+			expectedRoutes:  routes,
+		},
+	}
+
+	for name, tc := range tests {
+		s.Run(name, func() {
+			cosmWasmPoolsConfig := domain.CosmWasmPoolRouterConfig{
+				GeneralCosmWasmCalcQueryBudget: tc.calcQueryBudget,
+			}
+
+			routerUsecase := usecase.NewRouterUsecase(nil, nil, nil, nil, defaultRouterConfig, cosmWasmPoolsConfig, &log.NoOpLogger{}, cache.New(), cache.New())
+
+			routerUsecaseImpl, ok := routerUsecase.(*usecase.RouterUseCaseImpl)
+			s.Require().True(ok)
+
+			// System under test
+			actualRoutes := routerUsecaseImpl.FilterGeneralizedCosmWasmPoolRoutesForSplits(routes)
+
+			s.Require().Equal(tc.expectedRoutes, actualRoutes)
+		})
+	}
+}
+
 // setupSplitsMainnetTestCase sets up the test case for GetSplitQuote using mainnet state.
 // Calls all the relevant functions as if we were estimating the quote up until starting the
 // splits computation.
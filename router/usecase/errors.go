@@ -9,6 +9,7 @@ var (
 	ErrNilCurrentRoute     = errors.New("currentRoute cannot be nil")
 	ErrNilRouterRepository = errors.New("router repository is not set")
 	ErrNilPoolsRepository  = errors.New("pools repository is not set")
+	ErrZeroTokenInEstimate = errors.New("zero token in estimate")
 )
 
 type SortedPoolsAndPoolsUsedLengthMismatchError struct {
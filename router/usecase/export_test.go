@@ -19,10 +19,15 @@ type (
 
 	CandidatePoolWrapper  = candidatePoolWrapper
 	CandidateRouteWrapper = candidateRouteWrapper
+
+	BasketPoolUsage = basketPoolUsage
 )
 
 const (
 	NoPoolLiquidityCapError = noPoolLiquidityCapError
+
+	CoalescingResultComputed = coalescingResultComputed
+	CoalescingResultShared   = coalescingResultShared
 )
 
 func ValidateAndFilterRoutes(candidateRoutes []candidateRouteWrapper, tokenInDenom string, logger log.Logger) (sqsdomain.CandidateRoutes, error) {
@@ -30,11 +35,20 @@ func ValidateAndFilterRoutes(candidateRoutes []candidateRouteWrapper, tokenInDen
 }
 
 func (r *routerUseCaseImpl) HandleRoutes(ctx context.Context, tokenIn sdk.Coin, tokenOutDenom string, candidateRouteSearchOptions domain.CandidateRouteSearchOptions) (candidateRoutes sqsdomain.CandidateRoutes, err error) {
-	return r.handleCandidateRoutes(ctx, tokenIn, tokenOutDenom, candidateRouteSearchOptions)
+	candidateRoutes, _, err = r.handleCandidateRoutes(ctx, tokenIn, tokenOutDenom, candidateRouteSearchOptions)
+	return candidateRoutes, err
 }
 
 func (r *routerUseCaseImpl) EstimateAndRankSingleRouteQuote(ctx context.Context, routes []route.RouteImpl, tokenIn sdk.Coin, logger log.Logger) (domain.Quote, []RouteWithOutAmount, error) {
-	return r.estimateAndRankSingleRouteQuote(ctx, routes, tokenIn, logger)
+	return r.estimateAndRankSingleRouteQuote(ctx, routes, tokenIn, nil, nil, logger)
+}
+
+func NetAmountOut(gasCostModel domain.GasCostModelConfig, gasPriceInTokenOut *osmomath.Dec, quote domain.Quote) (osmomath.Int, error) {
+	gasRanker, err := newGasCostRanker(gasCostModel, gasPriceInTokenOut)
+	if err != nil {
+		return osmomath.Int{}, err
+	}
+	return gasRanker.netAmountOut(quote)
 }
 
 func FilterDuplicatePoolIDRoutes(rankedRoutes []RouteWithOutAmount) []route.RouteImpl {
@@ -57,16 +71,36 @@ func FormatCandidateRouteCacheKey(tokenInDenom string, tokenOutDenom string) str
 	return formatCandidateRouteCacheKey(tokenInDenom, tokenOutDenom)
 }
 
-func SortPools(pools []sqsdomain.PoolI, transmuterCodeIDs map[uint64]struct{}, totalTVL osmomath.Int, preferredPoolIDsMap map[uint64]struct{}, logger log.Logger) []sqsdomain.PoolI {
-	return sortPools(pools, transmuterCodeIDs, totalTVL, preferredPoolIDsMap, logger)
+func SortPools(pools []sqsdomain.PoolI, cosmWasmPoolsConfig domain.CosmWasmPoolRouterConfig, totalTVL osmomath.Int, preferredPoolIDsMap map[uint64]struct{}, logger log.Logger) []sqsdomain.PoolI {
+	return sortPools(pools, cosmWasmPoolsConfig, totalTVL, preferredPoolIDsMap, logger)
+}
+
+func RatePool(pool sqsdomain.PoolI, cosmWasmPoolsConfig domain.CosmWasmPoolRouterConfig, totalTVLFloat float64, preferredPoolIDsMap map[uint64]struct{}, logger log.Logger) (float64, bool) {
+	return ratePool(pool, cosmWasmPoolsConfig, totalTVLFloat, preferredPoolIDsMap, logger)
+}
+
+func FindCyclicArbAmountIn(ctx context.Context, cycle route.RouteImpl, denom string, maxAmountIn osmomath.Int) (domain.CyclicArb, bool) {
+	return findCyclicArbAmountIn(ctx, cycle, denom, maxAmountIn)
 }
 
 func GetSplitQuote(ctx context.Context, routes []route.RouteImpl, tokenIn sdk.Coin) (domain.Quote, error) {
-	return getSplitQuote(ctx, routes, tokenIn)
+	return getSplitQuote(ctx, routes, tokenIn, defaultSplitOptions)
+}
+
+func GetSplitQuoteWithOptions(ctx context.Context, routes []route.RouteImpl, tokenIn sdk.Coin, routerOptions domain.RouterOptions) (domain.Quote, error) {
+	return getSplitQuote(ctx, routes, tokenIn, newSplitOptions(routerOptions))
+}
+
+func GetSplitQuoteInGivenOut(ctx context.Context, routes []route.RouteImpl, tokenOut sdk.Coin, tokenInDenom string) (domain.Quote, error) {
+	return getSplitQuoteInGivenOut(ctx, routes, tokenOut, tokenInDenom, defaultSplitOptions)
+}
+
+func GetSplitQuoteInGivenOutWithOptions(ctx context.Context, routes []route.RouteImpl, tokenOut sdk.Coin, tokenInDenom string, routerOptions domain.RouterOptions) (domain.Quote, error) {
+	return getSplitQuoteInGivenOut(ctx, routes, tokenOut, tokenInDenom, newSplitOptions(routerOptions))
 }
 
 func (r *routerUseCaseImpl) RankRoutesByDirectQuote(ctx context.Context, candidateRoutes sqsdomain.CandidateRoutes, tokenIn sdk.Coin, tokenOutDenom string, maxRoutes int) (domain.Quote, []route.RouteImpl, error) {
-	return r.rankRoutesByDirectQuote(ctx, candidateRoutes, tokenIn, tokenOutDenom, maxRoutes)
+	return r.rankRoutesByDirectQuote(ctx, candidateRoutes, tokenIn, tokenOutDenom, maxRoutes, nil, nil)
 }
 
 func CutRoutesForSplits(maxSplitRoutes int, routes []route.RouteImpl) []route.RouteImpl {
@@ -89,3 +123,23 @@ func (r *routerUseCaseImpl) SetRankedRouteCacheToMock(tokenInDenom, tokenOutDeno
 		}}, 0)
 
 }
+
+func SimulateBasketLeg(ctx context.Context, quote domain.Quote, usage BasketPoolUsage) (domain.Quote, []uint64, error) {
+	return simulateBasketLeg(ctx, quote, usage)
+}
+
+func IsAmountIndependentQuoteError(err error) bool {
+	return isAmountIndependentQuoteError(err)
+}
+
+func (r *routerUseCaseImpl) DefaultRouterOptions() domain.RouterOptions {
+	return r.defaultRouterOptions()
+}
+
+func PrefetchGeneralizedCosmWasmRouteAmounts(routes []route.RouteImpl, totalIncrements uint8, amountIncrementCb func(uint8) osmomath.Int, routeAmtCache map[int]map[uint8]osmomath.Int, computeAmountCb func(routeIndex int, amountIncrement osmomath.Int) osmomath.Int) {
+	prefetchGeneralizedCosmWasmRouteAmounts(routes, totalIncrements, amountIncrementCb, routeAmtCache, computeAmountCb)
+}
+
+func (r *routerUseCaseImpl) FilterGeneralizedCosmWasmPoolRoutesForSplits(rankedRoutes []route.RouteImpl) []route.RouteImpl {
+	return r.filterGeneralizedCosmWasmPoolRoutesForSplits(rankedRoutes)
+}
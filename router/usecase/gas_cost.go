@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	"github.com/osmosis-labs/sqs/domain"
+	"github.com/osmosis-labs/sqs/router/types"
+)
+
+// gasCostRanker ranks the exact amount in routes by the amount out net of the estimated gas cost.
+// All methods fall back to the amount out alone on the nil ranker so that the quote computation
+// does not have to check whether the gas-aware ranking was requested.
+type gasCostRanker struct {
+	gasCostModel domain.GasCostModelConfig
+	// gasPrice is the price of a unit of gas in the fee denom.
+	gasPrice osmomath.Dec
+	// gasPriceInTokenOut is the price of a unit of gas in the token out denom.
+	gasPriceInTokenOut osmomath.Dec
+}
+
+// newGasCostRanker returns the ranker for the given gas cost model and the gas price in the token out denom.
+// Returns nil if the gas price in the token out denom is nil.
+// Returns error if the gas price of the model is not valid.
+func newGasCostRanker(gasCostModel domain.GasCostModelConfig, gasPriceInTokenOut *osmomath.Dec) (*gasCostRanker, error) {
+	if gasPriceInTokenOut == nil {
+		return nil, nil
+	}
+
+	gasPrice, err := gasCostModel.GetGasPrice()
+	if err != nil {
+		return nil, err
+	}
+
+	return &gasCostRanker{
+		gasCostModel:       gasCostModel,
+		gasPrice:           gasPrice,
+		gasPriceInTokenOut: *gasPriceInTokenOut,
+	}, nil
+}
+
+// rankByNetAmountOut sorts the routes by the amount out net of the estimated gas cost in descending order.
+// The sort is stable so that the routes with equal net amount out keep their order.
+// The routes failing the gas estimate are ranked last.
+// No-op on the nil ranker.
+func (g *gasCostRanker) rankByNetAmountOut(ctx context.Context, routes []RouteWithOutAmount, tokenInDenom string) {
+	if g == nil {
+		return
+	}
+
+	type rankedRoute struct {
+		route        RouteWithOutAmount
+		netAmountOut osmomath.Int
+		isEstimated  bool
+	}
+
+	rankedRoutes := make([]rankedRoute, 0, len(routes))
+	for _, route := range routes {
+		gas, err := route.EstimateGas(ctx, sdk.NewCoin(tokenInDenom, route.InAmount), g.gasCostModel)
+		if err != nil {
+			rankedRoutes = append(rankedRoutes, rankedRoute{route: route})
+			continue
+		}
+
+		rankedRoutes = append(rankedRoutes, rankedRoute{
+			route:        route,
+			netAmountOut: route.OutAmount.Sub(g.costInTokenOut(gas)),
+			isEstimated:  true,
+		})
+	}
+
+	sort.SliceStable(rankedRoutes, func(i, j int) bool {
+		if rankedRoutes[i].isEstimated != rankedRoutes[j].isEstimated {
+			return rankedRoutes[i].isEstimated
+		}
+		return rankedRoutes[i].isEstimated && rankedRoutes[i].netAmountOut.GT(rankedRoutes[j].netAmountOut)
+	})
+
+	for i, rankedRoute := range rankedRoutes {
+		routes[i] = rankedRoute.route
+	}
+}
+
+// setGasEstimate estimates the gas cost of executing the given exact amount in quote and sets it on the quote.
+// The estimate is left unset if any of the routes fails the gas estimate.
+// No-op on the nil ranker.
+func (g *gasCostRanker) setGasEstimate(ctx context.Context, quote domain.Quote) {
+	if g == nil {
+		return
+	}
+
+	q, ok := quote.(*quoteExactAmountIn)
+	if !ok {
+		return
+	}
+
+	gasEstimate, err := g.estimate(ctx, q)
+	if err != nil {
+		q.GasEstimate = nil
+		return
+	}
+
+	q.GasEstimate = gasEstimate
+}
+
+// netAmountOut returns the amount out of the quote net of the estimated gas cost.
+// Returns the amount out on the nil ranker.
+// Returns types.ErrGasEstimateNotFound if the quote has no gas estimate set
+// since the quote cannot be compared by the amount out net of the gas cost.
+func (g *gasCostRanker) netAmountOut(quote domain.Quote) (osmomath.Int, error) {
+	if g == nil {
+		return quote.GetAmountOut(), nil
+	}
+
+	q, ok := quote.(*quoteExactAmountIn)
+	if !ok || q.GasEstimate == nil {
+		return osmomath.Int{}, types.ErrGasEstimateNotFound
+	}
+
+	return q.GasEstimate.NetAmountOut, nil
+}
+
+// estimate returns the gas estimate of the quote, including the base gas of the swap message.
+// Returns error if any of the routes fails the gas estimate.
+func (g *gasCostRanker) estimate(ctx context.Context, quote *quoteExactAmountIn) (*domain.GasEstimate, error) {
+	gas := g.gasCostModel.BaseGas
+	for _, splitRoute := range quote.Route {
+		route, ok := splitRoute.(*RouteWithOutAmount)
+		if !ok {
+			return nil, fmt.Errorf("unexpected split route type (%T)", splitRoute)
+		}
+
+		routeGas, err := route.EstimateGas(ctx, sdk.NewCoin(quote.AmountIn.Denom, route.InAmount), g.gasCostModel)
+		if err != nil {
+			return nil, err
+		}
+
+		gas += routeGas
+	}
+
+	costInTokenOut := g.costInTokenOut(gas)
+
+	return &domain.GasEstimate{
+		Gas:            gas,
+		Fee:            sdk.NewCoin(g.gasCostModel.FeeDenom, g.gasPrice.MulInt64(int64(gas)).Ceil().TruncateInt()),
+		CostInTokenOut: costInTokenOut,
+		NetAmountOut:   quote.AmountOut.Sub(costInTokenOut),
+	}, nil
+}
+
+// costInTokenOut converts the given gas into the token out denom, rounding up.
+func (g *gasCostRanker) costInTokenOut(gas uint64) osmomath.Int {
+	return g.gasPriceInTokenOut.MulInt64(int64(gas)).Ceil().TruncateInt()
+}
@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	"github.com/osmosis-labs/sqs/domain"
+	"github.com/osmosis-labs/sqs/router/usecase/route"
+)
+
+// GetLiquidityDepth implements mvc.RouterUsecase.
+// Each side of the depth is computed in one pass. The candidate routes are retrieved once, from cache if present,
+// and converted into the routes with the pool data once. Each level is then quoted over the same routes,
+// splits included, the same way as by GetOptimalQuote.
+// The levels of each side stop at the first amount that fails to quote since the larger amounts fail too.
+// The compute deadline of the options does not apply since the levels are full quotes bounded by the context.
+// Returns error if:
+// - no amounts are given
+// - the smallest bid fails to quote
+// - fails to retrieve the candidate routes of either side
+func (r *routerUseCaseImpl) GetLiquidityDepth(ctx context.Context, baseDenom, quoteDenom string, baseAmounts []osmomath.Int, opts ...domain.RouterOption) (domain.LiquidityDepth, error) {
+	if len(baseAmounts) == 0 {
+		return domain.LiquidityDepth{}, errors.New("no amounts given for the liquidity depth")
+	}
+
+	options := r.defaultRouterOptions()
+	// Apply options
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	bidQuotes, err := r.computeDepthSideQuotes(ctx, baseDenom, quoteDenom, baseAmounts, options)
+	if err != nil {
+		return domain.LiquidityDepth{}, err
+	}
+
+	if len(bidQuotes) == 0 {
+		return domain.LiquidityDepth{}, fmt.Errorf("failed to quote the smallest amount (%s) of %s for %s", baseAmounts[0], baseDenom, quoteDenom)
+	}
+
+	bids := make([]domain.LiquidityDepthLevel, 0, len(bidQuotes))
+	for _, quote := range bidQuotes {
+		amountIn, amountOut := quote.GetAmountIn().Amount, quote.GetAmountOut()
+		bids = append(bids, domain.LiquidityDepthLevel{
+			AmountIn:       amountIn,
+			AmountOut:      amountOut,
+			ExecutionPrice: amountOut.ToLegacyDec().QuoMut(amountIn.ToLegacyDec()),
+		})
+	}
+
+	// Convert the base amounts into the quote denom at the execution price of the smallest bid.
+	conversionPrice := bids[0].ExecutionPrice
+	quoteAmounts := make([]osmomath.Int, 0, len(baseAmounts))
+	for _, baseAmount := range baseAmounts {
+		quoteAmount := baseAmount.ToLegacyDec().MulMut(conversionPrice).TruncateInt()
+		if quoteAmount.IsZero() {
+			continue
+		}
+		quoteAmounts = append(quoteAmounts, quoteAmount)
+	}
+
+	askQuotes, err := r.computeDepthSideQuotes(ctx, quoteDenom, baseDenom, quoteAmounts, options)
+	if err != nil {
+		return domain.LiquidityDepth{}, err
+	}
+
+	asks := make([]domain.LiquidityDepthLevel, 0, len(askQuotes))
+	for _, quote := range askQuotes {
+		amountIn, amountOut := quote.GetAmountIn().Amount, quote.GetAmountOut()
+		asks = append(asks, domain.LiquidityDepthLevel{
+			AmountIn:       amountIn,
+			AmountOut:      amountOut,
+			ExecutionPrice: amountIn.ToLegacyDec().QuoMut(amountOut.ToLegacyDec()),
+		})
+	}
+
+	return domain.LiquidityDepth{
+		BaseDenom:  baseDenom,
+		QuoteDenom: quoteDenom,
+		Bids:       bids,
+		Asks:       asks,
+	}, nil
+}
+
+// computeDepthSideQuotes computes the quotes for swapping each of the given amounts of the token in denom into
+// the token out denom over the same candidate routes. The candidate routes are retrieved for the smallest amount.
+// The quotes stop at the first amount that fails to quote or quotes zero out.
+// CONTRACT: the amounts are sorted in increasing order.
+func (r *routerUseCaseImpl) computeDepthSideQuotes(ctx context.Context, tokenInDenom, tokenOutDenom string, amountsIn []osmomath.Int, options domain.RouterOptions) ([]domain.Quote, error) {
+	if len(amountsIn) == 0 {
+		return nil, nil
+	}
+
+	if err := domain.ValidateInputDenoms(tokenInDenom, tokenOutDenom); err != nil {
+		return nil, err
+	}
+
+	// Get the dynamic min pool liquidity cap for the given token in and token out denoms.
+	dynamicMinPoolLiquidityCap, err := r.tokenMetadataHolder.GetMinPoolLiquidityCap(tokenInDenom, tokenOutDenom)
+	if err == nil && !options.DisableDynamicMinPoolLiquidityCap {
+		options.MinPoolLiquidityCap = r.ConvertMinTokensPoolLiquidityCapToFilter(dynamicMinPoolLiquidityCap)
+	}
+
+	candidateRoutes, _, err := r.handleCandidateRoutes(ctx, sdk.NewCoin(tokenInDenom, amountsIn[0]), tokenOutDenom, r.newCandidateRouteSearchOptions(options))
+	if err != nil {
+		return nil, err
+	}
+
+	routes, err := r.poolsUsecase.GetRoutesFromCandidates(candidateRoutes, tokenInDenom, tokenOutDenom)
+	if err != nil {
+		return nil, err
+	}
+
+	quotes := make([]domain.Quote, 0, len(amountsIn))
+	for _, amountIn := range amountsIn {
+		if ctx.Err() != nil {
+			return nil, context.Cause(ctx)
+		}
+
+		quote, err := r.computeQuoteOverRoutes(ctx, routes, sdk.NewCoin(tokenInDenom, amountIn), options)
+		if err != nil || quote.GetAmountOut().IsZero() {
+			break
+		}
+
+		quotes = append(quotes, quote)
+	}
+
+	return quotes, nil
+}
+
+// computeQuoteOverRoutes returns the best of the top single route and the split quotes for the given token in
+// over the given routes.
+// Returns error if none of the routes can quote the token in.
+func (r *routerUseCaseImpl) computeQuoteOverRoutes(ctx context.Context, routes []route.RouteImpl, tokenIn sdk.Coin, options domain.RouterOptions) (domain.Quote, error) {
+	topSingleRouteQuote, routesWithAmtOut, err := r.estimateAndRankSingleRouteQuote(ctx, routes, tokenIn, nil, nil, r.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if options.MaxSplitRoutes == domain.DisableSplitRoutes {
+		return topSingleRouteQuote, nil
+	}
+
+	rankedRoutes := filterAndConvertDuplicatePoolIDRankedRoutes(routesWithAmtOut)
+	rankedRoutes = cutRoutesForSplits(options.MaxSplitRoutes, rankedRoutes)
+	rankedRoutes = r.filterGeneralizedCosmWasmPoolRoutesForSplits(rankedRoutes)
+
+	if len(rankedRoutes) <= 1 {
+		return topSingleRouteQuote, nil
+	}
+
+	topSplitQuote, err := getSplitQuote(ctx, rankedRoutes, tokenIn, newSplitOptions(options))
+	if err != nil || !topSplitQuote.GetAmountOut().GT(topSingleRouteQuote.GetAmountOut()) {
+		return topSingleRouteQuote, nil
+	}
+
+	return topSplitQuote, nil
+}
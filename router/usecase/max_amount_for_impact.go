@@ -0,0 +1,203 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	"github.com/osmosis-labs/sqs/domain"
+	"github.com/osmosis-labs/sqs/router/types"
+)
+
+const (
+	// maxAmountForImpactInitialAmount is the amount that the search for the bracket starts from.
+	maxAmountForImpactInitialAmount = 1_000_000
+	// maxAmountForImpactGrowthFactor is the factor that the amount is scaled by while searching for the bracket.
+	maxAmountForImpactGrowthFactor = 10
+	// maxAmountForImpactBracketSteps bounds the number of quotes computed while searching for the bracket.
+	maxAmountForImpactBracketSteps = 24
+	// maxAmountForImpactBisectionSteps bounds the number of quotes computed while bisecting the bracket.
+	maxAmountForImpactBisectionSteps = 32
+)
+
+// maxAmountForImpactPrecision is the width of the bracket relative to its lower bound at which the bisection stops.
+var maxAmountForImpactPrecision = osmomath.MustNewDecFromStr("0.001")
+
+// GetMaxAmountForPriceImpact implements mvc.RouterUsecase.
+// The search brackets the amount by scaling it by a factor of 10 from the initial amount and then bisects the bracket
+// until its width is within 0.1% of the lower bound. The amount is within the max price impact if the absolute
+// price impact of its optimal quote, splits included, does not exceed it. The amounts that fail to quote
+// for the lack of liquidity are treated as exceeding the max price impact.
+// The search stops as soon as the context is cancelled since every step computes an optimal quote.
+// The returned quote is prepared for output with the unit spot price scaling factor.
+// Returns ErrNoAmountWithinPriceImpact if none of the amounts is within the max price impact.
+// Returns error if:
+// - the swap method is invalid
+// - the context is cancelled, with its cause
+// - the quote fails for a reason that does not depend on the amount, such as a missing taker fee or pool data
+func (r *routerUseCaseImpl) GetMaxAmountForPriceImpact(ctx context.Context, tokenInDenom, tokenOutDenom string, swapMethod domain.TokenSwapMethod, maxPriceImpact osmomath.Dec, opts ...domain.RouterOption) (domain.Quote, error) {
+	var (
+		givenDenom, otherDenom string
+		getOptimalQuote        func(ctx context.Context, token sdk.Coin, denom string, opts ...domain.RouterOption) (domain.Quote, error)
+	)
+
+	switch swapMethod {
+	case domain.TokenSwapMethodExactIn:
+		givenDenom, otherDenom, getOptimalQuote = tokenInDenom, tokenOutDenom, r.GetOptimalQuote
+	case domain.TokenSwapMethodExactOut:
+		givenDenom, otherDenom, getOptimalQuote = tokenOutDenom, tokenInDenom, r.GetOptimalQuoteInGivenOut
+	default:
+		return nil, types.ErrSwapMethodNotValid
+	}
+
+	// quoteWithinImpact returns the prepared quote for the given amount and whether its price impact is within the max.
+	quoteWithinImpact := func(amount osmomath.Int) (domain.Quote, bool, error) {
+		if ctx.Err() != nil {
+			return nil, false, context.Cause(ctx)
+		}
+
+		quote, err := getOptimalQuote(ctx, sdk.NewCoin(givenDenom, amount), otherDenom, opts...)
+
+		// The quote computed while the context was being cancelled may be missing routes.
+		if ctx.Err() != nil {
+			return nil, false, context.Cause(ctx)
+		}
+
+		if err != nil {
+			if isAmountIndependentQuoteError(err) {
+				return nil, false, err
+			}
+
+			return nil, false, nil
+		}
+
+		if _, _, err := quote.PrepareResult(ctx, osmomath.OneDec(), r.logger); err != nil {
+			return nil, false, nil
+		}
+
+		priceImpact := quote.GetPriceImpact()
+		if priceImpact.IsNil() || priceImpact.Abs().GT(maxPriceImpact) {
+			return nil, false, nil
+		}
+
+		return quote, true, nil
+	}
+
+	var (
+		lo, hi  osmomath.Int
+		loQuote domain.Quote
+	)
+
+	amount := osmomath.NewInt(maxAmountForImpactInitialAmount)
+	quote, ok, err := quoteWithinImpact(amount)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		// Scale up until the amount exceeds the max price impact.
+		lo, loQuote = amount, quote
+		for i := 0; i < maxAmountForImpactBracketSteps && hi.IsNil(); i++ {
+			amount = lo.MulRaw(maxAmountForImpactGrowthFactor)
+			quote, ok, err := quoteWithinImpact(amount)
+			if err != nil {
+				return nil, err
+			}
+
+			if ok {
+				lo, loQuote = amount, quote
+			} else {
+				hi = amount
+			}
+		}
+
+		// The max price impact is not exceeded by any of the bracket amounts.
+		if hi.IsNil() {
+			return loQuote, nil
+		}
+	} else {
+		// Scale down until the amount is within the max price impact.
+		hi = amount
+		for i := 0; i < maxAmountForImpactBracketSteps && loQuote == nil; i++ {
+			amount = hi.QuoRaw(maxAmountForImpactGrowthFactor)
+			if amount.IsZero() {
+				break
+			}
+
+			quote, ok, err := quoteWithinImpact(amount)
+			if err != nil {
+				return nil, err
+			}
+
+			if ok {
+				lo, loQuote = amount, quote
+			} else {
+				hi = amount
+			}
+		}
+
+		if loQuote == nil {
+			return nil, domain.ErrNoAmountWithinPriceImpact
+		}
+	}
+
+	for i := 0; i < maxAmountForImpactBisectionSteps; i++ {
+		if hi.Sub(lo).ToLegacyDec().LTE(lo.ToLegacyDec().MulMut(maxAmountForImpactPrecision)) {
+			break
+		}
+
+		amount = lo.Add(hi).QuoRaw(2)
+		quote, ok, err := quoteWithinImpact(amount)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			lo, loQuote = amount, quote
+		} else {
+			hi = amount
+		}
+	}
+
+	return loQuote, nil
+}
+
+// isAmountIndependentQuoteError returns true if the given quote error does not depend on the quoted amount.
+// Such errors fail every amount so they are returned by the search instead of being treated as exceeding
+// the max price impact. The remaining errors, such as running out of liquidity in a pool, are assumed to be
+// due to the amount being too large.
+func isAmountIndependentQuoteError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, domain.ErrComputeDeadlineExceeded) || errors.Is(err, domain.ErrRouterStateGuardTimeout) {
+		return true
+	}
+
+	var (
+		takerFeeNotFoundErr      domain.TakerFeeNotFoundForDenomPairError
+		poolNotFoundErr          domain.PoolNotFoundError
+		failedToCastPoolModelErr domain.FailedToCastPoolModelError
+		noTickModelErr           domain.ConcentratedPoolNoTickModelError
+		tickModelNotSetErr       domain.ConcentratedTickModelNotSetError
+		cosmWasmDataMissingErr   domain.CosmWasmPoolDataMissingError
+		missingNormalizationErr  domain.MissingNormalizationFactorError
+		zeroNormalizationErr     domain.ZeroNormalizationFactorError
+		unsupportedCosmWasmErr   domain.UnsupportedCosmWasmPoolError
+		invalidPoolTypeErr       domain.InvalidPoolTypeError
+		exactOutNotSupportedErr  domain.ExactAmountOutNotSupportedError
+	)
+
+	return errors.As(err, &takerFeeNotFoundErr) ||
+		errors.As(err, &poolNotFoundErr) ||
+		errors.As(err, &failedToCastPoolModelErr) ||
+		errors.As(err, &noTickModelErr) ||
+		errors.As(err, &tickModelNotSetErr) ||
+		errors.As(err, &cosmWasmDataMissingErr) ||
+		errors.As(err, &missingNormalizationErr) ||
+		errors.As(err, &zeroNormalizationErr) ||
+		errors.As(err, &unsupportedCosmWasmErr) ||
+		errors.As(err, &invalidPoolTypeErr) ||
+		errors.As(err, &exactOutNotSupportedErr) ||
+		errors.Is(err, types.ErrGasEstimateNotFound) ||
+		errors.Is(err, types.ErrGasPriceInTokenOutNotFound)
+}
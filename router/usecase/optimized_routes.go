@@ -20,7 +20,11 @@ import (
 // Returns best quote as well as all routes sorted by amount out and error if any.
 // CONTRACT: router repository must be set on the router.
 // CONTRACT: pools reporitory must be set on the router
-func (r *routerUseCaseImpl) estimateAndRankSingleRouteQuote(ctx context.Context, routes []route.RouteImpl, tokenIn sdk.Coin, logger log.Logger) (quote domain.Quote, sortedRoutesByAmtOut []RouteWithOutAmount, err error) {
+// The direct quote over each route is recorded by the explainer.
+// If the gas cost ranker is non-nil, the routes are ranked by the amount out net of the estimated gas cost
+// and the gas estimate is set on the returned quote.
+// If the compute deadline is exceeded, the routes quoted so far are ranked and the returned quote is flagged as partial.
+func (r *routerUseCaseImpl) estimateAndRankSingleRouteQuote(ctx context.Context, routes []route.RouteImpl, tokenIn sdk.Coin, explainer *quoteExplainer, gasRanker *gasCostRanker, logger log.Logger) (quote domain.Quote, sortedRoutesByAmtOut []RouteWithOutAmount, err error) {
 	if len(routes) == 0 {
 		return nil, nil, fmt.Errorf("no routes were provided for token in (%s)", tokenIn.Denom)
 	}
@@ -30,10 +34,16 @@ func (r *routerUseCaseImpl) estimateAndRankSingleRouteQuote(ctx context.Context,
 	errors := []error{}
 
 	for _, route := range routes {
+		// Once the compute deadline is exceeded, rank the routes quoted so far.
+		if isComputeInterrupted(ctx) {
+			break
+		}
+
 		directRouteTokenOut, err := route.CalculateTokenOutByTokenIn(ctx, tokenIn)
 		if err != nil {
 			logger.Debug("skipping single route due to error in estimate", zap.Error(err))
 			errors = append(errors, err)
+			explainer.recordRouteQuote(&route, tokenIn.Amount, osmomath.ZeroInt(), err)
 			continue
 		}
 
@@ -41,6 +51,8 @@ func (r *routerUseCaseImpl) estimateAndRankSingleRouteQuote(ctx context.Context,
 			directRouteTokenOut.Amount = osmomath.ZeroInt()
 		}
 
+		explainer.recordRouteQuote(&route, tokenIn.Amount, directRouteTokenOut.Amount, nil)
+
 		routesWithAmountOut = append(routesWithAmountOut, RouteWithOutAmount{
 			RouteImpl: route,
 			InAmount:  tokenIn.Amount,
@@ -48,6 +60,12 @@ func (r *routerUseCaseImpl) estimateAndRankSingleRouteQuote(ctx context.Context,
 		})
 	}
 
+	// The routes may fail to be quoted after the compute deadline is exceeded.
+	// The caches are kept as the failure is not due to the routes.
+	if len(routesWithAmountOut) == 0 && isComputeInterrupted(ctx) {
+		return nil, nil, context.Cause(ctx)
+	}
+
 	// If we skipped all routes due to errors, return the first error
 	if len(routesWithAmountOut) == 0 && len(errors) > 0 {
 		// If we encounter this problem, we attempte to invalidate all caches to recompute the routes
@@ -59,9 +77,9 @@ func (r *routerUseCaseImpl) estimateAndRankSingleRouteQuote(ctx context.Context,
 		// Note: the zero length check occurred at the start of function.
 		tokenOutDenom := routes[0].GetTokenOutDenom()
 
-		r.candidateRouteCache.Delete(formatCandidateRouteCacheKey(tokenIn.Denom, tokenOutDenom))
+		r.deleteRouteCache(candidateRouteCacheLabel, formatCandidateRouteCacheKey(tokenIn.Denom, tokenOutDenom))
 		tokenInOrderOfMagnitude := GetPrecomputeOrderOfMagnitude(tokenIn.Amount)
-		r.rankedRouteCache.Delete(formatRankedRouteCacheKey(tokenIn.Denom, tokenOutDenom, tokenInOrderOfMagnitude))
+		r.deleteRouteCache(rankedRouteCacheLabel, formatRankedRouteCacheKey(tokenIn.Denom, tokenOutDenom, tokenInOrderOfMagnitude))
 
 		return nil, nil, errors[0]
 	}
@@ -71,17 +89,86 @@ func (r *routerUseCaseImpl) estimateAndRankSingleRouteQuote(ctx context.Context,
 		return routesWithAmountOut[i].OutAmount.GT(routesWithAmountOut[j].OutAmount)
 	})
 
+	// Re-rank by the amount out net of the estimated gas cost if requested.
+	gasRanker.rankByNetAmountOut(ctx, routesWithAmountOut, tokenIn.Denom)
+
 	bestRoute := routesWithAmountOut[0]
 
 	finalQuote := &quoteExactAmountIn{
 		AmountIn:  tokenIn,
 		AmountOut: bestRoute.OutAmount,
 		Route:     []domain.SplitRoute{&bestRoute},
+		Partial:   isComputeDeadlineExceeded(ctx),
 	}
 
+	gasRanker.setGasEstimate(ctx, finalQuote)
+
 	return finalQuote, routesWithAmountOut, nil
 }
 
+// Returns best exact amount out quote as well as all routes sorted by amount in and error if any.
+// The routes must be ordered from token in to token out.
+// The routes are sorted by the amount of token in required to receive the token out in increasing order.
+// The returned quote is over the routes from token in to token out.
+// The direct quote over each route is recorded by the explainer.
+func (r *routerUseCaseImpl) estimateAndRankSingleRouteQuoteInGivenOut(ctx context.Context, routes []route.RouteImpl, tokenOut sdk.Coin, tokenInDenom string, explainer *quoteExplainer, logger log.Logger) (quote *quoteExactAmountIn, sortedRoutesByAmtIn []RouteWithOutAmount, err error) {
+	if len(routes) == 0 {
+		return nil, nil, fmt.Errorf("no routes were provided for token out (%s)", tokenOut.Denom)
+	}
+
+	routesWithAmountIn := make([]RouteWithOutAmount, 0, len(routes))
+
+	errors := []error{}
+
+	for _, route := range routes {
+		directRouteTokenIn, err := route.CalculateTokenInByTokenOut(ctx, tokenOut)
+		if err != nil {
+			logger.Debug("skipping single route due to error in estimate", zap.Error(err))
+			errors = append(errors, err)
+			explainer.recordRouteQuote(&route, osmomath.ZeroInt(), tokenOut.Amount, err)
+			continue
+		}
+
+		if directRouteTokenIn.Amount.IsNil() || directRouteTokenIn.Amount.IsZero() {
+			logger.Debug("skipping single route due to zero token in estimate")
+			explainer.recordRouteQuote(&route, osmomath.ZeroInt(), tokenOut.Amount, ErrZeroTokenInEstimate)
+			continue
+		}
+
+		explainer.recordRouteQuote(&route, directRouteTokenIn.Amount, tokenOut.Amount, nil)
+
+		routesWithAmountIn = append(routesWithAmountIn, RouteWithOutAmount{
+			RouteImpl: route,
+			InAmount:  directRouteTokenIn.Amount,
+			OutAmount: tokenOut.Amount,
+		})
+	}
+
+	// If we skipped all routes due to errors, return the first error
+	if len(routesWithAmountIn) == 0 {
+		if len(errors) > 0 {
+			return nil, nil, errors[0]
+		}
+
+		return nil, nil, fmt.Errorf("no route can produce token out (%s)", tokenOut)
+	}
+
+	// Sort by amount in in ascending order
+	sort.Slice(routesWithAmountIn, func(i, j int) bool {
+		return routesWithAmountIn[i].InAmount.LT(routesWithAmountIn[j].InAmount)
+	})
+
+	bestRoute := routesWithAmountIn[0]
+
+	finalQuote := &quoteExactAmountIn{
+		AmountIn:  sdk.NewCoin(tokenInDenom, bestRoute.InAmount),
+		AmountOut: tokenOut.Amount,
+		Route:     []domain.SplitRoute{&bestRoute},
+	}
+
+	return finalQuote, routesWithAmountIn, nil
+}
+
 // validateAndFilterRoutes validates all routes. Specifically:
 // - all routes have at least one pool.
 // - all routes have the same final token out denom.
@@ -3,9 +3,14 @@ package usecase_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sort"
+	"sync"
+	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	dto "github.com/prometheus/client_model/go"
+
 	"github.com/osmosis-labs/sqs/sqsdomain"
 
 	"github.com/osmosis-labs/osmosis/osmomath"
@@ -15,9 +20,11 @@ import (
 	"github.com/osmosis-labs/sqs/domain"
 	"github.com/osmosis-labs/sqs/domain/cache"
 	"github.com/osmosis-labs/sqs/domain/mocks"
+	"github.com/osmosis-labs/sqs/domain/mvc"
 	"github.com/osmosis-labs/sqs/log"
 	poolsusecase "github.com/osmosis-labs/sqs/pools/usecase"
 	routerrepo "github.com/osmosis-labs/sqs/router/repository"
+	"github.com/osmosis-labs/sqs/router/types"
 	"github.com/osmosis-labs/sqs/router/usecase"
 	routerusecase "github.com/osmosis-labs/sqs/router/usecase"
 	"github.com/osmosis-labs/sqs/router/usecase/route"
@@ -826,6 +833,343 @@ func (s *RouterTestSuite) TestGetCustomQuote_GetCustomDirectQuote_Mainnet_UOSMOU
 	s.validateExpectedPoolIDOneRouteOneHopQuote(quote, expectedPoolID)
 }
 
+// Validates that the quote re-evaluated from its spec against the same state
+// goes through the same routes and, for the exact amount in, results in the same amount out.
+func (s *RouterTestSuite) TestGetQuoteFromSpec_Mainnet() {
+	tc := optimalQuoteTestCases["uosmo for uion"]
+
+	for _, swapMethod := range []domain.TokenSwapMethod{domain.TokenSwapMethodExactIn, domain.TokenSwapMethodExactOut} {
+		s.Run(fmt.Sprintf("swap method %d", swapMethod), func() {
+			// Setup mainnet router
+			mainnetState := s.SetupMainnetState()
+
+			// Mock router use case.
+			mainnetUseCase := s.SetupRouterAndPoolsUsecase(mainnetState)
+
+			var (
+				quote domain.Quote
+				err   error
+			)
+			if swapMethod == domain.TokenSwapMethodExactIn {
+				quote, err = mainnetUseCase.Router.GetOptimalQuote(context.Background(), sdk.NewCoin(tc.tokenInDenom, tc.amountIn), tc.tokenOutDenom)
+			} else {
+				quote, err = mainnetUseCase.Router.GetOptimalQuoteInGivenOut(context.Background(), sdk.NewCoin(tc.tokenOutDenom, tc.amountIn), tc.tokenInDenom)
+			}
+			s.Require().NoError(err)
+
+			_, _, err = quote.PrepareResult(context.Background(), osmomath.OneDec(), &log.NoOpLogger{})
+			s.Require().NoError(err)
+
+			spec, err := domain.NewQuoteSpec(quote, swapMethod, 1)
+			s.Require().NoError(err)
+
+			// System under test
+			reevaluatedQuote, err := mainnetUseCase.Router.GetQuoteFromSpec(context.Background(), spec)
+			s.Require().NoError(err)
+
+			_, _, err = reevaluatedQuote.PrepareResult(context.Background(), osmomath.OneDec(), &log.NoOpLogger{})
+			s.Require().NoError(err)
+
+			// The spec of the re-evaluated quote only differs in the quoted amount, if at all.
+			reevaluatedSpec, err := domain.NewQuoteSpec(reevaluatedQuote, swapMethod, 1)
+			s.Require().NoError(err)
+			s.Require().Equal(spec.Token.String(), reevaluatedSpec.Token.String())
+			s.Require().Len(reevaluatedSpec.Routes, len(spec.Routes))
+			for i := range spec.Routes {
+				s.Require().Equal(spec.Routes[i].PoolIDs, reevaluatedSpec.Routes[i].PoolIDs)
+				s.Require().Equal(spec.Routes[i].Denoms, reevaluatedSpec.Routes[i].Denoms)
+				s.Require().Equal(spec.Routes[i].Amount.String(), reevaluatedSpec.Routes[i].Amount.String())
+			}
+
+			if swapMethod == domain.TokenSwapMethodExactIn {
+				s.Require().Equal(spec.QuotedAmount.String(), reevaluatedSpec.QuotedAmount.String())
+			}
+		})
+	}
+}
+
+// Validates that the gas-aware ranking sets the gas estimate on the quote with the net amount out
+// being the amount out minus the gas cost in the token out denom.
+// Additionally, validates that a prohibitive gas price leads to a single route being selected.
+func (s *RouterTestSuite) TestGetOptimalQuote_GasAware_Mainnet() {
+	quoteTestCase := optimalQuoteTestCases["uosmo for uion"]
+	tokenIn := sdk.NewCoin(quoteTestCase.tokenInDenom, quoteTestCase.amountIn)
+	gasCostModel := routertesting.DefaultRouterConfig.GasCostModel
+
+	tests := map[string]struct {
+		gasPriceInTokenOut osmomath.Dec
+
+		expectSingleRoute bool
+	}{
+		"low gas price": {
+			gasPriceInTokenOut: osmomath.MustNewDecFromStr("0.000001"),
+		},
+		"prohibitive gas price": {
+			gasPriceInTokenOut: osmomath.NewDec(1_000_000),
+
+			expectSingleRoute: true,
+		},
+	}
+
+	for name, tc := range tests {
+		s.Run(name, func() {
+			// Setup mainnet router
+			mainnetState := s.SetupMainnetState()
+
+			// Mock router use case.
+			mainnetUseCase := s.SetupRouterAndPoolsUsecase(mainnetState)
+
+			// System under test
+			quote, err := mainnetUseCase.Router.GetOptimalQuote(context.Background(), tokenIn, quoteTestCase.tokenOutDenom, domain.WithGasAwareRanking(tc.gasPriceInTokenOut))
+			s.Require().NoError(err)
+
+			q, ok := quote.(*routerusecase.QuoteImpl)
+			s.Require().True(ok)
+			s.Require().NotNil(q.GasEstimate)
+
+			if tc.expectSingleRoute {
+				s.Require().Len(q.Route, 1)
+			}
+
+			// Recompute the expected gas over the selected routes.
+			expectedGas := gasCostModel.BaseGas
+			for _, splitRoute := range q.Route {
+				routeWithAmountOut, ok := splitRoute.(*routerusecase.RouteWithOutAmount)
+				s.Require().True(ok)
+
+				routeGas, err := routeWithAmountOut.EstimateGas(context.Background(), sdk.NewCoin(tokenIn.Denom, routeWithAmountOut.InAmount), gasCostModel)
+				s.Require().NoError(err)
+
+				expectedGas += routeGas
+			}
+
+			expectedCostInTokenOut := tc.gasPriceInTokenOut.MulInt64(int64(expectedGas)).Ceil().TruncateInt()
+
+			s.Require().Equal(expectedGas, q.GasEstimate.Gas)
+			s.Require().Equal(expectedCostInTokenOut.String(), q.GasEstimate.CostInTokenOut.String())
+			s.Require().Equal(q.AmountOut.Sub(expectedCostInTokenOut).String(), q.GasEstimate.NetAmountOut.String())
+
+			// The gas-aware ranking is not written to the ranked route cache shared with the other requests.
+			routerUseCase, ok := mainnetUseCase.Router.(*routerusecase.RouterUseCaseImpl)
+			s.Require().True(ok)
+
+			cachedRankedRoutes, err := routerUseCase.GetCachedRankedRoutes(context.Background(), tokenIn.Denom, quoteTestCase.tokenOutDenom, routerusecase.GetPrecomputeOrderOfMagnitude(tokenIn.Amount))
+			s.Require().NoError(err)
+			s.Require().Empty(cachedRankedRoutes.Routes)
+		})
+	}
+}
+
+// Validates that the amount out net of the gas cost fails for the quote without the gas estimate
+// rather than treating it as zero.
+func (s *RouterTestSuite) TestNetAmountOut() {
+	gasCostModel := routertesting.DefaultRouterConfig.GasCostModel
+	gasPriceInTokenOut := osmomath.OneDec()
+
+	tests := map[string]struct {
+		gasPriceInTokenOut *osmomath.Dec
+		gasEstimate        *domain.GasEstimate
+
+		expectedNetAmountOut osmomath.Int
+		expectedError        error
+	}{
+		"nil ranker returns the amount out": {
+			expectedNetAmountOut: osmomath.NewInt(1000),
+		},
+		"gas estimate set": {
+			gasPriceInTokenOut: &gasPriceInTokenOut,
+			gasEstimate:        &domain.GasEstimate{NetAmountOut: osmomath.NewInt(900)},
+
+			expectedNetAmountOut: osmomath.NewInt(900),
+		},
+		"gas estimate not set": {
+			gasPriceInTokenOut: &gasPriceInTokenOut,
+
+			expectedError: types.ErrGasEstimateNotFound,
+		},
+	}
+
+	for name, tc := range tests {
+		s.Run(name, func() {
+			quote := &routerusecase.QuoteImpl{
+				AmountIn:    sdk.NewCoin(UOSMO, osmomath.NewInt(1000)),
+				AmountOut:   osmomath.NewInt(1000),
+				GasEstimate: tc.gasEstimate,
+			}
+
+			netAmountOut, err := routerusecase.NetAmountOut(gasCostModel, tc.gasPriceInTokenOut, quote)
+			if tc.expectedError != nil {
+				s.Require().ErrorIs(err, tc.expectedError)
+				return
+			}
+
+			s.Require().NoError(err)
+			s.Require().Equal(tc.expectedNetAmountOut.String(), netAmountOut.String())
+		})
+	}
+}
+
+// Validates that the concurrent identical quotes computed before the ranked route cache is populated
+// share the ranked routes computed once and return the same quote as the one computed without the caches.
+// The quotes are bounded by the configured compute deadline, which does not prevent the coalescing.
+func (s *RouterTestSuite) TestGetOptimalQuote_Coalescing_Mainnet() {
+	const concurrentRequests = 10
+
+	quoteTestCase := optimalQuoteTestCases["uosmo for uion"]
+	tokenIn := sdk.NewCoin(quoteTestCase.tokenInDenom, quoteTestCase.amountIn)
+
+	routerConfig := routertesting.DefaultRouterConfig
+	routerConfig.QuoteComputeDeadlineMs = 60_000
+
+	// Setup mainnet router
+	mainnetState := s.SetupMainnetState()
+
+	expectedQuote, err := s.SetupRouterAndPoolsUsecase(mainnetState).Router.GetOptimalQuote(context.Background(), tokenIn, quoteTestCase.tokenOutDenom, domain.WithDisableCache())
+	s.Require().NoError(err)
+
+	mainnetUseCase := s.SetupRouterAndPoolsUsecase(mainnetState, routertesting.WithRouterConfig(routerConfig))
+
+	computedBefore := s.getCoalescingCount(routerusecase.CoalescingResultComputed)
+	sharedBefore := s.getCoalescingCount(routerusecase.CoalescingResultShared)
+
+	// System under test
+	quotes, errs := s.getOptimalQuotesConcurrently(mainnetUseCase.Router, concurrentRequests, tokenIn, quoteTestCase.tokenOutDenom)
+
+	for i := 0; i < concurrentRequests; i++ {
+		s.Require().NoError(errs[i])
+		s.Require().Equal(expectedQuote.GetAmountOut().String(), quotes[i].GetAmountOut().String())
+		s.Require().Equal(len(expectedQuote.GetRoute()), len(quotes[i].GetRoute()))
+	}
+
+	// The ranked routes are computed once and shared with the requests that were waiting for them.
+	// The requests starting after the computation hit the ranked route cache instead.
+	s.Require().Equal(float64(1), s.getCoalescingCount(routerusecase.CoalescingResultComputed)-computedBefore)
+	s.Require().GreaterOrEqual(s.getCoalescingCount(routerusecase.CoalescingResultShared)-sharedBefore, float64(1))
+
+	// The ranked routes are cached by the computation shared across the requests.
+	routerUseCase, ok := mainnetUseCase.Router.(*routerusecase.RouterUseCaseImpl)
+	s.Require().True(ok)
+
+	cachedRankedRoutes, err := routerUseCase.GetCachedRankedRoutes(context.Background(), tokenIn.Denom, quoteTestCase.tokenOutDenom, routerusecase.GetPrecomputeOrderOfMagnitude(tokenIn.Amount))
+	s.Require().NoError(err)
+	s.Require().NotEmpty(cachedRankedRoutes.Routes)
+}
+
+// Validates that the concurrent quotes with a compute deadline set by the request are not coalesced,
+// each computing the ranked routes and returning the same quote as the one computed without the caches.
+func (s *RouterTestSuite) TestGetOptimalQuote_Coalescing_RequestComputeDeadline_Mainnet() {
+	const concurrentRequests = 5
+
+	quoteTestCase := optimalQuoteTestCases["uosmo for uion"]
+	tokenIn := sdk.NewCoin(quoteTestCase.tokenInDenom, quoteTestCase.amountIn)
+
+	routerConfig := routertesting.DefaultRouterConfig
+	routerConfig.QuoteComputeDeadlineMs = 60_000
+
+	// Setup mainnet router
+	mainnetState := s.SetupMainnetState()
+
+	expectedQuote, err := s.SetupRouterAndPoolsUsecase(mainnetState).Router.GetOptimalQuote(context.Background(), tokenIn, quoteTestCase.tokenOutDenom, domain.WithDisableCache())
+	s.Require().NoError(err)
+
+	mainnetUseCase := s.SetupRouterAndPoolsUsecase(mainnetState, routertesting.WithRouterConfig(routerConfig))
+
+	computedBefore := s.getCoalescingCount(routerusecase.CoalescingResultComputed)
+	sharedBefore := s.getCoalescingCount(routerusecase.CoalescingResultShared)
+
+	// System under test
+	quotes, errs := s.getOptimalQuotesConcurrently(mainnetUseCase.Router, concurrentRequests, tokenIn, quoteTestCase.tokenOutDenom, domain.WithComputeDeadline(30*time.Second))
+
+	for i := 0; i < concurrentRequests; i++ {
+		s.Require().NoError(errs[i])
+		s.Require().Equal(expectedQuote.GetAmountOut().String(), quotes[i].GetAmountOut().String())
+		s.Require().False(quotes[i].(*routerusecase.QuoteImpl).Partial)
+	}
+
+	// None of the requests went through the coalescing.
+	s.Require().Equal(computedBefore, s.getCoalescingCount(routerusecase.CoalescingResultComputed))
+	s.Require().Equal(sharedBefore, s.getCoalescingCount(routerusecase.CoalescingResultShared))
+}
+
+// getOptimalQuotesConcurrently computes the given number of identical quotes concurrently, starting them at once.
+func (s *RouterTestSuite) getOptimalQuotesConcurrently(router mvc.RouterUsecase, numQuotes int, tokenIn sdk.Coin, tokenOutDenom string, opts ...domain.RouterOption) ([]domain.Quote, []error) {
+	var (
+		wg    sync.WaitGroup
+		start = make(chan struct{})
+
+		quotes = make([]domain.Quote, numQuotes)
+		errs   = make([]error, numQuotes)
+	)
+	for i := 0; i < numQuotes; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			quotes[i], errs[i] = router.GetOptimalQuote(context.Background(), tokenIn, tokenOutDenom, opts...)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	return quotes, errs
+}
+
+// getCoalescingCount returns the value of the ranked route coalescing counter with the given result.
+func (s *RouterTestSuite) getCoalescingCount(result string) float64 {
+	metric := &dto.Metric{}
+	s.Require().NoError(domain.SQSRouterRankedRoutesCoalescingCounter.WithLabelValues(result).Write(metric))
+	return metric.GetCounter().GetValue()
+}
+
+// Validates that the concurrent gas-aware and plain quotes for the same pair and order of magnitude
+// do not share the ranked routes, each returning the same quote as when computed alone.
+func (s *RouterTestSuite) TestGetOptimalQuote_Coalescing_GasAwareAndPlain_Mainnet() {
+	quoteTestCase := optimalQuoteTestCases["uosmo for uion"]
+	tokenIn := sdk.NewCoin(quoteTestCase.tokenInDenom, quoteTestCase.amountIn)
+
+	// The prohibitive gas price leads to a single route being selected by the gas-aware ranking.
+	gasAwareOpt := domain.WithGasAwareRanking(osmomath.NewDec(1_000_000))
+
+	// Setup mainnet router
+	mainnetState := s.SetupMainnetState()
+
+	expectedPlainQuote, err := s.SetupRouterAndPoolsUsecase(mainnetState).Router.GetOptimalQuote(context.Background(), tokenIn, quoteTestCase.tokenOutDenom, domain.WithDisableCache())
+	s.Require().NoError(err)
+
+	expectedGasAwareQuote, err := s.SetupRouterAndPoolsUsecase(mainnetState).Router.GetOptimalQuote(context.Background(), tokenIn, quoteTestCase.tokenOutDenom, gasAwareOpt)
+	s.Require().NoError(err)
+
+	mainnetUseCase := s.SetupRouterAndPoolsUsecase(mainnetState)
+
+	// System under test
+	var (
+		wg sync.WaitGroup
+
+		plainQuote, gasAwareQuote domain.Quote
+		plainErr, gasAwareErr     error
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		plainQuote, plainErr = mainnetUseCase.Router.GetOptimalQuote(context.Background(), tokenIn, quoteTestCase.tokenOutDenom)
+	}()
+	go func() {
+		defer wg.Done()
+		gasAwareQuote, gasAwareErr = mainnetUseCase.Router.GetOptimalQuote(context.Background(), tokenIn, quoteTestCase.tokenOutDenom, gasAwareOpt)
+	}()
+	wg.Wait()
+
+	s.Require().NoError(plainErr)
+	s.Require().Equal(expectedPlainQuote.GetAmountOut().String(), plainQuote.GetAmountOut().String())
+	s.Require().Equal(len(expectedPlainQuote.GetRoute()), len(plainQuote.GetRoute()))
+	s.Require().Nil(plainQuote.(*routerusecase.QuoteImpl).GasEstimate)
+
+	s.Require().NoError(gasAwareErr)
+	s.Require().Equal(expectedGasAwareQuote.GetAmountOut().String(), gasAwareQuote.GetAmountOut().String())
+	s.Require().Len(gasAwareQuote.GetRoute(), 1)
+	s.Require().NotNil(gasAwareQuote.(*routerusecase.QuoteImpl).GasEstimate)
+}
+
 // Validates that the logic skips errors from individual routes
 // and only fails if all routes error.
 // Additionally, validates that the highest amount route is chosen, routes
@@ -1029,6 +1373,81 @@ func (s *RouterTestSuite) TestEstimateAndRankSingleRouteQuote() {
 }
 
 // validates that the given quote has one route with one hop and the expected pool ID.
+// Validates that once the compute deadline is exceeded, the routes quoted so far are ranked
+// and the quote is flagged as partial. If no route is quoted, the deadline error is returned
+// without invalidating the caches.
+func (s *RouterTestSuite) TestEstimateAndRankSingleRouteQuote_ComputeDeadline() {
+	// Setup mock router use case
+	mainnetState := s.SetupMainnetState()
+	usecase := s.SetupRouterAndPoolsUsecase(mainnetState)
+	routerUseCase, ok := usecase.Router.(*routerusecase.RouterUseCaseImpl)
+	s.Require().True(ok)
+
+	tokenInAmount := osmomath.NewInt(5000000)
+	tokenInOrderOfMagnitude := routerusecase.GetPrecomputeOrderOfMagnitude(tokenInAmount)
+	defaultTokenIn := sdk.NewCoin(UOSMO, tokenInAmount)
+	tokenOutDenom := UION
+	lessDefaultAmount := defaultAmount.QuoRaw(2)
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+
+	// Pool that exceeds the compute deadline after being quoted.
+	deadlineMockPool := &mocks.MockRoutablePool{
+		TakerFee: osmomath.ZeroDec(),
+
+		CalculateTokenOutByTokenInFunc: func(ctx context.Context, tokenIn sdk.Coin) (sdk.Coin, error) {
+			cancel(domain.ErrComputeDeadlineExceeded)
+			return sdk.NewCoin(tokenOutDenom, lessDefaultAmount), nil
+		},
+
+		TokenOutDenom: tokenOutDenom,
+	}
+
+	// Pool with the better quote that is never reached.
+	validMockPool := &mocks.MockRoutablePool{
+		TakerFee: osmomath.ZeroDec(),
+
+		CalculateTokenOutByTokenInFunc: func(ctx context.Context, tokenIn sdk.Coin) (sdk.Coin, error) {
+			return sdk.NewCoin(tokenOutDenom, defaultAmount), nil
+		},
+
+		TokenOutDenom: tokenOutDenom,
+	}
+
+	routes := []route.RouteImpl{
+		WithRoutePools(EmptyRoute, []domain.RoutablePool{deadlineMockPool}),
+		WithRoutePools(EmptyRoute, []domain.RoutablePool{validMockPool}),
+	}
+
+	// System under test
+	quote, rankedRoutes, err := routerUseCase.EstimateAndRankSingleRouteQuote(ctx, routes, defaultTokenIn, &log.NoOpLogger{})
+	s.Require().NoError(err)
+
+	s.Require().Equal(lessDefaultAmount, quote.GetAmountOut())
+	s.Require().Len(rankedRoutes, 1)
+
+	q, ok := quote.(*routerusecase.QuoteImpl)
+	s.Require().True(ok)
+	s.Require().True(q.Partial)
+
+	// No route is quoted once the deadline is exceeded.
+	routerUseCase.SetCandidateRouteCacheToMock(defaultTokenIn.Denom, tokenOutDenom)
+	routerUseCase.SetRankedRouteCacheToMock(defaultTokenIn.Denom, tokenOutDenom, tokenInOrderOfMagnitude)
+
+	_, _, err = routerUseCase.EstimateAndRankSingleRouteQuote(ctx, routes, defaultTokenIn, &log.NoOpLogger{})
+	s.Require().ErrorIs(err, domain.ErrComputeDeadlineExceeded)
+
+	// Validate caches did not get invalidated
+	_, foundcandidateRoutes, err := routerUseCase.GetCachedCandidateRoutes(context.Background(), defaultTokenIn.Denom, tokenOutDenom)
+	s.Require().NoError(err)
+	s.Require().True(foundcandidateRoutes)
+
+	cachedRankedRoutes, err := routerUseCase.GetCachedRankedRoutes(context.Background(), defaultTokenIn.Denom, tokenOutDenom, tokenInOrderOfMagnitude)
+	s.Require().NoError(err)
+	s.Require().NotEmpty(cachedRankedRoutes)
+}
+
 func (s *RouterTestSuite) validateExpectedPoolIDOneRouteOneHopQuote(quote domain.Quote, expectedPoolID uint64) {
 	routes := quote.GetRoute()
 	s.Require().Len(routes, 1)
@@ -0,0 +1,235 @@
+package usecase
+
+import (
+	"math"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	"github.com/osmosis-labs/sqs/domain"
+	"github.com/osmosis-labs/sqs/log"
+	"github.com/osmosis-labs/sqs/sqsdomain"
+)
+
+const (
+	// maxIncrementalSortTotalTVLDrift is the maximum relative drift of the total value locked across all pools
+	// from the one that the pools were rated with before the pools are fully re-sorted.
+	// The ratings depend on the total value locked so the order of the pools drifts with it.
+	maxIncrementalSortTotalTVLDrift = 0.01
+)
+
+// PoolSorter maintains the pools sorted for the router across blocks.
+// Instead of re-sorting all pools on every block, only the updated pools are re-rated
+// and repositioned by merging them into the sorted pools of the previous block.
+//
+// The ratings are computed with the total value locked across all pools as of the last full sort.
+// Additionally, the liquidity capitalization of the pools is repriced asynchronously after their block is ingested.
+// The repriced pools are re-rated with Rerate once the pricing completes. As a result of the remaining drift,
+// the pools are fully re-sorted every fullSortIntervalBlocks blocks or whenever the total value locked drifts
+// by more than maxIncrementalSortTotalTVLDrift.
+//
+// The sorted pools returned are a snapshot that may be shared by multiple router usecases.
+type PoolSorter struct {
+	mu sync.Mutex
+
+	cosmWasmPoolsConfig    domain.CosmWasmPoolRouterConfig
+	preferredPoolIDsMap    map[uint64]struct{}
+	fullSortIntervalBlocks uint64
+	logger                 log.Logger
+
+	// ratedPools are the valid pools sorted by the rating in descending order.
+	ratedPools []ratedPool
+	// liquidityCapByPoolID is the liquidity capitalization of each of the rated pools
+	// as accounted for in the total value locked.
+	liquidityCapByPoolID map[uint64]osmomath.Int
+	// totalTVL is the total value locked across the rated pools.
+	totalTVL osmomath.Int
+	// ratingTotalTVLFloat is the total value locked that the pools are rated with.
+	ratingTotalTVLFloat float64
+	// blocksSinceFullSort is the number of updates since the last full sort.
+	blocksSinceFullSort uint64
+}
+
+// NewPoolSorter returns a new pool sorter with the given configuration.
+// The pools are fully re-sorted every fullSortIntervalBlocks blocks.
+// If fullSortIntervalBlocks is at most one, the pools are fully re-sorted on every block.
+func NewPoolSorter(cosmWasmPoolsConfig domain.CosmWasmPoolRouterConfig, preferredPoolIDs []uint64, fullSortIntervalBlocks uint64, logger log.Logger) *PoolSorter {
+	preferredPoolIDsMap := make(map[uint64]struct{}, len(preferredPoolIDs))
+	for _, poolID := range preferredPoolIDs {
+		preferredPoolIDsMap[poolID] = struct{}{}
+	}
+
+	return &PoolSorter{
+		cosmWasmPoolsConfig:    cosmWasmPoolsConfig,
+		preferredPoolIDsMap:    preferredPoolIDsMap,
+		fullSortIntervalBlocks: fullSortIntervalBlocks,
+		logger:                 logger,
+
+		ratedPools:           []ratedPool{},
+		liquidityCapByPoolID: map[uint64]osmomath.Int{},
+		totalTVL:             osmomath.ZeroInt(),
+	}
+}
+
+// Update repositions the given updated pools and returns all the sorted pools.
+// The pools are fully re-sorted instead if no pools were sorted yet, if the full sort interval has elapsed
+// or if the total value locked has drifted. In that case, all pools are retrieved with getAllPoolsCb.
+// Returns error if getAllPoolsCb fails.
+func (s *PoolSorter) Update(updatedPools []sqsdomain.PoolI, getAllPoolsCb func() ([]sqsdomain.PoolI, error)) ([]sqsdomain.PoolI, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.blocksSinceFullSort++
+
+	if s.shouldFullySortUnsafe() {
+		allPools, err := getAllPoolsCb()
+		if err != nil {
+			return nil, err
+		}
+
+		s.sortAllUnsafe(allPools)
+
+		return s.getPoolsUnsafe(), nil
+	}
+
+	s.repositionUnsafe(updatedPools)
+
+	return s.getPoolsUnsafe(), nil
+}
+
+// Rerate re-rates and repositions the given pools whose liquidity capitalization was repriced
+// and returns all the sorted pools. Unlike Update, it does not count towards the full sort interval.
+// Returns false if no pools were sorted yet since the repriced pools alone are not the full set of pools.
+func (s *PoolSorter) Rerate(repricedPools []sqsdomain.PoolI) ([]sqsdomain.PoolI, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.ratedPools) == 0 {
+		return nil, false
+	}
+
+	s.repositionUnsafe(repricedPools)
+
+	return s.getPoolsUnsafe(), true
+}
+
+// SortAll fully re-sorts the given pools and returns them sorted.
+func (s *PoolSorter) SortAll(pools []sqsdomain.PoolI) []sqsdomain.PoolI {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sortAllUnsafe(pools)
+
+	return s.getPoolsUnsafe()
+}
+
+// shouldFullySortUnsafe returns true if the pools must be fully re-sorted.
+// CONTRACT: the caller holds the mutex.
+func (s *PoolSorter) shouldFullySortUnsafe() bool {
+	if len(s.ratedPools) == 0 || s.fullSortIntervalBlocks <= 1 || s.blocksSinceFullSort >= s.fullSortIntervalBlocks {
+		return true
+	}
+
+	if s.ratingTotalTVLFloat == 0 {
+		return !s.totalTVL.IsZero()
+	}
+
+	totalTVLFloat, _ := s.totalTVL.BigIntMut().Float64()
+	return math.Abs(totalTVLFloat-s.ratingTotalTVLFloat)/s.ratingTotalTVLFloat > maxIncrementalSortTotalTVLDrift
+}
+
+// sortAllUnsafe validates, rates and sorts all the given pools, replacing the sorted pools.
+// CONTRACT: the caller holds the mutex.
+func (s *PoolSorter) sortAllUnsafe(pools []sqsdomain.PoolI) {
+	validPools := make([]sqsdomain.PoolI, 0, len(pools))
+	liquidityCapByPoolID := make(map[uint64]osmomath.Int, len(pools))
+	totalTVL := osmomath.ZeroInt()
+	for _, pool := range pools {
+		if isValid, _ := validatePool(pool, s.cosmWasmPoolsConfig, s.logger); !isValid {
+			continue
+		}
+
+		liquidityCap := pool.GetPoolLiquidityCap()
+
+		validPools = append(validPools, pool)
+		liquidityCapByPoolID[pool.GetId()] = liquidityCap
+		totalTVL = totalTVL.Add(liquidityCap)
+	}
+
+	totalTVLFloat, _ := totalTVL.BigIntMut().Float64()
+
+	s.ratedPools = rateAndSortPools(validPools, s.cosmWasmPoolsConfig, totalTVLFloat, s.preferredPoolIDsMap, s.logger)
+	s.liquidityCapByPoolID = liquidityCapByPoolID
+	s.totalTVL = totalTVL
+	s.ratingTotalTVLFloat = totalTVLFloat
+	s.blocksSinceFullSort = 0
+
+	s.logger.Debug("fully sorted pools", zap.Int("pool_count", len(s.ratedPools)))
+}
+
+// repositionUnsafe removes the given updated pools from the sorted pools, re-rates the valid ones and merges
+// them back into the sorted pools. The cost is linear in the number of pools rather than linearithmic
+// and only the updated pools are validated and rated.
+// CONTRACT: the caller holds the mutex.
+func (s *PoolSorter) repositionUnsafe(updatedPools []sqsdomain.PoolI) {
+	if len(updatedPools) == 0 {
+		return
+	}
+
+	updatedPoolIDs := make(map[uint64]struct{}, len(updatedPools))
+	validUpdatedPools := make([]sqsdomain.PoolI, 0, len(updatedPools))
+	for _, pool := range updatedPools {
+		poolID := pool.GetId()
+		if _, ok := updatedPoolIDs[poolID]; ok {
+			continue
+		}
+		updatedPoolIDs[poolID] = struct{}{}
+
+		if liquidityCap, ok := s.liquidityCapByPoolID[poolID]; ok {
+			s.totalTVL = s.totalTVL.Sub(liquidityCap)
+			delete(s.liquidityCapByPoolID, poolID)
+		}
+
+		if isValid, _ := validatePool(pool, s.cosmWasmPoolsConfig, s.logger); !isValid {
+			continue
+		}
+
+		liquidityCap := pool.GetPoolLiquidityCap()
+
+		validUpdatedPools = append(validUpdatedPools, pool)
+		s.liquidityCapByPoolID[poolID] = liquidityCap
+		s.totalTVL = s.totalTVL.Add(liquidityCap)
+	}
+
+	ratedUpdatedPools := rateAndSortPools(validUpdatedPools, s.cosmWasmPoolsConfig, s.ratingTotalTVLFloat, s.preferredPoolIDsMap, s.logger)
+
+	// Merge the pools that were not updated with the re-rated updated pools.
+	mergedPools := make([]ratedPool, 0, len(s.ratedPools)+len(ratedUpdatedPools))
+	j := 0
+	for _, pool := range s.ratedPools {
+		if _, isUpdated := updatedPoolIDs[pool.pool.GetId()]; isUpdated {
+			continue
+		}
+
+		for j < len(ratedUpdatedPools) && ratedUpdatedPools[j].rating > pool.rating {
+			mergedPools = append(mergedPools, ratedUpdatedPools[j])
+			j++
+		}
+
+		mergedPools = append(mergedPools, pool)
+	}
+	mergedPools = append(mergedPools, ratedUpdatedPools[j:]...)
+
+	s.ratedPools = mergedPools
+}
+
+// getPoolsUnsafe returns a copy of the sorted pools.
+// CONTRACT: the caller holds the mutex.
+func (s *PoolSorter) getPoolsUnsafe() []sqsdomain.PoolI {
+	pools := make([]sqsdomain.PoolI, 0, len(s.ratedPools))
+	for _, pool := range s.ratedPools {
+		pools = append(pools, pool.pool)
+	}
+	return pools
+}
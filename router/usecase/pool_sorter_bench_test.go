@@ -0,0 +1,77 @@
+package usecase_test
+
+import (
+	"testing"
+
+	"github.com/osmosis-labs/sqs/domain"
+	"github.com/osmosis-labs/sqs/router/usecase"
+	"github.com/osmosis-labs/sqs/router/usecase/routertesting"
+	"github.com/osmosis-labs/sqs/sqsdomain"
+)
+
+// benchmarkBlockUpdatedPoolsCount is the number of pools updated within a block
+// in the pool sorting benchmarks.
+const benchmarkBlockUpdatedPoolsCount = 30
+
+// Microbenchmark for sorting the mainnet pools on every block by fully re-sorting all pools.
+func BenchmarkValidateAndSortPools(b *testing.B) {
+	allPools, cosmWasmPoolsConfig := setupPoolSortingBenchmark(b)
+
+	b.ResetTimer()
+
+	// Run the benchmark
+	for i := 0; i < b.N; i++ {
+		// System under test
+		_, _ = usecase.ValidateAndSortPools(allPools, cosmWasmPoolsConfig, []uint64{}, noOpLogger)
+	}
+}
+
+// Microbenchmark for sorting the mainnet pools on every block by repositioning only the updated pools.
+func BenchmarkPoolSorter_Update(b *testing.B) {
+	allPools, cosmWasmPoolsConfig := setupPoolSortingBenchmark(b)
+
+	getAllPools := func() ([]sqsdomain.PoolI, error) {
+		return allPools, nil
+	}
+
+	// Never fully re-sort within the benchmark.
+	poolSorter := usecase.NewPoolSorter(cosmWasmPoolsConfig, []uint64{}, uint64(b.N)+2, noOpLogger)
+	if _, err := poolSorter.Update(nil, getAllPools); err != nil {
+		b.Fatal(err)
+	}
+
+	updatedPools := allPools[:benchmarkBlockUpdatedPoolsCount]
+
+	b.ResetTimer()
+
+	// Run the benchmark
+	for i := 0; i < b.N; i++ {
+		// System under test
+		if _, err := poolSorter.Update(updatedPools, getAllPools); err != nil {
+			b.Errorf("Update returned an error: %v", err)
+		}
+	}
+}
+
+// setupPoolSortingBenchmark returns all the mainnet pools and the cosmwasm pool config to sort them with.
+func setupPoolSortingBenchmark(b *testing.B) ([]sqsdomain.PoolI, domain.CosmWasmPoolRouterConfig) {
+	// This is a hack to be able to use test suite helpers with the benchmark.
+	// We need to set testing.T for assertings within the helpers. Otherwise, it would block
+	s := RouterTestSuite{}
+	s.SetT(&testing.T{})
+
+	mainnetState := s.SetupMainnetState()
+
+	mainnetUseCase := s.SetupRouterAndPoolsUsecase(mainnetState, routertesting.WithLoggerDisabled())
+
+	allPools, err := mainnetUseCase.Pools.GetAllPools()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	if len(allPools) < benchmarkBlockUpdatedPoolsCount {
+		b.Fatalf("expected at least %d pools, got %d", benchmarkBlockUpdatedPoolsCount, len(allPools))
+	}
+
+	return allPools, mainnetUseCase.Pools.GetCosmWasmPoolConfig()
+}
@@ -0,0 +1,269 @@
+package usecase_test
+
+import (
+	"errors"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	poolmanagertypes "github.com/osmosis-labs/osmosis/v25/x/poolmanager/types"
+
+	"github.com/osmosis-labs/sqs/domain"
+	"github.com/osmosis-labs/sqs/domain/mocks"
+	"github.com/osmosis-labs/sqs/router/usecase"
+	"github.com/osmosis-labs/sqs/router/usecase/routertesting"
+	"github.com/osmosis-labs/sqs/sqsdomain"
+)
+
+// Tests that the pool sorter repositions the updated pools incrementally
+// and falls back to the full sort as intended.
+func (s *RouterTestSuite) TestPoolSorter_Update() {
+	const defaultFullSortIntervalBlocks = 10
+
+	newPool := func(poolID uint64, liquidityCap int64) sqsdomain.PoolI {
+		return &mocks.MockRoutablePool{
+			ID:               poolID,
+			PoolLiquidityCap: osmomath.NewInt(liquidityCap),
+			PoolType:         poolmanagertypes.Balancer,
+		}
+	}
+
+	// Pool 5 dominates the total value locked so that the small pools
+	// can be updated without drifting the total value locked.
+	defaultPools := []sqsdomain.PoolI{
+		newPool(1, 100_000),
+		newPool(2, 200_000),
+		newPool(3, 300_000),
+		newPool(4, 400_000),
+		newPool(5, 100_000_000),
+	}
+
+	tests := []struct {
+		name string
+
+		fullSortIntervalBlocks uint64
+		updatedPools           []sqsdomain.PoolI
+		getAllPoolsErr         error
+
+		expectedPoolIDs          []uint64
+		expectedGetAllPoolsCalls int
+		expectedErr              error
+	}{
+		{
+			name: "no updated pools",
+
+			expectedPoolIDs:          []uint64{5, 4, 3, 2, 1},
+			expectedGetAllPoolsCalls: 1,
+		},
+		{
+			name:         "updated pool moves up",
+			updatedPools: []sqsdomain.PoolI{newPool(1, 350_000)},
+
+			expectedPoolIDs:          []uint64{5, 4, 1, 3, 2},
+			expectedGetAllPoolsCalls: 1,
+		},
+		{
+			name:         "updated pool moves down",
+			updatedPools: []sqsdomain.PoolI{newPool(4, 50_000)},
+
+			expectedPoolIDs:          []uint64{5, 3, 2, 1, 4},
+			expectedGetAllPoolsCalls: 1,
+		},
+		{
+			name:         "multiple updated pools, including a new one",
+			updatedPools: []sqsdomain.PoolI{newPool(6, 250_000), newPool(1, 500_000), newPool(4, 150_000)},
+
+			expectedPoolIDs:          []uint64{5, 1, 3, 6, 2, 4},
+			expectedGetAllPoolsCalls: 1,
+		},
+		{
+			name:         "total value locked drift triggers full sort",
+			updatedPools: []sqsdomain.PoolI{newPool(1, 5_000_000)},
+
+			expectedPoolIDs:          []uint64{5, 1, 4, 3, 2},
+			expectedGetAllPoolsCalls: 2,
+		},
+		{
+			name:                   "full sort on every block",
+			fullSortIntervalBlocks: 1,
+			updatedPools:           []sqsdomain.PoolI{newPool(1, 350_000)},
+
+			expectedPoolIDs:          []uint64{5, 4, 1, 3, 2},
+			expectedGetAllPoolsCalls: 2,
+		},
+		{
+			name:                   "error: failed to get all pools on full sort",
+			fullSortIntervalBlocks: 1,
+			updatedPools:           []sqsdomain.PoolI{newPool(1, 350_000)},
+			getAllPoolsErr:         errors.New("failed to get all pools"),
+
+			expectedGetAllPoolsCalls: 2,
+			expectedErr:              errors.New("failed to get all pools"),
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		s.Run(tc.name, func() {
+			fullSortIntervalBlocks := tc.fullSortIntervalBlocks
+			if fullSortIntervalBlocks == 0 {
+				fullSortIntervalBlocks = defaultFullSortIntervalBlocks
+			}
+
+			poolsByID := make(map[uint64]sqsdomain.PoolI, len(defaultPools))
+			for _, pool := range defaultPools {
+				poolsByID[pool.GetId()] = pool
+			}
+
+			getAllPoolsCalls := 0
+			var getAllPoolsErr error
+			getAllPools := func() ([]sqsdomain.PoolI, error) {
+				getAllPoolsCalls++
+				if getAllPoolsErr != nil {
+					return nil, getAllPoolsErr
+				}
+
+				allPools := make([]sqsdomain.PoolI, 0, len(poolsByID))
+				for _, pool := range poolsByID {
+					allPools = append(allPools, pool)
+				}
+				return allPools, nil
+			}
+
+			poolSorter := usecase.NewPoolSorter(domain.CosmWasmPoolRouterConfig{}, []uint64{}, fullSortIntervalBlocks, noOpLogger)
+
+			// The first update always fully sorts the pools.
+			_, err := poolSorter.Update(nil, getAllPools)
+			s.Require().NoError(err)
+
+			// Store the updated pools similarly to the ingester.
+			for _, pool := range tc.updatedPools {
+				poolsByID[pool.GetId()] = pool
+			}
+			getAllPoolsErr = tc.getAllPoolsErr
+
+			// System under test
+			sortedPools, err := poolSorter.Update(tc.updatedPools, getAllPools)
+
+			s.Require().Equal(tc.expectedGetAllPoolsCalls, getAllPoolsCalls)
+
+			if tc.expectedErr != nil {
+				s.Require().Error(err)
+				s.Require().Equal(tc.expectedErr, err)
+				return
+			}
+			s.Require().NoError(err)
+
+			actualPoolIDs := make([]uint64, 0, len(sortedPools))
+			for _, pool := range sortedPools {
+				actualPoolIDs = append(actualPoolIDs, pool.GetId())
+			}
+			s.Require().Equal(tc.expectedPoolIDs, actualPoolIDs)
+		})
+	}
+}
+
+// Tests that the pools whose liquidity capitalization is repriced after their block is ingested are re-rated
+// and repositioned without counting towards the full sort interval.
+func (s *RouterTestSuite) TestPoolSorter_Rerate() {
+	const fullSortIntervalBlocks = 2
+
+	newPool := func(poolID uint64, liquidityCap int64) *mocks.MockRoutablePool {
+		return &mocks.MockRoutablePool{
+			ID:               poolID,
+			PoolLiquidityCap: osmomath.NewInt(liquidityCap),
+			PoolType:         poolmanagertypes.Balancer,
+		}
+	}
+
+	pools := []sqsdomain.PoolI{
+		newPool(1, 100_000),
+		newPool(2, 200_000),
+		newPool(3, 100_000_000),
+	}
+
+	getAllPoolsCalls := 0
+	getAllPools := func() ([]sqsdomain.PoolI, error) {
+		getAllPoolsCalls++
+		return pools, nil
+	}
+
+	poolSorter := usecase.NewPoolSorter(domain.CosmWasmPoolRouterConfig{}, []uint64{}, fullSortIntervalBlocks, noOpLogger)
+
+	// Nothing is re-rated before the pools are sorted.
+	_, ok := poolSorter.Rerate(pools)
+	s.Require().False(ok)
+
+	_, err := poolSorter.Update(nil, getAllPools)
+	s.Require().NoError(err)
+	s.Require().Equal(1, getAllPoolsCalls)
+
+	// Reprice the liquidity capitalization of pool 1 in place similarly to the pool liquidity pricer worker.
+	repricedPool := pools[0].(*mocks.MockRoutablePool)
+	repricedPool.PoolLiquidityCap = osmomath.NewInt(300_000)
+
+	// System under test
+	sortedPools, ok := poolSorter.Rerate([]sqsdomain.PoolI{repricedPool})
+	s.Require().True(ok)
+
+	actualPoolIDs := make([]uint64, 0, len(sortedPools))
+	for _, pool := range sortedPools {
+		actualPoolIDs = append(actualPoolIDs, pool.GetId())
+	}
+	s.Require().Equal([]uint64{3, 1, 2}, actualPoolIDs)
+
+	// The re-rating does not count towards the full sort interval.
+	_, err = poolSorter.Update(nil, getAllPools)
+	s.Require().NoError(err)
+	s.Require().Equal(1, getAllPoolsCalls)
+}
+
+// Tests that repositioning unchanged mainnet pools incrementally yields
+// the same order as fully sorting them.
+func (s *RouterTestSuite) TestPoolSorter_Update_MainnetState() {
+	const (
+		orderbookCodeID = uint64(885)
+
+		// The top pools have distinct ratings so that their order is deterministic.
+		numTopPoolsToCompare = 50
+		numUpdatedPools      = 100
+	)
+
+	mainnetState := s.SetupMainnetState()
+
+	mainnetUseCase := s.SetupRouterAndPoolsUsecase(mainnetState, routertesting.WithLoggerDisabled())
+
+	pools, err := mainnetUseCase.Pools.GetAllPools()
+	s.Require().NoError(err)
+
+	cosmWasmPoolsConfig := domain.CosmWasmPoolRouterConfig{
+		OrderbookCodeIDs: map[uint64]struct{}{
+			orderbookCodeID: {},
+		},
+	}
+
+	expectedSortedPools, _ := usecase.ValidateAndSortPools(pools, cosmWasmPoolsConfig, []uint64{}, noOpLogger)
+	s.Require().Greater(len(expectedSortedPools), numUpdatedPools)
+
+	poolSorter := usecase.NewPoolSorter(cosmWasmPoolsConfig, []uint64{}, 10, noOpLogger)
+
+	getAllPoolsCalls := 0
+	getAllPools := func() ([]sqsdomain.PoolI, error) {
+		getAllPoolsCalls++
+		return pools, nil
+	}
+
+	sortedPools, err := poolSorter.Update(nil, getAllPools)
+	s.Require().NoError(err)
+	s.Require().Len(sortedPools, len(expectedSortedPools))
+
+	// System under test
+	sortedPools, err = poolSorter.Update(expectedSortedPools[:numUpdatedPools], getAllPools)
+	s.Require().NoError(err)
+
+	// Only the first update fully sorts the pools.
+	s.Require().Equal(1, getAllPoolsCalls)
+
+	s.Require().Len(sortedPools, len(expectedSortedPools))
+	for i := 0; i < numTopPoolsToCompare; i++ {
+		s.Require().Equal(expectedSortedPools[i].GetId(), sortedPools[i].GetId())
+	}
+}
@@ -1,6 +1,8 @@
 package pools
 
 import (
+	"time"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/osmosis-labs/osmosis/osmomath"
 	cwpoolmodel "github.com/osmosis-labs/osmosis/v25/x/cosmwasmpool/model"
@@ -31,3 +33,7 @@ func NewRoutableCosmWasmPoolWithCustomModel(
 func (r *routableAlloyTransmuterPoolImpl) CheckStaticRateLimiter(tokenInCoin sdk.Coin) error {
 	return r.checkStaticRateLimiter(tokenInCoin)
 }
+
+func (r *routableAlloyTransmuterPoolImpl) CheckChangeRateLimiter(tokenInCoin sdk.Coin, blockTime time.Time) error {
+	return r.checkChangeRateLimiter(tokenInCoin, blockTime)
+}
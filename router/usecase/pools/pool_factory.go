@@ -1,6 +1,8 @@
 package pools
 
 import (
+	"fmt"
+
 	cosmwasmdomain "github.com/osmosis-labs/sqs/domain/cosmwasm"
 	"github.com/osmosis-labs/sqs/sqsdomain"
 
@@ -89,7 +91,9 @@ func NewRoutablePool(pool sqsdomain.PoolI, tokenOutDenom string, takerFee osmoma
 }
 
 // newRoutableCosmWasmPool creates a new RoutablePool for CosmWasm pools.
-// Panics if the given pool is not a cosmwasm pool or if the
+// The pools whose contract info matches one of the configured implementations are constructed
+// by the factory registered for it. Otherwise, the implementation is determined by the pool's code ID.
+// Returns error if the given pool is not a cosmwasm pool or if it is not supported.
 func newRoutableCosmWasmPool(pool sqsdomain.PoolI, tokenOutDenom string, takerFee osmomath.Dec, cosmWasmPoolsParams cosmwasmdomain.CosmWasmPoolsParams) (domain.RoutablePool, error) {
 	chainPool := pool.GetUnderlyingPool()
 	poolType := pool.GetType()
@@ -102,30 +106,26 @@ func newRoutableCosmWasmPool(pool sqsdomain.PoolI, tokenOutDenom string, takerFe
 		}
 	}
 
-	balances := pool.GetSQSPoolModel().Balances
+	if implementationName, ok := cosmWasmPoolsParams.Config.GetImplementation(pool.GetSQSPoolModel().CosmWasmPoolModel); ok {
+		registration, ok := GetRoutableCosmWasmPoolRegistration(implementationName)
+		if !ok {
+			return nil, domain.UnsupportedCosmWasmPoolError{
+				PoolId: cosmwasmPool.PoolId,
+			}
+		}
+
+		return registration.Factory(pool, cosmwasmPool, tokenOutDenom, takerFee, cosmWasmPoolsParams)
+	}
 
 	// Check if the pool is a transmuter pool
 	_, isTransmuter := cosmWasmPoolsParams.Config.TransmuterCodeIDs[cosmwasmPool.CodeId]
 	if isTransmuter {
-		spreadFactor := pool.GetSQSPoolModel().SpreadFactor
-
-		// Transmuter has a custom implementation since it does not need to interact with the chain.
-		return &routableTransmuterPoolImpl{
-			ChainPool:     cosmwasmPool,
-			Balances:      balances,
-			TokenOutDenom: tokenOutDenom,
-			TakerFee:      takerFee,
-			SpreadFactor:  spreadFactor,
-		}, nil
+		return newRoutableTransmuterPool(pool, cosmwasmPool, tokenOutDenom, takerFee, cosmWasmPoolsParams)
 	}
 
 	_, isGeneralizedCosmWasmPool := cosmWasmPoolsParams.Config.GeneralCosmWasmCodeIDs[cosmwasmPool.CodeId]
 	if isGeneralizedCosmWasmPool {
-		spreadFactor := pool.GetSQSPoolModel().SpreadFactor
-
-		// for most other CosmWasm pools, interaction with the chain will
-		// be required. As a result, we have a custom implementation.
-		return NewRoutableCosmWasmPool(cosmwasmPool, balances, tokenOutDenom, takerFee, spreadFactor, cosmWasmPoolsParams), nil
+		return newRoutableGeneralizedCosmWasmPool(pool, cosmwasmPool, tokenOutDenom, takerFee, cosmWasmPoolsParams)
 	}
 
 	return newRoutableCosmWasmPoolWithCustomModel(pool, cosmwasmPool, cosmWasmPoolsParams, tokenOutDenom, takerFee)
@@ -143,52 +143,19 @@ func newRoutableCosmWasmPoolWithCustomModel(
 	tokenOutDenom string,
 	takerFee osmomath.Dec,
 ) (domain.RoutablePool, error) {
-	sqsPoolModel := pool.GetSQSPoolModel()
-
-	// Check if the pool is a transmuter pool with alloyed assets
-	model := sqsPoolModel.CosmWasmPoolModel
-	balances := sqsPoolModel.Balances
-	spreadFactor := sqsPoolModel.SpreadFactor
+	model := pool.GetSQSPoolModel().CosmWasmPoolModel
 	if model != nil {
 		// since v2, we introduce concept of alloyed assets but not yet actively used
 		// since v3, we introduce concept of normalization factor
 		// `routableAlloyTransmuterPoolImpl` is v3 compatible
 		_, isAlloyedTransmuterCodeId := cosmWasmPoolsParams.Config.AlloyedTransmuterCodeIDs[cosmwasmPool.CodeId]
 		if isAlloyedTransmuterCodeId && model.IsAlloyTransmuter() {
-			if model.Data.AlloyTransmuter == nil {
-				return nil, domain.CosmWasmPoolDataMissingError{
-					CosmWasmPoolType: domain.CosmWasmPoolAlloyTransmuter,
-					PoolId:           pool.GetId(),
-				}
-			}
-
-			return &routableAlloyTransmuterPoolImpl{
-				ChainPool:           cosmwasmPool,
-				AlloyTransmuterData: model.Data.AlloyTransmuter,
-				Balances:            balances,
-				TokenOutDenom:       tokenOutDenom,
-				TakerFee:            takerFee,
-				SpreadFactor:        spreadFactor,
-			}, nil
+			return newRoutableAlloyTransmuterPool(pool, cosmwasmPool, tokenOutDenom, takerFee, cosmWasmPoolsParams)
 		}
 
 		_, isOrderbookCodeId := cosmWasmPoolsParams.Config.OrderbookCodeIDs[cosmwasmPool.CodeId]
 		if isOrderbookCodeId && model.IsOrderbook() {
-			if model.Data.Orderbook == nil {
-				return nil, domain.CosmWasmPoolDataMissingError{
-					CosmWasmPoolType: domain.CosmWasmPoolOrderbook,
-					PoolId:           pool.GetId(),
-				}
-			}
-
-			return &routableOrderbookPoolImpl{
-				ChainPool:     cosmwasmPool,
-				Balances:      balances,
-				TokenOutDenom: tokenOutDenom,
-				TakerFee:      takerFee,
-				SpreadFactor:  spreadFactor,
-				OrderbookData: model.Data.Orderbook,
-			}, nil
+			return newRoutableOrderbookPool(pool, cosmwasmPool, tokenOutDenom, takerFee, cosmWasmPoolsParams)
 		}
 	}
 
@@ -196,3 +163,110 @@ func newRoutableCosmWasmPoolWithCustomModel(
 		PoolId: cosmwasmPool.PoolId,
 	}
 }
+
+// newRoutableTransmuterPool implements RoutableCosmWasmPoolFactory for the transmuter v1 pools.
+func newRoutableTransmuterPool(pool sqsdomain.PoolI, cosmwasmPool *cwpoolmodel.CosmWasmPool, tokenOutDenom string, takerFee osmomath.Dec, _ cosmwasmdomain.CosmWasmPoolsParams) (domain.RoutablePool, error) {
+	sqsPoolModel := pool.GetSQSPoolModel()
+
+	// Transmuter has a custom implementation since it does not need to interact with the chain.
+	return &routableTransmuterPoolImpl{
+		ChainPool:     cosmwasmPool,
+		Balances:      sqsPoolModel.Balances,
+		TokenOutDenom: tokenOutDenom,
+		TakerFee:      takerFee,
+		SpreadFactor:  sqsPoolModel.SpreadFactor,
+	}, nil
+}
+
+// newRoutableGeneralizedCosmWasmPool implements RoutableCosmWasmPoolFactory for the generalized CosmWasm pools.
+func newRoutableGeneralizedCosmWasmPool(pool sqsdomain.PoolI, cosmwasmPool *cwpoolmodel.CosmWasmPool, tokenOutDenom string, takerFee osmomath.Dec, cosmWasmPoolsParams cosmwasmdomain.CosmWasmPoolsParams) (domain.RoutablePool, error) {
+	sqsPoolModel := pool.GetSQSPoolModel()
+
+	// for most other CosmWasm pools, interaction with the chain will
+	// be required. As a result, we have a custom implementation.
+	return NewRoutableCosmWasmPool(cosmwasmPool, sqsPoolModel.Balances, tokenOutDenom, takerFee, sqsPoolModel.SpreadFactor, cosmWasmPoolsParams), nil
+}
+
+// newRoutableAlloyTransmuterPool implements RoutableCosmWasmPoolFactory for the alloyed transmuter pools.
+// Returns error if the pool's model is missing the alloyed transmuter data.
+func newRoutableAlloyTransmuterPool(pool sqsdomain.PoolI, cosmwasmPool *cwpoolmodel.CosmWasmPool, tokenOutDenom string, takerFee osmomath.Dec, _ cosmwasmdomain.CosmWasmPoolsParams) (domain.RoutablePool, error) {
+	sqsPoolModel := pool.GetSQSPoolModel()
+
+	model := sqsPoolModel.CosmWasmPoolModel
+	if model == nil || model.Data.AlloyTransmuter == nil {
+		return nil, domain.CosmWasmPoolDataMissingError{
+			CosmWasmPoolType: domain.CosmWasmPoolAlloyTransmuter,
+			PoolId:           pool.GetId(),
+		}
+	}
+
+	return &routableAlloyTransmuterPoolImpl{
+		ChainPool:           cosmwasmPool,
+		AlloyTransmuterData: model.Data.AlloyTransmuter,
+		Balances:            sqsPoolModel.Balances,
+		TokenOutDenom:       tokenOutDenom,
+		TakerFee:            takerFee,
+		SpreadFactor:        sqsPoolModel.SpreadFactor,
+	}, nil
+}
+
+// newRoutableOrderbookPool implements RoutableCosmWasmPoolFactory for the orderbook pools.
+// Returns error if the pool's model is missing the orderbook data.
+func newRoutableOrderbookPool(pool sqsdomain.PoolI, cosmwasmPool *cwpoolmodel.CosmWasmPool, tokenOutDenom string, takerFee osmomath.Dec, _ cosmwasmdomain.CosmWasmPoolsParams) (domain.RoutablePool, error) {
+	sqsPoolModel := pool.GetSQSPoolModel()
+
+	model := sqsPoolModel.CosmWasmPoolModel
+	if model == nil || model.Data.Orderbook == nil {
+		return nil, domain.CosmWasmPoolDataMissingError{
+			CosmWasmPoolType: domain.CosmWasmPoolOrderbook,
+			PoolId:           pool.GetId(),
+		}
+	}
+
+	return &routableOrderbookPoolImpl{
+		ChainPool:     cosmwasmPool,
+		Balances:      sqsPoolModel.Balances,
+		TokenOutDenom: tokenOutDenom,
+		TakerFee:      takerFee,
+		SpreadFactor:  sqsPoolModel.SpreadFactor,
+		OrderbookData: model.Data.Orderbook,
+	}, nil
+}
+
+// newRoutableAstroportPCLPool implements RoutableCosmWasmPoolFactory for the Astroport PCL pools.
+// The PCL data is taken from the pool's model if present and from the data store otherwise.
+// The data store is refreshed in the background as the pools are updated, so the params may lag
+// the latest update of the pool until its refresh completes.
+// Falls back to the generalized CosmWasm pool querying the contract if the native math is disabled
+// or if no PCL data is available so that the pools remain routable until their params are fetched.
+// Returns error if the PCL data is invalid.
+func newRoutableAstroportPCLPool(pool sqsdomain.PoolI, cosmwasmPool *cwpoolmodel.CosmWasmPool, tokenOutDenom string, takerFee osmomath.Dec, cosmWasmPoolsParams cosmwasmdomain.CosmWasmPoolsParams) (domain.RoutablePool, error) {
+	sqsPoolModel := pool.GetSQSPoolModel()
+
+	model := sqsPoolModel.CosmWasmPoolModel
+	if !cosmWasmPoolsParams.Config.AstroportPCLEnabled || model == nil {
+		return newRoutableGeneralizedCosmWasmPool(pool, cosmwasmPool, tokenOutDenom, takerFee, cosmWasmPoolsParams)
+	}
+
+	pclData := model.Data.AstroportPCL
+	if pclData == nil && cosmWasmPoolsParams.AstroportPCLDataStore != nil {
+		pclData, _ = cosmWasmPoolsParams.AstroportPCLDataStore.Get(pool.GetId())
+	}
+
+	if pclData == nil {
+		return newRoutableGeneralizedCosmWasmPool(pool, cosmwasmPool, tokenOutDenom, takerFee, cosmWasmPoolsParams)
+	}
+
+	if err := pclData.Validate(); err != nil {
+		return nil, fmt.Errorf("pool (%d): %w", pool.GetId(), err)
+	}
+
+	return &routableAstroportPCLPoolImpl{
+		ChainPool:        cosmwasmPool,
+		AstroportPCLData: pclData,
+		Balances:         sqsPoolModel.Balances,
+		TokenOutDenom:    tokenOutDenom,
+		TakerFee:         takerFee,
+		SpreadFactor:     sqsPoolModel.SpreadFactor,
+	}, nil
+}
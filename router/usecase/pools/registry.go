@@ -0,0 +1,162 @@
+package pools
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	cwpoolmodel "github.com/osmosis-labs/osmosis/v25/x/cosmwasmpool/model"
+
+	"github.com/osmosis-labs/sqs/domain"
+	cosmwasmdomain "github.com/osmosis-labs/sqs/domain/cosmwasm"
+	"github.com/osmosis-labs/sqs/sqsdomain"
+	"github.com/osmosis-labs/sqs/sqsdomain/cosmwasmpool"
+)
+
+// RoutableCosmWasmPoolFactory constructs the routable pool of the given CosmWasm pool.
+// Returns error if the pool is missing the data required by the implementation.
+type RoutableCosmWasmPoolFactory func(pool sqsdomain.PoolI, cosmwasmPool *cwpoolmodel.CosmWasmPool, tokenOutDenom string, takerFee osmomath.Dec, cosmWasmPoolsParams cosmwasmdomain.CosmWasmPoolsParams) (domain.RoutablePool, error)
+
+// RoutableCosmWasmPoolRegistration is a routable CosmWasm pool implementation registered in the pool registry.
+type RoutableCosmWasmPoolRegistration struct {
+	// Name is the unique name of the implementation that the config refers to.
+	Name string
+	// Contract is the cw2 contract name that the implementation matches by default.
+	// May be empty, in which case the config must specify it.
+	Contract string
+	// VersionConstraint is the semver constraint on the cw2 contract version that the implementation matches by default.
+	// Empty matches any version.
+	VersionConstraint string
+	// Factory constructs the routable pool.
+	Factory RoutableCosmWasmPoolFactory
+}
+
+const (
+	// anyVersionConstraint is the semver constraint matching any version.
+	anyVersionConstraint = "*"
+)
+
+// routableCosmWasmPoolRegistry is the registry of the routable CosmWasm pool implementations keyed by name.
+var routableCosmWasmPoolRegistry = struct {
+	mu            sync.RWMutex
+	registrations map[string]RoutableCosmWasmPoolRegistration
+}{
+	registrations: map[string]RoutableCosmWasmPoolRegistration{},
+}
+
+func init() {
+	MustRegisterRoutableCosmWasmPool(RoutableCosmWasmPoolRegistration{
+		Name:              domain.TransmuterCosmWasmPoolImplementation,
+		Contract:          cosmwasmpool.ALLOY_TRANSMUTER_CONTRACT_NAME,
+		VersionConstraint: "< " + cosmwasmpool.ALLOY_TRANSMUTER_MIN_CONTRACT_VERSION,
+		Factory:           newRoutableTransmuterPool,
+	})
+
+	MustRegisterRoutableCosmWasmPool(RoutableCosmWasmPoolRegistration{
+		Name:              domain.AlloyedTransmuterCosmWasmPoolImplementation,
+		Contract:          cosmwasmpool.ALLOY_TRANSMUTER_CONTRACT_NAME,
+		VersionConstraint: cosmwasmpool.ALLOY_TRANSMUTER_CONTRACT_VERSION_CONSTRAINT,
+		Factory:           newRoutableAlloyTransmuterPool,
+	})
+
+	MustRegisterRoutableCosmWasmPool(RoutableCosmWasmPoolRegistration{
+		Name:              domain.OrderbookCosmWasmPoolImplementation,
+		Contract:          cosmwasmpool.ORDERBOOK_CONTRACT_NAME,
+		VersionConstraint: cosmwasmpool.ORDERBOOK_CONTRACT_VERSION_CONSTRAINT,
+		Factory:           newRoutableOrderbookPool,
+	})
+
+	MustRegisterRoutableCosmWasmPool(RoutableCosmWasmPoolRegistration{
+		Name:              domain.AstroportPCLCosmWasmPoolImplementation,
+		Contract:          cosmwasmpool.ASTROPORT_PCL_CONTRACT_NAME,
+		VersionConstraint: cosmwasmpool.ASTROPORT_PCL_CONTRACT_VERSION_CONSTRAINT,
+		Factory:           newRoutableAstroportPCLPool,
+	})
+
+	MustRegisterRoutableCosmWasmPool(RoutableCosmWasmPoolRegistration{
+		Name:    domain.GeneralizedCosmWasmPoolImplementation,
+		Factory: newRoutableGeneralizedCosmWasmPool,
+	})
+}
+
+// RegisterRoutableCosmWasmPool registers the given routable CosmWasm pool implementation
+// so that the config may map CosmWasm pool contracts to it by name.
+// Implementations outside of this package are expected to register themselves on init.
+// Returns error if the name is empty, if the factory is nil or if the name is already registered.
+func RegisterRoutableCosmWasmPool(registration RoutableCosmWasmPoolRegistration) error {
+	if registration.Name == "" {
+		return fmt.Errorf("routable cosmwasm pool implementation name must be non-empty")
+	}
+
+	if registration.Factory == nil {
+		return fmt.Errorf("routable cosmwasm pool implementation (%s) factory must be non-nil", registration.Name)
+	}
+
+	routableCosmWasmPoolRegistry.mu.Lock()
+	defer routableCosmWasmPoolRegistry.mu.Unlock()
+
+	if _, ok := routableCosmWasmPoolRegistry.registrations[registration.Name]; ok {
+		return fmt.Errorf("routable cosmwasm pool implementation (%s) is already registered", registration.Name)
+	}
+
+	routableCosmWasmPoolRegistry.registrations[registration.Name] = registration
+
+	return nil
+}
+
+// MustRegisterRoutableCosmWasmPool registers the given routable CosmWasm pool implementation.
+// Panics if the registration fails. See RegisterRoutableCosmWasmPool for details.
+func MustRegisterRoutableCosmWasmPool(registration RoutableCosmWasmPoolRegistration) {
+	if err := RegisterRoutableCosmWasmPool(registration); err != nil {
+		panic(err)
+	}
+}
+
+// GetRoutableCosmWasmPoolRegistration returns the routable CosmWasm pool implementation registered under the given name.
+// Returns false if no implementation is registered under the name.
+func GetRoutableCosmWasmPoolRegistration(name string) (RoutableCosmWasmPoolRegistration, bool) {
+	routableCosmWasmPoolRegistry.mu.RLock()
+	defer routableCosmWasmPoolRegistry.mu.RUnlock()
+
+	registration, ok := routableCosmWasmPoolRegistry.registrations[name]
+	return registration, ok
+}
+
+// NewCosmWasmPoolImplementations resolves the given config entries against the registered implementations.
+// The contract name and the version constraint of an entry default to the ones registered with its implementation.
+// Returns error if an entry refers to an implementation that is not registered, if it has no contract name
+// or if its version constraint is invalid.
+func NewCosmWasmPoolImplementations(configs []domain.CosmWasmPoolImplementationConfig) ([]domain.CosmWasmPoolImplementation, error) {
+	implementations := make([]domain.CosmWasmPoolImplementation, 0, len(configs))
+	for _, config := range configs {
+		registration, ok := GetRoutableCosmWasmPoolRegistration(config.Implementation)
+		if !ok {
+			return nil, fmt.Errorf("routable cosmwasm pool implementation (%s) is not registered", config.Implementation)
+		}
+
+		contract := config.Contract
+		if contract == "" {
+			contract = registration.Contract
+		}
+
+		versionConstraint := config.VersionConstraint
+		if versionConstraint == "" {
+			versionConstraint = registration.VersionConstraint
+		}
+		if versionConstraint == "" {
+			versionConstraint = anyVersionConstraint
+		}
+
+		matcher, err := cosmwasmpool.NewContractMatcher(contract, versionConstraint)
+		if err != nil {
+			return nil, fmt.Errorf("routable cosmwasm pool implementation (%s): %w", config.Implementation, err)
+		}
+
+		implementations = append(implementations, domain.CosmWasmPoolImplementation{
+			Matcher: matcher,
+			Name:    config.Implementation,
+		})
+	}
+
+	return implementations, nil
+}
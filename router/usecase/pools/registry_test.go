@@ -0,0 +1,243 @@
+package pools_test
+
+import (
+	"testing"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	cwpoolmodel "github.com/osmosis-labs/osmosis/v25/x/cosmwasmpool/model"
+	poolmanagertypes "github.com/osmosis-labs/osmosis/v25/x/poolmanager/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osmosis-labs/sqs/domain"
+	cosmwasmdomain "github.com/osmosis-labs/sqs/domain/cosmwasm"
+	"github.com/osmosis-labs/sqs/domain/mocks"
+	"github.com/osmosis-labs/sqs/router/usecase/pools"
+	"github.com/osmosis-labs/sqs/sqsdomain"
+	"github.com/osmosis-labs/sqs/sqsdomain/cosmwasmpool"
+)
+
+const (
+	testRegistryImplementation = "test-registry-pool"
+	testRegistryContract       = "crates.io:test-registry-pool"
+)
+
+// testRegistryFactory constructs a mock routable pool with the ID of the given pool.
+func testRegistryFactory(pool sqsdomain.PoolI, cosmwasmPool *cwpoolmodel.CosmWasmPool, tokenOutDenom string, takerFee osmomath.Dec, _ cosmwasmdomain.CosmWasmPoolsParams) (domain.RoutablePool, error) {
+	return &mocks.MockRoutablePool{
+		ID:            cosmwasmPool.PoolId,
+		PoolType:      poolmanagertypes.CosmWasm,
+		TokenOutDenom: tokenOutDenom,
+		TakerFee:      takerFee,
+	}, nil
+}
+
+func init() {
+	pools.MustRegisterRoutableCosmWasmPool(pools.RoutableCosmWasmPoolRegistration{
+		Name:              testRegistryImplementation,
+		Contract:          testRegistryContract,
+		VersionConstraint: ">= 1.0.0",
+		Factory:           testRegistryFactory,
+	})
+}
+
+func TestRegisterRoutableCosmWasmPool(t *testing.T) {
+	tests := []struct {
+		name         string
+		registration pools.RoutableCosmWasmPoolRegistration
+		expectError  bool
+	}{
+		{
+			name: "valid registration",
+			registration: pools.RoutableCosmWasmPoolRegistration{
+				Name:    "test-valid-registration",
+				Factory: testRegistryFactory,
+			},
+		},
+		{
+			name: "error: empty name",
+			registration: pools.RoutableCosmWasmPoolRegistration{
+				Factory: testRegistryFactory,
+			},
+			expectError: true,
+		},
+		{
+			name: "error: nil factory",
+			registration: pools.RoutableCosmWasmPoolRegistration{
+				Name: "test-nil-factory",
+			},
+			expectError: true,
+		},
+		{
+			name: "error: already registered",
+			registration: pools.RoutableCosmWasmPoolRegistration{
+				Name:    domain.OrderbookCosmWasmPoolImplementation,
+				Factory: testRegistryFactory,
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := pools.RegisterRoutableCosmWasmPool(tt.registration)
+
+			if tt.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			registration, ok := pools.GetRoutableCosmWasmPoolRegistration(tt.registration.Name)
+			require.True(t, ok)
+			require.Equal(t, tt.registration.Name, registration.Name)
+		})
+	}
+}
+
+func TestNewCosmWasmPoolImplementations(t *testing.T) {
+	tests := []struct {
+		name    string
+		configs []domain.CosmWasmPoolImplementationConfig
+
+		// contract infos expected to match each of the resolved implementations.
+		expectedMatches    []cosmwasmpool.ContractInfo
+		expectedMismatches []cosmwasmpool.ContractInfo
+		expectError        bool
+	}{
+		{
+			name: "defaults to the registered contract and version constraint",
+			configs: []domain.CosmWasmPoolImplementationConfig{
+				{Implementation: domain.OrderbookCosmWasmPoolImplementation},
+			},
+			expectedMatches: []cosmwasmpool.ContractInfo{
+				{Contract: cosmwasmpool.ORDERBOOK_CONTRACT_NAME, Version: cosmwasmpool.ORDERBOOK_MIN_CONTRACT_VERSION},
+			},
+			expectedMismatches: []cosmwasmpool.ContractInfo{
+				{Contract: cosmwasmpool.ALLOY_TRANSMUTER_CONTRACT_NAME, Version: cosmwasmpool.ALLOY_TRANSMUTER_MIN_CONTRACT_VERSION},
+			},
+		},
+		{
+			name: "overrides the registered contract and version constraint",
+			configs: []domain.CosmWasmPoolImplementationConfig{
+				{Implementation: domain.GeneralizedCosmWasmPoolImplementation, Contract: "crates.io:custom-pool", VersionConstraint: "~1.2"},
+			},
+			expectedMatches: []cosmwasmpool.ContractInfo{
+				{Contract: "crates.io:custom-pool", Version: "1.2.5"},
+			},
+			expectedMismatches: []cosmwasmpool.ContractInfo{
+				{Contract: "crates.io:custom-pool", Version: "1.3.0"},
+				{Contract: "crates.io:other-pool", Version: "1.2.5"},
+			},
+		},
+		{
+			name: "matches any version without a version constraint",
+			configs: []domain.CosmWasmPoolImplementationConfig{
+				{Implementation: domain.GeneralizedCosmWasmPoolImplementation, Contract: "crates.io:custom-pool"},
+			},
+			expectedMatches: []cosmwasmpool.ContractInfo{
+				{Contract: "crates.io:custom-pool", Version: "0.1.0"},
+				{Contract: "crates.io:custom-pool", Version: "12.0.0"},
+			},
+		},
+		{
+			name: "error: implementation is not registered",
+			configs: []domain.CosmWasmPoolImplementationConfig{
+				{Implementation: "unregistered"},
+			},
+			expectError: true,
+		},
+		{
+			name: "error: no contract name",
+			configs: []domain.CosmWasmPoolImplementationConfig{
+				{Implementation: domain.GeneralizedCosmWasmPoolImplementation},
+			},
+			expectError: true,
+		},
+		{
+			name: "error: invalid version constraint",
+			configs: []domain.CosmWasmPoolImplementationConfig{
+				{Implementation: domain.OrderbookCosmWasmPoolImplementation, VersionConstraint: "not a constraint"},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			implementations, err := pools.NewCosmWasmPoolImplementations(tt.configs)
+
+			if tt.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, implementations, len(tt.configs))
+
+			config := domain.CosmWasmPoolRouterConfig{
+				Implementations: implementations,
+			}
+
+			for _, contractInfo := range tt.expectedMatches {
+				name, ok := config.GetImplementation(&cosmwasmpool.CosmWasmPoolModel{ContractInfo: contractInfo})
+				require.True(t, ok, contractInfo)
+				require.Equal(t, tt.configs[0].Implementation, name)
+			}
+
+			for _, contractInfo := range tt.expectedMismatches {
+				_, ok := config.GetImplementation(&cosmwasmpool.CosmWasmPoolModel{ContractInfo: contractInfo})
+				require.False(t, ok, contractInfo)
+			}
+		})
+	}
+}
+
+// Tests that the pools matched by the configured implementations are constructed by the registered factories
+// regardless of their code ID.
+func TestNewRoutablePool_RegisteredImplementation(t *testing.T) {
+	const (
+		poolID        = uint64(1500)
+		unknownCodeID = uint64(9999)
+		tokenOutDenom = "uosmo"
+	)
+
+	takerFee := osmomath.NewDecWithPrec(1, 3)
+
+	implementations, err := pools.NewCosmWasmPoolImplementations([]domain.CosmWasmPoolImplementationConfig{
+		{Implementation: testRegistryImplementation},
+	})
+	require.NoError(t, err)
+
+	newPool := func(version string) sqsdomain.PoolI {
+		return &mocks.MockRoutablePool{
+			ID:             poolID,
+			PoolType:       poolmanagertypes.CosmWasm,
+			ChainPoolModel: &cwpoolmodel.CosmWasmPool{PoolId: poolID, CodeId: unknownCodeID},
+			CosmWasmPoolModel: &cosmwasmpool.CosmWasmPoolModel{
+				ContractInfo: cosmwasmpool.ContractInfo{
+					Contract: testRegistryContract,
+					Version:  version,
+				},
+			},
+		}
+	}
+
+	cosmWasmPoolsParams := cosmwasmdomain.CosmWasmPoolsParams{
+		Config: domain.CosmWasmPoolRouterConfig{
+			Implementations: implementations,
+		},
+	}
+
+	// System under test
+	routablePool, err := pools.NewRoutablePool(newPool("1.0.0"), tokenOutDenom, takerFee, cosmWasmPoolsParams)
+	require.NoError(t, err)
+	require.Equal(t, &mocks.MockRoutablePool{
+		ID:            poolID,
+		PoolType:      poolmanagertypes.CosmWasm,
+		TokenOutDenom: tokenOutDenom,
+		TakerFee:      takerFee,
+	}, routablePool)
+
+	// The version constraint is not satisfied so the pool falls back to the code ID lookup.
+	_, err = pools.NewRoutablePool(newPool("0.9.0"), tokenOutDenom, takerFee, cosmWasmPoolsParams)
+	require.Equal(t, domain.UnsupportedCosmWasmPoolError{PoolId: poolID}, err)
+}
@@ -35,6 +35,16 @@ func (r *routableBalancerPoolImpl) CalculateTokenOutByTokenIn(ctx context.Contex
 	return tokenOut, nil
 }
 
+// CalculateTokenInByTokenOut implements RoutablePool.
+func (r *routableBalancerPoolImpl) CalculateTokenInByTokenOut(ctx context.Context, tokenOut sdk.Coin) (sdk.Coin, error) {
+	tokenIn, err := r.ChainPool.CalcInAmtGivenOut(sdk.Context{}, sdk.Coins{tokenOut}, r.TokenInDenom, r.GetSpreadFactor())
+	if err != nil {
+		return sdk.Coin{}, err
+	}
+
+	return tokenIn, nil
+}
+
 // GetTokenOutDenom implements RoutablePool.
 func (r *routableBalancerPoolImpl) GetTokenOutDenom() string {
 	return r.TokenOutDenom
@@ -57,6 +67,13 @@ func (r *routableBalancerPoolImpl) ChargeTakerFeeExactIn(tokenIn sdk.Coin) (toke
 	return tokenInAfterTakerFee
 }
 
+// ChargeTakerFeeExactOut implements domain.RoutablePool.
+// Returns the token in amount that must be provided for the given token in to remain after the taker fee is charged.
+func (r *routableBalancerPoolImpl) ChargeTakerFeeExactOut(tokenIn sdk.Coin) (tokenInAfterFee sdk.Coin) {
+	tokenInAfterTakerFee, _ := poolmanager.CalcTakerFeeExactOut(tokenIn, r.TakerFee)
+	return tokenInAfterTakerFee
+}
+
 // GetTakerFee implements domain.RoutablePool.
 func (r *routableBalancerPoolImpl) GetTakerFee() math.LegacyDec {
 	return r.TakerFee
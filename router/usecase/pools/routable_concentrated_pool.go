@@ -21,6 +21,7 @@ import (
 )
 
 var _ domain.RoutablePool = &routableConcentratedPoolImpl{}
+var _ domain.TickCrossingEstimator = &routableConcentratedPoolImpl{}
 var smallestDec = osmomath.BigDecFromDec(osmomath.SmallestDec())
 
 type routableConcentratedPoolImpl struct {
@@ -83,43 +84,32 @@ func (r *routableConcentratedPoolImpl) GetTakerFee() math.LegacyDec {
 // - the current sqrt price is zero
 // - rans out of ticks during swap (token in is too high for liquidity in the pool)
 func (r *routableConcentratedPoolImpl) CalculateTokenOutByTokenIn(ctx context.Context, tokenIn sdk.Coin) (sdk.Coin, error) {
-	concentratedPool := r.ChainPool
-	tickModel := r.TickModel
-
-	if tickModel == nil {
-		return sdk.Coin{}, domain.ConcentratedPoolNoTickModelError{
-			PoolId: r.ChainPool.Id,
-		}
-	}
+	tokenOut, _, err := r.swapOutGivenIn(tokenIn)
+	return tokenOut, err
+}
 
-	// Ensure pool has liquidity.
-	if tickModel.HasNoLiquidity {
-		return sdk.Coin{}, domain.ConcentratedNoLiquidityError{
-			PoolId: concentratedPool.Id,
-		}
+// EstimateTicksCrossed implements domain.TickCrossingEstimator.
+// It returns the number of initialized ticks crossed when swapping the given token in.
+// Fails under the same conditions as CalculateTokenOutByTokenIn.
+func (r *routableConcentratedPoolImpl) EstimateTicksCrossed(ctx context.Context, tokenIn sdk.Coin) (int, error) {
+	_, bucketsTraversed, err := r.swapOutGivenIn(tokenIn)
+	if err != nil {
+		return 0, err
 	}
 
-	// Ensure that the current bucket is within the available bucket range.
-	currentBucketIndex := tickModel.CurrentTickIndex
-
-	if currentBucketIndex < 0 || currentBucketIndex >= int64(len(tickModel.Ticks)) {
-		return sdk.Coin{}, domain.ConcentratedCurrentTickNotWithinBucketError{
-			PoolId:             concentratedPool.Id,
-			CurrentBucketIndex: currentBucketIndex,
-			TotalBuckets:       int64(len(tickModel.Ticks)),
-		}
-	}
+	// The swap ends within the last traversed bucket.
+	return max(bucketsTraversed-1, 0), nil
+}
 
-	currentBucket := tickModel.Ticks[currentBucketIndex]
+// swapOutGivenIn computes the out given in swap over the tick model buckets.
+// Returns the token out and the number of buckets traversed by the swap.
+func (r *routableConcentratedPoolImpl) swapOutGivenIn(tokenIn sdk.Coin) (sdk.Coin, int, error) {
+	concentratedPool := r.ChainPool
+	tickModel := r.TickModel
 
-	isCurrentTickWithinBucket := concentratedPool.IsCurrentTickInRange(currentBucket.LowerTick, currentBucket.UpperTick)
-	if !isCurrentTickWithinBucket {
-		return sdk.Coin{}, domain.ConcentratedCurrentTickAndBucketMismatchError{
-			PoolID:      concentratedPool.Id,
-			CurrentTick: concentratedPool.CurrentTick,
-			LowerTick:   currentBucket.LowerTick,
-			UpperTick:   currentBucket.UpperTick,
-		}
+	currentBucketIndex, err := r.validateCurrentBucket()
+	if err != nil {
+		return sdk.Coin{}, 0, err
 	}
 
 	// Set the appropriate token out denom.
@@ -138,10 +128,12 @@ func (r *routableConcentratedPoolImpl) CalculateTokenOutByTokenIn(ctx context.Co
 
 		amountRemainingIn = tokenIn.Amount.ToLegacyDec()
 		amountOutTotal    = osmomath.ZeroDec()
+
+		bucketsTraversed = 0
 	)
 
 	if currentSqrtPrice.IsZero() {
-		return sdk.Coin{}, domain.ConcentratedZeroCurrentSqrtPriceError{
+		return sdk.Coin{}, 0, domain.ConcentratedZeroCurrentSqrtPriceError{
 			PoolId: concentratedPool.Id,
 		}
 	}
@@ -151,13 +143,14 @@ func (r *routableConcentratedPoolImpl) CalculateTokenOutByTokenIn(ctx context.Co
 		if currentBucketIndex >= int64(len(tickModel.Ticks)) || currentBucketIndex < 0 {
 			// This happens when there is not enough liquidity in the pool to complete the swap
 			// for a given amount of token in.
-			return sdk.Coin{}, domain.ConcentratedNotEnoughLiquidityToCompleteSwapError{
+			return sdk.Coin{}, 0, domain.ConcentratedNotEnoughLiquidityToCompleteSwapError{
 				PoolId:   concentratedPool.Id,
 				AmountIn: sdk.NewCoins(tokenIn).String(),
 			}
 		}
 
-		currentBucket = tickModel.Ticks[currentBucketIndex]
+		currentBucket := tickModel.Ticks[currentBucketIndex]
+		bucketsTraversed++
 
 		// Compute the next initialized tick index depending on the swap direction.
 		// Zero for one - in the lower tick direction.
@@ -174,7 +167,7 @@ func (r *routableConcentratedPoolImpl) CalculateTokenOutByTokenIn(ctx context.Co
 		// Get the sqrt price for the next initialized tick index.
 		sqrtPriceTarget, err := getTickToSqrtPrice(nextInitializedTickIndex)
 		if err != nil {
-			return sdk.Coin{}, err
+			return sdk.Coin{}, 0, err
 		}
 
 		// Compute the swap within current bucket
@@ -190,7 +183,143 @@ func (r *routableConcentratedPoolImpl) CalculateTokenOutByTokenIn(ctx context.Co
 
 	// Return the total amount out.
 
-	return sdk.Coin{Denom: tokenOutDenom, Amount: amountOutTotal.TruncateInt()}, nil
+	return sdk.Coin{Denom: tokenOutDenom, Amount: amountOutTotal.TruncateInt()}, bucketsTraversed, nil
+}
+
+// CalculateTokenInByTokenOut implements domain.RoutablePool.
+// It calculates the amount of token in required to receive the given token out for a concentrated liquidity pool.
+// The ticks are traversed in the same direction as for the out given in swap with the same token in denom.
+// Fails if:
+// - the underlying chain pool set on the routable pool is not of concentrated type
+// - fails to retrieve the tick model for the pool
+// - the current tick is not within the specified current bucket range
+// - tick model has no liquidity flag set
+// - the current sqrt price is zero
+// - rans out of ticks during swap (token out is too high for liquidity in the pool)
+func (r *routableConcentratedPoolImpl) CalculateTokenInByTokenOut(ctx context.Context, tokenOut sdk.Coin) (sdk.Coin, error) {
+	concentratedPool := r.ChainPool
+	tickModel := r.TickModel
+
+	currentBucketIndex, err := r.validateCurrentBucket()
+	if err != nil {
+		return sdk.Coin{}, err
+	}
+
+	// Set the appropriate token in denom.
+	// Swapping token zero in for token one out moves the price down.
+	isZeroForOne := tokenOut.Denom == concentratedPool.Token1
+	tokenInDenom := concentratedPool.Token1
+	if isZeroForOne {
+		tokenInDenom = concentratedPool.Token0
+	}
+
+	// Initialize the swap strategy.
+	swapStrategy := swapstrategy.New(isZeroForOne, smallestDec, &storetypes.KVStoreKey{}, concentratedPool.SpreadFactor)
+
+	var (
+		// Swap state
+		currentSqrtPrice = concentratedPool.GetCurrentSqrtPrice()
+
+		amountRemainingOut = tokenOut.Amount.ToLegacyDec()
+		amountInTotal      = osmomath.ZeroDec()
+	)
+
+	if currentSqrtPrice.IsZero() {
+		return sdk.Coin{}, domain.ConcentratedZeroCurrentSqrtPriceError{
+			PoolId: concentratedPool.Id,
+		}
+	}
+
+	// Compute swap over all buckets.
+	for amountRemainingOut.IsPositive() {
+		if currentBucketIndex >= int64(len(tickModel.Ticks)) || currentBucketIndex < 0 {
+			// This happens when there is not enough liquidity in the pool to complete the swap
+			// for a given amount of token out.
+			return sdk.Coin{}, domain.ConcentratedNotEnoughLiquidityToCompleteSwapError{
+				PoolId:   concentratedPool.Id,
+				AmountIn: sdk.NewCoins(tokenOut).String(),
+			}
+		}
+
+		currentBucket := tickModel.Ticks[currentBucketIndex]
+
+		// Compute the next initialized tick index depending on the swap direction.
+		// Zero for one - in the lower tick direction.
+		// One for zero - in the upper tick direction.
+		var nextInitializedTickIndex int64
+		if isZeroForOne {
+			nextInitializedTickIndex = currentBucket.LowerTick
+			currentBucketIndex--
+		} else {
+			nextInitializedTickIndex = currentBucket.UpperTick
+			currentBucketIndex++
+		}
+
+		// Get the sqrt price for the next initialized tick index.
+		sqrtPriceTarget, err := getTickToSqrtPrice(nextInitializedTickIndex)
+		if err != nil {
+			return sdk.Coin{}, err
+		}
+
+		// Compute the swap within current bucket
+		sqrtPriceNext, amountOutConsumed, amountInComputed, spreadRewardChargeTotal := swapStrategy.ComputeSwapWithinBucketInGivenOut(currentSqrtPrice, sqrtPriceTarget, currentBucket.LiquidityAmount, amountRemainingOut)
+
+		// Update swap state for next iteration
+		amountRemainingOut = amountRemainingOut.SubMut(amountOutConsumed)
+		amountInTotal = amountInTotal.AddMut(amountInComputed).AddMut(spreadRewardChargeTotal)
+
+		// Update current sqrt price
+		currentSqrtPrice = sqrtPriceNext
+	}
+
+	// Return the total amount in, rounding up in favor of the pool.
+	return sdk.Coin{Denom: tokenInDenom, Amount: amountInTotal.Ceil().TruncateInt()}, nil
+}
+
+// validateCurrentBucket validates that the pool has a tick model with liquidity
+// and that the current tick is within the current bucket.
+// Returns the current bucket index on success.
+func (r *routableConcentratedPoolImpl) validateCurrentBucket() (int64, error) {
+	concentratedPool := r.ChainPool
+	tickModel := r.TickModel
+
+	if tickModel == nil {
+		return 0, domain.ConcentratedPoolNoTickModelError{
+			PoolId: r.ChainPool.Id,
+		}
+	}
+
+	// Ensure pool has liquidity.
+	if tickModel.HasNoLiquidity {
+		return 0, domain.ConcentratedNoLiquidityError{
+			PoolId: concentratedPool.Id,
+		}
+	}
+
+	// Ensure that the current bucket is within the available bucket range.
+	currentBucketIndex := tickModel.CurrentTickIndex
+
+	if currentBucketIndex < 0 || currentBucketIndex >= int64(len(tickModel.Ticks)) {
+		return 0, domain.ConcentratedCurrentTickNotWithinBucketError{
+			PoolId:             concentratedPool.Id,
+			CurrentBucketIndex: currentBucketIndex,
+			TotalBuckets:       int64(len(tickModel.Ticks)),
+		}
+	}
+
+	currentBucket := tickModel.Ticks[currentBucketIndex]
+
+	isCurrentTickWithinBucket := concentratedPool.IsCurrentTickInRange(currentBucket.LowerTick, currentBucket.UpperTick)
+	if !isCurrentTickWithinBucket {
+		return 0, domain.ConcentratedCurrentTickAndBucketMismatchError{
+			PoolID:      concentratedPool.Id,
+			CurrentTick: concentratedPool.CurrentTick,
+			LowerTick:   currentBucket.LowerTick,
+			UpperTick:   currentBucket.UpperTick,
+		}
+	}
+
+	return currentBucketIndex, nil
 }
 
 // GetTokenOutDenom implements RoutablePool.
@@ -216,6 +345,13 @@ func (r *routableConcentratedPoolImpl) ChargeTakerFeeExactIn(tokenIn sdk.Coin) (
 	return tokenInAfterTakerFee
 }
 
+// ChargeTakerFeeExactOut implements domain.RoutablePool.
+// Returns the token in amount that must be provided for the given token in to remain after the taker fee is charged.
+func (r *routableConcentratedPoolImpl) ChargeTakerFeeExactOut(tokenIn sdk.Coin) (tokenInAfterFee sdk.Coin) {
+	tokenInAfterTakerFee, _ := poolmanager.CalcTakerFeeExactOut(tokenIn, r.GetTakerFee())
+	return tokenInAfterTakerFee
+}
+
 // SetTokenInDenom implements domain.RoutablePool.
 func (r *routableConcentratedPoolImpl) SetTokenInDenom(tokenInDenom string) {
 	r.TokenInDenom = tokenInDenom
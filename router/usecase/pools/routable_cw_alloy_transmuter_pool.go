@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"cosmossdk.io/math"
 	sdk "github.com/cosmos/cosmos-sdk/types"
@@ -64,6 +65,7 @@ func (r *routableAlloyTransmuterPoolImpl) GetSpreadFactor() math.LegacyDec {
 // - the underlying chain pool set on the routable pool is not of transmuter type
 // - the token in amount is greater than the balance of the token in
 // - the token in amount is greater than the balance of the token out
+// - the token in amount exceeds the static or the change rate limiter upper limit
 //
 // Note that balance validation does not apply to alloyed asset since it can be minted or burned by the pool.
 func (r *routableAlloyTransmuterPoolImpl) CalculateTokenOutByTokenIn(ctx context.Context, tokenIn sdk.Coin) (sdk.Coin, error) {
@@ -84,6 +86,31 @@ func (r *routableAlloyTransmuterPoolImpl) CalculateTokenOutByTokenIn(ctx context
 	return sdk.Coin{Denom: r.TokenOutDenom, Amount: tokenOutAmtInt}, nil
 }
 
+// CalculateTokenInByTokenOut implements domain.RoutablePool.
+// It calculates the amount of token in required to receive the given token out for a transmuter pool.
+// The ratio of token in to token out is dependent on the normalization factor.
+// The token in amount is rounded up.
+// Returns error if:
+// - the token out amount is greater than the balance of the token out
+// - the token in amount exceeds the static or the change rate limiter upper limit
+//
+// Note that balance validation does not apply to alloyed asset since it can be minted or burned by the pool.
+func (r *routableAlloyTransmuterPoolImpl) CalculateTokenInByTokenOut(ctx context.Context, tokenOut sdk.Coin) (sdk.Coin, error) {
+	// Validate token out balance if not alloyed
+	if tokenOut.Denom != r.AlloyTransmuterData.AlloyedDenom {
+		if err := validateTransmuterBalance(tokenOut.Amount, r.Balances, tokenOut.Denom); err != nil {
+			return sdk.Coin{}, err
+		}
+	}
+
+	tokenInAmt, err := r.CalcTokenInAmt(tokenOut, r.TokenInDenom)
+	if err != nil {
+		return sdk.Coin{}, err
+	}
+
+	return sdk.Coin{Denom: r.TokenInDenom, Amount: tokenInAmt.Dec().Ceil().TruncateInt()}, nil
+}
+
 // GetTokenOutDenom implements RoutablePool.
 func (r *routableAlloyTransmuterPoolImpl) GetTokenOutDenom() string {
 	return r.TokenOutDenom
@@ -106,6 +133,13 @@ func (r *routableAlloyTransmuterPoolImpl) ChargeTakerFeeExactIn(tokenIn sdk.Coin
 	return tokenInAfterTakerFee
 }
 
+// ChargeTakerFeeExactOut implements domain.RoutablePool.
+// Returns the token in amount that must be provided for the given token in to remain after the taker fee is charged.
+func (r *routableAlloyTransmuterPoolImpl) ChargeTakerFeeExactOut(tokenIn sdk.Coin) (tokenInAfterFee sdk.Coin) {
+	tokenInAfterTakerFee, _ := poolmanager.CalcTakerFeeExactOut(tokenIn, r.GetTakerFee())
+	return tokenInAfterTakerFee
+}
+
 // GetTakerFee implements domain.RoutablePool.
 func (r *routableAlloyTransmuterPoolImpl) GetTakerFee() math.LegacyDec {
 	return r.TakerFee
@@ -186,9 +220,9 @@ func (r *routableAlloyTransmuterPoolImpl) CalcTokenOutAmt(tokenIn sdk.Coin, toke
 		return osmomath.BigDec{}, domain.ZeroNormalizationFactorError{Denom: tokenOutDenom, PoolId: r.GetId()}
 	}
 
-	// Check static upper rate limiter
-	// We only need to check it for the token in coin since that is the only one that is increased by the current quote.
-	if err := r.checkStaticRateLimiter(tokenIn); err != nil {
+	// Check the rate limiters
+	// We only need to check them for the token in coin since that is the only one that is increased by the current quote.
+	if err := r.checkRateLimiters(tokenIn); err != nil {
 		return osmomath.BigDec{}, err
 	}
 
@@ -202,6 +236,52 @@ func (r *routableAlloyTransmuterPoolImpl) CalcTokenOutAmt(tokenIn sdk.Coin, toke
 	return tokenOutAmount, nil
 }
 
+// Calculate the token in amount based on the normalization factors:
+//
+// token_in_amt = token_out_amt * token_in_norm_factor / token_out_norm_factor
+//
+// The result is rounded up so that the token in is sufficient to receive the token out.
+func (r *routableAlloyTransmuterPoolImpl) CalcTokenInAmt(tokenOut sdk.Coin, tokenInDenom string) (osmomath.BigDec, error) {
+	tokenInNormFactor, tokenOutNormFactor, err := r.FindNormalizationFactors(tokenInDenom, tokenOut.Denom)
+	if err != nil {
+		return osmomath.BigDec{}, err
+	}
+
+	if tokenInNormFactor.IsZero() {
+		return osmomath.BigDec{}, domain.ZeroNormalizationFactorError{Denom: tokenInDenom, PoolId: r.GetId()}
+	}
+
+	if tokenOutNormFactor.IsZero() {
+		return osmomath.BigDec{}, domain.ZeroNormalizationFactorError{Denom: tokenOut.Denom, PoolId: r.GetId()}
+	}
+
+	tokenOutAmount := osmomath.BigDecFromSDKInt(tokenOut.Amount)
+
+	tokenInNormFactorBig := osmomath.NewBigIntFromBigInt(tokenInNormFactor.BigInt())
+
+	tokenInAmount := tokenOutAmount.MulInt(tokenInNormFactorBig).QuoRoundUp(osmomath.BigDecFromSDKInt(tokenOutNormFactor))
+
+	// Check the rate limiters
+	// We only need to check them for the token in coin since that is the only one that is increased by the current quote.
+	if err := r.checkRateLimiters(sdk.Coin{Denom: tokenInDenom, Amount: tokenInAmount.Ceil().Dec().TruncateInt()}); err != nil {
+		return osmomath.BigDec{}, err
+	}
+
+	return tokenInAmount, nil
+}
+
+// checkRateLimiters checks the static and the change rate limiters for the token in coin.
+// The change rate limiter is checked at the block time ingested with the pool data so that the quotes
+// only depend on the ingested state. If the block time is not ingested, the change rate limiter is checked
+// at the latest update of its divisions.
+func (r *routableAlloyTransmuterPoolImpl) checkRateLimiters(tokenInCoin sdk.Coin) error {
+	if err := r.checkStaticRateLimiter(tokenInCoin); err != nil {
+		return err
+	}
+
+	return r.checkChangeRateLimiter(tokenInCoin, time.Unix(0, r.AlloyTransmuterData.BlockTime))
+}
+
 // checkStaticRateLimiter checks the static rate limiter for the token in coin.
 // Note: static rate limit only has an upper limit.
 // Therefore, we only need to validate the token in balance.
@@ -220,11 +300,69 @@ func (r *routableAlloyTransmuterPoolImpl) checkStaticRateLimiter(tokenInCoin sdk
 		return nil
 	}
 
-	preComputedData := r.AlloyTransmuterData.PreComputedData
-	normalizationFactors := preComputedData.NormalizationScalingFactors
+	// Token in weight
+	tokenInWeight, err := r.computeTokenInWeight(tokenInCoin)
+	if err != nil {
+		return err
+	}
+
+	// Validate upper limit
+	upperLimitInt := osmomath.MustNewDecFromStr(tokeInStaticLimiter.UpperLimit)
+
+	// Check the upper limit
+	if tokenInWeight.GT(upperLimitInt) {
+		return domain.StaticRateLimiterInvalidUpperLimitError{
+			UpperLimit: tokeInStaticLimiter.UpperLimit,
+			Weight:     tokenInWeight.String(),
+			Denom:      tokenInCoin.Denom,
+		}
+	}
+
+	return nil
+}
+
+// checkChangeRateLimiter checks the change rate limiter for the token in coin at the given block time.
+// The change rate limiter bounds the token in weight by its moving average over the window plus the boundary offset.
+// Like the static rate limiter, it only has an upper limit. Therefore, we only need to validate the token in balance.
+// No-op if the change rate limiter is not set for the token in denom or if it has no data points to average.
+// Returns error if the token in weight is greater than the upper limit or if the limiter data is invalid.
+func (r *routableAlloyTransmuterPoolImpl) checkChangeRateLimiter(tokenInCoin sdk.Coin, blockTime time.Time) error {
+	tokenInChangeLimiter, ok := r.AlloyTransmuterData.RateLimiterConfig.GetChangeLimiter(tokenInCoin.Denom)
+	if !ok {
+		return nil
+	}
+
+	upperLimit, ok, err := tokenInChangeLimiter.UpperLimit(blockTime.UnixNano())
+	if err != nil {
+		return fmt.Errorf("failed to compute change rate limiter upper limit for denom %s, pool id %d: %w", tokenInCoin.Denom, r.GetId(), err)
+	}
+	if !ok {
+		return nil
+	}
+
+	tokenInWeight, err := r.computeTokenInWeight(tokenInCoin)
+	if err != nil {
+		return err
+	}
+
+	if tokenInWeight.GT(upperLimit) {
+		return domain.ChangeRateLimiterInvalidUpperLimitError{
+			UpperLimit: upperLimit.String(),
+			Weight:     tokenInWeight.String(),
+			Denom:      tokenInCoin.Denom,
+		}
+	}
+
+	return nil
+}
+
+// computeTokenInWeight returns the weight of the token in denom among the normalized balances of the pool assets
+// after the token in coin is added to the pool. The alloyed LP share is excluded.
+// Returns error if the normalization scaling factor of any of the assets is not found.
+func (r *routableAlloyTransmuterPoolImpl) computeTokenInWeight(tokenInCoin sdk.Coin) (osmomath.Dec, error) {
+	normalizationFactors := r.AlloyTransmuterData.PreComputedData.NormalizationScalingFactors
 
-	// Note: -1 for the LP share.
-	normalizedBalances := make(map[string]osmomath.Int, len(r.AlloyTransmuterData.AssetConfigs)-1)
+	normalizedTokenInBalance := osmomath.ZeroInt()
 	normalizeTotal := osmomath.ZeroInt()
 
 	// Calculate normalized balances
@@ -246,49 +384,20 @@ func (r *routableAlloyTransmuterPoolImpl) checkStaticRateLimiter(tokenInCoin sdk
 
 		normalizationScalingFactor, ok := normalizationFactors[assetDenom]
 		if !ok {
-			return fmt.Errorf("normalization scaling factor not found for asset %s, pool id %d", assetDenom, r.GetId())
+			return osmomath.Dec{}, fmt.Errorf("normalization scaling factor not found for asset %s, pool id %d", assetDenom, r.GetId())
 		}
 
 		// Normalize balance
 		normalizedBalance := assetBalance.Mul(normalizationScalingFactor)
 
-		// Store normalized balance
-		normalizedBalances[assetDenom] = normalizedBalance
+		if assetDenom == tokenInCoin.Denom {
+			normalizedTokenInBalance = normalizedBalance
+		}
 
 		// Update total
 		normalizeTotal = normalizeTotal.Add(normalizedBalance)
 	}
 
-	// Calculate weights
-	// Note: -1 for the alloyed LP share.
-	weights := make(map[string]osmomath.Dec, len(r.AlloyTransmuterData.AssetConfigs)-1)
-	for i := 0; i < len(r.AlloyTransmuterData.AssetConfigs); i++ {
-		assetConfig := r.AlloyTransmuterData.AssetConfigs[i]
-		assetDenom := assetConfig.Denom
-
-		// Skip if the asset is alloyed LP hsare
-		if strings.Contains(assetDenom, alloyedLPShareDenomComponent) {
-			continue
-		}
-
-		// Calculate weight
-		weights[assetDenom] = normalizedBalances[assetDenom].ToLegacyDec().Quo(normalizeTotal.ToLegacyDec())
-	}
-
-	// Validate upper limit
-	upperLimitInt := osmomath.MustNewDecFromStr(tokeInStaticLimiter.UpperLimit)
-
-	// Token in weight
-	tokenInWeight := weights[tokenInCoin.Denom]
-
-	// Check the upper limit
-	if tokenInWeight.GT(upperLimitInt) {
-		return domain.StaticRateLimiterInvalidUpperLimitError{
-			UpperLimit: tokeInStaticLimiter.UpperLimit,
-			Weight:     tokenInWeight.String(),
-			Denom:      tokenInCoin.Denom,
-		}
-	}
-
-	return nil
+	// Calculate weight
+	return normalizedTokenInBalance.ToLegacyDec().Quo(normalizeTotal.ToLegacyDec()), nil
 }
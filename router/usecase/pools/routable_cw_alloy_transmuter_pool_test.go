@@ -2,6 +2,7 @@ package pools_test
 
 import (
 	"context"
+	"time"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 
@@ -423,3 +424,195 @@ func (s *RoutablePoolTestSuite) TestCheckStaticRateLimiter() {
 		})
 	}
 }
+
+func (s *RoutablePoolTestSuite) TestCheckChangeRateLimiter() {
+	defaultScalingFactors := map[string]osmomath.Int{
+		USDC:               osmomath.NewInt(1),
+		USDT:               osmomath.NewInt(1),
+		OVERLY_PRECISE_USD: osmomath.NewInt(1),
+		NO_PRECISION_USD:   osmomath.NewInt(1),
+	}
+
+	// The weight of USDC is 1/3 before the swap.
+	defaultInitialBalances := sdk.NewCoins(
+		sdk.NewCoin(USDC, osmomath.NewInt(1_000_000)),
+		sdk.NewCoin(USDT, osmomath.NewInt(2_000_000)),
+	)
+
+	blockTime := time.Unix(0, 1_000_000)
+
+	defaultWindowConfig := cosmwasmpool.WindowConfig{
+		WindowSize:    1000,
+		DivisionCount: 10,
+	}
+
+	// The moving average of the weight of USDC is 0.3333 for an upper limit of 0.4333.
+	defaultChangeLimiter := cosmwasmpool.ChangeLimiter{
+		Divisions: []cosmwasmpool.Division{
+			{StartedAt: blockTime.UnixNano() - 100, UpdatedAt: blockTime.UnixNano() - 100, LatestValue: "0.3333", Integral: "0"},
+		},
+		LatestValue:    "0.3333",
+		WindowConfig:   defaultWindowConfig,
+		BoundaryOffset: "0.1",
+	}
+
+	tests := map[string]struct {
+		tokenInCoin         sdk.Coin
+		changeLimiterConfig map[string]cosmwasmpool.ChangeLimiter
+		expectError         error
+		expectAnyError      bool
+	}{
+		"valid token in - below upper limit": {
+			// 1.1 / 3.1 = 0.3548
+			tokenInCoin:         sdk.NewCoin(USDC, osmomath.NewInt(100_000)),
+			changeLimiterConfig: map[string]cosmwasmpool.ChangeLimiter{USDC: defaultChangeLimiter},
+		},
+		"invalid token in - exceeds upper limit": {
+			// 1.6 / 3.6 = 0.4444
+			tokenInCoin:         sdk.NewCoin(USDC, osmomath.NewInt(600_000)),
+			changeLimiterConfig: map[string]cosmwasmpool.ChangeLimiter{USDC: defaultChangeLimiter},
+			expectError: domain.ChangeRateLimiterInvalidUpperLimitError{
+				Denom:      USDC,
+				UpperLimit: osmomath.MustNewDecFromStr("0.4333").String(),
+				Weight:     osmomath.MustNewDecFromStr("0.444444444444444444").String(),
+			},
+		},
+		"no change limiter configured": {
+			tokenInCoin:         sdk.NewCoin(USDC, osmomath.NewInt(600_000)),
+			changeLimiterConfig: map[string]cosmwasmpool.ChangeLimiter{},
+		},
+		"change limiter not set for token in denom": {
+			tokenInCoin:         sdk.NewCoin(USDC, osmomath.NewInt(600_000)),
+			changeLimiterConfig: map[string]cosmwasmpool.ChangeLimiter{USDT: defaultChangeLimiter},
+		},
+		"change limiter without data points": {
+			tokenInCoin: sdk.NewCoin(USDC, osmomath.NewInt(600_000)),
+			changeLimiterConfig: map[string]cosmwasmpool.ChangeLimiter{USDC: {
+				LatestValue:    "0.3333",
+				WindowConfig:   defaultWindowConfig,
+				BoundaryOffset: "0.1",
+			}},
+		},
+		"invalid window config": {
+			tokenInCoin: sdk.NewCoin(USDC, osmomath.NewInt(100_000)),
+			changeLimiterConfig: map[string]cosmwasmpool.ChangeLimiter{USDC: {
+				Divisions:      defaultChangeLimiter.Divisions,
+				BoundaryOffset: "0.1",
+			}},
+			expectAnyError: true,
+		},
+	}
+
+	for name, tc := range tests {
+		s.Run(name, func() {
+			s.Setup()
+			routablePool := s.SetupRoutableAlloyTransmuterPoolCustom(USDC, USDT, defaultInitialBalances, osmomath.ZeroDec(), cosmwasmpool.AlloyedRateLimiter{
+				ChangeLimiterByDenomMap: tc.changeLimiterConfig,
+			}, cosmwasmpool.PrecomputedData{
+				StdNormFactor:               osmomath.NewInt(1),
+				NormalizationScalingFactors: defaultScalingFactors,
+			})
+
+			r := routablePool.(*pools.RoutableAlloyTransmuterPoolImpl)
+
+			// System under test
+			err := r.CheckChangeRateLimiter(tc.tokenInCoin, blockTime)
+
+			switch {
+			case tc.expectError != nil:
+				s.Require().Error(err)
+				s.Require().ErrorIs(err, tc.expectError)
+			case tc.expectAnyError:
+				s.Require().Error(err)
+			default:
+				s.Require().NoError(err)
+			}
+		})
+	}
+}
+
+// Validates that the swaps exceeding the change rate limiter are rejected.
+// The block time is not ingested so the limiter is checked at the latest update of the only division
+// where the moving average is its latest value.
+func (s *RoutablePoolTestSuite) TestCalculateTokenOutByTokenIn_AlloyTransmuter_ChangeRateLimiter() {
+	routablePool := s.SetupRoutableAlloyTransmuterPoolCustom(USDC, USDT, sdk.NewCoins(
+		sdk.NewCoin(USDC, osmomath.NewInt(1_000_000)),
+		sdk.NewCoin(USDT, osmomath.NewInt(2_000_000)),
+	), osmomath.ZeroDec(), cosmwasmpool.AlloyedRateLimiter{
+		ChangeLimiterByDenomMap: map[string]cosmwasmpool.ChangeLimiter{
+			USDC: {
+				Divisions: []cosmwasmpool.Division{
+					{StartedAt: 0, UpdatedAt: 0, LatestValue: "0.3333", Integral: "0"},
+				},
+				LatestValue:    "0.3333",
+				WindowConfig:   cosmwasmpool.WindowConfig{WindowSize: 1000, DivisionCount: 10},
+				BoundaryOffset: "0.1",
+			},
+		},
+	}, cosmwasmpool.PrecomputedData{
+		StdNormFactor: osmomath.NewInt(1),
+		NormalizationScalingFactors: map[string]osmomath.Int{
+			USDC:               osmomath.NewInt(1),
+			USDT:               osmomath.NewInt(1),
+			OVERLY_PRECISE_USD: osmomath.NewInt(1),
+			NO_PRECISION_USD:   osmomath.NewInt(1),
+		},
+	})
+
+	// Below the upper limit
+	tokenOut, err := routablePool.CalculateTokenOutByTokenIn(context.TODO(), sdk.NewCoin(USDC, osmomath.NewInt(100_000)))
+	s.Require().NoError(err)
+	s.Require().Equal(sdk.NewCoin(USDT, osmomath.NewInt(1_000)), tokenOut)
+
+	// Above the upper limit
+	_, err = routablePool.CalculateTokenOutByTokenIn(context.TODO(), sdk.NewCoin(USDC, osmomath.NewInt(600_000)))
+	s.Require().ErrorAs(err, &domain.ChangeRateLimiterInvalidUpperLimitError{})
+}
+
+// Validates that the change rate limiter is checked at the block time ingested with the pool data
+// rather than at the current time.
+// The division size is 100. At the ingested block time, the window starts 1000 before it and spans both divisions:
+// - the first division started 900 before the block time with the value of 0.6
+// - the second division started 100 before the block time with the value of 0.3333
+// The moving average is (0.6 * 800 + 0.3333 * 100) / 900 = 0.5703 for an upper limit of 0.6703.
+// At the current time, both divisions are outdated and the upper limit is 0.3333 + 0.1 = 0.4333.
+func (s *RoutablePoolTestSuite) TestCalculateTokenOutByTokenIn_AlloyTransmuter_ChangeRateLimiterBlockTime() {
+	const blockTime = int64(1_000_000)
+
+	routablePool := s.SetupRoutableAlloyTransmuterPoolCustom(USDC, USDT, sdk.NewCoins(
+		sdk.NewCoin(USDC, osmomath.NewInt(1_000_000)),
+		sdk.NewCoin(USDT, osmomath.NewInt(2_000_000)),
+	), osmomath.ZeroDec(), cosmwasmpool.AlloyedRateLimiter{
+		ChangeLimiterByDenomMap: map[string]cosmwasmpool.ChangeLimiter{
+			USDC: {
+				Divisions: []cosmwasmpool.Division{
+					{StartedAt: blockTime - 900, UpdatedAt: blockTime - 900, LatestValue: "0.6", Integral: "0"},
+					{StartedAt: blockTime - 100, UpdatedAt: blockTime - 100, LatestValue: "0.3333", Integral: "0"},
+				},
+				LatestValue:    "0.3333",
+				WindowConfig:   cosmwasmpool.WindowConfig{WindowSize: 1000, DivisionCount: 10},
+				BoundaryOffset: "0.1",
+			},
+		},
+	}, cosmwasmpool.PrecomputedData{
+		StdNormFactor: osmomath.NewInt(1),
+		NormalizationScalingFactors: map[string]osmomath.Int{
+			USDC:               osmomath.NewInt(1),
+			USDT:               osmomath.NewInt(1),
+			OVERLY_PRECISE_USD: osmomath.NewInt(1),
+			NO_PRECISION_USD:   osmomath.NewInt(1),
+		},
+	})
+
+	r := routablePool.(*pools.RoutableAlloyTransmuterPoolImpl)
+	r.AlloyTransmuterData.BlockTime = blockTime
+
+	// 1.6 / 3.6 = 0.4444 is within the upper limit at the ingested block time.
+	tokenIn := sdk.NewCoin(USDC, osmomath.NewInt(600_000))
+	_, err := routablePool.CalculateTokenOutByTokenIn(context.TODO(), tokenIn)
+	s.Require().NoError(err)
+
+	// The same swap exceeds the upper limit at the current time.
+	err = r.CheckChangeRateLimiter(tokenIn, time.Now())
+	s.Require().ErrorAs(err, &domain.ChangeRateLimiterInvalidUpperLimitError{})
+}
@@ -0,0 +1,144 @@
+package pools
+
+import (
+	"context"
+	"fmt"
+
+	"cosmossdk.io/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/osmosis-labs/sqs/domain"
+	"github.com/osmosis-labs/sqs/sqsdomain/cosmwasmpool"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	cwpoolmodel "github.com/osmosis-labs/osmosis/v25/x/cosmwasmpool/model"
+	"github.com/osmosis-labs/osmosis/v25/x/poolmanager"
+	poolmanagertypes "github.com/osmosis-labs/osmosis/v25/x/poolmanager/types"
+)
+
+var _ domain.RoutablePool = &routableAstroportPCLPoolImpl{}
+
+// routableAstroportPCLPoolImpl is the routable pool of the Astroport PCL pools.
+// It solves the PCL invariant from the ingested or refreshed pool params instead of querying the contract.
+type routableAstroportPCLPoolImpl struct {
+	ChainPool        *cwpoolmodel.CosmWasmPool      "json:\"pool\""
+	AstroportPCLData *cosmwasmpool.AstroportPCLData "json:\"astroport_pcl_data\""
+	Balances         sdk.Coins                      "json:\"balances\""
+	TokenInDenom     string                         "json:\"token_in_denom,omitempty\""
+	TokenOutDenom    string                         "json:\"token_out_denom,omitempty\""
+	TakerFee         osmomath.Dec                   "json:\"taker_fee\""
+	SpreadFactor     osmomath.Dec                   "json:\"spread_factor\""
+}
+
+// GetId implements domain.RoutablePool.
+func (r *routableAstroportPCLPoolImpl) GetId() uint64 {
+	return r.ChainPool.PoolId
+}
+
+// GetPoolDenoms implements domain.RoutablePool.
+func (r *routableAstroportPCLPoolImpl) GetPoolDenoms() []string {
+	return r.AstroportPCLData.AssetDenoms
+}
+
+// GetType implements domain.RoutablePool.
+func (*routableAstroportPCLPoolImpl) GetType() poolmanagertypes.PoolType {
+	return poolmanagertypes.CosmWasm
+}
+
+// GetSpreadFactor implements domain.RoutablePool.
+func (r *routableAstroportPCLPoolImpl) GetSpreadFactor() math.LegacyDec {
+	return r.SpreadFactor
+}
+
+// CalculateTokenOutByTokenIn implements domain.RoutablePool.
+// It calculates the amount of token out given the amount of token in by solving the PCL invariant
+// the same way as the swap simulation of the contract. The fees of the pool are deducted from the amount out.
+// Returns error if:
+// - either denom is not in the pool
+// - the pool has no liquidity
+// - the invariant fails to solve
+func (r *routableAstroportPCLPoolImpl) CalculateTokenOutByTokenIn(ctx context.Context, tokenIn sdk.Coin) (sdk.Coin, error) {
+	tokenOutAmount, err := r.AstroportPCLData.CalcTokenOutAmt(r.Balances, tokenIn, r.TokenOutDenom)
+	if err != nil {
+		return sdk.Coin{}, err
+	}
+
+	return sdk.Coin{Denom: r.TokenOutDenom, Amount: tokenOutAmount}, nil
+}
+
+// CalculateTokenInByTokenOut implements domain.RoutablePool.
+// It calculates the amount of token in required to receive the given token out by solving the PCL invariant
+// the same way as the reverse swap simulation of the contract, which charges the out fee as the maximum fee rate.
+// Returns error if:
+// - either denom is not in the pool
+// - the token out before the fee exceeds the balance of the pool
+// - the invariant fails to solve
+func (r *routableAstroportPCLPoolImpl) CalculateTokenInByTokenOut(ctx context.Context, tokenOut sdk.Coin) (sdk.Coin, error) {
+	tokenInAmount, err := r.AstroportPCLData.CalcTokenInAmt(r.Balances, tokenOut, r.TokenInDenom)
+	if err != nil {
+		return sdk.Coin{}, err
+	}
+
+	return sdk.Coin{Denom: r.TokenInDenom, Amount: tokenInAmount}, nil
+}
+
+// GetTokenOutDenom implements RoutablePool.
+func (r *routableAstroportPCLPoolImpl) GetTokenOutDenom() string {
+	return r.TokenOutDenom
+}
+
+// GetTokenInDenom implements RoutablePool.
+func (r *routableAstroportPCLPoolImpl) GetTokenInDenom() string {
+	return r.TokenInDenom
+}
+
+// String implements domain.RoutablePool.
+func (r *routableAstroportPCLPoolImpl) String() string {
+	return fmt.Sprintf("pool (%d), pool type (%d) Astroport PCL, pool denoms (%v), token out (%s)", r.ChainPool.PoolId, poolmanagertypes.CosmWasm, r.GetPoolDenoms(), r.TokenOutDenom)
+}
+
+// ChargeTakerFeeExactIn implements domain.RoutablePool.
+// Returns tokenInAmount after the taker fee is charged.
+func (r *routableAstroportPCLPoolImpl) ChargeTakerFeeExactIn(tokenIn sdk.Coin) (inAmountAfterFee sdk.Coin) {
+	tokenInAfterTakerFee, _ := poolmanager.CalcTakerFeeExactIn(tokenIn, r.GetTakerFee())
+	return tokenInAfterTakerFee
+}
+
+// ChargeTakerFeeExactOut implements domain.RoutablePool.
+// Returns the token in amount that must be provided for the given token in to remain after the taker fee is charged.
+func (r *routableAstroportPCLPoolImpl) ChargeTakerFeeExactOut(tokenIn sdk.Coin) (tokenInAfterFee sdk.Coin) {
+	tokenInAfterTakerFee, _ := poolmanager.CalcTakerFeeExactOut(tokenIn, r.GetTakerFee())
+	return tokenInAfterTakerFee
+}
+
+// GetTakerFee implements domain.RoutablePool.
+func (r *routableAstroportPCLPoolImpl) GetTakerFee() math.LegacyDec {
+	return r.TakerFee
+}
+
+// SetTokenInDenom implements domain.RoutablePool.
+func (r *routableAstroportPCLPoolImpl) SetTokenInDenom(tokenInDenom string) {
+	r.TokenInDenom = tokenInDenom
+}
+
+// SetTokenOutDenom implements domain.RoutablePool.
+func (r *routableAstroportPCLPoolImpl) SetTokenOutDenom(tokenOutDenom string) {
+	r.TokenOutDenom = tokenOutDenom
+}
+
+// CalcSpotPrice implements domain.RoutablePool.
+// Contrary to the spot price query of the contract, which is a moving average of the past trades,
+// it returns the marginal price along the invariant at the current balances, excluding the fees.
+func (r *routableAstroportPCLPoolImpl) CalcSpotPrice(ctx context.Context, baseDenom string, quoteDenom string) (osmomath.BigDec, error) {
+	return r.AstroportPCLData.SpotPrice(r.Balances, baseDenom, quoteDenom)
+}
+
+// GetSQSType implements domain.RoutablePool.
+func (*routableAstroportPCLPoolImpl) GetSQSType() domain.SQSPoolType {
+	return domain.AstroportPCL
+}
+
+// GetCodeID implements domain.RoutablePool.
+func (r *routableAstroportPCLPoolImpl) GetCodeID() uint64 {
+	return r.ChainPool.CodeId
+}
@@ -0,0 +1,171 @@
+package pools_test
+
+import (
+	"context"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/osmosis-labs/osmosis/osmomath"
+	cwpoolmodel "github.com/osmosis-labs/osmosis/v25/x/cosmwasmpool/model"
+	poolmanagertypes "github.com/osmosis-labs/osmosis/v25/x/poolmanager/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/osmosis-labs/sqs/domain"
+	cosmwasmdomain "github.com/osmosis-labs/sqs/domain/cosmwasm"
+	"github.com/osmosis-labs/sqs/domain/mocks"
+	"github.com/osmosis-labs/sqs/router/usecase/pools"
+	"github.com/osmosis-labs/sqs/sqsdomain"
+	"github.com/osmosis-labs/sqs/sqsdomain/cosmwasmpool"
+)
+
+// Tests that the Astroport PCL pools are routed natively when enabled and their params are ingested,
+// fall back to the generalized CosmWasm pool otherwise and fail to construct with invalid params.
+func TestNewRoutablePool_AstroportPCL(t *testing.T) {
+	const (
+		poolID        = uint64(1600)
+		codeID        = uint64(773)
+		tokenInDenom  = "uusdc"
+		tokenOutDenom = "uosmo"
+	)
+
+	takerFee := osmomath.NewDecWithPrec(1, 3)
+
+	balances := sdk.NewCoins(
+		sdk.NewCoin(tokenInDenom, osmomath.NewInt(500_000_000_000)),
+		sdk.NewCoin(tokenOutDenom, osmomath.NewInt(1_000_000_000_000)),
+	)
+
+	validData := &cosmwasmpool.AstroportPCLData{
+		AssetDenoms:     []string{tokenOutDenom, tokenInDenom},
+		AssetPrecisions: []uint32{6, 6},
+		Amp:             osmomath.NewDec(10),
+		Gamma:           osmomath.MustNewDecFromStr("0.000145"),
+		MidFee:          osmomath.MustNewDecFromStr("0.0026"),
+		OutFee:          osmomath.MustNewDecFromStr("0.0045"),
+		FeeGamma:        osmomath.MustNewDecFromStr("0.00023"),
+		// 2 OSMO per USDC
+		PriceScale: osmomath.NewDec(2),
+	}
+
+	invalidData := *validData
+	invalidData.Gamma = osmomath.ZeroDec()
+
+	implementations, err := pools.NewCosmWasmPoolImplementations([]domain.CosmWasmPoolImplementationConfig{
+		{Implementation: domain.AstroportPCLCosmWasmPoolImplementation},
+	})
+	require.NoError(t, err)
+
+	newCosmWasmPoolsParams := func(enabled bool, storedData *cosmwasmpool.AstroportPCLData) cosmwasmdomain.CosmWasmPoolsParams {
+		store := cosmwasmdomain.NewAstroportPCLDataStore()
+		if storedData != nil {
+			store.Set(poolID, storedData)
+		}
+
+		return cosmwasmdomain.CosmWasmPoolsParams{
+			Config: domain.CosmWasmPoolRouterConfig{
+				Implementations:     implementations,
+				AstroportPCLEnabled: enabled,
+			},
+			ScalingFactorGetterCb: domain.UnsetScalingFactorGetterCb,
+			AstroportPCLDataStore: store,
+		}
+	}
+
+	newPool := func(data *cosmwasmpool.AstroportPCLData) sqsdomain.PoolI {
+		return &mocks.MockRoutablePool{
+			ID:             poolID,
+			PoolType:       poolmanagertypes.CosmWasm,
+			ChainPoolModel: &cwpoolmodel.CosmWasmPool{PoolId: poolID, CodeId: codeID},
+			CosmWasmPoolModel: cosmwasmpool.NewCWPoolModel(
+				cosmwasmpool.ASTROPORT_PCL_CONTRACT_NAME, "4.0.0",
+				cosmwasmpool.CosmWasmPoolData{AstroportPCL: data},
+			),
+			Balances: balances,
+		}
+	}
+
+	tests := map[string]struct {
+		data            *cosmwasmpool.AstroportPCLData
+		storedData      *cosmwasmpool.AstroportPCLData
+		disabled        bool
+		expectedSQSType domain.SQSPoolType
+		expectError     bool
+	}{
+		"native pool with the ingested params": {
+			data:            validData,
+			expectedSQSType: domain.AstroportPCL,
+		},
+		"native pool with the refreshed params": {
+			storedData:      validData,
+			expectedSQSType: domain.AstroportPCL,
+		},
+		"native pool with the ingested params taking precedence over the refreshed ones": {
+			data:            validData,
+			storedData:      &invalidData,
+			expectedSQSType: domain.AstroportPCL,
+		},
+		"generalized pool without the ingested or refreshed params": {
+			expectedSQSType: domain.GeneralizedCosmWasm,
+		},
+		"generalized pool with the native math disabled": {
+			data:            validData,
+			disabled:        true,
+			expectedSQSType: domain.GeneralizedCosmWasm,
+		},
+		"error: invalid params": {
+			data:        &invalidData,
+			expectError: true,
+		},
+		"error: invalid refreshed params": {
+			storedData:  &invalidData,
+			expectError: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			routablePool, err := pools.NewRoutablePool(newPool(tc.data), tokenOutDenom, takerFee, newCosmWasmPoolsParams(!tc.disabled, tc.storedData))
+			if tc.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			require.Equal(t, tc.expectedSQSType, routablePool.GetSQSType())
+			require.Equal(t, poolID, routablePool.GetId())
+			require.Equal(t, takerFee, routablePool.GetTakerFee())
+
+			if tc.expectedSQSType != domain.AstroportPCL {
+				return
+			}
+
+			tokenIn := sdk.NewCoin(tokenInDenom, osmomath.NewInt(1_000_000_000))
+
+			expectedData := tc.data
+			if expectedData == nil {
+				expectedData = tc.storedData
+			}
+
+			expectedAmountOut, err := expectedData.CalcTokenOutAmt(balances, tokenIn, tokenOutDenom)
+			require.NoError(t, err)
+
+			tokenOut, err := routablePool.CalculateTokenOutByTokenIn(context.TODO(), tokenIn)
+			require.NoError(t, err)
+			require.Equal(t, sdk.NewCoin(tokenOutDenom, expectedAmountOut), tokenOut)
+
+			// The pool is balanced at the price scale so the amount out is close to 2 OSMO per USDC net of the fees.
+			require.True(t, tokenOut.Amount.LT(osmomath.NewInt(2_000_000_000)))
+			require.True(t, tokenOut.Amount.GT(osmomath.NewInt(1_990_000_000)))
+
+			routablePool.SetTokenInDenom(tokenInDenom)
+			tokenInForOut, err := routablePool.CalculateTokenInByTokenOut(context.TODO(), tokenOut)
+			require.NoError(t, err)
+			require.Equal(t, tokenInDenom, tokenInForOut.Denom)
+			require.True(t, tokenInForOut.Amount.GTE(tokenIn.Amount))
+
+			spotPrice, err := routablePool.CalcSpotPrice(context.TODO(), tokenInDenom, tokenOutDenom)
+			require.NoError(t, err)
+			require.Equal(t, 0, osmomath.ErrTolerance{MultiplicativeTolerance: osmomath.NewDecWithPrec(1, 12)}.CompareBigDec(osmomath.NewBigDec(2), spotPrice), "got %s", spotPrice)
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 var oneBigDec = osmomath.OneBigDec()
 
 var _ domain.RoutablePool = &routableOrderbookPoolImpl{}
+var _ domain.TickCrossingEstimator = &routableOrderbookPoolImpl{}
 
 type routableOrderbookPoolImpl struct {
 	ChainPool     *cwpoolmodel.CosmWasmPool   "json:\"pool\""
@@ -61,29 +62,48 @@ func (r *routableOrderbookPoolImpl) GetSpreadFactor() math.LegacyDec {
 // - runs out of ticks during swap (token in is too high for liquidity in the pool)
 // - `TickToPrice` calculation fails
 func (r *routableOrderbookPoolImpl) CalculateTokenOutByTokenIn(ctx context.Context, tokenIn sdk.Coin) (sdk.Coin, error) {
+	tokenOut, _, err := r.swapOutGivenIn(tokenIn)
+	return tokenOut, err
+}
+
+// EstimateTicksCrossed implements domain.TickCrossingEstimator.
+// It returns the number of orderbook ticks walked when swapping the given token in.
+// Fails under the same conditions as CalculateTokenOutByTokenIn.
+func (r *routableOrderbookPoolImpl) EstimateTicksCrossed(ctx context.Context, tokenIn sdk.Coin) (int, error) {
+	_, ticksWalked, err := r.swapOutGivenIn(tokenIn)
+	if err != nil {
+		return 0, err
+	}
+
+	return ticksWalked, nil
+}
+
+// swapOutGivenIn walks the ticks on the "out" side of the orderbook.
+// Returns the token out and the number of ticks walked by the swap.
+func (r *routableOrderbookPoolImpl) swapOutGivenIn(tokenIn sdk.Coin) (sdk.Coin, int, error) {
 	poolType := r.GetType()
 
 	// Esnure that the pool is a cosmwasm pool
 	if poolType != poolmanagertypes.CosmWasm {
-		return sdk.Coin{}, domain.InvalidPoolTypeError{PoolType: int32(poolType)}
+		return sdk.Coin{}, 0, domain.InvalidPoolTypeError{PoolType: int32(poolType)}
 	}
 
 	// Get the expected order directionIn
 	directionIn, err := r.OrderbookData.GetDirection(tokenIn.Denom, r.TokenOutDenom)
 	if err != nil {
-		return sdk.Coin{}, err
+		return sdk.Coin{}, 0, err
 	}
 	directionOut := directionIn.Opposite()
 	iterationStep, err := directionOut.IterationStep()
 	if err != nil {
-		return sdk.Coin{}, err
+		return sdk.Coin{}, 0, err
 	}
 
 	// Get starting tick index for the "out" side of the orderbook
 	// Since the order will get the liquidity out from that side
 	tickIdx, err := r.OrderbookData.GetStartTickIndex(directionOut)
 	if err != nil {
-		return sdk.Coin{}, err
+		return sdk.Coin{}, 0, err
 	}
 
 	amountOutTotal := osmomath.ZeroBigDec()
@@ -98,23 +118,26 @@ func (r *routableOrderbookPoolImpl) CalculateTokenOutByTokenIn(ctx context.Conte
 
 	// check if amount in > amountInToExhaustLiquidity, if so this swap is not possible due to insufficient liquidity
 	if amountInRemaining.GT(amountInToExhaustLiquidity) {
-		return sdk.Coin{}, domain.OrderbookNotEnoughLiquidityToCompleteSwapError{PoolId: r.GetId(), AmountIn: tokenIn.String()}
+		return sdk.Coin{}, 0, domain.OrderbookNotEnoughLiquidityToCompleteSwapError{PoolId: r.GetId(), AmountIn: tokenIn.String()}
 	}
 
+	ticksWalked := 0
+
 	// ASSUMPTION: Ticks are ordered
 	for amountInRemaining.GT(smallestDec) {
 		// Order has run out of ticks to iterate
 		if tickIdx >= len(r.OrderbookData.Ticks) || tickIdx < 0 {
-			return sdk.Coin{}, domain.OrderbookNotEnoughLiquidityToCompleteSwapError{PoolId: r.GetId(), AmountIn: tokenIn.String()}
+			return sdk.Coin{}, 0, domain.OrderbookNotEnoughLiquidityToCompleteSwapError{PoolId: r.GetId(), AmountIn: tokenIn.String()}
 		}
 
 		// According to the check on amountInToExhaustLiquidity above, we should never run out of ticks here
 		tick := r.OrderbookData.Ticks[tickIdx]
+		ticksWalked++
 
 		// Calculate the price for the current tick
 		tickPrice, err := clmath.TickToPrice(tick.TickId)
 		if err != nil {
-			return sdk.Coin{}, err
+			return sdk.Coin{}, 0, err
 		}
 
 		// Amount that should be filled given the current tick price and all the remaining amount of tokens in
@@ -150,7 +173,84 @@ func (r *routableOrderbookPoolImpl) CalculateTokenOutByTokenIn(ctx context.Conte
 	}
 
 	// Return total amount out
-	return sdk.Coin{Denom: r.TokenOutDenom, Amount: amountOutTotal.Dec().TruncateInt()}, nil
+	return sdk.Coin{Denom: r.TokenOutDenom, Amount: amountOutTotal.Dec().TruncateInt()}, ticksWalked, nil
+}
+
+// CalculateTokenInByTokenOut implements domain.RoutablePool.
+// It calculates the amount of token in required to receive the given amount of token out for a orderbook pool.
+// The ticks are walked on the "out" side of the orderbook, filling the token out
+// and converting each filled amount into the token in in the opposite direction.
+// Note that orderbook contract does not implement the exact amount out swap API.
+// As a result, the swap through this pool is expected to be executed as exact amount in with
+// the computed token in.
+// Fails if:
+// - the underlying chain pool set on the routable pool is not of cosmwasm type
+// - token in and token out denoms are the same
+// - the provided denom pair is not supported by the orderbook
+// - runs out of ticks during swap (token out is too high for liquidity in the pool)
+// - `TickToPrice` calculation fails
+func (r *routableOrderbookPoolImpl) CalculateTokenInByTokenOut(ctx context.Context, tokenOut sdk.Coin) (sdk.Coin, error) {
+	poolType := r.GetType()
+
+	// Esnure that the pool is a cosmwasm pool
+	if poolType != poolmanagertypes.CosmWasm {
+		return sdk.Coin{}, domain.InvalidPoolTypeError{PoolType: int32(poolType)}
+	}
+
+	// Get the expected order directionIn
+	directionIn, err := r.OrderbookData.GetDirection(r.TokenInDenom, tokenOut.Denom)
+	if err != nil {
+		return sdk.Coin{}, err
+	}
+	directionOut := directionIn.Opposite()
+	iterationStep, err := directionOut.IterationStep()
+	if err != nil {
+		return sdk.Coin{}, err
+	}
+
+	// Get starting tick index for the "out" side of the orderbook
+	// Since the order will get the liquidity out from that side
+	tickIdx, err := r.OrderbookData.GetStartTickIndex(directionOut)
+	if err != nil {
+		return sdk.Coin{}, err
+	}
+
+	amountInTotal := osmomath.ZeroBigDec()
+	amountOutRemaining := osmomath.BigDecFromSDKInt(tokenOut.Amount)
+
+	// ASSUMPTION: Ticks are ordered
+	for amountOutRemaining.GT(smallestDec) {
+		// Order has run out of ticks to iterate
+		if tickIdx >= len(r.OrderbookData.Ticks) || tickIdx < 0 {
+			return sdk.Coin{}, domain.OrderbookNotEnoughLiquidityToCompleteSwapExactOutError{PoolId: r.GetId(), AmountOut: tokenOut.String()}
+		}
+
+		tick := r.OrderbookData.Ticks[tickIdx]
+
+		// Calculate the price for the current tick
+		tickPrice, err := clmath.TickToPrice(tick.TickId)
+		if err != nil {
+			return sdk.Coin{}, err
+		}
+
+		// Cap the output amount to the amount of tokens that can be filled in the current tick
+		outputFilled := tick.TickLiquidity.GetFillableAmount(amountOutRemaining, directionOut)
+
+		// Convert the filled amount to the input amount that is required to fill it
+		inputFilled := cosmwasmpool.OrderbookValueInOppositeDirection(outputFilled, tickPrice, directionOut, cosmwasmpool.ROUND_UP)
+
+		// Add the required amount to the order total
+		amountInTotal.AddMut(inputFilled)
+
+		// Subtract the filled amount from the remaining amount of tokens out
+		amountOutRemaining.SubMut(outputFilled)
+
+		// Increment or decrement the current tick index depending on out order direction
+		tickIdx += iterationStep
+	}
+
+	// Return total amount in, rounded up so that it is sufficient to receive the token out
+	return sdk.Coin{Denom: r.TokenInDenom, Amount: amountInTotal.Ceil().Dec().TruncateInt()}, nil
 }
 
 // GetTokenOutDenom implements RoutablePool.
@@ -175,6 +275,13 @@ func (r *routableOrderbookPoolImpl) ChargeTakerFeeExactIn(tokenIn sdk.Coin) (tok
 	return tokenInAfterTakerFee
 }
 
+// ChargeTakerFeeExactOut implements domain.RoutablePool.
+// Returns the token in amount that must be provided for the given token in to remain after the taker fee is charged.
+func (r *routableOrderbookPoolImpl) ChargeTakerFeeExactOut(tokenIn sdk.Coin) (tokenInAfterFee sdk.Coin) {
+	tokenInAfterTakerFee, _ := poolmanager.CalcTakerFeeExactOut(tokenIn, r.GetTakerFee())
+	return tokenInAfterTakerFee
+}
+
 // GetTakerFee implements domain.RoutablePool.
 func (r *routableOrderbookPoolImpl) GetTakerFee() math.LegacyDec {
 	return r.TakerFee
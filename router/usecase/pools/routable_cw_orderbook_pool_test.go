@@ -286,6 +286,121 @@ func (s *RoutablePoolTestSuite) TestCalculateTokenOutByTokenIn_Orderbook() {
 	}
 }
 
+func (s *RoutablePoolTestSuite) TestCalculateTokenInByTokenOut_Orderbook() {
+	tests := map[string]struct {
+		tokenOut         sdk.Coin
+		expectedTokenIn  sdk.Coin
+		nextBidTickIndex int
+		nextAskTickIndex int
+		ticks            []cosmwasmpool.OrderbookTick
+		expectError      error
+	}{
+		"BID: simple swap": {
+			tokenOut:         sdk.NewCoin(BASE_DENOM, osmomath.NewInt(100)),
+			expectedTokenIn:  sdk.NewCoin(QUOTE_DENOM, osmomath.NewInt(100)),
+			nextBidTickIndex: MIN_TICK,
+			nextAskTickIndex: 0,
+			ticks: []cosmwasmpool.OrderbookTick{
+				{TickId: 0, TickLiquidity: cosmwasmpool.OrderbookTickLiquidity{
+					BidLiquidity: osmomath.ZeroBigDec(),
+					AskLiquidity: osmomath.NewBigDec(100),
+				}},
+			},
+		},
+		"BID: multi-tick/direction swap": {
+			tokenOut: sdk.NewCoin(BASE_DENOM, osmomath.NewInt(125)),
+			// 100 * 1 (tick: 0) + 25 * 2 (tick: LARGE_POSITIVE_TICK) = 150
+			expectedTokenIn:  sdk.NewCoin(QUOTE_DENOM, osmomath.NewInt(150)),
+			nextBidTickIndex: -1, // no next bid tick
+			nextAskTickIndex: 0,
+			ticks: []cosmwasmpool.OrderbookTick{
+				{
+					TickId: 0,
+					TickLiquidity: cosmwasmpool.OrderbookTickLiquidity{
+						BidLiquidity: osmomath.ZeroBigDec(),
+						AskLiquidity: osmomath.NewBigDec(100),
+					},
+				},
+				{
+					TickId: LARGE_POSITIVE_TICK,
+					TickLiquidity: cosmwasmpool.OrderbookTickLiquidity{
+						BidLiquidity: osmomath.ZeroBigDec(),
+						AskLiquidity: osmomath.NewBigDec(100),
+					},
+				},
+			},
+		},
+		"BID: error not enough liquidity": {
+			tokenOut:         sdk.NewCoin(BASE_DENOM, osmomath.NewInt(100)),
+			expectedTokenIn:  sdk.NewCoin(QUOTE_DENOM, osmomath.NewInt(100)),
+			nextBidTickIndex: -1, // no next bid tick
+			nextAskTickIndex: 0,
+			ticks: []cosmwasmpool.OrderbookTick{
+				{TickId: 0, TickLiquidity: cosmwasmpool.OrderbookTickLiquidity{
+					BidLiquidity: osmomath.ZeroBigDec(),
+					AskLiquidity: osmomath.NewBigDec(99),
+				}},
+			},
+			expectError: domain.OrderbookNotEnoughLiquidityToCompleteSwapExactOutError{
+				PoolId:    defaultPoolID,
+				AmountOut: sdk.NewCoin(BASE_DENOM, osmomath.NewInt(100)).String(),
+			},
+		},
+		"ASK: multi-tick/direction swap": {
+			tokenOut: sdk.NewCoin(QUOTE_DENOM, osmomath.NewInt(200)),
+			// 100 / 2 (tick: LARGE_POSITIVE_TICK) + 100 / 1 (tick: 0) = 150
+			expectedTokenIn:  sdk.NewCoin(BASE_DENOM, osmomath.NewInt(150)),
+			nextBidTickIndex: 1,
+			nextAskTickIndex: 0,
+			ticks: []cosmwasmpool.OrderbookTick{
+				{
+					TickId: 0,
+					TickLiquidity: cosmwasmpool.OrderbookTickLiquidity{
+						BidLiquidity: osmomath.NewBigDec(100),
+						AskLiquidity: osmomath.NewBigDec(100),
+					},
+				},
+				{
+					TickId: LARGE_POSITIVE_TICK,
+					TickLiquidity: cosmwasmpool.OrderbookTickLiquidity{
+						BidLiquidity: osmomath.NewBigDec(100),
+						AskLiquidity: osmomath.NewBigDec(100),
+					},
+				},
+			},
+		},
+		"invalid: duplicate denom": {
+			tokenOut:         sdk.NewCoin(BASE_DENOM, osmomath.NewInt(150)),
+			expectedTokenIn:  sdk.NewCoin(BASE_DENOM, osmomath.NewInt(125)),
+			nextBidTickIndex: -1, // no next bid tick
+			nextAskTickIndex: -1, // no next ask tick
+			ticks:            []cosmwasmpool.OrderbookTick{},
+			expectError: cosmwasmpool.DuplicatedDenomError{
+				Denom: BASE_DENOM,
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		s.Run(name, func() {
+			s.Setup()
+			routablePool := s.SetupRoutableOrderbookPool(tc.expectedTokenIn.Denom, tc.tokenOut.Denom, tc.nextBidTickIndex, tc.nextAskTickIndex, tc.ticks, osmomath.ZeroDec())
+			routablePool.SetTokenInDenom(tc.expectedTokenIn.Denom)
+
+			tokenIn, err := routablePool.CalculateTokenInByTokenOut(context.TODO(), tc.tokenOut)
+
+			if tc.expectError != nil {
+				s.Require().Error(err)
+				s.Require().Equal(err, tc.expectError)
+				return
+			}
+			s.Require().NoError(err)
+
+			s.Require().Equal(tc.expectedTokenIn, tokenIn)
+		})
+	}
+}
+
 func (s *RoutablePoolTestSuite) TestCalcSpotPrice_Orderbook() {
 	tests := map[string]struct {
 		quoteDenom        string
@@ -36,6 +36,7 @@ type routableCosmWasmPoolImpl struct {
 	TakerFee                 osmomath.Dec                    "json:\"taker_fee\""
 	SpreadFactor             osmomath.Dec                    "json:\"spread_factor\""
 	wasmClient               wasmtypes.QueryClient           "json:\"-\""
+	calcQueryMemo            *cosmwasmdomain.CalcQueryMemo   "json:\"-\""
 	spotPriceQuoteCalculator domain.SpotPriceQuoteCalculator "json:\"-\""
 }
 
@@ -49,6 +50,7 @@ func NewRoutableCosmWasmPool(pool *cwpoolmodel.CosmWasmPool, balances sdk.Coins,
 		TakerFee:      takerFee,
 		SpreadFactor:  spreadFactor,
 		wasmClient:    cosmWasmPoolsParams.WasmClient,
+		calcQueryMemo: cosmWasmPoolsParams.CalcQueryMemo,
 
 		// Note, that there is no calculator set
 		// since we need to wire quote calculation callback to it.
@@ -103,6 +105,23 @@ func (r *routableCosmWasmPoolImpl) calculateTokenOutByTokenIn(ctx context.Contex
 		return sdk.Coin{}, domain.InvalidPoolTypeError{PoolType: int32(poolType)}
 	}
 
+	if r.calcQueryMemo != nil {
+		tokenOutAmount, err := r.calcQueryMemo.CalcOutAmtGivenIn(ctx, r.GetId(), tokenIn.Denom, tokenIn.Amount, tokenOutDenom, func(ctx context.Context, amount osmomath.Int) (osmomath.Int, error) {
+			tokenOut, err := r.queryCalcOutAmtGivenIn(ctx, sdk.NewCoin(tokenIn.Denom, amount), tokenOutDenom)
+			return tokenOut.Amount, err
+		})
+		if err != nil {
+			return sdk.Coin{}, err
+		}
+
+		return sdk.NewCoin(tokenOutDenom, tokenOutAmount), nil
+	}
+
+	return r.queryCalcOutAmtGivenIn(ctx, tokenIn, tokenOutDenom)
+}
+
+// queryCalcOutAmtGivenIn queries the pool contract for the amount of token out given the token in.
+func (r *routableCosmWasmPoolImpl) queryCalcOutAmtGivenIn(ctx context.Context, tokenIn sdk.Coin, tokenOutDenom string) (sdk.Coin, error) {
 	// Configure the calc query message
 	calcMessage := msg.NewCalcOutAmtGivenInRequest(tokenIn, tokenOutDenom, r.SpreadFactor)
 
@@ -111,11 +130,47 @@ func (r *routableCosmWasmPoolImpl) calculateTokenOutByTokenIn(ctx context.Contex
 		return sdk.Coin{}, err
 	}
 
-	// No slippage swaps - just return the same amount of token out as token in
-	// as long as there is enough liquidity in the pool.
 	return calcOutAmtGivenInResponse.TokenOut, nil
 }
 
+// CalculateTokenInByTokenOut implements domain.RoutablePool.
+// It queries the pool contract for the amount of token in required to receive the given token out.
+func (r *routableCosmWasmPoolImpl) CalculateTokenInByTokenOut(ctx context.Context, tokenOut sdk.Coin) (sdk.Coin, error) {
+	poolType := r.GetType()
+
+	// Ensure that the pool is cosmwasm
+	if poolType != poolmanagertypes.CosmWasm {
+		return sdk.Coin{}, domain.InvalidPoolTypeError{PoolType: int32(poolType)}
+	}
+
+	if r.calcQueryMemo != nil {
+		tokenInAmount, err := r.calcQueryMemo.CalcInAmtGivenOut(ctx, r.GetId(), tokenOut.Denom, tokenOut.Amount, r.TokenInDenom, func(ctx context.Context, amount osmomath.Int) (osmomath.Int, error) {
+			tokenIn, err := r.queryCalcInAmtGivenOut(ctx, sdk.NewCoin(tokenOut.Denom, amount))
+			return tokenIn.Amount, err
+		})
+		if err != nil {
+			return sdk.Coin{}, err
+		}
+
+		return sdk.NewCoin(r.TokenInDenom, tokenInAmount), nil
+	}
+
+	return r.queryCalcInAmtGivenOut(ctx, tokenOut)
+}
+
+// queryCalcInAmtGivenOut queries the pool contract for the amount of token in required to receive the given token out.
+func (r *routableCosmWasmPoolImpl) queryCalcInAmtGivenOut(ctx context.Context, tokenOut sdk.Coin) (sdk.Coin, error) {
+	// Configure the calc query message
+	calcMessage := msg.NewCalcInAmtGivenOutRequest(r.TokenInDenom, tokenOut, r.SpreadFactor)
+
+	calcInAmtGivenOutResponse := msg.CalcInAmtGivenOutResponse{}
+	if err := cosmwasmdomain.QueryCosmwasmContract(ctx, r.wasmClient, r.ChainPool.ContractAddress, &calcMessage, &calcInAmtGivenOutResponse); err != nil {
+		return sdk.Coin{}, err
+	}
+
+	return calcInAmtGivenOutResponse.TokenIn, nil
+}
+
 // SetTokenInDenom implements domain.RoutablePool.
 func (r *routableCosmWasmPoolImpl) SetTokenInDenom(tokenInDenom string) {
 	r.TokenInDenom = tokenInDenom
@@ -148,6 +203,13 @@ func (r *routableCosmWasmPoolImpl) ChargeTakerFeeExactIn(tokenIn sdk.Coin) (inAm
 	return tokenInAfterTakerFee
 }
 
+// ChargeTakerFeeExactOut implements domain.RoutablePool.
+// Returns the token in amount that must be provided for the given token in to remain after the taker fee is charged.
+func (r *routableCosmWasmPoolImpl) ChargeTakerFeeExactOut(tokenIn sdk.Coin) (tokenInAfterFee sdk.Coin) {
+	tokenInAfterTakerFee, _ := poolmanager.CalcTakerFeeExactOut(tokenIn, r.GetTakerFee())
+	return tokenInAfterTakerFee
+}
+
 // GetTakerFee implements domain.RoutablePool.
 func (r *routableCosmWasmPoolImpl) GetTakerFee() math.LegacyDec {
 	return r.TakerFee
@@ -73,6 +73,28 @@ func (r *routableTransmuterPoolImpl) CalculateTokenOutByTokenIn(ctx context.Cont
 	return sdk.Coin{Denom: r.TokenOutDenom, Amount: tokenIn.Amount}, nil
 }
 
+// CalculateTokenInByTokenOut implements domain.RoutablePool.
+// It calculates the amount of token in required to receive the given token out for a transmuter pool.
+// Transmuter pool allows no slippage swaps. It just returns the same amount of token in as token out.
+// Returns error if:
+// - the underlying chain pool set on the routable pool is not of transmuter type
+// - the token out amount is greater than the balance of the token out
+func (r *routableTransmuterPoolImpl) CalculateTokenInByTokenOut(ctx context.Context, tokenOut sdk.Coin) (sdk.Coin, error) {
+	poolType := r.GetType()
+
+	// Esnure that the pool is cosmwasm
+	if poolType != poolmanagertypes.CosmWasm {
+		return sdk.Coin{}, domain.InvalidPoolTypeError{PoolType: int32(poolType)}
+	}
+
+	// Validate token out balance
+	if err := validateTransmuterBalance(tokenOut.Amount, r.Balances, tokenOut.Denom); err != nil {
+		return sdk.Coin{}, err
+	}
+
+	return sdk.Coin{Denom: r.TokenInDenom, Amount: tokenOut.Amount}, nil
+}
+
 // GetTokenOutDenom implements RoutablePool.
 func (r *routableTransmuterPoolImpl) GetTokenOutDenom() string {
 	return r.TokenOutDenom
@@ -94,6 +116,13 @@ func (r *routableTransmuterPoolImpl) ChargeTakerFeeExactIn(tokenIn sdk.Coin) (in
 	return tokenInAfterTakerFee
 }
 
+// ChargeTakerFeeExactOut implements domain.RoutablePool.
+// Returns the token in amount that must be provided for the given token in to remain after the taker fee is charged.
+func (r *routableTransmuterPoolImpl) ChargeTakerFeeExactOut(tokenIn sdk.Coin) (tokenInAfterFee sdk.Coin) {
+	tokenInAfterTakerFee, _ := poolmanager.CalcTakerFeeExactOut(tokenIn, r.GetTakerFee())
+	return tokenInAfterTakerFee
+}
+
 // validateTransmuterBalance validates that the balance of the denom to validate is greater than the token in amount.
 // Returns nil on success, error otherwise.
 func validateTransmuterBalance(tokenInAmount osmomath.Int, balances sdk.Coins, denomToValidate string) error {
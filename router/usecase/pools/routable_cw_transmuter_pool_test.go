@@ -92,3 +92,73 @@ func (s *RoutablePoolTestSuite) TestCalculateTokenOutByTokenIn_Transmuter() {
 		})
 	}
 }
+
+// Tests no slippage exact amount out quotes and validation edge cases around transmuter pools.
+func (s *RoutablePoolTestSuite) TestCalculateTokenInByTokenOut_Transmuter() {
+	defaultAmount := DefaultAmt0
+	defaultBalances := sdk.NewCoins(sdk.NewCoin(USDC, defaultAmount), sdk.NewCoin(ETH, defaultAmount))
+
+	tests := map[string]struct {
+		tokenOut     sdk.Coin
+		tokenInDenom string
+		balances     sdk.Coins
+		expectError  error
+	}{
+		"valid transmuter quote": {
+			tokenOut:     sdk.NewCoin(ETH, defaultAmount),
+			tokenInDenom: USDC,
+			balances:     defaultBalances,
+		},
+		"error: token out is larger than balance of token out": {
+			tokenOut:     sdk.NewCoin(ETH, defaultAmount),
+			tokenInDenom: USDC,
+
+			// Make token out amount 1 smaller than the default amount
+			balances: sdk.NewCoins(sdk.NewCoin(USDC, defaultAmount), sdk.NewCoin(ETH, defaultAmount.Sub(osmomath.OneInt()))),
+
+			expectError: domain.TransmuterInsufficientBalanceError{
+				Denom:         ETH,
+				BalanceAmount: defaultAmount.Sub(osmomath.OneInt()).String(),
+				Amount:        defaultAmount.String(),
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		s.Run(name, func() {
+			s.Setup()
+
+			cosmwasmPool := s.PrepareCustomTransmuterPool(s.TestAccs[0], []string{tc.tokenInDenom, tc.tokenOut.Denom})
+
+			poolType := cosmwasmPool.GetType()
+
+			mock := &mocks.MockRoutablePool{ChainPoolModel: cosmwasmPool.AsSerializablePool(), Balances: tc.balances, PoolType: poolType}
+
+			cosmWasmPoolsParams := cosmwasmdomain.CosmWasmPoolsParams{
+				Config: domain.CosmWasmPoolRouterConfig{
+					TransmuterCodeIDs: map[uint64]struct{}{
+						cosmwasmPool.GetCodeId(): {},
+					},
+				},
+				ScalingFactorGetterCb: domain.UnsetScalingFactorGetterCb,
+			}
+			routablePool, err := pools.NewRoutablePool(mock, tc.tokenOut.Denom, noTakerFee, cosmWasmPoolsParams)
+			s.Require().NoError(err)
+
+			routablePool.SetTokenInDenom(tc.tokenInDenom)
+
+			tokenIn, err := routablePool.CalculateTokenInByTokenOut(context.TODO(), tc.tokenOut)
+
+			if tc.expectError != nil {
+				s.Require().Error(err)
+				s.Require().ErrorIs(err, tc.expectError)
+				return
+			}
+			s.Require().NoError(err)
+
+			// No slippage swaps on success
+			s.Require().Equal(tc.tokenInDenom, tokenIn.Denom)
+			s.Require().Equal(tc.tokenOut.Amount, tokenIn.Amount)
+		})
+	}
+}
@@ -33,6 +33,12 @@ type routableResultPoolImpl struct {
 	TokenInDenom  string                    "json:\"token_in_denom,omitempty\""
 	TakerFee      osmomath.Dec              "json:\"taker_fee\""
 	CodeID        uint64                    "json:\"code_id,omitempty\""
+	// ExecuteAsExactIn is true if the pool does not support the exact amount out swap API.
+	// As a result, the swap through the pool is executed as exact amount in
+	// with the token in amount computed by the quote.
+	ExecuteAsExactIn bool "json:\"execute_as_exact_in,omitempty\""
+	// SwapBreakdown is the price impact and fee breakdown of the swap through the pool.
+	SwapBreakdown *domain.PoolSwapBreakdown "json:\"swap_breakdown,omitempty\""
 }
 
 // GetCodeID implements domain.RoutablePool.
@@ -74,6 +80,26 @@ func NewExactAmountOutRoutableResultPool(ID uint64, poolType poolmanagertypes.Po
 	}
 }
 
+// SetExecuteAsExactIn implements domain.RoutableResultPool.
+func (r *routableResultPoolImpl) SetExecuteAsExactIn(executeAsExactIn bool) {
+	r.ExecuteAsExactIn = executeAsExactIn
+}
+
+// IsExecutedAsExactIn implements domain.RoutableResultPool.
+func (r *routableResultPoolImpl) IsExecutedAsExactIn() bool {
+	return r.ExecuteAsExactIn
+}
+
+// SetSwapBreakdown implements domain.RoutableResultPool.
+func (r *routableResultPoolImpl) SetSwapBreakdown(breakdown *domain.PoolSwapBreakdown) {
+	r.SwapBreakdown = breakdown
+}
+
+// GetSwapBreakdown implements domain.RoutableResultPool.
+func (r *routableResultPoolImpl) GetSwapBreakdown() *domain.PoolSwapBreakdown {
+	return r.SwapBreakdown
+}
+
 // GetId implements domain.RoutablePool.
 func (r *routableResultPoolImpl) GetId() uint64 {
 	return r.ID
@@ -128,6 +154,11 @@ func (r *routableResultPoolImpl) CalculateTokenOutByTokenIn(ctx context.Context,
 	return sdk.Coin{}, errors.New("not implemented")
 }
 
+// CalculateTokenInByTokenOut implements RoutablePool.
+func (r *routableResultPoolImpl) CalculateTokenInByTokenOut(ctx context.Context, tokenOut sdk.Coin) (sdk.Coin, error) {
+	return sdk.Coin{}, errors.New("not implemented")
+}
+
 // GetTokenOutDenom implements RoutablePool.
 func (r *routableResultPoolImpl) GetTokenOutDenom() string {
 	return r.TokenOutDenom
@@ -150,6 +181,13 @@ func (r *routableResultPoolImpl) ChargeTakerFeeExactIn(tokenIn sdk.Coin) (tokenI
 	return tokenInAfterTakerFee
 }
 
+// ChargeTakerFeeExactOut implements domain.RoutablePool.
+// Returns the token in amount that must be provided for the given token in to remain after the taker fee is charged.
+func (r *routableResultPoolImpl) ChargeTakerFeeExactOut(tokenIn sdk.Coin) (tokenInAfterFee sdk.Coin) {
+	tokenInAfterTakerFee, _ := poolmanager.CalcTakerFeeExactOut(tokenIn, r.TakerFee)
+	return tokenInAfterTakerFee
+}
+
 // GetTakerFee implements domain.RoutablePool.
 func (r *routableResultPoolImpl) GetTakerFee() math.LegacyDec {
 	return r.TakerFee
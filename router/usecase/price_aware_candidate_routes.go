@@ -0,0 +1,514 @@
+package usecase
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	"github.com/osmosis-labs/sqs/domain"
+	"github.com/osmosis-labs/sqs/domain/mvc"
+	"github.com/osmosis-labs/sqs/log"
+	"github.com/osmosis-labs/sqs/sqsdomain"
+)
+
+// priceGraphEdge is an edge of the pool graph swapping the token in denom for the token out denom over the pool.
+type priceGraphEdge struct {
+	pool          *sqsdomain.PoolWrapper
+	tokenInDenom  string
+	tokenOutDenom string
+	// weight is the negative natural log of the estimated swap rate of the edge net of the spread factor.
+	// The path with the least total weight has the best estimated rate.
+	weight float64
+}
+
+// priceGraphPath is a path over the pool graph.
+type priceGraphPath struct {
+	edges  []*priceGraphEdge
+	weight float64
+}
+
+// key returns the key identifying the path by its pools and token out denoms.
+func (p priceGraphPath) key() string {
+	var sb strings.Builder
+	for _, edge := range p.edges {
+		sb.WriteString(strconv.FormatUint(edge.pool.GetId(), 10))
+		sb.WriteByte(':')
+		sb.WriteString(edge.tokenOutDenom)
+		sb.WriteByte('/')
+	}
+	return sb.String()
+}
+
+// priceGraphPathState is the state of a path during the shortest path search.
+type priceGraphPathState struct {
+	edge   *priceGraphEdge
+	prev   *priceGraphPathState
+	weight float64
+}
+
+// containsDenomOrPool returns true if the path ending at the state goes through the given denom or pool.
+func (s *priceGraphPathState) containsDenomOrPool(denom string, poolID uint64) bool {
+	for state := s; state != nil && state.edge != nil; state = state.prev {
+		if state.edge.tokenInDenom == denom || state.edge.tokenOutDenom == denom || state.edge.pool.GetId() == poolID {
+			return true
+		}
+	}
+	return false
+}
+
+// edges returns the edges of the path ending at the state in order.
+func (s *priceGraphPathState) edges() []*priceGraphEdge {
+	var edges []*priceGraphEdge
+	for state := s; state != nil && state.edge != nil; state = state.prev {
+		edges = append(edges, state.edge)
+	}
+
+	for i, j := 0, len(edges)-1; i < j; i, j = i+1, j-1 {
+		edges[i], edges[j] = edges[j], edges[i]
+	}
+
+	return edges
+}
+
+// priceAwareCandidateRouteFinder finds the candidate routes as the k shortest paths over the pool graph
+// using Yen's algorithm. The edges are weighted by the negative log of the swap rates estimated from
+// the pool models net of the spread factors. As a result, the routes through the less liquid pools
+// with better prices are found that the breadth-first search over the pools ranked by liquidity may miss.
+//
+// The estimated rates ignore the price impact of the amount swapped. The routes are ranked
+// by the quotes computed over them by the router as with any other candidate routes.
+type priceAwareCandidateRouteFinder struct {
+	candidateRouteDataHolder mvc.CandidateRouteSearchDataHolder
+	cosmWasmPoolsConfig      domain.CosmWasmPoolRouterConfig
+	logger                   log.Logger
+
+	// bfsFinder finds the candidate cycles.
+	bfsFinder candidateRouteFinder
+}
+
+var _ domain.CandidateRouteSearcher = priceAwareCandidateRouteFinder{}
+
+// NewPriceAwareCandidateRouteFinder returns a new price-aware candidate route finder.
+func NewPriceAwareCandidateRouteFinder(candidateRouteDataHolder mvc.CandidateRouteSearchDataHolder, cosmWasmPoolsConfig domain.CosmWasmPoolRouterConfig, logger log.Logger) priceAwareCandidateRouteFinder {
+	return priceAwareCandidateRouteFinder{
+		candidateRouteDataHolder: candidateRouteDataHolder,
+		cosmWasmPoolsConfig:      cosmWasmPoolsConfig,
+		logger:                   logger,
+
+		bfsFinder: NewCandidateRouteFinder(candidateRouteDataHolder, logger),
+	}
+}
+
+// FindCandidateRoutes implements domain.CandidateRouteSearcher.
+// The routes follow the same rules as the ones found by the breadth-first search. That is,
+// the first pool must hold enough of the token in, the pools after the first must not contain the token in,
+// any pool containing the token out must swap into it and the routes must not go through
+// the intermediary denoms that the options are configured to skip.
+// As with the breadth-first search, the canonical orderbook of the pair is added as the first route
+// and is not used by the other routes.
+func (c priceAwareCandidateRouteFinder) FindCandidateRoutes(tokenIn sdk.Coin, tokenOutDenom string, options domain.CandidateRouteSearchOptions) (sqsdomain.CandidateRoutes, error) {
+	denomData, err := c.candidateRouteDataHolder.GetDenomData(tokenIn.Denom)
+	if err != nil {
+		return sqsdomain.CandidateRoutes{}, err
+	}
+
+	routes := make([]candidateRouteWrapper, 0, options.MaxRoutes)
+
+	canonicalOrderbookRoute, canonicalOrderbookPoolID, ok := getCanonicalOrderbookRoute(denomData, tokenOutDenom, options)
+	if ok {
+		routes = append(routes, canonicalOrderbookRoute)
+	}
+
+	graph := &priceGraph{
+		finder:                   c,
+		tokenIn:                  tokenIn,
+		tokenOutDenom:            tokenOutDenom,
+		options:                  options,
+		canonicalOrderbookPoolID: canonicalOrderbookPoolID,
+		edgesByDenom:             make(map[string][]*priceGraphEdge),
+	}
+
+	paths, err := graph.kShortestPaths(options.MaxRoutes - len(routes))
+	if err != nil {
+		return sqsdomain.CandidateRoutes{}, err
+	}
+
+	for _, path := range paths {
+		pools := make([]candidatePoolWrapper, 0, len(path.edges))
+		for _, edge := range path.edges {
+			pools = append(pools, candidatePoolWrapper{
+				CandidatePool: sqsdomain.CandidatePool{
+					ID:            edge.pool.GetId(),
+					TokenOutDenom: edge.tokenOutDenom,
+				},
+				PoolDenoms: edge.pool.SQSModel.PoolDenoms,
+			})
+		}
+
+		routes = append(routes, candidateRouteWrapper{
+			Pools:                     pools,
+			IsCanonicalOrderboolRoute: false,
+		})
+	}
+
+	return validateAndFilterRoutes(routes, tokenIn.Denom, c.logger)
+}
+
+// FindCandidateCycles implements domain.CandidateRouteSearcher.
+// The cycles are found by the breadth-first search.
+func (c priceAwareCandidateRouteFinder) FindCandidateCycles(denom string, options domain.CandidateRouteSearchOptions) (sqsdomain.CandidateRoutes, error) {
+	return c.bfsFinder.FindCandidateCycles(denom, options)
+}
+
+// priceGraph is the pool graph of a single candidate route search.
+// The edges out of each denom are constructed lazily on first visit.
+type priceGraph struct {
+	finder        priceAwareCandidateRouteFinder
+	tokenIn       sdk.Coin
+	tokenOutDenom string
+	options       domain.CandidateRouteSearchOptions
+	// canonicalOrderbookPoolID is the ID of the canonical orderbook pool of the pair that is excluded
+	// from the graph since it is added as its own route. Zero if there is none.
+	canonicalOrderbookPoolID uint64
+
+	edgesByDenom map[string][]*priceGraphEdge
+}
+
+// edgesFrom returns the edges out of the given denom that satisfy the search options and the route rules.
+func (g *priceGraph) edgesFrom(denom string) ([]*priceGraphEdge, error) {
+	if edges, ok := g.edgesByDenom[denom]; ok {
+		return edges, nil
+	}
+
+	denomData, err := g.finder.candidateRouteDataHolder.GetDenomData(denom)
+	if err != nil {
+		return nil, err
+	}
+
+	isTokenIn := denom == g.tokenIn.Denom
+
+	edges := make([]*priceGraphEdge, 0)
+	for _, rankedPool := range denomData.SortedPools {
+		// Unsafe cast for performance reasons.
+		// nolint: forcetypeassert
+		pool := rankedPool.(*sqsdomain.PoolWrapper)
+
+		if g.options.ShouldSkipPool(pool) || pool.GetId() == g.canonicalOrderbookPoolID {
+			continue
+		}
+
+		if pool.GetLiquidityCap().Uint64() < g.options.MinPoolLiquidityCap {
+			if g.options.LiquidityFilteredPoolCb != nil {
+				g.options.LiquidityFilteredPoolCb(pool.GetId())
+			}
+			continue
+		}
+
+		poolDenoms := pool.SQSModel.PoolDenoms
+		hasDenom, hasTokenIn, hasTokenOut := false, false, false
+		for _, poolDenom := range poolDenoms {
+			hasDenom = hasDenom || poolDenom == denom
+			hasTokenIn = hasTokenIn || poolDenom == g.tokenIn.Denom
+			hasTokenOut = hasTokenOut || poolDenom == g.tokenOutDenom
+		}
+
+		// Only the first pool may contain the token in.
+		if !hasDenom || (hasTokenIn && !isTokenIn) {
+			continue
+		}
+
+		if isTokenIn {
+			// HACK: alloyed LP share is not contained in balances.
+			// See candidateRouteFinder.FindCandidateRoutes for details.
+			cosmwasmModel := pool.SQSModel.CosmWasmPoolModel
+			isAlloyed := cosmwasmModel != nil && cosmwasmModel.IsAlloyTransmuter()
+
+			if pool.SQSModel.Balances.AmountOf(denom).LT(g.tokenIn.Amount) && !isAlloyed {
+				// Not enough token in to swap.
+				continue
+			}
+		}
+
+		for _, poolDenom := range poolDenoms {
+			if poolDenom == denom {
+				continue
+			}
+
+			// The pools containing the token out only swap into it.
+			if hasTokenOut && poolDenom != g.tokenOutDenom {
+				continue
+			}
+
+			if !hasTokenOut && g.options.ShouldSkipIntermediaryDenom(poolDenom) {
+				continue
+			}
+
+			rate, ok := g.finder.estimateRate(pool, denom, poolDenom)
+			if !ok {
+				continue
+			}
+
+			edges = append(edges, &priceGraphEdge{
+				pool:          pool,
+				tokenInDenom:  denom,
+				tokenOutDenom: poolDenom,
+				weight:        -math.Log(rate),
+			})
+		}
+	}
+
+	g.edgesByDenom[denom] = edges
+
+	return edges, nil
+}
+
+// kShortestPaths returns up to k shortest paths from the token in to the token out denom
+// in increasing order of weight using Yen's algorithm.
+func (g *priceGraph) kShortestPaths(k int) ([]priceGraphPath, error) {
+	if k <= 0 || g.options.MaxPoolsPerRoute <= 0 {
+		return nil, nil
+	}
+
+	shortestPath, ok, err := g.shortestPath(nil, nil)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	paths := []priceGraphPath{shortestPath}
+	seenPaths := map[string]struct{}{shortestPath.key(): {}}
+	candidatePaths := make([]priceGraphPath, 0)
+
+	for len(paths) < k {
+		prevPath := paths[len(paths)-1]
+
+		// Each edge of the previous path is a spur edge, deviating from the root path preceding it.
+		for i := range prevPath.edges {
+			rootEdges := prevPath.edges[:i]
+
+			// Remove the edges deviating from the same root path in the paths found so far
+			// so that the spur path differs from all of them.
+			removedEdges := make(map[*priceGraphEdge]struct{})
+			for _, path := range paths {
+				if len(path.edges) > i && isSameRoot(path.edges, rootEdges) {
+					removedEdges[path.edges[i]] = struct{}{}
+				}
+			}
+
+			spurPath, ok, err := g.shortestPath(rootEdges, removedEdges)
+			if err != nil {
+				return nil, err
+			}
+
+			if !ok {
+				continue
+			}
+
+			if _, seen := seenPaths[spurPath.key()]; seen {
+				continue
+			}
+			seenPaths[spurPath.key()] = struct{}{}
+
+			candidatePaths = append(candidatePaths, spurPath)
+		}
+
+		if len(candidatePaths) == 0 {
+			break
+		}
+
+		// Move the candidate path with the least weight to the found paths.
+		sort.SliceStable(candidatePaths, func(i, j int) bool {
+			return candidatePaths[i].weight < candidatePaths[j].weight
+		})
+
+		paths = append(paths, candidatePaths[0])
+		candidatePaths = candidatePaths[1:]
+	}
+
+	return paths, nil
+}
+
+// isSameRoot returns true if the given edges start with the root edges.
+func isSameRoot(edges []*priceGraphEdge, rootEdges []*priceGraphEdge) bool {
+	for i, rootEdge := range rootEdges {
+		if edges[i] != rootEdge {
+			return false
+		}
+	}
+	return true
+}
+
+// shortestPath returns the path with the least weight from the token in to the token out denom
+// that starts with the given root edges, does not use the removed edges and has at most MaxPoolsPerRoute pools.
+// The path does not go through the same denom or pool twice.
+//
+// The search is a Bellman-Ford relaxation bounded by the number of pools per route so that the negative weights
+// of the edges with the estimated rates above one are supported. Only the least weight path to each denom is kept
+// for each number of pools. As a result, the path is the shortest up to the paths discarded for revisiting
+// a denom or a pool.
+// Returns false if there is no such path.
+func (g *priceGraph) shortestPath(rootEdges []*priceGraphEdge, removedEdges map[*priceGraphEdge]struct{}) (priceGraphPath, bool, error) {
+	var root *priceGraphPathState
+	spurDenom := g.tokenIn.Denom
+	for _, edge := range rootEdges {
+		root = &priceGraphPathState{edge: edge, prev: root, weight: root.getWeight() + edge.weight}
+		spurDenom = edge.tokenOutDenom
+	}
+
+	maxSpurPools := g.options.MaxPoolsPerRoute - len(rootEdges)
+
+	var best *priceGraphPathState
+
+	// Only the root path itself may reach the spur denom without any spur pools.
+	layer := map[string]*priceGraphPathState{spurDenom: root}
+	for hop := 0; hop < maxSpurPools && len(layer) > 0; hop++ {
+		nextLayer := make(map[string]*priceGraphPathState)
+
+		for denom, state := range layer {
+			edges, err := g.edgesFrom(denom)
+			if err != nil {
+				return priceGraphPath{}, false, err
+			}
+
+			for _, edge := range edges {
+				if _, removed := removedEdges[edge]; removed && state == root {
+					continue
+				}
+
+				if edge.tokenOutDenom == g.tokenIn.Denom || state.containsDenomOrPool(edge.tokenOutDenom, edge.pool.GetId()) {
+					continue
+				}
+
+				next := &priceGraphPathState{edge: edge, prev: state, weight: state.getWeight() + edge.weight}
+
+				if edge.tokenOutDenom == g.tokenOutDenom {
+					if best == nil || next.weight < best.weight {
+						best = next
+					}
+					continue
+				}
+
+				if current, ok := nextLayer[edge.tokenOutDenom]; !ok || next.weight < current.weight {
+					nextLayer[edge.tokenOutDenom] = next
+				}
+			}
+		}
+
+		layer = nextLayer
+	}
+
+	if best == nil {
+		return priceGraphPath{}, false, nil
+	}
+
+	return priceGraphPath{edges: best.edges(), weight: best.weight}, true, nil
+}
+
+// getWeight returns the weight of the path ending at the state. Zero for the empty path.
+func (s *priceGraphPathState) getWeight() float64 {
+	if s == nil {
+		return 0
+	}
+	return s.weight
+}
+
+// estimateRate returns the estimated amount of the token out received per unit of the token in swapped over the pool
+// net of the spread factor. The rate is estimated from the pool model without network requests:
+// - balancer, stableswap and concentrated pools use the spot price of the chain model.
+// - transmuter pools swap one to one.
+// - alloyed transmuter pools swap by the ratio of the normalization factors.
+// - orderbook pools use the average price of exhausting the liquidity of the opposite side.
+// - generalized cosmwasm pools use the ratio of the balances as a rough estimate.
+// Returns false if the rate cannot be estimated or is not positive.
+func (c priceAwareCandidateRouteFinder) estimateRate(pool *sqsdomain.PoolWrapper, tokenInDenom, tokenOutDenom string) (float64, bool) {
+	var (
+		rate float64
+		err  error
+	)
+
+	switch domain.GetCandidateRoutePoolType(pool, c.cosmWasmPoolsConfig) {
+	case domain.CandidateRoutePoolTypeBalancer, domain.CandidateRoutePoolTypeStableswap, domain.CandidateRoutePoolTypeConcentrated:
+		spotPrice, spotPriceErr := pool.ChainModel.SpotPrice(sdk.Context{}, tokenOutDenom, tokenInDenom)
+		if spotPriceErr != nil {
+			return 0, false
+		}
+		rate, err = strconv.ParseFloat(spotPrice.String(), 64)
+	case domain.CandidateRoutePoolTypeTransmuter:
+		rate = 1
+	case domain.CandidateRoutePoolTypeAlloyedTransmuter:
+		alloyTransmuter := pool.SQSModel.CosmWasmPoolModel.Data.AlloyTransmuter
+		if alloyTransmuter == nil {
+			return 0, false
+		}
+
+		var tokenInNormFactor, tokenOutNormFactor float64
+		for _, assetConfig := range alloyTransmuter.AssetConfigs {
+			if assetConfig.Denom == tokenInDenom {
+				tokenInNormFactor = intToFloat(assetConfig.NormalizationFactor)
+			}
+			if assetConfig.Denom == tokenOutDenom {
+				tokenOutNormFactor = intToFloat(assetConfig.NormalizationFactor)
+			}
+		}
+		if tokenInNormFactor == 0 {
+			return 0, false
+		}
+		rate = tokenOutNormFactor / tokenInNormFactor
+	case domain.CandidateRoutePoolTypeOrderbook:
+		orderbook := pool.SQSModel.CosmWasmPoolModel.Data.Orderbook
+		if orderbook == nil {
+			return 0, false
+		}
+
+		amountToExhaust := orderbook.BidAmountToExhaustAskLiquidity
+		if tokenInDenom == orderbook.BaseDenom {
+			amountToExhaust = orderbook.AskAmountToExhaustBidLiquidity
+		}
+		if amountToExhaust.IsNil() {
+			return 0, false
+		}
+
+		var amountToExhaustFloat float64
+		amountToExhaustFloat, err = strconv.ParseFloat(amountToExhaust.String(), 64)
+		if amountToExhaustFloat == 0 {
+			return 0, false
+		}
+		rate = intToFloat(pool.SQSModel.Balances.AmountOf(tokenOutDenom)) / amountToExhaustFloat
+	default:
+		tokenInBalance := intToFloat(pool.SQSModel.Balances.AmountOf(tokenInDenom))
+		if tokenInBalance == 0 {
+			return 0, false
+		}
+		rate = intToFloat(pool.SQSModel.Balances.AmountOf(tokenOutDenom)) / tokenInBalance
+	}
+
+	if err != nil {
+		return 0, false
+	}
+
+	if spreadFactor := pool.SQSModel.SpreadFactor; !spreadFactor.IsNil() {
+		spreadFactorFloat, err := strconv.ParseFloat(spreadFactor.String(), 64)
+		if err != nil {
+			return 0, false
+		}
+		rate *= 1 - spreadFactorFloat
+	}
+
+	if rate <= 0 || math.IsInf(rate, 0) || math.IsNaN(rate) {
+		return 0, false
+	}
+
+	return rate, true
+}
+
+// intToFloat returns the float approximation of the given integer. Zero if nil.
+func intToFloat(i osmomath.Int) float64 {
+	if i.IsNil() {
+		return 0
+	}
+	f, _ := strconv.ParseFloat(i.String(), 64)
+	return f
+}
@@ -0,0 +1,193 @@
+package usecase_test
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	poolmanagertypes "github.com/osmosis-labs/osmosis/v25/x/poolmanager/types"
+	"github.com/osmosis-labs/sqs/domain"
+	"github.com/osmosis-labs/sqs/domain/mocks"
+	"github.com/osmosis-labs/sqs/router/usecase"
+	"github.com/osmosis-labs/sqs/sqsdomain"
+)
+
+const (
+	priceGraphDenomA = "denomA"
+	priceGraphDenomB = "denomB"
+	priceGraphDenomC = "denomC"
+	priceGraphDenomD = "denomD"
+)
+
+// newPriceGraphPool returns the generalized cosmwasm pool with the given balances.
+// The rate of the pool estimated by the price-aware search is the ratio of the balances.
+func newPriceGraphPool(poolID uint64, balances ...sdk.Coin) *sqsdomain.PoolWrapper {
+	coins := sdk.NewCoins(balances...)
+
+	poolDenoms := make([]string, 0, len(coins))
+	for _, coin := range coins {
+		poolDenoms = append(poolDenoms, coin.Denom)
+	}
+
+	return &sqsdomain.PoolWrapper{
+		ChainModel: &mocks.ChainPoolMock{
+			ID:   poolID,
+			Type: poolmanagertypes.CosmWasm,
+		},
+		SQSModel: sqsdomain.SQSPool{
+			PoolLiquidityCap: osmomath.NewInt(1_000_000),
+			Balances:         coins,
+			PoolDenoms:       poolDenoms,
+		},
+	}
+}
+
+// newPriceGraphSearchData returns the candidate route search data over the given pools
+// with the pools of each denom in the given order.
+func newPriceGraphSearchData(pools ...*sqsdomain.PoolWrapper) map[string]domain.CandidateRouteDenomData {
+	searchData := map[string]domain.CandidateRouteDenomData{}
+	for _, pool := range pools {
+		for _, denom := range pool.SQSModel.PoolDenoms {
+			denomData := searchData[denom]
+			denomData.SortedPools = append(denomData.SortedPools, pool)
+			searchData[denom] = denomData
+		}
+	}
+	return searchData
+}
+
+// Validates the k shortest paths found by the price-aware candidate route search over a synthetic pool graph
+// whose estimated route rates are all distinct:
+//
+//	A -> B -> C -> D through pools 2, 6 and 5: 2 * 1 * 1.1 = 2.2
+//	A -> B -> D through pools 2 and 3: 2 * 0.6 = 1.2
+//	A -> C -> D through pools 4 and 5: 1 * 1.1 = 1.1
+//	A -> D through pool 1: 1
+//	A -> D through pool 7: 0.9
+//	A -> C -> B -> D through pools 4, 6 and 3: 1 * 1 * 0.6 = 0.6
+//
+// The routes are expected in the decreasing order of the rates, bounded by the max number of routes
+// and the max number of pools per route.
+func (s *RouterTestSuite) TestPriceAwareCandidateRouteFinder_KShortestPaths() {
+	var (
+		poolOne   = newPriceGraphPool(1, sdk.NewInt64Coin(priceGraphDenomA, 1000), sdk.NewInt64Coin(priceGraphDenomD, 1000))
+		poolTwo   = newPriceGraphPool(2, sdk.NewInt64Coin(priceGraphDenomA, 1000), sdk.NewInt64Coin(priceGraphDenomB, 2000))
+		poolThree = newPriceGraphPool(3, sdk.NewInt64Coin(priceGraphDenomB, 1000), sdk.NewInt64Coin(priceGraphDenomD, 600))
+		poolFour  = newPriceGraphPool(4, sdk.NewInt64Coin(priceGraphDenomA, 1000), sdk.NewInt64Coin(priceGraphDenomC, 1000))
+		poolFive  = newPriceGraphPool(5, sdk.NewInt64Coin(priceGraphDenomC, 1000), sdk.NewInt64Coin(priceGraphDenomD, 1100))
+		poolSix   = newPriceGraphPool(6, sdk.NewInt64Coin(priceGraphDenomB, 1000), sdk.NewInt64Coin(priceGraphDenomC, 1000))
+		poolSeven = newPriceGraphPool(7, sdk.NewInt64Coin(priceGraphDenomA, 1000), sdk.NewInt64Coin(priceGraphDenomD, 900))
+
+		tokenIn = sdk.NewInt64Coin(priceGraphDenomA, 1)
+	)
+
+	newRoute := func(tokenOutDenoms []string, poolIDs ...uint64) sqsdomain.CandidateRoute {
+		route := sqsdomain.CandidateRoute{Pools: make([]sqsdomain.CandidatePool, 0, len(poolIDs))}
+		for i, poolID := range poolIDs {
+			route.Pools = append(route.Pools, sqsdomain.CandidatePool{ID: poolID, TokenOutDenom: tokenOutDenoms[i]})
+		}
+		return route
+	}
+
+	var (
+		routeABCD  = newRoute([]string{priceGraphDenomB, priceGraphDenomC, priceGraphDenomD}, 2, 6, 5)
+		routeABD   = newRoute([]string{priceGraphDenomB, priceGraphDenomD}, 2, 3)
+		routeACD   = newRoute([]string{priceGraphDenomC, priceGraphDenomD}, 4, 5)
+		routeADOne = newRoute([]string{priceGraphDenomD}, 1)
+		routeADTwo = newRoute([]string{priceGraphDenomD}, 7)
+		routeACBD  = newRoute([]string{priceGraphDenomC, priceGraphDenomB, priceGraphDenomD}, 4, 6, 3)
+	)
+
+	canonicalOrderbookRoute := newRoute([]string{priceGraphDenomD}, 1)
+	canonicalOrderbookRoute.IsCanonicalOrderboolRoute = true
+
+	tests := []struct {
+		name string
+
+		maxRoutes          int
+		maxPoolsPerRoute   int
+		isCanonicalPoolOne bool
+		filteredOutPoolOne bool
+
+		expectedRoutes []sqsdomain.CandidateRoute
+	}{
+		{
+			name:             "all routes",
+			maxRoutes:        10,
+			maxPoolsPerRoute: 3,
+
+			expectedRoutes: []sqsdomain.CandidateRoute{routeABCD, routeABD, routeACD, routeADOne, routeADTwo, routeACBD},
+		},
+		{
+			name:             "bounded by the max number of routes",
+			maxRoutes:        3,
+			maxPoolsPerRoute: 3,
+
+			expectedRoutes: []sqsdomain.CandidateRoute{routeABCD, routeABD, routeACD},
+		},
+		{
+			name:             "bounded by the max number of pools per route",
+			maxRoutes:        10,
+			maxPoolsPerRoute: 2,
+
+			expectedRoutes: []sqsdomain.CandidateRoute{routeABD, routeACD, routeADOne, routeADTwo},
+		},
+		{
+			name:             "single pool routes",
+			maxRoutes:        10,
+			maxPoolsPerRoute: 1,
+
+			expectedRoutes: []sqsdomain.CandidateRoute{routeADOne, routeADTwo},
+		},
+		{
+			name:               "canonical orderbook is injected as the first route and excluded from the others",
+			maxRoutes:          3,
+			maxPoolsPerRoute:   3,
+			isCanonicalPoolOne: true,
+
+			expectedRoutes: []sqsdomain.CandidateRoute{canonicalOrderbookRoute, routeABCD, routeABD},
+		},
+		{
+			name:               "filtered out canonical orderbook is excluded from all routes",
+			maxRoutes:          10,
+			maxPoolsPerRoute:   1,
+			isCanonicalPoolOne: true,
+			filteredOutPoolOne: true,
+
+			expectedRoutes: []sqsdomain.CandidateRoute{routeADTwo},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		s.Run(tc.name, func() {
+			searchData := newPriceGraphSearchData(poolOne, poolTwo, poolThree, poolFour, poolFive, poolSix, poolSeven)
+
+			if tc.isCanonicalPoolOne {
+				denomData := searchData[priceGraphDenomA]
+				denomData.CanonicalOrderbooks = map[string]sqsdomain.PoolI{priceGraphDenomD: poolOne}
+				searchData[priceGraphDenomA] = denomData
+			}
+
+			options := domain.CandidateRouteSearchOptions{
+				MaxRoutes:        tc.maxRoutes,
+				MaxPoolsPerRoute: tc.maxPoolsPerRoute,
+			}
+
+			if tc.filteredOutPoolOne {
+				options.PoolFiltersAnyOf = []domain.CandidateRoutePoolFiltrerCb{
+					domain.CandidateRoutePoolIDFilterOptionCb{PoolIDsToSkip: map[uint64]struct{}{poolOne.GetId(): {}}}.ShouldSkipPool,
+				}
+			}
+
+			finder := usecase.NewPriceAwareCandidateRouteFinder(&mocks.CandidateRouteSearchDataHolderMock{
+				CandidateRouteSearchData: searchData,
+			}, domain.CosmWasmPoolRouterConfig{}, noOpLogger)
+
+			// System under test
+			candidateRoutes, err := finder.FindCandidateRoutes(tokenIn, priceGraphDenomD, options)
+			s.Require().NoError(err)
+
+			s.Require().Equal(tc.expectedRoutes, candidateRoutes.Routes)
+		})
+	}
+}
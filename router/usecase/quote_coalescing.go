@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/osmosis-labs/sqs/domain"
+	"github.com/osmosis-labs/sqs/router/usecase/route"
+	"github.com/osmosis-labs/sqs/sqsdomain"
+)
+
+const (
+	// coalescingResultComputed is the result of computing the ranked routes as the leader.
+	coalescingResultComputed = "computed"
+	// coalescingResultShared is the result of reusing the ranked routes computed by the leader.
+	coalescingResultShared = "shared"
+	// coalescingResultFallback is the result of computing the ranked routes again after the leader failed.
+	coalescingResultFallback = "fallback"
+)
+
+// coalescedComputeAndRankRoutesByDirectQuote computes candidate routes and ranks them by token out
+// after estimating direct quotes, coalescing the concurrent computations with the same ranked route cache key.
+// Only one of the concurrent callers computes the ranked routes. The others wait for it and then quote the shared
+// ranked routes with their own token in as if the ranked route cache was hit.
+// If the shared computation fails or is cut by the compute deadline, the waiting callers compute the ranked routes themselves
+// since the failure may be due to the context of the computing caller.
+//
+// The computations that bypass the route caches, rank by the gas cost, are bounded by a compute deadline set by the request
+// or record the explanation are not coalesced since they depend on the request options that the cache key does not capture.
+// In particular, the requests with a shorter deadline than the configured one must not make the others wait for a ranking
+// that is then cut. The requests bounded by the configured deadline alone are coalesced.
+func (r *routerUseCaseImpl) coalescedComputeAndRankRoutesByDirectQuote(ctx context.Context, tokenIn sdk.Coin, tokenOutDenom string, routingOptions domain.RouterOptions, explainer *quoteExplainer, gasRanker *gasCostRanker) (domain.Quote, []route.RouteImpl, error) {
+	isRequestComputeDeadline := routingOptions.ComputeDeadline != time.Duration(r.defaultConfig.QuoteComputeDeadlineMs)*time.Millisecond
+
+	if routingOptions.DisableCache || gasRanker != nil || routingOptions.Explain != nil || isRequestComputeDeadline {
+		return r.computeAndRankRoutesByDirectQuote(ctx, tokenIn, tokenOutDenom, routingOptions, explainer, gasRanker)
+	}
+
+	key := formatRankedRouteCacheKey(tokenIn.Denom, tokenOutDenom, GetPrecomputeOrderOfMagnitude(tokenIn.Amount))
+
+	var (
+		isLeader            bool
+		topSingleRouteQuote domain.Quote
+		rankedRoutes        []route.RouteImpl
+		err                 error
+	)
+
+	// The callback is only run by the leader in its own goroutine.
+	sharedRoutes, sharedErr, _ := r.rankedRoutesGroup.Do(key, func() (interface{}, error) {
+		isLeader = true
+
+		topSingleRouteQuote, rankedRoutes, err = r.computeAndRankRoutesByDirectQuote(ctx, tokenIn, tokenOutDenom, routingOptions, explainer, gasRanker)
+		if err != nil {
+			return nil, err
+		}
+
+		// The ranking cut by the compute deadline misses the routes that were not quoted.
+		if isPartialQuote(topSingleRouteQuote) {
+			return nil, domain.ErrComputeDeadlineExceeded
+		}
+
+		return convertRankedToCandidateRoutes(rankedRoutes), nil
+	})
+
+	if isLeader {
+		domain.SQSRouterRankedRoutesCoalescingCounter.WithLabelValues(coalescingResultComputed).Inc()
+		return topSingleRouteQuote, rankedRoutes, err
+	}
+
+	if sharedErr != nil {
+		domain.SQSRouterRankedRoutesCoalescingCounter.WithLabelValues(coalescingResultFallback).Inc()
+		return r.computeAndRankRoutesByDirectQuote(ctx, tokenIn, tokenOutDenom, routingOptions, explainer, gasRanker)
+	}
+
+	domain.SQSRouterRankedRoutesCoalescingCounter.WithLabelValues(coalescingResultShared).Inc()
+
+	return r.rankRoutesByDirectQuote(ctx, sharedRoutes.(sqsdomain.CandidateRoutes), tokenIn, tokenOutDenom, routingOptions.MaxSplitRoutes, explainer, gasRanker)
+}
@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	"github.com/osmosis-labs/sqs/domain"
+	"github.com/osmosis-labs/sqs/router/usecase/route"
+)
+
+// quoteExplainer populates the quote explain while the quote is computed.
+// All methods are no-ops on the nil explainer so that the quote computation
+// does not have to check whether the explain was requested.
+type quoteExplainer struct {
+	explain *domain.QuoteExplain
+	// routeIndexes maps the route key to the index of the route in the explain routes.
+	routeIndexes map[string]int
+}
+
+// newQuoteExplainer returns the explainer populating the given explain.
+// Returns nil if the explain is nil.
+func newQuoteExplainer(explain *domain.QuoteExplain) *quoteExplainer {
+	if explain == nil {
+		return nil
+	}
+
+	return &quoteExplainer{
+		explain:      explain,
+		routeIndexes: make(map[string]int),
+	}
+}
+
+// setCandidateRoutesCacheHit records whether the candidate routes were read from cache.
+func (e *quoteExplainer) setCandidateRoutesCacheHit(isCacheHit bool) {
+	if e == nil {
+		return
+	}
+	e.explain.CandidateRoutesCacheHit = isCacheHit
+}
+
+// setRankedRoutesCacheHit records whether the ranked routes were read from cache.
+func (e *quoteExplainer) setRankedRoutesCacheHit(isCacheHit bool) {
+	if e == nil {
+		return
+	}
+	e.explain.RankedRoutesCacheHit = isCacheHit
+}
+
+// setMinPoolLiquidityCap records the min pool liquidity cap applied by the candidate route search.
+func (e *quoteExplainer) setMinPoolLiquidityCap(minPoolLiquidityCap uint64) {
+	if e == nil {
+		return
+	}
+	e.explain.MinPoolLiquidityCap = minPoolLiquidityCap
+}
+
+// liquidityFilteredPoolCb returns the callback recording the pools skipped by the candidate route search
+// for insufficient liquidity. Returns nil on the nil explainer.
+func (e *quoteExplainer) liquidityFilteredPoolCb() func(poolID uint64) {
+	if e == nil {
+		return nil
+	}
+
+	return func(poolID uint64) {
+		e.explain.LiquidityFilteredPoolIDs = append(e.explain.LiquidityFilteredPoolIDs, poolID)
+	}
+}
+
+// recordRouteQuote records the direct quote over the route.
+// If err is non-nil, the route is recorded as dropped due to the calculation error.
+func (e *quoteExplainer) recordRouteQuote(r domain.Route, amountIn, amountOut osmomath.Int, err error) {
+	if e == nil {
+		return
+	}
+
+	explainRoute := domain.ExplainRoute{
+		PoolIDs:   getRoutePoolIDs(r.GetPools()),
+		AmountIn:  amountIn,
+		AmountOut: amountOut,
+	}
+
+	if explainRoute.AmountIn.IsNil() {
+		explainRoute.AmountIn = osmomath.ZeroInt()
+	}
+
+	if explainRoute.AmountOut.IsNil() {
+		explainRoute.AmountOut = osmomath.ZeroInt()
+	}
+
+	if err != nil {
+		explainRoute.DropReason = domain.RouteDropReasonCalculationError
+		explainRoute.Error = err.Error()
+	}
+
+	e.routeIndexes[formatExplainRouteKey(explainRoute.PoolIDs)] = len(e.explain.Routes)
+	e.explain.Routes = append(e.explain.Routes, explainRoute)
+}
+
+// recordDroppedRoutes records all routes that are neither dropped yet nor in the kept routes
+// as dropped for the given reason.
+func (e *quoteExplainer) recordDroppedRoutes(keptRoutes []route.RouteImpl, reason domain.RouteDropReason) {
+	if e == nil {
+		return
+	}
+
+	keptRouteIndexes := make(map[int]struct{}, len(keptRoutes))
+	for _, keptRoute := range keptRoutes {
+		if i, ok := e.routeIndexes[formatExplainRouteKey(getRoutePoolIDs(keptRoute.GetPools()))]; ok {
+			keptRouteIndexes[i] = struct{}{}
+		}
+	}
+
+	for i := range e.explain.Routes {
+		if _, ok := keptRouteIndexes[i]; ok || e.explain.Routes[i].DropReason != "" {
+			continue
+		}
+		e.explain.Routes[i].DropReason = reason
+	}
+}
+
+// recordSplitQuote records the split quote computed over the given routes.
+// The proportions are of the token in for the exact amount in and of the token out for the exact amount out swap method.
+// If err is non-nil, only the error is recorded.
+func (e *quoteExplainer) recordSplitQuote(routes []domain.SplitRoute, swapMethod domain.TokenSwapMethod, isSelected bool, err error) {
+	if e == nil {
+		return
+	}
+
+	if err != nil {
+		e.explain.Split = &domain.ExplainSplit{Error: err.Error()}
+		return
+	}
+
+	getSplitAmount := func(splitRoute domain.SplitRoute) osmomath.Int {
+		if swapMethod == domain.TokenSwapMethodExactIn {
+			return splitRoute.GetAmountIn()
+		}
+		return splitRoute.GetAmountOut()
+	}
+
+	totalAmount := osmomath.ZeroInt()
+	for _, splitRoute := range routes {
+		totalAmount = totalAmount.Add(getSplitAmount(splitRoute))
+	}
+
+	split := &domain.ExplainSplit{
+		Selected: isSelected,
+		Routes:   make([]domain.ExplainSplitRoute, 0, len(routes)),
+	}
+
+	for _, splitRoute := range routes {
+		proportion := osmomath.ZeroDec()
+		if !totalAmount.IsZero() {
+			proportion = getSplitAmount(splitRoute).ToLegacyDec().QuoMut(totalAmount.ToLegacyDec())
+		}
+
+		split.Routes = append(split.Routes, domain.ExplainSplitRoute{
+			PoolIDs:    getRoutePoolIDs(splitRoute.GetPools()),
+			AmountIn:   splitRoute.GetAmountIn(),
+			AmountOut:  splitRoute.GetAmountOut(),
+			Proportion: proportion,
+		})
+	}
+
+	e.explain.Split = split
+}
+
+// getRoutePoolIDs returns the IDs of the given pools.
+func getRoutePoolIDs(pools []domain.RoutablePool) []uint64 {
+	poolIDs := make([]uint64, 0, len(pools))
+	for _, pool := range pools {
+		poolIDs = append(poolIDs, pool.GetId())
+	}
+	return poolIDs
+}
+
+// formatExplainRouteKey formats the key identifying the route by its pool IDs.
+func formatExplainRouteKey(poolIDs []uint64) string {
+	var sb strings.Builder
+	for i, poolID := range poolIDs {
+		if i > 0 {
+			sb.WriteString("/")
+		}
+		sb.WriteString(strconv.FormatUint(poolID, 10))
+	}
+	return sb.String()
+}
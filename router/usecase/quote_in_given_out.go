@@ -6,10 +6,13 @@ import (
 	"github.com/osmosis-labs/sqs/domain"
 	"github.com/osmosis-labs/sqs/log"
 	"github.com/osmosis-labs/sqs/router/types"
+	"github.com/osmosis-labs/sqs/router/usecase/route"
 
 	"github.com/osmosis-labs/osmosis/osmomath"
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"go.uber.org/zap"
 )
 
 var (
@@ -26,6 +29,8 @@ type quoteExactAmountOut struct {
 	EffectiveFee            osmomath.Dec        "json:\"effective_fee\""
 	PriceImpact             osmomath.Dec        "json:\"price_impact\""
 	InBaseOutQuoteSpotPrice osmomath.Dec        "json:\"in_base_out_quote_spot_price\""
+	QuoteID                 string              "json:\"quote_id,omitempty\""
+	Height                  uint64              "json:\"height,omitempty\""
 }
 
 // PrepareResult implements domain.Quote.
@@ -37,6 +42,32 @@ type quoteExactAmountOut struct {
 //
 // Returns the updated route and the effective spread factor.
 func (q *quoteExactAmountOut) PrepareResult(ctx context.Context, scalingFactor osmomath.Dec, logger log.Logger) ([]domain.SplitRoute, osmomath.Dec, error) {
+	// Track the pools that do not support the exact amount out swap API before
+	// they are stripped away so that they can be flagged in the result.
+	executeAsExactIn := make([][]bool, len(q.quoteExactAmountIn.Route))
+	// The breakdowns computed by the exact amount in result preparation are of the inverted swap.
+	// Compute them in the direction of the exact amount out swap instead.
+	swapBreakdowns := make([][]*domain.PoolSwapBreakdown, len(q.quoteExactAmountIn.Route))
+	for i, route := range q.quoteExactAmountIn.Route {
+		routePools := route.GetPools()
+		executeAsExactIn[i] = make([]bool, len(routePools))
+		for j, pool := range routePools {
+			executeAsExactIn[i][j] = pool.GetSQSType() == domain.Orderbook
+		}
+
+		routeWithAmount, ok := route.(*RouteWithOutAmount)
+		if !ok {
+			return nil, osmomath.Dec{}, types.ErrInvalidRouteType
+		}
+
+		// The breakdowns are best-effort. We do not fail the quote if they fail to compute.
+		breakdowns, err := routeWithAmount.PrepareSwapBreakdownsExactOut(ctx, sdk.NewCoin(q.quoteExactAmountIn.AmountIn.Denom, routeWithAmount.GetAmountIn()), logger)
+		if err != nil {
+			logger.Error("failed to prepare exact out swap breakdowns", zap.Error(err))
+		}
+		swapBreakdowns[i] = breakdowns
+	}
+
 	// Prepare exact out in the quote for inputs inversion
 	if _, _, err := q.quoteExactAmountIn.PrepareResult(ctx, scalingFactor, logger); err != nil {
 		return nil, osmomath.Dec{}, err
@@ -62,11 +93,96 @@ func (q *quoteExactAmountOut) PrepareResult(ctx context.Context, scalingFactor o
 		q.Route[i] = route
 
 		// invert the in and out amounts for each pool
-		for _, p := range route.GetPools() {
+		for j, p := range route.GetPools() {
 			p.SetTokenInDenom(p.GetTokenOutDenom())
 			p.SetTokenOutDenom("")
+
+			resultPool, ok := p.(domain.RoutableResultPool)
+			if !ok {
+				return nil, osmomath.Dec{}, types.ErrInvalidRouteType
+			}
+
+			// Nil if the breakdowns of the route failed to compute.
+			var swapBreakdown *domain.PoolSwapBreakdown
+			if swapBreakdowns[i] != nil {
+				swapBreakdown = swapBreakdowns[i][j]
+			}
+			resultPool.SetSwapBreakdown(swapBreakdown)
+
+			if executeAsExactIn[i][j] {
+				resultPool.SetExecuteAsExactIn(true)
+			}
 		}
 	}
 
 	return q.Route, q.EffectiveFee, nil
 }
+
+// SetQuoteID implements domain.Quote.
+// The exact out quote marshals its own fields rather than the ones of the embedded quote.
+func (q *quoteExactAmountOut) SetQuoteID(quoteID string, height uint64) {
+	q.QuoteID = quoteID
+	q.Height = height
+}
+
+// GetQuoteID implements domain.Quote.
+func (q *quoteExactAmountOut) GetQuoteID() string {
+	return q.QuoteID
+}
+
+// GetHeight implements domain.Quote.
+func (q *quoteExactAmountOut) GetHeight() uint64 {
+	return q.Height
+}
+
+// convertToQuoteExactAmountOut converts the given quote computed over the routes
+// from token in to token out into the exact amount out quote.
+// The routes are reversed to start from the token out denom and the in and out amounts are swapped.
+// As a result, the quote has the same layout as the one expected by PrepareResult.
+// Note that it mutates the pools in the routes.
+// Returns error if any of the routes is not of RouteWithOutAmount type.
+func convertToQuoteExactAmountOut(q *quoteExactAmountIn, tokenOutDenom string) (*quoteExactAmountOut, error) {
+	reversedRoutes := make([]domain.SplitRoute, 0, len(q.Route))
+	for _, splitRoute := range q.Route {
+		routeWithAmount, ok := splitRoute.(*RouteWithOutAmount)
+		if !ok {
+			return nil, types.ErrInvalidRouteType
+		}
+
+		reversedRoutes = append(reversedRoutes, &RouteWithOutAmount{
+			RouteImpl: reverseRoute(routeWithAmount.RouteImpl),
+			InAmount:  routeWithAmount.OutAmount,
+			OutAmount: routeWithAmount.InAmount,
+		})
+	}
+
+	return &quoteExactAmountOut{
+		quoteExactAmountIn: &quoteExactAmountIn{
+			AmountIn:  sdk.NewCoin(tokenOutDenom, q.AmountOut),
+			AmountOut: q.AmountIn.Amount,
+			Route:     reversedRoutes,
+		},
+	}, nil
+}
+
+// reverseRoute returns the route with the pools in reverse order.
+// The token in and token out denoms of each pool are swapped.
+// Note that it mutates the pools in the route.
+func reverseRoute(r route.RouteImpl) route.RouteImpl {
+	reversedPools := make([]domain.RoutablePool, 0, len(r.Pools))
+	for i := len(r.Pools) - 1; i >= 0; i-- {
+		pool := r.Pools[i]
+
+		tokenInDenom := pool.GetTokenInDenom()
+		pool.SetTokenInDenom(pool.GetTokenOutDenom())
+		pool.SetTokenOutDenom(tokenInDenom)
+
+		reversedPools = append(reversedPools, pool)
+	}
+
+	return route.RouteImpl{
+		Pools:                      reversedPools,
+		HasGeneralizedCosmWasmPool: r.HasGeneralizedCosmWasmPool,
+		HasCanonicalOrderbookPool:  r.HasCanonicalOrderbookPool,
+	}
+}
@@ -42,6 +42,13 @@ type quoteExactAmountIn struct {
 	EffectiveFee            osmomath.Dec        "json:\"effective_fee\""
 	PriceImpact             osmomath.Dec        "json:\"price_impact\""
 	InBaseOutQuoteSpotPrice osmomath.Dec        "json:\"in_base_out_quote_spot_price\""
+	QuoteID                 string              "json:\"quote_id,omitempty\""
+	Height                  uint64              "json:\"height,omitempty\""
+	// GasEstimate is the estimated gas cost of the quote. Only set for the gas-aware ranking.
+	GasEstimate *domain.GasEstimate "json:\"gas_estimate,omitempty\""
+	// Partial is true if the compute deadline was exceeded before the quote computation finished.
+	// The quote is then the best one found within the deadline.
+	Partial bool "json:\"partial,omitempty\""
 }
 
 // PrepareResult implements domain.Quote.
@@ -151,3 +158,19 @@ func (q *quoteExactAmountIn) GetPriceImpact() osmomath.Dec {
 func (q *quoteExactAmountIn) GetInBaseOutQuoteSpotPrice() osmomath.Dec {
 	return q.InBaseOutQuoteSpotPrice
 }
+
+// SetQuoteID implements domain.Quote.
+func (q *quoteExactAmountIn) SetQuoteID(quoteID string, height uint64) {
+	q.QuoteID = quoteID
+	q.Height = height
+}
+
+// GetQuoteID implements domain.Quote.
+func (q *quoteExactAmountIn) GetQuoteID() string {
+	return q.QuoteID
+}
+
+// GetHeight implements domain.Quote.
+func (q *quoteExactAmountIn) GetHeight() uint64 {
+	return q.Height
+}
@@ -0,0 +1,359 @@
+package quoteaudit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	poolmanagertypes "github.com/osmosis-labs/osmosis/v25/x/poolmanager/types"
+	"github.com/osmosis-labs/sqs/domain"
+	"github.com/osmosis-labs/sqs/domain/mvc"
+	poolmanagergrpcclientdomain "github.com/osmosis-labs/sqs/domain/poolmanager/grpcclient"
+	"github.com/osmosis-labs/sqs/log"
+)
+
+const (
+	quoteAuditStageQuote = "quote"
+	quoteAuditStageRoute = "route"
+	quoteAuditStagePool  = "pool"
+)
+
+var errZeroChainAmountOut = errors.New("chain simulated zero amount out")
+
+type quoteAuditor struct {
+	routerUsecase     mvc.RouterUsecase
+	stateGuard        *domain.RouterStateGuard
+	poolManagerClient poolmanagergrpcclientdomain.PoolManagerClient
+	config            domain.QuoteAuditConfig
+	pairs             []domain.QuoteStreamRequest
+	logger            log.Logger
+
+	// isAuditing is true while an audit is in progress.
+	// The audits that are due while another one is in progress are skipped.
+	isAuditing atomic.Bool
+
+	mu               sync.Mutex
+	blocksSinceAudit uint64
+	sampledQuotes    []sampledQuote
+	worstDivergences []domain.QuoteAuditDivergence
+}
+
+// sampledQuote is the prepared quote audited against the state at the given height.
+type sampledQuote struct {
+	height uint64
+	quote  domain.Quote
+}
+
+var _ mvc.QuoteAuditor = &quoteAuditor{}
+
+// New returns a new quote auditor.
+// It must be registered as an end block process plugin with the ingest usecase to audit the quotes.
+// Returns error if any of the configured pairs is invalid.
+func New(routerUsecase mvc.RouterUsecase, stateGuard *domain.RouterStateGuard, poolManagerClient poolmanagergrpcclientdomain.PoolManagerClient, config domain.QuoteAuditConfig, logger log.Logger) (*quoteAuditor, error) {
+	pairs := make([]domain.QuoteStreamRequest, 0, len(config.Pairs))
+	for _, pair := range config.Pairs {
+		tokenIn, err := sdk.ParseCoinNormalized(pair.TokenIn)
+		if err != nil {
+			return nil, fmt.Errorf("invalid quote audit pair token in (%s): %w", pair.TokenIn, err)
+		}
+
+		if pair.TokenOutDenom == "" {
+			return nil, fmt.Errorf("quote audit pair token out denom must be non-empty for token in (%s)", pair.TokenIn)
+		}
+
+		pairs = append(pairs, domain.QuoteStreamRequest{
+			TokenIn:       tokenIn,
+			TokenOutDenom: pair.TokenOutDenom,
+		})
+	}
+
+	return &quoteAuditor{
+		routerUsecase:     routerUsecase,
+		stateGuard:        stateGuard,
+		poolManagerClient: poolManagerClient,
+		config:            config,
+		pairs:             pairs,
+		logger:            logger,
+
+		sampledQuotes:    []sampledQuote{},
+		worstDivergences: []domain.QuoteAuditDivergence{},
+	}, nil
+}
+
+// SampleQuote implements mvc.QuoteAuditor.
+func (a *quoteAuditor) SampleQuote(quote domain.Quote) {
+	if a.config.ServedQuoteSampleRate <= 0 || rand.Float64() >= a.config.ServedQuoteSampleRate {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if len(a.sampledQuotes) >= a.config.MaxSampledQuotes {
+		domain.SQSQuoteAuditDroppedSamplesCounter.Inc()
+		return
+	}
+
+	// The quote is stamped with the height of the state it was computed against when served.
+	// The quotes that are not stamped cannot be simulated at the same state so they are not sampled.
+	height := quote.GetHeight()
+	if height == 0 {
+		return
+	}
+
+	a.sampledQuotes = append(a.sampledQuotes, sampledQuote{
+		height: height,
+		quote:  quote,
+	})
+}
+
+// GetWorstDivergences implements mvc.QuoteAuditor.
+func (a *quoteAuditor) GetWorstDivergences() []domain.QuoteAuditDivergence {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	worstDivergences := make([]domain.QuoteAuditDivergence, len(a.worstDivergences))
+	copy(worstDivergences, a.worstDivergences)
+	return worstDivergences
+}
+
+// ProcessEndBlock implements domain.EndBlockProcessPlugin.
+// Every configured number of blocks, it audits the configured pairs and the quotes sampled since the previous audit.
+func (a *quoteAuditor) ProcessEndBlock(ctx context.Context, _ uint64, _ domain.BlockPoolMetadata) error {
+	a.mu.Lock()
+	a.blocksSinceAudit++
+	isAuditDue := a.blocksSinceAudit >= a.config.IntervalBlocks
+	a.mu.Unlock()
+
+	if !isAuditDue {
+		return nil
+	}
+
+	// The end block plugins are executed concurrently across blocks.
+	// Skip the audit rather than piling up the chain queries if the previous one is still in progress.
+	if !a.isAuditing.CompareAndSwap(false, true) {
+		return nil
+	}
+	defer a.isAuditing.Store(false)
+
+	a.mu.Lock()
+	a.blocksSinceAudit = 0
+	quotes := a.sampledQuotes
+	a.sampledQuotes = []sampledQuote{}
+	a.mu.Unlock()
+
+	quotes = append(quotes, a.computePairQuotes(ctx)...)
+
+	divergences := make([]domain.QuoteAuditDivergence, 0, len(quotes))
+	for _, sampled := range quotes {
+		divergences = append(divergences, a.auditQuote(ctx, sampled)...)
+	}
+
+	worstDivergences := getWorstDivergences(divergences, a.config.MaxWorstDivergences)
+
+	a.mu.Lock()
+	a.worstDivergences = worstDivergences
+	a.mu.Unlock()
+
+	return nil
+}
+
+// computePairQuotes computes and prepares the quotes of the configured pairs against the latest router state.
+// The quotes are stamped with the height of the router state read under the router state guard
+// since the end blocks may be processed concurrently with the ingest of the next block.
+// The computation holding the guard is bounded by the pairs compute timeout so that it does not stall the ingest.
+// The pairs that fail to be quoted or are not quoted in time are skipped.
+func (a *quoteAuditor) computePairQuotes(ctx context.Context) []sampledQuote {
+	// Compute over a consistent view of the router state.
+	a.stateGuard.RLock()
+	defer a.stateGuard.RUnlock()
+
+	height := a.stateGuard.GetHeight()
+
+	if a.config.PairsComputeTimeoutMs > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, time.Duration(a.config.PairsComputeTimeoutMs)*time.Millisecond, domain.ErrComputeDeadlineExceeded)
+		defer cancel()
+	}
+
+	quotes := make([]sampledQuote, 0, len(a.pairs))
+	for i, pair := range a.pairs {
+		if ctx.Err() != nil {
+			a.logger.Debug("skipped audited quotes", zap.Int("num_skipped", len(a.pairs)-i), zap.Error(context.Cause(ctx)))
+			break
+		}
+
+		quote, err := a.routerUsecase.GetOptimalQuote(ctx, pair.TokenIn, pair.TokenOutDenom)
+		if err == nil {
+			_, _, err = quote.PrepareResult(ctx, osmomath.OneDec(), a.logger)
+		}
+
+		if err != nil {
+			domain.SQSQuoteAuditErrorsCounter.WithLabelValues(quoteAuditStageQuote).Inc()
+			a.logger.Debug("failed to compute audited quote", zap.Stringer("token_in", pair.TokenIn), zap.String("token_out_denom", pair.TokenOutDenom), zap.Error(err))
+			continue
+		}
+
+		quotes = append(quotes, sampledQuote{
+			height: height,
+			quote:  quote,
+		})
+	}
+
+	return quotes
+}
+
+// auditQuote simulates each route of the exact amount in quote and each pool within the routes against the chain
+// and records the divergences. Returns the divergences of the pools.
+func (a *quoteAuditor) auditQuote(ctx context.Context, sampled sampledQuote) []domain.QuoteAuditDivergence {
+	tokenInDenom := sampled.quote.GetAmountIn().Denom
+
+	divergences := []domain.QuoteAuditDivergence{}
+	for _, route := range sampled.quote.GetRoute() {
+		pools := route.GetPools()
+
+		swapRoutes := make([]poolmanagertypes.SwapAmountInRoute, 0, len(pools))
+		for _, pool := range pools {
+			swapRoutes = append(swapRoutes, poolmanagertypes.SwapAmountInRoute{
+				PoolId:        pool.GetId(),
+				TokenOutDenom: pool.GetTokenOutDenom(),
+			})
+		}
+
+		routeTokenIn := sdk.NewCoin(tokenInDenom, route.GetAmountIn())
+		queryCtx, cancel := a.withChainQueryTimeout(ctx)
+		chainAmountOut, err := a.poolManagerClient.EstimateSwapExactAmountIn(queryCtx, sampled.height, routeTokenIn, swapRoutes)
+		cancel()
+		if err == nil {
+			var divergence osmomath.Dec
+			divergence, err = computeDivergence(route.GetAmountOut(), chainAmountOut)
+			if err == nil {
+				domain.SQSQuoteAuditRouteDivergenceHistogram.Observe(divergence.Abs().MustFloat64())
+			}
+		}
+
+		if err != nil {
+			domain.SQSQuoteAuditErrorsCounter.WithLabelValues(quoteAuditStageRoute).Inc()
+			a.logger.Debug("failed to audit route", zap.Uint64("height", sampled.height), zap.Stringer("token_in", routeTokenIn), zap.Any("routes", swapRoutes), zap.Error(err))
+		}
+
+		for _, pool := range pools {
+			resultPool, ok := pool.(domain.RoutableResultPool)
+			if !ok {
+				continue
+			}
+
+			breakdown := resultPool.GetSwapBreakdown()
+			if breakdown == nil {
+				continue
+			}
+
+			divergence, err := a.auditPool(ctx, sampled.height, pool, breakdown)
+			if err != nil {
+				domain.SQSQuoteAuditErrorsCounter.WithLabelValues(quoteAuditStagePool).Inc()
+				a.logger.Debug("failed to audit pool", zap.Uint64("height", sampled.height), zap.Uint64("pool_id", pool.GetId()), zap.Stringer("token_in", breakdown.TokenIn), zap.Error(err))
+				continue
+			}
+
+			domain.SQSQuoteAuditPoolDivergenceHistogram.WithLabelValues(divergence.PoolType).Observe(divergence.Divergence.Abs().MustFloat64())
+
+			divergences = append(divergences, divergence)
+		}
+	}
+
+	return divergences
+}
+
+// auditPool simulates the swap through the pool described by the breakdown against the chain.
+// The single pool simulation does not charge the taker fee so the token in is simulated net of it.
+func (a *quoteAuditor) auditPool(ctx context.Context, height uint64, pool domain.RoutablePool, breakdown *domain.PoolSwapBreakdown) (domain.QuoteAuditDivergence, error) {
+	tokenInAfterTakerFee := sdk.NewCoin(breakdown.TokenIn.Denom, breakdown.TokenIn.Amount.Sub(breakdown.TakerFeeCharged.Amount))
+
+	queryCtx, cancel := a.withChainQueryTimeout(ctx)
+	defer cancel()
+
+	chainAmountOut, err := a.poolManagerClient.EstimateSinglePoolSwapExactAmountIn(queryCtx, height, pool.GetId(), tokenInAfterTakerFee, breakdown.TokenOut.Denom)
+	if err != nil {
+		return domain.QuoteAuditDivergence{}, err
+	}
+
+	divergence, err := computeDivergence(breakdown.TokenOut.Amount, chainAmountOut)
+	if err != nil {
+		return domain.QuoteAuditDivergence{}, err
+	}
+
+	return domain.QuoteAuditDivergence{
+		Height:         height,
+		PoolID:         pool.GetId(),
+		PoolType:       pool.GetType().String(),
+		TokenIn:        breakdown.TokenIn.String(),
+		TokenOutDenom:  breakdown.TokenOut.Denom,
+		SQSAmountOut:   breakdown.TokenOut.Amount,
+		ChainAmountOut: chainAmountOut,
+		Divergence:     divergence,
+	}, nil
+}
+
+// withChainQueryTimeout returns the context of a single chain simulation query bounded by the chain query timeout.
+// If the timeout is not positive, the given context is returned as is.
+func (a *quoteAuditor) withChainQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if a.config.ChainQueryTimeoutMs <= 0 {
+		return ctx, func() {}
+	}
+
+	return context.WithTimeout(ctx, time.Duration(a.config.ChainQueryTimeoutMs)*time.Millisecond)
+}
+
+// computeDivergence returns the SQS amount out relative to the chain amount out, minus one.
+// Returns error if the chain amount out is zero while the SQS amount out is not.
+func computeDivergence(sqsAmountOut, chainAmountOut osmomath.Int) (osmomath.Dec, error) {
+	if chainAmountOut.IsZero() {
+		if sqsAmountOut.IsZero() {
+			return osmomath.ZeroDec(), nil
+		}
+		return osmomath.Dec{}, errZeroChainAmountOut
+	}
+
+	return sqsAmountOut.ToLegacyDec().QuoMut(chainAmountOut.ToLegacyDec()).SubMut(osmomath.OneDec()), nil
+}
+
+// getWorstDivergences returns at most limit divergences with the largest absolute value,
+// keeping only the largest one per pool, sorted in descending order.
+func getWorstDivergences(divergences []domain.QuoteAuditDivergence, limit int) []domain.QuoteAuditDivergence {
+	worstByPoolID := make(map[uint64]domain.QuoteAuditDivergence, len(divergences))
+	for _, divergence := range divergences {
+		worst, ok := worstByPoolID[divergence.PoolID]
+		if !ok || divergence.Divergence.Abs().GT(worst.Divergence.Abs()) {
+			worstByPoolID[divergence.PoolID] = divergence
+		}
+	}
+
+	worstDivergences := make([]domain.QuoteAuditDivergence, 0, len(worstByPoolID))
+	for _, divergence := range worstByPoolID {
+		worstDivergences = append(worstDivergences, divergence)
+	}
+
+	sort.Slice(worstDivergences, func(i, j int) bool {
+		absI, absJ := worstDivergences[i].Divergence.Abs(), worstDivergences[j].Divergence.Abs()
+		if !absI.Equal(absJ) {
+			return absI.GT(absJ)
+		}
+		return worstDivergences[i].PoolID < worstDivergences[j].PoolID
+	})
+
+	if len(worstDivergences) > limit {
+		worstDivergences = worstDivergences[:limit]
+	}
+
+	return worstDivergences
+}
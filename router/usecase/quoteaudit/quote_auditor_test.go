@@ -0,0 +1,368 @@
+package quoteaudit_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	poolmanagertypes "github.com/osmosis-labs/osmosis/v25/x/poolmanager/types"
+	"github.com/osmosis-labs/sqs/domain"
+	"github.com/osmosis-labs/sqs/domain/mocks"
+	"github.com/osmosis-labs/sqs/log"
+	"github.com/osmosis-labs/sqs/router/usecase"
+	"github.com/osmosis-labs/sqs/router/usecase/pools"
+	"github.com/osmosis-labs/sqs/router/usecase/quoteaudit"
+	"github.com/osmosis-labs/sqs/router/usecase/route"
+)
+
+const (
+	denomA = "denomA"
+	denomB = "denomB"
+	denomC = "denomC"
+
+	defaultHeight = uint64(100)
+)
+
+var (
+	errEstimate = errors.New("failed to estimate")
+
+	defaultConfig = domain.QuoteAuditConfig{
+		Enabled:        true,
+		IntervalBlocks: 1,
+		Pairs: []domain.QuoteAuditPairConfig{
+			{TokenIn: "1000" + denomA, TokenOutDenom: denomC},
+		},
+		ServedQuoteSampleRate: 1,
+		MaxSampledQuotes:      1,
+		MaxWorstDivergences:   10,
+	}
+)
+
+// quoteMock is the exact amount in quote over the given route with no-op result preparation.
+type quoteMock struct {
+	domain.Quote
+	amountIn sdk.Coin
+	routes   []domain.SplitRoute
+	height   uint64
+}
+
+// GetAmountIn implements domain.Quote.
+func (q *quoteMock) GetAmountIn() sdk.Coin {
+	return q.amountIn
+}
+
+// GetRoute implements domain.Quote.
+func (q *quoteMock) GetRoute() []domain.SplitRoute {
+	return q.routes
+}
+
+// GetHeight implements domain.Quote.
+func (q *quoteMock) GetHeight() uint64 {
+	return q.height
+}
+
+// PrepareResult implements domain.Quote.
+func (q *quoteMock) PrepareResult(ctx context.Context, scalingFactor osmomath.Dec, logger log.Logger) ([]domain.SplitRoute, osmomath.Dec, error) {
+	return q.routes, osmomath.ZeroDec(), nil
+}
+
+// poolSwap is the swap through a single pool of the quote route.
+type poolSwap struct {
+	poolID   uint64
+	poolType poolmanagertypes.PoolType
+	tokenIn  sdk.Coin
+	takerFee osmomath.Int
+	tokenOut sdk.Coin
+}
+
+// newQuoteMock returns the quote through a single route over the given pool swaps.
+func newQuoteMock(height uint64, swaps ...poolSwap) *quoteMock {
+	routePools := make([]domain.RoutablePool, 0, len(swaps))
+	for _, swap := range swaps {
+		pool := pools.NewRoutableResultPool(swap.poolID, swap.poolType, osmomath.ZeroDec(), swap.tokenOut.Denom, osmomath.ZeroDec(), 0)
+		pool.(domain.RoutableResultPool).SetSwapBreakdown(&domain.PoolSwapBreakdown{
+			TokenIn:         swap.tokenIn,
+			TokenOut:        swap.tokenOut,
+			TakerFeeCharged: sdk.NewCoin(swap.tokenIn.Denom, swap.takerFee),
+		})
+		routePools = append(routePools, pool)
+	}
+
+	return &quoteMock{
+		amountIn: swaps[0].tokenIn,
+		routes: []domain.SplitRoute{&usecase.RouteWithOutAmount{
+			RouteImpl: route.RouteImpl{Pools: routePools},
+			InAmount:  swaps[0].tokenIn.Amount,
+			OutAmount: swaps[len(swaps)-1].tokenOut.Amount,
+		}},
+		height: height,
+	}
+}
+
+// newChainStub returns the pool manager client stub that simulates the swaps
+// with the given amounts out per pool and records the requested heights and tokens in.
+func newChainStub(amountOutByPoolID map[uint64]int64, estimatedTokensIn map[uint64]sdk.Coin, estimatedHeights map[uint64]uint64) *mocks.PoolManagerGRPCClientMock {
+	return &mocks.PoolManagerGRPCClientMock{
+		EstimateSwapExactAmountInCb: func(ctx context.Context, height uint64, tokenIn sdk.Coin, routes []poolmanagertypes.SwapAmountInRoute) (osmomath.Int, error) {
+			return osmomath.NewInt(amountOutByPoolID[routes[len(routes)-1].PoolId]), nil
+		},
+		EstimateSinglePoolSwapExactAmountInCb: func(ctx context.Context, height uint64, poolID uint64, tokenIn sdk.Coin, tokenOutDenom string) (osmomath.Int, error) {
+			estimatedTokensIn[poolID] = tokenIn
+			estimatedHeights[poolID] = height
+
+			amountOut, ok := amountOutByPoolID[poolID]
+			if !ok {
+				return osmomath.Int{}, errEstimate
+			}
+			return osmomath.NewInt(amountOut), nil
+		},
+	}
+}
+
+// TestQuoteAuditor_ProcessEndBlock tests that the configured pairs and the sampled quotes are audited
+// against the chain simulation and that the worst divergences are reported per pool.
+func TestQuoteAuditor_ProcessEndBlock(t *testing.T) {
+	var (
+		ctx = context.Background()
+
+		estimatedTokensIn = map[uint64]sdk.Coin{}
+		estimatedHeights  = map[uint64]uint64{}
+	)
+
+	// The configured pair is quoted through pools 1 and 2.
+	pairQuote := newQuoteMock(0,
+		poolSwap{poolID: 1, poolType: poolmanagertypes.Balancer, tokenIn: sdk.NewCoin(denomA, osmomath.NewInt(1000)), takerFee: osmomath.NewInt(10), tokenOut: sdk.NewCoin(denomB, osmomath.NewInt(990))},
+		poolSwap{poolID: 2, poolType: poolmanagertypes.Concentrated, tokenIn: sdk.NewCoin(denomB, osmomath.NewInt(990)), takerFee: osmomath.ZeroInt(), tokenOut: sdk.NewCoin(denomC, osmomath.NewInt(2000))},
+	)
+
+	routerUsecaseMock := &mocks.RouterUsecaseMock{
+		GetOptimalQuoteFunc: func(ctx context.Context, tokenIn sdk.Coin, tokenOutDenom string, opts ...domain.RouterOption) (domain.Quote, error) {
+			return pairQuote, nil
+		},
+	}
+
+	// SQS overestimates pool 2 by 25% and underestimates pool 3 by 50%.
+	chainStub := newChainStub(map[uint64]int64{1: 990, 2: 1600, 3: 2000}, estimatedTokensIn, estimatedHeights)
+
+	// The pair quotes are stamped with the height of the router state rather than the height of the processed block.
+	stateGuard := newStateGuard(defaultHeight)
+
+	auditor, err := quoteaudit.New(routerUsecaseMock, stateGuard, chainStub, defaultConfig, &log.NoOpLogger{})
+	require.NoError(t, err)
+
+	// The quote that is not stamped with the height of its state is not sampled.
+	auditor.SampleQuote(newQuoteMock(0,
+		poolSwap{poolID: 5, poolType: poolmanagertypes.Balancer, tokenIn: sdk.NewCoin(denomA, osmomath.NewInt(500)), takerFee: osmomath.ZeroInt(), tokenOut: sdk.NewCoin(denomC, osmomath.NewInt(1000))},
+	))
+
+	// The served quote is sampled and the one beyond the limit is dropped.
+	auditor.SampleQuote(newQuoteMock(defaultHeight-1,
+		poolSwap{poolID: 3, poolType: poolmanagertypes.CosmWasm, tokenIn: sdk.NewCoin(denomA, osmomath.NewInt(500)), takerFee: osmomath.ZeroInt(), tokenOut: sdk.NewCoin(denomC, osmomath.NewInt(1000))},
+	))
+	auditor.SampleQuote(newQuoteMock(defaultHeight-1,
+		poolSwap{poolID: 4, poolType: poolmanagertypes.Stableswap, tokenIn: sdk.NewCoin(denomA, osmomath.NewInt(500)), takerFee: osmomath.ZeroInt(), tokenOut: sdk.NewCoin(denomC, osmomath.NewInt(1000))},
+	))
+
+	// System under test
+	err = auditor.ProcessEndBlock(ctx, defaultHeight+1, domain.BlockPoolMetadata{})
+	require.NoError(t, err)
+
+	// The single pool swaps are simulated net of the taker fee at the height of the quote.
+	require.Equal(t, map[uint64]sdk.Coin{
+		1: sdk.NewCoin(denomA, osmomath.NewInt(990)),
+		2: sdk.NewCoin(denomB, osmomath.NewInt(990)),
+		3: sdk.NewCoin(denomA, osmomath.NewInt(500)),
+	}, estimatedTokensIn)
+	require.Equal(t, map[uint64]uint64{1: defaultHeight, 2: defaultHeight, 3: defaultHeight - 1}, estimatedHeights)
+
+	expectedDivergences := []domain.QuoteAuditDivergence{
+		{
+			Height:         defaultHeight - 1,
+			PoolID:         3,
+			PoolType:       poolmanagertypes.CosmWasm.String(),
+			TokenIn:        "500" + denomA,
+			TokenOutDenom:  denomC,
+			SQSAmountOut:   osmomath.NewInt(1000),
+			ChainAmountOut: osmomath.NewInt(2000),
+			Divergence:     osmomath.MustNewDecFromStr("-0.5"),
+		},
+		{
+			Height:         defaultHeight,
+			PoolID:         2,
+			PoolType:       poolmanagertypes.Concentrated.String(),
+			TokenIn:        "990" + denomB,
+			TokenOutDenom:  denomC,
+			SQSAmountOut:   osmomath.NewInt(2000),
+			ChainAmountOut: osmomath.NewInt(1600),
+			Divergence:     osmomath.MustNewDecFromStr("0.25"),
+		},
+		{
+			Height:         defaultHeight,
+			PoolID:         1,
+			PoolType:       poolmanagertypes.Balancer.String(),
+			TokenIn:        "1000" + denomA,
+			TokenOutDenom:  denomB,
+			SQSAmountOut:   osmomath.NewInt(990),
+			ChainAmountOut: osmomath.NewInt(990),
+			Divergence:     osmomath.ZeroDec(),
+		},
+	}
+	requireDivergencesEqual(t, expectedDivergences, auditor.GetWorstDivergences())
+
+	// The sampled quotes are drained by the audit.
+	estimatedTokensIn = map[uint64]sdk.Coin{}
+	chainStub.EstimateSinglePoolSwapExactAmountInCb = newChainStub(map[uint64]int64{1: 990, 2: 1600}, estimatedTokensIn, estimatedHeights).EstimateSinglePoolSwapExactAmountInCb
+
+	err = auditor.ProcessEndBlock(ctx, defaultHeight+2, domain.BlockPoolMetadata{})
+	require.NoError(t, err)
+
+	require.Len(t, estimatedTokensIn, 2)
+	require.Len(t, auditor.GetWorstDivergences(), 2)
+}
+
+// newStateGuard returns the router state guard recording the router state as updated with the given height.
+func newStateGuard(height uint64) *domain.RouterStateGuard {
+	stateGuard := domain.NewRouterStateGuard()
+	stateGuard.Lock()
+	stateGuard.SetHeight(height)
+	stateGuard.Unlock()
+	return stateGuard
+}
+
+// requireDivergencesEqual asserts that the divergences are equal, comparing the decimals by value.
+func requireDivergencesEqual(t *testing.T, expected, actual []domain.QuoteAuditDivergence) {
+	t.Helper()
+
+	require.Len(t, actual, len(expected))
+	for i := range expected {
+		require.True(t, expected[i].Divergence.Equal(actual[i].Divergence), "expected %s, got %s", expected[i].Divergence, actual[i].Divergence)
+
+		expectedDivergence, actualDivergence := expected[i], actual[i]
+		expectedDivergence.Divergence, actualDivergence.Divergence = osmomath.Dec{}, osmomath.Dec{}
+		require.Equal(t, expectedDivergence, actualDivergence)
+	}
+}
+
+// TestQuoteAuditor_IntervalBlocks tests that the audits are only run every configured number of blocks.
+func TestQuoteAuditor_IntervalBlocks(t *testing.T) {
+	ctx := context.Background()
+
+	quotesComputed := 0
+	routerUsecaseMock := &mocks.RouterUsecaseMock{
+		GetOptimalQuoteFunc: func(ctx context.Context, tokenIn sdk.Coin, tokenOutDenom string, opts ...domain.RouterOption) (domain.Quote, error) {
+			quotesComputed++
+			return nil, errors.New("no route found")
+		},
+	}
+
+	config := defaultConfig
+	config.IntervalBlocks = 3
+
+	auditor, err := quoteaudit.New(routerUsecaseMock, domain.NewRouterStateGuard(), &mocks.PoolManagerGRPCClientMock{}, config, &log.NoOpLogger{})
+	require.NoError(t, err)
+
+	for height := defaultHeight; height < defaultHeight+7; height++ {
+		err = auditor.ProcessEndBlock(ctx, height, domain.BlockPoolMetadata{})
+		require.NoError(t, err)
+	}
+
+	// Audited on the 3rd and the 6th blocks.
+	require.Equal(t, 2, quotesComputed)
+	require.Empty(t, auditor.GetWorstDivergences())
+}
+
+// TestNew_InvalidPairs tests that the invalid configured pairs are rejected.
+func TestNew_InvalidPairs(t *testing.T) {
+	tests := []struct {
+		name  string
+		pairs []domain.QuoteAuditPairConfig
+	}{
+		{
+			name:  "invalid token in",
+			pairs: []domain.QuoteAuditPairConfig{{TokenIn: "invalid", TokenOutDenom: denomB}},
+		},
+		{
+			name:  "empty token out denom",
+			pairs: []domain.QuoteAuditPairConfig{{TokenIn: "1000" + denomA}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := defaultConfig
+			config.Pairs = tt.pairs
+
+			_, err := quoteaudit.New(&mocks.RouterUsecaseMock{}, domain.NewRouterStateGuard(), &mocks.PoolManagerGRPCClientMock{}, config, &log.NoOpLogger{})
+			require.Error(t, err)
+		})
+	}
+}
+
+// TestQuoteAuditor_Timeouts tests that the computation of the pair quotes under the router state guard
+// and each chain simulation query are bounded by the configured timeouts.
+func TestQuoteAuditor_Timeouts(t *testing.T) {
+	ctx := context.Background()
+
+	pairQuote := newQuoteMock(0,
+		poolSwap{poolID: 1, poolType: poolmanagertypes.Balancer, tokenIn: sdk.NewCoin(denomA, osmomath.NewInt(1000)), takerFee: osmomath.ZeroInt(), tokenOut: sdk.NewCoin(denomB, osmomath.NewInt(990))},
+	)
+
+	// The first pair is quoted until the pairs compute timeout is exceeded.
+	quotesComputed := 0
+	routerUsecaseMock := &mocks.RouterUsecaseMock{
+		GetOptimalQuoteFunc: func(ctx context.Context, tokenIn sdk.Coin, tokenOutDenom string, opts ...domain.RouterOption) (domain.Quote, error) {
+			quotesComputed++
+			<-ctx.Done()
+			return pairQuote, nil
+		},
+	}
+
+	// The chain does not respond until the query is cancelled.
+	chainQueries := 0
+	chainStub := &mocks.PoolManagerGRPCClientMock{
+		EstimateSwapExactAmountInCb: func(ctx context.Context, height uint64, tokenIn sdk.Coin, routes []poolmanagertypes.SwapAmountInRoute) (osmomath.Int, error) {
+			chainQueries++
+			<-ctx.Done()
+			return osmomath.Int{}, ctx.Err()
+		},
+		EstimateSinglePoolSwapExactAmountInCb: func(ctx context.Context, height uint64, poolID uint64, tokenIn sdk.Coin, tokenOutDenom string) (osmomath.Int, error) {
+			chainQueries++
+			<-ctx.Done()
+			return osmomath.Int{}, ctx.Err()
+		},
+	}
+
+	config := defaultConfig
+	config.Pairs = []domain.QuoteAuditPairConfig{
+		{TokenIn: "1000" + denomA, TokenOutDenom: denomB},
+		{TokenIn: "1000" + denomA, TokenOutDenom: denomC},
+	}
+	config.PairsComputeTimeoutMs = 10
+	config.ChainQueryTimeoutMs = 10
+
+	stateGuard := newStateGuard(defaultHeight)
+
+	auditor, err := quoteaudit.New(routerUsecaseMock, stateGuard, chainStub, config, &log.NoOpLogger{})
+	require.NoError(t, err)
+
+	// System under test
+	start := time.Now()
+	err = auditor.ProcessEndBlock(ctx, defaultHeight+1, domain.BlockPoolMetadata{})
+	require.NoError(t, err)
+	require.Less(t, time.Since(start), time.Second)
+
+	// The second pair is skipped and the route and pool queries of the first one time out.
+	require.Equal(t, 1, quotesComputed)
+	require.Equal(t, 2, chainQueries)
+	require.Empty(t, auditor.GetWorstDivergences())
+
+	// The router state guard is released.
+	require.True(t, stateGuard.TryLock())
+	stateGuard.Unlock()
+}
@@ -0,0 +1,6 @@
+package quotestream
+
+// WaitProcessed waits until the pending blocks are processed.
+func (s *quoteStreamer) WaitProcessed() {
+	s.processingWg.Wait()
+}
@@ -0,0 +1,348 @@
+package quotestream
+
+import (
+	"context"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	"github.com/osmosis-labs/sqs/domain"
+	"github.com/osmosis-labs/sqs/domain/mvc"
+	"github.com/osmosis-labs/sqs/log"
+)
+
+const (
+	// updatesBufferBlocks is the number of blocks worth of updates buffered for each subscription.
+	// If the subscriber falls further behind, the updates are dropped.
+	updatesBufferBlocks = 4
+
+	// pushUpdatesWorkers is the number of subscriptions whose updates are pushed concurrently at the end of a block.
+	pushUpdatesWorkers = 4
+)
+
+type quoteStreamer struct {
+	routerUsecase mvc.RouterUsecase
+	stateGuard    *domain.RouterStateGuard
+	config        domain.QuoteStreamConfig
+	logger        log.Logger
+
+	mu                 sync.Mutex
+	subscriptions      map[uint64]*subscription
+	nextSubscriptionID uint64
+	// pendingBlock is the block that is yet to be processed. If blocks end while the previous block
+	// is still being processed, they are coalesced into it. Nil if there is no such block.
+	pendingBlock *pendingBlock
+	// isProcessing is true while the updates of a block are being pushed.
+	isProcessing bool
+	// processingWg tracks the goroutine processing the pending blocks.
+	processingWg sync.WaitGroup
+}
+
+// pendingBlock is the block whose updates are yet to be pushed, coalescing the blocks that ended
+// while the previous block was still being processed.
+type pendingBlock struct {
+	// poolIDs are the IDs of the pools updated in any of the coalesced blocks.
+	poolIDs map[uint64]struct{}
+}
+
+// subscription is the state of a single quote stream subscription.
+type subscription struct {
+	mu       sync.Mutex
+	requests []domain.QuoteStreamRequest
+	// routePoolIDs are the IDs of the pools in the route of the last quote pushed for each request.
+	// They are only updated once the update is enqueued so that a dropped update is retried.
+	// If nil, the quote is recomputed at the end of the next block regardless of the updated pools.
+	routePoolIDs []map[uint64]struct{}
+	updates      chan domain.QuoteStreamUpdate
+	closed       bool
+}
+
+// blockQuotes memoizes the quotes computed within a block so that
+// the subscriptions to the same quote share the computation.
+// It is safe for concurrent use by the subscriptions pushed concurrently.
+type blockQuotes struct {
+	mu sync.Mutex
+	// quotes are keyed by formatQuoteKey.
+	quotes map[string]*quoteResult
+}
+
+type quoteResult struct {
+	once  sync.Once
+	quote domain.Quote
+	// height is the height of the router state the quote was computed against.
+	height uint64
+	err    error
+}
+
+func newBlockQuotes() *blockQuotes {
+	return &blockQuotes{
+		quotes: make(map[string]*quoteResult),
+	}
+}
+
+// get returns the quote memoized under the given key and the height of the router state it was computed against,
+// computing it with the given callback if not yet computed. The concurrent callers with the same key wait for the single computation.
+func (b *blockQuotes) get(key string, computeQuoteCb func() (domain.Quote, uint64, error)) (domain.Quote, uint64, error) {
+	b.mu.Lock()
+	result, ok := b.quotes[key]
+	if !ok {
+		result = &quoteResult{}
+		b.quotes[key] = result
+	}
+	b.mu.Unlock()
+
+	result.once.Do(func() {
+		result.quote, result.height, result.err = computeQuoteCb()
+	})
+
+	return result.quote, result.height, result.err
+}
+
+var _ mvc.QuoteStreamer = &quoteStreamer{}
+
+// New returns a new quote streamer.
+// It must be registered as an end block process plugin with the ingest usecase to push updates after each block.
+func New(routerUsecase mvc.RouterUsecase, stateGuard *domain.RouterStateGuard, config domain.QuoteStreamConfig, logger log.Logger) *quoteStreamer {
+	return &quoteStreamer{
+		routerUsecase: routerUsecase,
+		stateGuard:    stateGuard,
+		config:        config,
+		logger:        logger,
+
+		subscriptions: make(map[uint64]*subscription),
+	}
+}
+
+// Subscribe implements mvc.QuoteStreamer.
+func (s *quoteStreamer) Subscribe(ctx context.Context, requests []domain.QuoteStreamRequest) (<-chan domain.QuoteStreamUpdate, func(), error) {
+	if len(requests) > s.config.MaxQuotesPerSubscription {
+		return nil, nil, domain.ErrQuoteStreamTooManyQuotes
+	}
+
+	sub := &subscription{
+		requests:     requests,
+		routePoolIDs: make([]map[uint64]struct{}, len(requests)),
+		updates:      make(chan domain.QuoteStreamUpdate, len(requests)*updatesBufferBlocks),
+	}
+
+	s.mu.Lock()
+	if len(s.subscriptions) >= s.config.MaxSubscriptions {
+		s.mu.Unlock()
+		return nil, nil, domain.ErrQuoteStreamSubscriptionLimitReached
+	}
+	subscriptionID := s.nextSubscriptionID
+	s.nextSubscriptionID++
+	s.subscriptions[subscriptionID] = sub
+	s.mu.Unlock()
+
+	domain.SQSQuoteStreamSubscriptionsGauge.Inc()
+
+	unsubscribe := func() {
+		s.mu.Lock()
+		_, ok := s.subscriptions[subscriptionID]
+		delete(s.subscriptions, subscriptionID)
+		s.mu.Unlock()
+
+		if !ok {
+			return
+		}
+
+		domain.SQSQuoteStreamSubscriptionsGauge.Dec()
+
+		sub.mu.Lock()
+		sub.closed = true
+		close(sub.updates)
+		sub.mu.Unlock()
+	}
+
+	// Push the initial quotes.
+	s.pushUpdates(ctx, sub, nil, newBlockQuotes())
+
+	return sub.updates, unsubscribe, nil
+}
+
+// ProcessEndBlock implements domain.EndBlockProcessPlugin.
+// It recomputes and pushes the quotes whose routes contain any of the pools updated in the block.
+// The block is handed off to a background goroutine so that the end block plugins executed after this one are not blocked.
+// At most one block is processed at a time. The blocks ending while the previous block is still being
+// processed are coalesced into a single pending block that is processed next with the union of their updated pools
+// against the latest router state. The updates of the subscriptions are pushed by a bounded number of workers.
+func (s *quoteStreamer) ProcessEndBlock(ctx context.Context, blockHeight uint64, metadata domain.BlockPoolMetadata) error {
+	s.mu.Lock()
+	if s.pendingBlock == nil {
+		s.pendingBlock = &pendingBlock{poolIDs: make(map[uint64]struct{}, len(metadata.PoolIDs))}
+	}
+	for poolID := range metadata.PoolIDs {
+		s.pendingBlock.poolIDs[poolID] = struct{}{}
+	}
+
+	// The block is processed by the goroutine that is processing the previous block.
+	if s.isProcessing {
+		s.mu.Unlock()
+		domain.SQSQuoteStreamCoalescedBlocksCounter.Inc()
+		return nil
+	}
+	s.isProcessing = true
+	s.processingWg.Add(1)
+	s.mu.Unlock()
+
+	// The pending blocks outlive the end block call.
+	go s.processPendingBlocks(context.WithoutCancel(ctx))
+
+	return nil
+}
+
+// processPendingBlocks processes the pending blocks until there are none left.
+// CONTRACT: isProcessing is set and processingWg is incremented by the caller.
+func (s *quoteStreamer) processPendingBlocks(ctx context.Context) {
+	defer s.processingWg.Done()
+
+	for {
+		s.mu.Lock()
+		block := s.pendingBlock
+		s.pendingBlock = nil
+		if block == nil {
+			s.isProcessing = false
+			s.mu.Unlock()
+			return
+		}
+		subscriptions := make([]*subscription, 0, len(s.subscriptions))
+		for _, sub := range s.subscriptions {
+			subscriptions = append(subscriptions, sub)
+		}
+		s.mu.Unlock()
+
+		s.processBlock(ctx, block, subscriptions)
+	}
+}
+
+// processBlock pushes the updates of the given block to the subscriptions with pushUpdatesWorkers workers.
+func (s *quoteStreamer) processBlock(ctx context.Context, block *pendingBlock, subscriptions []*subscription) {
+	if len(subscriptions) == 0 {
+		return
+	}
+
+	var (
+		quotes        = newBlockQuotes()
+		subscriptionC = make(chan *subscription)
+		wg            sync.WaitGroup
+	)
+
+	for i := 0; i < pushUpdatesWorkers && i < len(subscriptions); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for sub := range subscriptionC {
+				s.pushUpdates(ctx, sub, block.poolIDs, quotes)
+			}
+		}()
+	}
+
+	for _, sub := range subscriptions {
+		subscriptionC <- sub
+	}
+	close(subscriptionC)
+
+	wg.Wait()
+}
+
+// pushUpdates recomputes and pushes the quotes of the subscription that are either not yet computed
+// or whose routes contain any of the updated pools. If updatedPoolIDs is nil, only the quotes that
+// are not yet computed are pushed.
+func (s *quoteStreamer) pushUpdates(ctx context.Context, sub *subscription, updatedPoolIDs map[uint64]struct{}, quotes *blockQuotes) {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+
+	if sub.closed {
+		return
+	}
+
+	for i, request := range sub.requests {
+		if sub.routePoolIDs[i] != nil && !containsAny(sub.routePoolIDs[i], updatedPoolIDs) {
+			continue
+		}
+
+		quote, height, err := quotes.get(formatQuoteKey(request), func() (domain.Quote, uint64, error) {
+			return s.computeQuote(ctx, request)
+		})
+
+		update := domain.QuoteStreamUpdate{
+			Height:        height,
+			TokenIn:       request.TokenIn,
+			TokenOutDenom: request.TokenOutDenom,
+		}
+
+		// Recompute after the next block unless the quote is enqueued.
+		var routePoolIDs map[uint64]struct{}
+		if err != nil {
+			update.Error = err.Error()
+		} else {
+			update.Quote = quote
+		}
+
+		select {
+		case sub.updates <- update:
+			domain.SQSQuoteStreamUpdatesCounter.Inc()
+
+			if err == nil {
+				routePoolIDs = getRoutePoolIDs(quote)
+			}
+		default:
+			// Drop the update rather than blocking the other subscriptions
+			// on the slow subscriber.
+			domain.SQSQuoteStreamDroppedUpdatesCounter.Inc()
+		}
+
+		sub.routePoolIDs[i] = routePoolIDs
+	}
+}
+
+// computeQuote computes the optimal quote for the request and prepares the result for the subscriber.
+// Returns the quote with the height of the router state it was computed against.
+// The router state guard is held for the computation of the single quote only so that the ingest
+// of the next block is not stalled by all of the subscriptions.
+func (s *quoteStreamer) computeQuote(ctx context.Context, request domain.QuoteStreamRequest) (domain.Quote, uint64, error) {
+	s.stateGuard.RLock()
+	defer s.stateGuard.RUnlock()
+
+	height := s.stateGuard.GetHeight()
+
+	quote, err := s.routerUsecase.GetOptimalQuote(ctx, request.TokenIn, request.TokenOutDenom)
+	if err != nil {
+		s.logger.Debug("failed to compute streamed quote", zap.Stringer("token_in", request.TokenIn), zap.String("token_out_denom", request.TokenOutDenom), zap.Error(err))
+		return nil, height, err
+	}
+
+	if _, _, err := quote.PrepareResult(ctx, osmomath.OneDec(), s.logger); err != nil {
+		return nil, height, err
+	}
+
+	return quote, height, nil
+}
+
+// formatQuoteKey formats the key of the request in blockQuotes.
+func formatQuoteKey(request domain.QuoteStreamRequest) string {
+	return request.TokenIn.String() + "/" + request.TokenOutDenom
+}
+
+// getRoutePoolIDs returns the IDs of all pools in the routes of the quote.
+func getRoutePoolIDs(quote domain.Quote) map[uint64]struct{} {
+	poolIDs := make(map[uint64]struct{})
+	for _, route := range quote.GetRoute() {
+		for _, pool := range route.GetPools() {
+			poolIDs[pool.GetId()] = struct{}{}
+		}
+	}
+	return poolIDs
+}
+
+// containsAny returns true if any of the pool IDs in b is present in a.
+func containsAny(a, b map[uint64]struct{}) bool {
+	for poolID := range b {
+		if _, ok := a[poolID]; ok {
+			return true
+		}
+	}
+	return false
+}
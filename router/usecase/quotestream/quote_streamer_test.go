@@ -0,0 +1,334 @@
+package quotestream_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	"github.com/osmosis-labs/sqs/domain"
+	"github.com/osmosis-labs/sqs/domain/mocks"
+	"github.com/osmosis-labs/sqs/log"
+	"github.com/osmosis-labs/sqs/router/usecase"
+	"github.com/osmosis-labs/sqs/router/usecase/quotestream"
+	"github.com/osmosis-labs/sqs/router/usecase/route"
+)
+
+const (
+	denomA = "denomA"
+	denomB = "denomB"
+	denomC = "denomC"
+)
+
+var (
+	errNoRoute = errors.New("no route found")
+
+	tokenInA = sdk.NewCoin(denomA, osmomath.NewInt(1000))
+
+	defaultConfig = domain.QuoteStreamConfig{
+		Enabled:                  true,
+		MaxSubscriptions:         2,
+		MaxQuotesPerSubscription: 2,
+	}
+)
+
+// quoteMock is the quote over the given pools with no-op result preparation.
+type quoteMock struct {
+	domain.Quote
+	routes []domain.SplitRoute
+}
+
+// GetRoute implements domain.Quote.
+func (q *quoteMock) GetRoute() []domain.SplitRoute {
+	return q.routes
+}
+
+// PrepareResult implements domain.Quote.
+func (q *quoteMock) PrepareResult(ctx context.Context, scalingFactor osmomath.Dec, logger log.Logger) ([]domain.SplitRoute, osmomath.Dec, error) {
+	return q.routes, osmomath.ZeroDec(), nil
+}
+
+func newQuoteMock(poolIDs ...uint64) *quoteMock {
+	pools := make([]domain.RoutablePool, 0, len(poolIDs))
+	for _, poolID := range poolIDs {
+		pools = append(pools, &mocks.MockRoutablePool{ID: poolID})
+	}
+
+	return &quoteMock{
+		routes: []domain.SplitRoute{&usecase.RouteWithOutAmount{RouteImpl: route.RouteImpl{Pools: pools}}},
+	}
+}
+
+// newRouterUsecaseMock returns the router usecase mock that quotes denom B through pool 1
+// and denom C through pools 2 and 3. The number of quotes computed per token out denom is tracked.
+func newRouterUsecaseMock(computedQuotes map[string]int) *mocks.RouterUsecaseMock {
+	var mu sync.Mutex
+
+	return &mocks.RouterUsecaseMock{
+		GetOptimalQuoteFunc: func(ctx context.Context, tokenIn sdk.Coin, tokenOutDenom string, opts ...domain.RouterOption) (domain.Quote, error) {
+			mu.Lock()
+			computedQuotes[tokenOutDenom]++
+			mu.Unlock()
+
+			switch tokenOutDenom {
+			case denomB:
+				return newQuoteMock(1), nil
+			case denomC:
+				return newQuoteMock(2, 3), nil
+			default:
+				return nil, errNoRoute
+			}
+		},
+	}
+}
+
+// drainUpdates returns all updates currently buffered in the channel.
+func drainUpdates(updates <-chan domain.QuoteStreamUpdate) []domain.QuoteStreamUpdate {
+	result := []domain.QuoteStreamUpdate{}
+	for {
+		select {
+		case update := <-updates:
+			result = append(result, update)
+		default:
+			return result
+		}
+	}
+}
+
+// updateState records the given height with the router state as the ingester does when updating it with a block.
+func updateState(stateGuard *domain.RouterStateGuard, height uint64) {
+	stateGuard.Lock()
+	stateGuard.SetHeight(height)
+	stateGuard.Unlock()
+}
+
+// TestQuoteStreamer_ProcessEndBlock tests that the initial quotes are pushed upon subscription and
+// that the quotes are only recomputed and pushed when any of the pools in their route is updated.
+func TestQuoteStreamer_ProcessEndBlock(t *testing.T) {
+	var (
+		ctx            = context.Background()
+		computedQuotes = map[string]int{}
+	)
+
+	stateGuard := domain.NewRouterStateGuard()
+	streamer := quotestream.New(newRouterUsecaseMock(computedQuotes), stateGuard, defaultConfig, &log.NoOpLogger{})
+
+	updateState(stateGuard, 9)
+
+	updates, unsubscribe, err := streamer.Subscribe(ctx, []domain.QuoteStreamRequest{
+		{TokenIn: tokenInA, TokenOutDenom: denomB},
+		{TokenIn: tokenInA, TokenOutDenom: denomC},
+	})
+	require.NoError(t, err)
+
+	// Initial quotes.
+	initialUpdates := drainUpdates(updates)
+	require.Len(t, initialUpdates, 2)
+	require.Equal(t, denomB, initialUpdates[0].TokenOutDenom)
+	require.Equal(t, denomC, initialUpdates[1].TokenOutDenom)
+	require.Equal(t, map[string]int{denomB: 1, denomC: 1}, computedQuotes)
+
+	// Pool 4 is not in any route.
+	updateState(stateGuard, 10)
+	err = streamer.ProcessEndBlock(ctx, 10, domain.BlockPoolMetadata{PoolIDs: map[uint64]struct{}{4: {}}})
+	require.NoError(t, err)
+	streamer.WaitProcessed()
+	require.Empty(t, drainUpdates(updates))
+	require.Equal(t, map[string]int{denomB: 1, denomC: 1}, computedQuotes)
+
+	// Pool 3 is in the route of denom C.
+	updateState(stateGuard, 11)
+	err = streamer.ProcessEndBlock(ctx, 11, domain.BlockPoolMetadata{PoolIDs: map[uint64]struct{}{3: {}}})
+	require.NoError(t, err)
+	streamer.WaitProcessed()
+	blockUpdates := drainUpdates(updates)
+	require.Len(t, blockUpdates, 1)
+	require.Equal(t, uint64(11), blockUpdates[0].Height)
+	require.Equal(t, denomC, blockUpdates[0].TokenOutDenom)
+	require.NotNil(t, blockUpdates[0].Quote)
+	require.Equal(t, map[string]int{denomB: 1, denomC: 2}, computedQuotes)
+
+	// The channel is closed upon unsubscribing and no further updates are pushed.
+	unsubscribe()
+	_, ok := <-updates
+	require.False(t, ok)
+
+	updateState(stateGuard, 12)
+	err = streamer.ProcessEndBlock(ctx, 12, domain.BlockPoolMetadata{PoolIDs: map[uint64]struct{}{1: {}}})
+	require.NoError(t, err)
+	streamer.WaitProcessed()
+	require.Equal(t, map[string]int{denomB: 1, denomC: 2}, computedQuotes)
+}
+
+// TestQuoteStreamer_SharedBlockQuotes tests that the subscriptions to the same quote
+// share the computation within a block and that the failed quotes are recomputed after every block.
+func TestQuoteStreamer_SharedBlockQuotes(t *testing.T) {
+	var (
+		ctx            = context.Background()
+		computedQuotes = map[string]int{}
+	)
+
+	streamer := quotestream.New(newRouterUsecaseMock(computedQuotes), domain.NewRouterStateGuard(), defaultConfig, &log.NoOpLogger{})
+
+	requests := []domain.QuoteStreamRequest{
+		{TokenIn: tokenInA, TokenOutDenom: denomB},
+		{TokenIn: tokenInA, TokenOutDenom: "unknown"},
+	}
+
+	updatesOne, _, err := streamer.Subscribe(ctx, requests)
+	require.NoError(t, err)
+	updatesTwo, _, err := streamer.Subscribe(ctx, requests)
+	require.NoError(t, err)
+
+	initialUpdates := drainUpdates(updatesOne)
+	require.Len(t, initialUpdates, 2)
+	require.Equal(t, errNoRoute.Error(), initialUpdates[1].Error)
+	require.Len(t, drainUpdates(updatesTwo), 2)
+
+	err = streamer.ProcessEndBlock(ctx, 10, domain.BlockPoolMetadata{PoolIDs: map[uint64]struct{}{1: {}}})
+	require.NoError(t, err)
+	streamer.WaitProcessed()
+
+	require.Len(t, drainUpdates(updatesOne), 2)
+	require.Len(t, drainUpdates(updatesTwo), 2)
+
+	// Each subscription computed the initial quotes. Afterwards, the block quotes are computed once.
+	require.Equal(t, map[string]int{denomB: 3, "unknown": 3}, computedQuotes)
+}
+
+// TestQuoteStreamer_SubscribeLimits tests the subscription limits.
+func TestQuoteStreamer_SubscribeLimits(t *testing.T) {
+	ctx := context.Background()
+
+	streamer := quotestream.New(newRouterUsecaseMock(map[string]int{}), domain.NewRouterStateGuard(), defaultConfig, &log.NoOpLogger{})
+
+	request := domain.QuoteStreamRequest{TokenIn: tokenInA, TokenOutDenom: denomB}
+
+	// Too many quotes per subscription.
+	_, _, err := streamer.Subscribe(ctx, []domain.QuoteStreamRequest{request, request, request})
+	require.ErrorIs(t, err, domain.ErrQuoteStreamTooManyQuotes)
+
+	_, unsubscribe, err := streamer.Subscribe(ctx, []domain.QuoteStreamRequest{request})
+	require.NoError(t, err)
+	_, _, err = streamer.Subscribe(ctx, []domain.QuoteStreamRequest{request})
+	require.NoError(t, err)
+
+	// Too many subscriptions.
+	_, _, err = streamer.Subscribe(ctx, []domain.QuoteStreamRequest{request})
+	require.ErrorIs(t, err, domain.ErrQuoteStreamSubscriptionLimitReached)
+
+	// Unsubscribing frees up the subscription.
+	unsubscribe()
+	_, _, err = streamer.Subscribe(ctx, []domain.QuoteStreamRequest{request})
+	require.NoError(t, err)
+}
+
+// TestQuoteStreamer_CoalescedBlocks tests that the blocks ending while the previous block is still being processed
+// are coalesced into a single block with the latest height and the union of the updated pools.
+func TestQuoteStreamer_CoalescedBlocks(t *testing.T) {
+	var (
+		ctx            = context.Background()
+		computedQuotes = map[string]int{}
+
+		isBlocking  = false
+		quoteStartC = make(chan struct{})
+		releaseC    = make(chan struct{})
+	)
+
+	routerUsecase := newRouterUsecaseMock(computedQuotes)
+	getOptimalQuote := routerUsecase.GetOptimalQuoteFunc
+	routerUsecase.GetOptimalQuoteFunc = func(ctx context.Context, tokenIn sdk.Coin, tokenOutDenom string, opts ...domain.RouterOption) (domain.Quote, error) {
+		if isBlocking && tokenOutDenom == denomB {
+			quoteStartC <- struct{}{}
+			<-releaseC
+		}
+		return getOptimalQuote(ctx, tokenIn, tokenOutDenom, opts...)
+	}
+
+	stateGuard := domain.NewRouterStateGuard()
+	streamer := quotestream.New(routerUsecase, stateGuard, defaultConfig, &log.NoOpLogger{})
+
+	updates, _, err := streamer.Subscribe(ctx, []domain.QuoteStreamRequest{
+		{TokenIn: tokenInA, TokenOutDenom: denomB},
+		{TokenIn: tokenInA, TokenOutDenom: denomC},
+	})
+	require.NoError(t, err)
+	require.Len(t, drainUpdates(updates), 2)
+
+	updateState(stateGuard, 10)
+	isBlocking = true
+
+	// Block 10 is handed off without waiting for the quotes to be pushed.
+	require.NoError(t, streamer.ProcessEndBlock(ctx, 10, domain.BlockPoolMetadata{PoolIDs: map[uint64]struct{}{1: {}}}))
+
+	// Block 10 is being processed.
+	<-quoteStartC
+	isBlocking = false
+
+	// Blocks 11 and 12 are coalesced into the block being processed and return immediately.
+	// Note that the router state is not updated since the block being processed holds the guard.
+	require.NoError(t, streamer.ProcessEndBlock(ctx, 11, domain.BlockPoolMetadata{PoolIDs: map[uint64]struct{}{3: {}}}))
+	require.NoError(t, streamer.ProcessEndBlock(ctx, 12, domain.BlockPoolMetadata{PoolIDs: map[uint64]struct{}{4: {}}}))
+
+	close(releaseC)
+	streamer.WaitProcessed()
+
+	blockUpdates := drainUpdates(updates)
+	require.Len(t, blockUpdates, 2)
+
+	// Block 10 updated the quote of denom B.
+	require.Equal(t, uint64(10), blockUpdates[0].Height)
+	require.Equal(t, denomB, blockUpdates[0].TokenOutDenom)
+
+	// Blocks 11 and 12 were processed once, updating the quote of denom C through pool 3.
+	require.Equal(t, uint64(10), blockUpdates[1].Height)
+	require.Equal(t, denomC, blockUpdates[1].TokenOutDenom)
+	require.Equal(t, map[string]int{denomB: 2, denomC: 2}, computedQuotes)
+
+	// The guard is released once the blocks are processed.
+	require.True(t, stateGuard.TryLock())
+	stateGuard.Unlock()
+}
+
+// TestQuoteStreamer_DroppedUpdates tests that the quotes whose updates are dropped because the subscriber
+// fell behind are recomputed and pushed after the next block regardless of the updated pools.
+func TestQuoteStreamer_DroppedUpdates(t *testing.T) {
+	var (
+		ctx            = context.Background()
+		computedQuotes = map[string]int{}
+	)
+
+	streamer := quotestream.New(newRouterUsecaseMock(computedQuotes), domain.NewRouterStateGuard(), defaultConfig, &log.NoOpLogger{})
+
+	updates, _, err := streamer.Subscribe(ctx, []domain.QuoteStreamRequest{
+		{TokenIn: tokenInA, TokenOutDenom: denomB},
+	})
+	require.NoError(t, err)
+
+	// Fill the buffer of the subscription with the initial quote and the quotes of the blocks updating pool 1.
+	// The update of the last block is dropped.
+	for height := uint64(10); height < 14; height++ {
+		require.NoError(t, streamer.ProcessEndBlock(ctx, height, domain.BlockPoolMetadata{PoolIDs: map[uint64]struct{}{1: {}}}))
+		streamer.WaitProcessed()
+	}
+	require.Len(t, drainUpdates(updates), 4)
+	require.Equal(t, map[string]int{denomB: 5}, computedQuotes)
+
+	// Pool 4 is not in the route but the quote is pushed since its last update was dropped.
+	require.NoError(t, streamer.ProcessEndBlock(ctx, 14, domain.BlockPoolMetadata{PoolIDs: map[uint64]struct{}{4: {}}}))
+	streamer.WaitProcessed()
+	blockUpdates := drainUpdates(updates)
+	require.Len(t, blockUpdates, 1)
+	require.NotNil(t, blockUpdates[0].Quote)
+
+	// Once pushed, the quote is only recomputed when pool 1 is updated.
+	require.NoError(t, streamer.ProcessEndBlock(ctx, 15, domain.BlockPoolMetadata{PoolIDs: map[uint64]struct{}{4: {}}}))
+	streamer.WaitProcessed()
+	require.Empty(t, drainUpdates(updates))
+	require.Equal(t, map[string]int{denomB: 6}, computedQuotes)
+}
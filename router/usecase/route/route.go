@@ -81,6 +81,7 @@ func (r RouteImpl) PrepareResultPools(ctx context.Context, tokenIn sdk.Coin, log
 		}
 
 		// Charge taker fee
+		tokenInBeforeTakerFee := tokenIn
 		tokenIn = pool.ChargeTakerFeeExactIn(tokenIn)
 
 		tokenOut, err := pool.CalculateTokenOutByTokenIn(ctx, tokenIn)
@@ -88,8 +89,10 @@ func (r RouteImpl) PrepareResultPools(ctx context.Context, tokenIn sdk.Coin, log
 			return nil, osmomath.Dec{}, osmomath.Dec{}, err
 		}
 
+		swapBreakdown := NewPoolSwapBreakdown(pool, tokenInBeforeTakerFee, tokenIn, tokenOut, spotPriceInBaseOutQuote.Dec())
+
 		// Update effective spot price
-		effectiveSpotPriceInBaseOutQuote.MulMut(tokenOut.Amount.ToLegacyDec().QuoMut(tokenIn.Amount.ToLegacyDec()))
+		effectiveSpotPriceInBaseOutQuote.MulMut(swapBreakdown.EffectivePrice)
 
 		// Note, in the future we may want to increase the precision of the spot price
 		routeSpotPriceInBaseOutQuote.MulMut(spotPriceInBaseOutQuote.Dec())
@@ -103,6 +106,10 @@ func (r RouteImpl) PrepareResultPools(ctx context.Context, tokenIn sdk.Coin, log
 			pool.GetCodeID(),
 		)
 
+		if resultPool, ok := newPool.(domain.RoutableResultPool); ok {
+			resultPool.SetSwapBreakdown(swapBreakdown)
+		}
+
 		newPools = append(newPools, newPool)
 
 		tokenIn = tokenOut
@@ -110,6 +117,90 @@ func (r RouteImpl) PrepareResultPools(ctx context.Context, tokenIn sdk.Coin, log
 	return newPools, routeSpotPriceInBaseOutQuote, effectiveSpotPriceInBaseOutQuote, nil
 }
 
+// PrepareSwapBreakdownsExactOut returns the breakdown of the swap through each pool
+// of the route for receiving the given token out.
+// CONTRACT: the route has the layout of the exact amount out quote. That is, the pools are ordered
+// from token out to token in, and the token in and token out denoms of each pool are swapped.
+// The returned breakdowns are in the same order as the pools.
+// Note that the pool denoms are swapped for the duration of the computation and restored afterwards.
+func (r RouteImpl) PrepareSwapBreakdownsExactOut(ctx context.Context, tokenOut sdk.Coin, logger log.Logger) (breakdowns []*domain.PoolSwapBreakdown, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			breakdowns = nil
+			err = fmt.Errorf("error when preparing exact out swap breakdowns: %v", r)
+		}
+	}()
+
+	breakdowns = make([]*domain.PoolSwapBreakdown, 0, len(r.Pools))
+
+	for _, pool := range r.Pools {
+		tokenInDenom, tokenOutDenom := pool.GetTokenOutDenom(), pool.GetTokenInDenom()
+
+		breakdown, err := func() (*domain.PoolSwapBreakdown, error) {
+			pool.SetTokenInDenom(tokenInDenom)
+			pool.SetTokenOutDenom(tokenOutDenom)
+			defer func() {
+				pool.SetTokenInDenom(tokenOutDenom)
+				pool.SetTokenOutDenom(tokenInDenom)
+			}()
+
+			spotPriceInBaseOutQuote, err := pool.CalcSpotPrice(ctx, tokenInDenom, tokenOutDenom)
+			if err != nil {
+				logger.Error("failed to calculate spot price for pool", zap.Error(err))
+				spotPriceInBaseOutQuote = osmomath.ZeroBigDec()
+			}
+
+			tokenInAfterTakerFee, err := pool.CalculateTokenInByTokenOut(ctx, tokenOut)
+			if err != nil {
+				return nil, err
+			}
+
+			tokenIn := pool.ChargeTakerFeeExactOut(tokenInAfterTakerFee)
+
+			return NewPoolSwapBreakdown(pool, tokenIn, tokenInAfterTakerFee, tokenOut, spotPriceInBaseOutQuote.Dec()), nil
+		}()
+		if err != nil {
+			return nil, err
+		}
+
+		breakdowns = append(breakdowns, breakdown)
+
+		tokenOut = breakdown.TokenIn
+	}
+
+	return breakdowns, nil
+}
+
+// NewPoolSwapBreakdown returns the breakdown of the swap of token in for token out through the pool.
+// The token in after taker fee is the amount that is swapped by the pool.
+// The spot price before the swap has token in as base and token out as quote.
+func NewPoolSwapBreakdown(pool domain.RoutablePool, tokenIn, tokenInAfterTakerFee, tokenOut sdk.Coin, spotPrice osmomath.Dec) *domain.PoolSwapBreakdown {
+	effectivePrice := osmomath.ZeroDec()
+	if !tokenInAfterTakerFee.Amount.IsZero() {
+		effectivePrice = tokenOut.Amount.ToLegacyDec().QuoMut(tokenInAfterTakerFee.Amount.ToLegacyDec())
+	}
+
+	priceImpact := osmomath.ZeroDec()
+	if !spotPrice.IsZero() {
+		priceImpact = effectivePrice.Quo(spotPrice).SubMut(osmomath.OneDec())
+	}
+
+	spreadFactorAmount := osmomath.ZeroInt()
+	if spreadFactor := pool.GetSpreadFactor(); !spreadFactor.IsNil() {
+		spreadFactorAmount = tokenInAfterTakerFee.Amount.ToLegacyDec().MulMut(spreadFactor).TruncateInt()
+	}
+
+	return &domain.PoolSwapBreakdown{
+		TokenIn:             tokenIn,
+		TokenOut:            tokenOut,
+		SpotPrice:           spotPrice,
+		EffectivePrice:      effectivePrice,
+		PriceImpact:         priceImpact,
+		SpreadFactorCharged: sdk.NewCoin(tokenIn.Denom, spreadFactorAmount),
+		TakerFeeCharged:     sdk.NewCoin(tokenIn.Denom, tokenIn.Amount.Sub(tokenInAfterTakerFee.Amount)),
+	}
+}
+
 // GetPools implements Route.
 func (r *RouteImpl) GetPools() []domain.RoutablePool {
 	return r.Pools
@@ -145,6 +236,77 @@ func (r *RouteImpl) CalculateTokenOutByTokenIn(ctx context.Context, tokenIn sdk.
 	return tokenOut, nil
 }
 
+// EstimateGas estimates the gas consumed by swapping the given token in over the route
+// according to the gas cost model. The base gas of the swap message is not included.
+// The pools are traversed from first to last so that the ticks crossed by each pool
+// are estimated for the token in of that pool.
+// Returns error if the swap over the route fails.
+func (r *RouteImpl) EstimateGas(ctx context.Context, tokenIn sdk.Coin, gasCostModel domain.GasCostModelConfig) (gas uint64, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			gas = 0
+			err = fmt.Errorf("error when estimating gas in route: %v", r)
+		}
+	}()
+
+	for _, pool := range r.Pools {
+		tokenIn = pool.ChargeTakerFeeExactIn(tokenIn)
+		if tokenIn.Amount.IsNil() || tokenIn.Amount.IsZero() {
+			return gas, nil
+		}
+
+		ticksCrossed := 0
+		if tickCrossingEstimator, ok := pool.(domain.TickCrossingEstimator); ok {
+			ticksCrossed, err = tickCrossingEstimator.EstimateTicksCrossed(ctx, tokenIn)
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		gas += gasCostModel.GetPoolGas(pool.GetSQSType(), ticksCrossed)
+
+		tokenIn, err = pool.CalculateTokenOutByTokenIn(ctx, tokenIn)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return gas, nil
+}
+
+// CalculateTokenInByTokenOut implements Route.
+// Traverses the pools from last to first, computing the token in required by each pool
+// to receive the token out of that pool. The taker fee is charged on the token in of each pool.
+func (r *RouteImpl) CalculateTokenInByTokenOut(ctx context.Context, tokenOut sdk.Coin) (tokenIn sdk.Coin, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			tokenIn = sdk.Coin{}
+			err = fmt.Errorf("error when calculating in by out in route: %v", r)
+		}
+	}()
+
+	for i := len(r.Pools) - 1; i >= 0; i-- {
+		pool := r.Pools[i]
+
+		tokenOutAmt := tokenOut.Amount.ToLegacyDec()
+		if tokenOutAmt.IsNil() || tokenOutAmt.IsZero() {
+			return sdk.Coin{}, nil
+		}
+
+		tokenIn, err = pool.CalculateTokenInByTokenOut(ctx, tokenOut)
+		if err != nil {
+			return sdk.Coin{}, err
+		}
+
+		// Charge taker fee
+		tokenIn = pool.ChargeTakerFeeExactOut(tokenIn)
+
+		tokenOut = tokenIn
+	}
+
+	return tokenIn, nil
+}
+
 // String implements domain.Route.
 func (r *RouteImpl) String() string {
 	var strBuilder strings.Builder
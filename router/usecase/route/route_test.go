@@ -217,6 +217,219 @@ func (s *RouterTestSuite) TestPrepareResultPools() {
 	}
 }
 
+// This test validates that the token in is computed by traversing the pools
+// from last to first, charging the taker fee on the token in of each pool.
+func (s *RouterTestSuite) TestCalculateTokenInByTokenOut() {
+	var (
+		tokenOut = sdk.NewCoin(DenomThree, osmomath.NewInt(100))
+
+		// Requires twice the token out amount.
+		doubleInCb = func(ctx context.Context, tokenOut sdk.Coin) (sdk.Coin, error) {
+			return sdk.NewCoin(DenomOne, tokenOut.Amount.MulRaw(2)), nil
+		}
+
+		// Requires thrice the token out amount.
+		tripleInCb = func(ctx context.Context, tokenOut sdk.Coin) (sdk.Coin, error) {
+			return sdk.NewCoin(DenomTwo, tokenOut.Amount.MulRaw(3)), nil
+		}
+
+		errorCb = func(ctx context.Context, tokenOut sdk.Coin) (sdk.Coin, error) {
+			return sdk.Coin{}, domain.ExactAmountOutNotSupportedError{PoolId: 2}
+		}
+	)
+
+	tests := map[string]struct {
+		pools []domain.RoutablePool
+
+		expectedTokenIn sdk.Coin
+		expectedError   error
+	}{
+		"single pool, no taker fee": {
+			pools: []domain.RoutablePool{
+				&mocks.MockRoutablePool{ID: 1, TakerFee: osmomath.ZeroDec(), CalculateTokenInByTokenOutFunc: tripleInCb},
+			},
+
+			expectedTokenIn: sdk.NewCoin(DenomTwo, osmomath.NewInt(300)),
+		},
+		"two pools, traversed from last to first": {
+			pools: []domain.RoutablePool{
+				&mocks.MockRoutablePool{ID: 1, TakerFee: osmomath.ZeroDec(), CalculateTokenInByTokenOutFunc: doubleInCb},
+				&mocks.MockRoutablePool{ID: 2, TakerFee: osmomath.ZeroDec(), CalculateTokenInByTokenOutFunc: tripleInCb},
+			},
+
+			// 100 * 3 * 2
+			expectedTokenIn: sdk.NewCoin(DenomOne, osmomath.NewInt(600)),
+		},
+		"two pools, taker fee charged on token in": {
+			pools: []domain.RoutablePool{
+				&mocks.MockRoutablePool{ID: 1, TakerFee: osmomath.ZeroDec(), CalculateTokenInByTokenOutFunc: doubleInCb},
+				&mocks.MockRoutablePool{ID: 2, TakerFee: osmomath.NewDecWithPrec(5, 1), CalculateTokenInByTokenOutFunc: tripleInCb},
+			},
+
+			// 100 * 3 / (1 - 0.5) * 2
+			expectedTokenIn: sdk.NewCoin(DenomOne, osmomath.NewInt(1200)),
+		},
+		"error in one of the pools": {
+			pools: []domain.RoutablePool{
+				&mocks.MockRoutablePool{ID: 1, TakerFee: osmomath.ZeroDec(), CalculateTokenInByTokenOutFunc: doubleInCb},
+				&mocks.MockRoutablePool{ID: 2, TakerFee: osmomath.ZeroDec(), CalculateTokenInByTokenOutFunc: errorCb},
+			},
+
+			expectedError: domain.ExactAmountOutNotSupportedError{PoolId: 2},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		s.Run(name, func() {
+			r := route.RouteImpl{
+				Pools: tc.pools,
+			}
+
+			tokenIn, err := r.CalculateTokenInByTokenOut(context.TODO(), tokenOut)
+
+			if tc.expectedError != nil {
+				s.Require().Error(err)
+				s.Require().ErrorIs(err, tc.expectedError)
+				return
+			}
+			s.Require().NoError(err)
+
+			s.Require().Equal(tc.expectedTokenIn, tokenIn)
+		})
+	}
+}
+
+// This test validates the per-pool swap breakdowns of the exact amount in result pools
+// and of the routes in the exact amount out layout.
+// The pools are mocked as CosmWasm pools so that their spot price is one.
+func (s *RouterTestSuite) TestPrepareSwapBreakdowns() {
+	var (
+		// Returns half the token in amount.
+		halfOutCb = func(ctx context.Context, tokenIn sdk.Coin) (sdk.Coin, error) {
+			return sdk.NewCoin(DenomTwo, tokenIn.Amount.QuoRaw(2)), nil
+		}
+
+		// Returns the token in amount.
+		oneToOneOutCb = func(ctx context.Context, tokenIn sdk.Coin) (sdk.Coin, error) {
+			return sdk.NewCoin(DenomThree, tokenIn.Amount), nil
+		}
+
+		// Requires twice the token out amount.
+		doubleInCb = func(ctx context.Context, tokenOut sdk.Coin) (sdk.Coin, error) {
+			return sdk.NewCoin(DenomOne, tokenOut.Amount.MulRaw(2)), nil
+		}
+
+		// Requires thrice the token out amount.
+		tripleInCb = func(ctx context.Context, tokenOut sdk.Coin) (sdk.Coin, error) {
+			return sdk.NewCoin(DenomTwo, tokenOut.Amount.MulRaw(3)), nil
+		}
+	)
+
+	s.Run("exact amount in", func() {
+		r := route.RouteImpl{
+			Pools: []domain.RoutablePool{
+				&mocks.MockRoutablePool{ID: 1, PoolType: poolmanagertypes.CosmWasm, TokenOutDenom: DenomTwo, TakerFee: osmomath.NewDecWithPrec(1, 2), SpreadFactor: osmomath.NewDecWithPrec(2, 3), CalculateTokenOutByTokenInFunc: halfOutCb},
+				&mocks.MockRoutablePool{ID: 2, PoolType: poolmanagertypes.CosmWasm, TokenOutDenom: DenomThree, TakerFee: osmomath.ZeroDec(), SpreadFactor: osmomath.ZeroDec(), CalculateTokenOutByTokenInFunc: oneToOneOutCb},
+			},
+		}
+
+		resultPools, _, _, err := r.PrepareResultPools(context.TODO(), sdk.NewCoin(DenomOne, osmomath.NewInt(1000)), &log.NoOpLogger{})
+		s.Require().NoError(err)
+
+		s.validateSwapBreakdowns([]*domain.PoolSwapBreakdown{
+			{
+				// 1000 - 1000 * 0.01 = 990 swapped for 990 / 2
+				TokenIn:        sdk.NewCoin(DenomOne, osmomath.NewInt(1000)),
+				TokenOut:       sdk.NewCoin(DenomTwo, osmomath.NewInt(495)),
+				SpotPrice:      osmomath.OneDec(),
+				EffectivePrice: osmomath.NewDecWithPrec(5, 1),
+				PriceImpact:    osmomath.NewDecWithPrec(-5, 1),
+				// 990 * 0.002 truncated
+				SpreadFactorCharged: sdk.NewCoin(DenomOne, osmomath.NewInt(1)),
+				TakerFeeCharged:     sdk.NewCoin(DenomOne, osmomath.NewInt(10)),
+			},
+			{
+				TokenIn:             sdk.NewCoin(DenomTwo, osmomath.NewInt(495)),
+				TokenOut:            sdk.NewCoin(DenomThree, osmomath.NewInt(495)),
+				SpotPrice:           osmomath.OneDec(),
+				EffectivePrice:      osmomath.OneDec(),
+				PriceImpact:         osmomath.ZeroDec(),
+				SpreadFactorCharged: sdk.NewCoin(DenomTwo, osmomath.ZeroInt()),
+				TakerFeeCharged:     sdk.NewCoin(DenomTwo, osmomath.ZeroInt()),
+			},
+		}, resultPools)
+	})
+
+	s.Run("exact amount out", func() {
+		// The pools are ordered from token out to token in with the token in and token out denoms swapped.
+		r := route.RouteImpl{
+			Pools: []domain.RoutablePool{
+				&mocks.MockRoutablePool{ID: 2, PoolType: poolmanagertypes.CosmWasm, TokenInDenom: DenomThree, TokenOutDenom: DenomTwo, TakerFee: osmomath.ZeroDec(), CalculateTokenInByTokenOutFunc: tripleInCb},
+				&mocks.MockRoutablePool{ID: 1, PoolType: poolmanagertypes.CosmWasm, TokenInDenom: DenomTwo, TokenOutDenom: DenomOne, TakerFee: osmomath.NewDecWithPrec(5, 1), SpreadFactor: osmomath.NewDecWithPrec(1, 2), CalculateTokenInByTokenOutFunc: doubleInCb},
+			},
+		}
+
+		breakdowns, err := r.PrepareSwapBreakdownsExactOut(context.TODO(), sdk.NewCoin(DenomThree, osmomath.NewInt(100)), &log.NoOpLogger{})
+		s.Require().NoError(err)
+
+		expectedBreakdowns := []*domain.PoolSwapBreakdown{
+			{
+				TokenIn:             sdk.NewCoin(DenomTwo, osmomath.NewInt(300)),
+				TokenOut:            sdk.NewCoin(DenomThree, osmomath.NewInt(100)),
+				SpotPrice:           osmomath.OneDec(),
+				EffectivePrice:      osmomath.MustNewDecFromStr("0.333333333333333333"),
+				PriceImpact:         osmomath.MustNewDecFromStr("-0.666666666666666667"),
+				SpreadFactorCharged: sdk.NewCoin(DenomTwo, osmomath.ZeroInt()),
+				TakerFeeCharged:     sdk.NewCoin(DenomTwo, osmomath.ZeroInt()),
+			},
+			{
+				// 300 * 2 = 600 after the taker fee, 600 / (1 - 0.5) before
+				TokenIn:        sdk.NewCoin(DenomOne, osmomath.NewInt(1200)),
+				TokenOut:       sdk.NewCoin(DenomTwo, osmomath.NewInt(300)),
+				SpotPrice:      osmomath.OneDec(),
+				EffectivePrice: osmomath.NewDecWithPrec(5, 1),
+				PriceImpact:    osmomath.NewDecWithPrec(-5, 1),
+				// 600 * 0.01
+				SpreadFactorCharged: sdk.NewCoin(DenomOne, osmomath.NewInt(6)),
+				TakerFeeCharged:     sdk.NewCoin(DenomOne, osmomath.NewInt(600)),
+			},
+		}
+
+		s.Require().Len(breakdowns, len(expectedBreakdowns))
+		for i, expected := range expectedBreakdowns {
+			s.validateSwapBreakdown(expected, breakdowns[i])
+		}
+
+		// The pool denoms are restored.
+		s.Require().Equal(DenomThree, r.Pools[0].GetTokenInDenom())
+		s.Require().Equal(DenomTwo, r.Pools[0].GetTokenOutDenom())
+	})
+}
+
+// validateSwapBreakdowns validates the swap breakdowns of the given result pools.
+func (s *RouterTestSuite) validateSwapBreakdowns(expectedBreakdowns []*domain.PoolSwapBreakdown, resultPools []domain.RoutablePool) {
+	s.Require().Len(resultPools, len(expectedBreakdowns))
+	for i, expected := range expectedBreakdowns {
+		resultPool, ok := resultPools[i].(domain.RoutableResultPool)
+		s.Require().True(ok)
+
+		s.validateSwapBreakdown(expected, resultPool.GetSwapBreakdown())
+	}
+}
+
+// validateSwapBreakdown validates that the given swap breakdowns are equal.
+func (s *RouterTestSuite) validateSwapBreakdown(expected, actual *domain.PoolSwapBreakdown) {
+	s.Require().NotNil(actual)
+	s.Require().Equal(expected.TokenIn.String(), actual.TokenIn.String())
+	s.Require().Equal(expected.TokenOut.String(), actual.TokenOut.String())
+	s.Require().Equal(expected.SpotPrice.String(), actual.SpotPrice.String())
+	s.Require().Equal(expected.EffectivePrice.String(), actual.EffectivePrice.String())
+	s.Require().Equal(expected.PriceImpact.String(), actual.PriceImpact.String())
+	s.Require().Equal(expected.SpreadFactorCharged.String(), actual.SpreadFactorCharged.String())
+	s.Require().Equal(expected.TakerFeeCharged.String(), actual.TakerFeeCharged.String())
+}
+
 func WithRoutePools(r route.RouteImpl, pools []domain.RoutablePool) route.RouteImpl {
 	return routertesting.WithRoutePools(r, pools)
 }
@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"sync"
+	"time"
+
+	"github.com/osmosis-labs/sqs/domain"
+	"github.com/osmosis-labs/sqs/domain/cache"
+	"github.com/osmosis-labs/sqs/sqsdomain"
+)
+
+// routeCacheEntry identifies an entry of the route caches by the label of its cache and its key.
+type routeCacheEntry struct {
+	cacheType string
+	key       string
+}
+
+// routeCacheIndex is the reverse index from the pool IDs to the route cache entries whose routes
+// go through the pools. It allows evicting the entries affected by the pools updated within a block
+// instead of waiting for them to expire.
+//
+// The index only tracks the entries set through it. The entries that expire by TTL remain
+// in the index until they are overwritten or evicted. Their number is bounded by the number of cache keys.
+type routeCacheIndex struct {
+	mu              sync.Mutex
+	entriesByPoolID map[uint64]map[routeCacheEntry]struct{}
+	poolIDsByEntry  map[routeCacheEntry]map[uint64]struct{}
+}
+
+// newRouteCacheIndex returns a new empty route cache index.
+func newRouteCacheIndex() *routeCacheIndex {
+	return &routeCacheIndex{
+		entriesByPoolID: make(map[uint64]map[routeCacheEntry]struct{}),
+		poolIDsByEntry:  make(map[routeCacheEntry]map[uint64]struct{}),
+	}
+}
+
+// add indexes the entry by the given pool IDs, replacing the pool IDs it was previously indexed by.
+func (i *routeCacheIndex) add(entry routeCacheEntry, poolIDs map[uint64]struct{}) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.removeUnsafe(entry)
+
+	if len(poolIDs) == 0 {
+		return
+	}
+
+	entryPoolIDs := make(map[uint64]struct{}, len(poolIDs))
+	for poolID := range poolIDs {
+		entries, ok := i.entriesByPoolID[poolID]
+		if !ok {
+			entries = make(map[routeCacheEntry]struct{})
+			i.entriesByPoolID[poolID] = entries
+		}
+		entries[entry] = struct{}{}
+		entryPoolIDs[poolID] = struct{}{}
+	}
+	i.poolIDsByEntry[entry] = entryPoolIDs
+}
+
+// remove drops the entry from the index.
+func (i *routeCacheIndex) remove(entry routeCacheEntry) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.removeUnsafe(entry)
+}
+
+// popEntries drops the entries indexed by any of the given pool IDs from the index and returns them.
+func (i *routeCacheIndex) popEntries(poolIDs map[uint64]struct{}) []routeCacheEntry {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	var poppedEntries []routeCacheEntry
+	for poolID := range poolIDs {
+		for entry := range i.entriesByPoolID[poolID] {
+			poppedEntries = append(poppedEntries, entry)
+			i.removeUnsafe(entry)
+		}
+	}
+
+	return poppedEntries
+}
+
+// len returns the number of entries in the index.
+func (i *routeCacheIndex) len() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return len(i.poolIDsByEntry)
+}
+
+// removeUnsafe drops the entry from the index.
+// CONTRACT: the caller holds the mutex.
+func (i *routeCacheIndex) removeUnsafe(entry routeCacheEntry) {
+	for poolID := range i.poolIDsByEntry[entry] {
+		entries := i.entriesByPoolID[poolID]
+		delete(entries, entry)
+		if len(entries) == 0 {
+			delete(i.entriesByPoolID, poolID)
+		}
+	}
+	delete(i.poolIDsByEntry, entry)
+}
+
+// getRouteCache returns the route cache with the given label.
+func (r *routerUseCaseImpl) getRouteCache(cacheType string) *cache.Cache {
+	if cacheType == rankedRouteCacheLabel {
+		return r.rankedRouteCache
+	}
+	return r.candidateRouteCache
+}
+
+// isRouteCacheInvalidationEnabled returns true if the entries of the route cache with the given label
+// are evicted when their pools are updated.
+// The ranked routes are evicted since they are ordered by the quotes against the pool reserves.
+// The candidate routes only depend on the pool liquidity filters that change slowly. Evicting them on every update
+// of their pools would recompute them on most requests for the active pairs, so they are only evicted if configured.
+func (r *routerUseCaseImpl) isRouteCacheInvalidationEnabled(cacheType string) bool {
+	if !r.defaultConfig.RouteCacheInvalidationEnabled {
+		return false
+	}
+
+	return cacheType == rankedRouteCacheLabel || r.defaultConfig.CandidateRouteCacheInvalidationEnabled
+}
+
+// setRouteCache sets the routes under the key of the route cache with the given label and indexes the entry
+// by the pool IDs of the routes if the invalidation of the route cache is enabled.
+func (r *routerUseCaseImpl) setRouteCache(cacheType string, key string, routes sqsdomain.CandidateRoutes, expiration time.Duration) {
+	r.getRouteCache(cacheType).Set(key, routes, expiration)
+
+	if r.isRouteCacheInvalidationEnabled(cacheType) {
+		r.routeCacheIndex.add(routeCacheEntry{cacheType: cacheType, key: key}, routes.UniquePoolIDs)
+	}
+}
+
+// deleteRouteCache deletes the key from the route cache with the given label and from the index.
+func (r *routerUseCaseImpl) deleteRouteCache(cacheType string, key string) {
+	r.getRouteCache(cacheType).Delete(key)
+	r.routeCacheIndex.remove(routeCacheEntry{cacheType: cacheType, key: key})
+}
+
+// InvalidateRouteCaches implements mvc.RouterUsecase.
+func (r *routerUseCaseImpl) InvalidateRouteCaches(poolIDs map[uint64]struct{}) int {
+	if !r.defaultConfig.RouteCacheInvalidationEnabled {
+		return 0
+	}
+
+	evictedEntries := r.routeCacheIndex.popEntries(poolIDs)
+	for _, entry := range evictedEntries {
+		r.getRouteCache(entry.cacheType).Delete(entry.key)
+		domain.SQSRoutesCacheEvictionsCounter.WithLabelValues(entry.cacheType).Inc()
+	}
+
+	return len(evictedEntries)
+}
@@ -53,35 +53,13 @@ func ValidateAndSortPools(pools []sqsdomain.PoolI, cosmWasmPoolsConfig domain.Co
 
 	// Make a copy and filter pools
 	for _, pool := range pools {
-		// TODO: the zero argument can be removed in a future release
-		// since we will be filtering at a different layer of abstraction.
-		if err := pool.Validate(zero); err != nil {
-			logger.Debug("pool validation failed, skip silently", zap.Uint64("pool_id", pool.GetId()), zap.Error(err))
+		isValid, isOrderbook := validatePool(pool, cosmWasmPoolsConfig, logger)
+		if !isValid {
 			continue
 		}
 
-		// Confirm that a cosmwasm code ID is whitelisted via config.
-		if pool.GetType() == poolmanagertypes.CosmWasm {
-			cosmWasmPool, ok := pool.GetUnderlyingPool().(cosmwasmpooltypes.CosmWasmExtension)
-			if !ok {
-				logger.Debug("failed to cast a cosm wasm pool, skip silently", zap.Uint64("pool_id", pool.GetId()))
-				continue
-			}
-
-			_, isTransmuterCodeID := cosmWasmPoolsConfig.TransmuterCodeIDs[cosmWasmPool.GetCodeId()]
-			_, isAlloyedTransmuterCodeID := cosmWasmPoolsConfig.AlloyedTransmuterCodeIDs[cosmWasmPool.GetCodeId()]
-			_, isOrderbookCodeID := cosmWasmPoolsConfig.OrderbookCodeIDs[cosmWasmPool.GetCodeId()]
-			_, isGeneralCosmWasmCodeID := cosmWasmPoolsConfig.GeneralCosmWasmCodeIDs[cosmWasmPool.GetCodeId()]
-
-			if !(isTransmuterCodeID || isAlloyedTransmuterCodeID || isOrderbookCodeID || isGeneralCosmWasmCodeID) {
-				logger.Debug("cw pool code id is not added to config, skip silently", zap.Uint64("pool_id", pool.GetId()))
-
-				continue
-			}
-
-			if isOrderbookCodeID {
-				orderbookPools = append(orderbookPools, pool)
-			}
+		if isOrderbook {
+			orderbookPools = append(orderbookPools, pool)
 		}
 
 		filteredPools = append(filteredPools, pool)
@@ -96,7 +74,47 @@ func ValidateAndSortPools(pools []sqsdomain.PoolI, cosmWasmPoolsConfig domain.Co
 
 	logger.Debug("validated pools", zap.Int("num_pools", len(filteredPools)))
 
-	return sortPools(filteredPools, cosmWasmPoolsConfig.TransmuterCodeIDs, totalTVL, preferredPoolIDsMap, logger), orderbookPools
+	return sortPools(filteredPools, cosmWasmPoolsConfig, totalTVL, preferredPoolIDsMap, logger), orderbookPools
+}
+
+// validatePool returns true if the given pool may be used in the router.
+// That is, the pool passes validation and, if it is a cosmwasm pool, its code ID is whitelisted via config.
+// As a second return value, it returns true if the pool is an orderbook pool.
+func validatePool(pool sqsdomain.PoolI, cosmWasmPoolsConfig domain.CosmWasmPoolRouterConfig, logger log.Logger) (isValid bool, isOrderbook bool) {
+	// TODO: the zero argument can be removed in a future release
+	// since we will be filtering at a different layer of abstraction.
+	if err := pool.Validate(zero); err != nil {
+		logger.Debug("pool validation failed, skip silently", zap.Uint64("pool_id", pool.GetId()), zap.Error(err))
+		return false, false
+	}
+
+	// Confirm that a cosmwasm code ID is whitelisted via config.
+	if pool.GetType() != poolmanagertypes.CosmWasm {
+		return true, false
+	}
+
+	cosmWasmPool, ok := pool.GetUnderlyingPool().(cosmwasmpooltypes.CosmWasmExtension)
+	if !ok {
+		logger.Debug("failed to cast a cosm wasm pool, skip silently", zap.Uint64("pool_id", pool.GetId()))
+		return false, false
+	}
+
+	// The pools matched by a configured implementation are supported regardless of their code ID.
+	if implementationName, ok := cosmWasmPoolsConfig.GetImplementation(pool.GetSQSPoolModel().CosmWasmPoolModel); ok {
+		return true, implementationName == domain.OrderbookCosmWasmPoolImplementation
+	}
+
+	_, isTransmuterCodeID := cosmWasmPoolsConfig.TransmuterCodeIDs[cosmWasmPool.GetCodeId()]
+	_, isAlloyedTransmuterCodeID := cosmWasmPoolsConfig.AlloyedTransmuterCodeIDs[cosmWasmPool.GetCodeId()]
+	_, isOrderbookCodeID := cosmWasmPoolsConfig.OrderbookCodeIDs[cosmWasmPool.GetCodeId()]
+	_, isGeneralCosmWasmCodeID := cosmWasmPoolsConfig.GeneralCosmWasmCodeIDs[cosmWasmPool.GetCodeId()]
+
+	if !(isTransmuterCodeID || isAlloyedTransmuterCodeID || isOrderbookCodeID || isGeneralCosmWasmCodeID) {
+		logger.Debug("cw pool code id is not added to config, skip silently", zap.Uint64("pool_id", pool.GetId()))
+		return false, false
+	}
+
+	return true, isOrderbookCodeID
 }
 
 // sortPools sorts the given pools so that the most appropriate pools are at the top.
@@ -117,58 +135,32 @@ func ValidateAndSortPools(pools []sqsdomain.PoolI, cosmWasmPoolsConfig domain.Co
 // - Pools with no error in TVL are prioritized by getting an even smaller boost.
 //
 // These heuristics are imperfect and subject to change.
-func sortPools(pools []sqsdomain.PoolI, transmuterCodeIDs map[uint64]struct{}, totalTVL osmomath.Int, preferredPoolIDsMap map[uint64]struct{}, logger log.Logger) []sqsdomain.PoolI {
+func sortPools(pools []sqsdomain.PoolI, cosmWasmPoolsConfig domain.CosmWasmPoolRouterConfig, totalTVL osmomath.Int, preferredPoolIDsMap map[uint64]struct{}, logger log.Logger) []sqsdomain.PoolI {
 	logger.Debug("total tvl", zap.Stringer("total_tvl", totalTVL))
 	totalTVLFloat, _ := totalTVL.BigIntMut().Float64()
 
-	ratedPools := make([]ratedPool, 0, len(pools))
-	for _, pool := range pools {
-		// Initialize rating to TVL.
-		rating, _ := pool.GetPoolLiquidityCap().BigIntMut().Float64()
+	ratedPools := rateAndSortPools(pools, cosmWasmPoolsConfig, totalTVLFloat, preferredPoolIDsMap, logger)
 
-		// rating += 1/ 100 of TVL of asset across all pools
-		// (Ignoring any pool with an error in TVL)
-		if strings.TrimSpace(pool.GetSQSPoolModel().PoolLiquidityCapError) == noPoolLiquidityCapError {
-			rating += totalTVLFloat / 100
-		}
-
-		// Preferred pools get a boost equal to the total value locked across all pools
-		_, isPreferred := preferredPoolIDsMap[pool.GetId()]
-		if isPreferred {
-			rating += totalTVLFloat
-		}
+	logger.Debug("sorted pools", zap.Int("pool_count", len(ratedPools)))
+	// Convert back to pools
+	for i, ratedPool := range ratedPools {
+		pool := ratedPool.pool
 
-		// Concentrated pools get a boost equal to 1/2 of total value locked across all pools
-		isConcentrated := pool.GetType() == poolmanagertypes.Concentrated
-		if isConcentrated {
-			rating += totalTVLFloat / 2
-		}
+		sqsModel := pool.GetSQSPoolModel()
+		logger.Debug("pool", zap.Int("index", i), zap.Any("pool", pool.GetId()), zap.Float64("rate", ratedPool.rating), zap.Stringer("pool_liquidity_cap", sqsModel.PoolLiquidityCap), zap.String("pool_liquidity_cap_error", sqsModel.PoolLiquidityCapError))
+		pools[i] = ratedPool.pool
+	}
+	return pools
+}
 
-		// Transmuter pools get a boost equal to 3/2 of total value locked across all pools
-		if pool.GetType() == poolmanagertypes.CosmWasm {
-			// Grant additional rating to alloyed transmuter.
-			cosmWasmPoolModel := pool.GetSQSPoolModel().CosmWasmPoolModel
-			if cosmWasmPoolModel != nil {
-				if cosmWasmPoolModel.IsAlloyTransmuter() {
-					// Grant additional rating if alloyed transmuter.
-					rating += totalTVLFloat * 1.5
-				} else if cosmWasmPoolModel.IsOrderbook() {
-					// Orderbook is ranked the highest so that its limits are considered
-					// frequently.
-					rating += totalTVLFloat * 2
-				}
-			} else {
-				// Grant additional rating if transmuter.
-				cosmWasmPool, ok := pool.GetUnderlyingPool().(cosmwasmpooltypes.CosmWasmExtension)
-				if !ok {
-					logger.Debug("failed to cast a cosm wasm pool, skip silently", zap.Uint64("pool_id", pool.GetId()))
-					continue
-				}
-				_, isTransmuter := transmuterCodeIDs[cosmWasmPool.GetCodeId()]
-				if isTransmuter {
-					rating += totalTVLFloat * 1.5
-				}
-			}
+// rateAndSortPools rates the given pools and sorts them by the rating in descending order.
+// See sortPools for details.
+func rateAndSortPools(pools []sqsdomain.PoolI, cosmWasmPoolsConfig domain.CosmWasmPoolRouterConfig, totalTVLFloat float64, preferredPoolIDsMap map[uint64]struct{}, logger log.Logger) []ratedPool {
+	ratedPools := make([]ratedPool, 0, len(pools))
+	for _, pool := range pools {
+		rating, ok := ratePool(pool, cosmWasmPoolsConfig, totalTVLFloat, preferredPoolIDsMap, logger)
+		if !ok {
+			continue
 		}
 
 		ratedPools = append(ratedPools, ratedPool{
@@ -182,14 +174,68 @@ func sortPools(pools []sqsdomain.PoolI, transmuterCodeIDs map[uint64]struct{}, t
 		return ratedPools[i].rating > ratedPools[j].rating
 	})
 
-	logger.Debug("sorted pools", zap.Int("pool_count", len(ratedPools)))
-	// Convert back to pools
-	for i, ratedPool := range ratedPools {
-		pool := ratedPool.pool
+	return ratedPools
+}
 
-		sqsModel := pool.GetSQSPoolModel()
-		logger.Debug("pool", zap.Int("index", i), zap.Any("pool", pool.GetId()), zap.Float64("rate", ratedPool.rating), zap.Stringer("pool_liquidity_cap", sqsModel.PoolLiquidityCap), zap.String("pool_liquidity_cap_error", sqsModel.PoolLiquidityCapError))
-		pools[i] = ratedPool.pool
+// ratePool returns the rating of the given pool given the total value locked across all pools.
+// See sortPools for details.
+// Returns false if the pool is a cosmwasm pool that fails to cast and must be skipped.
+func ratePool(pool sqsdomain.PoolI, cosmWasmPoolsConfig domain.CosmWasmPoolRouterConfig, totalTVLFloat float64, preferredPoolIDsMap map[uint64]struct{}, logger log.Logger) (float64, bool) {
+	// Initialize rating to TVL.
+	rating, _ := pool.GetPoolLiquidityCap().BigIntMut().Float64()
+
+	// rating += 1/ 100 of TVL of asset across all pools
+	// (Ignoring any pool with an error in TVL)
+	if strings.TrimSpace(pool.GetSQSPoolModel().PoolLiquidityCapError) == noPoolLiquidityCapError {
+		rating += totalTVLFloat / 100
 	}
-	return pools
+
+	// Preferred pools get a boost equal to the total value locked across all pools
+	_, isPreferred := preferredPoolIDsMap[pool.GetId()]
+	if isPreferred {
+		rating += totalTVLFloat
+	}
+
+	// Concentrated pools get a boost equal to 1/2 of total value locked across all pools
+	isConcentrated := pool.GetType() == poolmanagertypes.Concentrated
+	if isConcentrated {
+		rating += totalTVLFloat / 2
+	}
+
+	// Transmuter pools get a boost equal to 3/2 of total value locked across all pools
+	if pool.GetType() == poolmanagertypes.CosmWasm {
+		// Grant additional rating to alloyed transmuter.
+		cosmWasmPoolModel := pool.GetSQSPoolModel().CosmWasmPoolModel
+		if implementationName, ok := cosmWasmPoolsConfig.GetImplementation(cosmWasmPoolModel); ok {
+			// The pools matched by a configured implementation are rated by its name regardless of their code ID.
+			switch implementationName {
+			case domain.TransmuterCosmWasmPoolImplementation, domain.AlloyedTransmuterCosmWasmPoolImplementation:
+				rating += totalTVLFloat * 1.5
+			case domain.OrderbookCosmWasmPoolImplementation:
+				rating += totalTVLFloat * 2
+			}
+		} else if cosmWasmPoolModel != nil {
+			if cosmWasmPoolModel.IsAlloyTransmuter() {
+				// Grant additional rating if alloyed transmuter.
+				rating += totalTVLFloat * 1.5
+			} else if cosmWasmPoolModel.IsOrderbook() {
+				// Orderbook is ranked the highest so that its limits are considered
+				// frequently.
+				rating += totalTVLFloat * 2
+			}
+		} else {
+			// Grant additional rating if transmuter.
+			cosmWasmPool, ok := pool.GetUnderlyingPool().(cosmwasmpooltypes.CosmWasmExtension)
+			if !ok {
+				logger.Debug("failed to cast a cosm wasm pool, skip silently", zap.Uint64("pool_id", pool.GetId()))
+				return 0, false
+			}
+			_, isTransmuter := cosmWasmPoolsConfig.TransmuterCodeIDs[cosmWasmPool.GetCodeId()]
+			if isTransmuter {
+				rating += totalTVLFloat * 1.5
+			}
+		}
+	}
+
+	return rating, true
 }
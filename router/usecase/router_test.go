@@ -221,7 +221,7 @@ func (s *RouterTestSuite) TestRouterSorting() {
 		totalTVL = totalTVL.Add(pool.GetPoolLiquidityCap())
 	}
 
-	sortedPools := routerusecase.SortPools(defaultAllPools, cosmWasmPoolConfig.TransmuterCodeIDs, totalTVL, map[uint64]struct{}{
+	sortedPools := routerusecase.SortPools(defaultAllPools, cosmWasmPoolConfig, totalTVL, map[uint64]struct{}{
 		allPool.BalancerPoolID: {},
 	}, logger)
 
@@ -230,6 +230,45 @@ func (s *RouterTestSuite) TestRouterSorting() {
 	s.Require().Equal(expectedSortedPoolIDs, sortedPoolIDs)
 }
 
+// Validates that the transmuter pools matched by a configured implementation are granted
+// the transmuter rating boost even if their code ID is not configured.
+func (s *RouterTestSuite) TestRatePool_RegistryOnlyTransmuter() {
+	const totalTVL = float64(1000)
+
+	transmuterMatcher, err := cosmwasmpool.NewContractMatcher(cosmwasmpool.ALLOY_TRANSMUTER_CONTRACT_NAME, "< "+cosmwasmpool.ALLOY_TRANSMUTER_MIN_CONTRACT_VERSION)
+	s.Require().NoError(err)
+
+	registryOnlyTransmuterPool := &sqsdomain.PoolWrapper{
+		ChainModel: &mocks.ChainPoolMock{ID: 1, Type: poolmanagertypes.CosmWasm},
+		SQSModel: sqsdomain.SQSPool{
+			PoolLiquidityCap:      osmomath.ZeroInt(),
+			PoolLiquidityCapError: dummyPoolLiquidityCapErrorStr,
+			CosmWasmPoolModel: &cosmwasmpool.CosmWasmPoolModel{
+				ContractInfo: cosmwasmpool.ContractInfo{
+					Contract: cosmwasmpool.ALLOY_TRANSMUTER_CONTRACT_NAME,
+					Version:  "2.0.0",
+				},
+			},
+		},
+	}
+
+	registryConfig := domain.CosmWasmPoolRouterConfig{
+		TransmuterCodeIDs: map[uint64]struct{}{},
+		Implementations: []domain.CosmWasmPoolImplementation{
+			{Matcher: transmuterMatcher, Name: domain.TransmuterCosmWasmPoolImplementation},
+		},
+	}
+
+	rating, ok := routerusecase.RatePool(registryOnlyTransmuterPool, registryConfig, totalTVL, map[uint64]struct{}{}, &log.NoOpLogger{})
+	s.Require().True(ok)
+	s.Require().Equal(totalTVL*1.5, rating)
+
+	// Without the implementation, the pool is not recognized as a transmuter.
+	rating, ok = routerusecase.RatePool(registryOnlyTransmuterPool, routertesting.EmpyCosmWasmPoolRouterConfig, totalTVL, map[uint64]struct{}{}, &log.NoOpLogger{})
+	s.Require().True(ok)
+	s.Require().Zero(rating)
+}
+
 // getTakerFeeMapForAllPoolTokenPairs returns a map of all pool token pairs to their taker fees.
 func (s *RouterTestSuite) getTakerFeeMapForAllPoolTokenPairs(pools []sqsdomain.PoolI) sqsdomain.TakerFeeMap {
 	pairs := make(sqsdomain.TakerFeeMap, 0)
@@ -9,6 +9,7 @@ import (
 
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
 
 	"github.com/osmosis-labs/osmosis/osmomath"
 	"github.com/osmosis-labs/osmosis/osmoutils"
@@ -44,6 +45,12 @@ type routerUseCaseImpl struct {
 	sortedPools   []sqsdomain.PoolI
 
 	candidateRouteCache *cache.Cache
+
+	// routeCacheIndex indexes the entries of the route caches by the pool IDs of their routes for eviction.
+	routeCacheIndex *routeCacheIndex
+
+	// rankedRoutesGroup coalesces the concurrent computations of the ranked routes by their cache key.
+	rankedRoutesGroup singleflight.Group
 }
 
 const (
@@ -70,12 +77,30 @@ func NewRouterUsecase(tokensRepository mvc.RouterRepository, poolsUsecase mvc.Po
 
 		rankedRouteCache:    rankedRouteCache,
 		candidateRouteCache: candidateRouteCache,
+		routeCacheIndex:     newRouteCacheIndex(),
 
 		sortedPools:   make([]sqsdomain.PoolI, 0),
 		sortedPoolsMu: sync.RWMutex{},
 	}
 }
 
+// defaultRouterOptions returns the router options configured by default, before the options of the request are applied.
+func (r *routerUseCaseImpl) defaultRouterOptions() domain.RouterOptions {
+	return domain.RouterOptions{
+		MaxPoolsPerRoute:                 r.defaultConfig.MaxPoolsPerRoute,
+		MaxRoutes:                        r.defaultConfig.MaxRoutes,
+		MinPoolLiquidityCap:              r.defaultConfig.MinPoolLiquidityCap,
+		CandidateRouteCacheExpirySeconds: r.defaultConfig.CandidateRouteCacheExpirySeconds,
+		RankedRouteCacheExpirySeconds:    r.defaultConfig.RankedRouteCacheExpirySeconds,
+		MaxSplitRoutes:                   r.defaultConfig.MaxSplitRoutes,
+		SplitIncrements:                  r.defaultConfig.SplitIncrements,
+		SplitRefinementRounds:            r.defaultConfig.SplitRefinementRounds,
+		DisableCache:                     !r.defaultConfig.RouteCacheEnabled,
+		CandidateRoutesPoolFiltersAnyOf:  []domain.CandidateRoutePoolFiltrerCb{},
+		ComputeDeadline:                  time.Duration(r.defaultConfig.QuoteComputeDeadlineMs) * time.Millisecond,
+	}
+}
+
 // GetOptimalQuote returns the optimal quote by estimating the optimal route(s) through pools
 // on the osmosis network.
 // Uses default router config if no options parameter is provided.
@@ -85,31 +110,34 @@ func NewRouterUsecase(tokensRepository mvc.RouterRepository, poolsUsecase mvc.Po
 // are present in cache, they are used without re-computing them. Otherwise, they are computed and cached.
 // In the future, we will support caching of ranked routes that are constructed from candidate and sorted
 // by the decreasing amount out within an order of magnitude of token in. Similarly, We will also support optimal split caching
+// If the compute deadline is exceeded, the best quote found so far is returned flagged as partial.
 // Returns error if:
 // - fails to estimate direct quotes for ranked routes
 // - fails to retrieve candidate routes
+// - the compute deadline is exceeded before any route is quoted
 func (r *routerUseCaseImpl) GetOptimalQuote(ctx context.Context, tokenIn sdk.Coin, tokenOutDenom string, opts ...domain.RouterOption) (domain.Quote, error) {
-	options := domain.RouterOptions{
-		MaxPoolsPerRoute:                 r.defaultConfig.MaxPoolsPerRoute,
-		MaxRoutes:                        r.defaultConfig.MaxRoutes,
-		MinPoolLiquidityCap:              r.defaultConfig.MinPoolLiquidityCap,
-		CandidateRouteCacheExpirySeconds: r.defaultConfig.CandidateRouteCacheExpirySeconds,
-		RankedRouteCacheExpirySeconds:    r.defaultConfig.RankedRouteCacheExpirySeconds,
-		MaxSplitRoutes:                   r.defaultConfig.MaxSplitRoutes,
-		DisableCache:                     !r.defaultConfig.RouteCacheEnabled,
-		CandidateRoutesPoolFiltersAnyOf:  []domain.CandidateRoutePoolFiltrerCb{},
-	}
+	options := r.defaultRouterOptions()
 	// Apply options
 	for _, opt := range opts {
 		opt(&options)
 	}
 
-	var (
-		candidateRankedRoutes sqsdomain.CandidateRoutes
-		err                   error
-	)
+	// Once the compute deadline is exceeded, the best quote found so far is returned flagged as partial.
+	ctx, cancel := withComputeDeadline(ctx, options.ComputeDeadline)
+	defer cancel()
 
-	if !options.DisableCache {
+	explainer := newQuoteExplainer(options.Explain)
+
+	gasRanker, err := newGasCostRanker(r.defaultConfig.GasCostModel, options.GasPriceInTokenOut)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidateRankedRoutes sqsdomain.CandidateRoutes
+
+	// The cached ranked routes are ranked by the amount out alone.
+	// As a result, the gas-aware ranking neither reads nor writes them.
+	if !options.DisableCache && gasRanker == nil {
 		// Get an order of magnitude for the token in amount
 		// This is used for caching ranked routes as these might differ depending on the amount swapped in.
 		tokenInOrderOfMagnitude := GetPrecomputeOrderOfMagnitude(tokenIn.Amount)
@@ -118,6 +146,8 @@ func (r *routerUseCaseImpl) GetOptimalQuote(ctx context.Context, tokenIn sdk.Coi
 		if err != nil {
 			return nil, err
 		}
+
+		explainer.setRankedRoutesCacheHit(len(candidateRankedRoutes.Routes) > 0)
 	}
 
 	var (
@@ -130,31 +160,41 @@ func (r *routerUseCaseImpl) GetOptimalQuote(ctx context.Context, tokenIn sdk.Coi
 	if len(candidateRankedRoutes.Routes) == 0 {
 		// Get the dynamic min pool liquidity cap for the given token in and token out denoms.
 		dynamicMinPoolLiquidityCap, err := r.tokenMetadataHolder.GetMinPoolLiquidityCap(tokenIn.Denom, tokenOutDenom)
-		if err == nil {
-			// Set the dynamic min pool liquidity cap only if there is no error retrieving it.
-			// Otherwise, use the default.
+		if err == nil && !options.DisableDynamicMinPoolLiquidityCap {
+			// Set the dynamic min pool liquidity cap only if there is no error retrieving it
+			// and it is not disabled by the options. Otherwise, use the one from options.
 			options.MinPoolLiquidityCap = r.ConvertMinTokensPoolLiquidityCapToFilter(dynamicMinPoolLiquidityCap)
 		}
 
-		// Find candidate routes and rank them by direct quotes.
-		topSingleRouteQuote, rankedRoutes, err = r.computeAndRankRoutesByDirectQuote(ctx, tokenIn, tokenOutDenom, options)
+		explainer.setMinPoolLiquidityCap(options.MinPoolLiquidityCap)
+
+		// Find candidate routes and rank them by direct quotes, sharing the ranking
+		// with the concurrent identical requests.
+		topSingleRouteQuote, rankedRoutes, err = r.coalescedComputeAndRankRoutesByDirectQuote(ctx, tokenIn, tokenOutDenom, options, explainer, gasRanker)
 		if err != nil {
-			return nil, err
+			return nil, computeDeadlineError(ctx, computeDeadlineStageRanking, err)
 		}
 	} else {
 		// Otherwise, simply compute quotes over cached ranked routes
-		topSingleRouteQuote, rankedRoutes, err = r.rankRoutesByDirectQuote(ctx, candidateRankedRoutes, tokenIn, tokenOutDenom, options.MaxSplitRoutes)
+		topSingleRouteQuote, rankedRoutes, err = r.rankRoutesByDirectQuote(ctx, candidateRankedRoutes, tokenIn, tokenOutDenom, options.MaxSplitRoutes, explainer, gasRanker)
 		if err != nil {
-			return nil, err
+			return nil, computeDeadlineError(ctx, computeDeadlineStageRanking, err)
 		}
 	}
 
+	// The compute deadline was exceeded while ranking the routes so there is no time left for the splits.
+	if isPartialQuote(topSingleRouteQuote) {
+		setPartialQuote(topSingleRouteQuote, computeDeadlineStageRanking)
+		return topSingleRouteQuote, nil
+	}
+
 	if len(rankedRoutes) == 1 || options.MaxSplitRoutes == domain.DisableSplitRoutes {
 		return topSingleRouteQuote, nil
 	}
 
 	// Filter out generalized cosmWasm pool routes
-	rankedRoutes = filterOutGeneralizedCosmWasmPoolRoutes(rankedRoutes)
+	rankedRoutes = r.filterGeneralizedCosmWasmPoolRoutesForSplits(rankedRoutes)
+	explainer.recordDroppedRoutes(rankedRoutes, domain.RouteDropReasonGeneralizedCosmWasmPool)
 
 	// If filtering leads to a single route left, return it.
 	if len(rankedRoutes) == 1 {
@@ -162,8 +202,16 @@ func (r *routerUseCaseImpl) GetOptimalQuote(ctx context.Context, tokenIn sdk.Coi
 	}
 
 	// Compute split route quote
-	topSplitQuote, err := getSplitQuote(ctx, rankedRoutes, tokenIn)
+	topSplitQuote, err := getSplitQuote(ctx, rankedRoutes, tokenIn, newSplitOptions(options))
 	if err != nil {
+		explainer.recordSplitQuote(nil, domain.TokenSwapMethodExactIn, false, err)
+
+		// If the compute deadline was exceeded while splitting, the single route quote
+		// is the best one found so far.
+		if isComputeDeadlineExceeded(ctx) {
+			setPartialQuote(topSingleRouteQuote, computeDeadlineStageSplit)
+		}
+
 		// If error occurs in splits, return the single route quote
 		// rather than failing.
 		return topSingleRouteQuote, nil
@@ -171,8 +219,25 @@ func (r *routerUseCaseImpl) GetOptimalQuote(ctx context.Context, tokenIn sdk.Coi
 
 	finalQuote := topSingleRouteQuote
 
-	// If the split route quote is better than the single route quote, return the split route quote
-	if topSplitQuote.GetAmountOut().GT(topSingleRouteQuote.GetAmountOut()) {
+	// If the split route quote is better than the single route quote, return the split route quote.
+	// For the gas-aware ranking, the quotes are compared by the amount out net of the estimated gas cost.
+	singleRouteNetAmountOut, err := gasRanker.netAmountOut(topSingleRouteQuote)
+	if err != nil {
+		return nil, err
+	}
+
+	gasRanker.setGasEstimate(ctx, topSplitQuote)
+	splitNetAmountOut, err := gasRanker.netAmountOut(topSplitQuote)
+	if err != nil {
+		explainer.recordSplitQuote(nil, domain.TokenSwapMethodExactIn, false, err)
+
+		// The split that fails the gas estimate cannot be compared with the single route quote.
+		// As with the other split errors, return the single route quote rather than failing.
+		return topSingleRouteQuote, nil
+	}
+
+	isSplitSelected := splitNetAmountOut.GT(singleRouteNetAmountOut)
+	if isSplitSelected {
 		routes := topSplitQuote.GetRoute()
 
 		r.logger.Debug("split route selected", zap.Int("route_count", len(routes)))
@@ -180,6 +245,14 @@ func (r *routerUseCaseImpl) GetOptimalQuote(ctx context.Context, tokenIn sdk.Coi
 		finalQuote = topSplitQuote
 	}
 
+	// If the compute deadline was exceeded while refining the split, the final quote
+	// is the best one found so far regardless of whether the split is selected.
+	if isPartialQuote(topSplitQuote) {
+		setPartialQuote(finalQuote, computeDeadlineStageRefinement)
+	}
+
+	explainer.recordSplitQuote(topSplitQuote.GetRoute(), domain.TokenSwapMethodExactIn, isSplitSelected, nil)
+
 	r.logger.Debug("single route selected", zap.Stringer("route", finalQuote.GetRoute()[0]))
 
 	if finalQuote.GetAmountOut().IsZero() {
@@ -190,31 +263,99 @@ func (r *routerUseCaseImpl) GetOptimalQuote(ctx context.Context, tokenIn sdk.Coi
 }
 
 // GetOptimalQuoteInGivenOut returns an optimal quote through the pools for the exact amount out token swap method.
-// Underlying implementation is the same as GetOptimalQuote, but the returned quote is wrapped in a quoteExactAmountOut.
-func (r *routerUseCaseImpl) GetOptimalQuoteInGivenOut(ctx context.Context, tokenIn sdk.Coin, tokenOutDenom string, opts ...domain.RouterOption) (domain.Quote, error) {
-	// Disable cache and add orderbook pool filter
-	// So that order-book pools are not used in the candidate route search.
-	// The reason is that order-book contract does not implement the MsgSwapExactAmountOut API.
-	// The reason we disable cache is so that the exluded candidate routes do not interfere with the main
-	// "out given in" API.
-	opts = append(opts,
-		domain.WithDisableCache(),
-		domain.WithCandidateRoutesPoolFiltersAnyOf(domain.ShouldSkipOrderbookPool),
-	)
+// The candidate routes are ranked by the amount of token in required to receive the exact token out.
+// Then, the token out is split among the top ranked routes so that the total amount of token in is minimized.
+// The best of the single route and the split quotes is returned wrapped in a quoteExactAmountOut.
+// Orderbook pools are included. Since orderbook contract does not implement the MsgSwapExactAmountOut API,
+// they are flagged in the result to be executed as exact amount in with the computed token in.
+// The compute deadline is only supported for the exact amount in swap method so it is ignored here.
+// The quote requests setting it for the exact amount out swap method are rejected by their validation.
+// Returns error if:
+// - fails to retrieve candidate routes
+// - none of the routes can produce the token out
+func (r *routerUseCaseImpl) GetOptimalQuoteInGivenOut(ctx context.Context, tokenOut sdk.Coin, tokenInDenom string, opts ...domain.RouterOption) (domain.Quote, error) {
+	options := r.defaultRouterOptions()
+	// Apply options
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	explainer := newQuoteExplainer(options.Explain)
+
+	// Get the dynamic min pool liquidity cap for the given token in and token out denoms.
+	dynamicMinPoolLiquidityCap, err := r.tokenMetadataHolder.GetMinPoolLiquidityCap(tokenInDenom, tokenOut.Denom)
+	if err == nil && !options.DisableDynamicMinPoolLiquidityCap {
+		// Set the dynamic min pool liquidity cap only if there is no error retrieving it
+		// and it is not disabled by the options. Otherwise, use the one from options.
+		options.MinPoolLiquidityCap = r.ConvertMinTokensPoolLiquidityCapToFilter(dynamicMinPoolLiquidityCap)
+	}
+
+	explainer.setMinPoolLiquidityCap(options.MinPoolLiquidityCap)
 
-	quote, err := r.GetOptimalQuote(ctx, tokenIn, tokenOutDenom, opts...)
+	candidateRouteSearchOptions := r.newCandidateRouteSearchOptions(options)
+	candidateRouteSearchOptions.LiquidityFilteredPoolCb = explainer.liquidityFilteredPoolCb()
+
+	// Candidate routes are searched from token out to token in so that the first pool
+	// in each route is validated to have enough of the token out.
+	candidateRoutes, isCandidateRoutesCacheHit, err := r.handleCandidateRoutes(ctx, tokenOut, tokenInDenom, candidateRouteSearchOptions)
 	if err != nil {
+		r.logger.Error("error handling routes", zap.Error(err))
 		return nil, err
 	}
 
-	q, ok := quote.(*quoteExactAmountIn)
-	if !ok {
-		return nil, errors.New("quote is not a quoteExactAmountIn")
+	explainer.setCandidateRoutesCacheHit(isCandidateRoutesCacheHit)
+
+	reversedRoutes, err := r.poolsUsecase.GetRoutesFromCandidates(candidateRoutes, tokenOut.Denom, tokenInDenom)
+	if err != nil {
+		return nil, err
 	}
 
-	return &quoteExactAmountOut{
-		quoteExactAmountIn: q,
-	}, nil
+	// Reverse the routes so that they are ordered from token in to token out.
+	routes := make([]route.RouteImpl, 0, len(reversedRoutes))
+	for _, reversedRoute := range reversedRoutes {
+		routes = append(routes, reverseRoute(reversedRoute))
+	}
+
+	topSingleRouteQuote, routesWithAmtIn, err := r.estimateAndRankSingleRouteQuoteInGivenOut(ctx, routes, tokenOut, tokenInDenom, explainer, r.logger)
+	if err != nil {
+		return nil, fmt.Errorf("%s, tokenInDenom (%s)", err, tokenInDenom)
+	}
+
+	// Filter out routes with duplicate pool IDs and cut them for splits
+	rankedRoutes := filterAndConvertDuplicatePoolIDRankedRoutes(routesWithAmtIn)
+	explainer.recordDroppedRoutes(rankedRoutes, domain.RouteDropReasonDuplicatePool)
+	rankedRoutes = cutRoutesForSplits(options.MaxSplitRoutes, rankedRoutes)
+	explainer.recordDroppedRoutes(rankedRoutes, domain.RouteDropReasonMaxSplitRoutes)
+
+	finalQuote := topSingleRouteQuote
+
+	if len(rankedRoutes) > 1 && options.MaxSplitRoutes != domain.DisableSplitRoutes {
+		// Filter out generalized cosmWasm pool routes
+		rankedRoutes = r.filterGeneralizedCosmWasmPoolRoutesForSplits(rankedRoutes)
+		explainer.recordDroppedRoutes(rankedRoutes, domain.RouteDropReasonGeneralizedCosmWasmPool)
+
+		if len(rankedRoutes) > 1 {
+			// Compute split route quote
+			topSplitQuote, err := getSplitQuoteInGivenOut(ctx, rankedRoutes, tokenOut, tokenInDenom, newSplitOptions(options))
+
+			// If error occurs in splits, use the single route quote rather than failing.
+			// If the split route quote requires less token in than the single route quote, use the split route quote.
+			isSplitSelected := err == nil && topSplitQuote.AmountIn.Amount.LT(topSingleRouteQuote.AmountIn.Amount)
+			if isSplitSelected {
+				r.logger.Debug("split route selected", zap.Int("route_count", len(topSplitQuote.Route)))
+
+				finalQuote = topSplitQuote
+			}
+
+			if err != nil {
+				explainer.recordSplitQuote(nil, domain.TokenSwapMethodExactOut, false, err)
+			} else {
+				explainer.recordSplitQuote(topSplitQuote.Route, domain.TokenSwapMethodExactOut, isSplitSelected, nil)
+			}
+		}
+	}
+
+	return convertToQuoteExactAmountOut(finalQuote, tokenOut.Denom)
 }
 
 // GetSimpleQuote implements mvc.RouterUsecase.
@@ -261,7 +402,7 @@ func (r *routerUseCaseImpl) GetSimpleQuote(ctx context.Context, tokenIn sdk.Coin
 		return nil, err
 	}
 
-	topQuote, _, err := r.estimateAndRankSingleRouteQuote(ctx, routes, tokenIn, r.logger)
+	topQuote, _, err := r.estimateAndRankSingleRouteQuote(ctx, routes, tokenIn, nil, nil, r.logger)
 	if err != nil {
 		return nil, fmt.Errorf("%s, tokenOutDenom (%s)", err, tokenOutDenom)
 	}
@@ -324,13 +465,13 @@ func filterAndConvertDuplicatePoolIDRankedRoutes(rankedRoutes []RouteWithOutAmou
 
 // rankRoutesByDirectQuote ranks the given candidate routes by estimating direct quotes over each route.
 // Additionally, it fileters out routes with duplicate pool IDs and cuts them for splits
-// based on the value of maxSplitRoutes.
+// based on the value of maxSplitRoutes. The direct quotes and the dropped routes are recorded by the explainer.
 // Returns the top quote as well as the ranked routes in decrease order of amount out.
 // Returns error if:
 // - fails to read taker fees
 // - fails to convert candidate routes to routes
 // - fails to estimate direct quotes
-func (r *routerUseCaseImpl) rankRoutesByDirectQuote(ctx context.Context, candidateRoutes sqsdomain.CandidateRoutes, tokenIn sdk.Coin, tokenOutDenom string, maxSplitRoutes int) (domain.Quote, []route.RouteImpl, error) {
+func (r *routerUseCaseImpl) rankRoutesByDirectQuote(ctx context.Context, candidateRoutes sqsdomain.CandidateRoutes, tokenIn sdk.Coin, tokenOutDenom string, maxSplitRoutes int, explainer *quoteExplainer, gasRanker *gasCostRanker) (domain.Quote, []route.RouteImpl, error) {
 	// Note that retrieving pools and taker fees is done in separate transactions.
 	// This is fine because taker fees don't change often.
 	routes, err := r.poolsUsecase.GetRoutesFromCandidates(candidateRoutes, tokenIn.Denom, tokenOutDenom)
@@ -338,39 +479,39 @@ func (r *routerUseCaseImpl) rankRoutesByDirectQuote(ctx context.Context, candida
 		return nil, nil, err
 	}
 
-	topQuote, routesWithAmtOut, err := r.estimateAndRankSingleRouteQuote(ctx, routes, tokenIn, r.logger)
+	topQuote, routesWithAmtOut, err := r.estimateAndRankSingleRouteQuote(ctx, routes, tokenIn, explainer, gasRanker, r.logger)
 	if err != nil {
 		return nil, nil, fmt.Errorf("%s, tokenOutDenom (%s)", err, tokenOutDenom)
 	}
 
 	// Update ranked routes with filtered ranked routes
 	routes = filterAndConvertDuplicatePoolIDRankedRoutes(routesWithAmtOut)
+	explainer.recordDroppedRoutes(routes, domain.RouteDropReasonDuplicatePool)
 
 	// Cut routes for splits
 	routes = cutRoutesForSplits(maxSplitRoutes, routes)
+	explainer.recordDroppedRoutes(routes, domain.RouteDropReasonMaxSplitRoutes)
 
 	return topQuote, routes, nil
 }
 
 // computeAndRankRoutesByDirectQuote computes candidate routes and ranks them by token out after estimating direct quotes.
-func (r *routerUseCaseImpl) computeAndRankRoutesByDirectQuote(ctx context.Context, tokenIn sdk.Coin, tokenOutDenom string, routingOptions domain.RouterOptions) (domain.Quote, []route.RouteImpl, error) {
+// The candidate route search and the ranking are recorded by the explainer.
+func (r *routerUseCaseImpl) computeAndRankRoutesByDirectQuote(ctx context.Context, tokenIn sdk.Coin, tokenOutDenom string, routingOptions domain.RouterOptions, explainer *quoteExplainer, gasRanker *gasCostRanker) (domain.Quote, []route.RouteImpl, error) {
 	tokenInOrderOfMagnitude := GetPrecomputeOrderOfMagnitude(tokenIn.Amount)
 
-	candidateRouteSearchOptions := domain.CandidateRouteSearchOptions{
-		MaxRoutes:           routingOptions.MaxRoutes,
-		MaxPoolsPerRoute:    routingOptions.MaxPoolsPerRoute,
-		MinPoolLiquidityCap: routingOptions.MinPoolLiquidityCap,
-		DisableCache:        routingOptions.DisableCache,
-		PoolFiltersAnyOf:    routingOptions.CandidateRoutesPoolFiltersAnyOf,
-	}
+	candidateRouteSearchOptions := r.newCandidateRouteSearchOptions(routingOptions)
+	candidateRouteSearchOptions.LiquidityFilteredPoolCb = explainer.liquidityFilteredPoolCb()
 
 	// If top routes are not present in cache, retrieve unranked candidate routes
-	candidateRoutes, err := r.handleCandidateRoutes(ctx, tokenIn, tokenOutDenom, candidateRouteSearchOptions)
+	candidateRoutes, isCandidateRoutesCacheHit, err := r.handleCandidateRoutes(ctx, tokenIn, tokenOutDenom, candidateRouteSearchOptions)
 	if err != nil {
 		r.logger.Error("error handling routes", zap.Error(err))
 		return nil, nil, err
 	}
 
+	explainer.setCandidateRoutesCacheHit(isCandidateRoutesCacheHit)
+
 	// Get request path for metrics
 	requestURLPath, err := domain.GetURLPathFromContext(ctx)
 	if err != nil {
@@ -381,19 +522,19 @@ func (r *routerUseCaseImpl) computeAndRankRoutesByDirectQuote(ctx context.Contex
 		if len(candidateRoutes.Routes) > 0 {
 			domain.SQSRoutesCacheWritesCounter.WithLabelValues(requestURLPath, candidateRouteCacheLabel).Inc()
 
-			r.candidateRouteCache.Set(formatCandidateRouteCacheKey(tokenIn.Denom, tokenOutDenom), candidateRoutes, time.Duration(routingOptions.CandidateRouteCacheExpirySeconds)*time.Second)
+			r.setRouteCache(candidateRouteCacheLabel, formatCandidateRouteCacheKey(tokenIn.Denom, tokenOutDenom), candidateRoutes, time.Duration(routingOptions.CandidateRouteCacheExpirySeconds)*time.Second)
 		} else {
 			// If no candidate routes found, cache them for quarter of the duration
-			r.candidateRouteCache.Set(formatCandidateRouteCacheKey(tokenIn.Denom, tokenOutDenom), candidateRoutes, time.Duration(routingOptions.CandidateRouteCacheExpirySeconds/4)*time.Second)
+			r.setRouteCache(candidateRouteCacheLabel, formatCandidateRouteCacheKey(tokenIn.Denom, tokenOutDenom), candidateRoutes, time.Duration(routingOptions.CandidateRouteCacheExpirySeconds/4)*time.Second)
 
-			r.rankedRouteCache.Set(formatRankedRouteCacheKey(tokenIn.Denom, tokenOutDenom, tokenInOrderOfMagnitude), candidateRoutes, time.Duration(routingOptions.RankedRouteCacheExpirySeconds/4)*time.Second)
+			r.setRouteCache(rankedRouteCacheLabel, formatRankedRouteCacheKey(tokenIn.Denom, tokenOutDenom, tokenInOrderOfMagnitude), candidateRoutes, time.Duration(routingOptions.RankedRouteCacheExpirySeconds/4)*time.Second)
 
 			return nil, nil, fmt.Errorf("no candidate routes found")
 		}
 	}
 
 	// Rank candidate routes by estimating direct quotes
-	topSingleRouteQuote, rankedRoutes, err := r.rankRoutesByDirectQuote(ctx, candidateRoutes, tokenIn, tokenOutDenom, routingOptions.MaxSplitRoutes)
+	topSingleRouteQuote, rankedRoutes, err := r.rankRoutesByDirectQuote(ctx, candidateRoutes, tokenIn, tokenOutDenom, routingOptions.MaxSplitRoutes, explainer, gasRanker)
 	if err != nil {
 		r.logger.Error("error getting ranked routes", zap.Error(err))
 		return nil, nil, err
@@ -419,15 +560,45 @@ func (r *routerUseCaseImpl) computeAndRankRoutesByDirectQuote(ctx context.Contex
 			}
 		}
 
-		if !routingOptions.DisableCache {
+		// The ranking cut by the compute deadline is not cached since it misses the routes that were not quoted.
+		// The gas-aware ranking is not cached since the cached ranked routes are ranked by the amount out alone.
+		if !routingOptions.DisableCache && gasRanker == nil && !isPartialQuote(topSingleRouteQuote) {
 			domain.SQSRoutesCacheWritesCounter.WithLabelValues(requestURLPath, rankedRouteCacheLabel).Inc()
-			r.rankedRouteCache.Set(formatRankedRouteCacheKey(tokenIn.Denom, tokenOutDenom, tokenInOrderOfMagnitude), convertedCandidateRoutes, time.Duration(routingOptions.RankedRouteCacheExpirySeconds)*time.Second)
+			r.setRouteCache(rankedRouteCacheLabel, formatRankedRouteCacheKey(tokenIn.Denom, tokenOutDenom, tokenInOrderOfMagnitude), convertedCandidateRoutes, time.Duration(routingOptions.RankedRouteCacheExpirySeconds)*time.Second)
 		}
 	}
 
 	return topSingleRouteQuote, rankedRoutes, nil
 }
 
+// newCandidateRouteSearchOptions returns the candidate route search options from the router options.
+// If the router options restrict the pool types, the pool type filter is appended to the pool filters.
+func (r *routerUseCaseImpl) newCandidateRouteSearchOptions(routingOptions domain.RouterOptions) domain.CandidateRouteSearchOptions {
+	poolFilters := routingOptions.CandidateRoutesPoolFiltersAnyOf
+	if len(routingOptions.CandidateRoutesPoolTypes) > 0 {
+		poolTypeFilter := domain.CandidateRoutePoolTypeFilterOptionCb{
+			PoolTypesToKeep:     make(map[domain.CandidateRoutePoolType]struct{}, len(routingOptions.CandidateRoutesPoolTypes)),
+			CosmWasmPoolsConfig: r.cosmWasmPoolsConfig,
+		}
+		for _, poolType := range routingOptions.CandidateRoutesPoolTypes {
+			poolTypeFilter.PoolTypesToKeep[poolType] = struct{}{}
+		}
+
+		// Copy to avoid mutating the filters of the caller.
+		poolFilters = append(append(make([]domain.CandidateRoutePoolFiltrerCb, 0, len(poolFilters)+1), poolFilters...), poolTypeFilter.ShouldSkipPool)
+	}
+
+	return domain.CandidateRouteSearchOptions{
+		MaxRoutes:                routingOptions.MaxRoutes,
+		MaxPoolsPerRoute:         routingOptions.MaxPoolsPerRoute,
+		MinPoolLiquidityCap:      routingOptions.MinPoolLiquidityCap,
+		DisableCache:             routingOptions.DisableCache,
+		PoolFiltersAnyOf:         poolFilters,
+		IntermediaryDenomsToSkip: routingOptions.CandidateRoutesIntermediaryDenomsToSkip,
+		Algorithm:                routingOptions.CandidateRouteAlgorithm,
+	}
+}
+
 var (
 	ErrTokenInDenomPoolNotFound  = fmt.Errorf("token in denom not found in pool")
 	ErrTokenOutDenomPoolNotFound = fmt.Errorf("token out denom not found in pool")
@@ -435,6 +606,27 @@ var (
 
 // GetCustomDirectQuote implements mvc.RouterUsecase.
 func (r *routerUseCaseImpl) GetCustomDirectQuote(ctx context.Context, tokenIn sdk.Coin, tokenOutDenom string, poolID uint64) (domain.Quote, error) {
+	routes, err := r.getCustomDirectRoutes(tokenIn.Denom, tokenOutDenom, poolID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Compute direct quote
+	bestSingleRouteQuote, _, err := r.estimateAndRankSingleRouteQuote(ctx, routes, tokenIn, nil, nil, r.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return bestSingleRouteQuote, nil
+}
+
+// getCustomDirectRoutes validates that the pool with the given ID contains the token in and token out denoms
+// and converts it into the route from token in to token out with all the pool data.
+// Returns error if:
+// - the pool is not found
+// - the token in or token out denom is not in the pool
+// - the taker fee is not found for the denom pair
+func (r *routerUseCaseImpl) getCustomDirectRoutes(tokenInDenom string, tokenOutDenom string, poolID uint64) ([]route.RouteImpl, error) {
 	pool, err := r.poolsUsecase.GetPool(poolID)
 	if err != nil {
 		return nil, err
@@ -442,39 +634,28 @@ func (r *routerUseCaseImpl) GetCustomDirectQuote(ctx context.Context, tokenIn sd
 
 	poolDenoms := pool.GetPoolDenoms()
 
-	if !osmoutils.Contains(poolDenoms, tokenIn.Denom) {
-		return nil, fmt.Errorf("denom %s in pool %d: %w", tokenIn.Denom, poolID, ErrTokenInDenomPoolNotFound)
+	if !osmoutils.Contains(poolDenoms, tokenInDenom) {
+		return nil, fmt.Errorf("denom %s in pool %d: %w", tokenInDenom, poolID, ErrTokenInDenomPoolNotFound)
 	}
 	if !osmoutils.Contains(poolDenoms, tokenOutDenom) {
 		return nil, fmt.Errorf("denom %s in pool %d: %w", tokenOutDenom, poolID, ErrTokenOutDenomPoolNotFound)
 	}
 
 	// Retrieve taker fee for the pool
-	takerFee, ok := r.routerRepository.GetTakerFee(tokenIn.Denom, tokenOutDenom)
+	takerFee, ok := r.routerRepository.GetTakerFee(tokenInDenom, tokenOutDenom)
 	if !ok {
-		return nil, fmt.Errorf("taker fee not found for pool %d, denom in (%s), denom out (%s)", poolID, tokenIn.Denom, tokenOutDenom)
+		return nil, fmt.Errorf("taker fee not found for pool %d, denom in (%s), denom out (%s)", poolID, tokenInDenom, tokenOutDenom)
 	}
 
 	// Create a taker fee map with the taker fee for the pool
 	takerFeeMap := sqsdomain.TakerFeeMap{}
-	takerFeeMap.SetTakerFee(tokenIn.Denom, tokenOutDenom, takerFee)
+	takerFeeMap.SetTakerFee(tokenInDenom, tokenOutDenom, takerFee)
 
 	// create candidate routes with given token out denom and pool ID.
 	candidateRoutes := r.createCandidateRouteByPoolID(tokenOutDenom, poolID)
 
 	// Convert candidate route into a route with all the pool data
-	routes, err := r.poolsUsecase.GetRoutesFromCandidates(candidateRoutes, tokenIn.Denom, tokenOutDenom)
-	if err != nil {
-		return nil, err
-	}
-
-	// Compute direct quote
-	bestSingleRouteQuote, _, err := r.estimateAndRankSingleRouteQuote(ctx, routes, tokenIn, r.logger)
-	if err != nil {
-		return nil, err
-	}
-
-	return bestSingleRouteQuote, nil
+	return r.poolsUsecase.GetRoutesFromCandidates(candidateRoutes, tokenInDenom, tokenOutDenom)
 }
 
 // GetCustomDirectQuoteMultiPool implements mvc.RouterUsecase.
@@ -538,21 +719,113 @@ func (r *routerUseCaseImpl) GetCustomDirectQuoteMultiPool(ctx context.Context, t
 	return &result, nil
 }
 
-// GetCustomDirectQuoteMultiPool implements mvc.RouterUsecase.
+// GetCustomDirectQuoteMultiPoolInGivenOut implements mvc.RouterUsecase.
+// The pools are given in order from the token out side. That is, the first pool swaps
+// from tokenInDenom[0] into the token out, the second from tokenInDenom[1] into tokenInDenom[0] and so on.
+// The token in amount required to receive the token out is computed by traversing the pools in that order.
 func (r *routerUseCaseImpl) GetCustomDirectQuoteMultiPoolInGivenOut(ctx context.Context, tokenOut sdk.Coin, tokenInDenom []string, poolIDs []uint64) (domain.Quote, error) {
-	quote, err := r.GetCustomDirectQuoteMultiPool(ctx, tokenOut, tokenInDenom, poolIDs)
+	if len(poolIDs) == 0 {
+		return nil, fmt.Errorf("%w: at least one pool ID should be specified", types.ErrValidationFailed)
+	}
+
+	if len(tokenInDenom) == 0 {
+		return nil, fmt.Errorf("%w: at least one token in denom should be specified", types.ErrValidationFailed)
+	}
+
+	// for each given pool we expect to have provided token in denom
+	if len(poolIDs) != len(tokenInDenom) {
+		return nil, fmt.Errorf("%w: number of pool ID should match number of in denom", types.ErrValidationFailed)
+	}
+
+	// The pools are collected in order from token in to token out.
+	pools := make([]domain.RoutablePool, len(poolIDs))
+
+	currentTokenOutDenom := tokenOut.Denom
+	for i, poolID := range poolIDs {
+		// Note that the route is retrieved in the direction from the token out side.
+		// It is reversed below.
+		routes, err := r.getCustomDirectRoutes(currentTokenOutDenom, tokenInDenom[i], poolID)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(routes) != 1 {
+			return nil, fmt.Errorf("custom direct quote must have 1 route, had: %d", len(routes))
+		}
+
+		poolsInRoute := reverseRoute(routes[0]).GetPools()
+		if len(poolsInRoute) != 1 {
+			return nil, fmt.Errorf("custom direct quote route must have 1 pool, had: %d", len(poolsInRoute))
+		}
+
+		pools[len(poolIDs)-1-i] = poolsInRoute[0]
+
+		currentTokenOutDenom = tokenInDenom[i]
+	}
+
+	customRoute := route.RouteImpl{
+		Pools: pools,
+	}
+
+	tokenIn, err := customRoute.CalculateTokenInByTokenOut(ctx, tokenOut)
 	if err != nil {
 		return nil, err
 	}
 
-	q, ok := quote.(*quoteExactAmountIn)
-	if !ok {
-		return nil, errors.New("quote is not a quoteExactAmountIn")
+	// Construct the final multi-hop custom direct quote route.
+	result := &quoteExactAmountIn{
+		AmountIn:  sdk.NewCoin(currentTokenOutDenom, tokenIn.Amount),
+		AmountOut: tokenOut.Amount,
+		Route: []domain.SplitRoute{
+			&RouteWithOutAmount{
+				RouteImpl: customRoute,
+				OutAmount: tokenOut.Amount,
+				InAmount:  tokenIn.Amount,
+			},
+		},
 	}
 
-	return &quoteExactAmountOut{
-		quoteExactAmountIn: q,
-	}, nil
+	return convertToQuoteExactAmountOut(result, tokenOut.Denom)
+}
+
+// GetQuoteFromSpec implements mvc.RouterUsecase.
+// The routes are computed with GetCustomDirectQuoteMultiPool or GetCustomDirectQuoteMultiPoolInGivenOut
+// depending on the swap method and are combined into a single quote with the layout expected by PrepareResult.
+func (r *routerUseCaseImpl) GetQuoteFromSpec(ctx context.Context, spec domain.QuoteSpec) (domain.Quote, error) {
+	// For the exact amount out swap method, this is the exact out layout.
+	// That is, the amount in is the token out and the amount out is the token in amount.
+	result := &quoteExactAmountIn{
+		AmountIn:  sdk.NewCoin(spec.Token.Denom, osmomath.ZeroInt()),
+		AmountOut: osmomath.ZeroInt(),
+		Route:     make([]domain.SplitRoute, 0, len(spec.Routes)),
+	}
+
+	for _, specRoute := range spec.Routes {
+		routeToken := sdk.NewCoin(spec.Token.Denom, specRoute.Amount)
+
+		var (
+			routeQuote domain.Quote
+			err        error
+		)
+		if spec.SwapMethod == domain.TokenSwapMethodExactIn {
+			routeQuote, err = r.GetCustomDirectQuoteMultiPool(ctx, routeToken, specRoute.Denoms, specRoute.PoolIDs)
+		} else {
+			routeQuote, err = r.GetCustomDirectQuoteMultiPoolInGivenOut(ctx, routeToken, specRoute.Denoms, specRoute.PoolIDs)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		result.AmountIn.Amount = result.AmountIn.Amount.Add(routeQuote.GetAmountIn().Amount)
+		result.AmountOut = result.AmountOut.Add(routeQuote.GetAmountOut())
+		result.Route = append(result.Route, routeQuote.GetRoute()...)
+	}
+
+	if spec.SwapMethod == domain.TokenSwapMethodExactOut {
+		return &quoteExactAmountOut{quoteExactAmountIn: result}, nil
+	}
+
+	return result, nil
 }
 
 // GetCandidateRoutes implements domain.RouterUsecase.
@@ -571,7 +844,7 @@ func (r *routerUseCaseImpl) GetCandidateRoutes(ctx context.Context, tokenIn sdk.
 		candidateRouteSearchOptions.MinPoolLiquidityCap = r.ConvertMinTokensPoolLiquidityCapToFilter(dynamicMinPoolLiquidityCap)
 	}
 
-	candidateRoutes, err := r.handleCandidateRoutes(ctx, tokenIn, tokenOutDenom, candidateRouteSearchOptions)
+	candidateRoutes, _, err := r.handleCandidateRoutes(ctx, tokenIn, tokenOutDenom, candidateRouteSearchOptions)
 	if err != nil {
 		return sqsdomain.CandidateRoutes{}, err
 	}
@@ -676,20 +949,19 @@ func (r *routerUseCaseImpl) GetCachedRankedRoutes(ctx context.Context, tokenInDe
 
 // handleCandidateRoutes attempts to retrieve candidate routes from the cache. If no routes are cached, it will
 // compute, persist in cache and return them.
-// Returns routes on success
+// Returns routes on success as well as whether they were read from cache.
 // Errors if:
 // - there is an error retrieving routes from cache
 // - there are no routes cached and there is an error computing them
 // - fails to persist the computed routes in cache
-func (r *routerUseCaseImpl) handleCandidateRoutes(ctx context.Context, tokenIn sdk.Coin, tokenOutDenom string, candidateRouteSearchOptions domain.CandidateRouteSearchOptions) (candidateRoutes sqsdomain.CandidateRoutes, err error) {
+func (r *routerUseCaseImpl) handleCandidateRoutes(ctx context.Context, tokenIn sdk.Coin, tokenOutDenom string, candidateRouteSearchOptions domain.CandidateRouteSearchOptions) (candidateRoutes sqsdomain.CandidateRoutes, isFoundCached bool, err error) {
 	r.logger.Debug("getting routes")
 
 	// Check cache for routes if enabled
-	var isFoundCached bool
 	if !candidateRouteSearchOptions.DisableCache {
 		candidateRoutes, isFoundCached, err = r.GetCachedCandidateRoutes(ctx, tokenIn.Denom, tokenOutDenom)
 		if err != nil {
-			return sqsdomain.CandidateRoutes{}, err
+			return sqsdomain.CandidateRoutes{}, false, err
 		}
 	}
 
@@ -702,7 +974,7 @@ func (r *routerUseCaseImpl) handleCandidateRoutes(ctx context.Context, tokenIn s
 		candidateRoutes, err = r.candidateRouteSearcher.FindCandidateRoutes(tokenIn, tokenOutDenom, candidateRouteSearchOptions)
 		if err != nil {
 			r.logger.Error("error getting candidate routes for pricing", zap.Error(err))
-			return sqsdomain.CandidateRoutes{}, err
+			return sqsdomain.CandidateRoutes{}, false, err
 		}
 
 		r.logger.Info("calculated routes", zap.Int("num_routes", len(candidateRoutes.Routes)))
@@ -717,11 +989,11 @@ func (r *routerUseCaseImpl) handleCandidateRoutes(ctx context.Context, tokenIn s
 			}
 
 			r.logger.Debug("persisting routes", zap.Int("num_routes", len(candidateRoutes.Routes)))
-			r.candidateRouteCache.Set(formatCandidateRouteCacheKey(tokenIn.Denom, tokenOutDenom), candidateRoutes, time.Duration(cacheDurationSeconds)*time.Second)
+			r.setRouteCache(candidateRouteCacheLabel, formatCandidateRouteCacheKey(tokenIn.Denom, tokenOutDenom), candidateRoutes, time.Duration(cacheDurationSeconds)*time.Second)
 		}
 	}
 
-	return candidateRoutes, nil
+	return candidateRoutes, isFoundCached, nil
 }
 
 // StoreRouterStateFiles implements domain.RouterUsecase.
@@ -918,6 +1190,17 @@ func (r *routerUseCaseImpl) GetConfig() domain.RouterConfig {
 	return r.defaultConfig
 }
 
+// filterGeneralizedCosmWasmPoolRoutesForSplits filters out the routes that contain generalized cosm wasm pools
+// before computing the split quote unless the calc queries of such pools are memoised.
+// With the memo, the repeated quotes of the split computation do not repeat the network requests to chain.
+func (r *routerUseCaseImpl) filterGeneralizedCosmWasmPoolRoutesForSplits(rankedRoutes []route.RouteImpl) []route.RouteImpl {
+	if r.cosmWasmPoolsConfig.GeneralCosmWasmCalcQueryBudget > 0 {
+		return rankedRoutes
+	}
+
+	return filterOutGeneralizedCosmWasmPoolRoutes(rankedRoutes)
+}
+
 // filterOutGeneralizedCosmWasmPoolRoutes filters out routes that contain generalized cosm wasm pool.
 // The reason for this is that making network requests to chain is expensive. Generalized cosmwasm pools
 // make such network requests.
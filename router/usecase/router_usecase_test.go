@@ -2,7 +2,9 @@ package usecase_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"os"
 	"testing"
 	"time"
@@ -834,6 +836,199 @@ func (s *RouterTestSuite) TestGetOptimalQuote_Cache_Overwrites() {
 	}
 }
 
+// This test validates that the explain describes the candidate routes, the direct quotes
+// and the cache usage of the quote computation.
+func (s *RouterTestSuite) TestGetOptimalQuote_Explain() {
+	var (
+		defaultTokenInDenom  = UOSMO
+		defaultTokenOutDenom = ATOM
+	)
+
+	tests := map[string]struct {
+		preCachedCandidateRoutes sqsdomain.CandidateRoutes
+		preCachedRankedRoutes    sqsdomain.CandidateRoutes
+
+		expectedCandidateRoutesCacheHit bool
+		expectedRankedRoutesCacheHit    bool
+		// Nil if the routes are computed.
+		expectedRoutes []domain.ExplainRoute
+	}{
+		"cache is not set, computes routes": {},
+		"candidate route cache is set": {
+			preCachedCandidateRoutes: poolIDOneRoute,
+
+			expectedCandidateRoutesCacheHit: true,
+			expectedRoutes:                  []domain.ExplainRoute{{PoolIDs: []uint64{poolIDOneBalancer}}},
+		},
+		"ranked route cache is set": {
+			preCachedRankedRoutes: poolID1135Route,
+
+			expectedRankedRoutesCacheHit: true,
+			expectedRoutes:               []domain.ExplainRoute{{PoolIDs: []uint64{poolID1135Concentrated}}},
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		s.Run(name, func() {
+			// Setup mainnet router
+			mainnetState := s.SetupMainnetState()
+
+			rankedRouteCache := cache.New()
+			candidateRouteCache := cache.New()
+
+			if len(tc.preCachedCandidateRoutes.Routes) > 0 {
+				candidateRouteCache.Set(usecase.FormatCandidateRouteCacheKey(defaultTokenInDenom, defaultTokenOutDenom), tc.preCachedCandidateRoutes, time.Hour)
+			}
+
+			if len(tc.preCachedRankedRoutes.Routes) > 0 {
+				tokeInOrderOfMagnitude := usecase.GetPrecomputeOrderOfMagnitude(defaultAmountInCache)
+				rankedRouteCache.Set(usecase.FormatRankedRouteCacheKey(defaultTokenInDenom, defaultTokenOutDenom, tokeInOrderOfMagnitude), tc.preCachedRankedRoutes, time.Hour)
+			}
+
+			mainnetUseCase := s.SetupRouterAndPoolsUsecase(mainnetState, routertesting.WithRankedRoutesCache(rankedRouteCache), routertesting.WithCandidateRoutesCache(candidateRouteCache))
+
+			explain := &domain.QuoteExplain{}
+
+			// System under test
+			quote, err := mainnetUseCase.Router.GetOptimalQuote(context.Background(), sdk.NewCoin(defaultTokenInDenom, defaultAmountInCache), defaultTokenOutDenom, domain.WithQuoteExplain(explain))
+			s.Require().NoError(err)
+
+			s.Require().Equal(tc.expectedCandidateRoutesCacheHit, explain.CandidateRoutesCacheHit)
+			s.Require().Equal(tc.expectedRankedRoutesCacheHit, explain.RankedRoutesCacheHit)
+			s.Require().NotEmpty(explain.Routes)
+
+			if tc.expectedRoutes != nil {
+				s.Require().Len(explain.Routes, len(tc.expectedRoutes))
+				for i, expectedRoute := range tc.expectedRoutes {
+					s.Require().Equal(expectedRoute.PoolIDs, explain.Routes[i].PoolIDs)
+					s.Require().Equal(expectedRoute.DropReason, explain.Routes[i].DropReason)
+				}
+			}
+
+			keptRoutes := 0
+			for _, explainRoute := range explain.Routes {
+				s.Require().Equal(defaultAmountInCache, explainRoute.AmountIn)
+
+				if explainRoute.DropReason == domain.RouteDropReasonCalculationError {
+					s.Require().NotEmpty(explainRoute.Error)
+				}
+
+				if explainRoute.DropReason == "" {
+					keptRoutes++
+				}
+			}
+
+			// At least the route of the best single route quote is kept.
+			s.Require().Positive(keptRoutes)
+
+			// The proportions of the split routes sum up to one.
+			if explain.Split != nil && explain.Split.Error == "" {
+				totalProportion := osmomath.ZeroDec()
+				for _, splitRoute := range explain.Split.Routes {
+					totalProportion.AddMut(splitRoute.Proportion)
+				}
+				s.Require().True(totalProportion.Sub(osmomath.OneDec()).Abs().LTE(osmomath.NewDecWithPrec(1, 12)))
+
+				if explain.Split.Selected {
+					s.Require().Len(quote.GetRoute(), len(explain.Split.Routes))
+				}
+			}
+		})
+	}
+}
+
+// This test validates that the cached ranked routes are evicted when any of their pools is updated and kept otherwise.
+// The cached candidate routes are only evicted if their invalidation is enabled.
+// If the route cache invalidation is disabled, nothing is evicted.
+func (s *RouterTestSuite) TestInvalidateRouteCaches() {
+	var (
+		tokenIn       = sdk.NewCoin(UOSMO, defaultAmountInCache)
+		tokenOutDenom = ATOM
+
+		candidateRouteCacheKey = usecase.FormatCandidateRouteCacheKey(tokenIn.Denom, tokenOutDenom)
+		rankedRouteCacheKey    = usecase.FormatRankedRouteCacheKey(tokenIn.Denom, tokenOutDenom, usecase.GetPrecomputeOrderOfMagnitude(tokenIn.Amount))
+
+		unrelatedPoolIDs = map[uint64]struct{}{math.MaxUint64: {}}
+	)
+
+	tests := map[string]struct {
+		isInvalidationDisabled              bool
+		isCandidateRouteInvalidationEnabled bool
+
+		expectedRankedEvicted    bool
+		expectedCandidateEvicted bool
+	}{
+		"evicts the ranked routes of the updated pool": {
+			expectedRankedEvicted: true,
+		},
+		"evicts the ranked and candidate routes of the updated pool": {
+			isCandidateRouteInvalidationEnabled: true,
+
+			expectedRankedEvicted:    true,
+			expectedCandidateEvicted: true,
+		},
+		"invalidation disabled": {
+			isInvalidationDisabled:              true,
+			isCandidateRouteInvalidationEnabled: true,
+		},
+	}
+
+	for name, tc := range tests {
+		tc := tc
+		s.Run(name, func() {
+			mainnetState := s.SetupMainnetState()
+
+			rankedRouteCache := cache.New()
+			candidateRouteCache := cache.New()
+
+			routerConfig := routertesting.DefaultRouterConfig
+			routerConfig.RouteCacheInvalidationEnabled = !tc.isInvalidationDisabled
+			routerConfig.CandidateRouteCacheInvalidationEnabled = tc.isCandidateRouteInvalidationEnabled
+
+			mainnetUseCase := s.SetupRouterAndPoolsUsecase(mainnetState, routertesting.WithRouterConfig(routerConfig), routertesting.WithRankedRoutesCache(rankedRouteCache), routertesting.WithCandidateRoutesCache(candidateRouteCache), routertesting.WithLoggerDisabled())
+
+			_, err := mainnetUseCase.Router.GetOptimalQuote(context.Background(), tokenIn, tokenOutDenom)
+			s.Require().NoError(err)
+
+			cachedRankedRoutes, found := rankedRouteCache.Get(rankedRouteCacheKey)
+			s.Require().True(found)
+			_, found = candidateRouteCache.Get(candidateRouteCacheKey)
+			s.Require().True(found)
+
+			// The pools of the ranked routes are a subset of the pools of the candidate routes.
+			rankedRoutes, ok := cachedRankedRoutes.(sqsdomain.CandidateRoutes)
+			s.Require().True(ok)
+			s.Require().NotEmpty(rankedRoutes.Routes)
+			updatedPoolIDs := map[uint64]struct{}{rankedRoutes.Routes[0].Pools[0].ID: {}}
+
+			// The pools outside of the cached routes do not evict anything.
+			s.Require().Zero(mainnetUseCase.Router.InvalidateRouteCaches(unrelatedPoolIDs))
+			s.Require().Equal(1, rankedRouteCache.Len())
+			s.Require().Equal(1, candidateRouteCache.Len())
+
+			// System under test
+			evictedCount := mainnetUseCase.Router.InvalidateRouteCaches(updatedPoolIDs)
+
+			expectedEvictedCount, expectedRankedCacheLen, expectedCandidateCacheLen := 0, 1, 1
+			if tc.expectedRankedEvicted {
+				expectedEvictedCount++
+				expectedRankedCacheLen = 0
+			}
+			if tc.expectedCandidateEvicted {
+				expectedEvictedCount++
+				expectedCandidateCacheLen = 0
+			}
+			s.Require().Equal(expectedEvictedCount, evictedCount)
+			s.Require().Equal(expectedRankedCacheLen, rankedRouteCache.Len())
+			s.Require().Equal(expectedCandidateCacheLen, candidateRouteCache.Len())
+
+			// The evicted entries are no longer indexed.
+			s.Require().Zero(mainnetUseCase.Router.InvalidateRouteCaches(updatedPoolIDs))
+		})
+	}
+}
+
 // This test validates that routes can be found for all supported tokens.
 // Fails if not.
 // We use this test in CI for detecting tokens with unsupported pricing.
@@ -1718,3 +1913,289 @@ func (s *RouterTestSuite) validatePoolIDInRoute(routerUseCase mvc.RouterUsecase,
 	// Validate that the pool ID is the expected one
 	s.Require().Equal(expectedPoolID, routePools[0].GetId())
 }
+
+// Validates that the cyclic arbitrage finder returns only the profitable cycles starting and ending
+// in the given denom, sorted by profit, and that the reported amounts match the custom direct quote
+// over the same pools.
+func (s *RouterTestSuite) TestFindCyclicArbs_Mainnet() {
+	mainnetState := s.SetupMainnetState()
+
+	mainnetUsecase := s.SetupRouterAndPoolsUsecase(mainnetState, routertesting.WithLoggerDisabled())
+
+	ctx := context.Background()
+
+	// System under test
+	arbs, err := mainnetUsecase.Router.FindCyclicArbs(ctx, UOSMO, domain.CyclicArbOptions{})
+	s.Require().NoError(err)
+
+	for i, arb := range arbs {
+		s.Require().Equal(UOSMO, arb.Denom)
+		s.Require().Equal(UOSMO, arb.Denoms[len(arb.Denoms)-1])
+		s.Require().Len(arb.PoolIDs, len(arb.Denoms))
+		s.Require().LessOrEqual(len(arb.PoolIDs), domain.DefaultCyclicArbMaxPoolsPerCycle)
+
+		s.Require().True(arb.Profit.IsPositive())
+		s.Require().Equal(arb.AmountOut.Sub(arb.AmountIn).String(), arb.Profit.String())
+
+		if i > 0 {
+			s.Require().True(arbs[i-1].Profit.GTE(arb.Profit))
+		}
+
+		quote, err := mainnetUsecase.Router.GetCustomDirectQuoteMultiPool(ctx, sdk.NewCoin(UOSMO, arb.AmountIn), arb.Denoms, arb.PoolIDs)
+		s.Require().NoError(err)
+		s.Require().Equal(arb.AmountOut.String(), quote.GetAmountOut().String())
+	}
+
+	// The amount in never exceeds the given bound.
+	maxAmountIn := osmomath.NewInt(1_000)
+	arbs, err = mainnetUsecase.Router.FindCyclicArbs(ctx, UOSMO, domain.CyclicArbOptions{
+		MaxAmountIn: maxAmountIn,
+	})
+	s.Require().NoError(err)
+
+	for _, arb := range arbs {
+		s.Require().True(arb.AmountIn.LTE(maxAmountIn))
+	}
+
+	// A cycle requires at least two pools.
+	_, err = mainnetUsecase.Router.FindCyclicArbs(ctx, UOSMO, domain.CyclicArbOptions{
+		MaxPoolsPerCycle: 1,
+	})
+	s.Require().ErrorIs(err, domain.ErrCyclicArbMaxPoolsPerCycleNotValid)
+}
+
+// Validates that the cyclic arbitrage amount in search finds the most profitable amount when the profit
+// has a less profitable local maximum at a larger order of magnitude that the ternary search over
+// the whole range would converge to. The profit of the mocked cycle is:
+// - min(amountIn - 1000, 100000 - amountIn) within [1000, 100000], peaking at 49500 for 50500.
+// - 1 within [1e9, 2e9].
+// - -1 otherwise.
+func (s *RouterTestSuite) TestFindCyclicArbAmountIn_PreScan() {
+	var (
+		peakAmountIn = osmomath.NewInt(50_500)
+		peakProfit   = osmomath.NewInt(49_500)
+
+		localMaxLo = osmomath.NewInt(1_000_000_000)
+		localMaxHi = osmomath.NewInt(2_000_000_000)
+	)
+
+	profitAt := func(amountIn osmomath.Int) osmomath.Int {
+		if amountIn.GTE(osmomath.NewInt(1_000)) && amountIn.LTE(osmomath.NewInt(100_000)) {
+			return osmomath.MinInt(amountIn.SubRaw(1_000), osmomath.NewInt(100_000).Sub(amountIn))
+		}
+		if amountIn.GTE(localMaxLo) && amountIn.LTE(localMaxHi) {
+			return osmomath.OneInt()
+		}
+		return osmomath.OneInt().Neg()
+	}
+
+	cycle := route.RouteImpl{
+		Pools: []domain.RoutablePool{
+			&mocks.MockRoutablePool{
+				ID:            defaultPoolID,
+				TokenOutDenom: UOSMO,
+				TakerFee:      osmomath.ZeroDec(),
+				CalculateTokenOutByTokenInFunc: func(ctx context.Context, tokenIn sdk.Coin) (sdk.Coin, error) {
+					return sdk.NewCoin(UOSMO, tokenIn.Amount.Add(profitAt(tokenIn.Amount))), nil
+				},
+			},
+		},
+	}
+
+	// System under test
+	arb, ok := usecase.FindCyclicArbAmountIn(context.Background(), cycle, UOSMO, osmomath.NewInt(1_000_000_000_000_000))
+	s.Require().True(ok)
+
+	s.Require().Equal(peakAmountIn.String(), arb.AmountIn.String())
+	s.Require().Equal(peakProfit.String(), arb.Profit.String())
+	s.Require().Equal(peakAmountIn.Add(peakProfit).String(), arb.AmountOut.String())
+	s.Require().Equal([]uint64{defaultPoolID}, arb.PoolIDs)
+}
+
+// Validates that the max amount for price impact search returns a quote within the max price impact
+// for both swap methods and that twice the amount exceeds it.
+func (s *RouterTestSuite) TestGetMaxAmountForPriceImpact_Mainnet() {
+	maxPriceImpact := osmomath.MustNewDecFromStr("0.01")
+
+	tests := []struct {
+		name       string
+		swapMethod domain.TokenSwapMethod
+	}{
+		{
+			name:       "exact amount in",
+			swapMethod: domain.TokenSwapMethodExactIn,
+		},
+		{
+			name:       "exact amount out",
+			swapMethod: domain.TokenSwapMethodExactOut,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+
+		s.Run(tc.name, func() {
+			mainnetState := s.SetupMainnetState()
+
+			mainnetUsecase := s.SetupRouterAndPoolsUsecase(mainnetState, routertesting.WithLoggerDisabled())
+
+			ctx := context.Background()
+
+			// System under test
+			quote, err := mainnetUsecase.Router.GetMaxAmountForPriceImpact(ctx, UOSMO, USDC, tc.swapMethod, maxPriceImpact)
+			s.Require().NoError(err)
+
+			s.Require().True(quote.GetPriceImpact().Abs().LTE(maxPriceImpact))
+
+			// The prepared quote returns the given token from GetAmountIn for both swap methods.
+			givenToken := quote.GetAmountIn()
+			givenDenom, otherDenom := UOSMO, USDC
+			if tc.swapMethod == domain.TokenSwapMethodExactOut {
+				givenDenom, otherDenom = USDC, UOSMO
+			}
+			s.Require().Equal(givenDenom, givenToken.Denom)
+
+			// Twice the amount exceeds the max price impact.
+			twiceGivenToken := sdk.NewCoin(givenDenom, givenToken.Amount.MulRaw(2))
+			var twiceQuote domain.Quote
+			if tc.swapMethod == domain.TokenSwapMethodExactIn {
+				twiceQuote, err = mainnetUsecase.Router.GetOptimalQuote(ctx, twiceGivenToken, otherDenom)
+			} else {
+				twiceQuote, err = mainnetUsecase.Router.GetOptimalQuoteInGivenOut(ctx, twiceGivenToken, otherDenom)
+			}
+			if err == nil {
+				_, _, err = twiceQuote.PrepareResult(ctx, osmomath.OneDec(), &log.NoOpLogger{})
+				s.Require().NoError(err)
+				s.Require().True(twiceQuote.GetPriceImpact().Abs().GT(maxPriceImpact))
+			}
+		})
+	}
+}
+
+// Validates that the default router options shared by the quote and the liquidity depth computations
+// are set from the router config, including the ranked route cache expiry and the compute deadline.
+func (s *RouterTestSuite) TestDefaultRouterOptions() {
+	config := defaultRouterConfig
+	config.RankedRouteCacheExpirySeconds = 7
+	config.QuoteComputeDeadlineMs = 250
+	config.RouteCacheEnabled = false
+
+	routerUsecase := usecase.NewRouterUsecase(nil, nil, nil, nil, config, emptyCosmWasmPoolsRouterConfig, &log.NoOpLogger{}, cache.New(), cache.New())
+
+	routerUsecaseImpl, ok := routerUsecase.(*usecase.RouterUseCaseImpl)
+	s.Require().True(ok)
+
+	// System under test
+	options := routerUsecaseImpl.DefaultRouterOptions()
+
+	s.Require().Equal(config.MaxPoolsPerRoute, options.MaxPoolsPerRoute)
+	s.Require().Equal(config.MaxRoutes, options.MaxRoutes)
+	s.Require().Equal(config.MaxSplitRoutes, options.MaxSplitRoutes)
+	s.Require().Equal(config.CandidateRouteCacheExpirySeconds, options.CandidateRouteCacheExpirySeconds)
+	s.Require().Equal(7, options.RankedRouteCacheExpirySeconds)
+	s.Require().Equal(250*time.Millisecond, options.ComputeDeadline)
+	s.Require().True(options.DisableCache)
+}
+
+// Validates that the max amount for price impact search stops with the cause of the cancelled context
+// instead of treating the failed quotes as exceeding the max price impact.
+func (s *RouterTestSuite) TestGetMaxAmountForPriceImpact_ContextCancelled() {
+	mainnetState := s.SetupMainnetState()
+
+	mainnetUsecase := s.SetupRouterAndPoolsUsecase(mainnetState, routertesting.WithLoggerDisabled())
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cancel(domain.ErrComputeDeadlineExceeded)
+
+	// System under test
+	_, err := mainnetUsecase.Router.GetMaxAmountForPriceImpact(ctx, UOSMO, USDC, domain.TokenSwapMethodExactIn, osmomath.MustNewDecFromStr("0.01"))
+	s.Require().ErrorIs(err, domain.ErrComputeDeadlineExceeded)
+}
+
+// Validates that only the quote errors that do not depend on the amount stop the max amount for price impact search.
+func (s *RouterTestSuite) TestIsAmountIndependentQuoteError() {
+	tests := map[string]struct {
+		err error
+
+		expected bool
+	}{
+		"context cancelled": {
+			err:      context.Canceled,
+			expected: true,
+		},
+		"compute deadline exceeded": {
+			err:      domain.ErrComputeDeadlineExceeded,
+			expected: true,
+		},
+		"taker fee not found": {
+			err:      fmt.Errorf("failed to quote: %w", domain.TakerFeeNotFoundForDenomPairError{Denom0: UOSMO, Denom1: USDC}),
+			expected: true,
+		},
+		"pool data missing": {
+			err:      domain.CosmWasmPoolDataMissingError{CosmWasmPoolType: domain.CosmWasmPoolTransmuter, PoolId: 1},
+			expected: true,
+		},
+		"gas estimate not found": {
+			err:      types.ErrGasEstimateNotFound,
+			expected: true,
+		},
+		"not enough concentrated liquidity": {
+			err:      domain.ConcentratedNotEnoughLiquidityToCompleteSwapError{PoolId: 1, AmountIn: "1000"},
+			expected: false,
+		},
+		"insufficient transmuter balance": {
+			err:      domain.TransmuterInsufficientBalanceError{Denom: USDC, BalanceAmount: "1", Amount: "1000"},
+			expected: false,
+		},
+		"no tokens out": {
+			err:      errors.New("best we can do is no tokens out"),
+			expected: false,
+		},
+	}
+
+	for name, tc := range tests {
+		s.Run(name, func() {
+			s.Require().Equal(tc.expected, usecase.IsAmountIndependentQuoteError(tc.err))
+		})
+	}
+}
+
+// Validates that the liquidity depth returns the levels of both sides with the execution prices
+// getting worse as the amounts grow and the bids below the asks.
+func (s *RouterTestSuite) TestGetLiquidityDepth_Mainnet() {
+	mainnetState := s.SetupMainnetState()
+
+	mainnetUsecase := s.SetupRouterAndPoolsUsecase(mainnetState, routertesting.WithLoggerDisabled())
+
+	baseAmounts, err := domain.NewLogSpacedAmounts(osmomath.NewInt(1_000_000), osmomath.NewInt(1_000_000_000_000), 7)
+	s.Require().NoError(err)
+
+	// System under test
+	depth, err := mainnetUsecase.Router.GetLiquidityDepth(context.Background(), UOSMO, USDC, baseAmounts)
+	s.Require().NoError(err)
+
+	s.Require().Equal(UOSMO, depth.BaseDenom)
+	s.Require().Equal(USDC, depth.QuoteDenom)
+	s.Require().NotEmpty(depth.Bids)
+	s.Require().NotEmpty(depth.Asks)
+	s.Require().LessOrEqual(len(depth.Bids), len(baseAmounts))
+	s.Require().LessOrEqual(len(depth.Asks), len(baseAmounts))
+
+	for i, bid := range depth.Bids {
+		s.Require().Equal(baseAmounts[i].String(), bid.AmountIn.String())
+		if i > 0 {
+			s.Require().True(bid.AmountOut.GT(depth.Bids[i-1].AmountOut))
+			s.Require().True(bid.ExecutionPrice.LTE(depth.Bids[i-1].ExecutionPrice))
+		}
+	}
+
+	for i, ask := range depth.Asks {
+		if i > 0 {
+			s.Require().True(ask.AmountOut.GT(depth.Asks[i-1].AmountOut))
+			s.Require().True(ask.ExecutionPrice.GTE(depth.Asks[i-1].ExecutionPrice))
+		}
+	}
+
+	// The fees charged in both directions keep the best bid below the best ask.
+	s.Require().True(depth.Bids[0].ExecutionPrice.LT(depth.Asks[0].ExecutionPrice))
+}
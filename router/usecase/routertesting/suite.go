@@ -164,12 +164,14 @@ var (
 	absolutePathToStateFiles = ""
 
 	DefaultRouterConfig = domain.RouterConfig{
-		PreferredPoolIDs:    []uint64{},
-		MaxRoutes:           20,
-		MaxPoolsPerRoute:    4,
-		MaxSplitRoutes:      3,
-		MinPoolLiquidityCap: 1000,
-		RouteCacheEnabled:   true,
+		PreferredPoolIDs:              []uint64{},
+		MaxRoutes:                     20,
+		MaxPoolsPerRoute:              4,
+		MaxSplitRoutes:                3,
+		MinPoolLiquidityCap:           1000,
+		RouteCacheEnabled:             true,
+		RouteCacheInvalidationEnabled: true,
+		PoolFullSortIntervalBlocks:    50,
 
 		// Set proper dynamic min liquidity config here
 		DynamicMinLiquidityCapFiltersDesc: []domain.DynamicMinLiquidityCapFilterEntry{
@@ -195,6 +197,19 @@ var (
 				FilterValue:  1,
 			},
 		},
+
+		GasCostModel: domain.GasCostModelConfig{
+			Enabled:                     true,
+			FeeDenom:                    "uosmo",
+			GasPrice:                    "0.0025",
+			BaseGas:                     100000,
+			BalancerGas:                 50000,
+			StableSwapGas:               70000,
+			ConcentratedGas:             80000,
+			ConcentratedTickCrossingGas: 30000,
+			CosmWasmGas:                 150000,
+			OrderbookTickGas:            25000,
+		},
 	}
 
 	DefaultPoolsConfig = domain.PoolsConfig{
@@ -401,7 +416,8 @@ func (s *RouterTestHelper) SetupRouterAndPoolsUsecase(mainnetState MockMainnetSt
 	tokensUsecase := tokensusecase.NewTokensUsecase(mainnetState.TokensMetadata, 0, &log.NoOpLogger{})
 	tokensUsecase.UpdatePoolDenomMetadata(mainnetState.PoolDenomsMetaData)
 
-	candidateRouteFinder := routerusecase.NewCandidateRouteFinder(routerRepositoryMock, logger)
+	candidateRouteFinder, err := routerusecase.NewCandidateRouteSearcher(routerRepositoryMock, poolsUsecase.GetCosmWasmPoolConfig(), options.RouterConfig.CandidateRouteAlgorithm, logger)
+	s.Require().NoError(err)
 
 	routerUsecase := routerusecase.NewRouterUsecase(routerRepositoryMock, poolsUsecase, candidateRouteFinder, tokensUsecase, options.RouterConfig, poolsUsecase.GetCosmWasmPoolConfig(), logger, options.RankedRoutes, options.CandidateRoutes)
 
@@ -428,7 +444,7 @@ func (s *RouterTestHelper) SetupRouterAndPoolsUsecase(mainnetState MockMainnetSt
 
 	encCfg := app.MakeEncodingConfig()
 
-	ingestUsecase, err := ingestusecase.NewIngestUsecase(poolsUsecase, routerUsecase, pricingRouterUsecase, tokensUsecase, nil, encCfg.Marshaler, nil, nil, nil, logger)
+	ingestUsecase, err := ingestusecase.NewIngestUsecase(poolsUsecase, routerUsecase, pricingRouterUsecase, tokensUsecase, nil, encCfg.Marshaler, nil, nil, nil, domain.NewRouterStateGuard(), logger)
 	if err != nil {
 		panic(err)
 	}
@@ -52,19 +52,18 @@ func (c *candidateRouteSearchDataWorker) ComputeSearchDataAsync(ctx context.Cont
 func (c *candidateRouteSearchDataWorker) ComputeSearchDataSync(ctx context.Context, height uint64, blockPoolMetaData domain.BlockPoolMetadata) error {
 	// TODO: measure processing time
 
-	if err := c.compute(blockPoolMetaData); err != nil {
+	searchData, err := c.PrepareSearchData(blockPoolMetaData, c.poolsHandler)
+	if err != nil {
 		return err
 	}
 
-	// Notify listeners
-	for _, listener := range c.listeners {
-		_ = listener.OnSearchDataUpdate(ctx, height)
-	}
+	c.StoreSearchData(ctx, height, searchData)
 
 	return nil
 }
 
-func (c *candidateRouteSearchDataWorker) compute(blockPoolMetaData domain.BlockPoolMetadata) error {
+// PrepareSearchData implements domain.CandidateRouteSearchDataWorker.
+func (c *candidateRouteSearchDataWorker) PrepareSearchData(blockPoolMetaData domain.BlockPoolMetadata, poolsReader domain.CandidateRouteSearchPoolReader) (map[string]domain.CandidateRouteDenomData, error) {
 	mu := sync.Mutex{}
 
 	candidateRouteData := make(map[string]domain.CandidateRouteDenomData, len(blockPoolMetaData.UpdatedDenoms))
@@ -86,7 +85,7 @@ func (c *candidateRouteSearchDataWorker) compute(blockPoolMetaData domain.BlockP
 
 			denomPoolsIDs := domain.KeysFromMap(denomLiquidityData.Pools)
 
-			unsortedDenomPools, err := c.poolsHandler.GetPools(
+			unsortedDenomPools, err := poolsReader.GetPools(
 				domain.WithPoolIDFilter(denomPoolsIDs),
 			)
 			if err != nil {
@@ -100,7 +99,7 @@ func (c *candidateRouteSearchDataWorker) compute(blockPoolMetaData domain.BlockP
 
 			canonicalOrderbookPoolMapByPairToken := make(map[string]sqsdomain.PoolI, len(orderbookPools))
 			for _, pool := range orderbookPools {
-				if c.poolsHandler.IsCanonicalOrderbookPool(pool.GetId()) {
+				if poolsReader.IsCanonicalOrderbookPool(pool.GetId()) {
 					poolDenoms := pool.GetPoolDenoms()
 
 					for _, poolDenom := range poolDenoms {
@@ -120,9 +119,17 @@ func (c *candidateRouteSearchDataWorker) compute(blockPoolMetaData domain.BlockP
 
 	wg.Wait()
 
-	c.candidateRouteDataHolder.SetCandidateRouteSearchData(candidateRouteData)
+	return candidateRouteData, nil
+}
 
-	return nil
+// StoreSearchData implements domain.CandidateRouteSearchDataWorker.
+func (c *candidateRouteSearchDataWorker) StoreSearchData(ctx context.Context, height uint64, searchData map[string]domain.CandidateRouteDenomData) {
+	c.candidateRouteDataHolder.SetCandidateRouteSearchData(searchData)
+
+	// Notify listeners
+	for _, listener := range c.listeners {
+		_ = listener.OnSearchDataUpdate(ctx, height)
+	}
 }
 
 // RegisterListener implements domain.CandidateRouteSearchDataWorker.
@@ -1,6 +1,8 @@
 package cosmwasmpool
 
 import (
+	"math"
+
 	"github.com/osmosis-labs/osmosis/osmomath"
 )
 
@@ -24,6 +26,11 @@ type AlloyTransmuterData struct {
 	AssetConfigs      []TransmuterAssetConfig `json:"asset_configs"`
 	RateLimiterConfig AlloyedRateLimiter      `json:"rate_limiter"`
 
+	// BlockTime is the time of the block the data was ingested at (Unix timestamp in nanoseconds).
+	// Set at ingestion from the block time of the ingest request. The change rate limiter is checked at this time.
+	// Zero if the node does not send the block time.
+	BlockTime int64 `json:"block_time,omitempty"`
+
 	PreComputedData PrecomputedData `json:"precomputed_data"`
 }
 
@@ -74,6 +81,7 @@ type StaticLimiter struct {
 }
 
 // WindowConfig represents the configuration for a rate limiter window.
+// The window size is in nanoseconds, the same unit as the division timestamps.
 type WindowConfig struct {
 	WindowSize    uint64 `json:"window_size"`
 	DivisionCount uint64 `json:"division_count"`
@@ -81,10 +89,10 @@ type WindowConfig struct {
 
 // Division represents a time division with its associated values.
 type Division struct {
-	// StartedAt is the time when the division is marked as started (Unix timestamp).
+	// StartedAt is the time when the division is marked as started (Unix timestamp in nanoseconds).
 	StartedAt int64 `json:"started_at"`
 
-	// UpdatedAt is the time when the division was last updated (Unix timestamp).
+	// UpdatedAt is the time when the division was last updated (Unix timestamp in nanoseconds).
 	UpdatedAt int64 `json:"updated_at"`
 
 	// LatestValue is the latest value that gets updated (represented as a decimal string).
@@ -101,3 +109,133 @@ type ChangeLimiter struct {
 	WindowConfig   WindowConfig `json:"window_config"`
 	BoundaryOffset string       `json:"boundary_offset"`
 }
+
+// divisionSize returns the size of each division of the window in nanoseconds.
+// Returns error if the window size or the division count is zero, or if the window size does not fit int64.
+func (c WindowConfig) divisionSize() (int64, error) {
+	if c.WindowSize == 0 || c.DivisionCount == 0 || c.WindowSize > math.MaxInt64 {
+		return 0, InvalidWindowConfigError{WindowSize: c.WindowSize, DivisionCount: c.DivisionCount}
+	}
+
+	return int64(c.WindowSize / c.DivisionCount), nil
+}
+
+// UpperLimit returns the upper limit of the value at the given block time, computed as the moving average
+// of the value over the window plus the boundary offset. Mirrors the transmuter contract's change limiter.
+// Returns false if there are no data points to average, in which case the contract does not enforce the limit.
+// Returns error if the window config is invalid or if any of the values fails to parse.
+func (l *ChangeLimiter) UpperLimit(blockTime int64) (osmomath.Dec, bool, error) {
+	average, ok, err := l.MovingAverage(blockTime)
+	if err != nil || !ok {
+		return osmomath.Dec{}, ok, err
+	}
+
+	boundaryOffset, err := osmomath.NewDecFromStr(l.BoundaryOffset)
+	if err != nil {
+		return osmomath.Dec{}, false, err
+	}
+
+	return average.Add(boundaryOffset), true, nil
+}
+
+// MovingAverage returns the time-weighted average of the value over the window ending at the given block time.
+// Mirrors the compressed moving average of the transmuter contract's change limiter:
+// - the divisions that ended before the window started are removed, keeping track of the latest removed division.
+// - the time from the start of the window until the first remaining division is filled with the latest value
+// of the latest removed division.
+// - each remaining division contributes its integral until its last update and its latest value from then until
+// the next division started or until the block time.
+// - the division that started before the window contributes pro-rata to the time it overlaps the window.
+//
+// If no division was removed, the average is over the time since the first division started rather than the whole window.
+// The block time is raised to the latest division update if it is behind it since the given block time
+// is only an approximation of the time of the next block.
+// Returns false if there are no data points to average.
+// Returns error if the window config is invalid or if any of the values fails to parse.
+func (l *ChangeLimiter) MovingAverage(blockTime int64) (osmomath.Dec, bool, error) {
+	divisionSize, err := l.WindowConfig.divisionSize()
+	if err != nil {
+		return osmomath.Dec{}, false, err
+	}
+
+	if numDivisions := len(l.Divisions); numDivisions > 0 {
+		blockTime = max(blockTime, l.Divisions[numDivisions-1].UpdatedAt)
+	}
+
+	windowStartedAt := blockTime - int64(l.WindowConfig.WindowSize)
+
+	// Clean up the divisions that ended before the window started.
+	var (
+		latestRemovedDivision *Division
+		divisions             = l.Divisions
+	)
+	for len(divisions) > 0 && divisions[0].StartedAt+divisionSize <= windowStartedAt {
+		latestRemovedDivision = &divisions[0]
+		divisions = divisions[1:]
+	}
+
+	if len(divisions) == 0 && latestRemovedDivision == nil {
+		return osmomath.Dec{}, false, nil
+	}
+
+	cumsum := osmomath.ZeroDec()
+	averagedFrom := windowStartedAt
+
+	if latestRemovedDivision != nil {
+		latestRemovedValue, err := osmomath.NewDecFromStr(latestRemovedDivision.LatestValue)
+		if err != nil {
+			return osmomath.Dec{}, false, err
+		}
+
+		gapEndedAt := blockTime
+		if len(divisions) > 0 {
+			gapEndedAt = divisions[0].StartedAt
+		}
+
+		if gapEndedAt > windowStartedAt {
+			cumsum.AddMut(latestRemovedValue.MulInt64(gapEndedAt - windowStartedAt))
+		}
+	} else if divisions[0].StartedAt > windowStartedAt {
+		averagedFrom = divisions[0].StartedAt
+	}
+
+	for i, division := range divisions {
+		latestValue, err := osmomath.NewDecFromStr(division.LatestValue)
+		if err != nil {
+			return osmomath.Dec{}, false, err
+		}
+
+		integral, err := osmomath.NewDecFromStr(division.Integral)
+		if err != nil {
+			return osmomath.Dec{}, false, err
+		}
+
+		endedAt := blockTime
+		if i+1 < len(divisions) {
+			endedAt = divisions[i+1].StartedAt
+		}
+
+		if endedAt > division.UpdatedAt {
+			integral.AddMut(latestValue.MulInt64(endedAt - division.UpdatedAt))
+		}
+
+		// Only the part of the division within the window is accounted for.
+		if division.StartedAt < windowStartedAt && endedAt > division.StartedAt {
+			integral = integral.MulInt64(endedAt - windowStartedAt).QuoInt64(endedAt - division.StartedAt)
+		}
+
+		cumsum.AddMut(integral)
+	}
+
+	duration := blockTime - averagedFrom
+	if duration <= 0 {
+		// The only division started at the block time.
+		latestValue, err := osmomath.NewDecFromStr(divisions[len(divisions)-1].LatestValue)
+		if err != nil {
+			return osmomath.Dec{}, false, err
+		}
+		return latestValue, true, nil
+	}
+
+	return cumsum.QuoInt64(duration), true, nil
+}
@@ -0,0 +1,136 @@
+package cosmwasmpool_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+	"github.com/osmosis-labs/sqs/sqsdomain/cosmwasmpool"
+)
+
+// TestChangeLimiterUpperLimit tests the moving average and the upper limit of the change limiter.
+// The window is 1000 nanoseconds long with 10 divisions of 100 nanoseconds each.
+func TestChangeLimiterUpperLimit(t *testing.T) {
+	defaultWindowConfig := cosmwasmpool.WindowConfig{
+		WindowSize:    1000,
+		DivisionCount: 10,
+	}
+
+	tests := map[string]struct {
+		divisions     []cosmwasmpool.Division
+		windowConfig  cosmwasmpool.WindowConfig
+		blockTime     int64
+		expectedOk    bool
+		expectedAvg   osmomath.Dec
+		expectedError error
+	}{
+		"no divisions": {
+			windowConfig: defaultWindowConfig,
+			blockTime:    100,
+		},
+		"single division": {
+			// The value was 0.4 from 0 to 50 and 0.5 since.
+			// (0.4 * 50 + 0.5 * 50) / 100
+			divisions: []cosmwasmpool.Division{
+				{StartedAt: 0, UpdatedAt: 50, LatestValue: "0.5", Integral: "20"},
+			},
+			windowConfig: defaultWindowConfig,
+			blockTime:    100,
+			expectedOk:   true,
+			expectedAvg:  osmomath.MustNewDecFromStr("0.45"),
+		},
+		"outdated division fills the gap until the first division in the window": {
+			// The first division ended at 100 before the window started at 200.
+			// (0.2 * 800 + 0.2 * 50 + 0.6 * 150) / 1000
+			divisions: []cosmwasmpool.Division{
+				{StartedAt: 0, UpdatedAt: 0, LatestValue: "0.2", Integral: "0"},
+				{StartedAt: 1000, UpdatedAt: 1050, LatestValue: "0.6", Integral: "10"},
+			},
+			windowConfig: defaultWindowConfig,
+			blockTime:    1200,
+			expectedOk:   true,
+			expectedAvg:  osmomath.MustNewDecFromStr("0.26"),
+		},
+		"only outdated divisions": {
+			divisions: []cosmwasmpool.Division{
+				{StartedAt: 0, UpdatedAt: 10, LatestValue: "0.3", Integral: "1"},
+			},
+			windowConfig: defaultWindowConfig,
+			blockTime:    5000,
+			expectedOk:   true,
+			expectedAvg:  osmomath.MustNewDecFromStr("0.3"),
+		},
+		"division overlapping the window start is accounted for pro-rata": {
+			// The window started at 150 in the middle of the first division lasting from 100 until 300.
+			// ((0.2 * 50 + 0.4 * 150) * 150 / 200 + 0.6 * 850) / 1000
+			divisions: []cosmwasmpool.Division{
+				{StartedAt: 100, UpdatedAt: 150, LatestValue: "0.4", Integral: "10"},
+				{StartedAt: 300, UpdatedAt: 300, LatestValue: "0.6", Integral: "0"},
+			},
+			windowConfig: defaultWindowConfig,
+			blockTime:    1150,
+			expectedOk:   true,
+			expectedAvg:  osmomath.MustNewDecFromStr("0.5625"),
+		},
+		"block time behind the latest update": {
+			// The block time is raised to 50.
+			divisions: []cosmwasmpool.Division{
+				{StartedAt: 0, UpdatedAt: 50, LatestValue: "0.5", Integral: "20"},
+			},
+			windowConfig: defaultWindowConfig,
+			blockTime:    40,
+			expectedOk:   true,
+			expectedAvg:  osmomath.MustNewDecFromStr("0.4"),
+		},
+		"division started at the block time": {
+			divisions: []cosmwasmpool.Division{
+				{StartedAt: 100, UpdatedAt: 100, LatestValue: "0.5", Integral: "0"},
+			},
+			windowConfig: defaultWindowConfig,
+			blockTime:    100,
+			expectedOk:   true,
+			expectedAvg:  osmomath.MustNewDecFromStr("0.5"),
+		},
+		"zero division count": {
+			divisions: []cosmwasmpool.Division{
+				{StartedAt: 0, UpdatedAt: 50, LatestValue: "0.5", Integral: "20"},
+			},
+			windowConfig:  cosmwasmpool.WindowConfig{WindowSize: 1000},
+			blockTime:     100,
+			expectedError: cosmwasmpool.InvalidWindowConfigError{WindowSize: 1000},
+		},
+	}
+
+	const boundaryOffset = "0.1"
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			limiter := cosmwasmpool.ChangeLimiter{
+				Divisions:      tc.divisions,
+				WindowConfig:   tc.windowConfig,
+				BoundaryOffset: boundaryOffset,
+			}
+
+			// System under test
+			avg, ok, err := limiter.MovingAverage(tc.blockTime)
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOk, ok)
+
+			upperLimit, upperLimitOk, err := limiter.UpperLimit(tc.blockTime)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOk, upperLimitOk)
+
+			if !tc.expectedOk {
+				return
+			}
+
+			assert.Equal(t, tc.expectedAvg.String(), avg.String())
+			assert.Equal(t, tc.expectedAvg.Add(osmomath.MustNewDecFromStr(boundaryOffset)).String(), upperLimit.String())
+		})
+	}
+}
@@ -0,0 +1,446 @@
+package cosmwasmpool
+
+import (
+	sdk "github.com/cosmos/cosmos-sdk/types"
+
+	"github.com/osmosis-labs/osmosis/osmomath"
+)
+
+const (
+	ASTROPORT_PCL_CONTRACT_NAME               = "astroport-pair-concentrated"
+	ASTROPORT_PCL_MIN_CONTRACT_VERSION        = "1.0.0"
+	ASTROPORT_PCL_CONTRACT_VERSION_CONSTRAINT = ">= " + ASTROPORT_PCL_MIN_CONTRACT_VERSION
+)
+
+const (
+	// astroportPCLNumAssets is the number of assets in an Astroport PCL pool.
+	astroportPCLNumAssets = 2
+	// astroportPCLMaxPrecision is the maximum precision of the pool assets, the decimal precision of the contract.
+	astroportPCLMaxPrecision = 18
+	// astroportPCLMaxIterations is the maximum number of Newton iterations when solving the invariant.
+	astroportPCLMaxIterations = 64
+)
+
+var (
+	// astroportPCLTolerance is the tolerance of the Newton iterations when solving the invariant.
+	astroportPCLTolerance = osmomath.NewDecWithPrec(1, 7)
+)
+
+func (model *CosmWasmPoolModel) IsAstroportPCL() bool {
+	return model.ContractInfo.Matches(
+		ASTROPORT_PCL_CONTRACT_NAME,
+		mustParseSemverConstraint(ASTROPORT_PCL_CONTRACT_VERSION_CONSTRAINT),
+	)
+}
+
+// AstroportPCLData is the data of the Astroport passive concentrated liquidity (PCL) pools.
+// The invariant is solved over the internal balances of the pool, that is, the balances in units of whole tokens
+// with the balance of the second asset repegged into units of the first asset by the price scale.
+// [more info](https://github.com/astroport-fi/astroport-core/tree/main/contracts/pair_concentrated)
+type AstroportPCLData struct {
+	// AssetDenoms are the denoms of the pool assets in the order of the contract.
+	AssetDenoms []string `json:"asset_denoms"`
+	// AssetPrecisions are the decimal precisions of the pool assets. Each index corresponds to the asset at the same index in AssetDenoms.
+	AssetPrecisions []uint32 `json:"asset_precisions"`
+
+	// Amp is the amplification parameter of the invariant at the time of ingestion or refresh.
+	Amp osmomath.Dec `json:"amp"`
+	// Gamma is the gamma parameter of the invariant at the time of ingestion or refresh.
+	Gamma osmomath.Dec `json:"gamma"`
+
+	// MidFee is the fee rate charged when the pool is balanced.
+	MidFee osmomath.Dec `json:"mid_fee"`
+	// OutFee is the fee rate charged when the pool is imbalanced.
+	OutFee osmomath.Dec `json:"out_fee"`
+	// FeeGamma controls how fast the fee rate moves from the mid fee to the out fee as the pool is imbalanced.
+	FeeGamma osmomath.Dec `json:"fee_gamma"`
+
+	// PriceScale is the price of the second asset in units of the first asset that the liquidity is concentrated around.
+	PriceScale osmomath.Dec `json:"price_scale"`
+}
+
+// Validate validates the PCL data.
+// Returns error if the pool does not have exactly two assets with a precision each
+// or if any of the parameters is out of range.
+func (d *AstroportPCLData) Validate() error {
+	if len(d.AssetDenoms) != astroportPCLNumAssets || len(d.AssetPrecisions) != astroportPCLNumAssets {
+		return AstroportPCLInvalidDataError{Reason: "the pool must have exactly two assets with a precision each"}
+	}
+
+	if d.AssetDenoms[0] == d.AssetDenoms[1] {
+		return DuplicatedDenomError{Denom: d.AssetDenoms[0]}
+	}
+
+	for _, precision := range d.AssetPrecisions {
+		if precision > astroportPCLMaxPrecision {
+			return AstroportPCLInvalidDataError{Reason: "the asset precisions must not exceed the decimal precision"}
+		}
+	}
+
+	for _, param := range []osmomath.Dec{d.Amp, d.Gamma, d.FeeGamma, d.PriceScale} {
+		if param.IsNil() || !param.IsPositive() {
+			return AstroportPCLInvalidDataError{Reason: "amp, gamma, fee gamma and price scale must be positive"}
+		}
+	}
+
+	for _, fee := range []osmomath.Dec{d.MidFee, d.OutFee} {
+		if fee.IsNil() || fee.IsNegative() || fee.GTE(osmomath.OneDec()) {
+			return AstroportPCLInvalidDataError{Reason: "the fees must be in the range [0, 1)"}
+		}
+	}
+
+	return nil
+}
+
+// CalcTokenOutAmt returns the amount of the token out denom received for swapping the token in through the pool
+// with the given balances. Mirrors the swap simulation of the contract:
+// - the invariant D is solved for the balances before the swap.
+// - the balance of the token out is solved for D after the token in is added to the pool.
+// - the fee rate is computed from the balances after the swap and charged on the amount out.
+//
+// The amount out is rounded down.
+// Returns error if either denom is not in the pool, if the pool has no liquidity or if the invariant fails to solve.
+func (d *AstroportPCLData) CalcTokenOutAmt(balances sdk.Coins, tokenIn sdk.Coin, tokenOutDenom string) (osmomath.Int, error) {
+	offerIndex, askIndex, err := d.swapIndices(tokenIn.Denom, tokenOutDenom)
+	if err != nil {
+		return osmomath.Int{}, err
+	}
+
+	xs, err := d.internalBalances(balances)
+	if err != nil {
+		return osmomath.Int{}, err
+	}
+
+	invariant, err := d.calcD(xs)
+	if err != nil {
+		return osmomath.Int{}, err
+	}
+
+	xs[offerIndex] = xs[offerIndex].Add(d.toInternal(tokenIn.Amount, offerIndex))
+
+	newY, err := d.calcY(xs, invariant, askIndex)
+	if err != nil {
+		return osmomath.Int{}, err
+	}
+
+	dy := xs[askIndex].Sub(newY)
+	if !dy.IsPositive() {
+		return osmomath.ZeroInt(), nil
+	}
+	xs[askIndex] = newY
+
+	dy = d.fromInternal(dy, askIndex, false)
+	dy = dy.Sub(d.feeRate(xs).MulTruncate(dy))
+
+	return d.toAmount(dy, askIndex, false), nil
+}
+
+// CalcTokenInAmt returns the amount of the token in denom required to receive the token out from the pool
+// with the given balances. Mirrors the reverse swap simulation of the contract which cannot predict the fee rate
+// and charges the out fee, the maximum fee rate, instead.
+//
+// The amount in is rounded up.
+// Returns error if either denom is not in the pool, if the pool has no liquidity, if the token out before the fee
+// exceeds the balance of the pool or if the invariant fails to solve.
+func (d *AstroportPCLData) CalcTokenInAmt(balances sdk.Coins, tokenOut sdk.Coin, tokenInDenom string) (osmomath.Int, error) {
+	offerIndex, askIndex, err := d.swapIndices(tokenInDenom, tokenOut.Denom)
+	if err != nil {
+		return osmomath.Int{}, err
+	}
+
+	xs, err := d.internalBalances(balances)
+	if err != nil {
+		return osmomath.Int{}, err
+	}
+
+	invariant, err := d.calcD(xs)
+	if err != nil {
+		return osmomath.Int{}, err
+	}
+
+	askAmount := d.toInternal(tokenOut.Amount, askIndex).QuoRoundUp(osmomath.OneDec().Sub(d.OutFee))
+	if askAmount.GTE(xs[askIndex]) {
+		return osmomath.Int{}, AstroportPCLInsufficientLiquidityError{Denom: tokenOut.Denom, Amount: tokenOut.Amount.String()}
+	}
+	xs[askIndex] = xs[askIndex].Sub(askAmount)
+
+	newX, err := d.calcY(xs, invariant, offerIndex)
+	if err != nil {
+		return osmomath.Int{}, err
+	}
+
+	dx := newX.Sub(xs[offerIndex])
+	if !dx.IsPositive() {
+		return osmomath.ZeroInt(), nil
+	}
+
+	return d.toAmount(d.fromInternal(dx, offerIndex, true), offerIndex, true), nil
+}
+
+// SpotPrice returns the marginal price of the base denom in units of the quote denom along the invariant
+// for the pool with the given balances, excluding the fees.
+// Returns error if either denom is not in the pool, if the pool has no liquidity or if the invariant fails to solve.
+func (d *AstroportPCLData) SpotPrice(balances sdk.Coins, baseDenom, quoteDenom string) (osmomath.BigDec, error) {
+	baseIndex, quoteIndex, err := d.swapIndices(baseDenom, quoteDenom)
+	if err != nil {
+		return osmomath.BigDec{}, err
+	}
+
+	xs, err := d.internalBalances(balances)
+	if err != nil {
+		return osmomath.BigDec{}, err
+	}
+
+	invariant, err := d.calcD(xs)
+	if err != nil {
+		return osmomath.BigDec{}, err
+	}
+
+	k, dkdk0, err := d.invariantTerms(invariant, xs)
+	if err != nil {
+		return osmomath.BigDec{}, err
+	}
+
+	// The marginal price in internal units is the ratio of the partial derivatives of the invariant.
+	dfdBase := d.dfdx(invariant, xs, k, dkdk0, baseIndex)
+	dfdQuote := d.dfdx(invariant, xs, k, dkdk0, quoteIndex)
+	if !dfdBase.IsPositive() || !dfdQuote.IsPositive() {
+		return osmomath.BigDec{}, AstroportPCLInvariantError{Variable: "spot price"}
+	}
+
+	spotPrice := osmomath.BigDecFromDec(dfdBase).Quo(osmomath.BigDecFromDec(dfdQuote))
+
+	// Convert from the internal units into the amounts of the denoms.
+	priceScale := osmomath.BigDecFromDec(d.PriceScale)
+	if baseIndex == 1 {
+		spotPrice = spotPrice.Mul(priceScale)
+	} else {
+		spotPrice = spotPrice.Quo(priceScale)
+	}
+
+	spotPrice = spotPrice.Mul(tenPowBigDec(d.AssetPrecisions[quoteIndex])).Quo(tenPowBigDec(d.AssetPrecisions[baseIndex]))
+
+	return spotPrice, nil
+}
+
+// swapIndices returns the indices of the given token in and token out denoms among the pool assets.
+// Returns error if either denom is not in the pool or if the denoms are the same.
+func (d *AstroportPCLData) swapIndices(tokenInDenom, tokenOutDenom string) (int, int, error) {
+	if err := d.Validate(); err != nil {
+		return 0, 0, err
+	}
+
+	tokenInIndex, tokenOutIndex := -1, -1
+	for i, denom := range d.AssetDenoms {
+		if denom == tokenInDenom {
+			tokenInIndex = i
+		}
+		if denom == tokenOutDenom {
+			tokenOutIndex = i
+		}
+	}
+
+	if tokenInIndex == -1 {
+		return 0, 0, AstroportPCLUnsupportedDenomError{Denom: tokenInDenom, Denoms: d.AssetDenoms}
+	}
+
+	if tokenOutIndex == -1 || tokenOutIndex == tokenInIndex {
+		return 0, 0, AstroportPCLUnsupportedDenomError{Denom: tokenOutDenom, Denoms: d.AssetDenoms}
+	}
+
+	return tokenInIndex, tokenOutIndex, nil
+}
+
+// internalBalances returns the internal balances of the pool given its balances.
+// Returns error if either balance is zero.
+func (d *AstroportPCLData) internalBalances(balances sdk.Coins) ([astroportPCLNumAssets]osmomath.Dec, error) {
+	var xs [astroportPCLNumAssets]osmomath.Dec
+	for i, denom := range d.AssetDenoms {
+		xs[i] = d.toInternal(balances.AmountOf(denom), i)
+		if !xs[i].IsPositive() {
+			return xs, AstroportPCLInsufficientLiquidityError{Denom: denom, Amount: balances.AmountOf(denom).String()}
+		}
+	}
+	return xs, nil
+}
+
+// toInternal converts the amount of the asset at the given index into internal units.
+// Rounds down like the decimal arithmetic of the contract.
+func (d *AstroportPCLData) toInternal(amount osmomath.Int, index int) osmomath.Dec {
+	internal := osmomath.NewDecFromInt(amount).QuoTruncate(tenPowDec(d.AssetPrecisions[index]))
+	if index == 1 {
+		internal = internal.MulTruncate(d.PriceScale)
+	}
+	return internal
+}
+
+// fromInternal removes the price scale from the internal value of the asset at the given index
+// so that it is in units of whole tokens of the asset.
+func (d *AstroportPCLData) fromInternal(value osmomath.Dec, index int, roundUp bool) osmomath.Dec {
+	if index != 1 {
+		return value
+	}
+	if roundUp {
+		return value.QuoRoundUp(d.PriceScale)
+	}
+	return value.QuoTruncate(d.PriceScale)
+}
+
+// toAmount converts the value in units of whole tokens of the asset at the given index into its amount.
+func (d *AstroportPCLData) toAmount(value osmomath.Dec, index int, roundUp bool) osmomath.Int {
+	amount := value.Mul(tenPowDec(d.AssetPrecisions[index]))
+	if roundUp {
+		return amount.Ceil().TruncateInt()
+	}
+	return amount.TruncateInt()
+}
+
+// feeRate returns the fee rate of swapping into the given internal balances:
+//
+// k = fee_gamma / (fee_gamma + 1 - 4 * x0 * x1 / (x0 + x1)^2)
+// fee_rate = k * mid_fee + (1 - k) * out_fee
+func (d *AstroportPCLData) feeRate(xs [astroportPCLNumAssets]osmomath.Dec) osmomath.Dec {
+	sum := xs[0].Add(xs[1])
+	balance := xs[0].Mul(xs[1]).MulInt64(4).Quo(sum.Mul(sum))
+
+	k := d.FeeGamma.Quo(d.FeeGamma.Add(osmomath.OneDec()).Sub(balance))
+
+	return k.Mul(d.MidFee).Add(osmomath.OneDec().Sub(k).Mul(d.OutFee))
+}
+
+// calcD solves the invariant D for the given internal balances by Newton's method,
+// starting from twice the geometric mean of the balances.
+func (d *AstroportPCLData) calcD(xs [astroportPCLNumAssets]osmomath.Dec) (osmomath.Dec, error) {
+	geometricMean, err := xs[0].Mul(xs[1]).ApproxSqrt()
+	if err != nil {
+		return osmomath.Dec{}, err
+	}
+
+	invariant := geometricMean.MulInt64(astroportPCLNumAssets)
+	for i := 0; i < astroportPCLMaxIterations; i++ {
+		f, dfdD, err := d.invariantAndDerivativeByD(invariant, xs)
+		if err != nil {
+			return osmomath.Dec{}, err
+		}
+
+		if dfdD.IsZero() {
+			return osmomath.Dec{}, AstroportPCLInvariantError{Variable: "D"}
+		}
+
+		next := invariant.Sub(f.Quo(dfdD))
+		if !next.IsPositive() {
+			return osmomath.Dec{}, AstroportPCLInvariantError{Variable: "D"}
+		}
+
+		if next.Sub(invariant).Abs().LTE(astroportPCLTolerance) {
+			return next, nil
+		}
+
+		invariant = next
+	}
+
+	return osmomath.Dec{}, AstroportPCLInvariantError{Variable: "D"}
+}
+
+// calcY solves the internal balance at the given index for the invariant D given the other internal balance
+// by Newton's method, starting from the balance of the constant product invariant.
+func (d *AstroportPCLData) calcY(xs [astroportPCLNumAssets]osmomath.Dec, invariant osmomath.Dec, index int) (osmomath.Dec, error) {
+	other := 1 - index
+
+	xs[index] = invariant.Mul(invariant).Quo(xs[other].MulInt64(4))
+	for i := 0; i < astroportPCLMaxIterations; i++ {
+		k, dkdk0, err := d.invariantTerms(invariant, xs)
+		if err != nil {
+			return osmomath.Dec{}, err
+		}
+
+		dfdx := d.dfdx(invariant, xs, k, dkdk0, index)
+		if dfdx.IsZero() {
+			return osmomath.Dec{}, AstroportPCLInvariantError{Variable: "y"}
+		}
+
+		next := xs[index].Sub(d.f(invariant, xs, k).Quo(dfdx))
+		if !next.IsPositive() {
+			return osmomath.Dec{}, AstroportPCLInvariantError{Variable: "y"}
+		}
+
+		if next.Sub(xs[index]).Abs().LTE(astroportPCLTolerance) {
+			return next, nil
+		}
+
+		xs[index] = next
+	}
+
+	return osmomath.Dec{}, AstroportPCLInvariantError{Variable: "y"}
+}
+
+// invariantTerms returns the term K of the invariant for the given D and internal balances:
+//
+// K0 = 4 * x0 * x1 / D^2
+// K = A * gamma^2 * K0 / (gamma + 1 - K0)^2
+//
+// along with the derivative of K by K0, A * gamma^2 * (gamma + 1 + K0) / (gamma + 1 - K0)^3.
+// Returns error if gamma + 1 - K0 is not positive.
+func (d *AstroportPCLData) invariantTerms(invariant osmomath.Dec, xs [astroportPCLNumAssets]osmomath.Dec) (k, dkdk0 osmomath.Dec, err error) {
+	k0 := xs[0].Mul(xs[1]).MulInt64(4).Quo(invariant.Mul(invariant))
+
+	gammaPlusOne := d.Gamma.Add(osmomath.OneDec())
+	denominator := gammaPlusOne.Sub(k0)
+	if !denominator.IsPositive() {
+		return osmomath.Dec{}, osmomath.Dec{}, AstroportPCLInvariantError{Variable: "K"}
+	}
+
+	ampGammaSquared := d.Amp.Mul(d.Gamma).Mul(d.Gamma)
+
+	k = ampGammaSquared.Mul(k0).Quo(denominator).Quo(denominator)
+	dkdk0 = ampGammaSquared.Mul(gammaPlusOne.Add(k0)).Quo(denominator).Quo(denominator).Quo(denominator)
+
+	return k, dkdk0, nil
+}
+
+// f returns the value of the invariant function for the given D, internal balances and K:
+//
+// f = K * D * (x0 + x1) + x0 * x1 - K * D^2 - D^2 / 4
+func (d *AstroportPCLData) f(invariant osmomath.Dec, xs [astroportPCLNumAssets]osmomath.Dec, k osmomath.Dec) osmomath.Dec {
+	sum := xs[0].Add(xs[1])
+	return k.Mul(invariant).Mul(sum.Sub(invariant)).Add(xs[0].Mul(xs[1])).Sub(invariant.Mul(invariant).QuoInt64(4))
+}
+
+// dfdx returns the derivative of the invariant function by the internal balance at the given index.
+func (d *AstroportPCLData) dfdx(invariant osmomath.Dec, xs [astroportPCLNumAssets]osmomath.Dec, k, dkdk0 osmomath.Dec, index int) osmomath.Dec {
+	other := xs[1-index]
+	sum := xs[0].Add(xs[1])
+
+	dkdx := other.MulInt64(4).Quo(invariant).Quo(invariant).Mul(dkdk0)
+
+	return dkdx.Mul(invariant).Mul(sum.Sub(invariant)).Add(k.Mul(invariant)).Add(other)
+}
+
+// invariantAndDerivativeByD returns the value of the invariant function and its derivative by D.
+func (d *AstroportPCLData) invariantAndDerivativeByD(invariant osmomath.Dec, xs [astroportPCLNumAssets]osmomath.Dec) (osmomath.Dec, osmomath.Dec, error) {
+	k, dkdk0, err := d.invariantTerms(invariant, xs)
+	if err != nil {
+		return osmomath.Dec{}, osmomath.Dec{}, err
+	}
+
+	sum := xs[0].Add(xs[1])
+
+	// dK/dD = dK/dK0 * -8 * x0 * x1 / D^3
+	dkdD := xs[0].Mul(xs[1]).MulInt64(8).Quo(invariant).Quo(invariant).Quo(invariant).Mul(dkdk0).Neg()
+
+	dfdD := dkdD.Mul(invariant).Mul(sum.Sub(invariant)).
+		Add(k.Mul(sum.Sub(invariant.MulInt64(2)))).
+		Sub(invariant.QuoInt64(2))
+
+	return d.f(invariant, xs, k), dfdD, nil
+}
+
+// tenPowDec returns 10^exponent.
+func tenPowDec(exponent uint32) osmomath.Dec {
+	return osmomath.NewDec(10).Power(uint64(exponent))
+}
+
+// tenPowBigDec returns 10^exponent.
+func tenPowBigDec(exponent uint32) osmomath.BigDec {
+	return osmomath.BigDecFromDec(tenPowDec(exponent))
+}
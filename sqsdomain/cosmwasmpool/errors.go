@@ -28,3 +28,46 @@ type OrderbookOrderNotAvailableError struct {
 func (e OrderbookOrderNotAvailableError) Error() string {
 	return fmt.Sprintf("There is no %s order in pool (%d)", e.Direction.String(), e.PoolId)
 }
+
+type InvalidWindowConfigError struct {
+	WindowSize    uint64
+	DivisionCount uint64
+}
+
+func (e InvalidWindowConfigError) Error() string {
+	return fmt.Sprintf("Invalid rate limiter window config with window size (%d) and division count (%d)", e.WindowSize, e.DivisionCount)
+}
+
+type AstroportPCLInvalidDataError struct {
+	Reason string
+}
+
+func (e AstroportPCLInvalidDataError) Error() string {
+	return fmt.Sprintf("Invalid Astroport PCL data: %s", e.Reason)
+}
+
+type AstroportPCLUnsupportedDenomError struct {
+	Denom  string
+	Denoms []string
+}
+
+func (e AstroportPCLUnsupportedDenomError) Error() string {
+	return fmt.Sprintf("Denom (%s) is not supported by Astroport PCL pool (%v)", e.Denom, e.Denoms)
+}
+
+type AstroportPCLInsufficientLiquidityError struct {
+	Denom  string
+	Amount string
+}
+
+func (e AstroportPCLInsufficientLiquidityError) Error() string {
+	return fmt.Sprintf("Insufficient Astroport PCL pool liquidity of denom (%s) for amount (%s)", e.Denom, e.Amount)
+}
+
+type AstroportPCLInvariantError struct {
+	Variable string
+}
+
+func (e AstroportPCLInvariantError) Error() string {
+	return fmt.Sprintf("Failed to solve the Astroport PCL invariant for %s", e.Variable)
+}
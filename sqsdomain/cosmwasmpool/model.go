@@ -1,6 +1,8 @@
 package cosmwasmpool
 
 import (
+	"fmt"
+
 	"github.com/Masterminds/semver"
 )
 
@@ -29,6 +31,35 @@ func (ci *ContractInfo) Matches(contract string, versionConstrains *semver.Const
 	return validSemver && (ci.Contract == contract && versionConstrains.Check(version))
 }
 
+// ContractMatcher matches the contract info by the contract name and a semver constraint on the version.
+type ContractMatcher struct {
+	Contract          string
+	versionConstraint *semver.Constraints
+}
+
+// NewContractMatcher returns a new contract matcher for the given contract name and semver version constraint.
+// Returns error if the contract name is empty or if the version constraint is invalid.
+func NewContractMatcher(contract string, versionConstraint string) (ContractMatcher, error) {
+	if contract == "" {
+		return ContractMatcher{}, fmt.Errorf("contract name must be non-empty")
+	}
+
+	constraint, err := semver.NewConstraint(versionConstraint)
+	if err != nil {
+		return ContractMatcher{}, fmt.Errorf("invalid version constraint (%s) for contract (%s): %w", versionConstraint, contract, err)
+	}
+
+	return ContractMatcher{
+		Contract:          contract,
+		versionConstraint: constraint,
+	}, nil
+}
+
+// Matches returns true if the given contract info matches the contract name and the version constraint.
+func (m ContractMatcher) Matches(contractInfo ContractInfo) bool {
+	return m.versionConstraint != nil && contractInfo.Matches(m.Contract, m.versionConstraint)
+}
+
 func mustParseSemverConstraint(constraint string) *semver.Constraints {
 	c, err := semver.NewConstraint(constraint)
 	if err != nil {
@@ -55,6 +86,9 @@ type CosmWasmPoolData struct {
 
 	// Data for Orderbook contract, must be present if and only if `IsOrderbook()` is true
 	Orderbook *OrderbookData `json:"orderbook,omitempty"`
+
+	// Data for Astroport PCL contract, may only be present if `IsAstroportPCL()` is true
+	AstroportPCL *AstroportPCLData `json:"astroport_pcl,omitempty"`
 }
 
 func NewCWPoolModel(contract string, version string, data CosmWasmPoolData) *CosmWasmPoolModel {